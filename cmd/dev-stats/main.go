@@ -1,26 +1,59 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
+	"dev-stats/pkg/api"
 	"dev-stats/pkg/backlog"
+	"dev-stats/pkg/bundle"
+	"dev-stats/pkg/cache"
 	"dev-stats/pkg/calendar"
 	"dev-stats/pkg/common"
+	"dev-stats/pkg/completion"
+	"dev-stats/pkg/config"
+	"dev-stats/pkg/copilot"
+	"dev-stats/pkg/doctor"
+	"dev-stats/pkg/focus"
+	"dev-stats/pkg/gitea"
 	"dev-stats/pkg/github"
 	"dev-stats/pkg/google"
+	"dev-stats/pkg/harvest"
+	"dev-stats/pkg/ingest"
+	"dev-stats/pkg/jira"
 	"dev-stats/pkg/notion"
+	"dev-stats/pkg/opsgenie"
+	"dev-stats/pkg/phabricator"
+	"dev-stats/pkg/quick"
+	"dev-stats/pkg/report"
+	"dev-stats/pkg/selfupdate"
+	"dev-stats/pkg/slack"
+	"dev-stats/pkg/snapshot"
+	"dev-stats/pkg/support"
+	"dev-stats/pkg/tasks"
+	"dev-stats/pkg/team"
+	"dev-stats/pkg/todoist"
+	"dev-stats/pkg/upload"
+
+	"github.com/joho/godotenv"
 )
 
+// version is set by release builds (go build -ldflags "-X main.version=v1.2.3"); make build uses git describe
+var version = "dev"
+
 func main() {
 	var (
-		analyzerFlag        = flag.String("analyzer", "", "Analyzer to run (github,backlog,calendar,notion,google,all)")
+		analyzerFlag        = flag.String("analyzer", "", "Analyzer to run (github,backlog,calendar,notion,google,todoist,jira,harvest,support,opsgenie,copilot,gitea,phabricator,focus,github-archive,webhooks,all)")
 		downloadFlag        = flag.String("download", "", "Download Notion pages from markdown file")
 		downloadGoogleFlag  = flag.Bool("download-google", false, "Download all Google Workspace files modified in START_DATE to END_DATE")
 		listBacklogFlag     = flag.Bool("list-backlog", false, "List Backlog projects and members for all profiles")
@@ -29,9 +62,41 @@ func main() {
 		listBacklogClear    = flag.Bool("list-backlog-clear", false, "Clear cache and refresh Backlog data")
 		helpFlag            = flag.Bool("help", false, "Show help")
 		listFlag            = flag.Bool("list", false, "List available analyzers")
+		extrapolateFlag     = flag.Bool("extrapolate", false, "Annotate metrics with run-rate extrapolations when the period is incomplete")
+		outputFlag          = flag.String("output", "text", "Output format of stats files (text, json, markdown, csv); json also writes <analyzer>-stats.json, markdown writes report.md, csv writes <analyzer>-<list>.csv")
+		kudosFlag           = flag.Bool("kudos", false, "Append thanks/kudos received on GitHub PRs and in Slack")
+		uploadFlag          = flag.String("upload", "", "Upload the stats directory to s3://bucket/prefix or gs://bucket/prefix after the run (default: UPLOAD_TARGET)")
+		gamificationFlag    = flag.Bool("gamification", false, "Show streaks and badges computed from stored history")
+		explainFlag         = flag.String("explain", "", "List the items counted in a metric (ID like github.prs_low_value, ID without prefix, or label)")
+		noCacheFlag         = flag.Bool("no-cache", false, "Fetch every API response again instead of using the HTTP response cache (.http-cache/)")
+		startFlag           = flag.String("start", "", "Start date (YYYY-MM-DD), overriding START_DATE")
+		endFlag             = flag.String("end", "", "End date (YYYY-MM-DD), overriding END_DATE")
+		timelineFlag        = flag.Bool("timeline", false, "Print every item from all analyzers as a per-day feed and save timeline.txt/timeline.csv")
+		splitDetailsFlag    = flag.Bool("split-details", false, "Write detail lists (PRs, issues, events, pages) to <analyzer>-details.jsonl instead of <analyzer>-stats.json")
+		obsidianFlag        = flag.String("obsidian", "", "Write per-day summaries into the daily notes of this Obsidian vault (default: OBSIDIAN_VAULT)")
+		rollupsFlag         = flag.Bool("rollups", false, "Print item counts and scheduled hours per week and per month across all analyzers")
+		periodFlag          = flag.String("period", "", "Period preset overriding START_DATE/END_DATE (last-month, last-quarter, this-year, 2024, 2024-H2, 2024-Q3, 2024-07, ...)")
+		workOnlyFlag        = flag.Bool("work-only", false, "Run only sources classified as work in config/scopes.yaml (output goes to stats-work/)")
+		personalOnlyFlag    = flag.Bool("personal-only", false, "Run only sources classified as personal in config/scopes.yaml (output goes to stats-personal/)")
+		backlogProfileFlag  = flag.String("backlog-profile", "", "Backlog profiles to analyze, comma-separated, or all (default: BACKLOG_PROFILE, else all)")
+		topFlag             = flag.Int("top", common.DefaultRankingLimit, "Entries listed per ranking (repositories, labels, event titles, databases, activity types, ...); 0 lists all")
+		summaryOnlyFlag     = flag.Bool("summary-only", false, "Print only counts, fetched from search totals and count endpoints where the source has them, without per-item listings")
+		reconcileFlag       = flag.Bool("reconcile", false, "Compare scheduled, logged, and estimated hours per project per week and save reconciliation.csv")
 	)
 	flag.Parse()
 
+	if *noCacheFlag {
+		common.DisableHTTPCache()
+	}
+	if *topFlag < 0 {
+		log.Fatalf("-top must be 0 or more, got %d", *topFlag)
+	}
+	common.SetRankingLimit(*topFlag)
+
+	if err := applyDateFlags(*periodFlag, *startFlag, *endFlag); err != nil {
+		log.Fatalf("Invalid date range: %v", err)
+	}
+
 	if *helpFlag {
 		printHelp()
 		return
@@ -42,210 +107,1996 @@ func main() {
 		return
 	}
 
-	// Handle Backlog profiles listing
-	if *listBacklogProfiles {
-		handleListBacklogProfiles()
-		return
+	// Handle subcommands (e.g. "dev-stats doctor"); import-bundle continues with a regular run on the bundle
+	var importedBundle *bundle.Bundle
+	if flag.NArg() > 0 {
+		if flag.Arg(0) != "import-bundle" {
+			handleCommand(flag.Arg(0), flag.Args()[1:])
+			return
+		}
+		importedBundle = importBundle(flag.Args()[1:])
+		if *analyzerFlag == "" {
+			*analyzerFlag = strings.Join(importedBundle.Analyzers, ",")
+		}
+	}
+
+	// Handle Backlog profiles listing
+	if *listBacklogProfiles {
+		handleListBacklogProfiles()
+		return
+	}
+
+	// Handle Backlog listing mode
+	if *listBacklogFlag || *listBacklogProject != "" || *listBacklogClear {
+		handleBacklogList(*listBacklogProject, *listBacklogClear)
+		return
+	}
+
+	// Handle download mode
+	if *downloadFlag != "" {
+		handleDownload(*downloadFlag)
+		return
+	}
+
+	// Handle Google Workspace download mode
+	if *downloadGoogleFlag {
+		handleDownloadGoogle()
+		return
+	}
+
+	if *analyzerFlag == "" {
+		fmt.Println("Error: -analyzer flag is required")
+		printHelp()
+		os.Exit(1)
+	}
+
+	// Load common configuration
+	config, err := common.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Refuse to run if today is past END_DATE: results would be incomplete
+	// because APIs filter by last_edited_time, so pages updated after END_DATE
+	// would be excluded even if they were active during the target period.
+	// A past period requested on the command line is run with a warning.
+	if time.Now().After(config.EndDate.AddDate(0, 0, 1)) {
+		if common.DateRangeOverridden() {
+			log.Printf("Warning: the period ended on %s. Notion and Google Workspace items edited after it are not counted.",
+				config.EndDate.Format("2006-01-02"))
+		} else {
+			log.Fatalf("Error: today (%s) is past END_DATE (%s). Running now would produce incomplete stats because active files updated after END_DATE would be excluded. Update END_DATE in .env before running.",
+				time.Now().Format("2006-01-02"),
+				config.EndDate.Format("2006-01-02"))
+		}
+	}
+
+	if *outputFlag != "text" && *outputFlag != "json" && *outputFlag != "markdown" && *outputFlag != "csv" {
+		log.Fatalf("Unknown output format: %s (expected text, json, markdown, or csv)", *outputFlag)
+	}
+
+	scope, scopes, err := loadRunScope(*workOnlyFlag, *personalOnlyFlag)
+	if err != nil {
+		log.Fatalf("Invalid scope: %v", err)
+	}
+
+	// Create analyzers
+	analyzers := newAnalyzers()
+
+	// Determine which analyzers to run
+	var analyzersToRun []common.Analyzer
+	requestedAnalyzers := parseAnalyzerNames(*analyzerFlag)
+
+	for _, name := range requestedAnalyzers {
+		if name == "backlog" {
+			// Handle Backlog separately due to multi-profile support
+			continue
+		}
+		if name == "github" {
+			// The default account and each GITHUB_<PROFILE>_* account run as separate analyzers
+			for _, githubAnalyzer := range github.NewGitHubAnalyzers() {
+				analyzersToRun = append(analyzersToRun, githubAnalyzer)
+			}
+			continue
+		}
+		if analyzer, exists := analyzers[name]; exists {
+			analyzersToRun = append(analyzersToRun, analyzer)
+		} else {
+			log.Fatalf("Unknown analyzer: %s", name)
+		}
+	}
+
+	// Check if backlog was requested
+	backlogRequested := false
+	for _, name := range requestedAnalyzers {
+		if name == "backlog" {
+			backlogRequested = true
+			break
+		}
+	}
+
+	if len(analyzersToRun) == 0 && !backlogRequested {
+		log.Fatal("No valid analyzers specified")
+	}
+
+	var backlogProfiles []backlog.BacklogProfile
+	if backlogRequested {
+		backlogProfiles, err = backlog.SelectProfiles(*backlogProfileFlag)
+		if err != nil {
+			log.Fatalf("Invalid -backlog-profile: %v", err)
+		}
+	}
+
+	// A report for one scope must never mix in sources of the other one
+	analyzersToRun = filterByScope(analyzersToRun, scopes, scope)
+	if len(analyzersToRun) == 0 && !backlogRequested {
+		log.Fatalf("No requested analyzer is classified as %s in config/scopes.yaml", scope)
+	}
+
+	// Calendar and Notion items come from the bundle, since Google Calendar and ICS files are not read through it
+	if importedBundle != nil {
+		loadBundledRawData(importedBundle, config, analyzersToRun)
+	}
+
+	fmt.Printf("Running analysis from %s to %s\n",
+		config.StartDate.Format("2006-01-02"),
+		config.EndDate.Format("2006-01-02"))
+
+	// Create output directory
+	outputDir := createOutputDirectory(config.StartDate, config.EndDate, scope)
+	fmt.Printf("Output directory: %s\n", outputDir)
+	fileLimits := loadFileLimits()
+
+	// Run analyzers
+	var results []*common.AnalysisResult
+
+	// Run Backlog analyzers for all profiles
+	if backlogRequested {
+		if len(backlogProfiles) == 0 {
+			log.Println("Warning: No Backlog profiles found. Please set BACKLOG_<PROFILE>_* environment variables.")
+		} else {
+			for _, profile := range backlogProfiles {
+				if !inScope(fmt.Sprintf("backlog-%s", strings.ToLower(profile.Name)), scopes, scope) {
+					continue
+				}
+				if !profile.IsAnalysisReady() {
+					fmt.Printf("⚠️  Backlog profile '%s' is missing PROJECT_ID. Skipping analysis.\n", profile.Name)
+					fmt.Printf("    Run 'make list-backlog' to find the ID.\n\n")
+					continue
+				}
+
+				analyzer := backlog.NewBacklogAnalyzerWithProfile(&profile)
+				analyzerName := fmt.Sprintf("backlog-%s", strings.ToLower(profile.Name))
+				filename := fmt.Sprintf("%s-stats.txt", analyzerName)
+				filePath := filepath.Join(outputDir, filename)
+
+				// Create file writer; large reports are split into detail pages
+				statsFile, err := common.NewPagedFile(filePath, fileLimits)
+				if err != nil {
+					log.Printf("Warning: Failed to create output file %s: %v", filePath, err)
+					continue
+				}
+				defer statsFile.Close()
+
+				// Create multi-writer to write to both stdout and file
+				writer := io.MultiWriter(os.Stdout, statsFile)
+
+				// Print header
+				fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+				fmt.Fprintf(writer, "Running Backlog analyzer (%s)...\n", profile.Name)
+				fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+				result, err := runAnalyzer(analyzer, config, writer, *summaryOnlyFlag)
+				if err != nil {
+					log.Printf("Error running Backlog analyzer (%s): %v", profile.Name, err)
+					continue
+				}
+
+				statsFile.Finish(result)
+				common.PrintWarningNote(writer, result)
+				printStatsFileSaved(writer, statsFile)
+				if *splitDetailsFlag {
+					splitResultDetails(writer, outputDir, analyzerName, result)
+				}
+				if *outputFlag == "json" {
+					saveResultJSON(writer, outputDir, analyzerName, result)
+				}
+				if *outputFlag == "csv" {
+					saveResultCSV(writer, outputDir, analyzerName, result)
+				}
+
+				closeStatsFile(statsFile)
+				results = append(results, result)
+			}
+		}
+	}
+
+	// Run other analyzers
+	for _, analyzer := range analyzersToRun {
+		analyzerName := outputName(analyzer.GetName())
+		filename := fmt.Sprintf("%s-stats.txt", analyzerName)
+		filePath := filepath.Join(outputDir, filename)
+
+		// Create file writer; large reports are split into detail pages
+		statsFile, err := common.NewPagedFile(filePath, fileLimits)
+		if err != nil {
+			log.Printf("Warning: Failed to create output file %s: %v", filePath, err)
+			continue
+		}
+		defer statsFile.Close()
+
+		// Create multi-writer to write to both stdout and file
+		writer := io.MultiWriter(os.Stdout, statsFile)
+
+		// Print header
+		fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+		fmt.Fprintf(writer, "Running %s analyzer...\n", analyzer.GetName())
+		fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+		result, err := runAnalyzer(analyzer, config, writer, *summaryOnlyFlag)
+		if err != nil {
+			log.Printf("Error running %s analyzer: %v", analyzer.GetName(), err)
+			continue
+		}
+
+		statsFile.Finish(result)
+		common.PrintWarningNote(writer, result)
+		printStatsFileSaved(writer, statsFile)
+		if *splitDetailsFlag {
+			splitResultDetails(writer, outputDir, analyzerName, result)
+		}
+		if *outputFlag == "json" {
+			saveResultJSON(writer, outputDir, analyzerName, result)
+		}
+		if *outputFlag == "csv" {
+			saveResultCSV(writer, outputDir, analyzerName, result)
+		}
+
+		// Keep fetched items so that `dev-stats recategorize` can apply new rules later
+		if categorized, ok := analyzer.(categorizedAnalyzer); ok && !*summaryOnlyFlag {
+			rawPath := rawDataPath(config, analyzerName)
+			if err := categorized.SaveRawData(rawPath); err != nil {
+				log.Printf("Warning: Failed to save raw data for %s: %v", analyzer.GetName(), err)
+			} else {
+				fmt.Fprintf(writer, "📁 Raw data saved to: %s\n", rawPath)
+			}
+		}
+
+		closeStatsFile(statsFile)
+		results = append(results, result)
+	}
+
+	if github.MergeProfilesFromEnv() {
+		results = mergeGitHubResults(results)
+	}
+
+	// A quick check stops at the counts: the sections below and the history need the items
+	if *summaryOnlyFlag {
+		if len(results) > 1 {
+			printOverallSummary(results)
+		}
+		common.PrintWarnings(os.Stdout, results)
+		fmt.Println("\nAnalysis completed successfully!")
+		return
+	}
+
+	// Manual overrides take precedence over what analyzers derived from keyword rules
+	overrides := loadOverrides()
+	for _, result := range results {
+		overrides.Apply(result.Activities)
+	}
+
+	// The same work often shows up in several sources; group it so that it is counted once
+	workItems := common.GroupWorkItems(results, overrides.SameAsLinks())
+
+	// Print overall summary
+	if len(results) > 1 {
+		printOverallSummary(results)
+		common.PrintAlignmentReport(os.Stdout, common.CheckAlignment(results))
+		common.PrintLinkedWorkItems(os.Stdout, workItems)
+	}
+	common.PrintProjectBreakdown(os.Stdout, workItems)
+	common.PrintOnCallReport(os.Stdout, results)
+	printSprintRollups(config, results)
+	printEffortEstimate(workItems)
+
+	// Qualitative wins logged with `dev-stats log`
+	if achievements, err := common.LoadAchievements(common.DefaultAchievementsPath); err != nil {
+		log.Printf("Warning: Failed to load achievements: %v", err)
+	} else {
+		common.PrintAchievements(os.Stdout, common.AchievementsInPeriod(achievements, config.StartDate, config.EndDate))
+	}
+
+	if *kudosFlag {
+		printRecognition(config)
+	}
+
+	// Mid-period check-ins: project each metric to END_DATE at the current pace
+	if *extrapolateFlag {
+		common.PrintRunRate(os.Stdout, results, config.ElapsedFraction(time.Now()))
+	}
+
+	// Audit a surprising number: which items were counted
+	if *explainFlag != "" {
+		common.PrintExplanation(os.Stdout, results, *explainFlag)
+	}
+
+	// What happened each day, across all sources
+	if *timelineFlag {
+		saveTimeline(outputDir, common.BuildTimeline(results, config.StartDate, config.EndDate))
+	}
+
+	// Long periods are easier to read as a trend than as one total
+	if *rollupsFlag {
+		common.PrintRollup(os.Stdout, common.RollupWeeks(results, config.StartDate, config.EndDate, loadWeekConfig()))
+		common.PrintRollup(os.Stdout, common.RollupMonths(results, config.StartDate, config.EndDate))
+	}
+
+	// Weeks where calendar, time tracker, and estimates tell different stories
+	if *reconcileFlag {
+		saveReconciliation(outputDir, workItems)
+	}
+
+	// Per-day counts are kept across runs so that streaks can span periods
+	history, err := common.LoadHistory(common.DefaultHistoryPath)
+	if err != nil {
+		log.Printf("Warning: Failed to load history: %v", err)
+	} else {
+		history.Record(results)
+		if err := history.Save(common.DefaultHistoryPath); err != nil {
+			log.Printf("Warning: Failed to save history: %v", err)
+		}
+		if *gamificationFlag {
+			var activities []common.Activity
+			for _, result := range results {
+				activities = append(activities, result.Activities...)
+			}
+			streaks := common.ComputeStreaks(history, config.EndDate, common.WeeklyGoalFromEnv(), loadWeekConfig())
+			common.PrintGamification(os.Stdout, streaks, common.AwardBadges(streaks, activities))
+		}
+	}
+
+	// One Markdown document covering every analyzer, written before the upload so that it is synced too
+	if *outputFlag == "markdown" {
+		reportPath := filepath.Join(outputDir, report.ReportFileName)
+		if err := report.SaveMarkdown(reportPath, results, config.StartDate, config.EndDate); err != nil {
+			log.Printf("Warning: Failed to save Markdown report: %v", err)
+		} else {
+			fmt.Printf("\n📁 Markdown report saved to: %s\n", reportPath)
+		}
+	}
+
+	if vaultPath := *obsidianFlag; vaultPath != "" || os.Getenv("OBSIDIAN_VAULT") != "" {
+		if vaultPath == "" {
+			vaultPath = os.Getenv("OBSIDIAN_VAULT")
+		}
+		exportObsidian(vaultPath, common.BuildTimeline(results, config.StartDate, config.EndDate))
+	}
+
+	// UPLOAD_TARGET is read after .env has been loaded so that scheduled runs can configure it there
+	if uploadTarget := *uploadFlag; uploadTarget != "" || os.Getenv("UPLOAD_TARGET") != "" {
+		if uploadTarget == "" {
+			uploadTarget = os.Getenv("UPLOAD_TARGET")
+		}
+		uploadStats(uploadTarget, config, outputDir)
+	}
+
+	// Summary metrics for team-report, which aggregates what the members push
+	if location := os.Getenv(team.StoreEnv); location != "" {
+		shareTeamMetrics(location, config, scope, results)
+	}
+
+	// Retention runs after the upload, so that pruned details were synced before they leave this machine
+	if cache.RetentionConfigured() {
+		if _, err := pruneCaches(os.Stdout, cache.Sources(), time.Now()); err != nil {
+			log.Printf("Warning: Failed to prune data past its retention: %v", err)
+		}
+	}
+
+	// Failed optional lookups, collected instead of interleaved with the reports
+	common.PrintWarnings(os.Stdout, results)
+
+	fmt.Println("\nAnalysis completed successfully!")
+}
+
+// runAnalyzer runs an analyzer, or only its counts with -summary-only
+func runAnalyzer(analyzer common.Analyzer, cfg *common.Config, writer io.Writer, summaryOnly bool) (*common.AnalysisResult, error) {
+	if summaryOnly {
+		return common.AnalyzeSummary(analyzer, cfg, writer)
+	}
+	return analyzer.Analyze(cfg, writer)
+}
+
+// handleCommand dispatches subcommands given as positional arguments
+func handleCommand(command string, args []string) {
+	switch command {
+	case "doctor":
+		handleDoctor()
+	case "watch":
+		handleWatch(args)
+	case "recategorize":
+		handleRecategorize(args)
+	case "oss-report":
+		handleOSSReport()
+	case "review":
+		handleReview(args)
+	case "log":
+		handleLog(args)
+	case "review-reminders":
+		handleReviewReminders(args)
+	case "action-items":
+		handleActionItems(args)
+	case "cache":
+		handleCache(args)
+	case "whoami":
+		handleWhoAmI()
+	case "notion":
+		handleNotion(args)
+	case "github":
+		handleGitHub(args)
+	case "backlog":
+		handleBacklog(args)
+	case "snapshot":
+		handleSnapshot(args)
+	case "completion":
+		handleCompletion(args)
+	case "man":
+		completion.WriteMan(os.Stdout, completionSpec(), manEnvironment)
+	case "version":
+		handleVersion(args)
+	case "self-update":
+		handleSelfUpdate(args)
+	case "export-bundle":
+		handleExportBundle(args)
+	case "team-report":
+		handleTeamReport(args)
+	case "ingest":
+		handleIngest(args)
+	case "serve":
+		handleServe(args)
+	case "quick":
+		handleQuick(args)
+	default:
+		fmt.Printf("Error: unknown command: %s\n", command)
+		printHelp()
+		os.Exit(1)
+	}
+}
+
+// loadRunScope returns the scope requested with -work-only or -personal-only ("" for none) and the classification
+func loadRunScope(workOnly, personalOnly bool) (string, *config.ScopeConfig, error) {
+	if workOnly && personalOnly {
+		return "", nil, common.NewError("-work-only and -personal-only cannot be combined")
+	}
+	scope := ""
+	if workOnly {
+		scope = config.ScopeWork
+	} else if personalOnly {
+		scope = config.ScopePersonal
+	}
+	if scope == "" {
+		return "", nil, nil
+	}
+	scopes, err := config.LoadScopeConfig("")
+	if err != nil {
+		return "", nil, err
+	}
+	if scopes.IsEmpty() {
+		return "", nil, common.NewError("%s classifies no sources (see config/scopes.sample.yaml)", config.DefaultScopeConfigPath)
+	}
+	fmt.Printf("Scope: %s sources only (%s)\n", scope, config.DefaultScopeConfigPath)
+	return scope, scopes, nil
+}
+
+// inScope reports whether the source with this output name belongs in the run, printing why it is skipped otherwise
+func inScope(name string, scopes *config.ScopeConfig, scope string) bool {
+	if scopes.Allows(name, scope) {
+		return true
+	}
+	if classified := scopes.Scope(name); classified != "" {
+		fmt.Printf("Skipping %s: classified as %s\n", name, classified)
+	} else {
+		fmt.Printf("Skipping %s: not classified in %s\n", name, config.DefaultScopeConfigPath)
+	}
+	return false
+}
+
+// filterByScope keeps the analyzers whose sources belong in the run
+func filterByScope(analyzers []common.Analyzer, scopes *config.ScopeConfig, scope string) []common.Analyzer {
+	var kept []common.Analyzer
+	for _, analyzer := range analyzers {
+		if inScope(outputName(analyzer.GetName()), scopes, scope) {
+			kept = append(kept, analyzer)
+		}
+	}
+	return kept
+}
+
+// outputName turns an analyzer name like "GitHub (WORK)" into the output file prefix "github-work"
+func outputName(analyzerName string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "-", "(", "", ")", "").Replace(analyzerName))
+}
+
+// mergeGitHubResults replaces the results of several GitHub accounts with one combined result (GITHUB_MERGE_PROFILES)
+func mergeGitHubResults(results []*common.AnalysisResult) []*common.AnalysisResult {
+	var githubResults, merged []*common.AnalysisResult
+	for _, result := range results {
+		if strings.HasPrefix(result.AnalyzerName, "GitHub") {
+			githubResults = append(githubResults, result)
+		}
+	}
+	if len(githubResults) < 2 {
+		return results
+	}
+	for _, result := range results {
+		if result == githubResults[0] {
+			merged = append(merged, common.MergeResults("GitHub (all accounts)", githubResults))
+		} else if !strings.HasPrefix(result.AnalyzerName, "GitHub") {
+			merged = append(merged, result)
+		}
+	}
+	return merged
+}
+
+// newAnalyzers creates the analyzers by name. Backlog analyzers are created per profile by the caller.
+func newAnalyzers() map[string]common.Analyzer {
+	analyzers := make(map[string]common.Analyzer)
+
+	if githubAnalyzer := github.NewGitHubAnalyzer(); githubAnalyzer != nil {
+		analyzers["github"] = githubAnalyzer
+	}
+	if calendarAnalyzer, err := calendar.NewCalendarAnalyzer(); err != nil {
+		log.Printf("Warning: Calendar analyzer unavailable: %v", err)
+	} else {
+		analyzers["calendar"] = calendarAnalyzer
+	}
+	if notionAnalyzer, err := notion.NewNotionAnalyzer(); err != nil {
+		log.Printf("Warning: Notion analyzer unavailable: %v", err)
+	} else {
+		analyzers["notion"] = notionAnalyzer
+	}
+	analyzers["google"] = google.NewGDocsAnalyzer()
+	analyzers["todoist"] = todoist.NewTodoistAnalyzer()
+	analyzers["jira"] = jira.NewJiraAnalyzer()
+	analyzers["harvest"] = harvest.NewHarvestAnalyzer()
+	analyzers["support"] = support.NewSupportAnalyzer()
+	analyzers["opsgenie"] = opsgenie.NewOpsgenieAnalyzer()
+	analyzers["copilot"] = copilot.NewCopilotAnalyzer()
+	analyzers["gitea"] = gitea.NewGiteaAnalyzer()
+	analyzers["phabricator"] = phabricator.NewPhabricatorAnalyzer()
+	analyzers["focus"] = focus.NewFocusAnalyzer()
+	analyzers["github-archive"] = github.NewArchiveAnalyzer()
+	analyzers["webhooks"] = ingest.NewWebhookAnalyzer()
+	return analyzers
+}
+
+// parseAnalyzerNames splits -analyzer into analyzer names, expanding "all"
+func parseAnalyzerNames(value string) []string {
+	if value == "all" {
+		return []string{"github", "backlog", "calendar", "notion", "google", "todoist", "jira", "harvest", "support", "opsgenie", "copilot", "gitea", "phabricator", "focus"}
+	}
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	return names
+}
+
+// applyDateFlags resolves -period and -start/-end (which win over the preset) into the date range used by common.LoadConfig
+func applyDateFlags(period, start, end string) error {
+	if period != "" {
+		periodStart, periodEnd, err := common.ParsePeriod(period, time.Now())
+		if err != nil {
+			return err
+		}
+		if start == "" {
+			start = periodStart.Format("2006-01-02")
+		}
+		if end == "" {
+			end = periodEnd.Format("2006-01-02")
+		}
+	}
+	for _, date := range []struct{ flag, value string }{{"-start", start}, {"-end", end}} {
+		if date.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date.value); err != nil {
+			return common.NewError("%s must be YYYY-MM-DD, got '%s'", date.flag, date.value)
+		}
+	}
+	common.OverrideDateRange(start, end)
+	return nil
+}
+
+// handleDoctor checks credentials, paths, config files, and API reachability
+func handleDoctor() {
+	// Load .env so that credentials are visible to the checks
+	godotenv.Load()
+
+	if !doctor.NewDoctor().Run(os.Stdout) {
+		os.Exit(1)
+	}
+}
+
+// handleOSSReport writes authored open-source PRs with merge status and stars as Markdown
+func handleOSSReport() {
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	contributions, err := github.NewGitHubAnalyzer().CollectOSSContributions(cfg, os.Stdout)
+	if err != nil {
+		log.Fatalf("Failed to collect open-source contributions: %v", err)
+	}
+
+	outputDir := createOutputDirectory(cfg.StartDate, cfg.EndDate, "")
+	filePath := filepath.Join(outputDir, "oss-report.md")
+	file, err := os.Create(filePath)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	fmt.Println()
+	github.WriteOSSReport(io.MultiWriter(os.Stdout, file), contributions, cfg.StartDate, cfg.EndDate)
+	fmt.Printf("\n📁 Output saved to: %s\n", filePath)
+}
+
+// handleReview runs the analyzers without printing their reports and writes a self-review template as Markdown
+func handleReview(args []string) {
+	flags := flag.NewFlagSet("review", flag.ExitOnError)
+	analyzerFlag := flags.String("analyzer", "all", "Analyzers to include (github,backlog,calendar,notion,google,todoist,jira,harvest,support,opsgenie,copilot,gitea,phabricator,focus,github-archive,webhooks,all)")
+	backlogProfileFlag := flags.String("backlog-profile", "", "Backlog profiles to include, comma-separated, or all (default: BACKLOG_PROFILE, else all)")
+	flags.Parse(args)
+
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	backlogProfiles, err := backlog.SelectProfiles(*backlogProfileFlag)
+	if err != nil {
+		log.Fatalf("Invalid -backlog-profile: %v", err)
+	}
+
+	analyzers := newAnalyzers()
+	var results []*common.AnalysisResult
+	run := func(name string, analyzer common.Analyzer) {
+		fmt.Printf("🔄 Running %s analyzer...\n", name)
+		result, err := analyzer.Analyze(cfg, io.Discard)
+		if err != nil {
+			log.Printf("Error running %s analyzer: %v", name, err)
+			return
+		}
+		fmt.Printf("✓ %s: %d items\n", name, len(result.Activities))
+		results = append(results, result)
+	}
+	for _, name := range parseAnalyzerNames(*analyzerFlag) {
+		if name == "backlog" {
+			for _, profile := range backlogProfiles {
+				if profile.IsAnalysisReady() {
+					run(fmt.Sprintf("Backlog (%s)", profile.Name), backlog.NewBacklogAnalyzerWithProfile(&profile))
+				}
+			}
+			continue
+		}
+		if name == "github" {
+			for _, githubAnalyzer := range github.NewGitHubAnalyzers() {
+				run(githubAnalyzer.GetName(), githubAnalyzer)
+			}
+			continue
+		}
+		analyzer, exists := analyzers[name]
+		if !exists {
+			log.Fatalf("Unknown analyzer: %s", name)
+		}
+		run(analyzer.GetName(), analyzer)
+	}
+	if len(results) == 0 {
+		log.Fatal("No analyzer produced results")
+	}
+
+	overrides := loadOverrides()
+	for _, result := range results {
+		overrides.Apply(result.Activities)
+	}
+	review := report.SelfReview{
+		Results:   results,
+		WorkItems: common.GroupWorkItems(results, overrides.SameAsLinks()),
+		StartDate: cfg.StartDate,
+		EndDate:   cfg.EndDate,
+	}
+	if achievements, err := common.LoadAchievements(common.DefaultAchievementsPath); err != nil {
+		log.Printf("Warning: Failed to load achievements: %v", err)
+	} else {
+		review.Achievements = common.AchievementsInPeriod(achievements, cfg.StartDate, cfg.EndDate)
+	}
+
+	outputDir := createOutputDirectory(cfg.StartDate, cfg.EndDate, "")
+	filePath := filepath.Join(outputDir, report.ReviewFileName)
+	if err := report.SaveSelfReview(filePath, review); err != nil {
+		log.Fatalf("Failed to save self-review: %v", err)
+	}
+	fmt.Printf("\n📁 Self-review saved to: %s\n", filePath)
+}
+
+// handleActionItems reports open vs completed to-dos assigned to you in downloaded Notion meeting notes
+func handleActionItems(args []string) {
+	flags := flag.NewFlagSet("action-items", flag.ExitOnError)
+	dirFlag := flags.String("dir", "", "Directory of downloaded meeting notes (default: output/<period>/notion)")
+	flags.Parse(args)
+
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	dir := *dirFlag
+	if dir == "" {
+		dir = filepath.Join("output", cfg.PeriodLabel(), "notion")
+	}
+
+	report, err := notion.CollectActionItems(dir, cfg.StartDate, cfg.EndDate, notion.ActionItemAssigneesFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to collect action items: %v", err)
+	}
+
+	outputDir := createOutputDirectory(cfg.StartDate, cfg.EndDate, "")
+	filePath := filepath.Join(outputDir, "action-items.txt")
+	file, err := os.Create(filePath)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	notion.PrintActionItemReport(io.MultiWriter(os.Stdout, file), report, cfg.StartDate, cfg.EndDate)
+	fmt.Printf("\n📁 Output saved to: %s\n", filePath)
+}
+
+// handleNotion runs Notion workspace helpers (dev-stats notion databases)
+func handleNotion(args []string) {
+	if len(args) == 0 || args[0] != "databases" {
+		fmt.Println("Usage: dev-stats notion databases")
+		os.Exit(1)
+	}
+	godotenv.Load()
+
+	notionAnalyzer, err := notion.NewNotionAnalyzer()
+	if err != nil {
+		log.Fatalf("Failed to create Notion analyzer: %v", err)
+	}
+	databases, err := notionAnalyzer.ListDatabases(os.Stdout)
+	if err != nil {
+		log.Fatalf("Failed to list Notion databases: %v", err)
+	}
+	notion.PrintDatabases(os.Stdout, databases)
+}
+
+// handleGitHub runs GitHub helpers (dev-stats github repos)
+func handleGitHub(args []string) {
+	if len(args) == 0 || args[0] != "repos" {
+		fmt.Println("Usage: dev-stats github repos")
+		os.Exit(1)
+	}
+
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	repos, err := github.NewGitHubAnalyzer().ListActiveRepositories(cfg, os.Stdout)
+	if err != nil {
+		log.Fatalf("Failed to list GitHub repositories: %v", err)
+	}
+	github.PrintRepoActivity(os.Stdout, repos, cfg.StartDate, cfg.EndDate)
+}
+
+// handleBacklog runs Backlog helpers (dev-stats backlog members)
+func handleBacklog(args []string) {
+	if len(args) == 0 || args[0] != "members" {
+		fmt.Println("Usage: dev-stats backlog members [-backlog-profile NAME]")
+		os.Exit(1)
+	}
+	flags := flag.NewFlagSet("backlog members", flag.ExitOnError)
+	backlogProfileFlag := flags.String("backlog-profile", "", "Backlog profiles to report, comma-separated, or all (default: BACKLOG_PROFILE, else all)")
+	flags.Parse(args[1:])
+
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	profiles, err := backlog.SelectProfiles(*backlogProfileFlag)
+	if err != nil {
+		log.Fatalf("Invalid -backlog-profile: %v", err)
+	}
+	if len(profiles) == 0 {
+		log.Fatalf("No Backlog profiles configured. Run './bin/dev-stats -list-backlog-profiles' for configuration help.")
+	}
+
+	outputDir := createOutputDirectory(cfg.StartDate, cfg.EndDate, "")
+	for _, profile := range profiles {
+		fmt.Printf("🔄 Collecting member activity of Backlog profile %s...\n", profile.Name)
+		rows, warnings, err := backlog.NewBacklogAnalyzerWithProfile(&profile).MemberActivities(cfg, os.Stdout)
+		if err != nil {
+			log.Fatalf("Failed to collect Backlog member activity (%s): %v", profile.Name, err)
+		}
+		backlog.PrintMemberActivities(os.Stdout, rows, cfg.StartDate, cfg.EndDate)
+		if len(warnings) > 0 {
+			fmt.Printf("\n%d degraded items\n", common.DegradedItems(warnings))
+			common.PrintWarningList(os.Stdout, warnings)
+		}
+
+		filePath := filepath.Join(outputDir, fmt.Sprintf("backlog-%s-members.csv", strings.ToLower(profile.Name)))
+		if err := common.WriteCSVFile(filePath, common.NewCSVTable("backlog_members", rows)); err != nil {
+			log.Fatalf("Failed to write %s: %v", filePath, err)
+		}
+		fmt.Printf("\n📁 Output saved to: %s\n", filePath)
+	}
+}
+
+// completionSpec describes the global flags and the subcommands for shell completion and the man page. Values of
+// -analyzer and -backlog-profile are listed when completing, by "dev-stats completion values <kind>".
+func completionSpec() *completion.Spec {
+	analyzers := completion.Flag{Name: "analyzer", Arg: "string", Dynamic: "analyzers", List: true}
+	profiles := completion.Flag{Name: "backlog-profile", Arg: "string", Dynamic: "backlog-profiles", List: true}
+	cacheArgs := []string{"ls", "stats", "clear", "prune"}
+	for _, source := range cache.Sources() {
+		cacheArgs = append(cacheArgs, source.Name)
+	}
+
+	return &completion.Spec{
+		Program: "dev-stats",
+		Summary: "development statistics from GitHub, Backlog, calendars, Notion, and other work tools",
+		Flags: completion.FlagsOf(flag.CommandLine, map[string]completion.Flag{
+			"analyzer":        analyzers,
+			"backlog-profile": profiles,
+			"output":          {Values: []string{"text", "json", "markdown", "csv"}},
+			"period":          {Values: common.PeriodPresets},
+			"download":        {File: true},
+			"obsidian":        {File: true},
+		}),
+		Commands: []completion.Command{
+			{Name: "doctor", Summary: "Check credentials, paths, config files, and API reachability"},
+			{Name: "watch", Synopsis: "[-analyzer calendar,notion] [-interval 2s]", Summary: "Re-run categorization when config/*.yaml or .env changes",
+				Flags: []completion.Flag{{Name: "analyzer", Arg: "string", Values: []string{"calendar", "notion"}, List: true}, {Name: "interval", Arg: "duration"}}},
+			{Name: "recategorize", Synopsis: "[-analyzer calendar,notion]", Summary: "Apply current categorization rules to stored raw data without fetching",
+				Flags: []completion.Flag{{Name: "analyzer", Arg: "string", Values: []string{"calendar", "notion"}, List: true}}},
+			{Name: "oss-report", Summary: "Write authored open-source PRs with merge status and stars as Markdown"},
+			{Name: "review", Synopsis: "[-analyzer all] [-backlog-profile NAME]", Summary: "Write a self-review template as Markdown",
+				Flags: []completion.Flag{analyzers, profiles}},
+			{Name: "log", Synopsis: "[-date YYYY-MM-DD] <text>", Summary: "Log a manual achievement shown in the period report",
+				Flags: []completion.Flag{{Name: "date", Arg: "string"}}},
+			{Name: "review-reminders", Synopsis: "[-to todoist|things|backlog] [-age 7] [-backlog-profile NAME] [-dry-run]",
+				Summary: "Create tasks for PRs awaiting your review and your aging PRs",
+				Flags: []completion.Flag{{Name: "to", Arg: "string", Values: []string{"todoist", "things", "backlog"}}, {Name: "age", Arg: "int"},
+					{Name: "backlog-profile", Arg: "string", Dynamic: "backlog-profiles"}, {Name: "dry-run"}}},
+			{Name: "action-items", Synopsis: "[-dir output/<period>/notion]", Summary: "Report open vs completed action items in downloaded Notion meeting notes",
+				Flags: []completion.Flag{{Name: "dir", Arg: "string", File: true}}},
+			{Name: "whoami", Summary: "Show the account and IDs behind each configured credential"},
+			{Name: "notion", Synopsis: "databases", Summary: "List databases shared with the Notion integration", Args: []string{"databases"}},
+			{Name: "github", Synopsis: "repos", Summary: "List repositories with your PRs in the period", Args: []string{"repos"}},
+			{Name: "backlog", Synopsis: "members [-backlog-profile NAME]", Summary: "Write activity counts per member of the space's projects as CSV",
+				Args: []string{"members"}, Flags: []completion.Flag{profiles}},
+			{Name: "snapshot", Synopsis: "[-update] [-dir testdata/snapshots] [case...]", Summary: "Run analyzers on recorded API responses and diff against expected reports",
+				Flags: []completion.Flag{{Name: "update"}, {Name: "dir", Arg: "string", File: true}}},
+			{Name: "cache", Synopsis: "ls|stats|clear|prune [source...]", Summary: "List, summarize, clear, or prune cached data", Args: cacheArgs},
+			{Name: "completion", Synopsis: strings.Join(completion.Shells, "|"), Summary: "Print the shell completion script", Args: completion.Shells},
+			{Name: "man", Summary: "Print the man page (roff)"},
+			{Name: "version", Synopsis: "[-check] [-channel stable|beta]", Summary: "Print the version, and with -check the latest release",
+				Flags: []completion.Flag{{Name: "check"}, {Name: "channel", Arg: "string", Values: selfupdate.Channels}}},
+			{Name: "self-update", Synopsis: "[-channel stable|beta] [-check] [-force]", Summary: "Replace the binary with the latest release after verifying its checksum",
+				Flags: []completion.Flag{{Name: "channel", Arg: "string", Values: selfupdate.Channels}, {Name: "check"}, {Name: "force"}}},
+			{Name: "export-bundle", Synopsis: "[-analyzer all] [-file dev-stats-<period>.bundle]", Summary: "Record the API responses of the period into an encrypted bundle",
+				Flags: []completion.Flag{analyzers, {Name: "file", Arg: "string", File: true}}},
+			{Name: "import-bundle", Synopsis: "<file>", Summary: "Write the reports of an exported bundle without calling any API"},
+			{Name: "team-report", Synopsis: "[-anonymize] [-store TEAM_STORE]", Summary: "Aggregate the metrics team members shared for the period as Markdown",
+				Flags: []completion.Flag{{Name: "anonymize"}, {Name: "store", Arg: "string"}}},
+			{Name: "ingest", Synopsis: "[-addr 127.0.0.1:8080]", Summary: "Receive GitHub/Backlog webhooks and store their events for -analyzer webhooks",
+				Flags: []completion.Flag{{Name: "addr", Arg: "string"}}},
+			{Name: "serve", Synopsis: "[-addr 127.0.0.1:8090]", Summary: "Serve saved results (periods, metrics, items) and daily history as a read-only JSON API",
+				Flags: []completion.Flag{{Name: "addr", Arg: "string"}}},
+			{Name: "quick", Synopsis: "[-json]", Summary: "Print this week's PRs, reviews, and meeting hours so far as one line, from stored data only",
+				Flags: []completion.Flag{{Name: "json"}}},
+		},
+	}
+}
+
+// manEnvironment lists the variables in the ENVIRONMENT section of the man page; the per-source credentials are
+// listed by -help and in .env.example
+var manEnvironment = [][2]string{
+	{"START_DATE, END_DATE", "Period to analyze (YYYY-MM-DD), unless -start/-end or -period is given"},
+	{"BACKLOG_PROFILE", "Backlog profiles analyzed by default, comma-separated"},
+	{"BACKLOG_<PROFILE>_API_KEY, BACKLOG_<PROFILE>_HOST", "Credentials of each Backlog space"},
+	{"GITHUB_TOKEN, GITHUB_USERNAME", "GitHub credentials"},
+	{"NOTION_TOKEN", "Notion integration token"},
+	{"UPLOAD_TARGET", "Default of -upload"},
+	{"OBSIDIAN_VAULT", "Default of -obsidian"},
+	{"STATS_MAX_FILE_LINES, STATS_MAX_FILE_MB", "Split larger stats files"},
+	{"DEV_STATS_CHANNEL", "Release channel of self-update and version -check: stable (default) or beta"},
+	{"DEV_STATS_UPDATE_REPO", "GitHub repository (owner/repo) releases are installed from"},
+	{"RETENTION_MONTHS, RETENTION_MONTHS_<SOURCE>", "Months cached and detailed data is kept; older files are pruned after each run"},
+	{"BUNDLE_PASSPHRASE", "Passphrase export-bundle encrypts bundles with and import-bundle decrypts them with"},
+	{"TEAM_STORE, TEAM_MEMBER", "Shared store (s3://, gs://, or a directory) each run pushes summary metrics to under the member's name"},
+	{"TEAM_METRICS", "Sources or metric IDs shared with the team, comma-separated (default: all)"},
+	{"SERVE_TOKEN", "Bearer token serve requires; needed to listen on other than a loopback address"},
+	{"GITHUB_WEBHOOK_SECRET, INGEST_TOKEN", "Secrets ingest verifies GitHub webhook signatures and Backlog webhook URLs (?token=) with"},
+}
+
+// handleCompletion prints the completion script for a shell, or with "values <kind>" the values completed for a flag
+func handleCompletion(args []string) {
+	if len(args) == 2 && args[0] == "values" {
+		switch args[1] {
+		case "analyzers":
+			for _, name := range append(parseAnalyzerNames("all"), "github-archive", "webhooks", "all") {
+				fmt.Println(name)
+			}
+		case "backlog-profiles":
+			godotenv.Load()
+			for _, profile := range backlog.LoadBacklogProfiles() {
+				fmt.Println(profile.Name)
+			}
+			fmt.Println("all")
+		}
+		return
+	}
+	if len(args) != 1 {
+		fmt.Printf("Usage: dev-stats completion %s\n", strings.Join(completion.Shells, "|"))
+		os.Exit(1)
+	}
+	if err := completion.Write(os.Stdout, args[0], completionSpec()); err != nil {
+		log.Fatalf("%v", err)
+	}
+}
+
+// defaultChannel returns DEV_STATS_CHANNEL, else stable
+func defaultChannel() string {
+	if channel := strings.TrimSpace(os.Getenv("DEV_STATS_CHANNEL")); channel != "" {
+		return channel
+	}
+	return "stable"
+}
+
+// handleVersion prints the version of the binary and, with -check, the latest release of the channel
+func handleVersion(args []string) {
+	godotenv.Load()
+	flags := flag.NewFlagSet("version", flag.ExitOnError)
+	checkFlag := flags.Bool("check", false, "Also show the latest release of the channel")
+	channelFlag := flags.String("channel", defaultChannel(), "Release channel: stable, or beta to include pre-releases (default: DEV_STATS_CHANNEL, else stable)")
+	flags.Parse(args)
+
+	fmt.Printf("dev-stats %s (%s/%s, %s)\n", version, runtime.GOOS, runtime.GOARCH, runtime.Version())
+	if !*checkFlag {
+		return
+	}
+	common.DisableHTTPCache()
+	release, err := selfupdate.NewUpdater().Latest(*channelFlag)
+	if err != nil {
+		log.Fatalf("Failed to check for updates: %v", err)
+	}
+	if selfupdate.CompareVersions(release.TagName, version) > 0 {
+		fmt.Printf("Update available: %s (%s channel, published %s) - run 'dev-stats self-update'\n",
+			release.TagName, *channelFlag, release.PublishedAt.Format("2006-01-02"))
+	} else {
+		fmt.Printf("Up to date (latest %s release: %s)\n", *channelFlag, release.TagName)
+	}
+}
+
+// handleSelfUpdate replaces the running binary with the latest release of the channel, verified against the
+// release's checksums.txt
+func handleSelfUpdate(args []string) {
+	godotenv.Load()
+	flags := flag.NewFlagSet("self-update", flag.ExitOnError)
+	channelFlag := flags.String("channel", defaultChannel(), "Release channel: stable, or beta to include pre-releases (default: DEV_STATS_CHANNEL, else stable)")
+	checkFlag := flags.Bool("check", false, "Only report whether an update is available")
+	forceFlag := flags.Bool("force", false, "Install the latest release even if it is not newer, or over a development build")
+	flags.Parse(args)
+
+	// Release lists must be current, and downloaded binaries don't belong in .http-cache/
+	common.DisableHTTPCache()
+	updater := selfupdate.NewUpdater()
+	release, err := updater.Latest(*channelFlag)
+	if err != nil {
+		log.Fatalf("Failed to check for updates: %v", err)
+	}
+	if selfupdate.CompareVersions(release.TagName, version) <= 0 && !*forceFlag {
+		fmt.Printf("dev-stats %s is up to date (latest %s release: %s)\n", version, *channelFlag, release.TagName)
+		return
+	}
+	if *checkFlag {
+		fmt.Printf("Update available: %s -> %s (%s)\n", version, release.TagName, release.HTMLURL)
+		return
+	}
+	if version == "dev" && !*forceFlag {
+		log.Fatalf("This is a development build; rebuild it from source, or run with -force to replace it with %s", release.TagName)
+	}
+
+	path, err := os.Executable()
+	if err == nil {
+		path, err = filepath.EvalSymlinks(path)
+	}
+	if err != nil {
+		log.Fatalf("Failed to locate the running binary: %v", err)
+	}
+	fmt.Printf("🔄 Installing %s from %s...\n", release.TagName, updater.Repository())
+	if err := updater.Install(release, path); err != nil {
+		log.Fatalf("Failed to update: %v", err)
+	}
+	fmt.Printf("✓ Updated %s from %s to %s (checksum verified)\n", path, version, release.TagName)
+}
+
+// handleSnapshot runs analyzers against recorded API responses and compares their reports with golden files
+func handleSnapshot(args []string) {
+	flags := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	updateFlag := flags.Bool("update", false, "Rewrite expected.txt with the current output instead of comparing")
+	dirFlag := flags.String("dir", snapshot.DefaultDir, "Directory of snapshot cases")
+	flags.Parse(args)
+
+	cases, err := snapshot.LoadCases(*dirFlag, flags.Args())
+	if err != nil {
+		log.Fatalf("Failed to load snapshot cases: %v", err)
+	}
+	failed, err := snapshot.Check(os.Stdout, cases, *updateFlag)
+	if err != nil {
+		log.Fatalf("Failed to run snapshot cases: %v", err)
+	}
+	if failed > 0 {
+		fmt.Printf("\n%d of %d snapshots changed. Review the diff and run 'make snapshot-update' if the change is intended.\n", failed, len(cases))
+		os.Exit(1)
+	}
+}
+
+// handleWhoAmI shows whose account each configured credential belongs to, with the IDs to put in .env
+func handleWhoAmI() {
+	godotenv.Load()
+
+	var resolvers []common.IdentityResolver
+	if os.Getenv("GITHUB_TOKEN") != "" {
+		resolvers = append(resolvers, github.NewGitHubAnalyzer())
+	}
+	for _, profile := range github.LoadGitHubProfiles() {
+		resolvers = append(resolvers, github.NewGitHubAnalyzerWithProfile(&profile))
+	}
+	for _, profile := range backlog.LoadBacklogProfiles() {
+		resolvers = append(resolvers, backlog.NewBacklogAnalyzerWithProfile(&profile))
+	}
+	if os.Getenv("NOTION_TOKEN") != "" {
+		if notionAnalyzer, err := notion.NewNotionAnalyzer(); err != nil {
+			log.Printf("Warning: Notion analyzer unavailable: %v", err)
+		} else {
+			resolvers = append(resolvers, notionAnalyzer)
+		}
+	}
+	// Only with a cached token, so that whoami never starts the browser authorization
+	if google.HasCachedToken() {
+		resolvers = append(resolvers, google.NewGDocsAnalyzer())
+	}
+	if os.Getenv("TODOIST_API_TOKEN") != "" {
+		resolvers = append(resolvers, todoist.NewTodoistAnalyzer())
+	}
+	if os.Getenv("JIRA_API_TOKEN") != "" {
+		resolvers = append(resolvers, jira.NewJiraAnalyzer())
+	}
+	if os.Getenv("HARVEST_ACCESS_TOKEN") != "" {
+		resolvers = append(resolvers, harvest.NewHarvestAnalyzer())
+	}
+	if os.Getenv("ZENDESK_API_TOKEN") != "" || os.Getenv("FRESHDESK_API_KEY") != "" {
+		resolvers = append(resolvers, support.NewSupportAnalyzer())
+	}
+	if os.Getenv("OPSGENIE_API_KEY") != "" {
+		resolvers = append(resolvers, opsgenie.NewOpsgenieAnalyzer())
+	}
+	if os.Getenv("GITEA_TOKEN") != "" {
+		resolvers = append(resolvers, gitea.NewGiteaAnalyzer())
+	}
+	if os.Getenv("PHABRICATOR_API_TOKEN") != "" {
+		resolvers = append(resolvers, phabricator.NewPhabricatorAnalyzer())
+	}
+	if collector := slack.NewKudosCollector(); collector != nil {
+		resolvers = append(resolvers, collector)
+	}
+	if len(resolvers) == 0 {
+		fmt.Println("No credentials configured. See .env.example.")
+		return
+	}
+
+	var identities []*common.Identity
+	for _, resolver := range resolvers {
+		identity, err := resolver.WhoAmI(io.Discard)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			continue
+		}
+		identities = append(identities, identity)
+	}
+	if len(identities) > 0 {
+		common.PrintIdentities(os.Stdout, identities)
+	}
+}
+
+// handleCache lists, summarizes, clears, or prunes cached data (dev-stats cache ls|stats|clear|prune [source...])
+func handleCache(args []string) {
+	flags := flag.NewFlagSet("cache", flag.ExitOnError)
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		fmt.Println("Usage: dev-stats cache ls|stats|clear|prune [source...]")
+		os.Exit(1)
+	}
+	action := flags.Arg(0)
+	if action != "ls" && action != "stats" && action != "clear" && action != "prune" {
+		log.Fatalf("Unknown cache action: %s (expected ls, stats, clear, or prune)", action)
+	}
+
+	// Without names, every source is covered except the persistent store, which must be named explicitly to be cleared
+	var sources []cache.Source
+	for _, name := range flags.Args()[1:] {
+		source, err := cache.Lookup(name)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		for _, source := range cache.Sources() {
+			if action != "clear" || !source.Store {
+				sources = append(sources, source)
+			}
+		}
+	}
+
+	if action == "prune" {
+		if !cache.RetentionConfigured() {
+			log.Fatalf("No retention configured: set %s (or %s_<SOURCE>) to the months data is kept", cache.RetentionEnv, cache.RetentionEnv)
+		}
+		if _, err := pruneCaches(os.Stdout, sources, time.Now()); err != nil {
+			log.Fatalf("Failed to prune cache: %v", err)
+		}
+		return
+	}
+
+	var usages []*cache.Usage
+	for _, source := range sources {
+		var usage *cache.Usage
+		var err error
+		if action == "clear" {
+			usage, err = source.Clear()
+		} else {
+			usage, err = source.Scan()
+		}
+		if err != nil {
+			log.Fatalf("Failed to %s cache: %v", action, err)
+		}
+		usages = append(usages, usage)
+	}
+
+	now := time.Now()
+	switch action {
+	case "ls":
+		cache.PrintList(os.Stdout, usages, now)
+	case "stats":
+		cache.PrintStats(os.Stdout, usages, now)
+	case "clear":
+		for _, usage := range usages {
+			fmt.Printf("✓ Cleared %s: %d files, %s\n", usage.Source.Name, len(usage.Files), cache.FormatBytes(usage.Bytes))
+		}
+	}
+}
+
+// pruneCaches removes the files of the sources older than their retention (RETENTION_MONTHS, overridden per
+// source by RETENTION_MONTHS_<SOURCE>) and prints what was removed
+func pruneCaches(writer io.Writer, sources []cache.Source, now time.Time) ([]*cache.Usage, error) {
+	var usages []*cache.Usage
+	for _, source := range sources {
+		months, err := source.Retention()
+		if err != nil {
+			return usages, err
+		}
+		usage, err := source.Prune(now)
+		if err != nil {
+			return usages, err
+		}
+		if len(usage.Files) > 0 {
+			fmt.Fprintf(writer, "✓ Pruned %s older than %d months: %d files, %s\n", source.Name, months, len(usage.Files), cache.FormatBytes(usage.Bytes))
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// handleLog appends a manual achievement (e.g. dev-stats log "Shipped X") to the persistent store
+func handleLog(args []string) {
+	flags := flag.NewFlagSet("log", flag.ExitOnError)
+	dateFlag := flags.String("date", "", "Date of the achievement in YYYY-MM-DD format (default: now)")
+	flags.Parse(args)
+
+	text := strings.TrimSpace(strings.Join(flags.Args(), " "))
+	if text == "" {
+		fmt.Println("Error: achievement text is required, e.g. dev-stats log \"Shipped X\"")
+		os.Exit(1)
+	}
+
+	achievement := common.Achievement{Time: time.Now(), Text: text}
+	if *dateFlag != "" {
+		date, err := time.ParseInLocation("2006-01-02", *dateFlag, time.Local)
+		if err != nil {
+			log.Fatalf("Invalid -date format: %v", err)
+		}
+		achievement.Time = date
+	}
+
+	if err := common.AppendAchievement(common.DefaultAchievementsPath, achievement); err != nil {
+		log.Fatalf("Failed to log achievement: %v", err)
+	}
+	fmt.Printf("✓ Logged %s: %s (%s)\n", achievement.Time.Format("2006-01-02"), achievement.Text, common.DefaultAchievementsPath)
+}
+
+// handleReviewReminders turns review debt (PRs awaiting review, aging own PRs) into tasks in a task manager
+func handleReviewReminders(args []string) {
+	flags := flag.NewFlagSet("review-reminders", flag.ExitOnError)
+	toFlag := flags.String("to", "", "Export target (todoist,things,backlog); without it tasks are only listed")
+	ageFlag := flags.Int("age", 7, "Days after which an open authored PR counts as aging")
+	profileFlag := flags.String("backlog-profile", "", "Backlog profile to create issues in (required with several profiles)")
+	dryRunFlag := flags.Bool("dry-run", false, "Show which tasks would be created without creating them")
+	flags.Parse(args)
+
+	godotenv.Load()
+
+	githubAnalyzer := github.NewGitHubAnalyzer()
+	debt, err := githubAnalyzer.CollectReviewDebt(os.Stdout, time.Duration(*ageFlag)*24*time.Hour)
+	if err != nil {
+		log.Fatalf("Failed to collect review debt: %v", err)
+	}
+
+	var taskList []tasks.Task
+	for _, pr := range debt.PendingReviews {
+		taskList = append(taskList, tasks.Task{
+			Key:   pr.URL,
+			Title: fmt.Sprintf("Review %s#%d: %s", githubAnalyzer.RepoName(pr), pr.Number, pr.Title),
+			Notes: fmt.Sprintf("%s\nOpened by %s on %s", pr.URL, pr.User.Login, pr.CreatedAt.Format("2006-01-02")),
+		})
+	}
+	for _, pr := range debt.AgingPRs {
+		taskList = append(taskList, tasks.Task{
+			Key:   pr.URL,
+			Title: fmt.Sprintf("Follow up %s#%d: %s", githubAnalyzer.RepoName(pr), pr.Number, pr.Title),
+			Notes: fmt.Sprintf("%s\nOpen since %s (%d days)", pr.URL, pr.CreatedAt.Format("2006-01-02"), int(time.Since(pr.CreatedAt).Hours()/24)),
+		})
+	}
+
+	fmt.Printf("\n👀 %d PRs awaiting your review, %d of your PRs open for %d+ days\n", len(debt.PendingReviews), len(debt.AgingPRs), *ageFlag)
+	if len(taskList) == 0 {
+		fmt.Println("✓ No review debt")
+		return
+	}
+
+	if *toFlag == "" {
+		for _, task := range taskList {
+			fmt.Printf("- %s\n", task.Title)
+		}
+		fmt.Println("\nUse -to todoist|things|backlog to create tasks")
+		return
+	}
+
+	exporter, err := tasks.NewExporter(*toFlag, *profileFlag, os.Stdout)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	created, err := tasks.Export(os.Stdout, exporter, taskList, tasks.DefaultExportLogPath, *dryRunFlag)
+	if err != nil {
+		log.Fatalf("Failed to export tasks: %v", err)
+	}
+	fmt.Printf("\n✓ Created %d tasks in %s (exported tasks are tracked in %s)\n", created, exporter.Name(), tasks.DefaultExportLogPath)
+}
+
+// loadOverrides loads config/overrides.yaml, returning nil with a warning if it is invalid
+func loadOverrides() *config.Overrides {
+	// Without categorization rules, override categories simply aren't checked
+	categoryConfig, _ := config.LoadCategorizationConfig("")
+	overrides, err := config.LoadOverrides("", categoryConfig)
+	if err != nil {
+		log.Printf("Warning: Failed to load overrides: %v", err)
+		return nil
+	}
+	return overrides
+}
+
+// loadEstimateFunc returns the effort estimate from config/estimation.yaml, or nil with a warning if it is invalid
+func loadEstimateFunc() common.EstimateFunc {
+	estimation, err := config.LoadEstimationConfig("")
+	if err != nil {
+		log.Printf("Warning: Failed to load estimation coefficients: %v", err)
+		return nil
+	}
+	return func(activity common.Activity) (time.Duration, bool) {
+		hours, ok := estimation.EstimateHours(activity.Source, activity.Kind, activity.Size)
+		return time.Duration(hours * float64(time.Hour)), ok
+	}
+}
+
+// printEffortEstimate prints measured calendar hours next to hours estimated for PRs, pages, and tickets, and reconciles them with logged time
+func printEffortEstimate(workItems []common.WorkItem) {
+	estimate := loadEstimateFunc()
+	if estimate == nil {
+		return
+	}
+	common.PrintEffortEstimate(os.Stdout, workItems, estimate)
+	// Time logged in Jira/Tempo/Harvest next to the calendar and estimated time of the same work
+	common.PrintLoggedTimeReconciliation(os.Stdout, workItems, estimate)
+	fmt.Printf("Estimates are approximations; adjust coefficients in %s (see config/estimation.sample.yaml)\n", config.DefaultEstimationPath)
+}
+
+// printSprintRollups buckets activities per sprint when config/sprints.yaml defines sprints
+func printSprintRollups(cfg *common.Config, results []*common.AnalysisResult) {
+	sprintConfig, err := config.LoadSprintConfig("")
+	if err != nil {
+		log.Printf("Warning: Failed to load sprints: %v", err)
+		return
+	}
+	common.PrintSprintRollups(os.Stdout, common.RollupSprints(results, sprintConfig.SprintsBetween(cfg.StartDate, cfg.EndDate)))
+}
+
+// categorizedAnalyzer is an analyzer whose results depend on config/categorization.yaml.
+// Its fetched items can be stored and categorized again later without calling the sources.
+type categorizedAnalyzer interface {
+	common.Analyzer
+	SetCategoryConfig(categoryConfig *config.CategorizationConfig)
+	SetOverrides(overrides *config.Overrides)
+	SaveRawData(path string) error
+	LoadRawData(path string, config *common.Config) error
+}
+
+// rawDataPath returns where fetched items of an analyzer are stored for the given period
+func rawDataPath(cfg *common.Config, analyzerName string) string {
+	return filepath.Join("output", cfg.PeriodLabel(), "raw", analyzerName+".json")
+}
+
+// handleRecategorize re-renders reports from stored raw data using the current categorization rules
+func handleRecategorize(args []string) {
+	flags := flag.NewFlagSet("recategorize", flag.ExitOnError)
+	analyzerFlag := flags.String("analyzer", "calendar,notion", "Analyzers to recategorize (calendar,notion)")
+	flags.Parse(args)
+
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	var analyzers []categorizedAnalyzer
+	for _, analyzer := range createCategorizedAnalyzers(parseCategorizedAnalyzerNames(*analyzerFlag)) {
+		analyzerName := strings.ToLower(strings.ReplaceAll(analyzer.GetName(), " ", "-"))
+		rawPath := rawDataPath(cfg, analyzerName)
+		if _, err := os.Stat(rawPath); err != nil {
+			fmt.Printf("⚠️  No stored %s data at %s. Run the analyzer once to store it.\n", analyzer.GetName(), rawPath)
+			continue
+		}
+		if err := analyzer.LoadRawData(rawPath, cfg); err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		fmt.Printf("✓ Loaded stored %s data from %s\n", analyzer.GetName(), rawPath)
+		analyzers = append(analyzers, analyzer)
+	}
+
+	if len(analyzers) == 0 {
+		log.Fatal("No stored data to recategorize")
+	}
+
+	runCategorizedAnalyzers(analyzers)
+}
+
+// parseCategorizedAnalyzerNames splits an -analyzer value and rejects analyzers without categorization
+func parseCategorizedAnalyzerNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name != "calendar" && name != "notion" {
+			log.Fatalf("Analyzer %s does not use categorization rules", name)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// handleWatch re-runs categorization-based analyzers whenever categorization.yaml, overrides.yaml, ignore.yaml, or .env changes.
+// Fetched data is kept in memory so that rule changes are re-rendered without refetching.
+func handleWatch(args []string) {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	analyzerFlag := flags.String("analyzer", "calendar,notion", "Analyzers to re-run (calendar,notion)")
+	intervalFlag := flags.Duration("interval", 2*time.Second, "How often to check files for changes")
+	flags.Parse(args)
+
+	godotenv.Load()
+
+	names := parseCategorizedAnalyzerNames(*analyzerFlag)
+	analyzers := createCategorizedAnalyzers(names)
+	runCategorizedAnalyzers(analyzers)
+
+	watcher := common.NewFileWatcher(config.DefaultCategorizationPath, config.DefaultOverridesPath, config.DefaultIgnoreListPath, ".env")
+	fmt.Printf("\n👀 Watching %s, %s, %s and .env for changes (Ctrl+C to stop)\n",
+		config.DefaultCategorizationPath, config.DefaultOverridesPath, config.DefaultIgnoreListPath)
+
+	for range time.Tick(*intervalFlag) {
+		changed := watcher.Changed()
+		if len(changed) == 0 {
+			continue
+		}
+		fmt.Printf("\n🔄 Detected changes in %s\n", strings.Join(changed, ", "))
+
+		for _, path := range changed {
+			if path == ".env" {
+				// Credentials or the date range may have changed, so fetched data can't be reused
+				if err := godotenv.Overload(); err != nil {
+					log.Printf("Warning: Failed to reload .env: %v", err)
+				}
+				analyzers = createCategorizedAnalyzers(names)
+			}
+		}
+
+		categoryConfig, err := config.LoadCategorizationConfig("")
+		if err != nil {
+			log.Printf("Error: %v", err)
+			fmt.Println("Keeping previous categorization rules. Fix the file to re-run.")
+			continue
+		}
+		overrides, err := config.LoadOverrides("", categoryConfig)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			fmt.Println("Keeping previous categorization rules. Fix the file to re-run.")
+			continue
+		}
+		for _, analyzer := range analyzers {
+			analyzer.SetCategoryConfig(categoryConfig)
+			analyzer.SetOverrides(overrides)
+		}
+		runCategorizedAnalyzers(analyzers)
+	}
+}
+
+// createCategorizedAnalyzers creates the named analyzers, skipping ones that fail to initialize
+func createCategorizedAnalyzers(names []string) []categorizedAnalyzer {
+	var analyzers []categorizedAnalyzer
+	for _, name := range names {
+		switch name {
+		case "calendar":
+			if analyzer, err := calendar.NewCalendarAnalyzer(); err != nil {
+				log.Printf("Warning: Calendar analyzer unavailable: %v", err)
+			} else {
+				analyzers = append(analyzers, analyzer)
+			}
+		case "notion":
+			if analyzer, err := notion.NewNotionAnalyzer(); err != nil {
+				log.Printf("Warning: Notion analyzer unavailable: %v", err)
+			} else {
+				analyzers = append(analyzers, analyzer)
+			}
+		}
+	}
+	return analyzers
+}
+
+// runCategorizedAnalyzers runs analyzers and rewrites their stats files
+func runCategorizedAnalyzers(analyzers []categorizedAnalyzer) {
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		log.Printf("Error: Failed to load configuration: %v", err)
+		return
+	}
+	outputDir := createOutputDirectory(cfg.StartDate, cfg.EndDate, "")
+	fileLimits := loadFileLimits()
+
+	for _, analyzer := range analyzers {
+		analyzerName := strings.ToLower(strings.ReplaceAll(analyzer.GetName(), " ", "-"))
+		filePath := filepath.Join(outputDir, fmt.Sprintf("%s-stats.txt", analyzerName))
+
+		statsFile, err := common.NewPagedFile(filePath, fileLimits)
+		if err != nil {
+			log.Printf("Warning: Failed to create output file %s: %v", filePath, err)
+			continue
+		}
+		writer := io.MultiWriter(os.Stdout, statsFile)
+
+		fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+		fmt.Fprintf(writer, "Running %s analyzer...\n", analyzer.GetName())
+		fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+		if result, err := analyzer.Analyze(cfg, writer); err != nil {
+			log.Printf("Error running %s analyzer: %v", analyzer.GetName(), err)
+		} else {
+			statsFile.Finish(result)
+			printStatsFileSaved(writer, statsFile)
+		}
+		closeStatsFile(statsFile)
+	}
+}
+
+// bundleCacheSources are the caches analyzers read instead of calling the API; bundles carry them
+var bundleCacheSources = []string{"github", "notion", "backlog"}
+
+// handleExportBundle runs the analyzers while recording every API response, and saves the responses, the settings
+// of .env without secrets, and the stored data the analyzers read into one encrypted bundle for import-bundle
+func handleExportBundle(args []string) {
+	flags := flag.NewFlagSet("export-bundle", flag.ExitOnError)
+	analyzerFlag := flags.String("analyzer", "all", "Analyzers to record, as with -analyzer")
+	fileFlag := flags.String("file", "", "Bundle to write (default: dev-stats-<period>.bundle)")
+	flags.Parse(args)
+
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	passphrase, err := bundle.Passphrase()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	bundlePath := *fileFlag
+	if bundlePath == "" {
+		bundlePath = fmt.Sprintf("dev-stats-%s.bundle", cfg.PeriodLabel())
+	}
+
+	// Every response has to reach the recorder, also those the HTTP cache would answer. Clients are created by the
+	// analyzers, so the transport is set before they are.
+	common.DisableHTTPCache()
+	recorder := bundle.NewRecorder(nil)
+	common.SetDefaultTransport(recorder)
+
+	exported := bundle.New(cfg.StartDate, cfg.EndDate)
+	analyzers := newAnalyzers()
+	fmt.Printf("Recording API responses from %s to %s\n", cfg.StartDate.Format("2006-01-02"), cfg.EndDate.Format("2006-01-02"))
+
+	for _, name := range parseAnalyzerNames(*analyzerFlag) {
+		var toRun []common.Analyzer
+		switch name {
+		case "github":
+			for _, githubAnalyzer := range github.NewGitHubAnalyzers() {
+				toRun = append(toRun, githubAnalyzer)
+			}
+		case "backlog":
+			profiles, err := backlog.SelectProfiles("")
+			if err != nil {
+				log.Fatalf("Invalid BACKLOG_PROFILE: %v", err)
+			}
+			for _, profile := range profiles {
+				if profile.IsAnalysisReady() {
+					toRun = append(toRun, backlog.NewBacklogAnalyzerWithProfile(&profile))
+				}
+			}
+		default:
+			analyzer, exists := analyzers[name]
+			if !exists {
+				log.Fatalf("Unknown analyzer: %s", name)
+			}
+			toRun = append(toRun, analyzer)
+		}
+
+		succeeded := len(toRun) > 0
+		for _, analyzer := range toRun {
+			if _, err := analyzer.Analyze(cfg, io.Discard); err != nil {
+				fmt.Printf("⚠️  %s: %v\n", analyzer.GetName(), err)
+				succeeded = false
+				continue
+			}
+			fmt.Printf("✓ %s\n", analyzer.GetName())
+			if categorized, ok := analyzer.(categorizedAnalyzer); ok {
+				rawPath := rawDataPath(cfg, outputName(analyzer.GetName()))
+				if err := categorized.SaveRawData(rawPath); err != nil {
+					log.Printf("Warning: Failed to save raw data for %s: %v", analyzer.GetName(), err)
+				} else if err := exported.AddFile(rawPath); err != nil {
+					log.Printf("Warning: %v", err)
+				}
+			}
+		}
+		if succeeded {
+			exported.Analyzers = append(exported.Analyzers, name)
+		}
+	}
+	if len(exported.Analyzers) == 0 {
+		log.Fatal("No analyzer ran successfully; nothing to export")
 	}
 
-	// Handle Backlog listing mode
-	if *listBacklogFlag || *listBacklogProject != "" || *listBacklogClear {
-		handleBacklogList(*listBacklogProject, *listBacklogClear)
-		return
+	for _, name := range bundleCacheSources {
+		source, err := cache.Lookup(name)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		usage, err := source.Scan()
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		for _, file := range usage.Files {
+			if err := exported.AddFile(file.Path); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
 	}
 
-	// Handle download mode
-	if *downloadFlag != "" {
-		handleDownload(*downloadFlag)
-		return
+	// Only the variables named in .env are carried: the environment of a shell holds much more than settings
+	var names []string
+	if values, err := godotenv.Read(); err != nil {
+		log.Printf("Warning: Failed to read .env; the bundle carries no settings: %v", err)
+	} else {
+		for name := range values {
+			names = append(names, name)
+		}
 	}
+	exported.CaptureEnv(names)
+	exported.Exchanges = recorder.Exchanges()
 
-	// Handle Google Workspace download mode
-	if *downloadGoogleFlag {
-		handleDownloadGoogle()
-		return
+	if err := bundle.Write(bundlePath, exported, passphrase); err != nil {
+		log.Fatalf("Failed to write bundle: %v", err)
 	}
+	fmt.Printf("\n📦 Bundle saved to: %s (%d responses, %d files, %d settings; analyzers: %s)\n",
+		bundlePath, len(exported.Exchanges), len(exported.Files), len(exported.Env), strings.Join(exported.Analyzers, ","))
+	fmt.Printf("    On the other machine: %s=... dev-stats import-bundle %s\n", bundle.PassphraseEnv, filepath.Base(bundlePath))
+}
 
-	if *analyzerFlag == "" {
-		fmt.Println("Error: -analyzer flag is required")
-		printHelp()
+// importBundle reads a bundle written by export-bundle and prepares a regular run on it: the period and settings of
+// the bundle, its stored files restored, and its responses served in place of the network
+func importBundle(args []string) *bundle.Bundle {
+	flags := flag.NewFlagSet("import-bundle", flag.ExitOnError)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: dev-stats [flags] import-bundle <file>")
 		os.Exit(1)
 	}
+	bundlePath := flags.Arg(0)
 
-	// Load common configuration
-	config, err := common.LoadConfig()
+	godotenv.Load() // BUNDLE_PASSPHRASE may be kept in .env
+	passphrase, err := bundle.Passphrase()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		log.Fatalf("Error: %v", err)
+	}
+	imported, err := bundle.Read(bundlePath, passphrase)
+	if err != nil {
+		log.Fatalf("Failed to import bundle: %v", err)
 	}
 
-	// Refuse to run if today is past END_DATE: results would be incomplete
-	// because APIs filter by last_edited_time, so pages updated after END_DATE
-	// would be excluded even if they were active during the target period.
-	if time.Now().After(config.EndDate.AddDate(0, 0, 1)) {
-		log.Fatalf("Error: today (%s) is past END_DATE (%s). Running now would produce incomplete stats because active files updated after END_DATE would be excluded. Update END_DATE in .env before running.",
-			time.Now().Format("2006-01-02"),
-			config.EndDate.Format("2006-01-02"))
+	imported.ApplyEnv()
+	common.OverrideDateRange(imported.StartDate, imported.EndDate)
+	if _, err := imported.RestoreFiles(); err != nil {
+		log.Fatalf("Failed to import bundle: %v", err)
 	}
+	common.DisableHTTPCache()
+	common.SetDefaultTransport(bundle.NewReplayer(imported.Exchanges))
 
-	// Create analyzers
-	analyzers := make(map[string]common.Analyzer)
+	fmt.Printf("📦 Imported %s: %s to %s, exported %s (%d responses, %d files)\n",
+		bundlePath, imported.StartDate, imported.EndDate, imported.CreatedAt.Local().Format("2006-01-02 15:04"),
+		len(imported.Exchanges), len(imported.Files))
+	return imported
+}
 
-	if githubAnalyzer := github.NewGitHubAnalyzer(); githubAnalyzer != nil {
-		analyzers["github"] = githubAnalyzer
+// loadBundledRawData makes Calendar and Notion use the items of an imported bundle instead of their sources
+func loadBundledRawData(imported *bundle.Bundle, cfg *common.Config, analyzers []common.Analyzer) {
+	for _, analyzer := range analyzers {
+		categorized, ok := analyzer.(categorizedAnalyzer)
+		if !ok {
+			continue
+		}
+		rawPath := rawDataPath(cfg, outputName(analyzer.GetName()))
+		if _, exists := imported.Files[filepath.ToSlash(rawPath)]; !exists {
+			continue
+		}
+		if err := categorized.LoadRawData(rawPath, cfg); err != nil {
+			log.Printf("Warning: %v", err)
+		}
 	}
+}
 
-	// Note: Backlog analyzers are handled separately due to multi-profile support
-	// They will be created dynamically when running backlog analysis
+// createOutputDirectory creates a directory for storing output files
+// printRecognition collects thanks/kudos from GitHub PR comments and Slack (when configured) into an appendix
+func printRecognition(config *common.Config) {
+	matcher := common.KudosMatcherFromEnv()
+	var kudos []common.Kudos
 
-	if calendarAnalyzer := calendar.NewCalendarAnalyzer(); calendarAnalyzer != nil {
-		analyzers["calendar"] = calendarAnalyzer
+	if githubAnalyzer := github.NewGitHubAnalyzer(); githubAnalyzer != nil {
+		fmt.Println("\n👀 Scanning GitHub PR comments for kudos...")
+		found, err := githubAnalyzer.CollectKudos(config, io.Discard, matcher)
+		if err != nil {
+			log.Printf("Warning: Failed to collect GitHub kudos: %v", err)
+		}
+		kudos = append(kudos, found...)
 	}
-	if notionAnalyzer := notion.NewNotionAnalyzer(); notionAnalyzer != nil {
-		analyzers["notion"] = notionAnalyzer
+	if collector := slack.NewKudosCollector(); collector != nil {
+		fmt.Println("👀 Searching Slack for kudos...")
+		found, err := collector.CollectKudos(config, io.Discard, matcher)
+		if err != nil {
+			log.Printf("Warning: Failed to collect Slack kudos: %v", err)
+		}
+		kudos = append(kudos, found...)
+	} else {
+		fmt.Println("Slack is not configured (set SLACK_USER_TOKEN and SLACK_USER_ID to include Slack kudos)")
 	}
-	analyzers["google"] = google.NewGDocsAnalyzer()
 
-	// Determine which analyzers to run
-	var analyzersToRun []common.Analyzer
-	requestedAnalyzers := []string{}
+	common.PrintRecognition(os.Stdout, kudos)
+}
 
-	if *analyzerFlag == "all" {
-		requestedAnalyzers = []string{"github", "backlog", "calendar", "notion", "google"}
-	} else {
-		for _, name := range strings.Split(*analyzerFlag, ",") {
-			requestedAnalyzers = append(requestedAnalyzers, strings.TrimSpace(name))
-		}
+// exportObsidian writes each day's items into the vault's daily notes
+func exportObsidian(vaultPath string, days []common.TimelineDay) {
+	vault, err := report.NewObsidianVault(vaultPath)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
 	}
 
-	for _, name := range requestedAnalyzers {
-		if name == "backlog" {
-			// Handle Backlog separately due to multi-profile support
-			continue
-		}
-		if analyzer, exists := analyzers[name]; exists {
-			analyzersToRun = append(analyzersToRun, analyzer)
-		} else {
-			log.Fatalf("Unknown analyzer: %s", name)
-		}
+	fmt.Printf("\n🔄 Writing daily notes to %s...\n", vaultPath)
+	count, err := vault.ExportDailyNotes(os.Stdout, days)
+	if err != nil {
+		log.Printf("Warning: Obsidian export failed after %d notes: %v", count, err)
+		return
 	}
+	fmt.Printf("✓ Updated %d daily notes\n", count)
+}
 
-	// Check if backlog was requested
-	backlogRequested := false
-	for _, name := range requestedAnalyzers {
-		if name == "backlog" {
-			backlogRequested = true
-			break
-		}
+// uploadStats syncs the stats directory to <prefix>/<period>/stats/ in the bucket
+func uploadStats(rawTarget string, cfg *common.Config, outputDir string) {
+	target, err := upload.ParseTarget(rawTarget)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+	uploader, err := upload.NewUploader(target)
+	if err != nil {
+		log.Printf("Warning: Failed to set up upload: %v", err)
+		return
 	}
 
-	if len(analyzersToRun) == 0 && !backlogRequested {
-		log.Fatal("No valid analyzers specified")
+	fmt.Printf("\n🔄 Uploading %s to %s...\n", outputDir, rawTarget)
+	count, err := upload.SyncDirectory(os.Stdout, uploader, target, outputDir, path.Join(cfg.PeriodLabel(), "stats"))
+	if err != nil {
+		log.Printf("Warning: Upload failed after %d files: %v", count, err)
+		return
 	}
+	fmt.Printf("✓ Uploaded %d files\n", count)
+}
 
-	fmt.Printf("Running analysis from %s to %s\n",
-		config.StartDate.Format("2006-01-02"),
-		config.EndDate.Format("2006-01-02"))
+// shareTeamMetrics pushes the run's summary metrics to the team store under TEAM_MEMBER; -personal-only runs
+// stay on this machine
+func shareTeamMetrics(location string, cfg *common.Config, scope string, results []*common.AnalysisResult) {
+	if scope == config.ScopePersonal {
+		return
+	}
+	member, err := team.MemberFromEnv()
+	if err != nil {
+		log.Printf("Warning: Not sharing metrics with the team: %v", err)
+		return
+	}
+	store, err := team.OpenStore(location)
+	if err != nil {
+		log.Printf("Warning: Failed to open the team store: %v", err)
+		return
+	}
+	url, count, err := store.Push(member, cfg, results)
+	if err != nil {
+		log.Printf("Warning: Failed to share metrics with the team: %v", err)
+		return
+	}
+	fmt.Printf("\n👥 Shared %d metrics as %s: %s\n", count, member, url)
+}
 
-	// Create output directory
-	outputDir := createOutputDirectory(config.StartDate, config.EndDate)
-	fmt.Printf("Output directory: %s\n", outputDir)
+// handleTeamReport aggregates the summaries team members pushed for the period (dev-stats team-report)
+func handleTeamReport(args []string) {
+	flags := flag.NewFlagSet("team-report", flag.ExitOnError)
+	anonymizeFlag := flags.Bool("anonymize", false, "Show only team-wide aggregates, without member names or per-member values")
+	storeFlag := flags.String("store", "", "Team store to read (default: TEAM_STORE)")
+	flags.Parse(args)
 
-	// Run analyzers
-	var results []*common.AnalysisResult
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	location := *storeFlag
+	if location == "" {
+		location = os.Getenv(team.StoreEnv)
+	}
+	store, err := team.OpenStore(location)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	summaries, err := store.Summaries(cfg)
+	if err != nil {
+		log.Fatalf("Failed to read the team store: %v", err)
+	}
+	if len(summaries) == 0 {
+		log.Fatalf("No member shared metrics for %s in %s", cfg.PeriodLabel(), location)
+	}
 
-	// Run Backlog analyzers for all profiles
-	if backlogRequested {
-		backlogProfiles := backlog.LoadBacklogProfiles()
-		if len(backlogProfiles) == 0 {
-			log.Println("Warning: No Backlog profiles found. Please set BACKLOG_<PROFILE>_* environment variables.")
-		} else {
-			for _, profile := range backlogProfiles {
-				if !profile.IsAnalysisReady() {
-					fmt.Printf("⚠️  Backlog profile '%s' is missing USER_ID or PROJECT_ID. Skipping analysis.\n", profile.Name)
-					fmt.Printf("    Run 'make list-backlog' to find the IDs.\n\n")
-					continue
-				}
+	outputDir := createOutputDirectory(cfg.StartDate, cfg.EndDate, "")
+	filePath := filepath.Join(outputDir, team.ReportFileName)
+	file, err := os.Create(filePath)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", filePath, err)
+	}
+	defer file.Close()
 
-				analyzer := backlog.NewBacklogAnalyzerWithProfile(&profile)
-				analyzerName := fmt.Sprintf("backlog-%s", strings.ToLower(profile.Name))
-				filename := fmt.Sprintf("%s-stats.txt", analyzerName)
-				filePath := filepath.Join(outputDir, filename)
+	team.WriteReport(io.MultiWriter(os.Stdout, file), summaries, cfg.StartDate, cfg.EndDate, *anonymizeFlag)
+	fmt.Printf("\n📁 Output saved to: %s\n", filePath)
+}
 
-				// Create file writer
-				file, err := os.Create(filePath)
-				if err != nil {
-					log.Printf("Warning: Failed to create output file %s: %v", filePath, err)
-					continue
-				}
-				defer file.Close()
+// handleIngest serves the webhook endpoints until interrupted (dev-stats ingest)
+func handleIngest(args []string) {
+	flags := flag.NewFlagSet("ingest", flag.ExitOnError)
+	addrFlag := flags.String("addr", "127.0.0.1:8080", "Address to listen on (:8080 for all interfaces)")
+	flags.Parse(args)
 
-				// Create multi-writer to write to both stdout and file
-				writer := io.MultiWriter(os.Stdout, file)
+	godotenv.Load()
+	server, err := ingest.NewServer(ingest.NewStore(""), os.Stdout)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fmt.Printf("📥 Storing webhook events in %s, listening on %s (Ctrl+C to stop)\n", ingest.DefaultDir, *addrFlag)
+	for _, endpoint := range server.Endpoints() {
+		fmt.Printf("  %s\n", endpoint)
+	}
+	httpServer := &http.Server{Addr: *addrFlag, Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(httpServer.ListenAndServe())
+}
 
-				// Print header
-				fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
-				fmt.Fprintf(writer, "Running Backlog analyzer (%s)...\n", profile.Name)
-				fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+// handleServe serves the read-only API over saved results until interrupted (dev-stats serve)
+func handleServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addrFlag := flags.String("addr", "127.0.0.1:8090", "Address to listen on (other than loopback requires SERVE_TOKEN)")
+	flags.Parse(args)
 
-				result, err := analyzer.Analyze(config, writer)
-				if err != nil {
-					log.Printf("Error running Backlog analyzer (%s): %v", profile.Name, err)
-					continue
-				}
+	godotenv.Load()
+	server, err := api.NewServer(*addrFlag, api.NewResults(""), common.DefaultHistoryPath)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fmt.Printf("🔎 Serving results saved with -output json and %s on http://%s (Ctrl+C to stop)\n", common.DefaultHistoryPath, *addrFlag)
+	fmt.Println("  GET /api/periods, /api/metrics, /api/items, /api/days")
+	httpServer := &http.Server{Addr: *addrFlag, Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(httpServer.ListenAndServe())
+}
 
-				fmt.Fprintf(writer, "\n📁 Output saved to: %s\n", filePath)
+// handleQuick prints the current week so far as one line (dev-stats quick). Nothing is fetched, so that shell
+// prompts and launchers get an answer at once: run with -output json (or dev-stats ingest) to keep it current.
+func handleQuick(args []string) {
+	flags := flag.NewFlagSet("quick", flag.ExitOnError)
+	jsonFlag := flags.Bool("json", false, "Print the summary as JSON")
+	flags.Parse(args)
 
-				results = append(results, result)
-			}
+	godotenv.Load()
+	categories, err := config.LoadCategorizationConfig("")
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	sources := quick.Sources{Results: api.NewResults(""), Webhooks: ingest.NewWebhookAnalyzer(), Categories: categories}
+	summary, err := quick.Collect(sources, time.Now(), loadWeekConfig())
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if *jsonFlag {
+		data, err := json.Marshal(summary)
+		if err != nil {
+			log.Fatalf("%v", err)
 		}
+		fmt.Println(string(data))
+		return
 	}
+	fmt.Println(summary.Line())
+}
 
-	// Run other analyzers
-	for _, analyzer := range analyzersToRun {
-		analyzerName := strings.ToLower(strings.ReplaceAll(analyzer.GetName(), " ", "-"))
-		filename := fmt.Sprintf("%s-stats.txt", analyzerName)
-		filePath := filepath.Join(outputDir, filename)
+// saveResultJSON writes the structured result (metrics, details, activities) next to the text report.
+// Like the text report it is always plain JSON, even with cache encryption enabled, so that it can be shared and uploaded.
+func saveResultJSON(writer io.Writer, outputDir, analyzerName string, result *common.AnalysisResult) {
+	jsonPath := filepath.Join(outputDir, analyzerName+"-stats.json")
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err == nil {
+		err = os.WriteFile(jsonPath, data, 0644)
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to save JSON output for %s: %v", result.AnalyzerName, err)
+		return
+	}
+	fmt.Fprintf(writer, "📁 JSON saved to: %s\n", jsonPath)
+}
 
-		// Create file writer
-		file, err := os.Create(filePath)
-		if err != nil {
-			log.Printf("Warning: Failed to create output file %s: %v", filePath, err)
-			continue
-		}
-		defer file.Close()
+// loadWeekConfig returns the week start and numbering from WEEK_START/WEEK_NUMBERING, or ISO weeks if they are invalid
+func loadWeekConfig() common.WeekConfig {
+	weeks, err := common.WeekConfigFromEnv()
+	if err != nil {
+		log.Printf("Warning: %v. Using %s.", err, weeks.Describe())
+	}
+	return weeks
+}
 
-		// Create multi-writer to write to both stdout and file
-		writer := io.MultiWriter(os.Stdout, file)
+// saveTimeline prints the per-day activity feed and saves it as timeline.txt and timeline.csv
+func saveTimeline(outputDir string, days []common.TimelineDay) {
+	textPath := filepath.Join(outputDir, common.TimelineFileName)
+	file, err := os.Create(textPath)
+	if err != nil {
+		log.Printf("Warning: Failed to create %s: %v", textPath, err)
+		common.PrintTimeline(os.Stdout, days)
+		return
+	}
+	defer file.Close()
+	common.PrintTimeline(io.MultiWriter(os.Stdout, file), days)
+	fmt.Printf("\n📁 Timeline saved to: %s\n", textPath)
+
+	table := common.TimelineCSVTable(days)
+	csvPath := filepath.Join(outputDir, table.Name+".csv")
+	if err := common.WriteCSVFile(csvPath, table); err != nil {
+		log.Printf("Warning: Failed to save timeline CSV: %v", err)
+		return
+	}
+	fmt.Printf("📁 Timeline CSV saved to: %s\n", csvPath)
+}
 
-		// Print header
-		fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
-		fmt.Fprintf(writer, "Running %s analyzer...\n", analyzer.GetName())
-		fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+// saveReconciliation prints scheduled, logged, and estimated hours per project per week and saves them as reconciliation.csv
+func saveReconciliation(outputDir string, workItems []common.WorkItem) {
+	estimate := loadEstimateFunc()
+	if estimate == nil {
+		return
+	}
+	reconciliation := common.ReconcileWeeks(workItems, estimate, loadWeekConfig(), common.ReconciliationThresholdFromEnv())
+	common.PrintReconciliation(os.Stdout, reconciliation)
 
-		result, err := analyzer.Analyze(config, writer)
-		if err != nil {
-			log.Printf("Error running %s analyzer: %v", analyzer.GetName(), err)
-			continue
-		}
+	table := common.ReconciliationCSVTable(reconciliation)
+	csvPath := filepath.Join(outputDir, table.Name+".csv")
+	if err := common.WriteCSVFile(csvPath, table); err != nil {
+		log.Printf("Warning: Failed to save reconciliation CSV: %v", err)
+		return
+	}
+	fmt.Printf("📁 Reconciliation CSV saved to: %s\n", csvPath)
+}
+
+// loadFileLimits returns the size limits of text stats files (STATS_MAX_FILE_LINES / STATS_MAX_FILE_MB)
+func loadFileLimits() common.FileLimits {
+	limits, err := common.FileLimitsFromEnv()
+	if err != nil {
+		log.Printf("Warning: %v; using %s per stats file", err, limits.Describe())
+	}
+	return limits
+}
 
-		fmt.Fprintf(writer, "\n📁 Output saved to: %s\n", filePath)
+// printStatsFileSaved reports where the text report went, including the detail pages of a split report
+func printStatsFileSaved(writer io.Writer, statsFile *common.PagedFile) {
+	fmt.Fprintf(writer, "\n📁 Output saved to: %s\n", statsFile.Path())
+	if pages := statsFile.Pages(); len(pages) > 0 {
+		fmt.Fprintf(writer, "📁 Detailed output split into %d files: %s ... %s\n",
+			len(pages), pages[0], filepath.Base(pages[len(pages)-1]))
+	}
+}
 
-		results = append(results, result)
+// closeStatsFile closes a text report, reporting write errors that were kept back during the run
+func closeStatsFile(statsFile *common.PagedFile) {
+	if err := statsFile.Close(); err != nil {
+		log.Printf("Warning: %v", err)
 	}
+}
 
-	// Print overall summary
-	if len(results) > 1 {
-		printOverallSummary(results)
+// splitResultDetails moves the detail lists of the result to <analyzer>-details.jsonl (-split-details)
+func splitResultDetails(writer io.Writer, outputDir, analyzerName string, result *common.AnalysisResult) {
+	detailsPath := filepath.Join(outputDir, analyzerName+"-details.jsonl")
+	count, err := common.SplitDetails(detailsPath, result)
+	if err != nil {
+		log.Printf("Warning: Failed to split details for %s: %v", result.AnalyzerName, err)
+		return
 	}
+	if count > 0 {
+		fmt.Fprintf(writer, "📁 %d detail items saved to: %s\n", count, detailsPath)
+	}
+}
 
-	fmt.Println("\nAnalysis completed successfully!")
+// saveResultCSV writes each detail list of the result (PRs, issues, events, pages, ...) as <analyzer>-<list>.csv
+func saveResultCSV(writer io.Writer, outputDir, analyzerName string, result *common.AnalysisResult) {
+	for _, table := range result.CSVTables {
+		csvPath := filepath.Join(outputDir, fmt.Sprintf("%s-%s.csv", analyzerName, table.Name))
+		if err := common.WriteCSVFile(csvPath, table); err != nil {
+			log.Printf("Warning: Failed to save CSV output for %s: %v", result.AnalyzerName, err)
+			continue
+		}
+		fmt.Fprintf(writer, "📁 CSV saved to: %s (%d rows)\n", csvPath, len(table.Rows))
+	}
 }
 
-// createOutputDirectory creates a directory for storing output files
-func createOutputDirectory(startDate, endDate time.Time) string {
+// createOutputDirectory creates a directory for storing output files.
+// Runs limited to a scope (-work-only, -personal-only) write to stats-<scope>/, so that an upload or report.md of
+// that directory never picks up files of the other scope.
+func createOutputDirectory(startDate, endDate time.Time, scope string) string {
 	outputDir := fmt.Sprintf("output/%s_to_%s/stats",
 		startDate.Format("2006-01-02"),
 		endDate.Format("2006-01-02"))
+	if scope != "" {
+		outputDir += "-" + scope
+	}
 
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		log.Printf("Warning: Failed to create output directory %s: %v", outputDir, err)
@@ -263,7 +2114,7 @@ func handleListBacklogProfiles() {
 		fmt.Println("No Backlog profiles found.")
 		fmt.Println("\nTo configure Backlog profiles, set environment variables with pattern:")
 		fmt.Println("  BACKLOG_<PROFILE_NAME>_API_KEY")
-		fmt.Println("  BACKLOG_<PROFILE_NAME>_HOST            (e.g., mycompany.backlog.com)")
+		fmt.Println("  BACKLOG_<PROFILE_NAME>_HOST            (e.g., mycompany.backlog.com, or mycompany to probe .com and .jp)")
 		fmt.Println("  BACKLOG_<PROFILE_NAME>_USER_ID         (optional, for analysis)")
 		fmt.Println("  BACKLOG_<PROFILE_NAME>_PROJECT_ID      (optional, for analysis)")
 		fmt.Println("\nExample:")
@@ -279,14 +2130,12 @@ func handleListBacklogProfiles() {
 	for _, profile := range profiles {
 		status := "Ready"
 		if !profile.IsAnalysisReady() {
-			if profile.UserID == "" || profile.ProjectID == "" {
-				status = "Missing IDs"
-			}
+			status = "Missing PROJECT_ID"
 		}
 
 		userID := profile.UserID
 		if userID == "" {
-			userID = "-"
+			userID = "(API key)"
 		}
 		projectID := profile.ProjectID
 		if projectID == "" {
@@ -359,9 +2208,14 @@ func handleDownloadGoogle() {
 	}
 
 	if time.Now().After(config.EndDate.AddDate(0, 0, 1)) {
-		log.Fatalf("Error: today (%s) is past END_DATE (%s). Running now would produce incomplete results. Update END_DATE in .env before running.",
-			time.Now().Format("2006-01-02"),
-			config.EndDate.Format("2006-01-02"))
+		if common.DateRangeOverridden() {
+			log.Printf("Warning: the period ended on %s. Files edited after it are not downloaded.",
+				config.EndDate.Format("2006-01-02"))
+		} else {
+			log.Fatalf("Error: today (%s) is past END_DATE (%s). Running now would produce incomplete results. Update END_DATE in .env before running.",
+				time.Now().Format("2006-01-02"),
+				config.EndDate.Format("2006-01-02"))
+		}
 	}
 
 	d := google.NewGDocsDownloader()
@@ -402,15 +2256,83 @@ func printHelp() {
 	fmt.Println("  dev-stats -download-google")
 	fmt.Println("  dev-stats -list-backlog")
 	fmt.Println("  dev-stats -list-backlog-profiles")
+	fmt.Println("  dev-stats doctor")
+	fmt.Println("  dev-stats watch [-analyzer calendar,notion] [-interval 2s]")
+	fmt.Println("  dev-stats recategorize [-analyzer calendar,notion]")
+	fmt.Println("  dev-stats oss-report")
+	fmt.Println("  dev-stats -period 2025-H1 review [-analyzer all]")
+	fmt.Println("  dev-stats log [-date YYYY-MM-DD] <text>")
+	fmt.Println("  dev-stats action-items [-dir output/<period>/notion]")
+	fmt.Println("  dev-stats whoami")
+	fmt.Println("  dev-stats notion databases")
+	fmt.Println("  dev-stats github repos")
+	fmt.Println("  dev-stats backlog members [-backlog-profile NAME]")
+	fmt.Println("  dev-stats snapshot [-update] [case...]")
+	fmt.Println("  dev-stats cache ls|stats|clear|prune [backlog|github|notion|http|raw|google|details|store]")
+	fmt.Println("  dev-stats review-reminders [-to todoist|things|backlog] [-age 7] [-backlog-profile NAME] [-dry-run]")
+	fmt.Println("  dev-stats completion bash|zsh|fish")
+	fmt.Println("  dev-stats man")
+	fmt.Println("  dev-stats version [-check] [-channel stable|beta]")
+	fmt.Println("  dev-stats self-update [-channel stable|beta] [-check] [-force]")
+	fmt.Println("  dev-stats -period 2025-H1 export-bundle [-analyzer all] [-file dev-stats-<period>.bundle]")
+	fmt.Println("  dev-stats [flags] import-bundle <file>")
+	fmt.Println("  dev-stats -period last-month team-report [-anonymize] [-store TEAM_STORE]")
+	fmt.Println("  dev-stats ingest [-addr 127.0.0.1:8080]")
+	fmt.Println("  dev-stats serve [-addr 127.0.0.1:8090]")
+	fmt.Println("  dev-stats quick [-json]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  doctor                       Check credentials, paths, config files, and API reachability")
+	fmt.Println("  watch                        Re-run categorization when config/*.yaml or .env changes")
+	fmt.Println("  recategorize                 Apply current categorization rules to stored raw data without fetching")
+	fmt.Println("  oss-report                   Write authored open-source PRs with merge status and stars as Markdown")
+	fmt.Println("  review                       Write a self-review template (top repositories, projects, highlights, raw counts) as Markdown")
+	fmt.Println("  log                          Log a manual achievement shown in the period report")
+	fmt.Println("  review-reminders             Create tasks for PRs awaiting your review and your aging PRs")
+	fmt.Println("  action-items                 Report open vs completed action items in downloaded Notion meeting notes")
+	fmt.Println("  whoami                       Show the account and IDs behind each configured credential")
+	fmt.Println("  notion databases             List databases shared with the Notion integration, with IDs and properties")
+	fmt.Println("  github repos                 List repositories with your PRs in the period (one search, before a full analysis)")
+	fmt.Println("  backlog members              Write activity counts per member of the space's projects as CSV (space admins)")
+	fmt.Println("  snapshot                     Run analyzers on recorded API responses (testdata/snapshots/) and diff against expected reports")
+	fmt.Println("  cache                        List (ls), summarize (stats), or clear cached data; clear skips store unless named, prune removes data past RETENTION_MONTHS")
+	fmt.Println("  completion                   Print the bash, zsh, or fish completion script (analyzer and profile names complete dynamically)")
+	fmt.Println("  man                          Print the man page (roff), e.g. dev-stats man > /usr/local/share/man/man1/dev-stats.1")
+	fmt.Println("  version                      Print the version; -check also shows the latest release of the channel")
+	fmt.Println("  self-update                  Install the latest GitHub release (checksum-verified) over this binary; -channel beta includes pre-releases")
+	fmt.Println("  export-bundle                Record all API responses of the period into one encrypted file (BUNDLE_PASSPHRASE), without secrets")
+	fmt.Println("  import-bundle                Run the analyzers on an exported bundle, offline and without tokens; flags apply as for a regular run")
+	fmt.Println("  team-report                  Aggregate the summary metrics members pushed to TEAM_STORE for the period (team retros); -anonymize names no one")
+	fmt.Println("  ingest                       Receive GitHub/Backlog webhooks and store their events in storage/webhooks, counted by -analyzer webhooks")
+	fmt.Println("  serve                        Serve results saved with -output json and the daily history as a JSON API (/api/periods, /api/metrics, /api/items, /api/days)")
+	fmt.Println("  quick                        Print this week's PRs, reviews, and meeting hours so far as one line from saved results and webhook events (shell prompts, launchers)")
 	fmt.Println()
 	fmt.Println("Flags:")
-	fmt.Println("  -analyzer string             Analyzer to run (github,backlog,calendar,notion,google,all)")
+	fmt.Println("  -analyzer string             Analyzer to run (github,backlog,calendar,notion,google,todoist,jira,harvest,support,opsgenie,copilot,gitea,phabricator,focus,github-archive,webhooks,all)")
 	fmt.Println("  -download string             Download Notion pages from markdown file")
 	fmt.Println("  -download-google             Download Google Workspace files modified in date range")
 	fmt.Println("  -list-backlog                List all Backlog projects and members (all profiles)")
 	fmt.Println("  -list-backlog-project ID     List members of a specific Backlog project (all profiles)")
 	fmt.Println("  -list-backlog-profiles       List all configured Backlog profiles")
 	fmt.Println("  -list-backlog-clear          Clear cache and refresh Backlog data")
+	fmt.Println("  -extrapolate                 Show \"on pace for\" projections when END_DATE is in the future")
+	fmt.Println("  -output string               Stats file format: text (default), json (also writes <analyzer>-stats.json), markdown (also writes report.md), or csv (also writes <analyzer>-<list>.csv)")
+	fmt.Println("  -kudos                       Append thanks/kudos received in GitHub PR comments and Slack")
+	fmt.Println("  -upload URL                  Upload stats to s3://bucket/prefix or gs://bucket/prefix (default: UPLOAD_TARGET)")
+	fmt.Println("  -gamification                Show commit streaks, weekly goal streaks, and badges")
+	fmt.Println("  -explain metric              List the items counted in a metric (e.g. github.prs_low_value, meeting_hours)")
+	fmt.Println("  -no-cache                    Bypass the HTTP response cache (.http-cache/) for this run")
+	fmt.Println("  -timeline                    Print all items from every analyzer day by day; saves timeline.txt and timeline.csv")
+	fmt.Println("  -obsidian PATH               Write each day's items into the vault's daily notes (default: OBSIDIAN_VAULT)")
+	fmt.Println("  -rollups                     Print item counts per source and scheduled hours per week (WEEK_START/WEEK_NUMBERING) and per month")
+	fmt.Println("  -reconcile                   Compare scheduled, logged, and estimated hours per project per week (reconciliation.csv)")
+	fmt.Println("  -split-details               Write PR/issue/event/page lists to <analyzer>-details.jsonl (JSON Lines) instead of <analyzer>-stats.json")
+	fmt.Println("  -start / -end YYYY-MM-DD     Date range for this run, overriding START_DATE/END_DATE")
+	fmt.Println("  -period preset               last-month, last-quarter, last-half, last-year, this-*, 2024, 2024-H2, 2024-Q3, or 2024-07")
+	fmt.Println("  -work-only / -personal-only  Run only sources classified as work/personal in config/scopes.yaml; writes to stats-<scope>/")
+	fmt.Println("  -backlog-profile NAME        Backlog profiles to analyze (comma-separated, or all; default: BACKLOG_PROFILE, else all)")
+	fmt.Println("  -top N                       Entries listed per ranking (default: 10; 0 lists all), followed by \"…and N more\"")
+	fmt.Println("  -summary-only                Print only counts (GitHub search totals, Backlog issue counts; other sources show their summary)")
 	fmt.Println("  -list                        List available analyzers")
 	fmt.Println("  -help                        Show this help message")
 	fmt.Println()
@@ -418,27 +2340,103 @@ func printHelp() {
 	fmt.Println("  dev-stats -analyzer github")
 	fmt.Println("  dev-stats -analyzer github,backlog")
 	fmt.Println("  dev-stats -analyzer all")
+	fmt.Println("  dev-stats -analyzer github -extrapolate")
+	fmt.Println("  dev-stats -analyzer github -explain github.prs_low_value")
+	fmt.Println("  dev-stats -analyzer all -period last-month")
+	fmt.Println("  dev-stats -analyzer all -timeline")
+	fmt.Println("  dev-stats -analyzer all -period 2025-H1 -rollups")
+	fmt.Println("  dev-stats -analyzer github -period 2024-H2")
+	fmt.Println("  dev-stats -analyzer all -work-only -output markdown")
+	fmt.Println("  dev-stats -analyzer backlog -backlog-profile hoge")
+	fmt.Println("  dev-stats -analyzer github,backlog -start 2025-06-02 -end 2025-06-08 -summary-only")
+	fmt.Println("  dev-stats -start 2025-04-01 -end 2025-06-30 oss-report")
 	fmt.Println("  dev-stats -download notion-urls/YYYY-MM-DD_to_YYYY-MM-DD.md")
 	fmt.Println("  dev-stats -download-google")
 	fmt.Println("  dev-stats -list-backlog-profiles")
 	fmt.Println("  dev-stats -list-backlog")
 	fmt.Println("  dev-stats -list-backlog-clear")
 	fmt.Println("  dev-stats -list-backlog-project 1073924896")
+	fmt.Println("  dev-stats doctor")
+	fmt.Println("  dev-stats watch -analyzer calendar")
+	fmt.Println("  dev-stats recategorize")
+	fmt.Println("  dev-stats log \"Shipped the new billing flow\"")
+	fmt.Println("  dev-stats cache stats")
+	fmt.Println("  dev-stats cache clear backlog")
 	fmt.Println()
 	fmt.Println("Environment Variables:")
 	fmt.Println("  START_DATE         Start date in YYYY-MM-DD format")
 	fmt.Println("  END_DATE           End date in YYYY-MM-DD format")
+	fmt.Println("  STATS_MAX_FILE_LINES/STATS_MAX_FILE_MB  (Optional) Split larger stats files into <analyzer>-stats-details-N.txt (default: 50000 lines / 5 MB, 0: no limit)")
 	fmt.Println()
 	fmt.Println("  For GitHub:")
 	fmt.Println("    GITHUB_TOKEN     GitHub personal access token")
 	fmt.Println("    GITHUB_USERNAME  GitHub username")
+	fmt.Println("    GITHUB_BOT_PATTERNS  (Optional) Bot accounts excluded from involved counts (default: dependabot*,renovate*,*-bot,*[bot])")
+	fmt.Println("    GITHUB_OSS_ORGS      (Optional) Organizations always counted as open-source (default: public repositories)")
+	fmt.Println("    GITHUB_INTERNAL_ORGS (Optional) Organizations always counted as internal, even for public repositories")
+	fmt.Println("    GITHUB_INCLUDE_ORGS/GITHUB_INCLUDE_REPOS  (Optional) Only count these organizations/owner/repo repositories (\"*\" wildcard)")
+	fmt.Println("    GITHUB_EXCLUDE_ORGS/GITHUB_EXCLUDE_REPOS  (Optional) Never count these, e.g. */dotfiles")
+	fmt.Println("    GITHUB_ACTIONS_REPOS (Optional) Workflow runs and deployments in these owner/repo (\"auto\": repositories with PRs or commits)")
+	fmt.Println("    GITHUB_HOST          (Optional) GitHub Enterprise Server host (default: github.com)")
+	fmt.Println("    GITHUB_<PROFILE>_TOKEN/USERNAME/HOST  (Optional) Additional accounts, each run as \"GitHub (<PROFILE>)\"")
+	fmt.Println("    GITHUB_MERGE_PROFILES (Optional) true combines all GitHub accounts into one result")
+	fmt.Println()
+	fmt.Println("  For Todoist (analyzer and review-reminders -to todoist):")
+	fmt.Println("    TODOIST_API_TOKEN    Todoist API token")
+	fmt.Println()
+	fmt.Println("  For Jira worklogs:")
+	fmt.Println("    JIRA_BASE_URL        Jira site (e.g., https://mycompany.atlassian.net)")
+	fmt.Println("    JIRA_EMAIL           Account email")
+	fmt.Println("    JIRA_API_TOKEN       Atlassian API token")
+	fmt.Println("    TEMPO_API_TOKEN      (Optional) Read worklogs from Tempo instead of native Jira worklogs")
+	fmt.Println()
+	fmt.Println("  For Harvest billable hours:")
+	fmt.Println("    HARVEST_ACCESS_TOKEN Harvest personal access token")
+	fmt.Println("    HARVEST_ACCOUNT_ID   Harvest account ID")
+	fmt.Println()
+	fmt.Println("  For support tickets (Zendesk, or Freshdesk when ZENDESK_SUBDOMAIN is not set):")
+	fmt.Println("    ZENDESK_SUBDOMAIN    Zendesk subdomain (mycompany for mycompany.zendesk.com)")
+	fmt.Println("    ZENDESK_EMAIL        Agent email")
+	fmt.Println("    ZENDESK_API_TOKEN    Zendesk API token")
+	fmt.Println("    FRESHDESK_DOMAIN     Freshdesk domain (mycompany or mycompany.freshdesk.com)")
+	fmt.Println("    FRESHDESK_API_KEY    Freshdesk API key")
+	fmt.Println()
+	fmt.Println("  For Opsgenie on-call shifts and alerts:")
+	fmt.Println("    OPSGENIE_API_KEY     Opsgenie API key (read access)")
+	fmt.Println("    OPSGENIE_USER        Your Opsgenie username (email)")
+	fmt.Println("    OPSGENIE_API_URL     (Optional) https://api.eu.opsgenie.com for EU accounts")
+	fmt.Println("    OPSGENIE_SCHEDULES   (Optional) Comma-separated schedule names to include (default: all)")
+	fmt.Println()
+	fmt.Println("  For GitHub Copilot usage (an export file, or the org metrics API with GITHUB_TOKEN):")
+	fmt.Println("    COPILOT_EXPORT_FILE  Per-user CSV export (date, suggestions, acceptances, ... columns)")
+	fmt.Println("    COPILOT_ORG          Organization whose Copilot metrics to read")
+	fmt.Println("    COPILOT_TEAM         (Optional) Team slug to narrow the org metrics")
+	fmt.Println()
+	fmt.Println("  For Gitea / Forgejo / Codeberg:")
+	fmt.Println("    GITEA_URL            Server URL (e.g. https://codeberg.org)")
+	fmt.Println("    GITEA_TOKEN          Access token (read:user, read:issue, read:repository)")
+	fmt.Println("    GITEA_USERNAME       (Optional) User to analyze (default: the token owner)")
+	fmt.Println()
+	fmt.Println("  For Phabricator / Differential (Conduit API, or a JSON export of an archived instance):")
+	fmt.Println("    PHABRICATOR_URL        Server URL (e.g. https://phabricator.example.com)")
+	fmt.Println("    PHABRICATOR_API_TOKEN  Conduit API token (api-...)")
+	fmt.Println("    PHABRICATOR_USERNAME   (Optional) User to analyze (default: the token owner)")
+	fmt.Println("    PHABRICATOR_EXPORT_FILE  Saved differential.revision.search results (used instead of the API)")
+	fmt.Println("    PHABRICATOR_USER_PHID  PHID-USER-... of the user (required with the export file)")
+	fmt.Println()
+	fmt.Println("  For focus sessions (Pomodoro/focus app exports, offline):")
+	fmt.Println("    FOCUS_EXPORT_FILE    CSV export of Forest, Session, or another app with start and end/duration columns (comma-separated for several)")
+	fmt.Println()
+	fmt.Println("  For GitHub archive (offline; not part of -analyzer all):")
+	fmt.Println("    GITHUB_ARCHIVE_PATH  Extracted account export / migration archive directory, or its .tar.gz")
+	fmt.Println("    GITHUB_USERNAME      GitHub username to analyze")
 	fmt.Println()
 	fmt.Println("  For Backlog (Multi-Profile Support):")
 	fmt.Println("    Pattern: BACKLOG_<PROFILE>_<SETTING>")
 	fmt.Println()
 	fmt.Println("    BACKLOG_<PROFILE>_API_KEY       Backlog API key")
-	fmt.Println("    BACKLOG_<PROFILE>_HOST          Backlog host (e.g., mycompany.backlog.com)")
-	fmt.Println("    BACKLOG_<PROFILE>_USER_ID       User ID (for analysis)")
+	fmt.Println("    BACKLOG_<PROFILE>_HOST          Backlog host (e.g., mycompany.backlog.com), or the space name to probe backlog.com and backlog.jp")
+	fmt.Println("    BACKLOG_<PROFILE>_USER_ID       (Optional) User ID (default: owner of the API key)")
 	fmt.Println("    BACKLOG_<PROFILE>_PROJECT_ID    Project ID (for analysis)")
 	fmt.Println()
 	fmt.Println("    Example for multiple profiles:")
@@ -454,7 +2452,7 @@ func printHelp() {
 	fmt.Println()
 	fmt.Println("  For Calendar:")
 	fmt.Println("    No additional environment variables required")
-	fmt.Println("    (Reads ICS files from storage/calendar directory)")
+	fmt.Println("    (Reads ICS files from storage/calendar directory and GOOGLE_TAKEOUT_PATH)")
 	fmt.Println()
 	fmt.Println("  For Notion:")
 	fmt.Println("    NOTION_TOKEN        Notion integration token")
@@ -464,6 +2462,8 @@ func printHelp() {
 	fmt.Println("    GOOGLE_CLIENT_ID     OAuth2 client ID (from GCP Console)")
 	fmt.Println("    GOOGLE_CLIENT_SECRET OAuth2 client secret")
 	fmt.Println("    GOOGLE_TOKEN_FILE    (Optional) Token cache path (default: storage/google_token.json)")
+	fmt.Println("    GOOGLE_TAKEOUT_PATH  (Optional) Google Takeout download (directory, .zip, or .tgz) analyzed offline:")
+	fmt.Println("                         Calendar ICS, My Activity Drive JSON, and Gmail mbox headers")
 }
 
 func printAvailableAnalyzers() {
@@ -473,6 +2473,17 @@ func printAvailableAnalyzers() {
 	fmt.Println("  calendar - Calendar event analysis")
 	fmt.Println("  notion   - Notion page analysis")
 	fmt.Println("  google   - Google Workspace activity analysis (Docs/Slides/Sheets)")
+	fmt.Println("  todoist  - Todoist completed task analysis")
+	fmt.Println("  jira     - Jira / Tempo logged time analysis")
+	fmt.Println("  harvest  - Harvest billable / non-billable hours per client project")
+	fmt.Println("  support  - Zendesk / Freshdesk tickets resolved, replies, and first-response time")
+	fmt.Println("  opsgenie - Opsgenie on-call shift hours and alerts handled")
+	fmt.Println("  copilot  - GitHub Copilot suggestions accepted per day")
+	fmt.Println("  gitea    - Gitea / Forgejo / Codeberg PRs, issues, and commits")
+	fmt.Println("  phabricator - Phabricator Differential revisions authored and reviewed")
+	fmt.Println("  focus    - Focus sessions and deep-work hours per day from Pomodoro/focus app exports")
+	fmt.Println("  github-archive - GitHub PRs, reviews, and issues from an account export (offline)")
+	fmt.Println("  webhooks - GitHub/Backlog events received by dev-stats ingest (offline)")
 	fmt.Println("  all      - Run all available analyzers")
 }
 
@@ -494,8 +2505,8 @@ func printOverallSummary(results []*common.AnalysisResult) {
 
 	for _, result := range results {
 		fmt.Printf("\n%s:\n", result.AnalyzerName)
-		for key, value := range result.Summary {
-			fmt.Printf("  %s: %v\n", key, value)
+		for _, metric := range result.Metrics {
+			fmt.Printf("  %s: %v\n", metric.Label, metric.Value)
 		}
 	}
 }
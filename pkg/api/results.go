@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"dev-stats/pkg/common"
+)
+
+// periodDirPattern matches the stats directories runs write: output/<start>_to_<end>/stats[-<scope>]
+var periodDirPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})_to_(\d{4}-\d{2}-\d{2})/stats(?:-([a-z]+))?$`)
+
+// resultSuffix ends the file names of results saved with -output json (<analyzer>-stats.json)
+const resultSuffix = "-stats.json"
+
+// Period is a period with saved results
+type Period struct {
+	Period    string   `json:"period"` // <start>_to_<end>, as in output/
+	StartDate string   `json:"start_date"`
+	EndDate   string   `json:"end_date"`
+	Scope     string   `json:"scope,omitempty"` // work or personal for -work-only / -personal-only runs
+	Analyzers []string `json:"analyzers"`
+	dir       string
+}
+
+// Metric is a metric of a saved result; durations are in hours
+type Metric struct {
+	Period   string      `json:"period"`
+	Scope    string      `json:"scope,omitempty"`
+	Analyzer string      `json:"analyzer"`
+	ID       string      `json:"id"`
+	Label    string      `json:"label"`
+	Value    interface{} `json:"value"`
+	Snapshot bool        `json:"snapshot,omitempty"`
+}
+
+// Item is an activity of a saved result
+type Item struct {
+	Period   string `json:"period"`
+	Analyzer string `json:"analyzer"`
+	common.Activity
+}
+
+// SavedResult holds the fields of <analyzer>-stats.json that are read
+type SavedResult struct {
+	Metrics    []Metric          `json:"metrics"`
+	Activities []common.Activity `json:"activities"`
+}
+
+// Results reads the results runs saved under an output directory
+type Results struct {
+	root string
+}
+
+// NewResults reads the results under root (output when empty)
+func NewResults(root string) *Results {
+	if root == "" {
+		root = "output"
+	}
+	return &Results{root: root}
+}
+
+// Periods lists the periods with saved results, newest first
+func (r *Results) Periods() ([]Period, error) {
+	dirs, err := filepath.Glob(filepath.Join(r.root, "*", "stats*"))
+	if err != nil {
+		return nil, common.WrapError(err, "failed to list %s", r.root)
+	}
+	var periods []Period
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(r.root, dir)
+		if err != nil {
+			continue
+		}
+		match := periodDirPattern.FindStringSubmatch(filepath.ToSlash(rel))
+		if match == nil {
+			continue
+		}
+		files, err := filepath.Glob(filepath.Join(dir, "*"+resultSuffix))
+		if err != nil || len(files) == 0 {
+			continue
+		}
+		period := Period{Period: match[1] + "_to_" + match[2], StartDate: match[1], EndDate: match[2], Scope: match[3], dir: dir}
+		for _, file := range files {
+			period.Analyzers = append(period.Analyzers, strings.TrimSuffix(filepath.Base(file), resultSuffix))
+		}
+		sort.Strings(period.Analyzers)
+		periods = append(periods, period)
+	}
+	sort.SliceStable(periods, func(i, j int) bool {
+		if periods[i].StartDate != periods[j].StartDate {
+			return periods[i].StartDate > periods[j].StartDate
+		}
+		if periods[i].EndDate != periods[j].EndDate {
+			return periods[i].EndDate < periods[j].EndDate
+		}
+		return periods[i].Scope < periods[j].Scope
+	})
+	return periods, nil
+}
+
+// Load reads the saved result of an analyzer in a period
+func (r *Results) Load(period Period, analyzer string) (*SavedResult, error) {
+	path := filepath.Join(period.dir, analyzer+resultSuffix)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to read %s", path)
+	}
+	var result SavedResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, common.WrapError(err, "failed to decode %s", path)
+	}
+	return &result, nil
+}
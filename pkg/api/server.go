@@ -0,0 +1,276 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dev-stats/pkg/common"
+)
+
+// TokenEnv names the bearer token clients must send (Authorization: Bearer <token>); required unless the server
+// only listens on a loopback address
+const TokenEnv = "SERVE_TOKEN"
+
+// Item limits of /api/items
+const (
+	defaultItemLimit = 100
+	maxItemLimit     = 1000
+)
+
+// Server answers read-only queries over the saved results and the activity history
+type Server struct {
+	results     *Results
+	historyPath string
+	token       string
+}
+
+// NewServer creates a server for addr, reading SERVE_TOKEN
+func NewServer(addr string, results *Results, historyPath string) (*Server, error) {
+	s := &Server{results: results, historyPath: historyPath, token: os.Getenv(TokenEnv)}
+	if s.token == "" && !isLoopback(addr) {
+		return nil, common.NewError("%s is required to listen on %s: stored results are private (or listen on 127.0.0.1)", TokenEnv, addr)
+	}
+	return s, nil
+}
+
+// isLoopback reports whether a listen address only accepts local connections
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Handler returns the routes of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /api/periods", s.authorized(s.handlePeriods))
+	mux.HandleFunc("GET /api/metrics", s.authorized(s.handleMetrics))
+	mux.HandleFunc("GET /api/items", s.authorized(s.handleItems))
+	mux.HandleFunc("GET /api/days", s.authorized(s.handleDays))
+	return mux
+}
+
+// authorized requires the bearer token when one is set
+func (s *Server) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+				writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+				return
+			}
+		}
+		handler(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// listParam splits a comma-separated query parameter
+func listParam(query url.Values, name string) []string {
+	var values []string
+	for _, value := range strings.Split(query.Get(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// matches reports whether value is one of allowed, or whether no filter is given
+func matches(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(a, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectPeriods returns the periods of the period and scope parameters; without scope only full runs, without
+// period all of them
+func (s *Server) selectPeriods(query url.Values) ([]Period, error) {
+	periods, err := s.results.Periods()
+	if err != nil {
+		return nil, err
+	}
+	names, scope := listParam(query, "period"), query.Get("scope")
+	var selected []Period
+	for _, period := range periods {
+		if period.Scope == scope && matches(names, period.Period) {
+			selected = append(selected, period)
+		}
+	}
+	return selected, nil
+}
+
+// handlePeriods lists the periods with saved results (GET /api/periods)
+func (s *Server) handlePeriods(w http.ResponseWriter, r *http.Request) {
+	periods, err := s.results.Periods()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if periods == nil {
+		periods = []Period{}
+	}
+	writeJSON(w, http.StatusOK, periods)
+}
+
+// handleMetrics lists metrics, filtered by period, scope, analyzer, and id (GET /api/metrics)
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	periods, err := s.selectPeriods(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	analyzers, ids := listParam(query, "analyzer"), listParam(query, "id")
+	metrics := []Metric{}
+	for _, period := range periods {
+		for _, analyzer := range period.Analyzers {
+			if !matches(analyzers, analyzer) {
+				continue
+			}
+			result, err := s.results.Load(period, analyzer)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			for _, metric := range result.Metrics {
+				if matches(ids, metric.ID) {
+					metric.Period, metric.Scope, metric.Analyzer = period.Period, period.Scope, analyzer
+					metrics = append(metrics, metric)
+				}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, metrics)
+}
+
+// itemFilter holds the item parameters besides period, scope, and analyzer
+type itemFilter struct {
+	kinds, projects []string
+	text            string
+	since, until    string // YYYY-MM-DD, inclusive, compared with the local day like history.json
+}
+
+func (f itemFilter) matches(activity common.Activity) bool {
+	day := activity.LocalDay()
+	return matches(f.kinds, activity.Kind) && matches(f.projects, activity.Project) &&
+		(f.since == "" || day >= f.since) && (f.until == "" || day <= f.until) &&
+		(f.text == "" || strings.Contains(strings.ToLower(activity.Title), f.text))
+}
+
+// handleItems lists activities, filtered by period, scope, analyzer, kind, project, q (text in the title), and
+// since/until (GET /api/items), newest first. Items saved in several periods (a month and its quarter) are listed once.
+func (s *Server) handleItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	periods, err := s.selectPeriods(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	limit, offset := defaultItemLimit, 0
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxItemLimit {
+			writeError(w, http.StatusBadRequest, "limit must be 1 to "+strconv.Itoa(maxItemLimit))
+			return
+		}
+	}
+	if value := query.Get("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			writeError(w, http.StatusBadRequest, "offset must be a non-negative number")
+			return
+		}
+	}
+	filter := itemFilter{
+		kinds:    listParam(query, "kind"),
+		projects: listParam(query, "project"),
+		text:     strings.ToLower(query.Get("q")),
+		since:    query.Get("since"),
+		until:    query.Get("until"),
+	}
+
+	analyzers := listParam(query, "analyzer")
+	seen := make(map[string]bool)
+	items := []Item{}
+	for _, period := range periods {
+		for _, analyzer := range period.Analyzers {
+			if !matches(analyzers, analyzer) {
+				continue
+			}
+			result, err := s.results.Load(period, analyzer)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			for _, activity := range result.Activities {
+				key := analyzer + "\x00" + activity.Kind + "\x00" + activity.ID
+				if seen[key] || !filter.matches(activity) {
+					continue
+				}
+				seen[key] = true
+				items = append(items, Item{Period: period.Period, Analyzer: analyzer, Activity: activity})
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Time.After(items[j].Time) })
+	total := len(items)
+	items = items[min(offset, total):min(offset+limit, total)]
+	writeJSON(w, http.StatusOK, map[string]interface{}{"total": total, "items": items})
+}
+
+// Day is the activity count of a day per source, from the history every run records
+type Day struct {
+	Date   string         `json:"date"`
+	Counts map[string]int `json:"counts"`
+}
+
+// handleDays lists the recorded activity counts per day, filtered by since/until (GET /api/days)
+func (s *Server) handleDays(w http.ResponseWriter, r *http.Request) {
+	history, err := common.LoadHistory(s.historyPath)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	since, until := r.URL.Query().Get("since"), r.URL.Query().Get("until")
+	days := []Day{}
+	for date, counts := range history.Days {
+		if (since == "" || date >= since) && (until == "" || date <= until) {
+			days = append(days, Day{Date: date, Counts: counts})
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	writeJSON(w, http.StatusOK, days)
+}
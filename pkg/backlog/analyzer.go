@@ -2,6 +2,7 @@ package backlog
 
 import (
 	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,30 +10,40 @@ import (
 	"os"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // BacklogAnalyzer implements the Analyzer interface for Backlog
 type BacklogAnalyzer struct {
-	profile *BacklogProfile
-	client  *common.HTTPClient
+	profile    *BacklogProfile
+	client     *common.HTTPClient
+	ignoreList config.IgnoreList
+	warnings   common.Warnings // optional lookups that failed during the current run
 }
 
 // Issue represents a Backlog issue
 type Issue struct {
 	ID          int       `json:"id"`
+	IssueKey    string    `json:"issueKey"`
 	Summary     string    `json:"summary"`
 	Created     time.Time `json:"created"`
 	Assignee    *User     `json:"assignee"`
 	CreatedUser User      `json:"createdUser"`
 	IssueType   IssueType `json:"issueType"`
 	Status      Status    `json:"status"`
+	// Hours recorded on the issue in total, nil when not entered
+	EstimatedHours *float64  `json:"estimatedHours"`
+	ActualHours    *float64  `json:"actualHours"`
+	Updated        time.Time `json:"updated"`
 }
 
 // User represents a Backlog user
 type User struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID          int    `json:"id"`
+	UserID      string `json:"userId,omitempty"`
+	Name        string `json:"name"`
+	MailAddress string `json:"mailAddress,omitempty"`
 }
 
 // IssueType represents a Backlog issue type
@@ -49,8 +60,11 @@ type Status struct {
 
 // Activity represents a Backlog activity
 type Activity struct {
-	ID      int                    `json:"id"`
-	Type    int                    `json:"type"`
+	ID      int `json:"id"`
+	Type    int `json:"type"`
+	Project struct {
+		ProjectKey string `json:"projectKey"`
+	} `json:"project"`
 	Content map[string]interface{} `json:"content"`
 	Created time.Time              `json:"created"`
 }
@@ -63,6 +77,37 @@ type ActivityItem struct {
 	Type    string
 }
 
+// Activity types based on official Backlog API documentation
+// https://developer.nulab.com/docs/backlog/api/2/get-activity/
+var activityTypes = map[int]string{
+	1:  "Issue Created",
+	2:  "Issue Updated",
+	3:  "Issue Commented",
+	4:  "Issue Deleted",
+	5:  "Wiki Created",
+	6:  "Wiki Updated",
+	7:  "Wiki Deleted",
+	8:  "File Added",
+	9:  "File Updated",
+	10: "File Deleted",
+	11: "SVN Committed",
+	12: "Git Pushed",
+	13: "Git Repository Created",
+	14: "Issue Multi Updated",
+	15: "Project User Added",
+	16: "Project User Deleted",
+	17: "Comment Notification Added",
+	18: "Pull Request Added",
+	19: "Pull Request Updated",
+	20: "Comment Added on Pull Request",
+	21: "Pull Request Deleted",
+	22: "Milestone Created",
+	23: "Milestone Updated",
+	24: "Milestone Deleted",
+	25: "Project Group Added",
+	26: "Project Group Deleted",
+}
+
 // NewBacklogAnalyzer creates a new Backlog analyzer (legacy method for backward compatibility)
 func NewBacklogAnalyzer() *BacklogAnalyzer {
 	// For backward compatibility, check old environment variables first
@@ -103,13 +148,14 @@ func (b *BacklogAnalyzer) ValidateConfig(writer io.Writer) error {
 	if b.profile.Host == "" {
 		return common.NewError("BACKLOG_HOST environment variable is required")
 	}
-	if b.profile.UserID == "" {
-		return common.NewError("BACKLOG_USER_ID environment variable is required")
-	}
 	if b.profile.ProjectID == "" {
 		return common.NewError("BACKLOG_PROJECT_ID environment variable is required")
 	}
 
+	if err := b.resolveHost(writer); err != nil {
+		return err
+	}
+
 	// Test API connectivity with helpful error messages
 	baseURL := b.profile.GetBaseURL()
 	fmt.Fprintf(writer, "Testing Backlog API connection to: %s\n", baseURL)
@@ -129,6 +175,122 @@ func (b *BacklogAnalyzer) ValidateConfig(writer io.Writer) error {
 	}
 	fmt.Fprintf(writer, "✓ Backlog API connection successful\n")
 
+	if err := b.ResolveUserID(writer); err != nil {
+		return err
+	}
+
+	// The API key carries the rights of the user who issued it
+	if err := b.checkPermissions(writer); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resolveHost replaces a bare space name in BACKLOG_<PROFILE>_HOST with the host the space is on, probing
+// /api/v2/space on backlog.com and then backlog.jp with the API key
+func (b *BacklogAnalyzer) resolveHost(writer io.Writer) error {
+	if !b.profile.HasSpaceNameOnly() {
+		return nil
+	}
+	space := b.profile.Host
+	params := url.Values{}
+	params.Set("apiKey", b.profile.APIKey)
+
+	var failures []string
+	for _, domain := range backlogDomains {
+		host := fmt.Sprintf("%s.%s", space, domain)
+		if _, err := b.client.Get(fmt.Sprintf("https://%s/api/v2/space?%s", host, params.Encode()), nil); err != nil {
+			failures = append(failures, fmt.Sprintf("  %s: %v", host, err))
+			continue
+		}
+		b.profile.Host = host
+		fmt.Fprintf(writer, "✓ Backlog space '%s' found at %s\n", space, host)
+		return nil
+	}
+	return common.NewError("Backlog space '%s' was not found on %s with BACKLOG_%s_API_KEY:\n%s",
+		space, strings.Join(backlogDomains, " or "), b.profile.Name, strings.Join(failures, "\n"))
+}
+
+// myself returns the owner of the API key (/users/myself)
+func (b *BacklogAnalyzer) myself() (*User, error) {
+	if err := b.resolveHost(io.Discard); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("apiKey", b.profile.APIKey)
+
+	myselfURL := fmt.Sprintf("%s/api/v2/users/myself?%s", b.profile.GetBaseURL(), params.Encode())
+	body, err := b.client.Get(myselfURL, nil)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to identify the owner of BACKLOG_%s_API_KEY", b.profile.Name)
+	}
+
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, common.WrapError(err, "failed to parse user response")
+	}
+	return &user, nil
+}
+
+// ResolveUserID fills in BACKLOG_<PROFILE>_USER_ID from the owner of the API key when it is not set
+func (b *BacklogAnalyzer) ResolveUserID(writer io.Writer) error {
+	if b.profile.UserID != "" {
+		return nil
+	}
+	user, err := b.myself()
+	if err != nil {
+		return err
+	}
+	b.profile.UserID = strconv.Itoa(user.ID)
+	fmt.Fprintf(writer, "✓ BACKLOG_%s_USER_ID not set; using the API key owner %s (ID: %d)\n", b.profile.Name, user.Name, user.ID)
+	return nil
+}
+
+// WhoAmI reports the owner of the API key
+func (b *BacklogAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	user, err := b.myself()
+	if err != nil {
+		return nil, err
+	}
+	identity := &common.Identity{
+		Source: fmt.Sprintf("Backlog (%s)", b.profile.Name),
+		ID:     strconv.Itoa(user.ID),
+		Login:  user.UserID,
+		Name:   user.Name,
+	}
+	if b.profile.UserID != "" && b.profile.UserID != identity.ID {
+		identity.Note = fmt.Sprintf("BACKLOG_%s_USER_ID is %s", b.profile.Name, b.profile.UserID)
+	}
+	return identity, nil
+}
+
+// checkPermissions verifies that the API key can read the configured user and project
+func (b *BacklogAnalyzer) checkPermissions(writer io.Writer) error {
+	params := url.Values{}
+	params.Set("apiKey", b.profile.APIKey)
+
+	myself, err := b.myself()
+	if err != nil {
+		return err
+	}
+	if strconv.Itoa(myself.ID) != b.profile.UserID {
+		fmt.Fprintf(writer, "⚠️  API key belongs to %s (ID: %d) but USER_ID is %s. Activities of other users may be restricted.\n",
+			myself.Name, myself.ID, b.profile.UserID)
+	}
+
+	projectURL := fmt.Sprintf("%s/api/v2/projects/%s?%s", b.profile.GetBaseURL(), b.profile.ProjectID, params.Encode())
+	if _, err := b.client.Get(projectURL, nil); err != nil {
+		switch common.HTTPStatusCode(err) {
+		case 403, 404:
+			return common.NewError("API key cannot access project %s.\n"+
+				"Ask a project administrator to add the API key owner to the project, or run 'make list-backlog' to find an accessible PROJECT_ID",
+				b.profile.ProjectID)
+		}
+		return common.WrapError(err, "failed to access project %s", b.profile.ProjectID)
+	}
+
+	fmt.Fprintf(writer, "✓ Backlog API key can access project %s\n", b.profile.ProjectID)
 	return nil
 }
 
@@ -137,19 +299,23 @@ func (b *BacklogAnalyzer) Analyze(config *common.Config, writer io.Writer) (*com
 	if err := b.ValidateConfig(writer); err != nil {
 		return nil, err
 	}
+	if err := b.ignoreList.Reload(); err != nil {
+		return nil, err
+	}
+	b.warnings.Reset()
 
 	fmt.Fprintf(writer, "Analyzing Backlog activity for user ID: %s\n", b.profile.UserID)
 	fmt.Fprintf(writer, "Host: %s, Project ID: %s\n", b.profile.Host, b.profile.ProjectID)
 	fmt.Fprintf(writer, "Date range: %s to %s\n", config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
 
 	// Get issues created by user
-	createdIssues, err := b.getIssuesCreatedByUser(config.StartDate, config.EndDate)
+	createdIssues, err := b.getIssuesCreatedByUser(writer, config.StartDate, config.EndDate)
 	if err != nil {
 		return nil, common.WrapError(err, "failed to get created issues")
 	}
 
 	// Get issues assigned to user
-	assignedIssues, err := b.getIssuesAssignedToUser(config.StartDate, config.EndDate)
+	assignedIssues, err := b.getIssuesAssignedToUser(writer, config.StartDate, config.EndDate)
 	if err != nil {
 		return nil, common.WrapError(err, "failed to get assigned issues")
 	}
@@ -160,6 +326,13 @@ func (b *BacklogAnalyzer) Analyze(config *common.Config, writer io.Writer) (*com
 		return nil, common.WrapError(err, "failed to get user activities")
 	}
 
+	createdIssues = b.filterIgnoredIssues(writer, createdIssues)
+	assignedIssues = b.filterIgnoredIssues(writer, assignedIssues)
+	activities = b.filterIgnoredActivities(writer, activities)
+
+	// Issues of other people that the user updated or commented on
+	involvedIssues := b.getInvolvedIssues(activities, config.StartDate)
+
 	// Analyze activities
 	activityStats := b.analyzeActivities(writer, activities)
 
@@ -169,90 +342,208 @@ func (b *BacklogAnalyzer) Analyze(config *common.Config, writer io.Writer) (*com
 	updatedWikis := b.extractUpdatedWikis(activities)
 	createdWikis := b.extractCreatedWikis(activities)
 
+	// Comment volume per issue, and wiki edits from the page history (activities only show one entry per save)
+	commentStats := b.analyzeComments(activities)
+	wikiStats := b.analyzeWikis(writer, config.StartDate, config.EndDate)
+
+	// Throughput: status changes made by the user, and how long assigned issues stayed open
+	transitionStats := b.analyzeTransitions(activities, assignedIssues)
+
+	// Pushes and pull requests in Backlog git repositories
+	gitStats := b.analyzeGit(activities)
+
+	// Actual and estimated hours entered on the assigned issues the user worked on in the period
+	workedIssues, err := b.getWorkedIssues(writer, config.StartDate, config.EndDate)
+	if err != nil {
+		b.warnings.Add("issue hours", "assigned issues updated in the period", err)
+	}
+	hoursStats := b.analyzeHours(b.filterIgnoredIssues(writer, workedIssues))
+
 	// Create result
 	result := &common.AnalysisResult{
 		AnalyzerName: b.GetName(),
 		StartDate:    config.StartDate,
 		EndDate:      config.EndDate,
-		Summary: map[string]interface{}{
-			"Issues created":   len(createdIssues),
-			"Issues assigned":  len(assignedIssues),
-			"Issues commented": len(commentedIssues),
-			"Issues updated":   len(updatedIssues),
-			"Wikis created":    len(createdWikis),
-			"Wikis updated":    len(updatedWikis),
-			"Total activities": len(activities),
-			"Activity types":   len(activityStats),
+		Metrics: []common.Metric{
+			{ID: "backlog.issues_created", Label: "Issues created", Value: len(createdIssues)},
+			{ID: "backlog.issues_assigned", Label: "Issues assigned", Value: len(assignedIssues)},
+			{ID: "backlog.issues_involved", Label: "Issues involved (not created or assigned)", Value: len(involvedIssues)},
+			{ID: "backlog.issues_commented", Label: "Issues commented", Value: len(commentedIssues)},
+			{ID: "backlog.issues_updated", Label: "Issues updated", Value: len(updatedIssues)},
+			{ID: "backlog.wikis_created", Label: "Wikis created", Value: len(createdWikis)},
+			{ID: "backlog.wikis_updated", Label: "Wikis updated", Value: len(updatedWikis)},
+			{ID: "backlog.activities_total", Label: "Total activities", Value: len(activities)},
+			{ID: "backlog.activity_types", Label: "Activity types", Value: len(activityStats), Snapshot: true},
+			{ID: "backlog.comments", Label: "Comments written", Value: commentStats.Total},
+			{ID: "backlog.comment_characters", Label: "Comment characters", Value: commentStats.Characters},
+			{ID: "backlog.wiki_pages_created", Label: "Wiki pages created (wiki API)", Value: len(wikiStats.Created)},
+			{ID: "backlog.wiki_pages_edited", Label: "Wiki pages edited (wiki API)", Value: len(wikiStats.Updated)},
+			{ID: "backlog.wiki_edits", Label: "Wiki edits", Value: wikiStats.Edits},
+			{ID: "backlog.issues_moved_in_progress", Label: "Issues moved to In Progress", Value: transitionStats.InProgress},
+			{ID: "backlog.issues_resolved", Label: "Issues resolved", Value: transitionStats.Resolved},
+			{ID: "backlog.issues_closed", Label: "Issues closed", Value: transitionStats.Closed},
+			{ID: "backlog.assigned_time_open_mean", Label: "Mean time open of assigned issues", Value: transitionStats.AverageTimeOpen, Snapshot: true},
+			{ID: "backlog.git_pushes", Label: "Git pushes", Value: gitStats.Pushes},
+			{ID: "backlog.git_commits", Label: "Git commits pushed", Value: gitStats.Commits},
+			{ID: "backlog.pull_requests_created", Label: "Pull requests created", Value: gitStats.Created},
+			{ID: "backlog.pull_requests_involved", Label: "Pull requests worked on", Value: len(gitStats.PullRequests)},
+			{ID: "backlog.pull_request_comments", Label: "Pull request comments", Value: gitStats.Comments},
+			{ID: "backlog.actual_hours", Label: "Actual hours of assigned issues", Value: hoursStats.Actual, Snapshot: true},
+			{ID: "backlog.estimated_hours", Label: "Estimated hours of assigned issues", Value: hoursStats.Estimated, Snapshot: true},
 		},
 		Details: map[string]interface{}{
 			"created_issues":   createdIssues,
 			"assigned_issues":  assignedIssues,
+			"involved_issues":  involvedIssues,
 			"commented_issues": commentedIssues,
 			"updated_issues":   updatedIssues,
 			"created_wikis":    createdWikis,
 			"updated_wikis":    updatedWikis,
 			"activities":       activities,
 			"activity_stats":   activityStats,
+			"comment_stats":    commentStats,
+			"wiki_stats":       wikiStats,
+			"transition_stats": transitionStats,
+			"git_stats":        gitStats,
+			"hours_stats":      hoursStats,
 		},
-	}
-
-	b.printResults(writer, result, createdIssues, assignedIssues, commentedIssues, updatedIssues, createdWikis, updatedWikis, activityStats)
+		Activities: append(b.buildActivities(activities), b.hoursActivities(hoursStats.Issues)...),
+		CSVTables:  b.csvTables(createdIssues, assignedIssues, involvedIssues, activities, hoursStats),
+	}
+	result.Explain("backlog.issues_created", b.issueActivities(createdIssues, "issue_created"))
+	result.Explain("backlog.issues_assigned", b.issueActivities(assignedIssues, "issue_assigned"))
+	result.Explain("backlog.issues_involved", b.issueActivities(involvedIssues, "issue_involved"))
+	result.Explain("backlog.issues_commented", b.itemActivities(commentedIssues))
+	result.Explain("backlog.issues_updated", b.itemActivities(updatedIssues))
+	result.Explain("backlog.wikis_created", b.itemActivities(createdWikis))
+	result.Explain("backlog.wikis_updated", b.itemActivities(updatedWikis))
+	result.Explain("backlog.pull_requests_created", b.pullRequestActivities(gitStats.PullRequests, true))
+	result.Explain("backlog.pull_requests_involved", b.pullRequestActivities(gitStats.PullRequests, false))
+	result.Explain("backlog.activities_total", b.buildActivities(activities))
+	result.Explain("backlog.actual_hours", b.hoursActivities(hoursStats.Issues))
+
+	b.printResults(writer, result, createdIssues, assignedIssues, involvedIssues, commentedIssues, updatedIssues, createdWikis, updatedWikis, activityStats)
+	b.printContentStats(writer, commentStats, wikiStats)
+	b.printTransitions(writer, transitionStats)
+	b.printGitStats(writer, gitStats)
+	b.printHours(writer, hoursStats)
+	result.Warnings = b.warnings.List()
 	return result, nil
 }
 
-func (b *BacklogAnalyzer) getIssuesCreatedByUser(startDate, endDate time.Time) ([]Issue, error) {
-	params := url.Values{}
-	params.Set("apiKey", b.profile.APIKey)
-	params.Set("projectId[]", b.profile.ProjectID)
-	params.Set("createdUserId[]", b.profile.UserID)
-	params.Set("createdSince", startDate.Format("2006-01-02"))
-	params.Set("createdUntil", endDate.Format("2006-01-02"))
-	params.Set("count", "100")
+// issueURL returns the browser URL of an issue
+func (b *BacklogAnalyzer) issueURL(issueKey string) string {
+	return fmt.Sprintf("%s/view/%s", b.profile.GetBaseURL(), issueKey)
+}
 
-	apiURL := fmt.Sprintf("%s/api/v2/issues?%s", b.profile.GetBaseURL(), params.Encode())
+// filterIgnoredIssues drops issues whose key or URL is listed in the ignore file
+func (b *BacklogAnalyzer) filterIgnoredIssues(writer io.Writer, issues []Issue) []Issue {
+	return config.FilterIgnored(writer, &b.ignoreList, issues, "issues", func(issue Issue) []string {
+		if issue.IssueKey == "" {
+			return nil
+		}
+		return []string{issue.IssueKey, b.issueURL(issue.IssueKey)}
+	})
+}
 
-	body, err := b.client.Get(apiURL, nil)
-	if err != nil {
-		return nil, err
+// filterIgnoredActivities drops activities on issues or wikis listed in the ignore file
+func (b *BacklogAnalyzer) filterIgnoredActivities(writer io.Writer, activities []Activity) []Activity {
+	return config.FilterIgnored(writer, &b.ignoreList, activities, "activities", func(activity Activity) []string {
+		return b.activityKeys(activity)
+	})
+}
+
+// activityIssueKey returns the key of the issue an activity is about, or "" for non-issue activities
+func (b *BacklogAnalyzer) activityIssueKey(activity Activity) string {
+	if keyID, ok := activity.Content["key_id"].(float64); ok && activity.Project.ProjectKey != "" {
+		return fmt.Sprintf("%s-%d", activity.Project.ProjectKey, int(keyID))
 	}
+	return ""
+}
 
-	var issues []Issue
-	if err := json.Unmarshal(body, &issues); err != nil {
-		return nil, common.WrapError(err, "failed to parse Backlog issues response")
+// activityURL returns the browser URL of the issue or wiki an activity is about
+func (b *BacklogAnalyzer) activityURL(activity Activity) string {
+	if issueKey := b.activityIssueKey(activity); issueKey != "" {
+		return b.issueURL(issueKey)
+	}
+	if activity.Type >= 5 && activity.Type <= 7 {
+		if id, ok := activity.Content["id"].(float64); ok {
+			return fmt.Sprintf("%s/alias/wiki/%d", b.profile.GetBaseURL(), int(id))
+		}
 	}
+	return b.gitActivityURL(activity)
+}
 
-	return issues, nil
+// activityKeys returns the issue key and URL that identify the target of an activity
+func (b *BacklogAnalyzer) activityKeys(activity Activity) []string {
+	return []string{b.activityIssueKey(activity), b.activityURL(activity)}
 }
 
-func (b *BacklogAnalyzer) getIssuesAssignedToUser(startDate, endDate time.Time) ([]Issue, error) {
-	params := url.Values{}
-	params.Set("apiKey", b.profile.APIKey)
-	params.Set("projectId[]", b.profile.ProjectID)
-	params.Set("assigneeId[]", b.profile.UserID)
-	params.Set("createdSince", startDate.Format("2006-01-02"))
-	params.Set("createdUntil", endDate.Format("2006-01-02"))
-	params.Set("count", "100")
+// issuesPageSize is the largest count the issues API accepts
+const issuesPageSize = 100
 
-	apiURL := fmt.Sprintf("%s/api/v2/issues?%s", b.profile.GetBaseURL(), params.Encode())
+func (b *BacklogAnalyzer) getIssuesCreatedByUser(writer io.Writer, startDate, endDate time.Time) ([]Issue, error) {
+	return b.getIssues(writer, "issues created by you", "createdUserId[]", "created", startDate, endDate)
+}
 
-	body, err := b.client.Get(apiURL, nil)
-	if err != nil {
-		return nil, err
-	}
+func (b *BacklogAnalyzer) getIssuesAssignedToUser(writer io.Writer, startDate, endDate time.Time) ([]Issue, error) {
+	return b.getIssues(writer, "issues assigned to you", "assigneeId[]", "created", startDate, endDate)
+}
 
+// getIssues lists the issues created (dateField "created") or updated ("updated") in the period that match
+// userParam (createdUserId[] or assigneeId[]), following offset pagination. Issues are sorted by creation date
+// so that the pages don't shift while issues are being updated.
+func (b *BacklogAnalyzer) getIssues(writer io.Writer, label, userParam, dateField string, startDate, endDate time.Time) ([]Issue, error) {
 	var issues []Issue
-	if err := json.Unmarshal(body, &issues); err != nil {
-		return nil, common.WrapError(err, "failed to parse Backlog issues response")
-	}
+	seen := make(map[int]bool)
+
+	for offset := 0; ; offset += issuesPageSize {
+		params := url.Values{}
+		params.Set("apiKey", b.profile.APIKey)
+		params.Set("projectId[]", b.profile.ProjectID)
+		params.Set(userParam, b.profile.UserID)
+		params.Set(dateField+"Since", startDate.Format("2006-01-02"))
+		params.Set(dateField+"Until", endDate.Format("2006-01-02"))
+		params.Set("sort", "created")
+		params.Set("order", "desc")
+		params.Set("count", strconv.Itoa(issuesPageSize))
+		params.Set("offset", strconv.Itoa(offset))
+
+		apiURL := fmt.Sprintf("%s/api/v2/issues?%s", b.profile.GetBaseURL(), params.Encode())
+
+		body, err := b.client.Get(apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []Issue
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, common.WrapError(err, "failed to parse Backlog issues response")
+		}
 
-	return issues, nil
+		for _, issue := range page {
+			if !seen[issue.ID] {
+				seen[issue.ID] = true
+				issues = append(issues, issue)
+			}
+		}
+
+		if len(page) < issuesPageSize {
+			return issues, nil
+		}
+		fmt.Fprintf(writer, "Fetched %d %s so far, requesting more...\n", len(issues), label)
+	}
 }
 
 func (b *BacklogAnalyzer) getUserActivities(startDate, endDate time.Time) ([]Activity, error) {
+	userIDInt, _ := strconv.Atoi(b.profile.UserID)
+	return b.getActivitiesOfUser(userIDInt, startDate, endDate)
+}
+
+// getActivitiesOfUser fetches the activities of any user in the space within the period, newest first
+func (b *BacklogAnalyzer) getActivitiesOfUser(userIDInt int, startDate, endDate time.Time) ([]Activity, error) {
 	var allActivities []Activity
 	maxId := ""
-
-	userIDInt, _ := strconv.Atoi(b.profile.UserID)
 	requestCount := 0
 
 	for {
@@ -307,37 +598,6 @@ func (b *BacklogAnalyzer) getUserActivities(startDate, endDate time.Time) ([]Act
 }
 
 func (b *BacklogAnalyzer) analyzeActivities(writer io.Writer, activities []Activity) map[string]int {
-	// Activity types based on official Backlog API documentation
-	// https://developer.nulab.com/docs/backlog/api/2/get-activity/
-	activityTypes := map[int]string{
-		1:  "Issue Created",
-		2:  "Issue Updated",
-		3:  "Issue Commented",
-		4:  "Issue Deleted",
-		5:  "Wiki Created",
-		6:  "Wiki Updated",
-		7:  "Wiki Deleted",
-		8:  "File Added",
-		9:  "File Updated",
-		10: "File Deleted",
-		11: "SVN Committed",
-		12: "Git Pushed",
-		13: "Git Repository Created",
-		14: "Issue Multi Updated",
-		15: "Project User Added",
-		16: "Project User Deleted",
-		17: "Comment Notification Added",
-		18: "Pull Request Added",
-		19: "Pull Request Updated",
-		20: "Comment Added on Pull Request",
-		21: "Pull Request Deleted",
-		22: "Milestone Created",
-		23: "Milestone Updated",
-		24: "Milestone Deleted",
-		25: "Project Group Added",
-		26: "Project Group Deleted",
-	}
-
 	stats := make(map[string]int)
 	unknownTypes := make(map[int][]string) // Track unknown types with examples
 
@@ -364,14 +624,79 @@ func (b *BacklogAnalyzer) analyzeActivities(writer io.Writer, activities []Activ
 	// Print unknown activity types with examples for debugging
 	if len(unknownTypes) > 0 {
 		fmt.Fprintln(writer, "\nUnknown activity types found:")
-		for actType, examples := range unknownTypes {
-			fmt.Fprintf(writer, "  Type %d: %v\n", actType, examples)
+		actTypes := make([]int, 0, len(unknownTypes))
+		for actType := range unknownTypes {
+			actTypes = append(actTypes, actType)
+		}
+		sort.Ints(actTypes)
+		for _, actType := range actTypes {
+			fmt.Fprintf(writer, "  Type %d: %v\n", actType, unknownTypes[actType])
 		}
 	}
 
 	return stats
 }
 
+// buildActivities converts Backlog activities into common dated activities
+func (b *BacklogAnalyzer) buildActivities(activities []Activity) []common.Activity {
+	var result []common.Activity
+	for _, activity := range activities {
+		kind, exists := activityTypes[activity.Type]
+		if !exists {
+			kind = fmt.Sprintf("Activity type %d", activity.Type)
+		}
+
+		title := b.gitActivityTitle(activity)
+		if title == "" {
+			title, _ = activity.Content["summary"].(string)
+		}
+		if title == "" {
+			title, _ = activity.Content["name"].(string)
+		}
+
+		result = append(result, common.Activity{
+			Source: b.GetName(),
+			Kind:   kind,
+			ID:     strconv.Itoa(activity.ID),
+			Title:  title,
+			URL:    b.activityURL(activity),
+			Time:   activity.Created,
+		})
+	}
+	return result
+}
+
+// issueActivities converts issues into activities for -explain
+func (b *BacklogAnalyzer) issueActivities(issues []Issue, kind string) []common.Activity {
+	var result []common.Activity
+	for _, issue := range issues {
+		result = append(result, common.Activity{
+			Source: b.GetName(),
+			Kind:   kind,
+			ID:     issue.IssueKey,
+			Title:  fmt.Sprintf("%s %s", issue.IssueKey, issue.Summary),
+			URL:    b.issueURL(issue.IssueKey),
+			Time:   issue.Created,
+		})
+	}
+	return result
+}
+
+// itemActivities converts commented/updated issues and wikis into activities for -explain
+func (b *BacklogAnalyzer) itemActivities(items []ActivityItem) []common.Activity {
+	var result []common.Activity
+	for _, item := range items {
+		result = append(result, common.Activity{
+			Source: b.GetName(),
+			Kind:   item.Type,
+			ID:     strconv.Itoa(item.ID),
+			Title:  item.Title,
+			Time:   item.Created,
+		})
+	}
+	return result
+}
+
 func (b *BacklogAnalyzer) extractCommentedIssues(activities []Activity) []ActivityItem {
 	var items []ActivityItem
 	seen := make(map[int]bool)
@@ -492,7 +817,7 @@ func (b *BacklogAnalyzer) extractCreatedWikis(activities []Activity) []ActivityI
 	return items
 }
 
-func (b *BacklogAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, createdIssues, assignedIssues []Issue, commentedIssues, updatedIssues, createdWikis, updatedWikis []ActivityItem, activityStats map[string]int) {
+func (b *BacklogAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, createdIssues, assignedIssues, involvedIssues []Issue, commentedIssues, updatedIssues, createdWikis, updatedWikis []ActivityItem, activityStats map[string]int) {
 	fmt.Fprintf(writer, "\nBacklog activity from %s to %s:\n",
 		result.StartDate.Format("2006-01-02"),
 		result.EndDate.Format("2006-01-02"))
@@ -516,6 +841,17 @@ func (b *BacklogAnalyzer) printResults(writer io.Writer, result *common.Analysis
 		fmt.Fprintln(writer)
 	}
 
+	fmt.Fprintf(writer, "Other issues you updated or commented on (%d):\n", len(involvedIssues))
+	for _, issue := range involvedIssues {
+		fmt.Fprintf(writer, "- %s: %s %s\n", issue.Created.Format("2006-01-02 15:04"), issue.IssueKey, issue.Summary)
+		fmt.Fprintf(writer, "  Type: %s\n", issue.IssueType.Name)
+		fmt.Fprintf(writer, "  Status: %s\n", issue.Status.Name)
+		if issue.CreatedUser.ID != 0 {
+			fmt.Fprintf(writer, "  Created by: %s\n", issue.CreatedUser.Name)
+		}
+		fmt.Fprintln(writer)
+	}
+
 	fmt.Fprintf(writer, "Issues you commented on (%d):\n", len(commentedIssues))
 	for _, item := range commentedIssues {
 		fmt.Fprintf(writer, "- %s: %s\n", item.Created.Format("2006-01-02 15:04"), item.Title)
@@ -562,7 +898,8 @@ func (b *BacklogAnalyzer) printResults(writer io.Writer, result *common.Analysis
 		}
 		return sortedStats[i].count > sortedStats[j].count
 	})
-	for i, stat := range sortedStats {
+	for i, stat := range sortedStats[:common.RankingLimit(len(sortedStats))] {
 		fmt.Fprintf(writer, "- %d. %s: %d\n", i+1, stat.name, stat.count)
 	}
+	common.PrintMoreEntries(writer, len(sortedStats))
 }
@@ -0,0 +1,213 @@
+package backlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"dev-stats/pkg/common"
+)
+
+// wikiHistoryPageSize is the largest count the wiki history API accepts
+const wikiHistoryPageSize = 100
+
+// IssueCommentCount is the number of comments the user wrote on one issue
+type IssueCommentCount struct {
+	IssueKey string `json:"issue_key"`
+	Summary  string `json:"summary"`
+	Comments int    `json:"comments"`
+}
+
+// CommentStats summarizes the comments the user wrote in the period
+type CommentStats struct {
+	Total      int                 `json:"total"`
+	Characters int                 `json:"characters"`
+	ByIssue    []IssueCommentCount `json:"by_issue"`
+}
+
+// WikiPage is a wiki page from the wiki API
+type WikiPage struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	CreatedUser User      `json:"createdUser"`
+	Created     time.Time `json:"created"`
+	UpdatedUser User      `json:"updatedUser"`
+	Updated     time.Time `json:"updated"`
+	Edits       int       `json:"edits"` // versions saved by the user in the period
+}
+
+// wikiVersion is one entry of a wiki page's history
+type wikiVersion struct {
+	Version     int       `json:"version"`
+	CreatedUser User      `json:"createdUser"`
+	Created     time.Time `json:"created"`
+}
+
+// WikiStats lists the wiki pages the user created or edited in the period
+type WikiStats struct {
+	Created []WikiPage `json:"created"`
+	Updated []WikiPage `json:"updated"`
+	Edits   int        `json:"edits"`
+}
+
+// analyzeComments counts the user's comments and their length per issue from "Issue Commented" activities
+func (b *BacklogAnalyzer) analyzeComments(activities []Activity) *CommentStats {
+	stats := &CommentStats{}
+	byIssue := make(map[string]*IssueCommentCount)
+	for _, activity := range activities {
+		if activity.Type != 3 {
+			continue
+		}
+		stats.Total++
+		if comment, ok := activity.Content["comment"].(map[string]interface{}); ok {
+			if content, ok := comment["content"].(string); ok {
+				stats.Characters += utf8.RuneCountInString(content)
+			}
+		}
+
+		issueKey := b.activityIssueKey(activity)
+		if issueKey == "" {
+			continue
+		}
+		count, exists := byIssue[issueKey]
+		if !exists {
+			summary, _ := activity.Content["summary"].(string)
+			count = &IssueCommentCount{IssueKey: issueKey, Summary: summary}
+			byIssue[issueKey] = count
+		}
+		count.Comments++
+	}
+
+	for _, count := range byIssue {
+		stats.ByIssue = append(stats.ByIssue, *count)
+	}
+	sort.Slice(stats.ByIssue, func(i, j int) bool {
+		if stats.ByIssue[i].Comments != stats.ByIssue[j].Comments {
+			return stats.ByIssue[i].Comments > stats.ByIssue[j].Comments
+		}
+		return stats.ByIssue[i].IssueKey < stats.ByIssue[j].IssueKey
+	})
+	return stats
+}
+
+// analyzeWikis lists the project's wiki pages and counts the versions the user saved in the period from each
+// page's history. Only pages last updated on or after the start date can have such versions.
+func (b *BacklogAnalyzer) analyzeWikis(writer io.Writer, startDate, endDate time.Time) *WikiStats {
+	stats := &WikiStats{}
+	pages, err := b.getWikiPages()
+	if err != nil {
+		b.warnings.Add("wiki pages", b.profile.ProjectID, err)
+		return stats
+	}
+
+	userID, _ := strconv.Atoi(b.profile.UserID)
+	end := endDate.AddDate(0, 0, 1)
+	inPeriod := func(t time.Time) bool { return !t.Before(startDate) && t.Before(end) }
+
+	fmt.Fprintf(writer, "Checking the history of %d wiki pages...\n", len(pages))
+	for _, page := range pages {
+		if page.CreatedUser.ID == userID && inPeriod(page.Created) {
+			stats.Created = append(stats.Created, page)
+		}
+		if page.Updated.Before(startDate) {
+			continue
+		}
+		history, err := b.getWikiHistory(page.ID)
+		if err != nil {
+			b.warnings.Add("wiki history", page.Name, err)
+			continue
+		}
+		for _, version := range history {
+			// The first version is the page creation, counted above
+			if version.Version > 1 && version.CreatedUser.ID == userID && inPeriod(version.Created) {
+				page.Edits++
+			}
+		}
+		if page.Edits > 0 {
+			stats.Edits += page.Edits
+			stats.Updated = append(stats.Updated, page)
+		}
+	}
+
+	sort.Slice(stats.Created, func(i, j int) bool { return stats.Created[i].Created.Before(stats.Created[j].Created) })
+	sort.Slice(stats.Updated, func(i, j int) bool {
+		if stats.Updated[i].Edits != stats.Updated[j].Edits {
+			return stats.Updated[i].Edits > stats.Updated[j].Edits
+		}
+		return stats.Updated[i].Name < stats.Updated[j].Name
+	})
+	return stats
+}
+
+// getWikiPages lists the wiki pages of the profile's project
+func (b *BacklogAnalyzer) getWikiPages() ([]WikiPage, error) {
+	params := url.Values{}
+	params.Set("apiKey", b.profile.APIKey)
+	params.Set("projectIdOrKey", b.profile.ProjectID)
+
+	body, err := b.client.Get(fmt.Sprintf("%s/api/v2/wikis?%s", b.profile.GetBaseURL(), params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	var pages []WikiPage
+	if err := json.Unmarshal(body, &pages); err != nil {
+		return nil, common.WrapError(err, "failed to parse Backlog wikis response")
+	}
+	return pages, nil
+}
+
+// getWikiHistory returns the versions of a wiki page, newest first, following maxId pagination
+func (b *BacklogAnalyzer) getWikiHistory(wikiID int) ([]wikiVersion, error) {
+	var versions []wikiVersion
+	maxID := ""
+	for {
+		params := url.Values{}
+		params.Set("apiKey", b.profile.APIKey)
+		params.Set("count", strconv.Itoa(wikiHistoryPageSize))
+		params.Set("order", "desc")
+		if maxID != "" {
+			params.Set("maxId", maxID)
+		}
+
+		body, err := b.client.Get(fmt.Sprintf("%s/api/v2/wikis/%d/history?%s", b.profile.GetBaseURL(), wikiID, params.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []wikiVersion
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, common.WrapError(err, "failed to parse Backlog wiki history response")
+		}
+		versions = append(versions, page...)
+		if len(page) < wikiHistoryPageSize {
+			return versions, nil
+		}
+		maxID = strconv.Itoa(page[len(page)-1].Version)
+	}
+}
+
+// printContentStats prints comments per issue and the wiki pages created and edited
+func (b *BacklogAnalyzer) printContentStats(writer io.Writer, comments *CommentStats, wikis *WikiStats) {
+	fmt.Fprintf(writer, "\nComments written (%d, %d characters):\n", comments.Total, comments.Characters)
+	if len(comments.ByIssue) == 0 {
+		fmt.Fprintln(writer, "- No comments found")
+	}
+	for _, count := range comments.ByIssue[:common.RankingLimit(len(comments.ByIssue))] {
+		fmt.Fprintf(writer, "- %s %s: %d\n", count.IssueKey, count.Summary, count.Comments)
+	}
+	common.PrintMoreEntries(writer, len(comments.ByIssue))
+
+	fmt.Fprintf(writer, "\nWiki pages created (%d):\n", len(wikis.Created))
+	for _, page := range wikis.Created {
+		fmt.Fprintf(writer, "- %s: %s\n", page.Created.Format("2006-01-02 15:04"), page.Name)
+	}
+	fmt.Fprintf(writer, "\nWiki pages edited (%d pages, %d edits):\n", len(wikis.Updated), wikis.Edits)
+	for _, page := range wikis.Updated[:common.RankingLimit(len(wikis.Updated))] {
+		fmt.Fprintf(writer, "- %s: %d edits\n", page.Name, page.Edits)
+	}
+	common.PrintMoreEntries(writer, len(wikis.Updated))
+}
@@ -0,0 +1,68 @@
+package backlog
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// issueCSVRow is one issue in backlog-<profile>-issues.csv
+type issueCSVRow struct {
+	Relation  string    `csv:"relation"`
+	IssueKey  string    `csv:"issue_key"`
+	Summary   string    `csv:"summary"`
+	IssueType string    `csv:"issue_type"`
+	Status    string    `csv:"status"`
+	Assignee  string    `csv:"assignee"`
+	CreatedBy string    `csv:"created_by"`
+	Created   time.Time `csv:"created"`
+	URL       string    `csv:"url"`
+}
+
+// activityCSVRow is one activity in backlog-<profile>-activities.csv
+type activityCSVRow struct {
+	Type    string    `csv:"type"`
+	Project string    `csv:"project"`
+	Title   string    `csv:"title"`
+	Created time.Time `csv:"created"`
+	URL     string    `csv:"url"`
+}
+
+// csvTables lists created, assigned, and involved issues, every activity of the user, and the hours of assigned issues
+func (b *BacklogAnalyzer) csvTables(createdIssues, assignedIssues, involvedIssues []Issue, activities []Activity, hours *HoursStats) []common.CSVTable {
+	var issues []issueCSVRow
+	for _, group := range []struct {
+		relation string
+		issues   []Issue
+	}{{"created", createdIssues}, {"assigned", assignedIssues}, {"involved", involvedIssues}} {
+		for _, issue := range group.issues {
+			row := issueCSVRow{
+				Relation:  group.relation,
+				IssueKey:  issue.IssueKey,
+				Summary:   issue.Summary,
+				IssueType: issue.IssueType.Name,
+				Status:    issue.Status.Name,
+				CreatedBy: issue.CreatedUser.Name,
+				Created:   issue.Created,
+				URL:       b.issueURL(issue.IssueKey),
+			}
+			if issue.Assignee != nil {
+				row.Assignee = issue.Assignee.Name
+			}
+			issues = append(issues, row)
+		}
+	}
+
+	var rows []activityCSVRow
+	for i, activity := range b.buildActivities(activities) {
+		rows = append(rows, activityCSVRow{
+			Type:    activity.Kind,
+			Project: activities[i].Project.ProjectKey,
+			Title:   activity.Title,
+			Created: activity.Time,
+			URL:     activity.URL,
+		})
+	}
+
+	return []common.CSVTable{common.NewCSVTable("issues", issues), common.NewCSVTable("activities", rows), common.NewCSVTable("hours", hours.Issues)}
+}
@@ -0,0 +1,227 @@
+package backlog
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// Git activity types
+const (
+	activityGitPushed          = 12
+	activityPullRequestAdded   = 18
+	activityPullRequestUpdated = 19
+	activityPullRequestComment = 20
+)
+
+// GitRepoStats is the user's git activity in one Backlog repository
+type GitRepoStats struct {
+	Repository   string `json:"repository"` // PROJECT/repo
+	Pushes       int    `json:"pushes"`
+	Commits      int    `json:"commits"`
+	PullRequests int    `json:"pull_requests"` // pull requests created, updated, or commented on
+}
+
+// BacklogPullRequest is a pull request the user created, updated, or commented on in the period
+type BacklogPullRequest struct {
+	Repository string    `json:"repository"`
+	Number     int       `json:"number"`
+	Summary    string    `json:"summary"`
+	URL        string    `json:"url"`
+	Created    bool      `json:"created"` // created by the user in the period
+	Updates    int       `json:"updates"`
+	Comments   int       `json:"comments"`
+	First      time.Time `json:"first"` // earliest activity of the user on it in the period
+}
+
+// GitStats summarizes pushes and pull requests from the git activities of the user
+type GitStats struct {
+	Pushes       int                  `json:"pushes"`
+	Commits      int                  `json:"commits"` // revisions in the pushes
+	Created      int                  `json:"created"` // pull requests created
+	Comments     int                  `json:"comments"`
+	PullRequests []BacklogPullRequest `json:"pull_requests"`
+	ByRepo       []GitRepoStats       `json:"by_repo"`
+}
+
+// activityRepository returns PROJECT/repo for git and pull request activities, or "" for other activities
+func (b *BacklogAnalyzer) activityRepository(activity Activity) string {
+	repository, ok := activity.Content["repository"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	name, _ := repository["name"].(string)
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", activity.Project.ProjectKey, name)
+}
+
+// repositoryURL returns the browser URL of a repository given as PROJECT/repo
+func (b *BacklogAnalyzer) repositoryURL(repository string) string {
+	return fmt.Sprintf("%s/git/%s", b.profile.GetBaseURL(), repository)
+}
+
+// pullRequestURL returns the browser URL of a pull request
+func (b *BacklogAnalyzer) pullRequestURL(repository string, number int) string {
+	return fmt.Sprintf("%s/pullRequests/%d", b.repositoryURL(repository), number)
+}
+
+// gitActivityTitle describes a push or pull request activity, e.g. "APP/web: 3 commits to main" or
+// "APP/web#5 Add login form"; it is "" for other activities
+func (b *BacklogAnalyzer) gitActivityTitle(activity Activity) string {
+	repository := b.activityRepository(activity)
+	if repository == "" {
+		return ""
+	}
+	switch activity.Type {
+	case activityGitPushed:
+		count, _ := activity.Content["revision_count"].(float64)
+		ref, _ := activity.Content["ref"].(string)
+		return fmt.Sprintf("%s: %d commits to %s", repository, int(count), strings.TrimPrefix(ref, "refs/heads/"))
+	case activityPullRequestAdded, activityPullRequestUpdated, activityPullRequestComment:
+		number, _ := activity.Content["number"].(float64)
+		summary, _ := activity.Content["summary"].(string)
+		return fmt.Sprintf("%s#%d %s", repository, int(number), summary)
+	}
+	return repository
+}
+
+// gitActivityURL returns the browser URL of the pull request or repository of a git activity
+func (b *BacklogAnalyzer) gitActivityURL(activity Activity) string {
+	repository := b.activityRepository(activity)
+	if repository == "" {
+		return ""
+	}
+	if number, ok := activity.Content["number"].(float64); ok {
+		return b.pullRequestURL(repository, int(number))
+	}
+	return b.repositoryURL(repository)
+}
+
+// analyzeGit counts pushes and commits per repository and lists the pull requests the user worked on
+// from "Git Pushed" and "Pull Request Added/Updated/Comment Added" activities
+func (b *BacklogAnalyzer) analyzeGit(activities []Activity) *GitStats {
+	stats := &GitStats{}
+	byRepo := make(map[string]*GitRepoStats)
+	pullRequests := make(map[string]*BacklogPullRequest)
+	repoStats := func(repository string) *GitRepoStats {
+		stat, exists := byRepo[repository]
+		if !exists {
+			stat = &GitRepoStats{Repository: repository}
+			byRepo[repository] = stat
+		}
+		return stat
+	}
+
+	for _, activity := range activities {
+		repository := b.activityRepository(activity)
+		if repository == "" {
+			continue
+		}
+		switch activity.Type {
+		case activityGitPushed:
+			count, _ := activity.Content["revision_count"].(float64)
+			stat := repoStats(repository)
+			stat.Pushes++
+			stat.Commits += int(count)
+			stats.Pushes++
+			stats.Commits += int(count)
+		case activityPullRequestAdded, activityPullRequestUpdated, activityPullRequestComment:
+			number, _ := activity.Content["number"].(float64)
+			key := fmt.Sprintf("%s#%d", repository, int(number))
+			pullRequest, exists := pullRequests[key]
+			if !exists {
+				summary, _ := activity.Content["summary"].(string)
+				pullRequest = &BacklogPullRequest{Repository: repository, Number: int(number), Summary: summary, URL: b.pullRequestURL(repository, int(number)), First: activity.Created}
+				pullRequests[key] = pullRequest
+				repoStats(repository).PullRequests++
+			}
+			if activity.Created.Before(pullRequest.First) {
+				pullRequest.First = activity.Created
+			}
+			switch activity.Type {
+			case activityPullRequestAdded:
+				pullRequest.Created = true
+				stats.Created++
+			case activityPullRequestUpdated:
+				pullRequest.Updates++
+			case activityPullRequestComment:
+				pullRequest.Comments++
+				stats.Comments++
+			}
+		}
+	}
+
+	for _, stat := range byRepo {
+		stats.ByRepo = append(stats.ByRepo, *stat)
+	}
+	sort.Slice(stats.ByRepo, func(i, j int) bool {
+		a, b := stats.ByRepo[i], stats.ByRepo[j]
+		if a.Pushes+a.PullRequests != b.Pushes+b.PullRequests {
+			return a.Pushes+a.PullRequests > b.Pushes+b.PullRequests
+		}
+		return a.Repository < b.Repository
+	})
+	for _, pullRequest := range pullRequests {
+		stats.PullRequests = append(stats.PullRequests, *pullRequest)
+	}
+	sort.Slice(stats.PullRequests, func(i, j int) bool {
+		if stats.PullRequests[i].Repository != stats.PullRequests[j].Repository {
+			return stats.PullRequests[i].Repository < stats.PullRequests[j].Repository
+		}
+		return stats.PullRequests[i].Number < stats.PullRequests[j].Number
+	})
+	return stats
+}
+
+// pullRequestActivities converts the pull requests into activities for -explain
+func (b *BacklogAnalyzer) pullRequestActivities(pullRequests []BacklogPullRequest, createdOnly bool) []common.Activity {
+	var result []common.Activity
+	for _, pullRequest := range pullRequests {
+		if createdOnly && !pullRequest.Created {
+			continue
+		}
+		result = append(result, common.Activity{
+			Source: b.GetName(),
+			Kind:   "pull_request",
+			ID:     pullRequest.URL,
+			Title:  fmt.Sprintf("%s#%d %s", pullRequest.Repository, pullRequest.Number, pullRequest.Summary),
+			URL:    pullRequest.URL,
+			Time:   pullRequest.First,
+		})
+	}
+	return result
+}
+
+// printGitStats prints pushes per repository and the pull requests the user worked on
+func (b *BacklogAnalyzer) printGitStats(writer io.Writer, stats *GitStats) {
+	fmt.Fprintf(writer, "\nGit activity (%d pushes, %d commits):\n", stats.Pushes, stats.Commits)
+	if len(stats.ByRepo) == 0 {
+		fmt.Fprintln(writer, "- No git activity found")
+		return
+	}
+	for _, repo := range stats.ByRepo[:common.RankingLimit(len(stats.ByRepo))] {
+		fmt.Fprintf(writer, "- %s: %d pushes, %d commits, %d pull requests\n", repo.Repository, repo.Pushes, repo.Commits, repo.PullRequests)
+	}
+	common.PrintMoreEntries(writer, len(stats.ByRepo))
+
+	fmt.Fprintf(writer, "\nPull requests (%d created, %d worked on, %d comments):\n", stats.Created, len(stats.PullRequests), stats.Comments)
+	for _, pullRequest := range stats.PullRequests {
+		var parts []string
+		if pullRequest.Created {
+			parts = append(parts, "created")
+		}
+		if pullRequest.Updates > 0 {
+			parts = append(parts, fmt.Sprintf("%d updates", pullRequest.Updates))
+		}
+		if pullRequest.Comments > 0 {
+			parts = append(parts, fmt.Sprintf("%d comments", pullRequest.Comments))
+		}
+		fmt.Fprintf(writer, "- %s#%d %s (%s)\n", pullRequest.Repository, pullRequest.Number, pullRequest.Summary, strings.Join(parts, ", "))
+	}
+}
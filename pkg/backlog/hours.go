@@ -0,0 +1,173 @@
+package backlog
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// IssueHours is the estimated and actual hours recorded on an issue the user is assigned to
+type IssueHours struct {
+	IssueKey  string        `json:"issue_key" csv:"issue_key"`
+	Summary   string        `json:"summary" csv:"summary"`
+	Project   string        `json:"project" csv:"project"`
+	IssueType string        `json:"issue_type" csv:"issue_type"`
+	Estimated time.Duration `json:"estimated" csv:"estimated_hours"`
+	Actual    time.Duration `json:"actual" csv:"actual_hours"`
+	Updated   time.Time     `json:"updated" csv:"updated"`
+	URL       string        `json:"url" csv:"url"`
+}
+
+// HoursGroup totals the hours of the issues of one project or issue type
+type HoursGroup struct {
+	Name      string        `json:"name"`
+	Issues    int           `json:"issues"`
+	Estimated time.Duration `json:"estimated"`
+	Actual    time.Duration `json:"actual"`
+}
+
+// HoursStats totals Backlog's time tracking (actualHours / estimatedHours) of the issues the user worked on
+type HoursStats struct {
+	Estimated time.Duration `json:"estimated"`
+	Actual    time.Duration `json:"actual"`
+	Issues    []IssueHours  `json:"issues"`
+	ByProject []HoursGroup  `json:"by_project"`
+	ByType    []HoursGroup  `json:"by_type"`
+}
+
+// getWorkedIssues lists the issues assigned to the user that were updated in the period, whenever they were created
+func (b *BacklogAnalyzer) getWorkedIssues(writer io.Writer, startDate, endDate time.Time) ([]Issue, error) {
+	return b.getIssues(writer, "issues you worked on", "assigneeId[]", "updated", startDate, endDate)
+}
+
+// analyzeHours totals the actual and estimated hours of the issues with hours entered, per project (the issue key
+// prefix) and per issue type. Backlog keeps one running total per issue, so hours logged before the period are
+// included.
+func (b *BacklogAnalyzer) analyzeHours(issues []Issue) *HoursStats {
+	stats := &HoursStats{}
+	byProject := make(map[string]*HoursGroup)
+	byType := make(map[string]*HoursGroup)
+	add := func(groups map[string]*HoursGroup, name string, hours IssueHours) {
+		group, exists := groups[name]
+		if !exists {
+			group = &HoursGroup{Name: name}
+			groups[name] = group
+		}
+		group.Issues++
+		group.Estimated += hours.Estimated
+		group.Actual += hours.Actual
+	}
+
+	for _, issue := range issues {
+		if issue.ActualHours == nil && issue.EstimatedHours == nil {
+			continue
+		}
+		hours := IssueHours{
+			IssueKey:  issue.IssueKey,
+			Summary:   issue.Summary,
+			Project:   issueProjectKey(issue.IssueKey),
+			IssueType: issue.IssueType.Name,
+			Estimated: hoursDuration(issue.EstimatedHours),
+			Actual:    hoursDuration(issue.ActualHours),
+			Updated:   issue.Updated,
+			URL:       b.issueURL(issue.IssueKey),
+		}
+		stats.Issues = append(stats.Issues, hours)
+		stats.Estimated += hours.Estimated
+		stats.Actual += hours.Actual
+		add(byProject, hours.Project, hours)
+		add(byType, hours.IssueType, hours)
+	}
+
+	sort.Slice(stats.Issues, func(i, j int) bool {
+		if stats.Issues[i].Actual != stats.Issues[j].Actual {
+			return stats.Issues[i].Actual > stats.Issues[j].Actual
+		}
+		return stats.Issues[i].IssueKey < stats.Issues[j].IssueKey
+	})
+	stats.ByProject = sortedHoursGroups(byProject)
+	stats.ByType = sortedHoursGroups(byType)
+	return stats
+}
+
+// sortedHoursGroups returns the groups by actual hours, then by name
+func sortedHoursGroups(groups map[string]*HoursGroup) []HoursGroup {
+	var result []HoursGroup
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Actual != result[j].Actual {
+			return result[i].Actual > result[j].Actual
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// issueProjectKey returns the project key of an issue key (APP-12 → APP)
+func issueProjectKey(issueKey string) string {
+	if idx := strings.LastIndex(issueKey, "-"); idx > 0 {
+		return issueKey[:idx]
+	}
+	return issueKey
+}
+
+// hoursDuration converts hours entered on an issue into a duration, 0 when not entered
+func hoursDuration(hours *float64) time.Duration {
+	if hours == nil {
+		return 0
+	}
+	return time.Duration(*hours * float64(time.Hour)).Round(time.Minute)
+}
+
+// hoursActivities converts the actual hours of each issue into a worklog dated at its last update, so that
+// Backlog time tracking is reconciled with calendar hours like Jira and Harvest worklogs
+func (b *BacklogAnalyzer) hoursActivities(issues []IssueHours) []common.Activity {
+	var result []common.Activity
+	for _, issue := range issues {
+		if issue.Actual == 0 {
+			continue
+		}
+		result = append(result, common.Activity{
+			Source:   b.GetName(),
+			Kind:     common.ActivityKindWorklog,
+			ID:       issue.IssueKey + "/actual-hours",
+			Title:    fmt.Sprintf("%s %s", issue.IssueKey, issue.Summary),
+			URL:      issue.URL,
+			Time:     issue.Updated,
+			Duration: issue.Actual,
+		})
+	}
+	return result
+}
+
+// printHours prints actual and estimated hours per project, per issue type, and per issue
+func (b *BacklogAnalyzer) printHours(writer io.Writer, stats *HoursStats) {
+	fmt.Fprintf(writer, "\nTime tracking of assigned issues updated in the period (actual %s, estimated %s):\n",
+		common.FormatDuration(stats.Actual), common.FormatDuration(stats.Estimated))
+	if len(stats.Issues) == 0 {
+		fmt.Fprintln(writer, "- No hours entered")
+		return
+	}
+	for _, group := range []struct {
+		label  string
+		groups []HoursGroup
+	}{{"By project", stats.ByProject}, {"By issue type", stats.ByType}} {
+		fmt.Fprintf(writer, "%s:\n", group.label)
+		for _, hours := range group.groups {
+			fmt.Fprintf(writer, "- %s: %s actual / %s estimated (%d issues)\n", hours.Name,
+				common.FormatDuration(hours.Actual), common.FormatDuration(hours.Estimated), hours.Issues)
+		}
+	}
+	fmt.Fprintln(writer, "By issue:")
+	for _, issue := range stats.Issues[:common.RankingLimit(len(stats.Issues))] {
+		fmt.Fprintf(writer, "- %s %s: %s actual / %s estimated\n", issue.IssueKey, issue.Summary,
+			common.FormatDuration(issue.Actual), common.FormatDuration(issue.Estimated))
+	}
+	common.PrintMoreEntries(writer, len(stats.Issues))
+}
@@ -0,0 +1,86 @@
+package backlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// involvementActivityTypes are the activities that make the user involved in an issue: "Issue Updated" and "Issue Commented"
+var involvementActivityTypes = map[int]bool{2: true, 3: true}
+
+// getInvolvedIssues returns the issues the user updated or commented on in the period without being their creator
+// or assignee, so that work on other people's tickets shows up next to created and assigned issues. Issue IDs come
+// from the activities and the details from /issues with id[] and updatedSince, 100 IDs per request. A failed
+// request is recorded as a warning and its issues are left out.
+func (b *BacklogAnalyzer) getInvolvedIssues(activities []Activity, startDate time.Time) []Issue {
+	userID, _ := strconv.Atoi(b.profile.UserID)
+	var ids []int
+	seen := make(map[int]bool)
+	for _, activity := range activities {
+		if !involvementActivityTypes[activity.Type] {
+			continue
+		}
+		id, ok := activity.Content["id"].(float64)
+		if !ok || seen[int(id)] {
+			continue
+		}
+		seen[int(id)] = true
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+
+	var issues []Issue
+	for start := 0; start < len(ids); start += issuesPageSize {
+		end := start + issuesPageSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		page, err := b.getIssuesByID(ids[start:end], startDate)
+		if err != nil {
+			b.warnings.Add("involved issues", fmt.Sprintf("%d issues", end-start), err)
+			continue
+		}
+		for _, issue := range page {
+			if issue.CreatedUser.ID == userID || (issue.Assignee != nil && issue.Assignee.ID == userID) {
+				continue
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if !issues[i].Created.Equal(issues[j].Created) {
+			return issues[i].Created.Before(issues[j].Created)
+		}
+		return issues[i].ID < issues[j].ID
+	})
+	return issues
+}
+
+// getIssuesByID fetches up to issuesPageSize issues of the profile's project by ID, updated since the start date
+func (b *BacklogAnalyzer) getIssuesByID(ids []int, startDate time.Time) ([]Issue, error) {
+	params := url.Values{}
+	params.Set("apiKey", b.profile.APIKey)
+	params.Set("projectId[]", b.profile.ProjectID)
+	for _, id := range ids {
+		params.Add("id[]", strconv.Itoa(id))
+	}
+	params.Set("updatedSince", startDate.Format("2006-01-02"))
+	params.Set("count", strconv.Itoa(issuesPageSize))
+
+	body, err := b.client.Get(fmt.Sprintf("%s/api/v2/issues?%s", b.profile.GetBaseURL(), params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	var issues []Issue
+	if err := json.Unmarshal(body, &issues); err != nil {
+		return nil, common.WrapError(err, "failed to parse Backlog issues response")
+	}
+	return issues, nil
+}
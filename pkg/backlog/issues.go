@@ -0,0 +1,66 @@
+package backlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"dev-stats/pkg/common"
+)
+
+// defaultPriorityID is Backlog's "Normal" priority
+const defaultPriorityID = "3"
+
+// CreateIssue adds an issue to the profile's project (BACKLOG_<PROFILE>_PROJECT_ID), assigned to the profile user.
+// The project's first issue type is used.
+func (b *BacklogAnalyzer) CreateIssue(summary, description string) (*Issue, error) {
+	if !b.profile.IsAnalysisReady() {
+		return nil, common.NewError("Backlog profile '%s' is missing PROJECT_ID", b.profile.Name)
+	}
+	if err := b.resolveHost(io.Discard); err != nil {
+		return nil, err
+	}
+	if err := b.ResolveUserID(io.Discard); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("apiKey", b.profile.APIKey)
+	typesURL := fmt.Sprintf("%s/api/v2/projects/%s/issueTypes?%s", b.profile.GetBaseURL(), b.profile.ProjectID, params.Encode())
+	body, err := b.client.Get(typesURL, nil)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get Backlog issue types")
+	}
+	var issueTypes []IssueType
+	if err := json.Unmarshal(body, &issueTypes); err != nil {
+		return nil, common.WrapError(err, "failed to parse Backlog issue types response")
+	}
+	if len(issueTypes) == 0 {
+		return nil, common.NewError("Backlog project %s has no issue types", b.profile.ProjectID)
+	}
+
+	form := url.Values{}
+	form.Set("projectId", b.profile.ProjectID)
+	form.Set("summary", summary)
+	form.Set("description", description)
+	form.Set("issueTypeId", fmt.Sprintf("%d", issueTypes[0].ID))
+	form.Set("priorityId", defaultPriorityID)
+	form.Set("assigneeId", b.profile.UserID)
+
+	issuesURL := fmt.Sprintf("%s/api/v2/issues?%s", b.profile.GetBaseURL(), params.Encode())
+	body, err = b.client.Post(issuesURL, form.Encode(), map[string]string{"Content-Type": "application/x-www-form-urlencoded"})
+	if err != nil {
+		return nil, common.WrapError(err, "failed to create Backlog issue")
+	}
+	var issue Issue
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return nil, common.WrapError(err, "failed to parse Backlog issue response")
+	}
+	return &issue, nil
+}
+
+// IssueURL returns the browser URL of an issue
+func (b *BacklogAnalyzer) IssueURL(issueKey string) string {
+	return b.issueURL(issueKey)
+}
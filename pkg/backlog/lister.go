@@ -50,6 +50,9 @@ func (b *BacklogAnalyzer) ListProjects(writer io.Writer) error {
 	if b.profile.Host == "" {
 		return common.NewError("BACKLOG_HOST environment variable is required")
 	}
+	if err := b.resolveHost(writer); err != nil {
+		return err
+	}
 
 	params := url.Values{}
 	params.Set("apiKey", b.profile.APIKey)
@@ -100,6 +103,9 @@ func (b *BacklogAnalyzer) ListProjectMembers(projectID string, writer io.Writer)
 	if b.profile.Host == "" {
 		return common.NewError("BACKLOG_HOST environment variable is required")
 	}
+	if err := b.resolveHost(writer); err != nil {
+		return err
+	}
 	if projectID == "" {
 		return common.NewError("Project ID is required")
 	}
@@ -191,7 +197,7 @@ func getCachePath(profileName string) string {
 func (b *BacklogAnalyzer) loadCache() (*ProfileCache, error) {
 	cachePath := getCachePath(b.profile.Name)
 
-	data, err := os.ReadFile(cachePath)
+	data, err := common.ReadProtectedFile(cachePath)
 	if err != nil {
 		return nil, err
 	}
@@ -217,7 +223,7 @@ func (b *BacklogAnalyzer) saveCache(cache *ProfileCache) error {
 		return err
 	}
 
-	return os.WriteFile(cachePath, data, 0644)
+	return common.WriteProtectedFile(cachePath, data)
 }
 
 // ListAllProjectsAndMembersWithCache lists all projects and members with caching
@@ -232,6 +238,9 @@ func (b *BacklogAnalyzer) ListAllProjectsAndMembersWithCache(writer io.Writer, f
 
 	// Fetch fresh data from API
 	fmt.Fprintf(writer, "\n🔄 Fetching fresh data from Backlog API...\n")
+	if err := b.resolveHost(writer); err != nil {
+		return err
+	}
 
 	// Get projects
 	params := url.Values{}
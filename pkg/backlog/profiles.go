@@ -11,11 +11,19 @@ import (
 type BacklogProfile struct {
 	Name      string
 	APIKey    string
-	Host      string // e.g., "mycompany.backlog.com" or "projectspace.backlog.jp"
+	Host      string // e.g., "mycompany.backlog.com" or "projectspace.backlog.jp", or only the space name "mycompany"
 	UserID    string
 	ProjectID string
 }
 
+// backlogDomains are the domains a bare space name is probed on, in order
+var backlogDomains = []string{"backlog.com", "backlog.jp"}
+
+// HasSpaceNameOnly reports whether HOST is a bare space name ("mycompany") that still has to be resolved to a host
+func (p *BacklogProfile) HasSpaceNameOnly() bool {
+	return p.Host != "" && !strings.Contains(p.Host, ".")
+}
+
 // GetBaseURL returns the base URL for this profile
 func (p *BacklogProfile) GetBaseURL() string {
 	return fmt.Sprintf("https://%s", p.Host)
@@ -26,9 +34,10 @@ func (p *BacklogProfile) IsComplete() bool {
 	return p.APIKey != "" && p.Host != ""
 }
 
-// IsAnalysisReady returns true if profile is ready for analysis (has user and project IDs)
+// IsAnalysisReady returns true if profile is ready for analysis (has a project ID).
+// USER_ID is optional: it is resolved from the owner of the API key when not set.
 func (p *BacklogProfile) IsAnalysisReady() bool {
-	return p.IsComplete() && p.UserID != "" && p.ProjectID != ""
+	return p.IsComplete() && p.ProjectID != ""
 }
 
 // LoadBacklogProfiles loads all Backlog profiles from environment variables
@@ -112,3 +121,34 @@ func GetProfileByName(name string) (*BacklogProfile, error) {
 	}
 	return nil, fmt.Errorf("profile '%s' not found", name)
 }
+
+// SelectProfiles returns the profiles named in selection, a comma-separated list of profile names
+// (case-insensitive). An empty selection falls back to BACKLOG_PROFILE; "all" or neither returns every profile.
+func SelectProfiles(selection string) ([]BacklogProfile, error) {
+	profiles := LoadBacklogProfiles()
+	selection = strings.TrimSpace(selection)
+	if selection == "" {
+		selection = strings.TrimSpace(os.Getenv("BACKLOG_PROFILE"))
+	}
+	if selection == "" || strings.EqualFold(selection, "all") {
+		return profiles, nil
+	}
+
+	var selected []BacklogProfile
+	for _, name := range strings.Split(selection, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		profile, err := GetProfileByName(name)
+		if err != nil {
+			names := make([]string, 0, len(profiles))
+			for _, p := range profiles {
+				names = append(names, p.Name)
+			}
+			return nil, fmt.Errorf("%w (configured: %s)", err, strings.Join(names, ", "))
+		}
+		selected = append(selected, *profile)
+	}
+	return selected, nil
+}
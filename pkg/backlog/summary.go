@@ -0,0 +1,64 @@
+package backlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// Summarize counts the issues created by and assigned to the user with /issues/count (-summary-only).
+// Activities (comments, updates, wikis) have no count endpoint and are left out, and issues listed in
+// ignore.yaml are still counted.
+func (b *BacklogAnalyzer) Summarize(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := b.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(writer, "Counting Backlog issues for user ID: %s (summary only)\n", b.profile.UserID)
+	created, err := b.countIssues("createdUserId[]", config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to count created issues")
+	}
+	assigned, err := b.countIssues("assigneeId[]", config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to count assigned issues")
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: b.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "backlog.issues_created", Label: "Issues created", Value: created},
+			{ID: "backlog.issues_assigned", Label: "Issues assigned", Value: assigned},
+		},
+	}
+	result.PrintSummary(writer)
+	return result, nil
+}
+
+// countIssues returns the number of issues created in the period that match userParam (createdUserId[] or assigneeId[])
+func (b *BacklogAnalyzer) countIssues(userParam string, startDate, endDate time.Time) (int, error) {
+	params := url.Values{}
+	params.Set("apiKey", b.profile.APIKey)
+	params.Set("projectId[]", b.profile.ProjectID)
+	params.Set(userParam, b.profile.UserID)
+	params.Set("createdSince", startDate.Format("2006-01-02"))
+	params.Set("createdUntil", endDate.Format("2006-01-02"))
+
+	body, err := b.client.Get(fmt.Sprintf("%s/api/v2/issues/count?%s", b.profile.GetBaseURL(), params.Encode()), nil)
+	if err != nil {
+		return 0, err
+	}
+	var response struct {
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return 0, common.WrapError(err, "failed to parse Backlog issue count response")
+	}
+	return response.Count, nil
+}
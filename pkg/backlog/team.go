@@ -0,0 +1,151 @@
+package backlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// MemberActivity is the activity count of one space member in the period, a row of the member report
+type MemberActivity struct {
+	UserID          string `json:"user_id" csv:"user_id"`
+	Name            string `json:"name" csv:"name"`
+	Projects        string `json:"projects" csv:"projects"` // project keys the user is a member of, joined with " "
+	Total           int    `json:"total" csv:"total"`
+	IssuesCreated   int    `json:"issues_created" csv:"issues_created"`
+	IssuesUpdated   int    `json:"issues_updated" csv:"issues_updated"`
+	Comments        int    `json:"comments" csv:"comments"`
+	WikiEdits       int    `json:"wiki_edits" csv:"wiki_edits"`
+	GitPushes       int    `json:"git_pushes" csv:"git_pushes"`
+	PullRequests    int    `json:"pull_requests" csv:"pull_requests"` // pull requests added, updated, or commented on
+	OtherActivities int    `json:"other_activities" csv:"other_activities"`
+}
+
+// MemberActivities reports the activity counts of every member of the space's projects in the period, for team
+// capacity discussions. Members come from each project's member list and their activities from
+// /users/{id}/activities, which shows other users' activities to space admins only. A member whose activities
+// cannot be fetched is recorded as a warning and left out. Rows are sorted by total activities.
+func (b *BacklogAnalyzer) MemberActivities(config *common.Config, writer io.Writer) ([]MemberActivity, []common.Warning, error) {
+	b.warnings.Reset()
+	if b.profile.APIKey == "" {
+		return nil, nil, common.NewError("BACKLOG_API_KEY environment variable is required")
+	}
+	if b.profile.Host == "" {
+		return nil, nil, common.NewError("BACKLOG_HOST environment variable is required")
+	}
+	if err := b.resolveHost(writer); err != nil {
+		return nil, nil, err
+	}
+
+	projects, err := b.getAllProjects()
+	if err != nil {
+		return nil, nil, err
+	}
+	members := make(map[int]*MemberActivity)
+	projectKeys := make(map[int][]string)
+	for _, project := range projects {
+		projectMembers, err := b.getProjectMembersInternal(fmt.Sprintf("%d", project.ID))
+		if err != nil {
+			b.warnings.Add("project members", project.ProjectKey, err)
+			continue
+		}
+		for _, member := range projectMembers {
+			if _, exists := members[member.ID]; !exists {
+				members[member.ID] = &MemberActivity{UserID: member.UserID, Name: member.Name}
+			}
+			projectKeys[member.ID] = append(projectKeys[member.ID], project.ProjectKey)
+		}
+	}
+
+	ids := make([]int, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var rows []MemberActivity
+	for i, id := range ids {
+		member := members[id]
+		fmt.Fprintf(writer, "Fetching activities of %s (%d/%d)...\n", member.Name, i+1, len(ids))
+		activities, err := b.getActivitiesOfUser(id, config.StartDate, config.EndDate)
+		if err != nil {
+			b.warnings.Add("member activities", member.Name, err)
+			continue
+		}
+		member.Projects = strings.Join(projectKeys[id], " ")
+		countMemberActivities(member, activities)
+		rows = append(rows, *member)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Total != rows[j].Total {
+			return rows[i].Total > rows[j].Total
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows, b.warnings.List(), nil
+}
+
+// countMemberActivities adds the activities to the member's counts by activity type
+func countMemberActivities(member *MemberActivity, activities []Activity) {
+	for _, activity := range activities {
+		member.Total++
+		switch activity.Type {
+		case 1:
+			member.IssuesCreated++
+		case 2, 14:
+			member.IssuesUpdated++
+		case 3:
+			member.Comments++
+		case 5, 6:
+			member.WikiEdits++
+		case activityGitPushed:
+			member.GitPushes++
+		case activityPullRequestAdded, activityPullRequestUpdated, activityPullRequestComment:
+			member.PullRequests++
+		default:
+			member.OtherActivities++
+		}
+	}
+}
+
+// getAllProjects lists the space's projects that are not archived; all=true includes the projects a space admin
+// is not a member of
+func (b *BacklogAnalyzer) getAllProjects() ([]Project, error) {
+	params := url.Values{}
+	params.Set("apiKey", b.profile.APIKey)
+	params.Set("all", "true")
+	params.Set("archived", "false")
+
+	body, err := b.client.Get(fmt.Sprintf("%s/api/v2/projects?%s", b.profile.GetBaseURL(), params.Encode()), nil)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get projects")
+	}
+	var projects []Project
+	if err := json.Unmarshal(body, &projects); err != nil {
+		return nil, common.WrapError(err, "failed to parse projects response")
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].ProjectKey < projects[j].ProjectKey
+	})
+	return projects, nil
+}
+
+// PrintMemberActivities prints the member report as a table
+func PrintMemberActivities(writer io.Writer, rows []MemberActivity, startDate, endDate time.Time) {
+	fmt.Fprintf(writer, "\n=== Backlog member activity (%s to %s) ===\n\n", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if len(rows) == 0 {
+		fmt.Fprintln(writer, "No members found")
+		return
+	}
+	fmt.Fprintf(writer, "%-24s %6s %7s %7s %8s %5s %6s %4s %6s\n", "Name", "Total", "Created", "Updated", "Comments", "Wiki", "Pushes", "PRs", "Other")
+	for _, row := range rows {
+		fmt.Fprintf(writer, "%-24s %6d %7d %7d %8d %5d %6d %4d %6d\n", truncate(row.Name, 24), row.Total, row.IssuesCreated, row.IssuesUpdated, row.Comments, row.WikiEdits, row.GitPushes, row.PullRequests, row.OtherActivities)
+	}
+}
@@ -0,0 +1,199 @@
+package backlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// commentsPageSize is the largest count the issue comments API accepts
+const commentsPageSize = 100
+
+// Backlog's built-in status IDs; custom statuses are not tracked
+const (
+	statusInProgress = 2
+	statusResolved   = 3
+	statusClosed     = 4
+)
+
+// trackedStatuses names the statuses whose transitions are counted
+var trackedStatuses = map[int]string{
+	statusInProgress: "In Progress",
+	statusResolved:   "Resolved",
+	statusClosed:     "Closed",
+}
+
+// IssueTransition is a status change the user made on an issue
+type IssueTransition struct {
+	IssueKey string    `json:"issue_key"`
+	Summary  string    `json:"summary"`
+	Status   string    `json:"status"` // In Progress, Resolved, or Closed
+	Changed  time.Time `json:"changed"`
+}
+
+// IssueTimeOpen is how long an assigned issue stayed open before it was resolved or closed
+type IssueTimeOpen struct {
+	IssueKey string        `json:"issue_key"`
+	Summary  string        `json:"summary"`
+	Resolved time.Time     `json:"resolved"`
+	TimeOpen time.Duration `json:"time_open"`
+}
+
+// TransitionStats counts the issues the user moved to In Progress, Resolved, and Closed, and how long
+// the assigned issues took to be resolved
+type TransitionStats struct {
+	InProgress      int               `json:"in_progress"` // issues, each counted once per status
+	Resolved        int               `json:"resolved"`
+	Closed          int               `json:"closed"`
+	Transitions     []IssueTransition `json:"transitions"`
+	AssignedDone    []IssueTimeOpen   `json:"assigned_done"`
+	AssignedOpen    int               `json:"assigned_open"` // assigned issues not resolved or closed yet
+	AverageTimeOpen time.Duration     `json:"average_time_open"`
+}
+
+// issueComment is one entry of an issue's comments, carrying the field changes saved with it
+type issueComment struct {
+	ID        int `json:"id"`
+	ChangeLog []struct {
+		Field    string `json:"field"`
+		NewValue string `json:"newValue"`
+	} `json:"changeLog"`
+	Created time.Time `json:"created"`
+}
+
+// analyzeTransitions counts status changes from the user's "Issue Updated" activities and measures the time from
+// creation to resolution of the assigned issues that are resolved or closed, from each issue's change log
+func (b *BacklogAnalyzer) analyzeTransitions(activities []Activity, assignedIssues []Issue) *TransitionStats {
+	stats := &TransitionStats{}
+	seen := make(map[string]bool) // status + issue key
+	for _, activity := range activities {
+		if activity.Type != 2 {
+			continue
+		}
+		issueKey := b.activityIssueKey(activity)
+		changes, _ := activity.Content["changes"].([]interface{})
+		for _, change := range changes {
+			fields, ok := change.(map[string]interface{})
+			if !ok || fields["field"] != "status" {
+				continue
+			}
+			statusID, _ := strconv.Atoi(fmt.Sprint(fields["new_value"]))
+			status, tracked := trackedStatuses[statusID]
+			if !tracked {
+				continue
+			}
+			summary, _ := activity.Content["summary"].(string)
+			stats.Transitions = append(stats.Transitions, IssueTransition{IssueKey: issueKey, Summary: summary, Status: status, Changed: activity.Created})
+			if seen[status+issueKey] {
+				continue
+			}
+			seen[status+issueKey] = true
+			switch statusID {
+			case statusInProgress:
+				stats.InProgress++
+			case statusResolved:
+				stats.Resolved++
+			case statusClosed:
+				stats.Closed++
+			}
+		}
+	}
+	sort.SliceStable(stats.Transitions, func(i, j int) bool {
+		return stats.Transitions[i].Changed.Before(stats.Transitions[j].Changed)
+	})
+
+	var total time.Duration
+	for _, issue := range assignedIssues {
+		if issue.Status.ID != statusResolved && issue.Status.ID != statusClosed {
+			stats.AssignedOpen++
+			continue
+		}
+		resolved, err := b.getResolutionTime(issue.IssueKey)
+		if err != nil {
+			b.warnings.Add("issue comments", issue.IssueKey, err)
+			continue
+		}
+		if resolved.IsZero() {
+			continue // resolved when created, or the change log was not kept
+		}
+		timeOpen := resolved.Sub(issue.Created)
+		stats.AssignedDone = append(stats.AssignedDone, IssueTimeOpen{IssueKey: issue.IssueKey, Summary: issue.Summary, Resolved: resolved, TimeOpen: timeOpen})
+		total += timeOpen
+	}
+	if len(stats.AssignedDone) > 0 {
+		stats.AverageTimeOpen = (total / time.Duration(len(stats.AssignedDone))).Round(time.Minute)
+	}
+	sort.Slice(stats.AssignedDone, func(i, j int) bool {
+		if stats.AssignedDone[i].TimeOpen != stats.AssignedDone[j].TimeOpen {
+			return stats.AssignedDone[i].TimeOpen > stats.AssignedDone[j].TimeOpen
+		}
+		return stats.AssignedDone[i].IssueKey < stats.AssignedDone[j].IssueKey
+	})
+	return stats
+}
+
+// getResolutionTime returns when the issue was first set to Resolved or Closed, from the change logs of its
+// comments (oldest first, following minId pagination). It is zero when no such change was saved.
+func (b *BacklogAnalyzer) getResolutionTime(issueKey string) (time.Time, error) {
+	minID := ""
+	for {
+		params := url.Values{}
+		params.Set("apiKey", b.profile.APIKey)
+		params.Set("count", strconv.Itoa(commentsPageSize))
+		params.Set("order", "asc")
+		if minID != "" {
+			params.Set("minId", minID)
+		}
+
+		body, err := b.client.Get(fmt.Sprintf("%s/api/v2/issues/%s/comments?%s", b.profile.GetBaseURL(), url.PathEscape(issueKey), params.Encode()), nil)
+		if err != nil {
+			return time.Time{}, err
+		}
+		var comments []issueComment
+		if err := json.Unmarshal(body, &comments); err != nil {
+			return time.Time{}, common.WrapError(err, "failed to parse Backlog issue comments response")
+		}
+		for _, comment := range comments {
+			for _, change := range comment.ChangeLog {
+				if change.Field != "status" {
+					continue
+				}
+				if statusID, _ := strconv.Atoi(change.NewValue); statusID == statusResolved || statusID == statusClosed {
+					return comment.Created, nil
+				}
+			}
+		}
+		if len(comments) < commentsPageSize {
+			return time.Time{}, nil
+		}
+		minID = strconv.Itoa(comments[len(comments)-1].ID)
+	}
+}
+
+// printTransitions prints the status changes made in the period and the time assigned issues stayed open
+func (b *BacklogAnalyzer) printTransitions(writer io.Writer, stats *TransitionStats) {
+	fmt.Fprintf(writer, "\nStatus changes (issues moved to In Progress: %d, Resolved: %d, Closed: %d):\n", stats.InProgress, stats.Resolved, stats.Closed)
+	if len(stats.Transitions) == 0 {
+		fmt.Fprintln(writer, "- No status changes found")
+	}
+	for _, transition := range stats.Transitions {
+		fmt.Fprintf(writer, "- %s: %s %s → %s\n", transition.Changed.Format("2006-01-02 15:04"), transition.IssueKey, transition.Summary, transition.Status)
+	}
+
+	fmt.Fprintf(writer, "\nTime open of assigned issues (%d resolved or closed, %d still open):\n", len(stats.AssignedDone), stats.AssignedOpen)
+	if len(stats.AssignedDone) == 0 {
+		fmt.Fprintln(writer, "- No assigned issue was resolved or closed")
+		return
+	}
+	fmt.Fprintf(writer, "- Average: %s\n", common.FormatDuration(stats.AverageTimeOpen))
+	for _, done := range stats.AssignedDone[:common.RankingLimit(len(stats.AssignedDone))] {
+		fmt.Fprintf(writer, "- %s %s: %s\n", done.IssueKey, done.Summary, common.FormatDuration(done.TimeOpen))
+	}
+	common.PrintMoreEntries(writer, len(stats.AssignedDone))
+}
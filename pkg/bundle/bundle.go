@@ -0,0 +1,198 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// formatVersion is increased when the content of a bundle changes incompatibly
+const formatVersion = 1
+
+// PassphraseEnv names the variable holding the passphrase bundles are encrypted with
+const PassphraseEnv = "BUNDLE_PASSPHRASE"
+
+// Bundle is what export-bundle carries to another machine: the period, the settings and API responses the analyzers
+// need to run again, and stored files read instead of an API (raw Calendar/Notion data, GitHub/Notion/Backlog caches)
+type Bundle struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"created_at"`
+	StartDate string            `json:"start_date"`
+	EndDate   string            `json:"end_date"`
+	Analyzers []string          `json:"analyzers"` // analyzers that ran without errors, the default of -analyzer on import
+	Env       map[string]string `json:"env"`       // secrets replaced by Placeholder
+	Files     map[string][]byte `json:"files"`     // by slash-separated path relative to the working directory
+	Exchanges []Exchange        `json:"exchanges"`
+}
+
+// New creates an empty bundle of a period
+func New(startDate, endDate time.Time) *Bundle {
+	return &Bundle{
+		Version:   formatVersion,
+		CreatedAt: time.Now(),
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Env:       make(map[string]string),
+		Files:     make(map[string][]byte),
+	}
+}
+
+// localEnv are settings of the machine rather than of the sources, which a bundle never carries (nor the retention
+// of this machine, RETENTION_MONTHS and RETENTION_MONTHS_<SOURCE>)
+var localEnv = map[string]bool{
+	"START_DATE": true, "END_DATE": true, PassphraseEnv: true,
+	"CACHE_PASSPHRASE": true, "CACHE_KEYCHAIN_SERVICE": true, "HTTP_CACHE_TTL_MINUTES": true,
+	"UPLOAD_TARGET": true, "OBSIDIAN_VAULT": true, "TEAM_STORE": true, "TEAM_MEMBER": true, "DEV_STATS_CHANNEL": true, "DEV_STATS_UPDATE_REPO": true,
+}
+
+// IsSecret reports whether a variable holds a credential (tokens, API keys, secrets, passwords), which a bundle
+// replaces with a placeholder. File paths such as GOOGLE_TOKEN_FILE are not secrets themselves.
+func IsSecret(name string) bool {
+	name = strings.ToUpper(name)
+	if strings.HasSuffix(name, "_FILE") || strings.HasSuffix(name, "_PATH") {
+		return false
+	}
+	for _, word := range []string{"TOKEN", "SECRET", "PASSWORD", "PASSPHRASE"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return strings.HasSuffix(name, "_KEY")
+}
+
+// Placeholder is the value a secret variable gets on the importing machine, so that analyzers requiring it run
+func Placeholder(name string) string {
+	return "bundle-redacted-" + name
+}
+
+// CaptureEnv stores the current values of the variables (those named in .env), leaving out machine settings and
+// replacing secrets with placeholders
+func (b *Bundle) CaptureEnv(names []string) {
+	for _, name := range names {
+		value := os.Getenv(name)
+		if value == "" || localEnv[name] || strings.HasPrefix(name, "RETENTION_MONTHS") {
+			continue
+		}
+		if IsSecret(name) {
+			value = Placeholder(name)
+		}
+		b.Env[name] = value
+	}
+}
+
+// ApplyEnv sets the variables of the bundle, which win over .env of this machine: the responses were recorded
+// for those settings
+func (b *Bundle) ApplyEnv() {
+	names := make([]string, 0, len(b.Env))
+	for name := range b.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		os.Setenv(name, b.Env[name])
+	}
+}
+
+// AddFile stores a file, decrypted when it was written with cache encryption
+func (b *Bundle) AddFile(path string) error {
+	data, err := common.ReadProtectedFile(path)
+	if err != nil {
+		return common.WrapError(err, "failed to read %s", path)
+	}
+	b.Files[filepath.ToSlash(filepath.Clean(path))] = data
+	return nil
+}
+
+// RestoreFiles writes the stored files into the working directory (encrypted when cache encryption is enabled
+// here) and returns their paths, sorted
+func (b *Bundle) RestoreFiles() ([]string, error) {
+	var paths []string
+	for name := range b.Files {
+		path := filepath.FromSlash(name)
+		if !filepath.IsLocal(path) {
+			return nil, common.NewError("bundle contains a file outside the working directory: %s", name)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, common.WrapError(err, "failed to create directory for %s", path)
+		}
+		if err := common.WriteProtectedFile(path, b.Files[filepath.ToSlash(path)]); err != nil {
+			return nil, common.WrapError(err, "failed to write %s", path)
+		}
+	}
+	return paths, nil
+}
+
+// Passphrase returns BUNDLE_PASSPHRASE, which must be given on both machines
+func Passphrase() (string, error) {
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		return "", common.NewError("%s is not set: bundles are always encrypted", PassphraseEnv)
+	}
+	return passphrase, nil
+}
+
+// Write saves the bundle as gzipped JSON encrypted with AES-256-GCM, readable only by the owner
+func Write(path string, b *Bundle, passphrase string) error {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if err := json.NewEncoder(gz).Encode(b); err != nil {
+		return common.WrapError(err, "failed to encode bundle")
+	}
+	if err := gz.Close(); err != nil {
+		return common.WrapError(err, "failed to compress bundle")
+	}
+	sealed, err := common.SealWithPassphrase(compressed.Bytes(), passphrase)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return common.WrapError(err, "failed to create directory for %s", path)
+		}
+	}
+	if err := os.WriteFile(path, sealed, 0600); err != nil {
+		return common.WrapError(err, "failed to write %s", path)
+	}
+	return nil
+}
+
+// Read loads a bundle written by Write
+func Read(path, passphrase string) (*Bundle, error) {
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to read %s", path)
+	}
+	compressed, err := common.OpenWithPassphrase(sealed, passphrase)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to open %s", path)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, common.WrapError(err, "failed to decompress %s", path)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to decompress %s", path)
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, common.WrapError(err, "failed to decode %s", path)
+	}
+	if b.Version != formatVersion {
+		return nil, common.NewError("%s is a version %d bundle; this build reads version %d", path, b.Version, formatVersion)
+	}
+	return &b, nil
+}
@@ -0,0 +1,226 @@
+package bundle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Exchange is one recorded API response with the request it answers
+type Exchange struct {
+	Key    string      `json:"key"` // requestKey of the redacted request
+	Method string      `json:"method"`
+	URL    string      `json:"url"` // with secrets replaced by {{NAME}}
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// minSecretLength keeps short values (a "1" or "true" in a variable named like a secret) from being replaced
+// everywhere they appear
+const minSecretLength = 8
+
+// redactor replaces the values of secret variables in URLs and request bodies with {{NAME}}. The importing machine
+// runs with placeholder values, which redact to the same text, so its requests find the recorded responses.
+type redactor struct {
+	replacer *strings.Replacer
+	scrubber *strings.Replacer // secret values to their placeholders, for recorded responses
+}
+
+// newRedactor reads the secret variables of the current environment
+func newRedactor() *redactor {
+	type secret struct{ name, value string }
+	var secrets []secret
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if IsSecret(name) && len(value) >= minSecretLength {
+			secrets = append(secrets, secret{name, value})
+		}
+	}
+	// Longer values first, so that a secret containing another one is replaced as a whole
+	sort.Slice(secrets, func(i, j int) bool {
+		if len(secrets[i].value) != len(secrets[j].value) {
+			return len(secrets[i].value) > len(secrets[j].value)
+		}
+		return secrets[i].name < secrets[j].name
+	})
+
+	var pairs, scrubs []string
+	for _, s := range secrets {
+		placeholder := "{{" + s.name + "}}"
+		pairs = append(pairs, s.value, placeholder)
+		scrubs = append(scrubs, s.value, Placeholder(s.name))
+		if escaped := url.QueryEscape(s.value); escaped != s.value {
+			pairs = append(pairs, escaped, placeholder)
+			scrubs = append(scrubs, escaped, Placeholder(s.name))
+		}
+	}
+	return &redactor{replacer: strings.NewReplacer(pairs...), scrubber: strings.NewReplacer(scrubs...)}
+}
+
+// scrub replaces secrets in a recorded response (pagination links that repeat an API key) with their placeholders
+func (r *redactor) scrub(header http.Header, body []byte) (http.Header, []byte) {
+	scrubbed := make(http.Header, len(header))
+	for name, values := range header {
+		if strings.EqualFold(name, "Set-Cookie") {
+			continue
+		}
+		for _, value := range values {
+			scrubbed.Add(name, r.scrubber.Replace(value))
+		}
+	}
+	return scrubbed, []byte(r.scrubber.Replace(string(body)))
+}
+
+// requestKey identifies a request by method, redacted URL, and the hash of its redacted body. Headers are left
+// out: they carry the credentials, which differ between the two machines.
+func (r *redactor) requestKey(req *http.Request, body []byte) (string, string) {
+	redactedURL := r.replacer.Replace(req.URL.String())
+	hash := sha256.Sum256([]byte(r.replacer.Replace(string(body))))
+	return req.Method + " " + redactedURL + " " + hex.EncodeToString(hash[:]), redactedURL
+}
+
+// readRequestBody reads the body of a request and puts it back for the next transport
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Recorder sends requests through the next transport and keeps every response, the last one per request
+// (a retried request is kept with the response that ended the retries)
+type Recorder struct {
+	next      http.RoundTripper
+	redactor  *redactor
+	exchanges []Exchange
+	index     map[string]int
+	mu        sync.Mutex // analyzers may send requests concurrently
+}
+
+// NewRecorder records the responses of next (http.DefaultTransport when nil)
+func NewRecorder(next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{next: next, redactor: newRedactor(), index: make(map[string]int)}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	header, scrubbedBody := r.redactor.scrub(resp.Header, responseBody)
+	key, redactedURL := r.redactor.requestKey(req, body)
+	exchange := Exchange{Key: key, Method: req.Method, URL: redactedURL, Status: resp.StatusCode, Header: header, Body: scrubbedBody}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, exists := r.index[key]; exists {
+		r.exchanges[i] = exchange
+	} else {
+		r.index[key] = len(r.exchanges)
+		r.exchanges = append(r.exchanges, exchange)
+	}
+	return resp, nil
+}
+
+// Exchanges returns the recorded responses in the order they were first requested
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Exchange{}, r.exchanges...)
+}
+
+// Replayer serves the responses of a bundle without network access. Requests that were not recorded get a 404,
+// so the analyzer reports them like any failed request, and are logged once each.
+type Replayer struct {
+	redactor  *redactor
+	exchanges map[string]Exchange
+	missing   []string // requests already logged
+	mu        sync.Mutex
+}
+
+// NewReplayer serves exchanges; call it after the placeholder credentials of the bundle are set
+func NewReplayer(exchanges []Exchange) *Replayer {
+	byKey := make(map[string]Exchange, len(exchanges))
+	for _, exchange := range exchanges {
+		byKey[exchange.Key] = exchange
+	}
+	return &Replayer{redactor: newRedactor(), exchanges: byKey}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key, redactedURL := r.redactor.requestKey(req, body)
+	if exchange, exists := r.exchanges[key]; exists {
+		return newResponse(req, exchange.Status, exchange.Header.Clone(), exchange.Body), nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	request := req.Method + " " + redactedURL
+	if !containsString(r.missing, request) {
+		r.missing = append(r.missing, request)
+		log.Printf("Warning: the bundle has no response for %s", request)
+	}
+	return newResponse(req, http.StatusNotFound, make(http.Header), []byte(`{"message":"not recorded in the bundle"}`)), nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func newResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
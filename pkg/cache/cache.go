@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/ingest"
+	"dev-stats/pkg/tasks"
+)
+
+// Source is one kind of cached or stored data managed by `dev-stats cache`
+type Source struct {
+	Name        string
+	Description string
+	Patterns    []string // glob patterns of files or directories
+	Store       bool     // persistent data that cannot be re-fetched; cleared only when named explicitly
+}
+
+// Sources returns every known cache location
+func Sources() []Source {
+	return []Source{
+		{Name: "backlog", Description: "Backlog projects and members per profile", Patterns: []string{".backlog-cache"}},
+		{Name: "github", Description: "GitHub repository metadata and closed PR details", Patterns: []string{".github-cache"}},
+		{Name: "notion", Description: "Notion relation and database titles", Patterns: []string{".notion-cache"}},
+		{Name: "http", Description: "API responses revalidated with ETag/Last-Modified", Patterns: []string{common.HTTPCacheDir}},
+		{Name: "raw", Description: "Fetched Calendar/Notion data with Notion relation titles (used by recategorize)", Patterns: []string{"output/*/raw"}},
+		{Name: "google", Description: "Google Workspace revision checks", Patterns: []string{"output/*/google/.cache"}},
+		{Name: "details", Description: "Detail lists split out of -stats.json and CSV exports of reports (-split-details, -output csv)", Patterns: []string{
+			"output/*/stats*/*-details.jsonl",
+			"output/*/stats*/*.csv",
+		}},
+		{Name: "store", Description: "History, achievements, exported tasks, and received webhook events", Store: true, Patterns: []string{
+			common.DefaultHistoryPath,
+			common.DefaultAchievementsPath,
+			tasks.DefaultExportLogPath,
+			ingest.DefaultDir,
+		}},
+	}
+}
+
+// Lookup returns the source with the given name
+func Lookup(name string) (Source, error) {
+	var names []string
+	for _, source := range Sources() {
+		if source.Name == name {
+			return source, nil
+		}
+		names = append(names, source.Name)
+	}
+	return Source{}, common.NewError("unknown cache '%s' (available: %s)", name, strings.Join(names, ", "))
+}
+
+// File is a cached file with its size and modification time
+type File struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Usage summarizes the files of a source
+type Usage struct {
+	Source Source
+	Files  []File
+	Bytes  int64
+	Oldest time.Time
+	Newest time.Time
+}
+
+// add counts a file into the usage
+func (u *Usage) add(file File) {
+	u.Files = append(u.Files, file)
+	u.Bytes += file.Size
+	if u.Oldest.IsZero() || file.ModTime.Before(u.Oldest) {
+		u.Oldest = file.ModTime
+	}
+	if file.ModTime.After(u.Newest) {
+		u.Newest = file.ModTime
+	}
+}
+
+// roots returns the existing paths matched by the source's patterns
+func (s Source) roots() []string {
+	var roots []string
+	for _, pattern := range s.Patterns {
+		matches, _ := filepath.Glob(pattern)
+		roots = append(roots, matches...)
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// Scan collects the files of the source
+func (s Source) Scan() (*Usage, error) {
+	usage := &Usage{Source: s}
+	for _, root := range s.roots() {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			usage.add(File{Path: path, Size: info.Size(), ModTime: info.ModTime()})
+			return nil
+		})
+		if err != nil {
+			return nil, common.WrapError(err, "failed to scan %s", root)
+		}
+	}
+	return usage, nil
+}
+
+// Clear removes the files of the source and returns what was removed
+func (s Source) Clear() (*Usage, error) {
+	usage, err := s.Scan()
+	if err != nil {
+		return nil, err
+	}
+	for _, root := range s.roots() {
+		if err := os.RemoveAll(root); err != nil {
+			return nil, common.WrapError(err, "failed to remove %s", root)
+		}
+	}
+	return usage, nil
+}
+
+// PrintStats prints per-source file counts, sizes, and ages
+func PrintStats(writer io.Writer, usages []*Usage, now time.Time) {
+	fmt.Fprintf(writer, "%-10s %6s %10s %10s %10s  %s\n", "Cache", "Files", "Size", "Oldest", "Newest", "Description")
+	fmt.Fprintln(writer, strings.Repeat("-", 60))
+
+	var totalFiles int
+	var totalBytes int64
+	for _, usage := range usages {
+		fmt.Fprintf(writer, "%-10s %6d %10s %10s %10s  %s\n",
+			usage.Source.Name, len(usage.Files), FormatBytes(usage.Bytes),
+			formatAge(usage.Oldest, now), formatAge(usage.Newest, now), usage.Source.Description)
+		totalFiles += len(usage.Files)
+		totalBytes += usage.Bytes
+	}
+	fmt.Fprintln(writer, strings.Repeat("-", 60))
+	fmt.Fprintf(writer, "%-10s %6d %10s\n", "Total", totalFiles, FormatBytes(totalBytes))
+}
+
+// PrintList prints every cached file grouped by source
+func PrintList(writer io.Writer, usages []*Usage, now time.Time) {
+	for _, usage := range usages {
+		fmt.Fprintf(writer, "\n%s (%d files, %s) - %s\n",
+			usage.Source.Name, len(usage.Files), FormatBytes(usage.Bytes), usage.Source.Description)
+		if len(usage.Files) == 0 {
+			fmt.Fprintln(writer, "  (empty)")
+			continue
+		}
+		for _, file := range usage.Files {
+			fmt.Fprintf(writer, "  %10s %10s  %s\n", FormatBytes(file.Size), formatAge(file.ModTime, now), file.Path)
+		}
+	}
+}
+
+// FormatBytes formats a size with a binary unit (e.g. 1.5 KiB)
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TiB", value)
+}
+
+// formatAge formats how long ago t was (e.g. 3h, 12d)
+func formatAge(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	age := now.Sub(t)
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// RetentionEnv sets how many months cached and detailed data is kept; RETENTION_MONTHS_<SOURCE> (e.g.
+// RETENTION_MONTHS_RAW) overrides it per source. Reports, JSON stats, and the store are never pruned, so that
+// summary metrics and history stay available for trends.
+const RetentionEnv = "RETENTION_MONTHS"
+
+// periodDir matches the period directory of a file under output/, whose data is as old as the period's end date
+var periodDir = regexp.MustCompile(`(?:^|/)output/\d{4}-\d{2}-\d{2}_to_(\d{4}-\d{2}-\d{2})/`)
+
+// Retention returns the months the source's files are kept (0: kept forever). The persistent store is never pruned.
+func (s Source) Retention() (int, error) {
+	if s.Store {
+		return 0, nil
+	}
+	for _, name := range []string{RetentionEnv + "_" + strings.ToUpper(s.Name), RetentionEnv} {
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			continue
+		}
+		months, err := strconv.Atoi(value)
+		if err != nil || months < 0 {
+			return 0, common.NewError("%s must be a number of months (0 keeps data forever), got %q", name, value)
+		}
+		return months, nil
+	}
+	return 0, nil
+}
+
+// RetentionConfigured reports whether any retention variable is set, so that runs prune automatically
+func RetentionConfigured() bool {
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if (name == RetentionEnv || strings.HasPrefix(name, RetentionEnv+"_")) && strings.TrimSpace(value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// dataTime returns when a file's data is from: the end of its period for files under output/<period>/, else the
+// time it was written
+func dataTime(file File) time.Time {
+	if match := periodDir.FindStringSubmatch(filepath.ToSlash(file.Path)); match != nil {
+		if end, err := time.ParseInLocation("2006-01-02", match[1], time.Local); err == nil {
+			return end.AddDate(0, 0, 1)
+		}
+	}
+	return file.ModTime
+}
+
+// Prune removes the files of the source older than its retention and returns what was removed; nothing is removed
+// without a retention
+func (s Source) Prune(now time.Time) (*Usage, error) {
+	removed := &Usage{Source: s}
+	months, err := s.Retention()
+	if err != nil || months == 0 {
+		return removed, err
+	}
+	usage, err := s.Scan()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := now.AddDate(0, -months, 0)
+	dirs := make(map[string]bool)
+	for _, file := range usage.Files {
+		if !dataTime(file).Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(file.Path); err != nil {
+			return nil, common.WrapError(err, "failed to remove %s", file.Path)
+		}
+		removed.add(file)
+		dirs[filepath.Dir(file.Path)] = true
+	}
+	removeEmptyDirs(dirs)
+	return removed, nil
+}
+
+// removeEmptyDirs removes directories left empty by pruning, deepest first, and their parents that become empty
+// (output/ itself stays)
+func removeEmptyDirs(dirs map[string]bool) {
+	var paths []string
+	for dir := range dirs {
+		for ; dir != "." && dir != "output" && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+			paths = append(paths, dir)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool { return len(paths[i]) > len(paths[j]) })
+	for _, dir := range paths {
+		os.Remove(dir) // fails while the directory still has files, which is intended
+	}
+}
@@ -1,7 +1,6 @@
 package calendar
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -18,17 +17,30 @@ import (
 // CalendarAnalyzer implements the Analyzer interface for Calendar
 type CalendarAnalyzer struct {
 	calendarDir    string
+	source         string // CALENDAR_SOURCE: auto, ics, or api
 	categoryConfig *config.CategorizationConfig
+	overrides      *config.Overrides
+	ignoreList     config.IgnoreList
+	location       *time.Location // display timezone (CALENDAR_TIMEZONE) events are converted into
+	emails         []string       // my addresses (CALENDAR_EMAIL), for organized vs attended meetings
+	acceptedOnly   bool           // CALENDAR_ACCEPTED_ONLY: count only events I organized or accepted
+	excluded       []string       // CALENDAR_EXCLUDE: calendars left out of the totals, lowercased
+	cachedEvents   []Event        // Events collected by the last run, reused while the date range is unchanged
+	cachedRange    string
+	warnings       common.Warnings // optional sources that failed while collecting the cached events
 }
 
 // Event represents a calendar event
 type Event struct {
-	UID      string
-	Summary  string
-	Start    time.Time
-	End      time.Time
-	Created  time.Time
-	IsAllDay bool
+	UID       string
+	Summary   string
+	Start     time.Time
+	End       time.Time
+	Created   time.Time
+	IsAllDay  bool
+	Organizer string // email
+	Attendees []Attendee
+	Calendar  string // X-WR-CALNAME or file name of the ICS file, or the name of the API calendar
 }
 
 // TitleStats represents statistics for events by title
@@ -45,13 +57,16 @@ type EventCategoryStats struct {
 	FocusTime    time.Duration            `json:"focus_time"`
 	LearningTime time.Duration            `json:"learning_time"`
 	AdminTime    time.Duration            `json:"admin_time"`
+	// MainCategoryEvents lists the events counted in each main category time (for -explain)
+	MainCategoryEvents map[string][]Event `json:"-"`
 }
 
 // CategoryInfo contains details about a specific category
 type CategoryInfo struct {
-	Count    int           `json:"count"`
-	Duration time.Duration `json:"duration"`
-	Events   []Event       `json:"events"`
+	Count       int           `json:"count"`
+	Duration    time.Duration `json:"duration"`
+	MeetingTime time.Duration `json:"meeting_time"` // the part of Duration counted as meeting time
+	Events      []Event       `json:"events"`
 }
 
 // WorkingHoursStats represents analysis of working hours patterns
@@ -62,21 +77,56 @@ type WorkingHoursStats struct {
 	TotalWorkingHours  time.Duration            `json:"total_working_hours"`
 }
 
+// Calendar sources (CALENDAR_SOURCE): every configured one, only ICS files (storage/calendar/ and Google Takeout),
+// or only the Google Calendar API
+const (
+	sourceAuto = "auto"
+	sourceICS  = "ics"
+	sourceAPI  = "api"
+)
+
 // NewCalendarAnalyzer creates a new Calendar analyzer
-func NewCalendarAnalyzer() *CalendarAnalyzer {
+func NewCalendarAnalyzer() (*CalendarAnalyzer, error) {
 	// Load category configuration
 	categoryConfig, err := config.LoadCategorizationConfig("")
 	if err != nil {
-		// Return nil to indicate initialization failure
-		// The caller should handle this error
-		fmt.Printf("Error: Failed to load category config: %v\n", err)
-		return nil
+		return nil, common.WrapError(err, "failed to load category config")
+	}
+
+	overrides, err := config.LoadOverrides("", categoryConfig)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to load overrides")
+	}
+
+	location, err := displayLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	source := strings.ToLower(strings.TrimSpace(os.Getenv("CALENDAR_SOURCE")))
+	if source == "" {
+		source = sourceAuto
+	}
+	if source != sourceAuto && source != sourceICS && source != sourceAPI {
+		return nil, common.NewError("unknown CALENDAR_SOURCE %q (expected %s, %s, or %s)", source, sourceAuto, sourceICS, sourceAPI)
+	}
+
+	emails := calendarEmails()
+	acceptedOnly := os.Getenv("CALENDAR_ACCEPTED_ONLY") == "true"
+	if acceptedOnly && len(emails) == 0 {
+		return nil, common.NewError("CALENDAR_ACCEPTED_ONLY requires CALENDAR_EMAIL (your address as it appears in invitations)")
 	}
 
 	return &CalendarAnalyzer{
 		calendarDir:    "storage/calendar",
+		source:         source,
 		categoryConfig: categoryConfig,
-	}
+		overrides:      overrides,
+		location:       location,
+		emails:         emails,
+		acceptedOnly:   acceptedOnly,
+		excluded:       excludedCalendars(),
+	}, nil
 }
 
 // GetName returns the analyzer name
@@ -84,68 +134,86 @@ func (c *CalendarAnalyzer) GetName() string {
 	return "Calendar"
 }
 
+// SetCategoryConfig replaces the categorization rules used by subsequent runs
+func (c *CalendarAnalyzer) SetCategoryConfig(categoryConfig *config.CategorizationConfig) {
+	c.categoryConfig = categoryConfig
+}
+
+// SetOverrides replaces the manual overrides used by subsequent runs
+func (c *CalendarAnalyzer) SetOverrides(overrides *config.Overrides) {
+	c.overrides = overrides
+}
+
+// SaveRawData stores the events collected by the last run so they can be recategorized later
+func (c *CalendarAnalyzer) SaveRawData(path string) error {
+	if c.cachedRange == "" {
+		return common.NewError("no calendar events have been loaded")
+	}
+	return common.WriteJSONFile(path, c.cachedEvents)
+}
+
+// LoadRawData loads stored events; runs for the same period use them instead of reading sources
+func (c *CalendarAnalyzer) LoadRawData(path string, config *common.Config) error {
+	var events []Event
+	if err := common.ReadJSONFile(path, &events); err != nil {
+		return err
+	}
+	c.cachedEvents = events
+	c.cachedRange = config.PeriodLabel()
+	return nil
+}
+
 // ValidateConfig validates the required configuration.
-// Passes if either storage/calendar/ exists or GOOGLE_CLIENT_ID is set.
+// Passes if storage/calendar/ exists, GOOGLE_TAKEOUT_PATH is set, or GOOGLE_CLIENT_ID is set, among the sources
+// CALENDAR_SOURCE selects. The API alone needs GOOGLE_CLIENT_SECRET too.
 func (c *CalendarAnalyzer) ValidateConfig() error {
+	if c.source == sourceAPI {
+		if os.Getenv("GOOGLE_CLIENT_ID") == "" || os.Getenv("GOOGLE_CLIENT_SECRET") == "" {
+			return common.NewError("CALENDAR_SOURCE=api requires GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET")
+		}
+		return nil
+	}
 	hasICS := false
 	if _, err := os.Stat(c.calendarDir); err == nil {
 		hasICS = true
 	}
-	hasAPI := os.Getenv("GOOGLE_CLIENT_ID") != ""
-	if !hasICS && !hasAPI {
-		return common.NewError("no calendar source: set GOOGLE_CLIENT_ID or place ICS files in '%s'", c.calendarDir)
+	hasTakeout := googlecal.TakeoutPathFromEnv() != ""
+	hasAPI := c.source == sourceAuto && os.Getenv("GOOGLE_CLIENT_ID") != ""
+	if c.source == sourceICS && !hasICS && !hasTakeout {
+		return common.NewError("CALENDAR_SOURCE=ics but no ICS files: set GOOGLE_TAKEOUT_PATH or place them in '%s'", c.calendarDir)
+	}
+	if !hasICS && !hasTakeout && !hasAPI {
+		return common.NewError("no calendar source: set GOOGLE_CLIENT_ID or GOOGLE_TAKEOUT_PATH, or place ICS files in '%s'", c.calendarDir)
 	}
 	return nil
 }
 
 // Analyze performs Calendar analysis
 func (c *CalendarAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
-	if err := c.ValidateConfig(); err != nil {
+	if err := c.ignoreList.Reload(); err != nil {
 		return nil, err
 	}
 
-	// Collect events from ICS files and/or Google Calendar API
-	seen := make(map[string]bool)
 	var allEvents []Event
-
-	if _, err := os.Stat(c.calendarDir); err == nil {
-		fmt.Fprintf(writer, "Analyzing calendar events from directory: %s\n", c.calendarDir)
-		icsEvents, err := c.readAllICSFiles(writer)
-		if err != nil {
-			return nil, common.WrapError(err, "failed to read ICS files")
-		}
-		for _, e := range icsEvents {
-			allEvents = append(allEvents, e)
-			if e.UID != "" {
-				seen[e.UID] = true
-			}
-		}
-	}
-
-	if os.Getenv("GOOGLE_CLIENT_ID") != "" {
-		fmt.Fprintln(writer, "Fetching events from Google Calendar API...")
-		apiEvents, err := googlecal.FetchCalendarEvents(config.StartDate, config.EndDate, writer)
+	if c.cachedRange == config.PeriodLabel() {
+		fmt.Fprintf(writer, "Reusing %d calendar events loaded earlier\n", len(c.cachedEvents))
+		allEvents = c.cachedEvents
+	} else {
+		c.warnings.Reset()
+		events, err := c.collectEvents(config, writer)
 		if err != nil {
-			fmt.Fprintf(writer, "Warning: failed to fetch from Google Calendar API: %v\n", err)
-		} else {
-			for _, ae := range apiEvents {
-				if seen[ae.ID] {
-					continue
-				}
-				seen[ae.ID] = true
-				allEvents = append(allEvents, Event{
-					UID:      ae.ID,
-					Summary:  ae.Summary,
-					Start:    ae.Start,
-					End:      ae.End,
-					IsAllDay: ae.IsAllDay,
-				})
-			}
+			return nil, err
 		}
+		allEvents = events
+		c.cachedEvents = events
+		c.cachedRange = config.PeriodLabel()
 	}
 
-	// Filter events by date range
-	filteredEvents := c.filterEventsByDateRange(allEvents, config.StartDate, config.EndDate)
+	// Filter events by date range, ignore list, and (CALENDAR_ACCEPTED_ONLY) my response; calendars are summarized
+	// before CALENDAR_EXCLUDE leaves some out of the totals
+	filteredEvents := c.filterEventsByDateRange(c.filterAccepted(writer, c.filterIgnored(writer, allEvents)), config.StartDate, config.EndDate)
+	calendarStats := c.analyzeCalendars(filteredEvents)
+	filteredEvents = c.filterExcluded(writer, filteredEvents)
 
 	// Sort events by start time
 	sort.Slice(filteredEvents, func(i, j int) bool {
@@ -161,23 +229,24 @@ func (c *CalendarAnalyzer) Analyze(config *common.Config, writer io.Writer) (*co
 	// Enhanced analysis
 	categoryStats := c.analyzeCategoryStats(filteredEvents)
 	workingHoursStats := c.analyzeWorkingHours(filteredEvents)
+	participationStats := c.analyzeParticipation(filteredEvents)
 
 	// Create result
 	result := &common.AnalysisResult{
 		AnalyzerName: c.GetName(),
 		StartDate:    config.StartDate,
 		EndDate:      config.EndDate,
-		Summary: map[string]interface{}{
-			"Total events":        len(filteredEvents),
-			"Total duration":      totalDuration,
-			"Event titles":        len(groupedByTitle),
-			"All-day events":      len(allDayStats),
-			"Meeting time":        categoryStats.MeetingTime,
-			"Focus time":          categoryStats.FocusTime,
-			"Learning time":       categoryStats.LearningTime,
-			"Admin time":          categoryStats.AdminTime,
-			"Total working hours": workingHoursStats.TotalWorkingHours,
-			"Event categories":    len(categoryStats.Categories),
+		Metrics: []common.Metric{
+			{ID: "calendar.events_total", Label: "Total events", Value: len(filteredEvents)},
+			{ID: "calendar.event_hours", Label: "Total duration", Value: totalDuration},
+			{ID: "calendar.event_titles", Label: "Event titles", Value: len(groupedByTitle), Snapshot: true},
+			{ID: "calendar.all_day_events", Label: "All-day events", Value: len(allDayStats)},
+			{ID: "calendar.meeting_hours", Label: "Meeting time", Value: categoryStats.MeetingTime},
+			{ID: "calendar.focus_hours", Label: "Focus time", Value: categoryStats.FocusTime},
+			{ID: "calendar.learning_hours", Label: "Learning time", Value: categoryStats.LearningTime},
+			{ID: "calendar.admin_hours", Label: "Admin time", Value: categoryStats.AdminTime},
+			{ID: "calendar.working_hours", Label: "Total working hours", Value: workingHoursStats.TotalWorkingHours},
+			{ID: "calendar.event_categories", Label: "Event categories", Value: len(categoryStats.Categories), Snapshot: true},
 		},
 		Details: map[string]interface{}{
 			"events":         filteredEvents,
@@ -185,29 +254,155 @@ func (c *CalendarAnalyzer) Analyze(config *common.Config, writer io.Writer) (*co
 			"all_day_stats":  allDayStats,
 			"category_stats": categoryStats,
 			"working_hours":  workingHoursStats,
+			"participation":  participationStats,
+			"calendars":      calendarStats,
 		},
+		Activities: c.buildActivities(filteredEvents),
+		Warnings:   c.warnings.List(),
+	}
+	result.Metrics = append(result.Metrics, participationMetrics(participationStats)...)
+	result.CSVTables = c.csvTables(filteredEvents, result.Activities)
+	result.Explain("calendar.events_total", result.Activities)
+	result.Explain("calendar.event_hours", result.Activities)
+	for id, mainCategory := range map[string]string{
+		"calendar.meeting_hours":  "meeting",
+		"calendar.focus_hours":    "focus",
+		"calendar.learning_hours": "learning",
+		"calendar.admin_hours":    "admin",
+	} {
+		result.Explain(id, c.buildActivities(categoryStats.MainCategoryEvents[mainCategory]))
+	}
+	if participationStats.Identified {
+		result.Explain("calendar.meetings_organized", c.buildActivities(participationStats.Organized))
+		result.Explain("calendar.meetings_attended", c.buildActivities(participationStats.Attended))
 	}
 
 	c.printResults(writer, result, filteredEvents, titleStats, allDayStats, categoryStats, workingHoursStats)
+	c.printParticipation(writer, participationStats)
+	c.printCalendars(writer, calendarStats)
 	return result, nil
 }
 
-func (c *CalendarAnalyzer) readAllICSFiles(writer io.Writer) ([]Event, error) {
+// filterIgnored drops events whose UID is listed in the ignore file
+func (c *CalendarAnalyzer) filterIgnored(writer io.Writer, events []Event) []Event {
+	return config.FilterIgnored(writer, &c.ignoreList, events, "events", func(event Event) []string {
+		return []string{event.UID}
+	})
+}
+
+// collectEvents gathers events from ICS files and/or the Google Calendar API, as CALENDAR_SOURCE selects
+func (c *CalendarAnalyzer) collectEvents(config *common.Config, writer io.Writer) ([]Event, error) {
+	if err := c.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
 	var allEvents []Event
 
+	if c.source == sourceAPI {
+		fmt.Fprintln(writer, "Reading events only from the Google Calendar API (CALENDAR_SOURCE=api)")
+	} else if _, err := os.Stat(c.calendarDir); err == nil {
+		fmt.Fprintf(writer, "Analyzing calendar events from directory: %s\n", c.calendarDir)
+		icsEvents, err := c.readAllICSFiles(writer, config.StartDate, config.EndDate)
+		if err != nil {
+			return nil, common.WrapError(err, "failed to read ICS files")
+		}
+		for _, e := range icsEvents {
+			allEvents = append(allEvents, e)
+			if e.UID != "" {
+				seen[e.UID] = true
+			}
+		}
+	}
+
+	if path := googlecal.TakeoutPathFromEnv(); path != "" && c.source != sourceAPI {
+		fmt.Fprintf(writer, "Analyzing calendar events from Google Takeout: %s\n", path)
+		takeoutEvents, err := c.readTakeoutICS(writer, googlecal.NewTakeout(path), config.StartDate, config.EndDate)
+		if err != nil {
+			return nil, common.WrapError(err, "failed to read the Google Takeout calendars")
+		}
+		for _, e := range takeoutEvents {
+			if e.UID != "" && seen[e.UID] {
+				continue
+			}
+			seen[e.UID] = e.UID != ""
+			allEvents = append(allEvents, e)
+		}
+	}
+
+	if os.Getenv("GOOGLE_CLIENT_ID") != "" && c.source != sourceICS {
+		fmt.Fprintln(writer, "Fetching events from Google Calendar API...")
+		apiEvents, err := googlecal.FetchCalendarEvents(config.StartDate, config.EndDate, googlecal.CalendarIDsFromEnv(), writer)
+		if err != nil && c.source == sourceAPI {
+			return nil, common.WrapError(err, "failed to fetch events from the Google Calendar API")
+		} else if err != nil {
+			c.warnings.Add("Google Calendar API events", "", err)
+		} else {
+			for _, ae := range apiEvents {
+				// Events exported to ICS files as well carry the same UID (the iCalUID), once per series
+				if seen[ae.ID] || (ae.ICalUID != "" && seen[ae.ICalUID]) {
+					continue
+				}
+				seen[ae.ID] = true
+				allEvents = append(allEvents, c.inDisplayZone(Event{
+					UID:       ae.ID,
+					Summary:   ae.Summary,
+					Start:     ae.Start,
+					End:       ae.End,
+					IsAllDay:  ae.IsAllDay,
+					Organizer: strings.ToLower(ae.Organizer),
+					Attendees: apiAttendees(ae.Attendees),
+					Calendar:  ae.Calendar,
+				}))
+			}
+		}
+	}
+
+	return allEvents, nil
+}
+
+// buildActivities converts events into dated activities; all-day events carry no duration
+func (c *CalendarAnalyzer) buildActivities(events []Event) []common.Activity {
+	var activities []common.Activity
+	for _, event := range events {
+		var duration time.Duration
+		if !c.isAllDayEvent(event) && !event.End.IsZero() && event.End.After(event.Start) {
+			duration = event.End.Sub(event.Start)
+		}
+		activities = append(activities, common.Activity{
+			Source:   c.GetName(),
+			Kind:     common.ActivityKindEvent,
+			ID:       event.UID,
+			Title:    event.Summary,
+			Time:     event.Start,
+			Duration: duration,
+			Category: c.categoryConfig.CategorizeByKeywords(strings.ToLower(event.Summary)),
+		})
+	}
+	c.overrides.Apply(activities)
+	return activities
+}
+
+// readAllICSFiles reads every .ics file, keeping only events in the date range so that exports spanning years
+// of history don't have to fit in memory
+func (c *CalendarAnalyzer) readAllICSFiles(writer io.Writer, startDate, endDate time.Time) ([]Event, error) {
+	var allEvents []Event
+	totalParsed := 0
+
 	err := filepath.Walk(c.calendarDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if strings.HasSuffix(strings.ToLower(info.Name()), ".ics") {
 			fmt.Fprintf(writer, "Reading calendar file: %s\n", path)
-			events, err := c.parseICSFile(path)
+			events, parsed, err := c.parseICSFile(path, startDate, endDate)
 			if err != nil {
 				fmt.Fprintf(writer, "Error parsing ICS file %s: %v\n", path, err)
 				fmt.Fprintf(writer, "Continuing with other files...\n")
 				return nil
 			}
-			fmt.Fprintf(writer, "Successfully parsed %d events from %s\n", len(events), path)
+			fmt.Fprintf(writer, "Successfully parsed %d events from %s (%d in date range)\n", parsed, path, len(events))
+			totalParsed += parsed
 			allEvents = append(allEvents, events...)
 		}
 		return nil
@@ -217,71 +412,210 @@ func (c *CalendarAnalyzer) readAllICSFiles(writer io.Writer) ([]Event, error) {
 		return nil, err
 	}
 
-	fmt.Fprintf(writer, "\nTotal events parsed from all files: %d\n", len(allEvents))
+	fmt.Fprintf(writer, "\nTotal events parsed from all files: %d (%d in date range)\n", totalParsed, len(allEvents))
 	return allEvents, nil
 }
 
-func (c *CalendarAnalyzer) parseICSFile(filePath string) ([]Event, error) {
+// readTakeoutICS reads the Calendar/*.ics files of a Google Takeout download, keeping events in the date range
+func (c *CalendarAnalyzer) readTakeoutICS(writer io.Writer, takeout *googlecal.Takeout, startDate, endDate time.Time) ([]Event, error) {
+	var allEvents []Event
+	err := takeout.Walk(func(kind string) bool {
+		return kind == googlecal.TakeoutCalendar
+	}, func(name, kind string, r io.Reader) error {
+		events, parsed, err := c.parseICS(r, fileCalendarName(name), startDate, endDate)
+		if err != nil {
+			fmt.Fprintf(writer, "Error parsing ICS file %s: %v\n", name, err)
+			return nil
+		}
+		fmt.Fprintf(writer, "Successfully parsed %d events from %s (%d in date range)\n", parsed, name, len(events))
+		allEvents = append(allEvents, events...)
+		return nil
+	})
+	return allEvents, err
+}
+
+// parseICSFile parses the file line by line and returns the events in the date range, with the number of events parsed
+func (c *CalendarAnalyzer) parseICSFile(filePath string, startDate, endDate time.Time) ([]Event, int, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer file.Close()
+	return c.parseICS(file, fileCalendarName(filePath), startDate, endDate)
+}
 
+// parseICS parses ICS content line by line and returns the events in the date range, with the number of events
+// parsed. Recurring events (RRULE) are expanded into their occurrences in the range once the whole content is
+// read, leaving out EXDATEs and the occurrences that RECURRENCE-ID overrides replace; each occurrence gets the
+// UID <UID>_<original start>. Times with a TZID are read in that zone (a VTIMEZONE of the file, or the IANA zone
+// of that name) and every event is converted into the display timezone (CALENDAR_TIMEZONE). Events belong to the
+// calendar named by X-WR-CALNAME, else to calendarName.
+func (c *CalendarAnalyzer) parseICS(r io.Reader, calendarName string, startDate, endDate time.Time) ([]Event, int, error) {
 	var events []Event
+	parsed := 0
 	var currentEvent Event
+	var startZone *icsZone
+	var rrule string
+	var recurrenceID time.Time
+	var exdates []time.Time
 	inEvent := false
+	var recurring []*recurringEvent
+	overridden := make(map[string]map[string]bool) // UID → occurrenceKey of the occurrences with an override
+	zones := make(map[string]*icsZone)             // TZID → VTIMEZONE defined in the content
+	var timezone *vtimezoneParser
+	nested := 0 // depth of components inside the event (VALARM), whose SUMMARY and DESCRIPTION are not the event's
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		if line == "BEGIN:VEVENT" {
-			inEvent = true
+	lines, err := contentLines(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if line == "BEGIN:VTIMEZONE" {
+			timezone = &vtimezoneParser{}
+		} else if timezone != nil {
+			if line == "END:VTIMEZONE" {
+				if zone := timezone.zone(); zone != nil && timezone.tzid != "" {
+					zones[timezone.tzid] = zone
+				}
+				timezone = nil
+			} else {
+				name, params, value := icsProperty(line)
+				timezone.line(c, name, params, value)
+			}
+		} else if !inEvent && strings.HasPrefix(strings.ToUpper(line), "X-WR-CALNAME") {
+			if name, params, value := icsProperty(line); name == "X-WR-CALNAME" && strings.TrimSpace(value) != "" {
+				calendarName = strings.TrimSpace(propertyText(params, value))
+			}
+		} else if line == "BEGIN:VEVENT" {
+			inEvent, nested = true, 0
 			currentEvent = Event{}
+			startZone, rrule, recurrenceID, exdates = nil, "", time.Time{}, nil
 		} else if line == "END:VEVENT" {
 			if inEvent {
-				events = append(events, currentEvent)
+				parsed++
+				if event := c.recurringEvent(currentEvent, startZone, rrule, exdates); event != nil {
+					recurring = append(recurring, event)
+				} else {
+					if !recurrenceID.IsZero() {
+						// An override of one occurrence: it replaces the occurrence generated from the rule
+						key := occurrenceKey(recurrenceID, currentEvent.IsAllDay)
+						if overridden[currentEvent.UID] == nil {
+							overridden[currentEvent.UID] = make(map[string]bool)
+						}
+						overridden[currentEvent.UID][key] = true
+						currentEvent.UID = occurrenceUID(currentEvent.UID, key)
+					}
+					if event := c.inDisplayZone(currentEvent); c.inDateRange(event, startDate, endDate) {
+						events = append(events, event)
+					}
+				}
 			}
 			inEvent = false
-		} else if inEvent {
-			if strings.HasPrefix(line, "UID:") {
-				currentEvent.UID = strings.TrimPrefix(line, "UID:")
-			} else if strings.HasPrefix(line, "SUMMARY:") {
-				currentEvent.Summary = strings.TrimPrefix(line, "SUMMARY:")
-			} else if strings.HasPrefix(line, "DTSTART") {
-				dtStart := c.extractDateTime(line)
-				if strings.Contains(line, "VALUE=DATE") {
+		} else if inEvent && strings.HasPrefix(line, "BEGIN:") {
+			nested++
+		} else if inEvent && strings.HasPrefix(line, "END:") {
+			nested--
+		} else if inEvent && nested == 0 {
+			name, params, value := icsProperty(line)
+			switch name {
+			case "RRULE":
+				rrule = value
+			case "EXDATE":
+				for _, exdate := range strings.Split(value, ",") {
+					if t, _, err := c.parseICSTime(params, strings.TrimSpace(exdate), zones); err == nil {
+						exdates = append(exdates, t)
+					}
+				}
+			case "RECURRENCE-ID":
+				if t, _, err := c.parseICSTime(params, value, zones); err == nil {
+					recurrenceID = t
+				}
+			case "UID":
+				currentEvent.UID = value
+			case "SUMMARY":
+				// Titles are grouped and printed one per line
+				currentEvent.Summary = strings.TrimSpace(strings.ReplaceAll(propertyText(params, value), "\n", " "))
+			case "DTSTART":
+				if params["VALUE"] == "DATE" {
 					currentEvent.IsAllDay = true
 				}
-				if t, err := c.parseDateTime(dtStart); err == nil {
-					currentEvent.Start = t
+				if t, zone, err := c.parseICSTime(params, value, zones); err == nil {
+					currentEvent.Start, startZone = t, zone
 				}
-			} else if strings.HasPrefix(line, "DTEND") {
-				dtEnd := c.extractDateTime(line)
-				if t, err := c.parseDateTime(dtEnd); err == nil {
+			case "DTEND":
+				if t, _, err := c.parseICSTime(params, value, zones); err == nil {
 					currentEvent.End = t
 				}
-			} else if strings.HasPrefix(line, "CREATED:") {
-				created := strings.TrimPrefix(line, "CREATED:")
-				if t, err := c.parseDateTime(created); err == nil {
+			case "ORGANIZER":
+				currentEvent.Organizer = calendarAddress(value)
+			case "ATTENDEE":
+				currentEvent.Attendees = append(currentEvent.Attendees, icsAttendee(params, value))
+			case "CREATED":
+				if t, err := c.parseDateTime(value); err == nil {
 					currentEvent.Created = t
 				}
 			}
 		}
 	}
 
-	return events, scanner.Err()
+	for _, event := range recurring {
+		for _, occurrence := range event.expand(overridden[event.event.UID], endDate) {
+			if occurrence = c.inDisplayZone(occurrence); c.inDateRange(occurrence, startDate, endDate) {
+				events = append(events, occurrence)
+			}
+		}
+	}
+	for i := range events {
+		events[i].Calendar = calendarName
+	}
+	return events, parsed, nil
 }
 
-func (c *CalendarAnalyzer) extractDateTime(line string) string {
-	colonIndex := strings.LastIndex(line, ":")
-	if colonIndex != -1 {
-		return line[colonIndex+1:]
+// recurringEvent returns the event with its parsed RRULE and EXDATEs, or nil when it doesn't recur or the rule
+// can't be read (the event then counts once, at DTSTART)
+func (c *CalendarAnalyzer) recurringEvent(event Event, zone *icsZone, rrule string, exdates []time.Time) *recurringEvent {
+	if rrule == "" || event.Start.IsZero() {
+		return nil
+	}
+	rule, err := c.parseRecurrenceRule(rrule)
+	if err != nil {
+		return nil
 	}
-	return ""
+	recurring := &recurringEvent{event: event, zone: zone, rule: rule, exdates: make(map[string]bool)}
+	for _, exdate := range exdates {
+		recurring.exdates[occurrenceKey(exdate, event.IsAllDay)] = true
+	}
+	return recurring
+}
+
+// inDisplayZone returns the event with its times in the display timezone
+func (c *CalendarAnalyzer) inDisplayZone(event Event) Event {
+	if !event.Start.IsZero() {
+		event.Start = event.Start.In(c.location)
+	}
+	if !event.End.IsZero() {
+		event.End = event.End.In(c.location)
+	}
+	return event
 }
 
+// parseICSTime parses a DATE or DATE-TIME value: in UTC (Z suffix), in the zone of its TZID parameter, or floating
+// (read in the display timezone). It also returns the zone of the TZID, if known.
+func (c *CalendarAnalyzer) parseICSTime(params map[string]string, value string, zones map[string]*icsZone) (time.Time, *icsZone, error) {
+	t, err := c.parseDateTime(value)
+	if err != nil || strings.HasSuffix(value, "Z") || len(value) == 8 {
+		return t, nil, err
+	}
+	zone := resolveZone(params["TZID"], zones)
+	if zone == nil {
+		return t, nil, nil
+	}
+	return zone.at(t), zone, nil
+}
+
+// parseDateTime parses a value without parameters; times without the Z suffix and dates are in the display timezone
 func (c *CalendarAnalyzer) parseDateTime(dtStr string) (time.Time, error) {
 	if dtStr == "" {
 		return time.Time{}, fmt.Errorf("empty datetime string")
@@ -293,11 +627,11 @@ func (c *CalendarAnalyzer) parseDateTime(dtStr string) (time.Time, error) {
 	}
 	// Try without timezone
 	if len(dtStr) >= 15 && strings.Contains(dtStr, "T") {
-		return time.Parse("20060102T150405", dtStr)
+		return time.ParseInLocation("20060102T150405", dtStr, c.location)
 	}
 	// Date only format: YYYYMMDD
 	if len(dtStr) == 8 {
-		return time.Parse("20060102", dtStr)
+		return time.ParseInLocation("20060102", dtStr, c.location)
 	}
 	return time.Time{}, fmt.Errorf("unsupported datetime format: '%s'", dtStr)
 }
@@ -305,14 +639,22 @@ func (c *CalendarAnalyzer) parseDateTime(dtStr string) (time.Time, error) {
 func (c *CalendarAnalyzer) filterEventsByDateRange(events []Event, startDate, endDate time.Time) []Event {
 	var filtered []Event
 	for _, event := range events {
-		// Include events on or after startDate and before or on endDate
-		if !event.Start.IsZero() && !event.Start.Before(startDate) && event.Start.Before(endDate.AddDate(0, 0, 1)) {
+		if c.inDateRange(event, startDate, endDate) {
 			filtered = append(filtered, event)
 		}
 	}
 	return filtered
 }
 
+// inDateRange reports whether the event starts on a day from startDate to endDate in the display timezone
+func (c *CalendarAnalyzer) inDateRange(event Event, startDate, endDate time.Time) bool {
+	if event.Start.IsZero() {
+		return false
+	}
+	day := event.Start.In(c.location).Format("2006-01-02")
+	return day >= startDate.Format("2006-01-02") && day <= endDate.Format("2006-01-02")
+}
+
 func (c *CalendarAnalyzer) calculateDuration(events []Event) time.Duration {
 	var totalDuration time.Duration
 	for _, event := range events {
@@ -443,7 +785,7 @@ func (c *CalendarAnalyzer) printResults(writer io.Writer, result *common.Analysi
 		return sortedByCount[i].Count > sortedByCount[j].Count
 	})
 
-	for i, stat := range sortedByCount {
+	for i, stat := range sortedByCount[:common.RankingLimit(len(sortedByCount))] {
 		hours := int(stat.Duration.Hours())
 		minutes := int(stat.Duration.Minutes()) % 60
 		durationStr := ""
@@ -452,11 +794,16 @@ func (c *CalendarAnalyzer) printResults(writer io.Writer, result *common.Analysi
 		}
 		fmt.Fprintf(writer, "%2d. %s: %d events%s\n", i+1, stat.Title, stat.Count, durationStr)
 	}
+	common.PrintMoreEntries(writer, len(sortedByCount))
 
 	// Print duration statistics
 	fmt.Fprintln(writer, "\nTop events by total duration:")
-	sortedByDuration := make([]TitleStats, len(titleStats))
-	copy(sortedByDuration, titleStats)
+	var sortedByDuration []TitleStats
+	for _, stat := range titleStats {
+		if stat.Duration >= time.Minute {
+			sortedByDuration = append(sortedByDuration, stat)
+		}
+	}
 	sort.Slice(sortedByDuration, func(i, j int) bool {
 		if sortedByDuration[i].Duration == sortedByDuration[j].Duration {
 			return sortedByDuration[i].Title < sortedByDuration[j].Title
@@ -464,14 +811,12 @@ func (c *CalendarAnalyzer) printResults(writer io.Writer, result *common.Analysi
 		return sortedByDuration[i].Duration > sortedByDuration[j].Duration
 	})
 
-	for i, stat := range sortedByDuration {
+	for i, stat := range sortedByDuration[:common.RankingLimit(len(sortedByDuration))] {
 		hours := int(stat.Duration.Hours())
 		minutes := int(stat.Duration.Minutes()) % 60
-		if hours > 0 || minutes > 0 {
-			durationStr := fmt.Sprintf("%dh%dm", hours, minutes)
-			fmt.Fprintf(writer, "%2d. %s: %s (%d events)\n", i+1, stat.Title, durationStr, stat.Count)
-		}
+		fmt.Fprintf(writer, "%2d. %s: %dh%dm (%d events)\n", i+1, stat.Title, hours, minutes, stat.Count)
 	}
+	common.PrintMoreEntries(writer, len(sortedByDuration))
 
 	// Print all-day event statistics
 	if len(allDayStats) > 0 {
@@ -485,10 +830,11 @@ func (c *CalendarAnalyzer) printResults(writer io.Writer, result *common.Analysi
 			return sortedByDays[i].Duration > sortedByDays[j].Duration
 		})
 
-		for i, stat := range sortedByDays {
+		for i, stat := range sortedByDays[:common.RankingLimit(len(sortedByDays))] {
 			totalDays := int(stat.Duration.Hours() / 24)
 			fmt.Fprintf(writer, "%2d. %s: %d days (%d events)\n", i+1, stat.Title, totalDays, stat.Count)
 		}
+		common.PrintMoreEntries(writer, len(sortedByDays))
 	}
 
 	// Print enhanced category analysis
@@ -497,6 +843,7 @@ func (c *CalendarAnalyzer) printResults(writer io.Writer, result *common.Analysi
 	fmt.Fprintf(writer, "- Focus time: %s\n", c.formatDuration(categoryStats.FocusTime))
 	fmt.Fprintf(writer, "- Learning time: %s\n", c.formatDuration(categoryStats.LearningTime))
 	fmt.Fprintf(writer, "- Admin time: %s\n", c.formatDuration(categoryStats.AdminTime))
+	c.printCategoryBreakdown(writer, categoryStats)
 
 	fmt.Fprintln(writer, "\nWorking Hours Analysis:")
 	fmt.Fprintf(writer, "- Total working hours: %s\n", c.formatDuration(workingHoursStats.TotalWorkingHours))
@@ -515,7 +862,8 @@ func (c *CalendarAnalyzer) printResults(writer io.Writer, result *common.Analysi
 // analyzeCategoryStats categorizes events based on their titles and calculates time spent
 func (c *CalendarAnalyzer) analyzeCategoryStats(events []Event) *EventCategoryStats {
 	stats := &EventCategoryStats{
-		Categories: make(map[string]*CategoryInfo),
+		Categories:         make(map[string]*CategoryInfo),
+		MainCategoryEvents: make(map[string][]Event),
 	}
 
 	for _, event := range events {
@@ -526,8 +874,13 @@ func (c *CalendarAnalyzer) analyzeCategoryStats(events []Event) *EventCategorySt
 		duration := event.End.Sub(event.Start)
 		title := strings.ToLower(event.Summary)
 
-		// Categorize events
+		// Categorize events, manual overrides take precedence over keyword rules
 		category := c.categorizeEvent(title)
+		categoryType := c.categoryConfig.GetCategoryTime(title)
+		if override, exists := c.overrides.Lookup(event.UID); exists && override.Category != "" {
+			category = c.displayCategory(override.Category)
+			categoryType = c.categoryConfig.MainCategory(override.Category)
+		}
 
 		if stats.Categories[category] == nil {
 			stats.Categories[category] = &CategoryInfo{
@@ -540,10 +893,11 @@ func (c *CalendarAnalyzer) analyzeCategoryStats(events []Event) *EventCategorySt
 		stats.Categories[category].Events = append(stats.Categories[category].Events, event)
 
 		// Update main category totals using configuration
-		categoryType := c.categoryConfig.GetCategoryTime(title)
+		stats.MainCategoryEvents[categoryType] = append(stats.MainCategoryEvents[categoryType], event)
 		switch categoryType {
 		case "meeting":
 			stats.MeetingTime += duration
+			stats.Categories[category].MeetingTime += duration
 		case "focus":
 			stats.FocusTime += duration
 		case "learning":
@@ -556,11 +910,44 @@ func (c *CalendarAnalyzer) analyzeCategoryStats(events []Event) *EventCategorySt
 	return stats
 }
 
+// printCategoryBreakdown prints the hours of each category with its share of the timed events, and of the meeting
+// time for meeting categories
+func (c *CalendarAnalyzer) printCategoryBreakdown(writer io.Writer, stats *EventCategoryStats) {
+	var total time.Duration
+	names := make([]string, 0, len(stats.Categories))
+	for name, info := range stats.Categories {
+		names = append(names, name)
+		total += info.Duration
+	}
+	if total <= 0 {
+		return
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := stats.Categories[names[i]], stats.Categories[names[j]]
+		if a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Fprintln(writer, "\nTime by Category:")
+	for _, name := range names {
+		info := stats.Categories[name]
+		share := fmt.Sprintf("%.0f%% of total", float64(info.Duration)/float64(total)*100)
+		if info.MeetingTime > 0 && stats.MeetingTime > 0 {
+			share += fmt.Sprintf(", %.0f%% of meetings", float64(info.MeetingTime)/float64(stats.MeetingTime)*100)
+		}
+		fmt.Fprintf(writer, "- %s: %s, %d events (%s)\n", name, c.formatDuration(info.Duration), info.Count, share)
+	}
+}
+
 // categorizeEvent determines the category of an event based on its title
 func (c *CalendarAnalyzer) categorizeEvent(title string) string {
-	category := c.categoryConfig.CategorizeByKeywords(title)
+	return c.displayCategory(c.categoryConfig.CategorizeByKeywords(title))
+}
 
-	// Convert category names to display format for compatibility
+// displayCategory converts category names to display format for compatibility
+func (c *CalendarAnalyzer) displayCategory(category string) string {
 	switch category {
 	case "1on1 meetings":
 		return "1on1 Meetings"
@@ -586,9 +973,14 @@ func (c *CalendarAnalyzer) categorizeEvent(title string) string {
 		return "Learning & Training"
 	case "admin":
 		return "Admin Work"
-	default:
+	case "other":
 		return "Other"
 	}
+	// Event rules added to categorization.yaml ("interviews") are shown by their name
+	if c.categoryConfig.HasCategory(category) {
+		return c.categoryConfig.GetCategoryDisplayName(category)
+	}
+	return "Other"
 }
 
 // analyzeWorkingHours analyzes patterns in working hours
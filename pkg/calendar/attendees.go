@@ -0,0 +1,225 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	googlecal "dev-stats/pkg/google"
+)
+
+// Attendee is a participant of an event (ATTENDEE in ICS, attendees in the Google Calendar API)
+type Attendee struct {
+	Email    string
+	Status   string // PARTSTAT: ACCEPTED, DECLINED, TENTATIVE, or NEEDS-ACTION
+	Resource bool   // a room or equipment (CUTYPE=ROOM/RESOURCE), not a person
+}
+
+// ParticipationStats summarizes the meetings of the period: events with at least one other person
+type ParticipationStats struct {
+	Enabled      bool // some event lists attendees; ICS files without them have nothing to report
+	Identified   bool // CALENDAR_EMAIL is set, so meetings are split into organized and attended
+	Meetings     int
+	Organized    []Event
+	Attended     []Event
+	Participants int // sum over meetings, for the average
+	Largest      Event
+	LargestCount int
+	PersonHours  time.Duration // duration times participants, summed over meetings
+}
+
+// AverageAttendees returns the participants per meeting, rounded to one decimal
+func (s *ParticipationStats) AverageAttendees() float64 {
+	if s.Meetings == 0 {
+		return 0
+	}
+	return math.Round(float64(s.Participants)/float64(s.Meetings)*10) / 10
+}
+
+// calendarEmails reads CALENDAR_EMAIL: my addresses, comma-separated (aliases and other accounts)
+func calendarEmails() []string {
+	var emails []string
+	for _, email := range strings.Split(os.Getenv("CALENDAR_EMAIL"), ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// calendarAddress returns the email of a CAL-ADDRESS value (mailto:alice@example.com), lowercased
+func calendarAddress(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 7 && strings.EqualFold(value[:7], "mailto:") {
+		value = value[7:]
+	}
+	return strings.ToLower(value)
+}
+
+// icsAttendee reads an ATTENDEE property; without PARTSTAT an invitation is unanswered (RFC 5545 3.2.12)
+func icsAttendee(params map[string]string, value string) Attendee {
+	status := strings.ToUpper(params["PARTSTAT"])
+	if status == "" {
+		status = "NEEDS-ACTION"
+	}
+	cuType := strings.ToUpper(params["CUTYPE"])
+	return Attendee{Email: calendarAddress(value), Status: status, Resource: cuType == "ROOM" || cuType == "RESOURCE"}
+}
+
+// apiAttendees converts the guests of a Google Calendar API event, with response statuses written like PARTSTAT
+func apiAttendees(attendees []googlecal.CalendarAttendee) []Attendee {
+	var converted []Attendee
+	for _, attendee := range attendees {
+		status := strings.ToUpper(attendee.ResponseStatus)
+		if status == "NEEDSACTION" || status == "" {
+			status = "NEEDS-ACTION"
+		}
+		converted = append(converted, Attendee{Email: strings.ToLower(attendee.Email), Status: status, Resource: attendee.Resource})
+	}
+	return converted
+}
+
+// isMine reports whether an email is one of CALENDAR_EMAIL
+func (c *CalendarAnalyzer) isMine(email string) bool {
+	for _, mine := range c.emails {
+		if email == mine {
+			return true
+		}
+	}
+	return false
+}
+
+// participants counts the people in an event who didn't decline, with the organizer when not listed as an attendee
+func participants(event Event) int {
+	count := 0
+	organizerListed := event.Organizer == ""
+	for _, attendee := range event.Attendees {
+		if attendee.Email != "" && attendee.Email == event.Organizer {
+			organizerListed = true
+		}
+		if !attendee.Resource && attendee.Status != "DECLINED" {
+			count++
+		}
+	}
+	if !organizerListed {
+		count++
+	}
+	return count
+}
+
+// filterAccepted keeps, with CALENDAR_ACCEPTED_ONLY, the events I organized or accepted and those without
+// attendees (own blocks); invitations I declined, answered tentatively, didn't answer, or am not listed in are left out
+func (c *CalendarAnalyzer) filterAccepted(writer io.Writer, events []Event) []Event {
+	if !c.acceptedOnly {
+		return events
+	}
+	var kept []Event
+	for _, event := range events {
+		if c.accepted(event) {
+			kept = append(kept, event)
+		}
+	}
+	if left := len(events) - len(kept); left > 0 {
+		fmt.Fprintf(writer, "Left out %d events not accepted by %s (CALENDAR_ACCEPTED_ONLY)\n", left, strings.Join(c.emails, ", "))
+	}
+	return kept
+}
+
+// accepted reports whether an event counts with CALENDAR_ACCEPTED_ONLY
+func (c *CalendarAnalyzer) accepted(event Event) bool {
+	if len(event.Attendees) == 0 || c.isMine(event.Organizer) {
+		return true
+	}
+	for _, attendee := range event.Attendees {
+		if c.isMine(attendee.Email) {
+			return attendee.Status == "ACCEPTED"
+		}
+	}
+	return false
+}
+
+// analyzeParticipation counts the meetings (timed events with two or more participants) I organized and attended,
+// their participants, and the person-hours they took
+func (c *CalendarAnalyzer) analyzeParticipation(events []Event) *ParticipationStats {
+	stats := &ParticipationStats{Identified: len(c.emails) > 0}
+	for _, event := range events {
+		if len(event.Attendees) > 0 {
+			stats.Enabled = true
+		}
+		count := participants(event)
+		if c.isAllDayEvent(event) || count < 2 {
+			continue
+		}
+		stats.Meetings++
+		stats.Participants += count
+		if !event.End.IsZero() && event.End.After(event.Start) {
+			stats.PersonHours += time.Duration(count) * event.End.Sub(event.Start)
+		}
+		if count > stats.LargestCount {
+			stats.Largest, stats.LargestCount = event, count
+		}
+		if stats.Identified {
+			if c.isMine(event.Organizer) {
+				stats.Organized = append(stats.Organized, event)
+			} else {
+				stats.Attended = append(stats.Attended, event)
+			}
+		}
+	}
+	return stats
+}
+
+// participationMetrics returns the meeting metrics, or none when no event lists attendees
+func participationMetrics(stats *ParticipationStats) []common.Metric {
+	if !stats.Enabled {
+		return nil
+	}
+	metrics := []common.Metric{
+		{ID: "calendar.meetings", Label: "Meetings with attendees", Value: stats.Meetings},
+	}
+	if stats.Identified {
+		metrics = append(metrics,
+			common.Metric{ID: "calendar.meetings_organized", Label: "Meetings organized", Value: len(stats.Organized)},
+			common.Metric{ID: "calendar.meetings_attended", Label: "Meetings attended", Value: len(stats.Attended)},
+		)
+	}
+	return append(metrics,
+		common.Metric{ID: "calendar.avg_attendees", Label: "Average attendees per meeting", Value: stats.AverageAttendees(), Snapshot: true},
+		common.Metric{ID: "calendar.person_hours", Label: "Meeting person-hours", Value: stats.PersonHours},
+	)
+}
+
+// printParticipation prints organized vs attended meetings, their size, and person-hours
+func (c *CalendarAnalyzer) printParticipation(writer io.Writer, stats *ParticipationStats) {
+	if !stats.Enabled {
+		return
+	}
+	fmt.Fprintln(writer, "\nMeeting Participation:")
+	if stats.Identified {
+		fmt.Fprintf(writer, "- Meetings: %d (organized %d, attended %d)\n", stats.Meetings, len(stats.Organized), len(stats.Attended))
+	} else {
+		fmt.Fprintf(writer, "- Meetings: %d (set CALENDAR_EMAIL to split organized and attended)\n", stats.Meetings)
+	}
+	if stats.Meetings == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "- Average attendees per meeting: %.1f (largest: %d, %s)\n", stats.AverageAttendees(), stats.LargestCount, stats.Largest.Summary)
+	fmt.Fprintf(writer, "- Person-hours: %s\n", c.formatDuration(stats.PersonHours))
+
+	if len(stats.Organized) > 0 {
+		fmt.Fprintln(writer, "- Organized by attendees:")
+		organized := append([]Event{}, stats.Organized...)
+		sort.SliceStable(organized, func(i, j int) bool {
+			return participants(organized[i]) > participants(organized[j])
+		})
+		for _, event := range organized[:common.RankingLimit(len(organized))] {
+			fmt.Fprintf(writer, "  %s %s: %d attendees\n", event.Start.Format("2006-01-02"), event.Summary, participants(event))
+		}
+		common.PrintMoreEntries(writer, len(organized))
+	}
+}
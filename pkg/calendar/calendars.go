@@ -0,0 +1,131 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// unnamedCalendar labels events without a calendar (raw data saved before calendars were recorded)
+const unnamedCalendar = "(unnamed)"
+
+// CalendarStats summarizes the events of one calendar
+type CalendarStats struct {
+	Name     string        `json:"name"`
+	Events   int           `json:"events"`
+	Duration time.Duration `json:"duration"` // timed events only
+	Excluded bool          `json:"excluded"` // listed in CALENDAR_EXCLUDE: shown here, left out of the totals
+}
+
+// excludedCalendars reads CALENDAR_EXCLUDE: calendar names (X-WR-CALNAME, ICS file names without .ics, or API
+// calendar names or IDs), comma-separated
+func excludedCalendars() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv("CALENDAR_EXCLUDE"), ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// fileCalendarName names the calendar of an ICS file without X-WR-CALNAME after the file: work.ics is "work"
+func fileCalendarName(path string) string {
+	name := filepath.Base(filepath.ToSlash(path))
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// calendarName returns the calendar of an event for display
+func calendarName(event Event) string {
+	if event.Calendar == "" {
+		return unnamedCalendar
+	}
+	return event.Calendar
+}
+
+// isExcluded reports whether an event's calendar is listed in CALENDAR_EXCLUDE
+func (c *CalendarAnalyzer) isExcluded(event Event) bool {
+	for _, name := range c.excluded {
+		if strings.ToLower(event.Calendar) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// filterExcluded leaves out the events of calendars listed in CALENDAR_EXCLUDE
+func (c *CalendarAnalyzer) filterExcluded(writer io.Writer, events []Event) []Event {
+	if len(c.excluded) == 0 {
+		return events
+	}
+	var kept []Event
+	for _, event := range events {
+		if !c.isExcluded(event) {
+			kept = append(kept, event)
+		}
+	}
+	if left := len(events) - len(kept); left > 0 {
+		fmt.Fprintf(writer, "Left out %d events of excluded calendars (CALENDAR_EXCLUDE)\n", left)
+	}
+	return kept
+}
+
+// analyzeCalendars counts the events and hours of each calendar, most hours first
+func (c *CalendarAnalyzer) analyzeCalendars(events []Event) []CalendarStats {
+	byName := make(map[string]*CalendarStats)
+	for _, event := range events {
+		name := calendarName(event)
+		stats, exists := byName[name]
+		if !exists {
+			stats = &CalendarStats{Name: name, Excluded: c.isExcluded(event)}
+			byName[name] = stats
+		}
+		stats.Events++
+		if !c.isAllDayEvent(event) && event.End.After(event.Start) {
+			stats.Duration += event.End.Sub(event.Start)
+		}
+	}
+
+	calendars := make([]CalendarStats, 0, len(byName))
+	for _, stats := range byName {
+		calendars = append(calendars, *stats)
+	}
+	sort.Slice(calendars, func(i, j int) bool {
+		if calendars[i].Duration != calendars[j].Duration {
+			return calendars[i].Duration > calendars[j].Duration
+		}
+		return calendars[i].Name < calendars[j].Name
+	})
+	return calendars
+}
+
+// printCalendars prints events and hours per calendar with their share of the counted hours, when events come
+// from more than one calendar or some are excluded
+func (c *CalendarAnalyzer) printCalendars(writer io.Writer, calendars []CalendarStats) {
+	if len(calendars) < 2 && len(c.excluded) == 0 {
+		return
+	}
+	var counted time.Duration
+	for _, stats := range calendars {
+		if !stats.Excluded {
+			counted += stats.Duration
+		}
+	}
+
+	fmt.Fprintln(writer, "\nCalendars:")
+	for _, stats := range calendars {
+		if stats.Excluded {
+			fmt.Fprintf(writer, "- %s: %d events, %s (excluded from totals)\n", stats.Name, stats.Events, c.formatDuration(stats.Duration))
+			continue
+		}
+		share := 0.0
+		if counted > 0 {
+			share = float64(stats.Duration) / float64(counted) * 100
+		}
+		fmt.Fprintf(writer, "- %s: %d events, %s (%.0f%%)\n", stats.Name, stats.Events, c.formatDuration(stats.Duration), share)
+	}
+}
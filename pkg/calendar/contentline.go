@@ -0,0 +1,73 @@
+package calendar
+
+import (
+	"bufio"
+	"io"
+	"mime/quotedprintable"
+	"strings"
+)
+
+// maxContentLineSize bounds one unfolded line; DESCRIPTIONs of meeting invites with long agendas or pasted
+// conference details exceed the 64 KB bufio.Scanner default
+const maxContentLineSize = 4 * 1024 * 1024
+
+// contentLines reads the content lines of an ICS file. Lines continued on the next line are joined: folding
+// (RFC 5545 3.1, a line break followed by a space or tab, which may split a UTF-8 character) and the soft line
+// breaks of quoted-printable values (a trailing =) in vCalendar 1.0 exports.
+func contentLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxContentLineSize)
+	var lines []string
+	softBreak := false
+	for scanner.Scan() {
+		raw := strings.TrimSuffix(scanner.Text(), "\r")
+		switch {
+		case softBreak: // the next line continues the value as is, leading spaces included
+			lines[len(lines)-1] = strings.TrimSuffix(lines[len(lines)-1], "=") + raw
+		case len(lines) > 0 && (strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")):
+			lines[len(lines)-1] += raw[1:]
+		case strings.TrimSpace(raw) == "":
+			continue
+		default:
+			lines = append(lines, raw)
+		}
+		last := lines[len(lines)-1]
+		softBreak = strings.HasSuffix(last, "=") && quotedPrintable(last)
+	}
+	return lines, scanner.Err()
+}
+
+// quotedPrintable reports whether a content line has the ENCODING=QUOTED-PRINTABLE parameter
+func quotedPrintable(line string) bool {
+	_, params, _ := icsProperty(line)
+	return strings.EqualFold(params["ENCODING"], "QUOTED-PRINTABLE")
+}
+
+// propertyText decodes a TEXT value: quoted-printable when the ENCODING parameter says so (UTF-8 only; other
+// CHARSETs are kept as bytes), then the escapes of RFC 5545 3.3.11 (\\ \; \, and \n for a line break)
+func propertyText(params map[string]string, value string) string {
+	if strings.EqualFold(params["ENCODING"], "QUOTED-PRINTABLE") {
+		if decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(value))); err == nil {
+			value = string(decoded)
+		}
+	}
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var text strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			text.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			text.WriteByte('\n')
+		default: // \\ \; \, and unknown escapes (Outlook writes \:) stand for the character itself
+			text.WriteByte(value[i])
+		}
+	}
+	return text.String()
+}
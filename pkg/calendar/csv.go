@@ -0,0 +1,45 @@
+package calendar
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// eventCSVRow is one event in calendar-events.csv
+type eventCSVRow struct {
+	Start     time.Time     `csv:"start"`
+	End       time.Time     `csv:"end"`
+	Title     string        `csv:"title"`
+	AllDay    bool          `csv:"all_day"`
+	Duration  time.Duration `csv:"duration_hours"`
+	Category  string        `csv:"category"`
+	Organizer string        `csv:"organizer"`
+	Attendees int           `csv:"attendees"` // participants who didn't decline; 0 for events without attendees
+	Calendar  string        `csv:"calendar"`
+}
+
+// csvTables lists the events of the period. activities are built from events in the same order and carry the
+// categories after overrides.
+func (c *CalendarAnalyzer) csvTables(events []Event, activities []common.Activity) []common.CSVTable {
+	var rows []eventCSVRow
+	for i, event := range events {
+		row := eventCSVRow{
+			Start:     event.Start,
+			End:       event.End,
+			Title:     event.Summary,
+			AllDay:    c.isAllDayEvent(event),
+			Category:  activities[i].Category,
+			Organizer: event.Organizer,
+			Calendar:  event.Calendar,
+		}
+		if len(event.Attendees) > 0 {
+			row.Attendees = participants(event)
+		}
+		if !row.AllDay && event.End.After(event.Start) {
+			row.Duration = event.End.Sub(event.Start)
+		}
+		rows = append(rows, row)
+	}
+	return []common.CSVTable{common.NewCSVTable("events", rows)}
+}
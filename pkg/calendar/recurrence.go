@@ -0,0 +1,330 @@
+package calendar
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// maxRecurrencePeriods bounds the periods (days, weeks, months, years) a recurrence rule is walked through, so a
+// malformed rule can't loop forever; a daily rule started 30 years before the period stays far below it
+const maxRecurrencePeriods = 100000
+
+// recurringEvent is a VEVENT with an RRULE, kept while parsing so that its occurrences can be generated once the
+// whole file is read and every RECURRENCE-ID override is known
+type recurringEvent struct {
+	event   Event
+	zone    *icsZone // zone of the DTSTART TZID, whose offset each occurrence is resolved with
+	rule    *recurrenceRule
+	exdates map[string]bool // occurrenceKey of the excluded start times
+}
+
+// recurrenceRule is the parsed RRULE of an event (RFC 5545 3.3.10). BYSETPOS, BYWEEKNO, BYYEARDAY, and
+// time-of-day parts are not supported: rules using them are expanded without them.
+type recurrenceRule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []weekdayRule
+	byMonthDay []int
+	byMonth    []time.Month
+	weekStart  time.Weekday
+}
+
+// weekdayRule is one BYDAY entry: a weekday with an optional ordinal (1MO: the first Monday, -1FR: the last Friday)
+type weekdayRule struct {
+	ordinal int
+	weekday time.Weekday
+}
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRecurrenceRule parses an RRULE value such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20250630T000000Z
+func (c *CalendarAnalyzer) parseRecurrenceRule(value string) (*recurrenceRule, error) {
+	rule := &recurrenceRule{interval: 1, weekStart: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		name, val, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.freq = strings.ToUpper(val)
+		case "INTERVAL":
+			if n, err := strconv.Atoi(val); err == nil && n > 0 {
+				rule.interval = n
+			}
+		case "COUNT":
+			if n, err := strconv.Atoi(val); err == nil && n > 0 {
+				rule.count = n
+			}
+		case "UNTIL":
+			until, err := c.parseDateTime(val)
+			if err != nil {
+				return nil, common.WrapError(err, "invalid UNTIL in RRULE %s", value)
+			}
+			rule.until = until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				day = strings.ToUpper(strings.TrimSpace(day))
+				if len(day) < 2 {
+					continue
+				}
+				weekday, ok := icsWeekdays[day[len(day)-2:]]
+				if !ok {
+					return nil, common.NewError("invalid BYDAY %s in RRULE %s", day, value)
+				}
+				ordinal := 0
+				if prefix := strings.TrimPrefix(day[:len(day)-2], "+"); prefix != "" {
+					n, err := strconv.Atoi(prefix)
+					if err != nil {
+						return nil, common.NewError("invalid BYDAY %s in RRULE %s", day, value)
+					}
+					ordinal = n
+				}
+				rule.byDay = append(rule.byDay, weekdayRule{ordinal: ordinal, weekday: weekday})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				if n, err := strconv.Atoi(day); err == nil && n != 0 {
+					rule.byMonthDay = append(rule.byMonthDay, n)
+				}
+			}
+		case "BYMONTH":
+			for _, month := range strings.Split(val, ",") {
+				if n, err := strconv.Atoi(month); err == nil && n >= 1 && n <= 12 {
+					rule.byMonth = append(rule.byMonth, time.Month(n))
+				}
+			}
+		case "WKST":
+			if weekday, ok := icsWeekdays[strings.ToUpper(val)]; ok {
+				rule.weekStart = weekday
+			}
+		}
+	}
+	switch rule.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+		return rule, nil
+	}
+	return nil, common.NewError("unsupported FREQ in RRULE %s", value)
+}
+
+// occurrences returns the start times of the rule from start (the event's DTSTART, always the first occurrence)
+// up to before limit, in order. COUNT counts the occurrences before the analysis period too.
+func (r *recurrenceRule) occurrences(start, limit time.Time) []time.Time {
+	var result []time.Time
+	emitted := 0
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		candidates := r.candidates(start, period*r.interval)
+		if len(candidates) == 0 && r.periodStart(start, period*r.interval).After(limit) {
+			break
+		}
+		for _, candidate := range candidates {
+			if candidate.Before(start) {
+				continue
+			}
+			if !candidate.Before(limit) || (!r.until.IsZero() && candidate.After(r.until)) {
+				return result
+			}
+			result = append(result, candidate)
+			emitted++
+			if r.count > 0 && emitted >= r.count {
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// periodStart returns the first day of the period offset periods after the one containing start
+func (r *recurrenceRule) periodStart(start time.Time, offset int) time.Time {
+	switch r.freq {
+	case "DAILY":
+		return start.AddDate(0, 0, offset)
+	case "WEEKLY":
+		back := (int(start.Weekday()) - int(r.weekStart) + 7) % 7
+		return time.Date(start.Year(), start.Month(), start.Day()-back+7*offset, 0, 0, 0, 0, start.Location())
+	case "MONTHLY":
+		return time.Date(start.Year(), start.Month()+time.Month(offset), 1, 0, 0, 0, 0, start.Location())
+	default:
+		return time.Date(start.Year()+offset, time.January, 1, 0, 0, 0, 0, start.Location())
+	}
+}
+
+// candidates returns the sorted start times the rule selects in one period, at the time of day of start
+func (r *recurrenceRule) candidates(start time.Time, offset int) []time.Time {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+	periodStart := r.periodStart(start, offset)
+	var days []time.Time
+
+	switch r.freq {
+	case "DAILY":
+		day := at(periodStart.Year(), periodStart.Month(), periodStart.Day())
+		if r.matchesWeekday(day) && r.matchesMonth(day) && r.matchesMonthDay(day) {
+			days = append(days, day)
+		}
+	case "WEEKLY":
+		weekdays := r.byDay
+		if len(weekdays) == 0 {
+			weekdays = []weekdayRule{{weekday: start.Weekday()}}
+		}
+		for _, weekday := range weekdays {
+			shift := (int(weekday.weekday) - int(r.weekStart) + 7) % 7
+			day := at(periodStart.Year(), periodStart.Month(), periodStart.Day()+shift)
+			if r.matchesMonth(day) {
+				days = append(days, day)
+			}
+		}
+	case "MONTHLY":
+		if r.matchesMonth(periodStart) {
+			days = r.monthDays(start, periodStart.Year(), periodStart.Month(), at)
+		}
+	case "YEARLY":
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, month := range months {
+			if len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+				// Without BYDAY/BYMONTHDAY the day of DTSTART repeats; years without it (Feb 29) are skipped
+				if day := at(periodStart.Year(), month, start.Day()); day.Month() == month {
+					days = append(days, day)
+				}
+				continue
+			}
+			days = append(days, r.monthDays(start, periodStart.Year(), month, at)...)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+	return days
+}
+
+// monthDays returns the days of one month selected by BYMONTHDAY and BYDAY, or the day of the month of start
+func (r *recurrenceRule) monthDays(start time.Time, year int, month time.Month, at func(int, time.Month, int) time.Time) []time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	var days []time.Time
+	if len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+		// Months without the day of DTSTART (the 31st in April) are skipped, as RFC 5545 requires
+		if start.Day() <= lastDay {
+			days = append(days, at(year, month, start.Day()))
+		}
+		return days
+	}
+
+	for day := 1; day <= lastDay; day++ {
+		candidate := at(year, month, day)
+		if !r.matchesMonthDay(candidate) {
+			continue
+		}
+		if len(r.byDay) == 0 {
+			days = append(days, candidate)
+			continue
+		}
+		for _, weekday := range r.byDay {
+			if candidate.Weekday() != weekday.weekday {
+				continue
+			}
+			nth := (day-1)/7 + 1              // 1 for the first of this weekday in the month
+			nthLast := -((lastDay-day)/7 + 1) // -1 for the last
+			if weekday.ordinal == 0 || weekday.ordinal == nth || weekday.ordinal == nthLast {
+				days = append(days, candidate)
+				break
+			}
+		}
+	}
+	return days
+}
+
+// matchesWeekday reports whether day is one of the BYDAY weekdays (ordinals ignored), or true without BYDAY
+func (r *recurrenceRule) matchesWeekday(day time.Time) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, weekday := range r.byDay {
+		if weekday.weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonth reports whether day is in one of the BYMONTH months, or true without BYMONTH
+func (r *recurrenceRule) matchesMonth(day time.Time) bool {
+	if len(r.byMonth) == 0 {
+		return true
+	}
+	for _, month := range r.byMonth {
+		if month == day.Month() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthDay reports whether day is one of the BYMONTHDAY days (negative: from the end), or true without it
+func (r *recurrenceRule) matchesMonthDay(day time.Time) bool {
+	if len(r.byMonthDay) == 0 {
+		return true
+	}
+	lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, monthDay := range r.byMonthDay {
+		if monthDay == day.Day() || (monthDay < 0 && lastDay+monthDay+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// occurrenceKey identifies one occurrence of a recurring event by its original start time, in the form Google
+// Calendar uses for instance IDs (20250106T010000Z, or 20250106 for all-day events)
+func occurrenceKey(start time.Time, allDay bool) string {
+	if allDay {
+		return start.Format("20060102")
+	}
+	return start.UTC().Format("20060102T150405Z")
+}
+
+// occurrenceUID returns the UID of one occurrence, so that occurrences stay distinct when deduplicated by UID
+func occurrenceUID(uid, key string) string {
+	if uid == "" {
+		return ""
+	}
+	return uid + "_" + key
+}
+
+// expand returns the occurrences of the event until shortly after endDate, leaving out EXDATEs and the
+// occurrences replaced by RECURRENCE-ID overrides
+func (e *recurringEvent) expand(overridden map[string]bool, endDate time.Time) []Event {
+	var duration time.Duration
+	if !e.event.End.IsZero() && e.event.End.After(e.event.Start) {
+		duration = e.event.End.Sub(e.event.Start)
+	}
+	var events []Event
+	// A day past endDate covers the display timezone being ahead of the zone of the event
+	for _, start := range e.rule.occurrences(e.event.Start, endDate.AddDate(0, 0, 2)) {
+		if e.zone != nil {
+			start = e.zone.at(start)
+		}
+		key := occurrenceKey(start, e.event.IsAllDay)
+		if e.exdates[key] || overridden[key] {
+			continue
+		}
+		occurrence := e.event
+		occurrence.UID = occurrenceUID(e.event.UID, key)
+		occurrence.Start = start
+		if duration > 0 {
+			occurrence.End = start.Add(duration)
+		}
+		events = append(events, occurrence)
+	}
+	return events
+}
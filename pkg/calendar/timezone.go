@@ -0,0 +1,206 @@
+package calendar
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// displayLocation returns the timezone events are converted into for day bucketing and hour distributions:
+// CALENDAR_TIMEZONE (an IANA name such as Asia/Tokyo), else the local timezone
+func displayLocation() (*time.Location, error) {
+	name := strings.TrimSpace(os.Getenv("CALENDAR_TIMEZONE"))
+	if name == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, common.WrapError(err, "invalid CALENDAR_TIMEZONE %q (expected an IANA name like Asia/Tokyo)", name)
+	}
+	return location, nil
+}
+
+// icsZone resolves the wall-clock times of one TZID: with the IANA zone it names (Google, Apple, and most exports),
+// or with the STANDARD/DAYLIGHT observances of its VTIMEZONE (Outlook's Windows zone names)
+type icsZone struct {
+	location    *time.Location
+	observances []observance
+}
+
+// observance is a STANDARD or DAYLIGHT block of a VTIMEZONE: from each onset (DTSTART and its RRULE
+// occurrences, in local time) the offset is TZOFFSETTO
+type observance struct {
+	start      time.Time // wall-clock time of the first onset, with UTC fields
+	rule       *recurrenceRule
+	offsetFrom int // seconds east of UTC
+	offsetTo   int
+}
+
+// at returns the instant of a wall-clock time (the date and time fields of wall) in the zone
+func (z *icsZone) at(wall time.Time) time.Time {
+	if z.location != nil {
+		return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, z.location)
+	}
+	utcWall := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, time.UTC)
+	offset := z.offsetAt(utcWall)
+	return utcWall.Add(-time.Duration(offset) * time.Second).In(time.FixedZone("", offset))
+}
+
+// offsetAt returns the offset in effect at a wall-clock time: the TZOFFSETTO of the latest onset at or before it,
+// or the TZOFFSETFROM of the earliest observance before any onset
+func (z *icsZone) offsetAt(wall time.Time) int {
+	var latest time.Time
+	offset, found := 0, false
+	var earliest *observance
+	for i, o := range z.observances {
+		if earliest == nil || o.start.Before(earliest.start) {
+			earliest = &z.observances[i]
+		}
+		onset := o.start
+		if o.rule != nil {
+			onsets := o.rule.occurrences(o.start, wall.Add(time.Second))
+			if len(onsets) == 0 {
+				continue
+			}
+			onset = onsets[len(onsets)-1]
+		} else if onset.After(wall) {
+			continue
+		}
+		if !found || onset.After(latest) {
+			latest, offset, found = onset, o.offsetTo, true
+		}
+	}
+	if !found && earliest != nil {
+		return earliest.offsetFrom
+	}
+	return offset
+}
+
+// resolveZone returns the zone of a TZID: a VTIMEZONE defined in the file, else the IANA zone of that name, or nil
+// when the TZID is unknown (the time is then read in the display timezone)
+func resolveZone(tzid string, zones map[string]*icsZone) *icsZone {
+	if tzid == "" {
+		return nil
+	}
+	if zone, exists := zones[tzid]; exists {
+		return zone
+	}
+	if location, err := time.LoadLocation(tzid); err == nil {
+		return &icsZone{location: location}
+	}
+	return nil
+}
+
+// vtimezoneParser collects the lines of a VTIMEZONE block
+type vtimezoneParser struct {
+	tzid        string
+	ianaName    string // X-LIC-LOCATION
+	observances []observance
+	current     *observance
+	rrule       string
+}
+
+// line reads one line inside the VTIMEZONE block
+func (p *vtimezoneParser) line(c *CalendarAnalyzer, name string, params map[string]string, value string) {
+	switch name {
+	case "TZID":
+		p.tzid = value
+	case "X-LIC-LOCATION":
+		p.ianaName = value
+	case "BEGIN":
+		if value == "STANDARD" || value == "DAYLIGHT" {
+			p.current, p.rrule = &observance{}, ""
+		}
+	case "END":
+		if p.current != nil && (value == "STANDARD" || value == "DAYLIGHT") {
+			if p.rrule != "" {
+				if rule, err := c.parseRecurrenceRule(p.rrule); err == nil {
+					// UNTIL is in UTC; compared with wall-clock onsets it is off by the offset at most
+					p.current.rule = rule
+				}
+			}
+			p.observances = append(p.observances, *p.current)
+			p.current = nil
+		}
+	}
+	if p.current == nil {
+		return
+	}
+	switch name {
+	case "DTSTART":
+		if t, err := time.Parse("20060102T150405", value); err == nil {
+			p.current.start = t
+		}
+	case "TZOFFSETFROM":
+		p.current.offsetFrom = parseUTCOffset(value)
+	case "TZOFFSETTO":
+		p.current.offsetTo = parseUTCOffset(value)
+	case "RRULE":
+		p.rrule = value
+	}
+}
+
+// zone returns the parsed zone: the IANA zone named by the TZID or X-LIC-LOCATION when there is one, which also
+// covers the transitions before and after the observances listed
+func (p *vtimezoneParser) zone() *icsZone {
+	for _, name := range []string{p.ianaName, p.tzid} {
+		if name == "" {
+			continue
+		}
+		if location, err := time.LoadLocation(name); err == nil {
+			return &icsZone{location: location}
+		}
+	}
+	if len(p.observances) == 0 {
+		return nil
+	}
+	return &icsZone{observances: p.observances}
+}
+
+// parseUTCOffset parses a UTC offset such as +0900, -0500, or +053000 into seconds east of UTC
+func parseUTCOffset(value string) int {
+	if len(value) < 5 {
+		return 0
+	}
+	sign := 1
+	if value[0] == '-' {
+		sign = -1
+	}
+	hours, _ := strconv.Atoi(value[1:3])
+	minutes, _ := strconv.Atoi(value[3:5])
+	seconds := 0
+	if len(value) >= 7 {
+		seconds, _ = strconv.Atoi(value[5:7])
+	}
+	return sign * (hours*3600 + minutes*60 + seconds)
+}
+
+// icsProperty splits a content line into its name, parameters, and value
+// (DTSTART;TZID="(UTC+09:00) Osaka, Sapporo, Tokyo":20250106T100000). Quoted parameter values may contain ':'.
+func icsProperty(line string) (string, map[string]string, string) {
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon == -1 {
+		return strings.ToUpper(line), nil, ""
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:]
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultAchievementsPath is where manual achievements are stored across periods
+const DefaultAchievementsPath = "storage/achievements.json"
+
+// Achievement is a manually logged qualitative win (e.g. "Shipped X")
+type Achievement struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// LoadAchievements loads all logged achievements. A missing file means none have been logged.
+func LoadAchievements(path string) ([]Achievement, error) {
+	var achievements []Achievement
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return achievements, nil
+	}
+	if err := ReadJSONFile(path, &achievements); err != nil {
+		return nil, err
+	}
+	return achievements, nil
+}
+
+// AppendAchievement adds an achievement to the store, keeping entries in chronological order
+func AppendAchievement(path string, achievement Achievement) error {
+	if strings.TrimSpace(achievement.Text) == "" {
+		return NewError("achievement text is empty")
+	}
+	achievements, err := LoadAchievements(path)
+	if err != nil {
+		return err
+	}
+	achievements = append(achievements, achievement)
+	sort.SliceStable(achievements, func(i, j int) bool {
+		return achievements[i].Time.Before(achievements[j].Time)
+	})
+	return WriteJSONFile(path, achievements)
+}
+
+// AchievementsInPeriod returns achievements logged between startDate and endDate (inclusive)
+func AchievementsInPeriod(achievements []Achievement, startDate, endDate time.Time) []Achievement {
+	var result []Achievement
+	for _, achievement := range achievements {
+		day := achievement.Time.Format("2006-01-02")
+		if day >= startDate.Format("2006-01-02") && day <= endDate.Format("2006-01-02") {
+			result = append(result, achievement)
+		}
+	}
+	return result
+}
+
+// PrintAchievements lists manual achievements next to the collected stats. Nothing is printed when there are none.
+func PrintAchievements(writer io.Writer, achievements []Achievement) {
+	if len(achievements) == 0 {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(writer, "ACHIEVEMENTS (%d, logged manually)\n", len(achievements))
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	for _, achievement := range achievements {
+		fmt.Fprintf(writer, "- %s: %s\n", achievement.Time.Format("2006-01-02"), achievement.Text)
+	}
+}
@@ -0,0 +1,34 @@
+package common
+
+import "time"
+
+// ActivityKindEvent is the kind used for scheduled calendar events
+const ActivityKindEvent = "event"
+
+// ActivityKindWorklog is the kind used for time logged on an issue (Jira worklogs, Tempo)
+const ActivityKindWorklog = "worklog"
+
+// Activity is a single dated item of work reported by an analyzer.
+// Analyzers populate AnalysisResult.Activities so that cross-analyzer
+// reports can line up the sources day by day.
+type Activity struct {
+	Source   string        `json:"source"`
+	Kind     string        `json:"kind"`
+	ID       string        `json:"id"`
+	Title    string        `json:"title"`
+	URL      string        `json:"url,omitempty"`
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration,omitempty"`
+	Category string        `json:"category,omitempty"`
+	Project  string        `json:"project,omitempty"`
+	Size     int           `json:"size,omitempty"` // changed lines for PRs, words for pages; used for effort estimation
+}
+
+// LocalDay returns the local date of the activity in YYYY-MM-DD format, so that sources reporting UTC times (GitHub,
+// Notion) and calendar events fall on the same day. All-day events are dated midnight UTC and keep their own date.
+func (a Activity) LocalDay() string {
+	if IsAllDayActivity(a) {
+		return a.Time.Format("2006-01-02")
+	}
+	return a.Time.Local().Format("2006-01-02")
+}
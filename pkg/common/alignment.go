@@ -0,0 +1,186 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// heavyCalendarLoad is the scheduled time per day above which a day without
+	// any other activity is considered suspicious
+	heavyCalendarLoad = 4 * time.Hour
+	// minUnscheduledActivities is the number of non-calendar items on a weekday
+	// without calendar events that is considered suspicious
+	minUnscheduledActivities = 3
+)
+
+// DayAlignment holds per-day activity totals across analyzers
+type DayAlignment struct {
+	Date           string
+	ScheduledTime  time.Duration
+	CalendarEvents int
+	SourceCounts   map[string]int
+}
+
+// OtherActivityCount returns the number of non-calendar activities on the day
+func (d *DayAlignment) OtherActivityCount() int {
+	total := 0
+	for _, count := range d.SourceCounts {
+		total += count
+	}
+	return total
+}
+
+// AlignmentReport contains the result of the cross-analyzer consistency check
+type AlignmentReport struct {
+	BusyDaysWithoutActivity []*DayAlignment
+	ActiveDaysWithoutEvents []*DayAlignment
+	SilentSources           []string
+}
+
+// HasIssues returns true if any potential data gap was found
+func (r *AlignmentReport) HasIssues() bool {
+	return len(r.BusyDaysWithoutActivity) > 0 || len(r.ActiveDaysWithoutEvents) > 0 || len(r.SilentSources) > 0
+}
+
+// CheckAlignment compares calendar load with activity from other analyzers day by day.
+// Day-by-day checks are skipped unless both calendar and non-calendar activities exist.
+func CheckAlignment(results []*AnalysisResult) *AlignmentReport {
+	days := make(map[string]*DayAlignment)
+	getDay := func(date string) *DayAlignment {
+		if day, exists := days[date]; exists {
+			return day
+		}
+		day := &DayAlignment{Date: date, SourceCounts: make(map[string]int)}
+		days[date] = day
+		return day
+	}
+
+	hasCalendar := false
+	hasOther := false
+	sourceTotals := make(map[string]int)
+
+	for _, result := range results {
+		if _, exists := sourceTotals[result.AnalyzerName]; !exists {
+			sourceTotals[result.AnalyzerName] = 0
+		}
+
+		for _, activity := range result.Activities {
+			if activity.Time.Before(result.StartDate) || !activity.Time.Before(result.EndDate.AddDate(0, 0, 1)) {
+				continue
+			}
+			sourceTotals[result.AnalyzerName]++
+
+			day := getDay(activity.LocalDay())
+			if activity.Kind == ActivityKindEvent {
+				hasCalendar = true
+				day.CalendarEvents++
+				day.ScheduledTime += activity.Duration
+			} else {
+				hasOther = true
+				day.SourceCounts[result.AnalyzerName]++
+			}
+		}
+	}
+
+	report := &AlignmentReport{}
+
+	for source, total := range sourceTotals {
+		if total == 0 {
+			report.SilentSources = append(report.SilentSources, source)
+		}
+	}
+	sort.Strings(report.SilentSources)
+
+	// Day-by-day comparison requires both calendar and non-calendar data
+	if !hasCalendar || !hasOther {
+		return report
+	}
+
+	var dates []string
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		day := days[date]
+		if day.ScheduledTime >= heavyCalendarLoad && day.OtherActivityCount() == 0 {
+			report.BusyDaysWithoutActivity = append(report.BusyDaysWithoutActivity, day)
+		}
+		if day.CalendarEvents == 0 && day.OtherActivityCount() >= minUnscheduledActivities && isWeekday(date) {
+			report.ActiveDaysWithoutEvents = append(report.ActiveDaysWithoutEvents, day)
+		}
+	}
+
+	return report
+}
+
+// PrintAlignmentReport prints days where calendar load and other activity disagree
+func PrintAlignmentReport(writer io.Writer, report *AlignmentReport) {
+	fmt.Fprintf(writer, "\n%s\n", strings.Repeat("=", 60))
+	fmt.Fprintln(writer, "DATA CONSISTENCY CHECK")
+	fmt.Fprintln(writer, strings.Repeat("=", 60))
+
+	if !report.HasIssues() {
+		fmt.Fprintln(writer, "\n✓ Calendar load and activity from other sources are consistent")
+		return
+	}
+
+	if len(report.BusyDaysWithoutActivity) > 0 {
+		fmt.Fprintf(writer, "\nDays with heavy calendar load (>= %s) but no other activity (%d):\n",
+			FormatDuration(heavyCalendarLoad), len(report.BusyDaysWithoutActivity))
+		for _, day := range report.BusyDaysWithoutActivity {
+			fmt.Fprintf(writer, "- %s: %s scheduled (%d events)\n",
+				formatDayWithWeekday(day.Date), FormatDuration(day.ScheduledTime), day.CalendarEvents)
+		}
+	}
+
+	if len(report.ActiveDaysWithoutEvents) > 0 {
+		fmt.Fprintf(writer, "\nWeekdays with activity but no calendar events (%d):\n", len(report.ActiveDaysWithoutEvents))
+		for _, day := range report.ActiveDaysWithoutEvents {
+			var sources []string
+			for source := range day.SourceCounts {
+				sources = append(sources, source)
+			}
+			sort.Strings(sources)
+
+			var parts []string
+			for _, source := range sources {
+				parts = append(parts, fmt.Sprintf("%s: %d", source, day.SourceCounts[source]))
+			}
+			fmt.Fprintf(writer, "- %s: %d items (%s)\n",
+				formatDayWithWeekday(day.Date), day.OtherActivityCount(), strings.Join(parts, ", "))
+		}
+	}
+
+	if len(report.SilentSources) > 0 {
+		fmt.Fprintln(writer, "\nSources with no activity in the period (check tokens, scopes, and IDs):")
+		for _, source := range report.SilentSources {
+			fmt.Fprintf(writer, "- %s\n", source)
+		}
+	}
+
+	fmt.Fprintln(writer, "\n⚠️  These days may indicate missing data (e.g. missing calendar export or token scope).")
+}
+
+// isWeekday returns true if the YYYY-MM-DD date falls on Monday to Friday
+func isWeekday(date string) bool {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return false
+	}
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// formatDayWithWeekday formats a YYYY-MM-DD date as "YYYY-MM-DD (Mon)"
+func formatDayWithWeekday(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return fmt.Sprintf("%s (%s)", date, t.Format("Mon"))
+}
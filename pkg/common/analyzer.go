@@ -3,7 +3,6 @@ package common
 import (
 	"fmt"
 	"io"
-	"sort"
 	"time"
 )
 
@@ -13,13 +12,38 @@ type Analyzer interface {
 	Analyze(config *Config, writer io.Writer) (*AnalysisResult, error)
 }
 
+// Summarizer is implemented by analyzers that can compute their counts without fetching the items behind them
+// (search totals, count endpoints). -summary-only calls Summarize instead of Analyze.
+type Summarizer interface {
+	Summarize(config *Config, writer io.Writer) (*AnalysisResult, error)
+}
+
+// AnalyzeSummary runs the count-only path of an analyzer for -summary-only. Analyzers without one run in full
+// with their report discarded, so that only the summary is printed.
+func AnalyzeSummary(analyzer Analyzer, config *Config, writer io.Writer) (*AnalysisResult, error) {
+	if summarizer, ok := analyzer.(Summarizer); ok {
+		return summarizer.Summarize(config, writer)
+	}
+	result, err := analyzer.Analyze(config, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+	result.PrintSummary(writer)
+	return result, nil
+}
+
 // AnalysisResult contains the results of an analysis
 type AnalysisResult struct {
-	AnalyzerName string                 `json:"analyzer_name"`
-	StartDate    time.Time              `json:"start_date"`
-	EndDate      time.Time              `json:"end_date"`
-	Summary      map[string]interface{} `json:"summary"`
-	Details      interface{}            `json:"details,omitempty"`
+	AnalyzerName string      `json:"analyzer_name"`
+	StartDate    time.Time   `json:"start_date"`
+	EndDate      time.Time   `json:"end_date"`
+	Metrics      []Metric    `json:"metrics"`
+	Details      interface{} `json:"details,omitempty"`
+	Activities   []Activity  `json:"activities,omitempty"`
+	Warnings     []Warning   `json:"warnings,omitempty"` // optional lookups that failed; the affected items have less detail
+	CSVTables    []CSVTable  `json:"-"`                  // detail lists written as CSV with -output csv
+	// Provenance lists the items counted in each metric, by metric ID (printed with -explain)
+	Provenance map[string][]Activity `json:"-"`
 }
 
 // AnalysisStats contains common statistics
@@ -37,14 +61,7 @@ func (r *AnalysisResult) PrintSummary(writer io.Writer) {
 		r.StartDate.Format("2006-01-02"),
 		r.EndDate.Format("2006-01-02"))
 
-	// Sort summary keys for deterministic output
-	var keys []string
-	for key := range r.Summary {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
-	for _, key := range keys {
-		fmt.Fprintf(writer, "%s: %v\n", key, r.Summary[key])
+	for _, metric := range r.Metrics {
+		fmt.Fprintf(writer, "%s: %v\n", metric.Label, metric.Value)
 	}
 }
@@ -13,16 +13,22 @@ type Config struct {
 	EndDate   time.Time
 }
 
-// LoadConfig loads common configuration from environment variables
+// LoadConfig loads common configuration from environment variables, or from -start/-end/-period when given
 func LoadConfig() (*Config, error) {
 	// Load .env file if it exists
 	godotenv.Load()
 
 	startDateStr := os.Getenv("START_DATE")
+	if dateOverride.start != "" {
+		startDateStr = dateOverride.start
+	}
 	endDateStr := os.Getenv("END_DATE")
+	if dateOverride.end != "" {
+		endDateStr = dateOverride.end
+	}
 
 	if startDateStr == "" || endDateStr == "" {
-		return nil, NewError("Environment variables START_DATE and END_DATE must be set")
+		return nil, NewError("Environment variables START_DATE and END_DATE must be set (or pass -start/-end or -period)")
 	}
 
 	startDate, err := time.Parse("2006-01-02", startDateStr)
@@ -40,3 +46,21 @@ func LoadConfig() (*Config, error) {
 		EndDate:   endDate,
 	}, nil
 }
+
+// PeriodLabel returns the analysis period as used in output directory names (e.g. 2025-01-01_to_2025-03-31)
+func (c *Config) PeriodLabel() string {
+	return c.StartDate.Format("2006-01-02") + "_to_" + c.EndDate.Format("2006-01-02")
+}
+
+// ElapsedFraction returns the share of the period's days that have started by now (1 for a completed period)
+func (c *Config) ElapsedFraction(now time.Time) float64 {
+	totalDays := c.EndDate.Sub(c.StartDate).Hours()/24 + 1
+	elapsedDays := now.Sub(c.StartDate).Hours() / 24
+	if totalDays <= 0 || elapsedDays >= totalDays {
+		return 1
+	}
+	if elapsedDays <= 0 {
+		return 0
+	}
+	return elapsedDays / totalDays
+}
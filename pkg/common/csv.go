@@ -0,0 +1,78 @@
+package common
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CSVTable is a list of detail records (PRs, issues, events, pages, ...) exported as <analyzer>-<name>.csv
+type CSVTable struct {
+	Name   string
+	Header []string
+	Rows   [][]string
+}
+
+// NewCSVTable builds a table from a slice of row structs. Columns are the fields tagged `csv:"column"`, in declaration order.
+// Times are written as RFC 3339, durations as hours, and slices joined with "; ".
+func NewCSVTable(name string, rows interface{}) CSVTable {
+	table := CSVTable{Name: name}
+	value := reflect.ValueOf(rows)
+	if value.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("NewCSVTable: rows must be a slice, got %s", value.Kind()))
+	}
+
+	rowType := value.Type().Elem()
+	var fields []int
+	for i := 0; i < rowType.NumField(); i++ {
+		if column := rowType.Field(i).Tag.Get("csv"); column != "" {
+			table.Header = append(table.Header, column)
+			fields = append(fields, i)
+		}
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		row := make([]string, len(fields))
+		for j, field := range fields {
+			row[j] = formatCSVValue(value.Index(i).Field(field).Interface())
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+func formatCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case time.Duration:
+		return fmt.Sprintf("%.2f", v.Hours())
+	case []string:
+		return strings.Join(v, "; ")
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// WriteCSVFile writes the table with a header row
+func WriteCSVFile(path string, table CSVTable) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return WrapError(err, "failed to create %s", path)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(table.Header); err != nil {
+		return WrapError(err, "failed to write %s", path)
+	}
+	if err := writer.WriteAll(table.Rows); err != nil {
+		return WrapError(err, "failed to write %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// SplitDetail replaces a detail list in AnalysisResult.Details after SplitDetails moved it to disk
+type SplitDetail struct {
+	File  string `json:"file"`
+	Items int    `json:"items"`
+}
+
+// detailLine is one item of a detail list in the JSON Lines file
+type detailLine struct {
+	List string      `json:"list"`
+	Item interface{} `json:"item"`
+}
+
+// SplitDetails writes every list in result.Details (PRs, issues, events, pages, ...) to path as JSON Lines,
+// one {"list": name, "item": ...} per item, and replaces the lists with SplitDetail so that <analyzer>-stats.json
+// stays small. It runs after Analyze, so it doesn't lower peak memory. Aggregates (maps and structs) stay in Details.
+// Returns the number of items written.
+func SplitDetails(path string, result *AnalysisResult) (int, error) {
+	details, ok := result.Details.(map[string]interface{})
+	if !ok {
+		return 0, nil
+	}
+
+	var names []string
+	for name, value := range details {
+		if value != nil && reflect.ValueOf(value).Kind() == reflect.Slice {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return 0, nil
+	}
+	sort.Strings(names)
+
+	// Like the text report this is plain, even with cache encryption enabled
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, WrapError(err, "failed to create %s", path)
+	}
+	defer file.Close()
+	buffered := bufio.NewWriter(file)
+	encoder := json.NewEncoder(buffered)
+
+	written := 0
+	for _, name := range names {
+		list := reflect.ValueOf(details[name])
+		for i := 0; i < list.Len(); i++ {
+			if err := encoder.Encode(detailLine{List: name, Item: list.Index(i).Interface()}); err != nil {
+				return written, WrapError(err, "failed to write %s", path)
+			}
+			written++
+		}
+		details[name] = SplitDetail{File: path, Items: list.Len()}
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return written, WrapError(err, "failed to write %s", path)
+	}
+	return written, nil
+}
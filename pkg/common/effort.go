@@ -0,0 +1,233 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EstimateFunc returns approximate time spent on an activity without a recorded duration.
+// The second value is false when the activity can't be estimated.
+type EstimateFunc func(activity Activity) (time.Duration, bool)
+
+// EffortTotal is the time attributed to one source: measured (calendar) or estimated
+type EffortTotal struct {
+	Source    string
+	Items     int
+	Duration  time.Duration
+	Estimated bool
+}
+
+// EstimateEffort attributes each work item's time to one source so that linked artifacts are counted once.
+// Items with a recorded duration use it; otherwise the largest estimate among their activities is used.
+func EstimateEffort(items []WorkItem, estimate EstimateFunc) []EffortTotal {
+	totals := make(map[string]*EffortTotal)
+	add := func(source string, duration time.Duration, estimated bool) {
+		key := source
+		if estimated {
+			key += " (estimated)"
+		}
+		total, exists := totals[key]
+		if !exists {
+			total = &EffortTotal{Source: source, Estimated: estimated}
+			totals[key] = total
+		}
+		total.Items++
+		total.Duration += duration
+	}
+
+	for _, item := range items {
+		if duration := item.Duration(); duration > 0 {
+			add(item.durationSource(), duration, false)
+			continue
+		}
+		if best, bestSource := item.bestEstimate(estimate); bestSource != "" {
+			add(bestSource, best, true)
+		}
+	}
+
+	var result []EffortTotal
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Estimated != result[j].Estimated {
+			return !result[i].Estimated
+		}
+		if result[i].Duration != result[j].Duration {
+			return result[i].Duration > result[j].Duration
+		}
+		return result[i].Source < result[j].Source
+	})
+	return result
+}
+
+// durationSource returns the source that provides the item's duration: the worklogs when they add up to it,
+// otherwise the longest activity
+func (w WorkItem) durationSource() string {
+	logged := w.LoggedDuration()
+	longest := w.Activities[0]
+	for _, activity := range w.Activities {
+		if logged > 0 && logged == w.Duration() && activity.Kind == ActivityKindWorklog {
+			return activity.Source
+		}
+		if activity.Duration > longest.Duration {
+			longest = activity
+		}
+	}
+	return longest.Source
+}
+
+// bestEstimate returns the largest estimate among the item's activities and its source ("" when none can be estimated)
+func (w WorkItem) bestEstimate(estimate EstimateFunc) (time.Duration, string) {
+	var best time.Duration
+	var bestSource string
+	for _, activity := range w.Activities {
+		if activity.Duration > 0 {
+			continue
+		}
+		if duration, ok := estimate(activity); ok && (bestSource == "" || duration > best) {
+			best, bestSource = duration, activity.Source
+		}
+	}
+	return best, bestSource
+}
+
+// scheduledDuration returns the total duration of the item's calendar events
+func (w WorkItem) scheduledDuration() time.Duration {
+	var scheduled time.Duration
+	for _, activity := range w.Activities {
+		if activity.Kind == ActivityKindEvent {
+			scheduled += activity.Duration
+		}
+	}
+	return scheduled
+}
+
+// PrintEffortEstimate prints measured and estimated hours per source with a combined total.
+// Nothing is printed when no work item has measured or estimated time.
+func PrintEffortEstimate(writer io.Writer, items []WorkItem, estimate EstimateFunc) {
+	totals := EstimateEffort(items, estimate)
+	if len(totals) == 0 {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintln(writer, "ESTIMATED EFFORT")
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	var measured, estimated time.Duration
+	for _, total := range totals {
+		label := "measured"
+		if total.Estimated {
+			label = "estimated"
+			estimated += total.Duration
+		} else {
+			measured += total.Duration
+		}
+		fmt.Fprintf(writer, "- %s (%s): %s, %d work items\n", total.Source, label, FormatDuration(total.Duration), total.Items)
+	}
+
+	fmt.Fprintf(writer, "\nMeasured: %s, estimated: %s, total: %s\n",
+		FormatDuration(measured), FormatDuration(estimated), FormatDuration(measured+estimated))
+}
+
+// maxReconciliationItems is how many logged work items with the largest differences are listed
+const maxReconciliationItems = 10
+
+// LoggedTimeItem compares the time logged on a work item with its calendar events and estimated effort
+type LoggedTimeItem struct {
+	Title     string
+	Logged    time.Duration
+	Scheduled time.Duration
+	Estimated time.Duration
+}
+
+// LoggedTimeReconciliation compares logged time (worklogs) with measured calendar time and estimated effort
+type LoggedTimeReconciliation struct {
+	Items              []LoggedTimeItem // work items with logged time, largest difference first
+	Logged             time.Duration
+	ScheduledLogged    time.Duration // calendar time linked to logged work items
+	EstimatedLogged    time.Duration // estimated time of logged work items
+	ScheduledNotLogged time.Duration // calendar time without linked worklogs
+	EstimatedNotLogged time.Duration // estimated time of work items without worklogs or events
+}
+
+// ReconcileLoggedTime splits calendar and estimated time into work that was logged and work that was not.
+// Returns nil when no work item has logged time.
+func ReconcileLoggedTime(items []WorkItem, estimate EstimateFunc) *LoggedTimeReconciliation {
+	reconciliation := &LoggedTimeReconciliation{}
+	for _, item := range items {
+		logged := item.LoggedDuration()
+		scheduled := item.scheduledDuration()
+		estimated, _ := item.bestEstimate(estimate)
+		if logged == 0 {
+			reconciliation.ScheduledNotLogged += scheduled
+			if scheduled == 0 {
+				reconciliation.EstimatedNotLogged += estimated
+			}
+			continue
+		}
+
+		title := ""
+		for _, activity := range item.Activities {
+			if activity.Kind == ActivityKindWorklog {
+				title = activity.Title
+				break
+			}
+		}
+		reconciliation.Items = append(reconciliation.Items, LoggedTimeItem{Title: title, Logged: logged, Scheduled: scheduled, Estimated: estimated})
+		reconciliation.Logged += logged
+		reconciliation.ScheduledLogged += scheduled
+		reconciliation.EstimatedLogged += estimated
+	}
+	if len(reconciliation.Items) == 0 {
+		return nil
+	}
+
+	difference := func(item LoggedTimeItem) time.Duration {
+		other := item.Scheduled
+		if item.Estimated > other {
+			other = item.Estimated
+		}
+		if item.Logged > other {
+			return item.Logged - other
+		}
+		return other - item.Logged
+	}
+	sort.SliceStable(reconciliation.Items, func(i, j int) bool {
+		return difference(reconciliation.Items[i]) > difference(reconciliation.Items[j])
+	})
+	return reconciliation
+}
+
+// PrintLoggedTimeReconciliation prints logged time next to the calendar and estimated time of the same work,
+// and the time that was not logged. Nothing is printed when there are no worklogs.
+func PrintLoggedTimeReconciliation(writer io.Writer, items []WorkItem, estimate EstimateFunc) {
+	reconciliation := ReconcileLoggedTime(items, estimate)
+	if reconciliation == nil {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n%s\n", strings.Repeat("=", 60))
+	fmt.Fprintln(writer, "LOGGED TIME")
+	fmt.Fprintln(writer, strings.Repeat("=", 60))
+
+	fmt.Fprintf(writer, "Logged: %s on %d work items\n", FormatDuration(reconciliation.Logged), len(reconciliation.Items))
+	fmt.Fprintf(writer, "- linked calendar events: %s\n", FormatDuration(reconciliation.ScheduledLogged))
+	fmt.Fprintf(writer, "- estimated for linked PRs, pages, and tickets: %s\n", FormatDuration(reconciliation.EstimatedLogged))
+	fmt.Fprintf(writer, "Not logged: %s of calendar events, %s estimated for other work items\n",
+		FormatDuration(reconciliation.ScheduledNotLogged), FormatDuration(reconciliation.EstimatedNotLogged))
+
+	fmt.Fprintln(writer, "\nLargest differences (logged / scheduled / estimated):")
+	for i, item := range reconciliation.Items {
+		if i == maxReconciliationItems {
+			fmt.Fprintf(writer, "  ... and %d more\n", len(reconciliation.Items)-maxReconciliationItems)
+			break
+		}
+		fmt.Fprintf(writer, "- %s: %s / %s / %s\n", item.Title,
+			FormatDuration(item.Logged), FormatDuration(item.Scheduled), FormatDuration(item.Estimated))
+	}
+}
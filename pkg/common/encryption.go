@@ -0,0 +1,263 @@
+package common
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptedMagic prefixes files written while cache encryption is enabled
+var encryptedMagic = []byte("DEVSTATS-ENC1\n")
+
+const (
+	saltSize = 16
+	keySize  = 32
+)
+
+var (
+	passphraseOnce sync.Once
+	passphrase     string
+	passphraseErr  error
+
+	derivedKeys   = make(map[string][]byte) // salt -> key, so that each file's key is derived once per run
+	derivedKeysMu sync.Mutex
+
+	// writeSalt is shared by the files written in one run so that scrypt runs once, not per file; nonces stay random
+	writeSaltOnce sync.Once
+	writeSalt     []byte
+	writeSaltErr  error
+)
+
+// CacheEncryptionEnabled reports whether cached and stored data is encrypted at rest
+// (CACHE_PASSPHRASE or CACHE_KEYCHAIN_SERVICE is set)
+func CacheEncryptionEnabled() bool {
+	return os.Getenv("CACHE_PASSPHRASE") != "" || os.Getenv("CACHE_KEYCHAIN_SERVICE") != ""
+}
+
+// cachePassphrase returns CACHE_PASSPHRASE, or the secret stored in the OS keychain under CACHE_KEYCHAIN_SERVICE
+// (macOS Keychain via `security`, Linux Secret Service via `secret-tool`)
+func cachePassphrase() (string, error) {
+	passphraseOnce.Do(func() {
+		if value := os.Getenv("CACHE_PASSPHRASE"); value != "" {
+			passphrase = value
+			return
+		}
+		service := os.Getenv("CACHE_KEYCHAIN_SERVICE")
+		if service == "" {
+			passphraseErr = NewError("cache encryption is not configured (set CACHE_PASSPHRASE or CACHE_KEYCHAIN_SERVICE)")
+			return
+		}
+
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("security", "find-generic-password", "-s", service, "-w")
+		case "linux":
+			cmd = exec.Command("secret-tool", "lookup", "service", service)
+		default:
+			passphraseErr = NewError("CACHE_KEYCHAIN_SERVICE is not supported on %s; use CACHE_PASSPHRASE", runtime.GOOS)
+			return
+		}
+		output, err := cmd.Output()
+		if err != nil {
+			passphraseErr = WrapError(err, "failed to read '%s' from the OS keychain", service)
+			return
+		}
+		passphrase = strings.TrimSpace(string(output))
+		if passphrase == "" {
+			passphraseErr = NewError("keychain item '%s' is empty", service)
+		}
+	})
+	return passphrase, passphraseErr
+}
+
+// deriveKey derives an AES-256 key from the passphrase with scrypt
+func deriveKey(salt []byte) ([]byte, error) {
+	derivedKeysMu.Lock()
+	defer derivedKeysMu.Unlock()
+	if key, exists := derivedKeys[string(salt)]; exists {
+		return key, nil
+	}
+
+	secret, err := cachePassphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key([]byte(secret), salt, 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, WrapError(err, "failed to derive encryption key")
+	}
+	derivedKeys[string(salt)] = key
+	return key, nil
+}
+
+// IsEncrypted reports whether data was written with cache encryption
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// SealIfEnabled encrypts data with AES-256-GCM when cache encryption is enabled; otherwise returns it unchanged
+func SealIfEnabled(data []byte) ([]byte, error) {
+	if !CacheEncryptionEnabled() {
+		return data, nil
+	}
+
+	writeSaltOnce.Do(func() {
+		writeSalt = make([]byte, saltSize)
+		if _, err := rand.Read(writeSalt); err != nil {
+			writeSaltErr = WrapError(err, "failed to generate salt")
+		}
+	})
+	if writeSaltErr != nil {
+		return nil, writeSaltErr
+	}
+	salt := writeSalt
+	key, err := deriveKey(salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, WrapError(err, "failed to generate nonce")
+	}
+
+	sealed := append([]byte{}, encryptedMagic...)
+	sealed = append(sealed, salt...)
+	sealed = append(sealed, nonce...)
+	return gcm.Seal(sealed, nonce, data, encryptedMagic), nil
+}
+
+// Open decrypts data written by SealIfEnabled. Plain data is returned unchanged, so caches written before encryption was enabled stay readable.
+func Open(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+
+	payload := data[len(encryptedMagic):]
+	if len(payload) < saltSize {
+		return nil, NewError("encrypted data is truncated")
+	}
+	key, err := deriveKey(payload[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	payload = payload[saltSize:]
+	if len(payload) < gcm.NonceSize() {
+		return nil, NewError("encrypted data is truncated")
+	}
+	plain, err := gcm.Open(nil, payload[:gcm.NonceSize()], payload[gcm.NonceSize():], encryptedMagic)
+	if err != nil {
+		return nil, NewError("failed to decrypt data (wrong passphrase?)")
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, WrapError(err, "failed to create cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, WrapError(err, "failed to create cipher")
+	}
+	return gcm, nil
+}
+
+// WriteProtectedFile writes data, encrypted when cache encryption is enabled (and then readable only by the owner)
+func WriteProtectedFile(path string, data []byte) error {
+	sealed, err := SealIfEnabled(data)
+	if err != nil {
+		return err
+	}
+	if !IsEncrypted(sealed) {
+		return os.WriteFile(path, sealed, 0644)
+	}
+	if err := os.WriteFile(path, sealed, 0600); err != nil {
+		return err
+	}
+	// os.WriteFile keeps the mode of an existing file, e.g. a cache written before encryption was enabled
+	return os.Chmod(path, 0600)
+}
+
+// ReadProtectedFile reads a file written by WriteProtectedFile
+func ReadProtectedFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Open(data)
+}
+
+// passphraseMagic prefixes data sealed by SealWithPassphrase, which is independent of the cache passphrase
+var passphraseMagic = []byte("DEVSTATS-PASS1\n")
+
+// SealWithPassphrase encrypts data with AES-256-GCM under a key derived from secret with a fresh salt, so that it
+// can be opened on another machine knowing only the passphrase (dev-stats export-bundle)
+func SealWithPassphrase(data []byte, secret string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, WrapError(err, "failed to generate salt")
+	}
+	key, err := scrypt.Key([]byte(secret), salt, 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, WrapError(err, "failed to derive encryption key")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, WrapError(err, "failed to generate nonce")
+	}
+
+	sealed := append([]byte{}, passphraseMagic...)
+	sealed = append(sealed, salt...)
+	sealed = append(sealed, nonce...)
+	return gcm.Seal(sealed, nonce, data, passphraseMagic), nil
+}
+
+// OpenWithPassphrase decrypts data written by SealWithPassphrase
+func OpenWithPassphrase(data []byte, secret string) ([]byte, error) {
+	if !bytes.HasPrefix(data, passphraseMagic) {
+		return nil, NewError("data was not sealed with a passphrase")
+	}
+	payload := data[len(passphraseMagic):]
+	if len(payload) < saltSize {
+		return nil, NewError("encrypted data is truncated")
+	}
+	key, err := scrypt.Key([]byte(secret), payload[:saltSize], 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, WrapError(err, "failed to derive encryption key")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	payload = payload[saltSize:]
+	if len(payload) < gcm.NonceSize() {
+		return nil, NewError("encrypted data is truncated")
+	}
+	plain, err := gcm.Open(nil, payload[:gcm.NonceSize()], payload[gcm.NonceSize():], passphraseMagic)
+	if err != nil {
+		return nil, NewError("failed to decrypt data (wrong passphrase?)")
+	}
+	return plain, nil
+}
@@ -1,6 +1,9 @@
 package common
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // DevStatsError represents an error in the dev-stats application
 type DevStatsError struct {
@@ -15,6 +18,23 @@ func (e *DevStatsError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying cause
+func (e *DevStatsError) Unwrap() error {
+	return e.Cause
+}
+
+// HTTPStatusError represents a non-2xx HTTP response
+type HTTPStatusError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d error for %s %s: %s", e.StatusCode, e.Method, e.URL, e.Body)
+}
+
 // NewError creates a new DevStatsError
 func NewError(format string, args ...interface{}) *DevStatsError {
 	return &DevStatsError{
@@ -29,3 +49,12 @@ func WrapError(cause error, format string, args ...interface{}) *DevStatsError {
 		Cause:   cause,
 	}
 }
+
+// HTTPStatusCode returns the HTTP status code of err, or 0 if err is not an HTTP status error
+func HTTPStatusCode(err error) int {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,114 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Explain records the items counted in a metric so that -explain <metric> can list them
+func (r *AnalysisResult) Explain(metricID string, items []Activity) {
+	if r.Provenance == nil {
+		r.Provenance = make(map[string][]Activity)
+	}
+	r.Provenance[metricID] = items
+}
+
+// ActivitiesOfKind returns the activities with any of the given kinds
+func ActivitiesOfKind(activities []Activity, kinds ...string) []Activity {
+	var matched []Activity
+	for _, activity := range activities {
+		for _, kind := range kinds {
+			if activity.Kind == kind {
+				matched = append(matched, activity)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// matchesMetric reports whether query names the metric by ID (with or without the analyzer prefix) or label
+func matchesMetric(metric Metric, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	id := strings.ToLower(metric.ID)
+	if query == id || query == strings.ToLower(metric.Label) {
+		return true
+	}
+	if dot := strings.Index(id, "."); dot >= 0 && query == id[dot+1:] {
+		return true
+	}
+	return false
+}
+
+// PrintExplanation lists the items behind every metric matching query.
+// It returns false when no metric matched, after listing the metric IDs that can be explained.
+func PrintExplanation(writer io.Writer, results []*AnalysisResult, query string) bool {
+	matched := false
+	for _, result := range results {
+		for _, metric := range result.Metrics {
+			if !matchesMetric(metric, query) {
+				continue
+			}
+			matched = true
+			printMetricItems(writer, result, metric)
+		}
+	}
+	if matched {
+		return true
+	}
+
+	fmt.Fprintf(writer, "\nNo metric matches '%s'. Metrics with an item breakdown:\n", query)
+	for _, result := range results {
+		var ids []string
+		for id := range result.Provenance {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			metric, _ := result.Metric(id)
+			fmt.Fprintf(writer, "  %-40s %s\n", id, metric.Label)
+		}
+	}
+	return false
+}
+
+// printMetricItems prints one metric and the items it counted, oldest first
+func printMetricItems(writer io.Writer, result *AnalysisResult, metric Metric) {
+	value := fmt.Sprintf("%v", metric.Value)
+	if duration, ok := metric.Value.(time.Duration); ok {
+		value = FormatDuration(duration)
+	}
+
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(writer, "EXPLAIN: %s (%s) = %s\n", metric.ID, metric.Label, value)
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	items, exists := result.Provenance[metric.ID]
+	if !exists {
+		fmt.Fprintln(writer, "No item breakdown is recorded for this metric (it is derived from other metrics or aggregate data).")
+		return
+	}
+
+	items = append([]Activity{}, items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Time.Before(items[j].Time)
+	})
+
+	fmt.Fprintf(writer, "%d items:\n", len(items))
+	for _, item := range items {
+		line := fmt.Sprintf("- %s  %s", item.Time.Local().Format("2006-01-02 15:04"), item.Title)
+		if item.Duration > 0 {
+			line += fmt.Sprintf(" (%s)", FormatDuration(item.Duration))
+		}
+		if item.Category != "" {
+			line += fmt.Sprintf(" [%s]", item.Category)
+		}
+		fmt.Fprintln(writer, line)
+		if item.URL != "" {
+			fmt.Fprintf(writer, "  %s\n", item.URL)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Extrapolate projects a metric to the end of the period at the current run rate.
+// The second value is false for non-numeric and snapshot metrics.
+func (m Metric) Extrapolate(elapsedFraction float64) (Metric, bool) {
+	if m.Snapshot || elapsedFraction <= 0 {
+		return Metric{}, false
+	}
+	projected := m
+	switch value := m.Value.(type) {
+	case int:
+		projected.Value = int(float64(value)/elapsedFraction + 0.5)
+	case time.Duration:
+		projected.Value = time.Duration(float64(value) / elapsedFraction)
+	case float64:
+		projected.Value = value / elapsedFraction
+	default:
+		return Metric{}, false
+	}
+	return projected, true
+}
+
+// PrintRunRate annotates metrics of an incomplete period with run-rate extrapolations ("on pace for 42")
+func PrintRunRate(writer io.Writer, results []*AnalysisResult, elapsedFraction float64) {
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(writer, "RUN RATE (%.0f%% of period elapsed)\n", elapsedFraction*100)
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	if elapsedFraction >= 1 {
+		fmt.Fprintln(writer, "The period is complete; nothing to extrapolate.")
+		return
+	}
+	if elapsedFraction <= 0 {
+		fmt.Fprintln(writer, "The period has not started yet; nothing to extrapolate.")
+		return
+	}
+
+	for _, result := range results {
+		fmt.Fprintf(writer, "\n%s:\n", result.AnalyzerName)
+		for _, metric := range result.Metrics {
+			projected, ok := metric.Extrapolate(elapsedFraction)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(writer, "- %s: %s so far, on pace for %s\n", metric.Label, formatMetricValue(metric.Value), formatMetricValue(projected.Value))
+		}
+	}
+}
+
+// formatMetricValue formats durations and fractional values for display
+func formatMetricValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Duration:
+		return FormatDuration(v)
+	case float64:
+		return fmt.Sprintf("%.1f", v)
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,17 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatDuration formats duration in a human-readable way (e.g. 5h30m)
+func FormatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,177 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// codeSources are the analyzers whose activity counts as a day with commits
+var codeSources = []string{"GitHub", "Backlog"}
+
+// defaultWeeklyGoal is the number of active days per week needed to meet the weekly goal
+const defaultWeeklyGoal = 4
+
+// Streaks summarizes consecutive active days and weeks from the stored history
+type Streaks struct {
+	CurrentCodeStreak int
+	LongestCodeStreak int
+	WeeklyGoal        int
+	GoalWeekStreak    int // consecutive weeks (WEEK_START) meeting the goal, ending at the period's last week
+	LongestGoalWeeks  int
+}
+
+// Badge is a small award shown in the gamification section
+type Badge struct {
+	Icon        string
+	Name        string
+	Description string
+}
+
+// WeeklyGoalFromEnv returns GAMIFICATION_WEEKLY_GOAL (active days per week), defaulting to 4
+func WeeklyGoalFromEnv() int {
+	if goal, err := strconv.Atoi(os.Getenv("GAMIFICATION_WEEKLY_GOAL")); err == nil && goal > 0 && goal <= 7 {
+		return goal
+	}
+	return defaultWeeklyGoal
+}
+
+// ComputeStreaks computes streaks up to endDate from the stored history, with weeks bucketed by weeks
+func ComputeStreaks(history *History, endDate time.Time, weeklyGoal int, weeks WeekConfig) Streaks {
+	streaks := Streaks{WeeklyGoal: weeklyGoal}
+	end := endDate.Format("2006-01-02")
+
+	codeDays := make(map[string]bool)
+	for _, day := range history.ActiveDays(codeSources...) {
+		if key := day.Format("2006-01-02"); key <= end {
+			codeDays[key] = true
+		}
+	}
+	streaks.LongestCodeStreak = longestRun(history.ActiveDays(codeSources...), endDate)
+
+	// Today isn't over yet, so an inactive end day doesn't break the current streak
+	day := endDate
+	if !codeDays[day.Format("2006-01-02")] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for codeDays[day.Format("2006-01-02")] {
+		streaks.CurrentCodeStreak++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	weekActiveDays := make(map[string]int)
+	var firstWeek time.Time
+	for _, day := range history.ActiveDays() {
+		if day.Format("2006-01-02") > end {
+			continue
+		}
+		week := weeks.WeekStart(day)
+		if firstWeek.IsZero() || week.Before(firstWeek) {
+			firstWeek = week
+		}
+		weekActiveDays[week.Format("2006-01-02")]++
+	}
+	if firstWeek.IsZero() {
+		return streaks
+	}
+
+	run := 0
+	lastWeek := weeks.WeekStart(endDate)
+	for week := firstWeek; !week.After(lastWeek); week = week.AddDate(0, 0, 7) {
+		if weekActiveDays[week.Format("2006-01-02")] >= weeklyGoal {
+			run++
+		} else if !week.Equal(lastWeek) {
+			// The last week may still be in progress, so only completed weeks reset the run
+			run = 0
+		}
+		if run > streaks.LongestGoalWeeks {
+			streaks.LongestGoalWeeks = run
+		}
+	}
+	streaks.GoalWeekStreak = run
+	return streaks
+}
+
+// longestRun returns the longest run of consecutive days in sorted days, up to endDate
+func longestRun(days []time.Time, endDate time.Time) int {
+	longest, run := 0, 0
+	var previous time.Time
+	for _, day := range days {
+		if day.Format("2006-01-02") > endDate.Format("2006-01-02") {
+			break
+		}
+		if !previous.IsZero() && previous.AddDate(0, 0, 1).Format("2006-01-02") == day.Format("2006-01-02") {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		previous = day
+	}
+	return longest
+}
+
+// AwardBadges returns the badges earned from the streaks and the period's activities
+func AwardBadges(streaks Streaks, activities []Activity) []Badge {
+	var badges []Badge
+	if streaks.CurrentCodeStreak >= 5 {
+		badges = append(badges, Badge{"🔥", "On Fire", fmt.Sprintf("%d-day commit streak in progress", streaks.CurrentCodeStreak)})
+	}
+	if streaks.LongestCodeStreak >= 14 {
+		badges = append(badges, Badge{"🏃", "Marathoner", fmt.Sprintf("longest commit streak of %d days", streaks.LongestCodeStreak)})
+	}
+	if streaks.GoalWeekStreak >= 4 {
+		badges = append(badges, Badge{"📅", "Goal Keeper", fmt.Sprintf("weekly goal met %d weeks in a row", streaks.GoalWeekStreak)})
+	}
+	if len(activities) >= 100 {
+		badges = append(badges, Badge{"💯", "Centurion", fmt.Sprintf("%d activities this period", len(activities))})
+	}
+
+	nightOwl, earlyBird, weekend := 0, 0, 0
+	for _, activity := range activities {
+		local := activity.Time.Local()
+		if local.Hour() >= 22 || local.Hour() < 4 {
+			nightOwl++
+		} else if local.Hour() < 7 {
+			earlyBird++
+		}
+		if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+			weekend++
+		}
+	}
+	if nightOwl >= 5 {
+		badges = append(badges, Badge{"🦉", "Night Owl", fmt.Sprintf("%d activities between 22:00 and 04:00", nightOwl)})
+	}
+	if earlyBird >= 5 {
+		badges = append(badges, Badge{"🌅", "Early Bird", fmt.Sprintf("%d activities before 07:00", earlyBird)})
+	}
+	if len(activities) > 0 && weekend == 0 {
+		badges = append(badges, Badge{"🏖️", "Weekend Guardian", "no activity on weekends"})
+	}
+	return badges
+}
+
+// PrintGamification prints the streaks and badges section
+func PrintGamification(writer io.Writer, streaks Streaks, badges []Badge) {
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(writer, "STREAKS & BADGES\n")
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	fmt.Fprintf(writer, "- Commit streak: %d days (longest: %d)\n", streaks.CurrentCodeStreak, streaks.LongestCodeStreak)
+	fmt.Fprintf(writer, "- Weeks meeting goal (%d active days): %d in a row (longest: %d)\n",
+		streaks.WeeklyGoal, streaks.GoalWeekStreak, streaks.LongestGoalWeeks)
+
+	if len(badges) == 0 {
+		fmt.Fprintln(writer, "\nNo badges yet - keep going!")
+		return
+	}
+	fmt.Fprintln(writer, "\nBadges:")
+	for _, badge := range badges {
+		fmt.Fprintf(writer, "%s %s - %s\n", badge.Icon, badge.Name, badge.Description)
+	}
+}
@@ -0,0 +1,94 @@
+package common
+
+import (
+	"os"
+	"sort"
+	"time"
+)
+
+// DefaultHistoryPath stores per-day activity counts across runs and periods
+const DefaultHistoryPath = "storage/history.json"
+
+// History holds activity counts per day (YYYY-MM-DD) and source
+type History struct {
+	Days map[string]map[string]int `json:"days"`
+}
+
+// LoadHistory loads stored history. A missing file means no history has been recorded.
+func LoadHistory(path string) (*History, error) {
+	history := &History{Days: make(map[string]map[string]int)}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return history, nil
+	}
+	if err := ReadJSONFile(path, history); err != nil {
+		return nil, err
+	}
+	if history.Days == nil {
+		history.Days = make(map[string]map[string]int)
+	}
+	return history, nil
+}
+
+// Record replaces the counts of each result's source within its period, so re-running a period doesn't double count
+func (h *History) Record(results []*AnalysisResult) {
+	for _, result := range results {
+		for day := result.StartDate; !day.After(result.EndDate); day = day.AddDate(0, 0, 1) {
+			if counts, exists := h.Days[day.Format("2006-01-02")]; exists {
+				delete(counts, result.AnalyzerName)
+			}
+		}
+		for _, activity := range result.Activities {
+			day := activity.Time.Local().Format("2006-01-02")
+			if day < result.StartDate.Format("2006-01-02") || day > result.EndDate.Format("2006-01-02") {
+				continue
+			}
+			if h.Days[day] == nil {
+				h.Days[day] = make(map[string]int)
+			}
+			h.Days[day][result.AnalyzerName]++
+		}
+	}
+	for day, counts := range h.Days {
+		if len(counts) == 0 {
+			delete(h.Days, day)
+		}
+	}
+}
+
+// Save writes the history
+func (h *History) Save(path string) error {
+	return WriteJSONFile(path, h)
+}
+
+// ActiveDays returns sorted days with at least one activity from any of sources (all sources if none given)
+func (h *History) ActiveDays(sources ...string) []time.Time {
+	var days []time.Time
+	for day, counts := range h.Days {
+		active := false
+		for source, count := range counts {
+			if count > 0 && (len(sources) == 0 || containsString(sources, source)) {
+				active = true
+				break
+			}
+		}
+		if !active {
+			continue
+		}
+		if date, err := time.ParseInLocation("2006-01-02", day, time.Local); err == nil {
+			days = append(days, date)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+	return days
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -9,15 +10,36 @@ import (
 
 // HTTPClient provides a common HTTP client interface
 type HTTPClient struct {
-	client  *http.Client
-	headers map[string]string
+	client    *http.Client
+	headers   map[string]string
+	rateLimit *rateLimiter
+	throttle  *tokenBucket
+	noCache   bool
+}
+
+// defaultTransport is used by clients created afterwards (nil means http.DefaultTransport)
+var defaultTransport http.RoundTripper
+
+// SetDefaultTransport makes new clients send requests through transport and returns the previous one.
+// The snapshot harness uses it to serve recorded API responses to unmodified analyzers.
+func SetDefaultTransport(transport http.RoundTripper) http.RoundTripper {
+	previous := defaultTransport
+	defaultTransport = transport
+	return previous
+}
+
+// DefaultTransport returns the transport set by SetDefaultTransport (nil means http.DefaultTransport), for clients
+// that other libraries build, such as the Google API clients
+func DefaultTransport() http.RoundTripper {
+	return defaultTransport
 }
 
 // NewHTTPClient creates a new HTTP client with common settings
 func NewHTTPClient() *HTTPClient {
 	return &HTTPClient{
 		client: &http.Client{
-			Timeout: 120 * time.Second, // Increase timeout to 2 minutes
+			Timeout:   120 * time.Second, // Increase timeout to 2 minutes
+			Transport: defaultTransport,
 		},
 		headers: make(map[string]string),
 	}
@@ -33,47 +55,125 @@ func (c *HTTPClient) SetTimeout(timeout time.Duration) {
 	c.client.Timeout = timeout
 }
 
+// DisableCache keeps the client's responses out of the HTTP response cache, for requests signed per call (S3)
+// whose cache keys never repeat
+func (c *HTTPClient) DisableCache() {
+	c.noCache = true
+}
+
 // Get performs a GET request
 func (c *HTTPClient) Get(url string, headers map[string]string) ([]byte, error) {
-	return c.makeRequest("GET", url, nil, headers)
+	body, _, err := c.makeRequest("GET", url, "", headers)
+	return body, err
+}
+
+// GetWithResponseHeaders performs a GET request and also returns the response headers
+func (c *HTTPClient) GetWithResponseHeaders(url string, headers map[string]string) ([]byte, http.Header, error) {
+	return c.makeRequest("GET", url, "", headers)
 }
 
 // Post performs a POST request
 func (c *HTTPClient) Post(url string, body string, headers map[string]string) ([]byte, error) {
-	return c.makeRequest("POST", url, strings.NewReader(body), headers)
+	responseBody, _, err := c.makeRequest("POST", url, body, headers)
+	return responseBody, err
 }
 
-// makeRequest performs an HTTP request with common error handling
-func (c *HTTPClient) makeRequest(method, url string, body io.Reader, headers map[string]string) ([]byte, error) {
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, WrapError(err, "failed to create %s request to %s", method, url)
-	}
+// Put performs a PUT request
+func (c *HTTPClient) Put(url string, body string, headers map[string]string) ([]byte, error) {
+	responseBody, _, err := c.makeRequest("PUT", url, body, headers)
+	return responseBody, err
+}
 
-	// Set default headers
-	for key, value := range c.headers {
-		req.Header.Set(key, value)
-	}
+// makeRequest performs an HTTP request with common error handling, retrying after rate limits when enabled
+func (c *HTTPClient) makeRequest(method, url string, body string, headers map[string]string) ([]byte, http.Header, error) {
+	for {
+		var reader io.Reader
+		if method != "GET" {
+			reader = strings.NewReader(body)
+		}
+		req, err := http.NewRequest(method, url, reader)
+		if err != nil {
+			return nil, nil, WrapError(err, "failed to create %s request to %s", method, url)
+		}
 
-	// Set request-specific headers
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
+		// Set default headers
+		for key, value := range c.headers {
+			req.Header.Set(key, value)
+		}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, WrapError(err, "failed to execute %s request to %s", method, url)
-	}
-	defer resp.Body.Close()
+		// Set request-specific headers
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, WrapError(err, "failed to read response body")
-	}
+		// GET responses are cached; a fresh entry is reused as is, an older one is revalidated
+		var cacheKey string
+		var cached *httpCacheEntry
+		if method == "GET" && !httpCacheDisabled && !c.noCache {
+			cacheKey = httpCacheKey(req)
+			if cached = loadHTTPCacheEntry(cacheKey); cached != nil {
+				if ttl := httpCacheTTL(); ttl > 0 && time.Since(cached.FetchedAt) < ttl {
+					return cached.Body, cached.Header, nil
+				}
+				if cached.ETag != "" {
+					req.Header.Set("If-None-Match", cached.ETag)
+				}
+				if cached.LastModified != "" {
+					req.Header.Set("If-Modified-Since", cached.LastModified)
+				}
+			}
+		}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, NewError("HTTP %d error for %s %s: %s", resp.StatusCode, method, url, string(responseBody))
-	}
+		if c.rateLimit != nil {
+			c.rateLimit.beforeRequest(req.URL.Host)
+		}
+		if c.throttle != nil {
+			c.throttle.wait()
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, nil, WrapError(err, "failed to execute %s request to %s", method, url)
+		}
+
+		responseBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, WrapError(err, "failed to read response body")
+		}
 
-	return responseBody, nil
+		if c.rateLimit != nil {
+			if wait, retry := c.rateLimit.afterResponse(req.URL.Host, resp.StatusCode, resp.Header, responseBody); retry {
+				c.rateLimit.sleep(req.URL.Host, wait, fmt.Sprintf("HTTP %d", resp.StatusCode))
+				continue
+			}
+		}
+
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			cached.FetchedAt = time.Now()
+			saveHTTPCacheEntry(cacheKey, cached)
+			return cached.Body, cached.Header, nil
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, resp.Header, &HTTPStatusError{
+				StatusCode: resp.StatusCode,
+				Method:     method,
+				URL:        url,
+				Body:       string(responseBody),
+			}
+		}
+
+		if cacheKey != "" && cacheableResponse(resp.Header) {
+			saveHTTPCacheEntry(cacheKey, &httpCacheEntry{
+				ETag:         resp.Header.Get("ETag"),
+				LastModified: resp.Header.Get("Last-Modified"),
+				Header:       resp.Header,
+				Body:         responseBody,
+				FetchedAt:    time.Now(),
+			})
+		}
+
+		return responseBody, resp.Header, nil
+	}
 }
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// HTTPCacheDir holds GET responses reused across runs (managed by `dev-stats cache`)
+const HTTPCacheDir = ".http-cache"
+
+// httpCacheDisabled is set by -no-cache
+var httpCacheDisabled bool
+
+// DisableHTTPCache makes every client bypass the response cache for this run (-no-cache)
+func DisableHTTPCache() {
+	httpCacheDisabled = true
+}
+
+// httpCacheTTL reads HTTP_CACHE_TTL_MINUTES: cached responses younger than this are reused without a request.
+// The default 0 always revalidates with If-None-Match/If-Modified-Since, so results are never stale.
+func httpCacheTTL() time.Duration {
+	if value := os.Getenv("HTTP_CACHE_TTL_MINUTES"); value != "" {
+		if minutes, err := strconv.Atoi(value); err == nil && minutes > 0 {
+			return time.Duration(minutes) * time.Minute
+		}
+	}
+	return 0
+}
+
+// httpCacheEntry is a cached GET response with its validators
+type httpCacheEntry struct {
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	FetchedAt    time.Time   `json:"fetched_at"`
+}
+
+// httpCacheKey identifies a request by URL (including the query) and headers, so that different credentials never share entries.
+// Only the hash is stored: URLs and headers may contain API keys.
+func httpCacheKey(req *http.Request) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.String() + "\n"))
+	var names []string
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			hash.Write([]byte(name + ": " + value + "\n"))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func httpCachePath(key string) string {
+	return filepath.Join(HTTPCacheDir, key[:2], key+".json")
+}
+
+// loadHTTPCacheEntry returns the cached response, or nil if there is none or it can't be read
+func loadHTTPCacheEntry(key string) *httpCacheEntry {
+	data, err := ReadProtectedFile(httpCachePath(key))
+	if err != nil {
+		return nil
+	}
+	var entry httpCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// saveHTTPCacheEntry stores a response. The cache is best-effort, so failures are ignored.
+func saveHTTPCacheEntry(key string, entry *httpCacheEntry) {
+	path := httpCachePath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_ = WriteProtectedFile(path, data)
+}
+
+// cacheableResponse reports whether a response is worth storing: it can be revalidated, or a TTL lets it be reused as is
+func cacheableResponse(header http.Header) bool {
+	return header.Get("ETag") != "" || header.Get("Last-Modified") != "" || httpCacheTTL() > 0
+}
@@ -0,0 +1,40 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Identity is the account that an analyzer's credentials belong to
+type Identity struct {
+	Source string
+	ID     string // ID to put in .env (e.g. BACKLOG_<PROFILE>_USER_ID, NOTION_USER_ID)
+	Login  string // username or email
+	Name   string
+	Note   string // e.g. a mismatch with the configured ID
+}
+
+// IdentityResolver is implemented by analyzers that can look up whose credentials they use (dev-stats whoami)
+type IdentityResolver interface {
+	WhoAmI(writer io.Writer) (*Identity, error)
+}
+
+// PrintIdentities prints the resolved accounts as a table
+func PrintIdentities(writer io.Writer, identities []*Identity) {
+	fmt.Fprintf(writer, "\n%-20s %-28s %-24s %s\n", "Source", "ID", "Login", "Name")
+	fmt.Fprintln(writer, strings.Repeat("-", 90))
+	for _, identity := range identities {
+		fmt.Fprintf(writer, "%-20s %-28s %-24s %s\n", identity.Source, orDash(identity.ID), orDash(identity.Login), orDash(identity.Name))
+		if identity.Note != "" {
+			fmt.Fprintf(writer, "  ⚠️  %s\n", identity.Note)
+		}
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// defaultKudosKeywords and defaultKudosEmoji are used when KUDOS_KEYWORDS / KUDOS_EMOJI are not set
+const (
+	defaultKudosKeywords = "thanks,thank you,thx,great work,nice work,great job,nice job,awesome,kudos,ありがとう,助かり,感謝,さすが"
+	defaultKudosEmoji    = ":pray:,:tada:,:clap:,:raised_hands:,:heart:,:muscle:,🙏,🎉,👏,🙌,❤️,💪"
+)
+
+// maxQuoteLength limits quotes in the recognition appendix
+const maxQuoteLength = 200
+
+// Kudos is a message thanking or praising the user
+type Kudos struct {
+	Source string    `json:"source"`
+	Author string    `json:"author"`
+	Quote  string    `json:"quote"`
+	URL    string    `json:"url"`
+	Time   time.Time `json:"time"`
+}
+
+// KudosMatcher detects thanks/kudos by keyword or emoji
+type KudosMatcher struct {
+	terms []string
+}
+
+// KudosMatcherFromEnv builds a matcher from comma-separated KUDOS_KEYWORDS and KUDOS_EMOJI
+func KudosMatcherFromEnv() *KudosMatcher {
+	keywords := os.Getenv("KUDOS_KEYWORDS")
+	if keywords == "" {
+		keywords = defaultKudosKeywords
+	}
+	emoji := os.Getenv("KUDOS_EMOJI")
+	if emoji == "" {
+		emoji = defaultKudosEmoji
+	}
+
+	matcher := &KudosMatcher{}
+	for _, term := range strings.Split(keywords+","+emoji, ",") {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			matcher.terms = append(matcher.terms, term)
+		}
+	}
+	return matcher
+}
+
+// Matches reports whether text contains any kudos keyword or emoji (case-insensitive)
+func (m *KudosMatcher) Matches(text string) bool {
+	lower := strings.ToLower(text)
+	for _, term := range m.terms {
+		if strings.Contains(lower, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// KudosQuote shortens a message to a single-line quote
+func KudosQuote(text string) string {
+	quote := strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(quote) > maxQuoteLength {
+		quote = string([]rune(quote)[:maxQuoteLength]) + "…"
+	}
+	return quote
+}
+
+// PrintRecognition prints the "recognition received" appendix, oldest first
+func PrintRecognition(writer io.Writer, kudos []Kudos) {
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(writer, "RECOGNITION RECEIVED (%d)\n", len(kudos))
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	if len(kudos) == 0 {
+		fmt.Fprintln(writer, "No thanks or kudos found in the period")
+		return
+	}
+
+	sorted := append([]Kudos{}, kudos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+	for _, k := range sorted {
+		fmt.Fprintf(writer, "- %s [%s] %s: \"%s\"\n", k.Time.Local().Format("2006-01-02"), k.Source, k.Author, k.Quote)
+		if k.URL != "" {
+			fmt.Fprintf(writer, "  %s\n", k.URL)
+		}
+	}
+}
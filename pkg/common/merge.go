@@ -0,0 +1,41 @@
+package common
+
+// MergeResults combines results of the same analyzer run for several accounts into one result.
+// Counter metrics are summed by ID; Snapshot metrics (rates, medians, distinct counts) can't be
+// combined from the per-account values and are left out. Details are kept per account.
+func MergeResults(name string, results []*AnalysisResult) *AnalysisResult {
+	merged := &AnalysisResult{AnalyzerName: name}
+	details := make(map[string]interface{})
+	metricIndex := make(map[string]int)
+
+	for _, result := range results {
+		if merged.StartDate.IsZero() || result.StartDate.Before(merged.StartDate) {
+			merged.StartDate = result.StartDate
+		}
+		if result.EndDate.After(merged.EndDate) {
+			merged.EndDate = result.EndDate
+		}
+
+		for _, metric := range result.Metrics {
+			value, isCount := metric.Value.(int)
+			if metric.Snapshot || !isCount {
+				continue
+			}
+			if index, exists := metricIndex[metric.ID]; exists {
+				merged.Metrics[index].Value = merged.Metrics[index].Value.(int) + value
+				continue
+			}
+			metricIndex[metric.ID] = len(merged.Metrics)
+			merged.Metrics = append(merged.Metrics, metric)
+		}
+
+		for id, activities := range result.Provenance {
+			merged.Explain(id, append(merged.Provenance[id], activities...))
+		}
+		details[result.AnalyzerName] = result.Details
+		merged.Activities = append(merged.Activities, result.Activities...)
+		merged.Warnings = append(merged.Warnings, result.Warnings...)
+	}
+	merged.Details = details
+	return merged
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Metric is a summary value with a stable machine ID (e.g. github.prs_authored) and a display label.
+// Comparisons and exports should reference metrics by ID since labels may be reworded.
+type Metric struct {
+	ID    string      `json:"id"`
+	Label string      `json:"label"`
+	Value interface{} `json:"value"`
+	// Snapshot marks values that don't accumulate over the period (peaks, distinct counts) and can't be extrapolated
+	Snapshot bool `json:"snapshot,omitempty"`
+}
+
+// Number returns the metric value as a number; durations are converted to hours
+func (m Metric) Number() (float64, bool) {
+	switch value := m.Value.(type) {
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	case time.Duration:
+		return value.Hours(), true
+	}
+	return 0, false
+}
+
+// MarshalJSON writes durations as hours so that exported values don't depend on Go's nanosecond representation
+func (m Metric) MarshalJSON() ([]byte, error) {
+	type plain Metric
+	if duration, ok := m.Value.(time.Duration); ok {
+		m.Value = duration.Hours()
+	}
+	return json.Marshal(plain(m))
+}
+
+// Metric returns the metric with the given ID
+func (r *AnalysisResult) Metric(id string) (Metric, bool) {
+	for _, metric := range r.Metrics {
+		if metric.ID == id {
+			return metric, true
+		}
+	}
+	return Metric{}, false
+}
@@ -0,0 +1,160 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OnCallDetailsKey is the AnalysisResult.Details key under which on-call analyzers (Opsgenie, ...) store *OnCallStats
+const OnCallDetailsKey = "oncall"
+
+// Alerts raised outside these local hours or on weekends count as off-hours
+const (
+	workdayStartHour = 9
+	workdayEndHour   = 18
+)
+
+// OnCallShift is a period the user was on call for a schedule, clipped to the analysis period
+type OnCallShift struct {
+	Schedule string    `json:"schedule"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+// Duration returns the length of the shift
+func (s OnCallShift) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// OnCallAlert is an alert the user acknowledged or closed
+type OnCallAlert struct {
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Priority       string    `json:"priority,omitempty"`
+	URL            string    `json:"url,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	AcknowledgedAt time.Time `json:"acknowledged_at,omitempty"` // zero when the user did not acknowledge it
+	Closed         bool      `json:"closed"`                    // closed by the user
+}
+
+// OnCallStats are the shifts and alerts of one on-call source
+type OnCallStats struct {
+	Shifts []OnCallShift `json:"shifts"`
+	Alerts []OnCallAlert `json:"alerts"`
+}
+
+// ShiftDuration returns the total time on call
+func (s *OnCallStats) ShiftDuration() time.Duration {
+	var total time.Duration
+	for _, shift := range s.Shifts {
+		total += shift.Duration()
+	}
+	return total
+}
+
+// OffHoursAlerts returns the number of alerts raised on weekends or outside working hours
+func (s *OnCallStats) OffHoursAlerts() int {
+	count := 0
+	for _, alert := range s.Alerts {
+		if isOffHours(alert.CreatedAt) {
+			count++
+		}
+	}
+	return count
+}
+
+// MeanTimeToAcknowledge returns the mean time from alert to the user's acknowledgement, rounded to the minute
+func (s *OnCallStats) MeanTimeToAcknowledge() time.Duration {
+	var total time.Duration
+	count := 0
+	for _, alert := range s.Alerts {
+		if !alert.AcknowledgedAt.IsZero() {
+			total += alert.AcknowledgedAt.Sub(alert.CreatedAt)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return (total / time.Duration(count)).Round(time.Minute)
+}
+
+// isOffHours reports whether t falls on a weekend or outside working hours (local time)
+func isOffHours(t time.Time) bool {
+	local := t.Local()
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return true
+	}
+	return local.Hour() < workdayStartHour || local.Hour() >= workdayEndHour
+}
+
+// ClipShift limits a shift to the analysis period (end date inclusive); the second value is false when they don't overlap
+func ClipShift(shift OnCallShift, startDate, endDate time.Time) (OnCallShift, bool) {
+	periodEnd := endDate.AddDate(0, 0, 1)
+	if shift.Start.Before(startDate) {
+		shift.Start = startDate
+	}
+	if shift.End.After(periodEnd) {
+		shift.End = periodEnd
+	}
+	return shift, shift.End.After(shift.Start)
+}
+
+// PrintOnCallReport prints one ON-CALL section for every source that reported shifts or alerts:
+// time on call, alerts handled, off-hours alerts, and alerts per priority. Nothing is printed without on-call data.
+func PrintOnCallReport(writer io.Writer, results []*AnalysisResult) {
+	var sources []string
+	stats := make(map[string]*OnCallStats)
+	for _, result := range results {
+		details, ok := result.Details.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		onCall, ok := details[OnCallDetailsKey].(*OnCallStats)
+		if !ok || (len(onCall.Shifts) == 0 && len(onCall.Alerts) == 0) {
+			continue
+		}
+		sources = append(sources, result.AnalyzerName)
+		stats[result.AnalyzerName] = onCall
+	}
+	if len(sources) == 0 {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n%s\n", strings.Repeat("=", 60))
+	fmt.Fprintln(writer, "ON-CALL")
+	fmt.Fprintln(writer, strings.Repeat("=", 60))
+
+	for _, source := range sources {
+		onCall := stats[source]
+		fmt.Fprintf(writer, "\n%s:\n", source)
+		fmt.Fprintf(writer, "- On call: %s in %d shifts\n", FormatDuration(onCall.ShiftDuration()), len(onCall.Shifts))
+		fmt.Fprintf(writer, "- Alerts handled: %d (%d off-hours)\n", len(onCall.Alerts), onCall.OffHoursAlerts())
+		if mtta := onCall.MeanTimeToAcknowledge(); mtta > 0 {
+			fmt.Fprintf(writer, "- Mean time to acknowledge: %s\n", FormatDuration(mtta))
+		}
+
+		byPriority := make(map[string]int)
+		var priorities []string
+		for _, alert := range onCall.Alerts {
+			priority := alert.Priority
+			if priority == "" {
+				priority = "none"
+			}
+			if byPriority[priority] == 0 {
+				priorities = append(priorities, priority)
+			}
+			byPriority[priority]++
+		}
+		sort.Strings(priorities)
+		for i, priority := range priorities {
+			priorities[i] = fmt.Sprintf("%s: %d", priority, byPriority[priority])
+		}
+		if len(priorities) > 0 {
+			fmt.Fprintf(writer, "- Alerts per priority: %s\n", strings.Join(priorities, ", "))
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FileLimits caps the size of each text stats file; 0 disables a limit
+type FileLimits struct {
+	MaxLines int
+	MaxBytes int64
+}
+
+// DefaultFileLimits keeps stats files small enough for editors to open comfortably
+var DefaultFileLimits = FileLimits{MaxLines: 50000, MaxBytes: 5 << 20}
+
+// FileLimitsFromEnv reads STATS_MAX_FILE_LINES and STATS_MAX_FILE_MB.
+// Invalid values return the defaults with an error describing the problem.
+func FileLimitsFromEnv() (FileLimits, error) {
+	limits := DefaultFileLimits
+
+	if value := strings.TrimSpace(os.Getenv("STATS_MAX_FILE_LINES")); value != "" {
+		lines, err := strconv.Atoi(value)
+		if err != nil || lines < 0 {
+			return DefaultFileLimits, NewError("STATS_MAX_FILE_LINES must be a number of lines (0 for no limit), got '%s'", value)
+		}
+		limits.MaxLines = lines
+	}
+	if value := strings.TrimSpace(os.Getenv("STATS_MAX_FILE_MB")); value != "" {
+		megabytes, err := strconv.Atoi(value)
+		if err != nil || megabytes < 0 {
+			return DefaultFileLimits, NewError("STATS_MAX_FILE_MB must be a number of megabytes (0 for no limit), got '%s'", value)
+		}
+		limits.MaxBytes = int64(megabytes) << 20
+	}
+	return limits, nil
+}
+
+// Describe returns the limits for messages, e.g. "50000 lines / 5 MB"
+func (l FileLimits) Describe() string {
+	var parts []string
+	if l.MaxLines > 0 {
+		parts = append(parts, fmt.Sprintf("%d lines", l.MaxLines))
+	}
+	if l.MaxBytes > 0 {
+		parts = append(parts, fmt.Sprintf("%d MB", l.MaxBytes>>20))
+	}
+	if len(parts) == 0 {
+		return "no limit"
+	}
+	return strings.Join(parts, " / ")
+}
+
+// exceeded reports whether a page with the given lines and bytes is full
+func (l FileLimits) exceeded(lines int, size int64) bool {
+	return (l.MaxLines > 0 && lines >= l.MaxLines) || (l.MaxBytes > 0 && size >= l.MaxBytes)
+}
+
+// PagedFile writes an analyzer's text report to <name>-stats.txt. Reports that stay within the limits are written
+// as they are. Larger reports move the detailed listing to <name>-stats-details-1.txt, -2.txt, ... (split only
+// at line breaks) and keep the main file summary-only. Everything written after Finish goes to the main file.
+type PagedFile struct {
+	path    string
+	limits  FileLimits
+	buffer  bytes.Buffer // the first page, kept until it is known whether the report is split
+	page    *os.File
+	pages   []string
+	lines   int
+	size    int64
+	midLine bool // the last write ended inside a line
+	main    *os.File
+	done    bool
+	err     error
+}
+
+// NewPagedFile prepares path for writing and removes detail pages left by an earlier run
+func NewPagedFile(path string, limits FileLimits) (*PagedFile, error) {
+	stale, _ := filepath.Glob(detailPagePattern(path))
+	for _, page := range stale {
+		if err := os.Remove(page); err != nil {
+			return nil, WrapError(err, "failed to remove %s", page)
+		}
+	}
+	// Create the main file up front so that an unwritable directory is reported before the analyzer runs
+	main, err := os.Create(path)
+	if err != nil {
+		return nil, WrapError(err, "failed to create %s", path)
+	}
+	return &PagedFile{path: path, limits: limits, main: main}, nil
+}
+
+// detailPagePattern returns the glob of the detail pages of a stats file
+func detailPagePattern(path string) string {
+	return strings.TrimSuffix(path, ".txt") + "-details-*.txt"
+}
+
+// Path returns the main file
+func (f *PagedFile) Path() string {
+	return f.path
+}
+
+// Pages returns the detail pages written so far
+func (f *PagedFile) Pages() []string {
+	return f.pages
+}
+
+// Write implements io.Writer. Write errors are kept and returned by Close so that the console output
+// of the run (written through an io.MultiWriter) is not cut short by a full disk.
+func (f *PagedFile) Write(p []byte) (int, error) {
+	if f.err != nil {
+		return len(p), nil
+	}
+	if f.done || f.limits == (FileLimits{}) {
+		f.write(f.main, p)
+		return len(p), nil
+	}
+
+	rest := p
+	for len(rest) > 0 {
+		chunk := rest
+		newline := bytes.IndexByte(rest, '\n')
+		if newline >= 0 {
+			chunk = rest[:newline+1]
+		}
+		rest = rest[len(chunk):]
+
+		// A new page starts only when there is more to write, so a report that just fits is not split
+		if !f.midLine && f.limits.exceeded(f.lines, f.size) {
+			f.nextPage()
+			if f.err != nil {
+				break
+			}
+		}
+		if f.page != nil {
+			f.write(f.page, chunk)
+		} else {
+			f.buffer.Write(chunk)
+		}
+		f.size += int64(len(chunk))
+		f.midLine = newline < 0
+		if !f.midLine {
+			f.lines++
+		}
+	}
+	return len(p), nil
+}
+
+// nextPage closes the current page and starts the next one. The first page leaves memory only at this point.
+func (f *PagedFile) nextPage() {
+	if f.err != nil {
+		return
+	}
+	f.closePage()
+	page, err := os.Create(fmt.Sprintf("%s-details-%d.txt", strings.TrimSuffix(f.path, ".txt"), len(f.pages)+1))
+	if err != nil {
+		f.err = WrapError(err, "failed to create detail page for %s", f.path)
+		return
+	}
+	f.page = page
+	f.pages = append(f.pages, page.Name())
+	f.lines, f.size = 0, 0
+	if f.buffer.Len() > 0 {
+		f.write(f.page, f.buffer.Bytes())
+		f.buffer = bytes.Buffer{}
+		f.nextPage()
+	}
+}
+
+// closePage closes the current detail page, keeping the first error
+func (f *PagedFile) closePage() {
+	if f.page == nil {
+		return
+	}
+	if err := f.page.Close(); err != nil && f.err == nil {
+		f.err = WrapError(err, "failed to write %s", f.page.Name())
+	}
+	f.page = nil
+}
+
+func (f *PagedFile) write(file *os.File, p []byte) {
+	if f.err != nil {
+		return
+	}
+	if _, err := file.Write(p); err != nil {
+		f.err = WrapError(err, "failed to write %s", file.Name())
+	}
+}
+
+// Finish completes the detailed listing. A split report gets a main file listing its detail pages and the summary
+// of result (nil when the analyzer failed); an unsplit one is written as it is. Later writes go to the main file.
+func (f *PagedFile) Finish(result *AnalysisResult) {
+	if f.done {
+		return
+	}
+	f.done = true
+	if len(f.pages) == 0 {
+		f.write(f.main, f.buffer.Bytes())
+	} else {
+		f.closePage()
+		f.writeIndex(result)
+	}
+	f.buffer = bytes.Buffer{}
+}
+
+// writeIndex writes the list of detail pages and the summary to the main file
+func (f *PagedFile) writeIndex(result *AnalysisResult) {
+	var index bytes.Buffer
+	fmt.Fprintf(&index, "📄 Detailed output exceeded %s per file and was split into %d files:\n", f.limits.Describe(), len(f.pages))
+	for _, page := range f.pages {
+		fmt.Fprintf(&index, "- %s\n", filepath.Base(page))
+	}
+	if result != nil {
+		result.PrintSummary(&index)
+	}
+	f.write(f.main, index.Bytes())
+}
+
+// Close finishes the report if needed and closes the main file, returning the first write error
+func (f *PagedFile) Close() error {
+	f.Finish(nil)
+	if f.main != nil {
+		if err := f.main.Close(); err != nil && f.err == nil {
+			f.err = WrapError(err, "failed to write %s", f.path)
+		}
+		f.main = nil
+	}
+	return f.err
+}
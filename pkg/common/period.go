@@ -0,0 +1,92 @@
+package common
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// PeriodPresets lists the relative presets accepted by ParsePeriod, besides YYYY, YYYY-HN, YYYY-QN, and YYYY-MM
+var PeriodPresets = []string{"this-month", "last-month", "this-quarter", "last-quarter", "this-half", "last-half", "this-year", "last-year"}
+
+var (
+	yearPattern    = regexp.MustCompile(`^(\d{4})$`)
+	halfPattern    = regexp.MustCompile(`^(\d{4})-[Hh]([12])$`)
+	quarterPattern = regexp.MustCompile(`^(\d{4})-[Qq]([1-4])$`)
+	monthPattern   = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+)
+
+// dateOverride holds -start/-end/-period, which take precedence over START_DATE/END_DATE
+var dateOverride struct {
+	start string
+	end   string
+}
+
+// OverrideDateRange makes LoadConfig use start and/or end (YYYY-MM-DD) instead of START_DATE/END_DATE; empty values keep the env var
+func OverrideDateRange(start, end string) {
+	dateOverride.start = start
+	dateOverride.end = end
+}
+
+// DateRangeOverridden reports whether the period was given on the command line
+func DateRangeOverridden() bool {
+	return dateOverride.start != "" || dateOverride.end != ""
+}
+
+// ParsePeriod returns the first and last day of a preset (last-month, last-quarter, ...) or a calendar period
+// (2024, 2024-H2, 2024-Q3, 2024-07). Relative presets are resolved against now.
+func ParsePeriod(period string, now time.Time) (time.Time, time.Time, error) {
+	year, month := now.Year(), int(now.Month())
+	quarterStart := (month-1)/3*3 + 1
+	halfStart := (month-1)/6*6 + 1
+
+	switch period {
+	case "this-month":
+		return monthsFrom(year, month, 1)
+	case "last-month":
+		return monthsFrom(year, month-1, 1)
+	case "this-quarter":
+		return monthsFrom(year, quarterStart, 3)
+	case "last-quarter":
+		return monthsFrom(year, quarterStart-3, 3)
+	case "this-half":
+		return monthsFrom(year, halfStart, 6)
+	case "last-half":
+		return monthsFrom(year, halfStart-6, 6)
+	case "this-year":
+		return monthsFrom(year, 1, 12)
+	case "last-year":
+		return monthsFrom(year-1, 1, 12)
+	}
+
+	if match := yearPattern.FindStringSubmatch(period); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		return monthsFrom(year, 1, 12)
+	}
+	if match := halfPattern.FindStringSubmatch(period); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		half, _ := strconv.Atoi(match[2])
+		return monthsFrom(year, (half-1)*6+1, 6)
+	}
+	if match := quarterPattern.FindStringSubmatch(period); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		quarter, _ := strconv.Atoi(match[2])
+		return monthsFrom(year, (quarter-1)*3+1, 3)
+	}
+	if match := monthPattern.FindStringSubmatch(period); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		if month >= 1 && month <= 12 {
+			return monthsFrom(year, month, 1)
+		}
+	}
+
+	return time.Time{}, time.Time{}, NewError("unknown period '%s' (expected one of %v, YYYY, YYYY-H1/H2, YYYY-Q1..Q4, or YYYY-MM)", period, PeriodPresets)
+}
+
+// monthsFrom returns the range of count months starting at month (which may be out of 1..12 and is normalized)
+func monthsFrom(year, month, count int) (time.Time, time.Time, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, count, -1)
+	return start, end, nil
+}
@@ -0,0 +1,80 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProjectTotal aggregates work items assigned to one project across analyzers
+type ProjectTotal struct {
+	Project  string
+	Count    int
+	Duration time.Duration
+	Sources  map[string]int
+}
+
+// AggregateProjects totals work items that have a project assigned, sorted by count.
+// Linked artifacts of the same work item are counted once.
+func AggregateProjects(items []WorkItem) []ProjectTotal {
+	totals := make(map[string]*ProjectTotal)
+	for _, item := range items {
+		project := item.Project()
+		if project == "" {
+			continue
+		}
+		total, exists := totals[project]
+		if !exists {
+			total = &ProjectTotal{Project: project, Sources: make(map[string]int)}
+			totals[project] = total
+		}
+		total.Count++
+		total.Duration += item.Duration()
+		for _, activity := range item.Activities {
+			total.Sources[activity.Source]++
+		}
+	}
+
+	var projects []ProjectTotal
+	for _, total := range totals {
+		projects = append(projects, *total)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Count != projects[j].Count {
+			return projects[i].Count > projects[j].Count
+		}
+		return projects[i].Project < projects[j].Project
+	})
+	return projects
+}
+
+// PrintProjectBreakdown prints per-project totals. Nothing is printed when no work item has a project.
+func PrintProjectBreakdown(writer io.Writer, items []WorkItem) {
+	projects := AggregateProjects(items)
+	if len(projects) == 0 {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintln(writer, "PROJECTS")
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	for _, project := range projects {
+		var sources []string
+		for source := range project.Sources {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		for i, source := range sources {
+			sources[i] = fmt.Sprintf("%s %d", source, project.Sources[source])
+		}
+
+		line := fmt.Sprintf("- %s: %d work items (%s)", project.Project, project.Count, strings.Join(sources, ", "))
+		if project.Duration > 0 {
+			line += ", " + FormatDuration(project.Duration)
+		}
+		fmt.Fprintln(writer, line)
+	}
+}
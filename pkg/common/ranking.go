@@ -0,0 +1,31 @@
+package common
+
+import (
+	"fmt"
+	"io"
+)
+
+// DefaultRankingLimit is how many entries each ranking lists unless -top changes it
+const DefaultRankingLimit = 10
+
+var rankingLimit = DefaultRankingLimit
+
+// SetRankingLimit sets how many entries rankings list (-top); 0 lists every entry
+func SetRankingLimit(limit int) {
+	rankingLimit = limit
+}
+
+// RankingLimit returns how many entries of a ranking with total entries are listed
+func RankingLimit(total int) int {
+	if rankingLimit <= 0 || total <= rankingLimit {
+		return total
+	}
+	return rankingLimit
+}
+
+// PrintMoreEntries prints "…and N more" below a ranking of total entries that was cut at RankingLimit
+func PrintMoreEntries(writer io.Writer, total int) {
+	if hidden := total - RankingLimit(total); hidden > 0 {
+		fmt.Fprintf(writer, "…and %d more\n", hidden)
+	}
+}
@@ -0,0 +1,170 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secondaryRateLimitWait is used when a rate-limited response carries neither Retry-After nor a reset time
+const secondaryRateLimitWait = 60 * time.Second
+
+// rateLimiter waits out X-RateLimit-* / Retry-After limits instead of failing the request.
+// It is shared by concurrent requests of one client.
+type rateLimiter struct {
+	writer  io.Writer
+	maxWait time.Duration
+	mu      sync.Mutex
+	resetAt time.Time // set when the last response reported no remaining requests
+}
+
+// WaitOnRateLimit makes the client sleep until the rate limit resets, printing progress to writer,
+// when a response reports X-RateLimit-Remaining: 0 or is rejected with 403/429 for rate limiting.
+// Waits longer than maxWait fail the request as before (maxWait <= 0 disables waiting).
+func (c *HTTPClient) WaitOnRateLimit(writer io.Writer, maxWait time.Duration) {
+	if maxWait <= 0 {
+		c.rateLimit = nil
+		return
+	}
+	c.rateLimit = &rateLimiter{writer: writer, maxWait: maxWait}
+}
+
+// beforeRequest sleeps if the previous response exhausted the quota
+func (r *rateLimiter) beforeRequest(host string) {
+	r.mu.Lock()
+	resetAt := r.resetAt
+	r.resetAt = time.Time{}
+	r.mu.Unlock()
+	if resetAt.IsZero() {
+		return
+	}
+	wait := time.Until(resetAt)
+	if wait > 0 && wait <= r.maxWait {
+		r.sleep(host, wait, "quota exhausted")
+	}
+}
+
+// afterResponse records the quota state and returns how long to wait before retrying a rejected request
+func (r *rateLimiter) afterResponse(host string, statusCode int, header http.Header, body []byte) (time.Duration, bool) {
+	remaining := header.Get("X-RateLimit-Remaining")
+	reset := parseRateLimitReset(header.Get("X-RateLimit-Reset"))
+
+	if statusCode >= 200 && statusCode < 300 {
+		if remaining == "0" && !reset.IsZero() {
+			r.mu.Lock()
+			r.resetAt = reset.Add(time.Second)
+			r.mu.Unlock()
+		}
+		return 0, false
+	}
+
+	if statusCode != http.StatusForbidden && statusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	var wait time.Duration
+	switch {
+	case header.Get("Retry-After") != "":
+		seconds, err := strconv.Atoi(header.Get("Retry-After"))
+		if err != nil {
+			return 0, false
+		}
+		wait = time.Duration(seconds) * time.Second
+	case remaining == "0" && !reset.IsZero():
+		wait = time.Until(reset) + time.Second
+	case statusCode == http.StatusTooManyRequests || strings.Contains(strings.ToLower(string(body)), "rate limit"):
+		wait = secondaryRateLimitWait
+	default:
+		// A plain 403 (permissions, SSO) is not a rate limit
+		return 0, false
+	}
+
+	if wait < time.Second {
+		wait = time.Second
+	}
+	if wait > r.maxWait {
+		fmt.Fprintf(r.writer, "⚠️  Rate limit for %s resets in %s, longer than the %s maximum wait. Giving up.\n",
+			host, wait.Round(time.Second), r.maxWait)
+		return 0, false
+	}
+	return wait, true
+}
+
+// sleep waits for the given duration, printing a progress line every minute
+func (r *rateLimiter) sleep(host string, wait time.Duration, reason string) {
+	until := time.Now().Add(wait)
+	fmt.Fprintf(r.writer, "⏳ Rate limit reached for %s (%s). Waiting %s until %s...\n",
+		host, reason, wait.Round(time.Second), until.Format("15:04:05"))
+	for {
+		left := time.Until(until)
+		if left <= 0 {
+			break
+		}
+		if left > time.Minute {
+			time.Sleep(time.Minute)
+			fmt.Fprintf(r.writer, "   %s remaining...\n", time.Until(until).Round(time.Second))
+			continue
+		}
+		time.Sleep(left)
+	}
+	fmt.Fprintln(r.writer, "🔄 Resuming requests")
+}
+
+// tokenBucket spaces requests to a steady rate per second, allowing short bursts
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// SetRequestRate throttles the client to perSecond requests on average with bursts of up to burst requests.
+// perSecond <= 0 removes the throttle.
+func (c *HTTPClient) SetRequestRate(perSecond float64, burst int) {
+	if perSecond <= 0 {
+		c.throttle = nil
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	c.throttle = &tokenBucket{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait blocks until a token is available and takes it
+func (b *tokenBucket) wait() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		time.Sleep(wait)
+		b.tokens = 1
+		b.last = time.Now()
+	}
+	b.tokens--
+}
+
+// parseRateLimitReset parses X-RateLimit-Reset as Unix epoch seconds
+func parseRateLimitReset(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
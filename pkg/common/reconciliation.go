@@ -0,0 +1,180 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultReconciliationThreshold is how far scheduled, logged, and estimated hours may differ before a week is flagged
+const defaultReconciliationThreshold = 4 * time.Hour
+
+// noProjectLabel groups the time of work items without a project
+const noProjectLabel = "(no project)"
+
+// ReconciliationRow compares the time of one project in one week as scheduled in calendars, logged in time
+// trackers (worklogs), and estimated from PRs, pages, and tickets
+type ReconciliationRow struct {
+	Week      string `csv:"week"` // e.g. 2025-W03
+	Start     time.Time
+	Project   string        `csv:"project"`
+	Scheduled time.Duration `csv:"scheduled_hours"`
+	Logged    time.Duration `csv:"logged_hours"`
+	Estimated time.Duration `csv:"estimated_hours"`
+	Diverges  bool          `csv:"diverges"`
+}
+
+// Reconciliation is the weekly comparison with the columns that have data in the period; only those are compared
+type Reconciliation struct {
+	Rows         []ReconciliationRow
+	Threshold    time.Duration
+	HasScheduled bool
+	HasLogged    bool
+	HasEstimated bool
+}
+
+// ReconciliationThresholdFromEnv reads RECONCILIATION_THRESHOLD_HOURS, the difference that flags a week (default 4)
+func ReconciliationThresholdFromEnv() time.Duration {
+	if hours, err := strconv.ParseFloat(os.Getenv("RECONCILIATION_THRESHOLD_HOURS"), 64); err == nil && hours > 0 {
+		return time.Duration(hours * float64(time.Hour))
+	}
+	return defaultReconciliationThreshold
+}
+
+// ReconcileWeeks totals scheduled, logged, and estimated time per project per week. Calendar events and worklogs
+// count in their own week; an item's estimate counts in the week of the activity it was estimated from. Work items
+// without a project are grouped under "(no project)". A row diverges when the largest and smallest of the columns
+// with data in the period differ by more than threshold.
+func ReconcileWeeks(items []WorkItem, estimate EstimateFunc, weeks WeekConfig, threshold time.Duration) *Reconciliation {
+	reconciliation := &Reconciliation{Threshold: threshold}
+	rows := make(map[string]*ReconciliationRow) // week label + project
+	row := func(activity Activity, project string) *ReconciliationRow {
+		day, _ := time.ParseInLocation("2006-01-02", activity.LocalDay(), time.Local)
+		label := weeks.WeekLabel(day)
+		key := label + "\x00" + project
+		if existing, exists := rows[key]; exists {
+			return existing
+		}
+		created := &ReconciliationRow{Week: label, Start: weeks.WeekStart(day), Project: project}
+		rows[key] = created
+		return created
+	}
+
+	for _, item := range items {
+		project := item.Project()
+		if project == "" {
+			project = noProjectLabel
+		}
+		var best time.Duration
+		var bestActivity *Activity
+		for i, activity := range item.Activities {
+			if activity.Time.IsZero() {
+				continue
+			}
+			switch {
+			case activity.Kind == ActivityKindEvent:
+				row(activity, project).Scheduled += activity.Duration
+				reconciliation.HasScheduled = reconciliation.HasScheduled || activity.Duration > 0
+			case activity.Kind == ActivityKindWorklog:
+				row(activity, project).Logged += activity.Duration
+				reconciliation.HasLogged = reconciliation.HasLogged || activity.Duration > 0
+			case activity.Duration == 0:
+				if duration, ok := estimate(activity); ok && (bestActivity == nil || duration > best) {
+					best, bestActivity = duration, &item.Activities[i]
+				}
+			}
+		}
+		if bestActivity != nil && best > 0 {
+			row(*bestActivity, project).Estimated += best
+			reconciliation.HasEstimated = true
+		}
+	}
+
+	for _, r := range rows {
+		r.Diverges = reconciliation.spread(*r) > threshold
+		reconciliation.Rows = append(reconciliation.Rows, *r)
+	}
+	sort.Slice(reconciliation.Rows, func(i, j int) bool {
+		a, b := reconciliation.Rows[i], reconciliation.Rows[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		return a.Project < b.Project
+	})
+	return reconciliation
+}
+
+// spread returns the difference between the largest and smallest compared columns of the row, or 0 when fewer
+// than two columns have data in the period
+func (r *Reconciliation) spread(row ReconciliationRow) time.Duration {
+	var values []time.Duration
+	if r.HasScheduled {
+		values = append(values, row.Scheduled)
+	}
+	if r.HasLogged {
+		values = append(values, row.Logged)
+	}
+	if r.HasEstimated {
+		values = append(values, row.Estimated)
+	}
+	if len(values) < 2 {
+		return 0
+	}
+	smallest, largest := values[0], values[0]
+	for _, value := range values[1:] {
+		if value < smallest {
+			smallest = value
+		}
+		if value > largest {
+			largest = value
+		}
+	}
+	return largest - smallest
+}
+
+// PrintReconciliation prints one line per project per week, marking the rows that diverge with "!"
+func PrintReconciliation(writer io.Writer, reconciliation *Reconciliation) {
+	fmt.Fprintf(writer, "\n%s\n", strings.Repeat("=", 60))
+	fmt.Fprintf(writer, "TIME RECONCILIATION (flagged when apart by more than %s)\n", FormatDuration(reconciliation.Threshold))
+	fmt.Fprintln(writer, strings.Repeat("=", 60))
+
+	if len(reconciliation.Rows) == 0 {
+		fmt.Fprintln(writer, "\nNo scheduled, logged, or estimated time in the period")
+		return
+	}
+	var missing []string
+	if !reconciliation.HasScheduled {
+		missing = append(missing, "scheduled")
+	}
+	if !reconciliation.HasLogged {
+		missing = append(missing, "logged")
+	}
+	if !reconciliation.HasEstimated {
+		missing = append(missing, "estimated")
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(writer, "\nNo %s time in the period; only the other columns are compared\n", strings.Join(missing, " or "))
+	}
+
+	fmt.Fprintf(writer, "\n   %-10s  %-24s  %9s  %9s  %9s\n", "Week", "Project", "Scheduled", "Logged", "Estimated")
+	diverging := 0
+	for _, row := range reconciliation.Rows {
+		mark := " "
+		if row.Diverges {
+			mark = "!"
+			diverging++
+		}
+		fmt.Fprintf(writer, "%s  %-10s  %-24s  %9s  %9s  %9s\n", mark, row.Week, row.Project,
+			FormatDuration(row.Scheduled), FormatDuration(row.Logged), FormatDuration(row.Estimated))
+	}
+	fmt.Fprintf(writer, "\n%d of %d project weeks diverge (RECONCILIATION_THRESHOLD_HOURS)\n", diverging, len(reconciliation.Rows))
+}
+
+// ReconciliationCSVTable returns the reconciliation rows for reconciliation.csv
+func ReconciliationCSVTable(reconciliation *Reconciliation) CSVTable {
+	return NewCSVTable("reconciliation", reconciliation.Rows)
+}
@@ -0,0 +1,171 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RollupBucket aggregates the activities of all sources within one week or month
+type RollupBucket struct {
+	Label     string // e.g. 2025-W03 or 2025-01
+	Start     time.Time
+	End       time.Time
+	Partial   bool // the bucket is cut off by START_DATE or END_DATE
+	Sources   map[string]int
+	Scheduled time.Duration // total duration of calendar events
+	Total     int
+}
+
+// Rollup is a series of consecutive buckets covering the period, including buckets without activity
+type Rollup struct {
+	Title   string // e.g. WEEKLY ROLLUP
+	Unit    string // week or month
+	Column  string // header of the bucket column
+	Note    string // e.g. the week numbering
+	Buckets []RollupBucket
+	Sources []string
+}
+
+// RollupWeeks buckets every result's activities into the weeks of the period, as defined by weeks
+func RollupWeeks(results []*AnalysisResult, startDate, endDate time.Time, weeks WeekConfig) Rollup {
+	rollup := Rollup{Title: "WEEKLY ROLLUP", Unit: "week", Column: "Week", Note: weeks.Describe()}
+	for day := weeks.WeekStart(startDate); !day.After(endDate); day = day.AddDate(0, 0, 7) {
+		rollup.Buckets = append(rollup.Buckets, newRollupBucket(weeks.WeekLabel(day), day, day.AddDate(0, 0, 6), startDate, endDate))
+	}
+	fillRollup(&rollup, results, startDate, endDate)
+	return rollup
+}
+
+// RollupMonths buckets every result's activities into the calendar months of the period
+func RollupMonths(results []*AnalysisResult, startDate, endDate time.Time) Rollup {
+	rollup := Rollup{Title: "MONTHLY ROLLUP", Unit: "month", Column: "Month"}
+	first := time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, startDate.Location())
+	for day := first; !day.After(endDate); day = day.AddDate(0, 1, 0) {
+		rollup.Buckets = append(rollup.Buckets, newRollupBucket(day.Format("2006-01"), day, day.AddDate(0, 1, -1), startDate, endDate))
+	}
+	fillRollup(&rollup, results, startDate, endDate)
+	return rollup
+}
+
+// newRollupBucket returns an empty bucket for start..end, marked partial when the period does not cover it entirely
+func newRollupBucket(label string, start, end, startDate, endDate time.Time) RollupBucket {
+	first, last := start.Format("2006-01-02"), end.Format("2006-01-02")
+	return RollupBucket{
+		Label:   label,
+		Start:   start,
+		End:     end,
+		Partial: first < startDate.Format("2006-01-02") || last > endDate.Format("2006-01-02"),
+		Sources: make(map[string]int),
+	}
+}
+
+// fillRollup counts the activities within the period into the buckets by their local day, like the timeline
+func fillRollup(rollup *Rollup, results []*AnalysisResult, startDate, endDate time.Time) {
+	first := startDate.Format("2006-01-02")
+	last := endDate.Format("2006-01-02")
+
+	sources := make(map[string]bool)
+	for _, result := range results {
+		for _, activity := range result.Activities {
+			if activity.Time.IsZero() {
+				continue
+			}
+			date := activity.LocalDay()
+			if date < first || date > last {
+				continue
+			}
+			for i := range rollup.Buckets {
+				bucket := &rollup.Buckets[i]
+				if date < bucket.Start.Format("2006-01-02") || date > bucket.End.Format("2006-01-02") {
+					continue
+				}
+				bucket.Sources[activity.Source]++
+				bucket.Total++
+				if activity.Kind == ActivityKindEvent {
+					bucket.Scheduled += activity.Duration
+				}
+				sources[activity.Source] = true
+				break
+			}
+		}
+	}
+
+	for source := range sources {
+		rollup.Sources = append(rollup.Sources, source)
+	}
+	sort.Strings(rollup.Sources)
+}
+
+// PrintRollup prints one row per bucket with the item count of each source and the scheduled hours,
+// followed by the average per full bucket
+func PrintRollup(writer io.Writer, rollup Rollup) {
+	fmt.Fprintf(writer, "\n%s\n", strings.Repeat("=", 60))
+	if rollup.Note != "" {
+		fmt.Fprintf(writer, "%s (%s)\n", rollup.Title, rollup.Note)
+	} else {
+		fmt.Fprintln(writer, rollup.Title)
+	}
+	fmt.Fprintln(writer, strings.Repeat("=", 60))
+
+	if len(rollup.Sources) == 0 {
+		fmt.Fprintln(writer, "\nNo dated activity in the period")
+		return
+	}
+
+	widths := make([]int, len(rollup.Sources))
+	header := fmt.Sprintf("%-10s  %-10s", rollup.Column, "Start")
+	for i, source := range rollup.Sources {
+		widths[i] = len(source)
+		if widths[i] < 5 {
+			widths[i] = 5
+		}
+		header += fmt.Sprintf("  %*s", widths[i], source)
+	}
+	header += fmt.Sprintf("  %6s  %9s", "Total", "Scheduled")
+	fmt.Fprintf(writer, "\n%s\n", header)
+
+	full := 0
+	var fullTotal int
+	var fullScheduled time.Duration
+	fullSources := make(map[string]int)
+	for _, bucket := range rollup.Buckets {
+		label := bucket.Label
+		if bucket.Partial {
+			label += "*"
+		}
+		line := fmt.Sprintf("%-10s  %-10s", label, bucket.Start.Format("2006-01-02"))
+		for i, source := range rollup.Sources {
+			line += fmt.Sprintf("  %*d", widths[i], bucket.Sources[source])
+		}
+		line += fmt.Sprintf("  %6d  %8.1fh", bucket.Total, bucket.Scheduled.Hours())
+		fmt.Fprintln(writer, line)
+
+		if bucket.Partial {
+			continue
+		}
+		full++
+		fullTotal += bucket.Total
+		fullScheduled += bucket.Scheduled
+		for source, count := range bucket.Sources {
+			fullSources[source] += count
+		}
+	}
+
+	if full > 0 {
+		line := fmt.Sprintf("%-22s", "Average")
+		for i, source := range rollup.Sources {
+			line += fmt.Sprintf("  %*.1f", widths[i], float64(fullSources[source])/float64(full))
+		}
+		line += fmt.Sprintf("  %6.1f  %8.1fh", float64(fullTotal)/float64(full), fullScheduled.Hours()/float64(full))
+		fmt.Fprintln(writer, line)
+	}
+	for _, bucket := range rollup.Buckets {
+		if bucket.Partial {
+			fmt.Fprintf(writer, "\n* partial %s, cut off by the period; not included in the average\n", rollup.Unit)
+			break
+		}
+	}
+}
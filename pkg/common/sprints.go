@@ -0,0 +1,81 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sprint is a named date range (inclusive, dates parsed like START_DATE/END_DATE) used to bucket activities the way the team reviews work
+type Sprint struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether t falls on a day within the sprint
+func (s Sprint) Contains(t time.Time) bool {
+	day := t.Local().Format("2006-01-02")
+	return day >= s.Start.Format("2006-01-02") && day <= s.End.Format("2006-01-02")
+}
+
+// SprintRollup aggregates activities of all sources within one sprint
+type SprintRollup struct {
+	Sprint   Sprint
+	Sources  map[string]int
+	Duration time.Duration // total of activities with a duration (calendar events)
+	Total    int
+}
+
+// RollupSprints buckets every result's activities into the sprints they fall in
+func RollupSprints(results []*AnalysisResult, sprints []Sprint) []SprintRollup {
+	rollups := make([]SprintRollup, len(sprints))
+	for i, sprint := range sprints {
+		rollups[i] = SprintRollup{Sprint: sprint, Sources: make(map[string]int)}
+	}
+	for _, result := range results {
+		for _, activity := range result.Activities {
+			for i := range rollups {
+				if !rollups[i].Sprint.Contains(activity.Time) {
+					continue
+				}
+				rollups[i].Sources[activity.Source]++
+				rollups[i].Duration += activity.Duration
+				rollups[i].Total++
+				break
+			}
+		}
+	}
+	return rollups
+}
+
+// PrintSprintRollups prints per-sprint activity counts by source. Nothing is printed when no sprints overlap the period.
+func PrintSprintRollups(writer io.Writer, rollups []SprintRollup) {
+	if len(rollups) == 0 {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintln(writer, "SPRINTS")
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	for _, rollup := range rollups {
+		fmt.Fprintf(writer, "\n%s (%s to %s): %d items",
+			rollup.Sprint.Name, rollup.Sprint.Start.Format("2006-01-02"), rollup.Sprint.End.Format("2006-01-02"), rollup.Total)
+		if rollup.Duration > 0 {
+			fmt.Fprintf(writer, ", %s scheduled", FormatDuration(rollup.Duration))
+		}
+		fmt.Fprintln(writer)
+
+		var sources []string
+		for source := range rollup.Sources {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		for _, source := range sources {
+			fmt.Fprintf(writer, "  - %s: %d\n", source, rollup.Sources[source])
+		}
+	}
+}
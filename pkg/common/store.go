@@ -0,0 +1,35 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// WriteJSONFile stores v as indented JSON, creating parent directories as needed.
+// The file is encrypted when cache encryption is enabled (see WriteProtectedFile).
+func WriteJSONFile(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return WrapError(err, "failed to create directory for %s", path)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return WrapError(err, "failed to encode %s", path)
+	}
+	if err := WriteProtectedFile(path, data); err != nil {
+		return WrapError(err, "failed to write %s", path)
+	}
+	return nil
+}
+
+// ReadJSONFile loads JSON written by WriteJSONFile into v
+func ReadJSONFile(path string, v interface{}) error {
+	data, err := ReadProtectedFile(path)
+	if err != nil {
+		return WrapError(err, "failed to read %s", path)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return WrapError(err, "failed to decode %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimelineFileName is the plain-text timeline written next to the stats files (-timeline)
+const TimelineFileName = "timeline.txt"
+
+// TimelineDay is the activity feed of one local day
+type TimelineDay struct {
+	Date       string // YYYY-MM-DD
+	Activities []Activity
+	Scheduled  time.Duration // total duration of calendar events
+}
+
+// timelineRow is a timeline entry exported as timeline.csv
+type timelineRow struct {
+	Date     string        `csv:"date"`
+	Time     time.Time     `csv:"time"`
+	Source   string        `csv:"source"`
+	Kind     string        `csv:"kind"`
+	Title    string        `csv:"title"`
+	Duration time.Duration `csv:"duration_hours"`
+	Category string        `csv:"category"`
+	Project  string        `csv:"project"`
+	URL      string        `csv:"url"`
+}
+
+// BuildTimeline merges the activities of all results within the period into per-day feeds sorted by time.
+// Days are local days; days without activity are omitted.
+func BuildTimeline(results []*AnalysisResult, startDate, endDate time.Time) []TimelineDay {
+	first := startDate.Format("2006-01-02")
+	last := endDate.Format("2006-01-02")
+
+	byDate := make(map[string]*TimelineDay)
+	for _, result := range results {
+		for _, activity := range result.Activities {
+			if activity.Time.IsZero() {
+				continue
+			}
+			date := activity.LocalDay()
+			if date < first || date > last {
+				continue
+			}
+			day, exists := byDate[date]
+			if !exists {
+				day = &TimelineDay{Date: date}
+				byDate[date] = day
+			}
+			day.Activities = append(day.Activities, activity)
+			if activity.Kind == ActivityKindEvent {
+				day.Scheduled += activity.Duration
+			}
+		}
+	}
+
+	days := make([]TimelineDay, 0, len(byDate))
+	for _, day := range byDate {
+		sort.SliceStable(day.Activities, func(i, j int) bool {
+			a, b := day.Activities[i], day.Activities[j]
+			if !a.Time.Equal(b.Time) {
+				return a.Time.Before(b.Time)
+			}
+			if a.Source != b.Source {
+				return a.Source < b.Source
+			}
+			return a.Title < b.Title
+		})
+		days = append(days, *day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	return days
+}
+
+// PrintTimeline prints the per-day activity feed: time, source, kind, and title of every item
+func PrintTimeline(writer io.Writer, days []TimelineDay) {
+	fmt.Fprintf(writer, "\n%s\n", strings.Repeat("=", 60))
+	fmt.Fprintln(writer, "TIMELINE")
+	fmt.Fprintln(writer, strings.Repeat("=", 60))
+
+	if len(days) == 0 {
+		fmt.Fprintln(writer, "\nNo dated activity in the period")
+		return
+	}
+
+	for _, day := range days {
+		header := fmt.Sprintf("%s: %d items", formatDayWithWeekday(day.Date), len(day.Activities))
+		if day.Scheduled > 0 {
+			header += fmt.Sprintf(", %s scheduled", FormatDuration(day.Scheduled))
+		}
+		fmt.Fprintf(writer, "\n%s\n", header)
+
+		for _, activity := range day.Activities {
+			clock := activity.Time.Local().Format("15:04")
+			if IsAllDayActivity(activity) {
+				clock = "all day"
+			}
+			line := fmt.Sprintf("  %-7s  [%s] %s: %s", clock, activity.Source, activity.Kind, activity.Title)
+			if activity.Duration > 0 {
+				line += fmt.Sprintf(" (%s)", FormatDuration(activity.Duration))
+			}
+			fmt.Fprintln(writer, line)
+		}
+	}
+}
+
+// IsAllDayActivity reports whether the activity is an all-day calendar event (events without a duration)
+func IsAllDayActivity(activity Activity) bool {
+	return activity.Kind == ActivityKindEvent && activity.Duration == 0
+}
+
+// TimelineCSVTable returns the timeline as one row per activity, for timeline.csv
+func TimelineCSVTable(days []TimelineDay) CSVTable {
+	var rows []timelineRow
+	for _, day := range days {
+		for _, activity := range day.Activities {
+			rows = append(rows, timelineRow{
+				Date:     day.Date,
+				Time:     activity.Time.Local(),
+				Source:   activity.Source,
+				Kind:     activity.Kind,
+				Title:    activity.Title,
+				Duration: activity.Duration,
+				Category: activity.Category,
+				Project:  activity.Project,
+				URL:      activity.URL,
+			})
+		}
+	}
+	if rows == nil {
+		rows = []timelineRow{}
+	}
+	return NewCSVTable("timeline", rows)
+}
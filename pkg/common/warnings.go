@@ -0,0 +1,121 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxWarningExamples is how many degraded items are listed per kind in the summary
+const maxWarningExamples = 5
+
+// Warning is an optional lookup (database title, user name, reviews, ...) that failed without failing the analysis.
+// The item is still counted, but with less detail.
+type Warning struct {
+	Kind  string `json:"kind"` // what could not be fetched, e.g. "reviews"
+	Item  string `json:"item"` // the affected item, e.g. "example-org/web#40"
+	Error string `json:"error"`
+}
+
+// Warnings collects warnings during an analysis so that they are summarized once instead of interleaved with the report
+type Warnings struct {
+	mu   sync.Mutex
+	list []Warning
+}
+
+// Add records a failed lookup of kind for item
+func (w *Warnings) Add(kind, item string, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	w.list = append(w.list, Warning{Kind: kind, Item: item, Error: message})
+}
+
+// Reset clears the warnings of a previous run
+func (w *Warnings) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.list = nil
+}
+
+// List returns the recorded warnings
+func (w *Warnings) List() []Warning {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Warning(nil), w.list...)
+}
+
+// DegradedItems returns the number of distinct items affected by the warnings
+func DegradedItems(warnings []Warning) int {
+	items := make(map[string]bool)
+	for _, warning := range warnings {
+		items[warning.Kind+"\x00"+warning.Item] = true
+	}
+	return len(items)
+}
+
+// PrintWarningNote prints a one-line pointer to the summary when the result is degraded
+func PrintWarningNote(writer io.Writer, result *AnalysisResult) {
+	if len(result.Warnings) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "\n⚠️  %d items have incomplete details because optional lookups failed (see WARNINGS)\n", DegradedItems(result.Warnings))
+}
+
+// PrintWarnings summarizes the warnings of all results: degraded items per analyzer, grouped by what failed.
+// Nothing is printed when every lookup succeeded.
+func PrintWarnings(writer io.Writer, results []*AnalysisResult) {
+	total := 0
+	for _, result := range results {
+		total += len(result.Warnings)
+	}
+	if total == 0 {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n%s\n", strings.Repeat("=", 60))
+	fmt.Fprintln(writer, "WARNINGS")
+	fmt.Fprintln(writer, strings.Repeat("=", 60))
+
+	for _, result := range results {
+		if len(result.Warnings) == 0 {
+			continue
+		}
+		fmt.Fprintf(writer, "\n%s: %d degraded items\n", result.AnalyzerName, DegradedItems(result.Warnings))
+		PrintWarningList(writer, result.Warnings)
+	}
+}
+
+// PrintWarningList prints warnings grouped by kind, with a few example items and errors per kind
+func PrintWarningList(writer io.Writer, warnings []Warning) {
+	byKind := make(map[string][]Warning)
+	var kinds []string
+	for _, warning := range warnings {
+		if _, exists := byKind[warning.Kind]; !exists {
+			kinds = append(kinds, warning.Kind)
+		}
+		byKind[warning.Kind] = append(byKind[warning.Kind], warning)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		group := byKind[kind]
+		fmt.Fprintf(writer, "- %s: %d failed\n", kind, len(group))
+		for i, warning := range group {
+			if i == maxWarningExamples {
+				fmt.Fprintf(writer, "    ... and %d more\n", len(group)-maxWarningExamples)
+				break
+			}
+			if warning.Item == "" {
+				fmt.Fprintf(writer, "    %s\n", warning.Error)
+				continue
+			}
+			fmt.Fprintf(writer, "    %s: %s\n", warning.Item, warning.Error)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package common
+
+import (
+	"os"
+	"time"
+)
+
+// FileWatcher detects changes to a set of files by polling their modification times
+type FileWatcher struct {
+	paths    []string
+	modTimes map[string]time.Time
+}
+
+// NewFileWatcher creates a watcher and records the current state of the given files
+func NewFileWatcher(paths ...string) *FileWatcher {
+	w := &FileWatcher{
+		paths:    paths,
+		modTimes: make(map[string]time.Time),
+	}
+	w.Changed()
+	return w
+}
+
+// Changed returns the files that were modified, created, or removed since the last call
+func (w *FileWatcher) Changed() []string {
+	var changed []string
+	for _, path := range w.paths {
+		var modTime time.Time
+		if info, err := os.Stat(path); err == nil {
+			modTime = info.ModTime()
+		}
+		if previous, seen := w.modTimes[path]; seen && !previous.Equal(modTime) {
+			changed = append(changed, path)
+		}
+		w.modTimes[path] = modTime
+	}
+	return changed
+}
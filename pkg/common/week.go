@@ -0,0 +1,91 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Week numbering schemes (WEEK_NUMBERING)
+const (
+	// WeekNumberingISO makes week 1 the first week with at least four days in the new year (ISO 8601 with a Monday start)
+	WeekNumberingISO = "iso"
+	// WeekNumberingUS makes week 1 the week containing January 1 (US convention with a Sunday start)
+	WeekNumberingUS = "us"
+)
+
+// WeekConfig defines how days are bucketed into weeks and how the weeks are numbered
+type WeekConfig struct {
+	Start     time.Weekday
+	Numbering string
+}
+
+// DefaultWeekConfig is ISO 8601: weeks start on Monday and week 1 contains the first Thursday
+var DefaultWeekConfig = WeekConfig{Start: time.Monday, Numbering: WeekNumberingISO}
+
+// WeekConfigFromEnv reads WEEK_START (monday or sunday) and WEEK_NUMBERING (iso or us).
+// WEEK_NUMBERING alone implies its usual start day; unknown values are reported and fall back to the default.
+func WeekConfigFromEnv() (WeekConfig, error) {
+	config := DefaultWeekConfig
+
+	switch numbering := strings.ToLower(strings.TrimSpace(os.Getenv("WEEK_NUMBERING"))); numbering {
+	case "", WeekNumberingISO:
+	case WeekNumberingUS:
+		config = WeekConfig{Start: time.Sunday, Numbering: WeekNumberingUS}
+	default:
+		return DefaultWeekConfig, NewError("WEEK_NUMBERING must be 'iso' or 'us', got '%s'", numbering)
+	}
+
+	switch start := strings.ToLower(strings.TrimSpace(os.Getenv("WEEK_START"))); start {
+	case "":
+	case "monday", "mon":
+		config.Start = time.Monday
+	case "sunday", "sun":
+		config.Start = time.Sunday
+	default:
+		return DefaultWeekConfig, NewError("WEEK_START must be 'monday' or 'sunday', got '%s'", start)
+	}
+	return config, nil
+}
+
+// WeekStart returns the first day of the day's week at midnight
+func (w WeekConfig) WeekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) - int(w.Start) + 7) % 7
+	return time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, day.Location())
+}
+
+// WeekEnd returns the last day of the day's week at midnight
+func (w WeekConfig) WeekEnd(day time.Time) time.Time {
+	return w.WeekStart(day).AddDate(0, 0, 6)
+}
+
+// WeekNumber returns the week-numbering year and week number of the day.
+// ISO weeks belong to the year that contains their fourth day, so late December may be week 1 of the next year;
+// US weeks are numbered within the calendar year, so the week containing January 1 is split between two years.
+func (w WeekConfig) WeekNumber(day time.Time) (int, int) {
+	if w.Numbering == WeekNumberingUS {
+		jan1 := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, day.Location())
+		firstWeekStart := w.WeekStart(jan1)
+		days := int(time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location()).Sub(firstWeekStart).Hours()/24 + 0.5)
+		return day.Year(), days/7 + 1
+	}
+
+	fourthDay := w.WeekStart(day).AddDate(0, 0, 3)
+	return fourthDay.Year(), (fourthDay.YearDay()-1)/7 + 1
+}
+
+// WeekLabel formats the day's week as YYYY-Www (e.g. 2025-W03)
+func (w WeekConfig) WeekLabel(day time.Time) string {
+	year, week := w.WeekNumber(day)
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Describe returns a short description for report headers (e.g. "ISO weeks, Monday start")
+func (w WeekConfig) Describe() string {
+	numbering := "ISO weeks"
+	if w.Numbering == WeekNumberingUS {
+		numbering = "US weeks"
+	}
+	return fmt.Sprintf("%s, %s start", numbering, w.Start)
+}
@@ -0,0 +1,253 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// issueKeyPattern matches Backlog-style issue keys such as PROJ-123 in titles
+var issueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[0-9]+\b`)
+
+// minTitleMatchLength avoids linking items whose titles are too short to be meaningful (e.g. "MTG")
+const minTitleMatchLength = 6
+
+// WorkItem groups activities from different sources that represent the same piece of work
+type WorkItem struct {
+	Activities []Activity
+	Reasons    []string
+}
+
+// Duration returns the time spent on the item without double counting:
+// linked artifacts describe the same work, so the longest one is used.
+// Worklogs are separate sessions on the same work and are added up first.
+func (w WorkItem) Duration() time.Duration {
+	var longest time.Duration
+	for _, activity := range w.Activities {
+		if activity.Kind != ActivityKindWorklog && activity.Duration > longest {
+			longest = activity.Duration
+		}
+	}
+	if logged := w.LoggedDuration(); logged > longest {
+		return logged
+	}
+	return longest
+}
+
+// LoggedDuration returns the total time logged on the item (worklogs)
+func (w WorkItem) LoggedDuration() time.Duration {
+	var logged time.Duration
+	for _, activity := range w.Activities {
+		if activity.Kind == ActivityKindWorklog {
+			logged += activity.Duration
+		}
+	}
+	return logged
+}
+
+// Project returns the first project assigned to any of the item's activities
+func (w WorkItem) Project() string {
+	for _, activity := range w.Activities {
+		if activity.Project != "" {
+			return activity.Project
+		}
+	}
+	return ""
+}
+
+// GroupWorkItems links activities that appear in several sources:
+// issue keys mentioned in other items' titles, calendar events with a matching Notion note on the same day,
+// and explicit links (item ID/URL -> IDs/URLs of the same work).
+func GroupWorkItems(results []*AnalysisResult, links map[string][]string) []WorkItem {
+	var activities []Activity
+	for _, result := range results {
+		activities = append(activities, result.Activities...)
+	}
+
+	parent := make([]int, len(activities))
+	reasons := make(map[int][]string)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int, reason string) {
+		rootA, rootB := find(a), find(b)
+		if rootA == rootB {
+			return
+		}
+		parent[rootB] = rootA
+		reasons[rootA] = append(append(reasons[rootA], reasons[rootB]...), reason)
+		delete(reasons, rootB)
+	}
+
+	// Index activities by ID/URL and Backlog/Jira issues by key
+	byKey := make(map[string][]int)
+	issues := make(map[string][]int)
+	for i, activity := range activities {
+		for _, key := range []string{activity.ID, activity.URL} {
+			if key != "" {
+				byKey[strings.TrimSuffix(key, "/")] = append(byKey[strings.TrimSuffix(key, "/")], i)
+			}
+		}
+		if issueKey := activityIssueKey(activity); issueKey != "" {
+			issues[issueKey] = append(issues[issueKey], i)
+		}
+	}
+
+	// Activities on the same issue are one work item
+	for issueKey, indexes := range issues {
+		for _, j := range indexes[1:] {
+			union(indexes[0], j, "same issue "+issueKey)
+		}
+	}
+
+	// Issue keys referenced from other sources (e.g. "PROJ-123: fix login" PR)
+	for i, activity := range activities {
+		if strings.HasPrefix(activity.Source, "Backlog") || activityIssueKey(activity) != "" {
+			continue
+		}
+		for _, issueKey := range issueKeyPattern.FindAllString(activity.Title, -1) {
+			if indexes, exists := issues[issueKey]; exists {
+				union(indexes[0], i, fmt.Sprintf("%s references %s", activity.Source, issueKey))
+			}
+		}
+	}
+
+	// Meetings with a Notion note of the same title on the same local day
+	notesByDay := make(map[string][]int)
+	for i, activity := range activities {
+		if activity.Source == "Notion" {
+			notesByDay[activity.LocalDay()] = append(notesByDay[activity.LocalDay()], i)
+		}
+	}
+	for i, event := range activities {
+		if event.Kind != ActivityKindEvent {
+			continue
+		}
+		eventTitle := normalizeTitle(event.Title)
+		if len([]rune(eventTitle)) < minTitleMatchLength {
+			continue
+		}
+		for _, j := range notesByDay[event.LocalDay()] {
+			noteTitle := normalizeTitle(activities[j].Title)
+			if len([]rune(noteTitle)) < minTitleMatchLength {
+				continue
+			}
+			if strings.Contains(noteTitle, eventTitle) || strings.Contains(eventTitle, noteTitle) {
+				union(i, j, "meeting note for "+event.Title)
+			}
+		}
+	}
+
+	// Explicit links from config/overrides.yaml, in key order so that chained groups list their reasons the same way
+	// every run
+	linkKeys := make([]string, 0, len(links))
+	for from := range links {
+		linkKeys = append(linkKeys, from)
+	}
+	sort.Strings(linkKeys)
+	for _, from := range linkKeys {
+		targets := links[from]
+		fromIndexes := byKey[strings.TrimSuffix(from, "/")]
+		if len(fromIndexes) == 0 {
+			continue
+		}
+		for _, target := range targets {
+			for _, j := range byKey[strings.TrimSuffix(target, "/")] {
+				union(fromIndexes[0], j, "linked in overrides")
+			}
+		}
+	}
+
+	groups := make(map[int][]Activity)
+	var roots []int
+	for i, activity := range activities {
+		root := find(i)
+		if _, exists := groups[root]; !exists {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], activity)
+	}
+
+	var items []WorkItem
+	for _, root := range roots {
+		items = append(items, WorkItem{Activities: groups[root], Reasons: reasons[root]})
+	}
+	return items
+}
+
+// PrintLinkedWorkItems lists work items that appear in more than one source
+func PrintLinkedWorkItems(writer io.Writer, items []WorkItem) {
+	var linked []WorkItem
+	for _, item := range items {
+		sources := make(map[string]bool)
+		for _, activity := range item.Activities {
+			sources[activity.Source] = true
+		}
+		if len(sources) > 1 {
+			linked = append(linked, item)
+		}
+	}
+	if len(linked) == 0 {
+		return
+	}
+
+	sort.SliceStable(linked, func(i, j int) bool {
+		return linked[i].Activities[0].Time.Before(linked[j].Activities[0].Time)
+	})
+
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(writer, "LINKED WORK ITEMS (%d, counted once in PROJECTS)\n", len(linked))
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	for _, item := range linked {
+		fmt.Fprintf(writer, "\n- %s\n", strings.Join(uniqueStrings(item.Reasons), "; "))
+		for _, activity := range item.Activities {
+			fmt.Fprintf(writer, "  [%s] %s %s\n", activity.Source, activity.LocalDay(), activity.Title)
+		}
+	}
+}
+
+// normalizeTitle lowercases a title and collapses whitespace for matching
+func normalizeTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// activityIssueKey returns the key of the issue an activity belongs to: Backlog activities (/view/KEY)
+// and Jira worklogs (/browse/KEY). Other activities return "".
+func activityIssueKey(activity Activity) string {
+	var marker string
+	switch {
+	case strings.HasPrefix(activity.Source, "Backlog"):
+		marker = "/view/"
+	case activity.Kind == ActivityKindWorklog:
+		marker = "/browse/"
+	default:
+		return ""
+	}
+	if idx := strings.LastIndex(activity.URL, marker); idx != -1 {
+		return activity.URL[idx+len(marker):]
+	}
+	return ""
+}
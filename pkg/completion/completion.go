@@ -0,0 +1,356 @@
+package completion
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"dev-stats/pkg/common"
+)
+
+// Shells lists the shells a completion script can be generated for
+var Shells = []string{"bash", "zsh", "fish"}
+
+// Flag is a command-line flag and the values shell completion offers for it
+type Flag struct {
+	Name    string
+	Usage   string
+	Arg     string   // placeholder of the value (e.g. string, duration), empty for boolean flags
+	Values  []string // fixed values
+	Dynamic string   // kind passed to "<program> completion values <kind>" to list the values when completing
+	List    bool     // the value is a comma-separated list
+	File    bool     // the value is a path
+}
+
+// Command is a subcommand (dev-stats doctor, dev-stats cache clear, ...)
+type Command struct {
+	Name     string
+	Synopsis string // arguments after the name, e.g. "[-update] [case...]"
+	Summary  string
+	Args     []string // words offered after the command
+	Flags    []Flag
+}
+
+// Spec describes the command line that completion scripts and the man page are generated from
+type Spec struct {
+	Program  string
+	Summary  string
+	Flags    []Flag
+	Commands []Command
+}
+
+// FlagsOf converts the flags of a flag set, adding the values to offer for the flags named in values
+func FlagsOf(flags *flag.FlagSet, values map[string]Flag) []Flag {
+	var result []Flag
+	flags.VisitAll(func(f *flag.Flag) {
+		arg, usage := flag.UnquoteUsage(f)
+		completion := values[f.Name]
+		completion.Name, completion.Usage, completion.Arg = f.Name, usage, arg
+		result = append(result, completion)
+	})
+	return result
+}
+
+// Write writes the completion script for shell
+func Write(writer io.Writer, shell string, spec *Spec) error {
+	switch shell {
+	case "bash":
+		writeBash(writer, spec)
+	case "zsh":
+		writeZsh(writer, spec)
+	case "fish":
+		writeFish(writer, spec)
+	default:
+		return common.NewError("unknown shell %q (expected %s)", shell, strings.Join(Shells, ", "))
+	}
+	return nil
+}
+
+// valueFlags returns the names of the flags that take a value, sorted
+func valueFlags(flags []Flag) []string {
+	var names []string
+	for _, f := range flags {
+		if f.Arg != "" {
+			names = append(names, f.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// commandFlags returns the flags of every command, prefixed with an empty command name for the global flags
+func (s *Spec) commandFlags() []Command {
+	return append([]Command{{Flags: s.Flags}}, s.Commands...)
+}
+
+// funcName returns the shell function name of the program (dev-stats → _dev_stats)
+func (s *Spec) funcName() string {
+	return "_" + strings.NewReplacer("-", "_", ".", "_").Replace(s.Program)
+}
+
+func writeBash(writer io.Writer, spec *Spec) {
+	fn := spec.funcName()
+	fmt.Fprintf(writer, "# bash completion for %s (generated by \"%s completion bash\")\n\n", spec.Program, spec.Program)
+	fmt.Fprintf(writer, "%s_values() {\n", fn)
+	fmt.Fprintln(writer, `    local cur="$1" prefix=""`)
+	fmt.Fprintln(writer, `    if [[ "$3" == list && "$cur" == *,* ]]; then`)
+	fmt.Fprintln(writer, `        prefix="${cur%,*},"`)
+	fmt.Fprintln(writer, `        cur="${cur##*,}"`)
+	fmt.Fprintln(writer, `    fi`)
+	fmt.Fprintln(writer, `    COMPREPLY=($(compgen -P "$prefix" -W "$2" -- "$cur"))`)
+	fmt.Fprintf(writer, "}\n\n")
+
+	fmt.Fprintf(writer, "%s() {\n", fn)
+	fmt.Fprintln(writer, `    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	fmt.Fprintln(writer, `    local cmd="" skip="" i`)
+	fmt.Fprintln(writer, `    for ((i = 1; i < COMP_CWORD; i++)); do`)
+	fmt.Fprintln(writer, `        if [[ -n "$skip" ]]; then`)
+	fmt.Fprintln(writer, `            skip=""`)
+	fmt.Fprintln(writer, `            continue`)
+	fmt.Fprintln(writer, `        fi`)
+	fmt.Fprintln(writer, `        case "${COMP_WORDS[i]}" in`)
+	if names := valueFlags(spec.Flags); len(names) > 0 {
+		fmt.Fprintf(writer, "            -%s) skip=1 ;;\n", strings.Join(names, "|-"))
+	}
+	fmt.Fprintln(writer, `            -*) ;;`)
+	fmt.Fprintln(writer, `            *) cmd="${COMP_WORDS[i]}"; break ;;`)
+	fmt.Fprintln(writer, `        esac`)
+	fmt.Fprintln(writer, `    done`)
+	fmt.Fprintln(writer)
+
+	fmt.Fprintln(writer, `    case "$cmd:$prev" in`)
+	for _, command := range spec.commandFlags() {
+		for _, f := range command.Flags {
+			if f.Arg == "" {
+				continue
+			}
+			fmt.Fprintf(writer, "        %s:-%s)\n", command.Name, f.Name)
+			switch {
+			case f.Dynamic != "":
+				fmt.Fprintf(writer, "            %s_values \"$cur\" \"$(\"${COMP_WORDS[0]}\" completion values %s 2>/dev/null)\" %s\n", fn, f.Dynamic, bashList(f))
+			case len(f.Values) > 0:
+				fmt.Fprintf(writer, "            %s_values \"$cur\" %q %s\n", fn, strings.Join(f.Values, " "), bashList(f))
+			case f.File:
+				fmt.Fprintln(writer, `            COMPREPLY=($(compgen -f -- "$cur"))`)
+			}
+			fmt.Fprintln(writer, `            return ;;`)
+		}
+	}
+	fmt.Fprintln(writer, `    esac`)
+	fmt.Fprintln(writer)
+
+	fmt.Fprintln(writer, `    if [[ "$cur" == -* ]]; then`)
+	fmt.Fprintln(writer, `        case "$cmd" in`)
+	for _, command := range spec.commandFlags() {
+		if len(command.Flags) == 0 {
+			continue
+		}
+		var names []string
+		for _, f := range command.Flags {
+			names = append(names, "-"+f.Name)
+		}
+		fmt.Fprintf(writer, "            %q) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", command.Name, strings.Join(names, " "))
+	}
+	fmt.Fprintln(writer, `        esac`)
+	fmt.Fprintln(writer, `        return`)
+	fmt.Fprintln(writer, `    fi`)
+	fmt.Fprintln(writer, `    case "$cmd" in`)
+	var names []string
+	for _, command := range spec.Commands {
+		names = append(names, command.Name)
+	}
+	fmt.Fprintf(writer, "        \"\") COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", strings.Join(names, " "))
+	for _, command := range spec.Commands {
+		if len(command.Args) > 0 {
+			fmt.Fprintf(writer, "        %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", command.Name, strings.Join(command.Args, " "))
+		}
+	}
+	fmt.Fprintln(writer, `    esac`)
+	fmt.Fprintln(writer, `}`)
+	fmt.Fprintln(writer)
+	fmt.Fprintf(writer, "complete -F %s %s\n", fn, spec.Program)
+}
+
+func bashList(f Flag) string {
+	if f.List {
+		return "list"
+	}
+	return ""
+}
+
+func writeZsh(writer io.Writer, spec *Spec) {
+	fn := spec.funcName()
+	fmt.Fprintf(writer, "#compdef %s\n", spec.Program)
+	fmt.Fprintf(writer, "# zsh completion for %s (generated by \"%s completion zsh\")\n\n", spec.Program, spec.Program)
+	fmt.Fprintf(writer, "%s() {\n", fn)
+	fmt.Fprintln(writer, `    local cmd="" skip="" i`)
+	fmt.Fprintln(writer, `    local -a entries`)
+	fmt.Fprintln(writer, `    for ((i = 2; i < CURRENT; i++)); do`)
+	fmt.Fprintln(writer, `        if [[ -n $skip ]]; then`)
+	fmt.Fprintln(writer, `            skip=""`)
+	fmt.Fprintln(writer, `            continue`)
+	fmt.Fprintln(writer, `        fi`)
+	fmt.Fprintln(writer, `        case ${words[i]} in`)
+	if names := valueFlags(spec.Flags); len(names) > 0 {
+		fmt.Fprintf(writer, "            (-%s) skip=1 ;;\n", strings.Join(names, "|-"))
+	}
+	fmt.Fprintln(writer, `            (-*) ;;`)
+	fmt.Fprintln(writer, `            (*) cmd=${words[i]}; break ;;`)
+	fmt.Fprintln(writer, `        esac`)
+	fmt.Fprintln(writer, `    done`)
+	fmt.Fprintln(writer)
+
+	fmt.Fprintln(writer, `    case "$cmd:${words[CURRENT-1]}" in`)
+	for _, command := range spec.commandFlags() {
+		for _, f := range command.Flags {
+			if f.Arg == "" {
+				continue
+			}
+			fmt.Fprintf(writer, "        (%s:-%s)\n", command.Name, f.Name)
+			values := ""
+			switch {
+			case f.Dynamic != "":
+				values = fmt.Sprintf("${(f)\"$(${words[1]} completion values %s 2>/dev/null)\"}", f.Dynamic)
+			case len(f.Values) > 0:
+				values = strings.Join(f.Values, " ")
+			}
+			switch {
+			case values != "" && f.List:
+				fmt.Fprintf(writer, "            _values -s , %s %s\n", f.Name, values)
+			case values != "":
+				fmt.Fprintf(writer, "            compadd -- %s\n", values)
+			case f.File:
+				fmt.Fprintln(writer, `            _files`)
+			default:
+				fmt.Fprintf(writer, "            _message %s\n", zshQuote(f.Arg))
+			}
+			fmt.Fprintln(writer, `            return ;;`)
+		}
+	}
+	fmt.Fprintln(writer, `    esac`)
+	fmt.Fprintln(writer)
+
+	fmt.Fprintln(writer, `    if [[ $PREFIX == -* ]]; then`)
+	fmt.Fprintln(writer, `        case $cmd in`)
+	for _, command := range spec.commandFlags() {
+		if len(command.Flags) == 0 {
+			continue
+		}
+		var entries []string
+		for _, f := range command.Flags {
+			entries = append(entries, zshQuote("-"+f.Name+":"+f.Usage))
+		}
+		fmt.Fprintf(writer, "            (%s) entries=(\n                %s\n            ) ;;\n",
+			zshQuote(command.Name), strings.Join(entries, "\n                "))
+	}
+	fmt.Fprintln(writer, `        esac`)
+	fmt.Fprintln(writer, `        _describe -t flags flag entries`)
+	fmt.Fprintln(writer, `        return`)
+	fmt.Fprintln(writer, `    fi`)
+	fmt.Fprintln(writer, `    case $cmd in`)
+	var entries []string
+	for _, command := range spec.Commands {
+		entries = append(entries, zshQuote(command.Name+":"+command.Summary))
+	}
+	fmt.Fprintf(writer, "        (\"\")\n            entries=(\n                %s\n            )\n            _describe -t commands command entries ;;\n",
+		strings.Join(entries, "\n                "))
+	for _, command := range spec.Commands {
+		if len(command.Args) > 0 {
+			fmt.Fprintf(writer, "        (%s) compadd -- %s ;;\n", command.Name, strings.Join(command.Args, " "))
+		}
+	}
+	fmt.Fprintln(writer, `    esac`)
+	fmt.Fprintln(writer, `}`)
+	fmt.Fprintln(writer)
+	fmt.Fprintf(writer, "if [[ $funcstack[1] == %s ]]; then\n    %s \"$@\"\nelse\n    compdef %s %s\nfi\n", fn, fn, fn, spec.Program)
+}
+
+// zshQuote quotes a word in single quotes for zsh and fish, closing, escaping, and reopening around quotes inside
+func zshQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func writeFish(writer io.Writer, spec *Spec) {
+	fn := strings.TrimPrefix(spec.funcName(), "_")
+	fmt.Fprintf(writer, "# fish completion for %s (generated by \"%s completion fish\")\n\n", spec.Program, spec.Program)
+	fmt.Fprintf(writer, "function __%s_command\n", fn)
+	fmt.Fprintln(writer, `    set -l tokens (commandline -opc)`)
+	fmt.Fprintln(writer, `    set -l skip`)
+	fmt.Fprintln(writer, `    for token in $tokens[2..-1]`)
+	fmt.Fprintln(writer, `        if set -q skip[1]`)
+	fmt.Fprintln(writer, `            set -e skip`)
+	fmt.Fprintln(writer, `            continue`)
+	fmt.Fprintln(writer, `        end`)
+	fmt.Fprintln(writer, `        switch $token`)
+	if names := valueFlags(spec.Flags); len(names) > 0 {
+		fmt.Fprintf(writer, "            case -%s\n                set skip 1\n", strings.Join(names, " -"))
+	}
+	fmt.Fprintln(writer, `            case '-*'`)
+	fmt.Fprintln(writer, `            case '*'`)
+	fmt.Fprintln(writer, `                echo $token`)
+	fmt.Fprintln(writer, `                return`)
+	fmt.Fprintln(writer, `        end`)
+	fmt.Fprintln(writer, `    end`)
+	fmt.Fprintln(writer, `    echo ""`)
+	fmt.Fprintf(writer, "end\n\n")
+	fmt.Fprintf(writer, "function __%s_command_is\n", fn)
+	fmt.Fprintf(writer, "    test (__%s_command) = \"$argv[1]\"\n", fn)
+	fmt.Fprintf(writer, "end\n\n")
+	fmt.Fprintf(writer, "# Offers values after the commas already typed in a comma-separated list\n")
+	fmt.Fprintf(writer, "function __%s_list\n", fn)
+	fmt.Fprintln(writer, `    set -l prefix (string replace -r '[^,]*$' '' -- (commandline -ct))`)
+	fmt.Fprintln(writer, `    for value in $argv`)
+	fmt.Fprintln(writer, `        echo $prefix$value`)
+	fmt.Fprintln(writer, `    end`)
+	fmt.Fprintf(writer, "end\n\n")
+
+	fmt.Fprintf(writer, "complete -c %s -f\n", spec.Program)
+	for _, command := range spec.commandFlags() {
+		condition := fmt.Sprintf("-n '__%s_command_is %s'", fn, fishCommandName(command.Name))
+		for _, f := range command.Flags {
+			line := fmt.Sprintf("complete -c %s %s -o %s", spec.Program, condition, f.Name)
+			if f.Arg != "" {
+				line += " -r"
+			}
+			values := ""
+			switch {
+			case f.Dynamic != "":
+				values = fmt.Sprintf("(%s completion values %s 2>/dev/null)", spec.Program, f.Dynamic)
+			case len(f.Values) > 0:
+				values = strings.Join(f.Values, " ")
+			}
+			switch {
+			case values != "" && f.List:
+				line += fmt.Sprintf(" -a \"(__%s_list %s)\"", fn, values)
+			case values != "":
+				line += fmt.Sprintf(" -a %q", values)
+			case f.File:
+				line += " -F"
+			}
+			if f.Usage != "" {
+				line += " -d " + zshQuote(f.Usage)
+			}
+			fmt.Fprintln(writer, line)
+		}
+	}
+	for _, command := range spec.Commands {
+		fmt.Fprintf(writer, "complete -c %s -n '__%s_command_is \"\"' -a %s -d %s\n",
+			spec.Program, fn, command.Name, zshQuote(command.Summary))
+	}
+	for _, command := range spec.Commands {
+		if len(command.Args) > 0 {
+			fmt.Fprintf(writer, "complete -c %s -n '__%s_command_is %s' -a %q\n",
+				spec.Program, fn, command.Name, strings.Join(command.Args, " "))
+		}
+	}
+}
+
+// fishCommandName returns the command name as an argument of the fish condition, "" for the global flags
+func fishCommandName(name string) string {
+	if name == "" {
+		return `""`
+	}
+	return name
+}
@@ -0,0 +1,73 @@
+package completion
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMan writes a man page (section 1, roff) of the commands and flags in spec. environment lists the variables
+// described in the ENVIRONMENT section as name and description pairs.
+func WriteMan(writer io.Writer, spec *Spec, environment [][2]string) {
+	program := roffEscape(spec.Program)
+	fmt.Fprintf(writer, ".TH %s 1 \"\" \"%s\" \"User Commands\"\n", strings.ToUpper(program), program)
+	fmt.Fprintln(writer, ".SH NAME")
+	fmt.Fprintf(writer, "%s \\- %s\n", program, roffEscape(spec.Summary))
+
+	fmt.Fprintln(writer, ".SH SYNOPSIS")
+	fmt.Fprintf(writer, ".B %s\n[\\fIflags\\fR] \\fB\\-analyzer\\fR \\fIname\\fR[,\\fIname\\fR...]\n.br\n", program)
+	fmt.Fprintf(writer, ".B %s\n[\\fIflags\\fR] \\fIcommand\\fR [\\fIargs\\fR...]\n", program)
+
+	fmt.Fprintln(writer, ".SH DESCRIPTION")
+	fmt.Fprintf(writer, ".B %s\n", program)
+	fmt.Fprintln(writer, "runs the analyzers named by \\fB\\-analyzer\\fR for the period from START_DATE to END_DATE and writes")
+	fmt.Fprintln(writer, "their reports under \\fIoutput/<period>/\\fR. Credentials and options are read from the environment")
+	fmt.Fprintln(writer, "and from \\fI.env\\fR in the working directory. Flags go before the command.")
+
+	fmt.Fprintln(writer, ".SH COMMANDS")
+	for _, command := range spec.Commands {
+		fmt.Fprintln(writer, ".TP")
+		fmt.Fprintln(writer, strings.TrimSpace(fmt.Sprintf("\\fB%s\\fR %s", roffEscape(command.Name), roffEscape(command.Synopsis))))
+		fmt.Fprintln(writer, roffEscape(command.Summary))
+	}
+
+	fmt.Fprintln(writer, ".SH OPTIONS")
+	for _, f := range spec.Flags {
+		fmt.Fprintln(writer, ".TP")
+		if f.Arg != "" {
+			fmt.Fprintf(writer, "\\fB\\-%s\\fR \\fI%s\\fR\n", roffEscape(f.Name), roffEscape(f.Arg))
+		} else {
+			fmt.Fprintf(writer, "\\fB\\-%s\\fR\n", roffEscape(f.Name))
+		}
+		fmt.Fprintln(writer, roffEscape(f.Usage))
+	}
+
+	if len(environment) > 0 {
+		fmt.Fprintln(writer, ".SH ENVIRONMENT")
+		for _, variable := range environment {
+			fmt.Fprintln(writer, ".TP")
+			fmt.Fprintf(writer, ".B %s\n", roffEscape(variable[0]))
+			fmt.Fprintln(writer, roffEscape(variable[1]))
+		}
+	}
+
+	fmt.Fprintln(writer, ".SH FILES")
+	for _, file := range [][2]string{
+		{".env", "Credentials and options, loaded when the variables are not already set (template: .env.example)"},
+		{"config/*.yaml", "Categorization, scopes, overrides, and other rules"},
+		{"output/<period>/", "Reports, raw data, and CSV/JSON exports of each run"},
+	} {
+		fmt.Fprintln(writer, ".TP")
+		fmt.Fprintf(writer, ".I %s\n", roffEscape(file[0]))
+		fmt.Fprintln(writer, roffEscape(file[1]))
+	}
+}
+
+// roffEscape escapes backslashes and hyphens, and keeps lines from starting with a control character
+func roffEscape(value string) string {
+	value = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(value)
+	if strings.HasPrefix(value, ".") || strings.HasPrefix(value, "'") {
+		value = `\&` + value
+	}
+	return value
+}
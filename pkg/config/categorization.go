@@ -5,8 +5,6 @@ import (
 	"os"
 	"sort"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 // CategorizationConfig represents the shared configuration for event categorization
@@ -33,11 +31,13 @@ type NotionRule struct {
 	Keywords []string `yaml:"keywords"`
 }
 
+// DefaultCategorizationPath is the categorization config used when no path is given
+const DefaultCategorizationPath = "config/categorization.yaml"
+
 // LoadCategorizationConfig loads categorization configuration from YAML file
 func LoadCategorizationConfig(configPath string) (*CategorizationConfig, error) {
 	if configPath == "" {
-		// Default config path
-		configPath = "config/categorization.yaml"
+		configPath = DefaultCategorizationPath
 	}
 
 	// Check if file exists
@@ -45,14 +45,14 @@ func LoadCategorizationConfig(configPath string) (*CategorizationConfig, error)
 		return nil, fmt.Errorf("configuration file %s not found. Please create this file with categorization rules", configPath)
 	}
 
-	data, err := os.ReadFile(configPath)
+	var config CategorizationConfig
+	root, err := LoadStrictYAML(configPath, &config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+		return nil, err
 	}
 
-	var config CategorizationConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+	if problems := validateCategorizationNode(root, &config); len(problems) > 0 {
+		return nil, &ValidationError{Path: configPath, Problems: problems}
 	}
 
 	return &config, nil
@@ -119,6 +119,28 @@ func (config *CategorizationConfig) GetCategoryTime(title string) string {
 	return "other"
 }
 
+// HasCategory reports whether name is a known category, event rule, Notion rule, or "other"
+func (config *CategorizationConfig) HasCategory(name string) bool {
+	if name == "other" {
+		return true
+	}
+	_, isCategory := config.Categories[name]
+	_, isEventRule := config.EventCategories[name]
+	_, isNotionRule := config.NotionCategories[name]
+	return isCategory || isEventRule || isNotionRule
+}
+
+// MainCategory resolves a category or event rule name to its main category
+func (config *CategorizationConfig) MainCategory(name string) string {
+	if _, exists := config.Categories[name]; exists {
+		return name
+	}
+	if rule, exists := config.EventCategories[name]; exists {
+		return rule.Category
+	}
+	return "other"
+}
+
 // GetCategoryDisplayName returns the display name for a category
 func (config *CategorizationConfig) GetCategoryDisplayName(category string) string {
 	if def, exists := config.Categories[category]; exists {
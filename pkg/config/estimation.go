@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultEstimationPath is the user-maintained estimation coefficients file used when no path is given
+const DefaultEstimationPath = "config/estimation.yaml"
+
+// EstimationRule converts an item without a recorded duration into approximate hours:
+// base_hours + hours_per_unit * size, capped at max_hours (0 means no cap).
+// Size is changed lines for PRs and words for Notion pages.
+type EstimationRule struct {
+	BaseHours    float64 `yaml:"base_hours"`
+	HoursPerUnit float64 `yaml:"hours_per_unit"`
+	MaxHours     float64 `yaml:"max_hours"`
+}
+
+// EstimationConfig holds estimation rules per source and activity kind.
+// Sources are lowercase analyzer names (github, backlog, notion, google, todoist, gitea, phabricator); kind "*" applies to unlisted kinds.
+type EstimationConfig struct {
+	Sources map[string]map[string]EstimationRule `yaml:"sources"`
+}
+
+// defaultEstimationRules are used for any source/kind not configured in the estimation file
+var defaultEstimationRules = map[string]map[string]EstimationRule{
+	"github": {
+		"pr_authored": {BaseHours: 1, HoursPerUnit: 0.01, MaxHours: 16},
+		"pr_involved": {BaseHours: 0.5},
+	},
+	"backlog": {
+		"Issue Created":                 {BaseHours: 0.5},
+		"Issue Updated":                 {BaseHours: 0.25},
+		"Issue Commented":               {BaseHours: 0.25},
+		"Wiki Created":                  {BaseHours: 1},
+		"Wiki Updated":                  {BaseHours: 0.5},
+		"Pull Request Added":            {BaseHours: 1},
+		"Comment Added on Pull Request": {BaseHours: 0.25},
+		"*":                             {BaseHours: 0.1},
+	},
+	"notion": {
+		"page_created": {BaseHours: 0.25, HoursPerUnit: 0.002, MaxHours: 8},
+		"page_updated": {BaseHours: 0.25},
+		"task_done":    {BaseHours: 0.25},
+	},
+	"google": {
+		"file_created": {BaseHours: 1},
+		"file_updated": {BaseHours: 0.5},
+	},
+	"todoist": {
+		"task_completed": {BaseHours: 0.25},
+	},
+	"gitea": {
+		"pr_authored":   {BaseHours: 1},
+		"issue_created": {BaseHours: 0.25},
+		"issue_closed":  {BaseHours: 0.5},
+	},
+	"phabricator": {
+		"revision_authored": {BaseHours: 1},
+		"revision_reviewed": {BaseHours: 0.5},
+	},
+}
+
+// LoadEstimationConfig loads estimation coefficients. A missing file is not an error and uses the built-in defaults.
+func LoadEstimationConfig(path string) (*EstimationConfig, error) {
+	if path == "" {
+		path = DefaultEstimationPath
+	}
+
+	estimation := &EstimationConfig{}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return estimation, nil
+	}
+
+	root, err := LoadStrictYAML(path, estimation)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	if sources := mappingValue(documentRoot(root), "sources"); sources != nil {
+		for i := 0; i+1 < len(sources.Content); i += 2 {
+			sourceNode := sources.Content[i]
+			if _, known := defaultEstimationRules[sourceNode.Value]; !known {
+				problems = append(problems, fmt.Sprintf("line %d: unknown source '%s' (expected github, backlog, notion, google, todoist, gitea, or phabricator)", sourceNode.Line, sourceNode.Value))
+				continue
+			}
+			kinds := sources.Content[i+1]
+			for j := 0; j+1 < len(kinds.Content); j += 2 {
+				rule := estimation.Sources[sourceNode.Value][kinds.Content[j].Value]
+				if rule.BaseHours < 0 || rule.HoursPerUnit < 0 || rule.MaxHours < 0 {
+					problems = append(problems, fmt.Sprintf("line %d: %s.%s has a negative coefficient", kinds.Content[j].Line, sourceNode.Value, kinds.Content[j].Value))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: path, Problems: problems}
+	}
+
+	return estimation, nil
+}
+
+// EstimateHours returns approximate hours for an item of the given source, kind, and size.
+// The second value is false when no rule applies (e.g. calendar events, which have real durations).
+func (c *EstimationConfig) EstimateHours(source, kind string, size int) (float64, bool) {
+	rule, exists := c.rule(estimationSourceKey(source), kind)
+	if !exists {
+		return 0, false
+	}
+	hours := rule.BaseHours + rule.HoursPerUnit*float64(size)
+	if rule.MaxHours > 0 && hours > rule.MaxHours {
+		hours = rule.MaxHours
+	}
+	return hours, true
+}
+
+// rule looks up a configured rule first, then the built-in default, falling back to the "*" kind
+func (c *EstimationConfig) rule(source, kind string) (EstimationRule, bool) {
+	var configured map[string]EstimationRule
+	if c != nil {
+		configured = c.Sources[source]
+	}
+	for _, key := range []string{kind, "*"} {
+		if rule, exists := configured[key]; exists {
+			return rule, true
+		}
+		if rule, exists := defaultEstimationRules[source][key]; exists {
+			return rule, true
+		}
+	}
+	return EstimationRule{}, false
+}
+
+// estimationSourceKey maps an analyzer name such as "Google Workspace" to its config key ("google")
+func estimationSourceKey(source string) string {
+	fields := strings.Fields(strings.ToLower(source))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
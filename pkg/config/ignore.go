@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultIgnoreListPath is the user-maintained ignore file used when no path is given
+const DefaultIgnoreListPath = "config/ignore.yaml"
+
+// IgnoreList holds URLs, calendar UIDs, issue keys, and item IDs excluded from all counts and listings
+type IgnoreList struct {
+	Items []string `yaml:"items"`
+
+	entries map[string]bool
+}
+
+// LoadIgnoreList loads the ignore file. A missing file is not an error and ignores nothing.
+func LoadIgnoreList(path string) (*IgnoreList, error) {
+	if path == "" {
+		path = DefaultIgnoreListPath
+	}
+
+	ignoreList := &IgnoreList{entries: make(map[string]bool)}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return ignoreList, nil
+	}
+
+	root, err := LoadStrictYAML(path, ignoreList)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	firstLines := make(map[string]int)
+	if items := mappingValue(documentRoot(root), "items"); items != nil {
+		for _, item := range items.Content {
+			key := normalizeItemKey(item.Value)
+			if key == "" {
+				problems = append(problems, fmt.Sprintf("line %d: empty entry", item.Line))
+				continue
+			}
+			if first, exists := firstLines[key]; exists {
+				problems = append(problems, fmt.Sprintf("line %d: duplicate entry '%s' (already listed on line %d)", item.Line, item.Value, first))
+				continue
+			}
+			firstLines[key] = item.Line
+			ignoreList.entries[key] = true
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: path, Problems: problems}
+	}
+
+	return ignoreList, nil
+}
+
+// Contains reports whether any of keys (ID, UID, URL, issue key) is on the ignore list
+func (l *IgnoreList) Contains(keys ...string) bool {
+	if l == nil {
+		return false
+	}
+	for _, key := range keys {
+		if key != "" && l.entries[normalizeItemKey(key)] {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of ignored entries
+func (l *IgnoreList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.entries)
+}
+
+// Reload reads the default ignore file into the list, so that analyzers pick up edits at the start of each run
+func (l *IgnoreList) Reload() error {
+	loaded, err := LoadIgnoreList("")
+	if err != nil {
+		return err
+	}
+	*l = *loaded
+	return nil
+}
+
+// FilterIgnored drops the items any of whose keys is on the ignore list and prints how many it dropped
+// ("Ignored 3 events listed in config/ignore.yaml"); a nil writer drops them silently
+func FilterIgnored[T any](writer io.Writer, l *IgnoreList, items []T, noun string, keys func(T) []string) []T {
+	var kept []T
+	for _, item := range items {
+		if !l.Contains(keys(item)...) {
+			kept = append(kept, item)
+		}
+	}
+	if ignored := len(items) - len(kept); ignored > 0 && writer != nil {
+		fmt.Fprintf(writer, "Ignored %d %s listed in %s\n", ignored, noun, DefaultIgnoreListPath)
+	}
+	return kept
+}
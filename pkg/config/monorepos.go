@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultMonorepoConfigPath is the monorepo mapping file used when no path is given
+const DefaultMonorepoConfigPath = "config/monorepos.yaml"
+
+// MonorepoConfig maps path prefixes of monorepos to sub-projects
+type MonorepoConfig struct {
+	Repositories map[string]MonorepoDefinition `yaml:"repositories"`
+}
+
+// MonorepoDefinition maps path prefixes (e.g. "services/api/") to project names
+type MonorepoDefinition struct {
+	Projects map[string]string `yaml:"projects"`
+}
+
+// LoadMonorepoConfig loads the monorepo mapping. A missing file is not an error and configures no monorepos.
+func LoadMonorepoConfig(path string) (*MonorepoConfig, error) {
+	if path == "" {
+		path = DefaultMonorepoConfigPath
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &MonorepoConfig{}, nil
+	}
+
+	var monorepos MonorepoConfig
+	root, err := LoadStrictYAML(path, &monorepos)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	if repositories := mappingValue(documentRoot(root), "repositories"); repositories != nil {
+		for i := 0; i+1 < len(repositories.Content); i += 2 {
+			repoNode := repositories.Content[i]
+			if !strings.Contains(repoNode.Value, "/") {
+				problems = append(problems, fmt.Sprintf("line %d: repository '%s' must be in owner/repo form", repoNode.Line, repoNode.Value))
+			}
+			projects := mappingValue(repositories.Content[i+1], "projects")
+			if projects == nil || len(projects.Content) == 0 {
+				problems = append(problems, fmt.Sprintf("line %d: repository '%s' has no projects", repoNode.Line, repoNode.Value))
+				continue
+			}
+			for j := 0; j+1 < len(projects.Content); j += 2 {
+				if strings.TrimSpace(projects.Content[j+1].Value) == "" {
+					problems = append(problems, fmt.Sprintf("line %d: path prefix '%s' has no project name", projects.Content[j].Line, projects.Content[j].Value))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: path, Problems: problems}
+	}
+
+	// Repository names are matched case-insensitively like on GitHub
+	normalized := make(map[string]MonorepoDefinition, len(monorepos.Repositories))
+	for repo, definition := range monorepos.Repositories {
+		normalized[strings.ToLower(repo)] = definition
+	}
+	monorepos.Repositories = normalized
+
+	return &monorepos, nil
+}
+
+// IsMonorepo reports whether the repository (owner/repo) has a path mapping
+func (c *MonorepoConfig) IsMonorepo(repo string) bool {
+	if c == nil {
+		return false
+	}
+	_, exists := c.Repositories[strings.ToLower(repo)]
+	return exists
+}
+
+// ProjectsForFiles returns the sorted projects touched by the changed files.
+// Each file is attributed to its longest matching prefix; files outside every prefix are not attributed.
+func (c *MonorepoConfig) ProjectsForFiles(repo string, files []string) []string {
+	if c == nil {
+		return nil
+	}
+	definition, exists := c.Repositories[strings.ToLower(repo)]
+	if !exists {
+		return nil
+	}
+
+	found := make(map[string]bool)
+	for _, file := range files {
+		bestPrefix := ""
+		for prefix := range definition.Projects {
+			if strings.HasPrefix(file, prefix) && len(prefix) > len(bestPrefix) {
+				bestPrefix = prefix
+			}
+		}
+		if bestPrefix != "" {
+			found[definition.Projects[bestPrefix]] = true
+		}
+	}
+
+	var projects []string
+	for project := range found {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+	return projects
+}
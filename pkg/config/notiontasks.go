@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultNotionTasksPath is the Notion task database config used when no path is given
+const DefaultNotionTasksPath = "config/notion-tasks.yaml"
+
+// NotionTaskDatabase describes how to tell that a task in a Notion database is done
+type NotionTaskDatabase struct {
+	ID             string   `yaml:"id"`
+	Name           string   `yaml:"name"`            // label in reports (default: the database ID)
+	StatusProperty string   `yaml:"status_property"` // status, select, or checkbox property
+	DoneValues     []string `yaml:"done_values"`     // status/select options that mean done (unused for checkbox)
+	// CompletedDateProperty is a date property set when the task is done; without it, last_edited_time approximates the completion date
+	CompletedDateProperty string `yaml:"completed_date_property"`
+	// AssigneeProperty is a people property; when set, only tasks assigned to NOTION_USER_ID (or the detected user) are counted
+	AssigneeProperty string `yaml:"assignee_property"`
+}
+
+// NotionTasksConfig lists Notion task databases whose completed tasks are counted
+type NotionTasksConfig struct {
+	Databases []NotionTaskDatabase `yaml:"databases"`
+}
+
+// LoadNotionTasksConfig loads the task database config. A missing file is not an error and configures no databases.
+func LoadNotionTasksConfig(path string) (*NotionTasksConfig, error) {
+	if path == "" {
+		path = DefaultNotionTasksPath
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &NotionTasksConfig{}, nil
+	}
+
+	var tasks NotionTasksConfig
+	root, err := LoadStrictYAML(path, &tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	if databases := mappingValue(documentRoot(root), "databases"); databases != nil {
+		for i, node := range databases.Content {
+			if i >= len(tasks.Databases) {
+				break
+			}
+			database := tasks.Databases[i]
+			if strings.TrimSpace(database.ID) == "" {
+				problems = append(problems, fmt.Sprintf("line %d: database has no id", node.Line))
+			}
+			if strings.TrimSpace(database.StatusProperty) == "" {
+				problems = append(problems, fmt.Sprintf("line %d: database '%s' has no status_property", node.Line, database.ID))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: path, Problems: problems}
+	}
+
+	for i := range tasks.Databases {
+		tasks.Databases[i].ID = strings.ReplaceAll(strings.TrimSpace(tasks.Databases[i].ID), "-", "")
+		if tasks.Databases[i].Name == "" {
+			tasks.Databases[i].Name = tasks.Databases[i].ID
+		}
+	}
+	return &tasks, nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"dev-stats/pkg/common"
+)
+
+// DefaultOverridesPath is the user-maintained overrides file used when no path is given
+const DefaultOverridesPath = "config/overrides.yaml"
+
+// Override assigns a category and/or project to a specific item, taking precedence over keyword rules.
+// SameAs lists IDs/URLs of items in other sources that represent the same work.
+type Override struct {
+	Category string   `yaml:"category"`
+	Project  string   `yaml:"project"`
+	SameAs   []string `yaml:"same_as"`
+}
+
+// Overrides maps item IDs, calendar UIDs, or URLs to manual overrides
+type Overrides struct {
+	Items map[string]Override `yaml:"items"`
+}
+
+// LoadOverrides loads the overrides file. A missing file is not an error and yields no overrides.
+// Categories are checked against categoryConfig when it is given.
+func LoadOverrides(path string, categoryConfig *CategorizationConfig) (*Overrides, error) {
+	if path == "" {
+		path = DefaultOverridesPath
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &Overrides{}, nil
+	}
+
+	var overrides Overrides
+	root, err := LoadStrictYAML(path, &overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	items := mappingValue(documentRoot(root), "items")
+	if items != nil {
+		for i := 0; i+1 < len(items.Content); i += 2 {
+			key := items.Content[i]
+			override := overrides.Items[key.Value]
+			if override.Category == "" && override.Project == "" && len(override.SameAs) == 0 {
+				problems = append(problems, fmt.Sprintf("line %d: override for %s sets none of category, project, or same_as", key.Line, key.Value))
+				continue
+			}
+			if override.Category != "" && categoryConfig != nil && !categoryConfig.HasCategory(override.Category) {
+				line := key.Line
+				if categoryNode := mappingValue(items.Content[i+1], "category"); categoryNode != nil {
+					line = categoryNode.Line
+				}
+				problems = append(problems, fmt.Sprintf("line %d: override for %s refers to unknown category '%s'", line, key.Value, override.Category))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: path, Problems: problems}
+	}
+
+	// Normalize keys so that URLs match with or without a trailing slash
+	normalized := make(map[string]Override, len(overrides.Items))
+	for key, override := range overrides.Items {
+		normalized[normalizeItemKey(key)] = override
+	}
+	overrides.Items = normalized
+
+	return &overrides, nil
+}
+
+// Lookup returns the override for the first of keys (ID, UID, URL) that has one
+func (o *Overrides) Lookup(keys ...string) (Override, bool) {
+	if o == nil {
+		return Override{}, false
+	}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if override, exists := o.Items[normalizeItemKey(key)]; exists {
+			return override, true
+		}
+	}
+	return Override{}, false
+}
+
+// Apply sets the category and project of activities that have an override
+func (o *Overrides) Apply(activities []common.Activity) {
+	for i := range activities {
+		override, exists := o.Lookup(activities[i].ID, activities[i].URL)
+		if !exists {
+			continue
+		}
+		if override.Category != "" {
+			activities[i].Category = override.Category
+		}
+		if override.Project != "" {
+			activities[i].Project = override.Project
+		}
+	}
+}
+
+// SameAsLinks returns the explicit links between items (item -> items representing the same work)
+func (o *Overrides) SameAsLinks() map[string][]string {
+	links := make(map[string][]string)
+	if o == nil {
+		return links
+	}
+	for key, override := range o.Items {
+		if len(override.SameAs) > 0 {
+			links[key] = override.SameAs
+		}
+	}
+	return links
+}
+
+// normalizeItemKey trims whitespace and trailing slashes so that URLs match either way
+func normalizeItemKey(key string) string {
+	return strings.TrimSuffix(strings.TrimSpace(key), "/")
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultScopeConfigPath is the work/personal classification file used when no path is given
+const DefaultScopeConfigPath = "config/scopes.yaml"
+
+// Scopes a source can be classified in
+const (
+	ScopeWork     = "work"
+	ScopePersonal = "personal"
+)
+
+// ScopeConfig classifies sources as work or personal for -work-only / -personal-only runs
+type ScopeConfig struct {
+	// Sources maps output names (github, github-personal, backlog-hoge, calendar, notion, ...) to work or personal
+	Sources map[string]string `yaml:"sources"`
+}
+
+// LoadScopeConfig loads the classification. A missing file is not an error and classifies no sources.
+func LoadScopeConfig(path string) (*ScopeConfig, error) {
+	if path == "" {
+		path = DefaultScopeConfigPath
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &ScopeConfig{}, nil
+	}
+
+	var scopes ScopeConfig
+	root, err := LoadStrictYAML(path, &scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	if sources := mappingValue(documentRoot(root), "sources"); sources != nil {
+		for i := 0; i+1 < len(sources.Content); i += 2 {
+			value := sources.Content[i+1].Value
+			if value != ScopeWork && value != ScopePersonal {
+				problems = append(problems, fmt.Sprintf("line %d: source '%s' must be %s or %s, not '%s'",
+					sources.Content[i+1].Line, sources.Content[i].Value, ScopeWork, ScopePersonal, value))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: path, Problems: problems}
+	}
+
+	normalized := make(map[string]string, len(scopes.Sources))
+	for source, scope := range scopes.Sources {
+		normalized[strings.ToLower(source)] = scope
+	}
+	scopes.Sources = normalized
+	return &scopes, nil
+}
+
+// Scope returns the scope of an output name such as "backlog-hoge". Profiles without an entry of their own
+// use the analyzer's entry ("backlog"); "" means the source is not classified.
+func (c *ScopeConfig) Scope(name string) string {
+	if c == nil {
+		return ""
+	}
+	name = strings.ToLower(name)
+	if scope, exists := c.Sources[name]; exists {
+		return scope
+	}
+	if base, _, found := strings.Cut(name, "-"); found {
+		return c.Sources[base]
+	}
+	return ""
+}
+
+// Allows reports whether a source belongs in a run limited to scope; every source is allowed when scope is "".
+// Unclassified sources are left out of scoped runs so that nothing unexpected reaches the report.
+func (c *ScopeConfig) Allows(name, scope string) bool {
+	return scope == "" || c.Scope(name) == scope
+}
+
+// IsEmpty reports whether no source is classified
+func (c *ScopeConfig) IsEmpty() bool {
+	return c == nil || len(c.Sources) == 0
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// DefaultSprintsPath is the sprint definition file used when no path is given
+const DefaultSprintsPath = "config/sprints.yaml"
+
+// SprintDefinition is an explicitly listed sprint (dates in YYYY-MM-DD, inclusive)
+type SprintDefinition struct {
+	Name  string `yaml:"name"`
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+// SprintCadence generates fixed-length sprints from a first start date
+type SprintCadence struct {
+	FirstStart string `yaml:"first_start"`
+	LengthDays int    `yaml:"length_days"`
+	NamePrefix string `yaml:"name_prefix"` // sprints are named <prefix><number>, numbered from 1 at first_start
+}
+
+// SprintConfig defines sprint boundaries, either listed explicitly or by a cadence (listed sprints take precedence)
+type SprintConfig struct {
+	Sprints []SprintDefinition `yaml:"sprints"`
+	Cadence *SprintCadence     `yaml:"cadence"`
+
+	sprints []common.Sprint
+}
+
+// LoadSprintConfig loads sprint boundaries. A missing file is not an error and defines no sprints.
+func LoadSprintConfig(path string) (*SprintConfig, error) {
+	if path == "" {
+		path = DefaultSprintsPath
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &SprintConfig{}, nil
+	}
+
+	var sprintConfig SprintConfig
+	root, err := LoadStrictYAML(path, &sprintConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	if sprints := mappingValue(documentRoot(root), "sprints"); sprints != nil {
+		for i, node := range sprints.Content {
+			if i >= len(sprintConfig.Sprints) {
+				break
+			}
+			definition := sprintConfig.Sprints[i]
+			start, startErr := time.Parse("2006-01-02", definition.Start)
+			end, endErr := time.Parse("2006-01-02", definition.End)
+			switch {
+			case definition.Name == "":
+				problems = append(problems, fmt.Sprintf("line %d: sprint has no name", node.Line))
+			case startErr != nil || endErr != nil:
+				problems = append(problems, fmt.Sprintf("line %d: sprint '%s' needs start and end in YYYY-MM-DD format", node.Line, definition.Name))
+			case end.Before(start):
+				problems = append(problems, fmt.Sprintf("line %d: sprint '%s' ends before it starts", node.Line, definition.Name))
+			default:
+				sprintConfig.sprints = append(sprintConfig.sprints, common.Sprint{Name: definition.Name, Start: start, End: end})
+			}
+		}
+	}
+	if cadence := sprintConfig.Cadence; cadence != nil {
+		line := 0
+		if node := mappingValue(documentRoot(root), "cadence"); node != nil {
+			line = node.Line
+		}
+		if _, err := time.Parse("2006-01-02", cadence.FirstStart); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: cadence.first_start must be in YYYY-MM-DD format", line))
+		}
+		if cadence.LengthDays <= 0 {
+			problems = append(problems, fmt.Sprintf("line %d: cadence.length_days must be positive", line))
+		}
+	}
+
+	sort.SliceStable(sprintConfig.sprints, func(i, j int) bool {
+		return sprintConfig.sprints[i].Start.Before(sprintConfig.sprints[j].Start)
+	})
+	for i := 1; i < len(sprintConfig.sprints); i++ {
+		if !sprintConfig.sprints[i].Start.After(sprintConfig.sprints[i-1].End) {
+			problems = append(problems, fmt.Sprintf("sprints '%s' and '%s' overlap", sprintConfig.sprints[i-1].Name, sprintConfig.sprints[i].Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: path, Problems: problems}
+	}
+	return &sprintConfig, nil
+}
+
+// SprintsBetween returns the sprints overlapping startDate..endDate
+func (c *SprintConfig) SprintsBetween(startDate, endDate time.Time) []common.Sprint {
+	if c == nil {
+		return nil
+	}
+
+	sprints := c.sprints
+	if len(sprints) == 0 && c.Cadence != nil {
+		sprints = c.cadenceSprints(startDate, endDate)
+	}
+
+	var overlapping []common.Sprint
+	for _, sprint := range sprints {
+		if !sprint.End.Before(startDate) && !sprint.Start.After(endDate) {
+			overlapping = append(overlapping, sprint)
+		}
+	}
+	return overlapping
+}
+
+// cadenceSprints generates sprints from the cadence up to endDate
+func (c *SprintConfig) cadenceSprints(startDate, endDate time.Time) []common.Sprint {
+	firstStart, err := time.Parse("2006-01-02", c.Cadence.FirstStart)
+	if err != nil || c.Cadence.LengthDays <= 0 {
+		return nil
+	}
+
+	var sprints []common.Sprint
+	for number, start := 1, firstStart; !start.After(endDate); number, start = number+1, start.AddDate(0, 0, c.Cadence.LengthDays) {
+		end := start.AddDate(0, 0, c.Cadence.LengthDays-1)
+		if end.Before(startDate) {
+			continue
+		}
+		sprints = append(sprints, common.Sprint{Name: fmt.Sprintf("%s%d", c.Cadence.NamePrefix, number), Start: start, End: end})
+	}
+	return sprints
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError reports every problem found in a configuration file
+type ValidationError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration file %s:\n  - %s", e.Path, strings.Join(e.Problems, "\n  - "))
+}
+
+var unknownFieldPattern = regexp.MustCompile(`field (\S+) not found in type \S+`)
+
+// LoadStrictYAML reads a YAML file into out, rejecting unknown keys and values of the wrong type.
+// The parsed document node is returned so that callers can run additional checks with line numbers.
+func LoadStrictYAML(path string, out interface{}) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		var problems []string
+		for _, problem := range typeErr.Errors {
+			problems = append(problems, unknownFieldPattern.ReplaceAllString(problem, "unknown key '$1'"))
+		}
+		return nil, &ValidationError{Path: path, Problems: problems}
+	}
+
+	return &root, nil
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// documentRoot returns the top-level mapping of a parsed document
+func documentRoot(root *yaml.Node) *yaml.Node {
+	if root != nil && root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		return root.Content[0]
+	}
+	return root
+}
+
+// validateCategorizationNode checks keyword rules that the YAML decoder cannot detect
+func validateCategorizationNode(root *yaml.Node, config *CategorizationConfig) []string {
+	var problems []string
+	doc := documentRoot(root)
+
+	for _, section := range []string{"categories", "event_categories", "notion_categories"} {
+		sectionNode := mappingValue(doc, section)
+		if sectionNode == nil || sectionNode.Kind != yaml.MappingNode {
+			continue
+		}
+
+		// keyword -> first location, within a section rules are matched in order so duplicates shadow each other
+		type location struct {
+			entry string
+			line  int
+		}
+		seen := make(map[string]location)
+
+		for i := 0; i+1 < len(sectionNode.Content); i += 2 {
+			entryName := sectionNode.Content[i].Value
+			entryNode := sectionNode.Content[i+1]
+
+			keywords := mappingValue(entryNode, "keywords")
+			if keywords == nil || len(keywords.Content) == 0 {
+				problems = append(problems, fmt.Sprintf("line %d: %s.%s has no keywords", sectionNode.Content[i].Line, section, entryName))
+				continue
+			}
+
+			for _, keyword := range keywords.Content {
+				normalized := strings.ToLower(strings.TrimSpace(keyword.Value))
+				if normalized == "" {
+					problems = append(problems, fmt.Sprintf("line %d: empty keyword in %s.%s", keyword.Line, section, entryName))
+					continue
+				}
+				if first, exists := seen[normalized]; exists {
+					problems = append(problems, fmt.Sprintf("line %d: duplicate keyword '%s' in %s.%s (already used by %s.%s on line %d)",
+						keyword.Line, keyword.Value, section, entryName, section, first.entry, first.line))
+					continue
+				}
+				seen[normalized] = location{entry: entryName, line: keyword.Line}
+			}
+
+			if section == "event_categories" {
+				categoryNode := mappingValue(entryNode, "category")
+				if categoryNode == nil {
+					problems = append(problems, fmt.Sprintf("line %d: %s.%s has no category", sectionNode.Content[i].Line, section, entryName))
+				} else if _, exists := config.Categories[categoryNode.Value]; !exists && categoryNode.Value != "other" {
+					problems = append(problems, fmt.Sprintf("line %d: %s.%s refers to unknown category '%s'",
+						categoryNode.Line, section, entryName, categoryNode.Value))
+				}
+			}
+		}
+	}
+
+	if len(config.Categories) == 0 {
+		problems = append(problems, "no categories defined")
+	}
+
+	return problems
+}
@@ -0,0 +1,295 @@
+package copilot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+const githubAPIURL = "https://api.github.com"
+
+// CopilotAnalyzer implements the Analyzer interface for GitHub Copilot usage, read either from a
+// per-user export file (COPILOT_EXPORT_FILE) or from the organization/team metrics API (COPILOT_ORG)
+type CopilotAnalyzer struct {
+	token      string
+	username   string
+	org        string
+	team       string // COPILOT_TEAM: narrow the org metrics to a team (e.g. a team of one)
+	exportFile string
+	client     *common.HTTPClient
+}
+
+// DailyUsage is the Copilot usage of one day
+type DailyUsage struct {
+	Date           time.Time      `json:"date"`
+	Suggestions    int            `json:"suggestions"`
+	Acceptances    int            `json:"acceptances"`
+	LinesSuggested int            `json:"lines_suggested"`
+	LinesAccepted  int            `json:"lines_accepted"`
+	Chats          int            `json:"chats"`
+	EngagedUsers   int            `json:"engaged_users"` // 1 for an export, the number of org/team users for the API
+	ByLanguage     map[string]int `json:"by_language"`   // acceptances per language
+}
+
+// metricsDay is one day of the /orgs/{org}/copilot/metrics response
+type metricsDay struct {
+	Date                      string `json:"date"`
+	TotalEngagedUsers         int    `json:"total_engaged_users"`
+	CopilotIDECodeCompletions struct {
+		Editors []struct {
+			Models []struct {
+				Languages []struct {
+					Name                    string `json:"name"`
+					TotalCodeSuggestions    int    `json:"total_code_suggestions"`
+					TotalCodeAcceptances    int    `json:"total_code_acceptances"`
+					TotalCodeLinesSuggested int    `json:"total_code_lines_suggested"`
+					TotalCodeLinesAccepted  int    `json:"total_code_lines_accepted"`
+				} `json:"languages"`
+			} `json:"models"`
+		} `json:"editors"`
+	} `json:"copilot_ide_code_completions"`
+	CopilotIDEChat struct {
+		Editors []struct {
+			Models []struct {
+				TotalChats int `json:"total_chats"`
+			} `json:"models"`
+		} `json:"editors"`
+	} `json:"copilot_ide_chat"`
+}
+
+// NewCopilotAnalyzer creates a new Copilot analyzer
+func NewCopilotAnalyzer() *CopilotAnalyzer {
+	token := os.Getenv("GITHUB_TOKEN")
+	client := common.NewHTTPClient()
+	client.SetHeader("Authorization", "token "+token)
+	client.SetHeader("Accept", "application/vnd.github+json")
+	return &CopilotAnalyzer{
+		token:      token,
+		username:   os.Getenv("GITHUB_USERNAME"),
+		org:        os.Getenv("COPILOT_ORG"),
+		team:       os.Getenv("COPILOT_TEAM"),
+		exportFile: os.Getenv("COPILOT_EXPORT_FILE"),
+		client:     client,
+	}
+}
+
+// GetName returns the analyzer name
+func (c *CopilotAnalyzer) GetName() string {
+	return "Copilot"
+}
+
+// ValidateConfig validates the required configuration
+func (c *CopilotAnalyzer) ValidateConfig(writer io.Writer) error {
+	if c.exportFile != "" {
+		if _, err := os.Stat(c.exportFile); err != nil {
+			return common.WrapError(err, "COPILOT_EXPORT_FILE is not readable")
+		}
+		fmt.Fprintf(writer, "✓ Copilot export file found: %s\n", c.exportFile)
+		return nil
+	}
+	if c.org == "" || c.token == "" {
+		return common.NewError("COPILOT_EXPORT_FILE, or COPILOT_ORG and GITHUB_TOKEN environment variables are required")
+	}
+	if _, err := c.client.Get(c.metricsURL(url.Values{"per_page": {"1"}}), nil); err != nil {
+		return common.WrapError(err, "failed to access Copilot metrics of %s (the token needs manage_billing:copilot or read:org, and the Copilot metrics API policy must be enabled)", c.org)
+	}
+	fmt.Fprintf(writer, "✓ Copilot metrics of %s are accessible\n", c.scope())
+	return nil
+}
+
+// Analyze reports Copilot suggestions and acceptances per day and language
+func (c *CopilotAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := c.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+
+	var days []DailyUsage
+	var err error
+	if c.exportFile != "" {
+		fmt.Fprintf(writer, "Reading Copilot usage from %s...\n", c.exportFile)
+		days, err = readExport(c.exportFile, c.username, config.StartDate, config.EndDate)
+	} else {
+		fmt.Fprintf(writer, "Fetching Copilot metrics of %s from %s to %s...\n", c.scope(),
+			config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+		days, err = c.getMetrics(config.StartDate, config.EndDate)
+	}
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get Copilot usage")
+	}
+
+	var suggestions, acceptances, linesAccepted, chats, engagedUsers int
+	byLanguage := make(map[string]int)
+	activeDays := 0
+	for _, day := range days {
+		suggestions += day.Suggestions
+		acceptances += day.Acceptances
+		linesAccepted += day.LinesAccepted
+		chats += day.Chats
+		if day.EngagedUsers > engagedUsers {
+			engagedUsers = day.EngagedUsers
+		}
+		if day.Acceptances > 0 || day.Chats > 0 {
+			activeDays++
+		}
+		for language, count := range day.ByLanguage {
+			byLanguage[language] += count
+		}
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: c.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "copilot.suggestions", Label: "Suggestions shown", Value: suggestions},
+			{ID: "copilot.acceptances", Label: "Suggestions accepted", Value: acceptances},
+			{ID: "copilot.acceptance_rate", Label: "Acceptance rate (%)", Value: acceptanceRate(suggestions, acceptances), Snapshot: true},
+			{ID: "copilot.lines_accepted", Label: "Lines accepted", Value: linesAccepted},
+			{ID: "copilot.chats", Label: "Chats", Value: chats},
+			{ID: "copilot.active_days", Label: "Days using Copilot", Value: activeDays},
+		},
+		Details: map[string]interface{}{
+			"daily_usage": days,
+			"by_language": byLanguage,
+		},
+		Activities: c.buildActivities(days),
+		CSVTables:  csvTables(days),
+	}
+	result.Explain("copilot.acceptances", result.Activities)
+
+	c.printResults(writer, result, days, byLanguage)
+	if engagedUsers > 1 {
+		fmt.Fprintf(writer, "\n⚠️  The metrics of %s cover up to %d engaged users, not only you; set COPILOT_TEAM to a team of one or use COPILOT_EXPORT_FILE\n", c.scope(), engagedUsers)
+	}
+	return result, nil
+}
+
+// scope names the org or team whose metrics are read
+func (c *CopilotAnalyzer) scope() string {
+	if c.team != "" {
+		return c.org + "/" + c.team
+	}
+	return c.org
+}
+
+func (c *CopilotAnalyzer) metricsURL(params url.Values) string {
+	path := fmt.Sprintf("/orgs/%s/copilot/metrics", url.PathEscape(c.org))
+	if c.team != "" {
+		path = fmt.Sprintf("/orgs/%s/team/%s/copilot/metrics", url.PathEscape(c.org), url.PathEscape(c.team))
+	}
+	return fmt.Sprintf("%s%s?%s", githubAPIURL, path, params.Encode())
+}
+
+// getMetrics reads the daily org/team metrics; the API keeps only the last 100 days
+func (c *CopilotAnalyzer) getMetrics(startDate, endDate time.Time) ([]DailyUsage, error) {
+	params := url.Values{}
+	params.Set("since", startDate.UTC().Format(time.RFC3339))
+	params.Set("until", endDate.AddDate(0, 0, 1).UTC().Format(time.RFC3339))
+	params.Set("per_page", "100")
+	body, err := c.client.Get(c.metricsURL(params), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response []metricsDay
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, common.WrapError(err, "failed to parse Copilot metrics")
+	}
+
+	var days []DailyUsage
+	for _, item := range response {
+		date, err := time.ParseInLocation("2006-01-02", item.Date, time.Local)
+		if err != nil || date.Before(startDate) || date.After(endDate) {
+			continue
+		}
+		day := DailyUsage{Date: date, EngagedUsers: item.TotalEngagedUsers, ByLanguage: make(map[string]int)}
+		for _, editor := range item.CopilotIDECodeCompletions.Editors {
+			for _, model := range editor.Models {
+				for _, language := range model.Languages {
+					day.Suggestions += language.TotalCodeSuggestions
+					day.Acceptances += language.TotalCodeAcceptances
+					day.LinesSuggested += language.TotalCodeLinesSuggested
+					day.LinesAccepted += language.TotalCodeLinesAccepted
+					if language.TotalCodeAcceptances > 0 {
+						day.ByLanguage[language.Name] += language.TotalCodeAcceptances
+					}
+				}
+			}
+		}
+		for _, editor := range item.CopilotIDEChat.Editors {
+			for _, model := range editor.Models {
+				day.Chats += model.TotalChats
+			}
+		}
+		days = append(days, day)
+	}
+	sort.SliceStable(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+	return days, nil
+}
+
+// acceptanceRate returns the percentage of suggestions accepted, rounded to one decimal
+func acceptanceRate(suggestions, acceptances int) float64 {
+	if suggestions == 0 {
+		return 0
+	}
+	return math.Round(float64(acceptances)/float64(suggestions)*1000) / 10
+}
+
+// buildActivities converts days with accepted suggestions or chats into dated activities
+func (c *CopilotAnalyzer) buildActivities(days []DailyUsage) []common.Activity {
+	var activities []common.Activity
+	for _, day := range days {
+		if day.Acceptances == 0 && day.Chats == 0 {
+			continue
+		}
+		activities = append(activities, common.Activity{
+			Source: c.GetName(),
+			Kind:   "copilot_usage",
+			ID:     day.Date.Format("2006-01-02"),
+			Title:  fmt.Sprintf("Copilot: %d of %d suggestions accepted, chats: %d", day.Acceptances, day.Suggestions, day.Chats),
+			Time:   day.Date,
+		})
+	}
+	return activities
+}
+
+func (c *CopilotAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, days []DailyUsage, byLanguage map[string]int) {
+	fmt.Fprintf(writer, "\nCopilot suggestions accepted per day from %s to %s:\n",
+		result.StartDate.Format("2006-01-02"), result.EndDate.Format("2006-01-02"))
+	if len(days) == 0 {
+		fmt.Fprintln(writer, "- None")
+	}
+	for _, day := range days {
+		fmt.Fprintf(writer, "- %s: %d / %d accepted (%.1f%%), lines accepted: %d, chats: %d\n", day.Date.Format("2006-01-02"),
+			day.Acceptances, day.Suggestions, acceptanceRate(day.Suggestions, day.Acceptances), day.LinesAccepted, day.Chats)
+	}
+
+	result.PrintSummary(writer)
+
+	var languages []string
+	for language := range byLanguage {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if byLanguage[languages[i]] != byLanguage[languages[j]] {
+			return byLanguage[languages[i]] > byLanguage[languages[j]]
+		}
+		return languages[i] < languages[j]
+	})
+	fmt.Fprintln(writer, "\nSuggestions accepted per language:")
+	if len(languages) == 0 {
+		fmt.Fprintln(writer, "- None")
+	}
+	for _, language := range languages[:common.RankingLimit(len(languages))] {
+		fmt.Fprintf(writer, "- %s: %d\n", language, byLanguage[language])
+	}
+	common.PrintMoreEntries(writer, len(languages))
+}
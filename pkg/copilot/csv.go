@@ -0,0 +1,37 @@
+package copilot
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// dayCSVRow is one day in copilot-days.csv
+type dayCSVRow struct {
+	Date           time.Time `csv:"date"`
+	Suggestions    int       `csv:"suggestions"`
+	Acceptances    int       `csv:"acceptances"`
+	AcceptanceRate float64   `csv:"acceptance_rate"`
+	LinesSuggested int       `csv:"lines_suggested"`
+	LinesAccepted  int       `csv:"lines_accepted"`
+	Chats          int       `csv:"chats"`
+	EngagedUsers   int       `csv:"engaged_users"`
+}
+
+// csvTables lists the usage per day
+func csvTables(days []DailyUsage) []common.CSVTable {
+	var rows []dayCSVRow
+	for _, day := range days {
+		rows = append(rows, dayCSVRow{
+			Date:           day.Date,
+			Suggestions:    day.Suggestions,
+			Acceptances:    day.Acceptances,
+			AcceptanceRate: acceptanceRate(day.Suggestions, day.Acceptances),
+			LinesSuggested: day.LinesSuggested,
+			LinesAccepted:  day.LinesAccepted,
+			Chats:          day.Chats,
+			EngagedUsers:   day.EngagedUsers,
+		})
+	}
+	return []common.CSVTable{common.NewCSVTable("days", rows)}
+}
@@ -0,0 +1,136 @@
+package copilot
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// exportColumns are the recognized header names of a COPILOT_EXPORT_FILE; only date is required.
+// Several rows of one day (e.g. one per language) are summed.
+var exportColumns = map[string][]string{
+	"date":            {"date", "day"},
+	"user":            {"user", "user_login", "login"},
+	"suggestions":     {"suggestions", "total_code_suggestions"},
+	"acceptances":     {"acceptances", "total_code_acceptances"},
+	"lines_suggested": {"lines_suggested", "total_code_lines_suggested"},
+	"lines_accepted":  {"lines_accepted", "total_code_lines_accepted"},
+	"chats":           {"chats", "total_chats"},
+	"language":        {"language"},
+}
+
+// readExport reads a per-user CSV export of Copilot usage. Rows of other users are skipped when the file has a
+// user column and username is set.
+func readExport(path, username string, startDate, endDate time.Time) ([]DailyUsage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to open %s", path)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to read the header of %s", path)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for column, aliases := range exportColumns {
+			for _, alias := range aliases {
+				if name == alias {
+					columns[column] = i
+				}
+			}
+		}
+	}
+	if _, ok := columns["date"]; !ok {
+		return nil, common.NewError("%s has no date column", path)
+	}
+
+	byDate := make(map[string]*DailyUsage)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, common.WrapError(err, "failed to read %s", path)
+		}
+		field := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		number := func(column string) (int, error) {
+			value := field(column)
+			if value == "" {
+				return 0, nil
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, common.WrapError(err, "%s line %d: invalid %s", path, line, column)
+			}
+			return n, nil
+		}
+
+		if user := field("user"); user != "" && username != "" && !strings.EqualFold(user, username) {
+			continue
+		}
+		dateText := field("date")
+		if len(dateText) > len("2006-01-02") {
+			dateText = dateText[:len("2006-01-02")]
+		}
+		date, err := time.ParseInLocation("2006-01-02", dateText, time.Local)
+		if err != nil {
+			return nil, common.WrapError(err, "%s line %d: invalid date", path, line)
+		}
+		if date.Before(startDate) || date.After(endDate) {
+			continue
+		}
+
+		day, exists := byDate[dateText]
+		if !exists {
+			day = &DailyUsage{Date: date, EngagedUsers: 1, ByLanguage: make(map[string]int)}
+			byDate[dateText] = day
+		}
+		counts := []struct {
+			column string
+			target *int
+		}{
+			{"suggestions", &day.Suggestions},
+			{"acceptances", &day.Acceptances},
+			{"lines_suggested", &day.LinesSuggested},
+			{"lines_accepted", &day.LinesAccepted},
+			{"chats", &day.Chats},
+		}
+		acceptancesBefore := day.Acceptances
+		for _, count := range counts {
+			n, err := number(count.column)
+			if err != nil {
+				return nil, err
+			}
+			*count.target += n
+		}
+		if language := field("language"); language != "" && day.Acceptances > acceptancesBefore {
+			day.ByLanguage[language] += day.Acceptances - acceptancesBefore
+		}
+	}
+
+	var days []DailyUsage
+	for _, day := range byDate {
+		days = append(days, *day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+	return days, nil
+}
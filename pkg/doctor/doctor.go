@@ -0,0 +1,521 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/backlog"
+	"dev-stats/pkg/calendar"
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+	"dev-stats/pkg/copilot"
+	"dev-stats/pkg/focus"
+	"dev-stats/pkg/gitea"
+	"dev-stats/pkg/github"
+	"dev-stats/pkg/google"
+	"dev-stats/pkg/harvest"
+	"dev-stats/pkg/jira"
+	"dev-stats/pkg/notion"
+	"dev-stats/pkg/opsgenie"
+	"dev-stats/pkg/phabricator"
+	"dev-stats/pkg/slack"
+	"dev-stats/pkg/support"
+	"dev-stats/pkg/todoist"
+)
+
+// Status represents the outcome of a single check
+type Status string
+
+const (
+	StatusPass Status = "PASS"
+	StatusWarn Status = "WARN"
+	StatusFail Status = "FAIL"
+	StatusSkip Status = "SKIP"
+)
+
+// CheckResult is the outcome of a single diagnostic check
+type CheckResult struct {
+	Name   string
+	Status Status
+	Detail string
+}
+
+// Doctor runs environment diagnostics for all analyzers
+type Doctor struct {
+	results []CheckResult
+}
+
+// NewDoctor creates a new Doctor
+func NewDoctor() *Doctor {
+	return &Doctor{}
+}
+
+// Run performs all checks and prints a pass/fail table.
+// Returns false if any check failed.
+func (d *Doctor) Run(writer io.Writer) bool {
+	fmt.Fprintln(writer, "Running dev-stats environment diagnosis...")
+
+	d.checkDateRange()
+	d.checkWeekConfig()
+	d.checkOutputDirectory()
+	d.checkCacheEncryption()
+	d.checkCategorizationConfig()
+	d.checkGitHub()
+	d.checkBacklog()
+	d.checkCalendar()
+	d.checkNotion()
+	d.checkGoogle()
+	d.checkTodoist()
+	d.checkJira()
+	d.checkHarvest()
+	d.checkSupport()
+	d.checkOpsgenie()
+	d.checkCopilot()
+	d.checkGitea()
+	d.checkPhabricator()
+	d.checkFocus()
+	d.checkGitHubArchive()
+	d.checkSlack()
+
+	return d.printResults(writer)
+}
+
+func (d *Doctor) add(name string, status Status, detail string) {
+	d.results = append(d.results, CheckResult{Name: name, Status: status, Detail: detail})
+}
+
+// addValidation records the outcome of an analyzer's ValidateConfig.
+// Warnings printed by the validator are reported as WARN.
+func (d *Doctor) addValidation(name string, output *bytes.Buffer, err error) {
+	if err != nil {
+		d.add(name, StatusFail, firstLine(err.Error()))
+		return
+	}
+	for _, line := range strings.Split(output.String(), "\n") {
+		if strings.Contains(line, "⚠️") {
+			d.add(name, StatusWarn, strings.TrimSpace(strings.ReplaceAll(line, "⚠️", "")))
+			return
+		}
+	}
+	d.add(name, StatusPass, "")
+}
+
+func (d *Doctor) checkWeekConfig() {
+	weeks, err := common.WeekConfigFromEnv()
+	if err != nil {
+		d.add("Weeks (WEEK_START/WEEK_NUMBERING)", StatusWarn, err.Error()+"; using "+weeks.Describe())
+		return
+	}
+	d.add("Weeks (WEEK_START/WEEK_NUMBERING)", StatusPass, weeks.Describe())
+}
+
+func (d *Doctor) checkDateRange() {
+	cfg, err := common.LoadConfig()
+	if err != nil {
+		d.add("Date range (START_DATE/END_DATE)", StatusFail, err.Error())
+		return
+	}
+	if cfg.EndDate.Before(cfg.StartDate) {
+		d.add("Date range (START_DATE/END_DATE)", StatusFail, "END_DATE is before START_DATE")
+		return
+	}
+	detail := fmt.Sprintf("%s to %s", cfg.StartDate.Format("2006-01-02"), cfg.EndDate.Format("2006-01-02"))
+	if common.DateRangeOverridden() {
+		detail += " (from -start/-end/-period)"
+	}
+	if time.Now().After(cfg.EndDate.AddDate(0, 0, 1)) && common.DateRangeOverridden() {
+		d.add("Date range (START_DATE/END_DATE)", StatusWarn, detail+": past period, Notion/Google items edited after END_DATE are not counted")
+		return
+	}
+	if time.Now().After(cfg.EndDate.AddDate(0, 0, 1)) {
+		d.add("Date range (START_DATE/END_DATE)", StatusWarn, detail+": today is past END_DATE, run-* commands will refuse to run")
+		return
+	}
+	d.add("Date range (START_DATE/END_DATE)", StatusPass, detail)
+
+	markdownFile := filepath.Join("notion-urls", fmt.Sprintf("%s_to_%s.md",
+		cfg.StartDate.Format("2006-01-02"), cfg.EndDate.Format("2006-01-02")))
+	if _, err := os.Stat(markdownFile); err != nil {
+		d.add("Notion download list", StatusSkip, markdownFile+" not found (created by make download-notion)")
+	} else {
+		d.add("Notion download list", StatusPass, markdownFile)
+	}
+}
+
+func (d *Doctor) checkOutputDirectory() {
+	if err := os.MkdirAll("output", 0755); err != nil {
+		d.add("Output directory", StatusFail, err.Error())
+		return
+	}
+	file, err := os.CreateTemp("output", ".doctor-*")
+	if err != nil {
+		d.add("Output directory", StatusFail, fmt.Sprintf("output/ is not writable: %v", err))
+		return
+	}
+	file.Close()
+	os.Remove(file.Name())
+	d.add("Output directory", StatusPass, "output/ is writable")
+}
+
+func (d *Doctor) checkCacheEncryption() {
+	if !common.CacheEncryptionEnabled() {
+		d.add("Cache encryption", StatusSkip, "CACHE_PASSPHRASE or CACHE_KEYCHAIN_SERVICE not set (caches are stored in plain text)")
+		return
+	}
+	sealed, err := common.SealIfEnabled([]byte("dev-stats"))
+	if err == nil {
+		_, err = common.Open(sealed)
+	}
+	if err != nil {
+		d.add("Cache encryption", StatusFail, err.Error())
+		return
+	}
+	d.add("Cache encryption", StatusPass, "caches and storage/ are encrypted at rest")
+}
+
+// addConfigFile records the outcome of loading a config file, listing every validation problem
+func (d *Doctor) addConfigFile(name string, err error, detail string) {
+	if err == nil {
+		d.add(name, StatusPass, detail)
+		return
+	}
+	var validationErr *config.ValidationError
+	if errors.As(err, &validationErr) {
+		d.add(name, StatusFail, strings.Join(validationErr.Problems, "; "))
+		return
+	}
+	d.add(name, StatusFail, err.Error())
+}
+
+func (d *Doctor) checkCategorizationConfig() {
+	categoryConfig, err := config.LoadCategorizationConfig("")
+	if err != nil {
+		d.addConfigFile(config.DefaultCategorizationPath, err, "")
+		return
+	}
+	d.addConfigFile(config.DefaultCategorizationPath, nil, fmt.Sprintf("%d categories, %d event rules, %d Notion rules",
+		len(categoryConfig.Categories), len(categoryConfig.EventCategories), len(categoryConfig.NotionCategories)))
+
+	d.checkOptionalConfigFile(config.DefaultOverridesPath, func() (string, error) {
+		overrides, err := config.LoadOverrides("", categoryConfig)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d overrides", len(overrides.Items)), nil
+	})
+	d.checkOptionalConfigFile(config.DefaultIgnoreListPath, func() (string, error) {
+		ignoreList, err := config.LoadIgnoreList("")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d ignored items", ignoreList.Len()), nil
+	})
+	d.checkOptionalConfigFile(config.DefaultMonorepoConfigPath, func() (string, error) {
+		monorepos, err := config.LoadMonorepoConfig("")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d monorepos", len(monorepos.Repositories)), nil
+	})
+	d.checkOptionalConfigFile(config.DefaultEstimationPath, func() (string, error) {
+		estimation, err := config.LoadEstimationConfig("")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("coefficients for %d sources", len(estimation.Sources)), nil
+	})
+	d.checkOptionalConfigFile(config.DefaultNotionTasksPath, func() (string, error) {
+		tasks, err := config.LoadNotionTasksConfig("")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d task databases", len(tasks.Databases)), nil
+	})
+	d.checkOptionalConfigFile(config.DefaultSprintsPath, func() (string, error) {
+		sprints, err := config.LoadSprintConfig("")
+		if err != nil {
+			return "", err
+		}
+		if sprints.Cadence != nil && len(sprints.Sprints) == 0 {
+			return fmt.Sprintf("%d-day cadence", sprints.Cadence.LengthDays), nil
+		}
+		return fmt.Sprintf("%d sprints", len(sprints.Sprints)), nil
+	})
+	d.checkOptionalConfigFile(config.DefaultScopeConfigPath, func() (string, error) {
+		scopes, err := config.LoadScopeConfig("")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d sources classified", len(scopes.Sources)), nil
+	})
+}
+
+// checkOptionalConfigFile validates a user-maintained config file if it exists.
+// Each optional file has a tracked template next to it (e.g. config/ignore.sample.yaml).
+func (d *Doctor) checkOptionalConfigFile(path string, load func() (string, error)) {
+	if _, err := os.Stat(path); err != nil {
+		sample := strings.TrimSuffix(path, ".yaml") + ".sample.yaml"
+		d.add(path, StatusSkip, fmt.Sprintf("not found (optional, see %s)", sample))
+		return
+	}
+	detail, err := load()
+	d.addConfigFile(path, err, detail)
+}
+
+func (d *Doctor) checkGitHub() {
+	profiles := github.LoadGitHubProfiles()
+	if os.Getenv("GITHUB_TOKEN") == "" && os.Getenv("GITHUB_USERNAME") == "" {
+		if len(profiles) == 0 {
+			d.add("GitHub", StatusSkip, "GITHUB_TOKEN not set")
+		}
+	} else {
+		var output bytes.Buffer
+		err := github.NewGitHubAnalyzer().ValidateConfig(&output)
+		d.addValidation("GitHub", &output, err)
+	}
+	for _, profile := range profiles {
+		analyzer := github.NewGitHubAnalyzerWithProfile(&profile)
+		var output bytes.Buffer
+		err := analyzer.ValidateConfig(&output)
+		d.addValidation(analyzer.GetName(), &output, err)
+	}
+}
+
+func (d *Doctor) checkBacklog() {
+	profiles := backlog.LoadBacklogProfiles()
+	if len(profiles) == 0 {
+		d.add("Backlog", StatusSkip, "no BACKLOG_<PROFILE>_* profiles configured")
+		return
+	}
+	for _, profile := range profiles {
+		name := fmt.Sprintf("Backlog (%s)", profile.Name)
+		if !profile.IsAnalysisReady() {
+			d.add(name, StatusWarn, "PROJECT_ID is missing (run make list-backlog)")
+			continue
+		}
+		var output bytes.Buffer
+		err := backlog.NewBacklogAnalyzerWithProfile(&profile).ValidateConfig(&output)
+		d.addValidation(name, &output, err)
+	}
+}
+
+func (d *Doctor) checkCalendar() {
+	analyzer, err := calendar.NewCalendarAnalyzer()
+	if err != nil {
+		d.add("Calendar", StatusFail, firstLine(err.Error()))
+		return
+	}
+	if err := analyzer.ValidateConfig(); err != nil {
+		d.add("Calendar", StatusSkip, err.Error())
+		return
+	}
+
+	icsCount := 0
+	filepath.Walk("storage/calendar", func(path string, info os.FileInfo, err error) error {
+		if err == nil && strings.HasSuffix(strings.ToLower(info.Name()), ".ics") {
+			icsCount++
+		}
+		return nil
+	})
+	if icsCount == 0 && os.Getenv("GOOGLE_CLIENT_ID") == "" && google.TakeoutPathFromEnv() == "" {
+		d.add("Calendar", StatusWarn, "storage/calendar contains no .ics files")
+		return
+	}
+	d.add("Calendar", StatusPass, fmt.Sprintf("%d ICS files", icsCount))
+}
+
+func (d *Doctor) checkNotion() {
+	if os.Getenv("NOTION_TOKEN") == "" {
+		d.add("Notion", StatusSkip, "NOTION_TOKEN not set")
+		return
+	}
+	analyzer, err := notion.NewNotionAnalyzer()
+	if err != nil {
+		d.add("Notion", StatusFail, firstLine(err.Error()))
+		return
+	}
+	var output bytes.Buffer
+	err = analyzer.ValidateConfig(&output)
+	d.addValidation("Notion", &output, err)
+}
+
+func (d *Doctor) checkGoogle() {
+	if path := google.TakeoutPathFromEnv(); path != "" {
+		d.checkGoogleTakeout(path) // used instead of the API
+		return
+	}
+	if os.Getenv("GOOGLE_CLIENT_ID") == "" && os.Getenv("GOOGLE_CLIENT_SECRET") == "" {
+		d.add("Google Workspace", StatusSkip, "GOOGLE_CLIENT_ID not set")
+		return
+	}
+	if err := google.NewGDocsAnalyzer().ValidateConfig(); err != nil {
+		d.add("Google Workspace", StatusFail, err.Error())
+		return
+	}
+	// Avoid API calls here: without a cached token they would open a browser
+	if !google.HasCachedToken() {
+		d.add("Google Workspace", StatusWarn, fmt.Sprintf("no cached token at %s (run make run-google to authenticate)", google.TokenFilePath()))
+		return
+	}
+	d.add("Google Workspace", StatusPass, "token cached at "+google.TokenFilePath())
+}
+
+// checkGoogleTakeout reports which data the Google Takeout download holds for the calendar and google analyzers
+func (d *Doctor) checkGoogleTakeout(path string) {
+	counts, err := google.NewTakeout(path).Detect()
+	if err != nil {
+		d.add("Google Takeout", StatusFail, firstLine(err.Error()))
+		return
+	}
+	if len(counts) == 0 {
+		d.add("Google Takeout", StatusWarn, google.DescribeKinds(counts)+" in "+path)
+		return
+	}
+	if counts[google.TakeoutDriveActivity] == 0 && counts[google.TakeoutDriveActivityHTML] > 0 {
+		d.add("Google Takeout", StatusWarn, "My Activity is HTML; export it as JSON to analyze Drive activity")
+		return
+	}
+	d.add("Google Takeout", StatusPass, google.DescribeKinds(counts))
+}
+
+func (d *Doctor) checkTodoist() {
+	if os.Getenv("TODOIST_API_TOKEN") == "" {
+		d.add("Todoist", StatusSkip, "TODOIST_API_TOKEN not set")
+		return
+	}
+	var output bytes.Buffer
+	err := todoist.NewTodoistAnalyzer().ValidateConfig(&output)
+	d.addValidation("Todoist", &output, err)
+}
+
+func (d *Doctor) checkJira() {
+	if os.Getenv("JIRA_API_TOKEN") == "" {
+		d.add("Jira", StatusSkip, "JIRA_API_TOKEN not set")
+		return
+	}
+	var output bytes.Buffer
+	err := jira.NewJiraAnalyzer().ValidateConfig(&output)
+	d.addValidation("Jira", &output, err)
+}
+
+func (d *Doctor) checkHarvest() {
+	if os.Getenv("HARVEST_ACCESS_TOKEN") == "" {
+		d.add("Harvest", StatusSkip, "HARVEST_ACCESS_TOKEN not set")
+		return
+	}
+	var output bytes.Buffer
+	err := harvest.NewHarvestAnalyzer().ValidateConfig(&output)
+	d.addValidation("Harvest", &output, err)
+}
+
+func (d *Doctor) checkSupport() {
+	if os.Getenv("ZENDESK_API_TOKEN") == "" && os.Getenv("FRESHDESK_API_KEY") == "" {
+		d.add("Support desk", StatusSkip, "ZENDESK_API_TOKEN / FRESHDESK_API_KEY not set")
+		return
+	}
+	analyzer := support.NewSupportAnalyzer()
+	var output bytes.Buffer
+	err := analyzer.ValidateConfig(&output)
+	d.addValidation(analyzer.GetName(), &output, err)
+}
+
+func (d *Doctor) checkOpsgenie() {
+	if os.Getenv("OPSGENIE_API_KEY") == "" {
+		d.add("Opsgenie", StatusSkip, "OPSGENIE_API_KEY not set")
+		return
+	}
+	var output bytes.Buffer
+	err := opsgenie.NewOpsgenieAnalyzer().ValidateConfig(&output)
+	d.addValidation("Opsgenie", &output, err)
+}
+
+func (d *Doctor) checkCopilot() {
+	if os.Getenv("COPILOT_EXPORT_FILE") == "" && os.Getenv("COPILOT_ORG") == "" {
+		d.add("Copilot", StatusSkip, "COPILOT_EXPORT_FILE / COPILOT_ORG not set")
+		return
+	}
+	var output bytes.Buffer
+	err := copilot.NewCopilotAnalyzer().ValidateConfig(&output)
+	d.addValidation("Copilot", &output, err)
+}
+
+func (d *Doctor) checkGitea() {
+	if os.Getenv("GITEA_TOKEN") == "" {
+		d.add("Gitea", StatusSkip, "GITEA_TOKEN not set")
+		return
+	}
+	var output bytes.Buffer
+	err := gitea.NewGiteaAnalyzer().ValidateConfig(&output)
+	d.addValidation("Gitea", &output, err)
+}
+
+func (d *Doctor) checkPhabricator() {
+	if os.Getenv("PHABRICATOR_EXPORT_FILE") == "" && os.Getenv("PHABRICATOR_API_TOKEN") == "" {
+		d.add("Phabricator", StatusSkip, "PHABRICATOR_EXPORT_FILE / PHABRICATOR_API_TOKEN not set")
+		return
+	}
+	var output bytes.Buffer
+	err := phabricator.NewPhabricatorAnalyzer().ValidateConfig(&output)
+	d.addValidation("Phabricator", &output, err)
+}
+
+func (d *Doctor) checkFocus() {
+	if os.Getenv("FOCUS_EXPORT_FILE") == "" {
+		d.add("Focus", StatusSkip, "FOCUS_EXPORT_FILE not set")
+		return
+	}
+	var output bytes.Buffer
+	err := focus.NewFocusAnalyzer().ValidateConfig(&output)
+	d.addValidation("Focus", &output, err)
+}
+
+func (d *Doctor) checkGitHubArchive() {
+	if os.Getenv("GITHUB_ARCHIVE_PATH") == "" {
+		d.add("GitHub archive", StatusSkip, "GITHUB_ARCHIVE_PATH not set")
+		return
+	}
+	var output bytes.Buffer
+	err := github.NewArchiveAnalyzer().ValidateConfig(&output)
+	d.addValidation("GitHub archive", &output, err)
+}
+
+func (d *Doctor) checkSlack() {
+	collector := slack.NewKudosCollector()
+	if collector == nil {
+		d.add("Slack", StatusSkip, "SLACK_USER_TOKEN or SLACK_USER_ID not set")
+		return
+	}
+	var output bytes.Buffer
+	err := collector.ValidateConfig(&output)
+	d.addValidation("Slack", &output, err)
+}
+
+func (d *Doctor) printResults(writer io.Writer) bool {
+	fmt.Fprintf(writer, "\n%-35s %-6s %s\n", "Check", "Status", "Detail")
+	fmt.Fprintln(writer, strings.Repeat("-", 90))
+
+	counts := make(map[Status]int)
+	for _, result := range d.results {
+		counts[result.Status]++
+		fmt.Fprintf(writer, "%-35s %-6s %s\n", result.Name, result.Status, result.Detail)
+	}
+
+	fmt.Fprintf(writer, "\n%d passed, %d warnings, %d failed, %d skipped\n",
+		counts[StatusPass], counts[StatusWarn], counts[StatusFail], counts[StatusSkip])
+
+	return counts[StatusFail] == 0
+}
+
+// firstLine returns the first line of a multi-line message
+func firstLine(message string) string {
+	if idx := strings.Index(message, "\n"); idx != -1 {
+		return message[:idx]
+	}
+	return message
+}
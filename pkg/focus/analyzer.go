@@ -0,0 +1,239 @@
+package focus
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// ActivityKindFocus is the kind of focus session activities; their duration is measured time, not scheduled
+const ActivityKindFocus = "focus_session"
+
+// FocusAnalyzer implements the Analyzer interface for focus sessions exported from Pomodoro/focus apps
+// (Forest, Session, or any CSV with start and end or duration columns)
+type FocusAnalyzer struct {
+	exportFiles []string
+}
+
+// Session is one focus session. Sessions that were given up (a withered tree in Forest) are not completed.
+type Session struct {
+	Start     time.Time     `json:"start"`
+	Duration  time.Duration `json:"duration"`
+	Title     string        `json:"title,omitempty"`
+	Tag       string        `json:"tag,omitempty"`
+	Completed bool          `json:"completed"`
+}
+
+// DayFocus totals the completed sessions of one day
+type DayFocus struct {
+	Date      string        `json:"date"`
+	Sessions  int           `json:"sessions"`
+	Abandoned int           `json:"abandoned"`
+	DeepWork  time.Duration `json:"deep_work"`
+	Longest   time.Duration `json:"longest"`
+}
+
+// TagFocus totals the completed sessions of one tag
+type TagFocus struct {
+	Tag      string        `json:"tag"`
+	Sessions int           `json:"sessions"`
+	DeepWork time.Duration `json:"deep_work"`
+}
+
+// NewFocusAnalyzer creates a new focus session analyzer from FOCUS_EXPORT_FILE (comma-separated paths)
+func NewFocusAnalyzer() *FocusAnalyzer {
+	var files []string
+	for _, path := range strings.Split(os.Getenv("FOCUS_EXPORT_FILE"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			files = append(files, path)
+		}
+	}
+	return &FocusAnalyzer{exportFiles: files}
+}
+
+// GetName returns the analyzer name
+func (f *FocusAnalyzer) GetName() string {
+	return "Focus"
+}
+
+// ValidateConfig validates the required configuration
+func (f *FocusAnalyzer) ValidateConfig(writer io.Writer) error {
+	if len(f.exportFiles) == 0 {
+		return common.NewError("FOCUS_EXPORT_FILE environment variable is required (CSV export of Forest, Session, or another focus app)")
+	}
+	for _, path := range f.exportFiles {
+		if _, err := os.Stat(path); err != nil {
+			return common.WrapError(err, "FOCUS_EXPORT_FILE is not readable")
+		}
+		fmt.Fprintf(writer, "✓ Focus export file: %s\n", path)
+	}
+	return nil
+}
+
+// Analyze counts completed focus sessions and deep-work hours per day and per tag
+func (f *FocusAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := f.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, path := range f.exportFiles {
+		exported, skipped, err := readExport(path)
+		if err != nil {
+			return nil, err
+		}
+		if skipped > 0 {
+			fmt.Fprintf(writer, "Skipped %d rows without a readable start time in %s\n", skipped, path)
+		}
+		first := config.StartDate.Format("2006-01-02")
+		last := config.EndDate.Format("2006-01-02")
+		for _, session := range exported {
+			if day := session.Start.Local().Format("2006-01-02"); day >= first && day <= last {
+				sessions = append(sessions, session)
+			}
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
+	})
+
+	var completed []Session
+	var deepWork time.Duration
+	abandoned := 0
+	byDay := make(map[string]*DayFocus)
+	byTag := make(map[string]*TagFocus)
+	for _, session := range sessions {
+		date := session.Start.Local().Format("2006-01-02")
+		day, exists := byDay[date]
+		if !exists {
+			day = &DayFocus{Date: date}
+			byDay[date] = day
+		}
+		if !session.Completed {
+			day.Abandoned++
+			abandoned++
+			continue
+		}
+		completed = append(completed, session)
+		deepWork += session.Duration
+		day.Sessions++
+		day.DeepWork += session.Duration
+		if session.Duration > day.Longest {
+			day.Longest = session.Duration
+		}
+		tagName := session.Tag
+		if tagName == "" {
+			tagName = "(untagged)"
+		}
+		tag, exists := byTag[tagName]
+		if !exists {
+			tag = &TagFocus{Tag: tagName}
+			byTag[tagName] = tag
+		}
+		tag.Sessions++
+		tag.DeepWork += session.Duration
+	}
+
+	var days []DayFocus
+	for _, day := range byDay {
+		days = append(days, *day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	var tags []TagFocus
+	for _, tag := range byTag {
+		tags = append(tags, *tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].DeepWork != tags[j].DeepWork {
+			return tags[i].DeepWork > tags[j].DeepWork
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	activeDays := 0
+	for _, day := range days {
+		if day.Sessions > 0 {
+			activeDays++
+		}
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: f.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "focus.sessions", Label: "Focus sessions", Value: len(completed)},
+			{ID: "focus.deep_work_hours", Label: "Deep work", Value: deepWork},
+			{ID: "focus.abandoned_sessions", Label: "Abandoned sessions", Value: abandoned},
+			{ID: "focus.active_days", Label: "Days with focus sessions", Value: activeDays},
+		},
+		Details: map[string]interface{}{
+			"sessions": sessions,
+			"by_day":   days,
+			"by_tag":   tags,
+		},
+		Activities: f.buildActivities(completed),
+		CSVTables:  f.csvTables(sessions, days),
+	}
+	result.Explain("focus.sessions", result.Activities)
+	result.Explain("focus.deep_work_hours", result.Activities)
+
+	f.printResults(writer, result, days, tags)
+	return result, nil
+}
+
+// buildActivities converts completed sessions into activities with the focused duration, categorized by tag
+func (f *FocusAnalyzer) buildActivities(sessions []Session) []common.Activity {
+	var activities []common.Activity
+	for _, session := range sessions {
+		title := session.Title
+		if title == "" {
+			title = "Focus session"
+		}
+		if session.Tag != "" {
+			title = session.Tag + ": " + title
+		}
+		activities = append(activities, common.Activity{
+			Source:   f.GetName(),
+			Kind:     ActivityKindFocus,
+			ID:       session.Start.Format(time.RFC3339),
+			Title:    title,
+			Time:     session.Start,
+			Duration: session.Duration,
+			Category: session.Tag,
+		})
+	}
+	return activities
+}
+
+func (f *FocusAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, days []DayFocus, tags []TagFocus) {
+	fmt.Fprintf(writer, "\nFocus sessions per day from %s to %s:\n",
+		result.StartDate.Format("2006-01-02"), result.EndDate.Format("2006-01-02"))
+	if len(days) == 0 {
+		fmt.Fprintln(writer, "- No focus sessions found")
+	}
+	for _, day := range days {
+		line := fmt.Sprintf("- %s: %d sessions, %s deep work", day.Date, day.Sessions, common.FormatDuration(day.DeepWork))
+		if day.Sessions > 0 {
+			line += fmt.Sprintf(" (longest %s)", common.FormatDuration(day.Longest))
+		}
+		if day.Abandoned > 0 {
+			line += fmt.Sprintf(", %d abandoned", day.Abandoned)
+		}
+		fmt.Fprintln(writer, line)
+	}
+
+	result.PrintSummary(writer)
+
+	fmt.Fprintln(writer, "\nDeep work per tag:")
+	for _, tag := range tags[:common.RankingLimit(len(tags))] {
+		fmt.Fprintf(writer, "- %s: %s (%d sessions)\n", tag.Tag, common.FormatDuration(tag.DeepWork), tag.Sessions)
+	}
+	common.PrintMoreEntries(writer, len(tags))
+}
@@ -0,0 +1,44 @@
+package focus
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// sessionCSVRow is one session in focus-sessions.csv
+type sessionCSVRow struct {
+	Start     time.Time     `csv:"start"`
+	Duration  time.Duration `csv:"hours"`
+	Tag       string        `csv:"tag"`
+	Title     string        `csv:"title"`
+	Completed bool          `csv:"completed"`
+}
+
+// dayCSVRow is one day in focus-days.csv
+type dayCSVRow struct {
+	Date      string        `csv:"date"`
+	Sessions  int           `csv:"sessions"`
+	Abandoned int           `csv:"abandoned"`
+	DeepWork  time.Duration `csv:"deep_work_hours"`
+	Longest   time.Duration `csv:"longest_hours"`
+}
+
+// csvTables lists every session and the totals per day
+func (f *FocusAnalyzer) csvTables(sessions []Session, days []DayFocus) []common.CSVTable {
+	var sessionRows []sessionCSVRow
+	for _, session := range sessions {
+		sessionRows = append(sessionRows, sessionCSVRow{
+			Start:     session.Start,
+			Duration:  session.Duration,
+			Tag:       session.Tag,
+			Title:     session.Title,
+			Completed: session.Completed,
+		})
+	}
+	var dayRows []dayCSVRow
+	for _, day := range days {
+		dayRows = append(dayRows, dayCSVRow(day))
+	}
+	return []common.CSVTable{common.NewCSVTable("sessions", sessionRows), common.NewCSVTable("days", dayRows)}
+}
@@ -0,0 +1,153 @@
+package focus
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// Column names recognized in export headers (lowercased). Forest exports "Start Time, End Time, Tag, Note,
+// Tree Type, Is Success"; Session exports "Title, Start Date, End Date, Duration, Category"; a generic CSV
+// needs a start column and an end or duration column.
+var (
+	startColumns    = []string{"start", "start time", "start date", "started", "started at", "begin"}
+	endColumns      = []string{"end", "end time", "end date", "ended", "ended at", "finish"}
+	durationColumns = []string{"duration", "duration (minutes)", "minutes"}
+	secondsColumns  = []string{"duration (seconds)", "seconds"}
+	titleColumns    = []string{"title", "note", "notes", "intention", "task"}
+	tagColumns      = []string{"tag", "category", "label", "project"}
+	successColumns  = []string{"is success", "success", "completed", "status"}
+)
+
+// timeLayouts are the date formats tried for start and end columns; times without a zone are local
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"Mon Jan 02 15:04:05 MST 2006",
+	"Mon Jan 2 15:04:05 MST 2006",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+}
+
+// readExport reads the focus sessions of one CSV export. Rows whose start time can't be parsed are skipped and
+// counted in the second return value.
+func readExport(path string) ([]Session, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, common.WrapError(err, "failed to open %s", path)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, common.WrapError(err, "failed to read the header of %s", path)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	column := func(names []string) int {
+		for _, name := range names {
+			if i, exists := columns[name]; exists {
+				return i
+			}
+		}
+		return -1
+	}
+	start, end := column(startColumns), column(endColumns)
+	minutes, seconds := column(durationColumns), column(secondsColumns)
+	title, tag, success := column(titleColumns), column(tagColumns), column(successColumns)
+	if start == -1 || (end == -1 && minutes == -1 && seconds == -1) {
+		return nil, 0, common.NewError("%s needs a start column and an end or duration column, got: %s", path, strings.Join(header, ", "))
+	}
+
+	var sessions []Session
+	skipped := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, common.WrapError(err, "failed to parse %s", path)
+		}
+		field := func(i int) string {
+			if i == -1 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		started, ok := parseTime(field(start))
+		if !ok {
+			skipped++
+			continue
+		}
+		session := Session{Start: started, Title: field(title), Tag: field(tag), Completed: parseSuccess(field(success))}
+		if ended, ok := parseTime(field(end)); ok && ended.After(started) {
+			session.Duration = ended.Sub(started)
+		} else if value, err := strconv.ParseFloat(field(seconds), 64); err == nil {
+			session.Duration = time.Duration(value * float64(time.Second))
+		} else if value, err := strconv.ParseFloat(field(minutes), 64); err == nil {
+			session.Duration = time.Duration(value * float64(time.Minute))
+		} else if value, ok := parseClock(field(minutes)); ok {
+			session.Duration = value
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, skipped, nil
+}
+
+// parseTime parses a start or end value in one of timeLayouts
+func parseTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range timeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseClock parses a duration written as H:MM:SS or MM:SS (e.g. 0:25:00)
+func parseClock(value string) (time.Duration, bool) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	var duration time.Duration
+	for _, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, false
+		}
+		duration = duration*60 + time.Duration(number)
+	}
+	return duration * time.Second, true
+}
+
+// parseSuccess reads a success column: Forest writes False for withered trees, other apps abandoned/failed/no.
+// Sessions without the column are completed.
+func parseSuccess(value string) bool {
+	switch strings.ToLower(value) {
+	case "false", "no", "0", "failed", "abandoned", "cancelled", "canceled", "interrupted":
+		return false
+	}
+	return true
+}
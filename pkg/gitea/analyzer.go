@@ -0,0 +1,467 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+// pageSize is the page size of Gitea lists (the server caps it at its MAX_RESPONSE_ITEMS, 50 by default)
+const pageSize = 50
+
+// GiteaAnalyzer implements the Analyzer interface for Gitea and its forks (Forgejo, Codeberg)
+type GiteaAnalyzer struct {
+	baseURL    string // e.g. https://codeberg.org or https://gitea.example.com
+	token      string
+	username   string // GITEA_USERNAME; the token owner when empty
+	client     *common.HTTPClient
+	ignoreList config.IgnoreList
+}
+
+// PullRequestState is the merge state of a pull request
+type PullRequestState struct {
+	Merged   bool       `json:"merged"`
+	MergedAt *time.Time `json:"merged_at"`
+}
+
+// Issue is an issue or pull request from the issue search
+type Issue struct {
+	Number      int               `json:"number"`
+	Title       string            `json:"title"`
+	URL         string            `json:"url"`
+	State       string            `json:"state"`
+	CreatedAt   time.Time         `json:"created_at"`
+	ClosedAt    *time.Time        `json:"closed_at"`
+	Repository  string            `json:"repository"`
+	PullRequest *PullRequestState `json:"pull_request,omitempty"` // nil for issues
+}
+
+// issueResponse is an item of /repos/issues/search
+type issueResponse struct {
+	Number     int        `json:"number"`
+	Title      string     `json:"title"`
+	HTMLURL    string     `json:"html_url"`
+	State      string     `json:"state"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ClosedAt   *time.Time `json:"closed_at"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	PullRequest *PullRequestState `json:"pull_request"`
+}
+
+// Commit is a commit pushed by the user, from the activity feed
+type Commit struct {
+	SHA        string    `json:"sha"`
+	Message    string    `json:"message"`
+	Repository string    `json:"repository"`
+	URL        string    `json:"url"`
+	Time       time.Time `json:"time"`
+}
+
+// Title returns the first line of the commit message
+func (c Commit) Title() string {
+	title, _, _ := strings.Cut(c.Message, "\n")
+	return title
+}
+
+// feedActivity is an item of /users/{username}/activities/feeds
+type feedActivity struct {
+	OpType string `json:"op_type"`
+	Repo   struct {
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repo"`
+	Content string    `json:"content"`
+	Created time.Time `json:"created"`
+}
+
+// pushContent is the JSON content of a commit_repo activity. The feed keeps only the latest few commits of
+// a push (FEED_MAX_COMMIT_NUM, 5 by default), so commits of larger pushes are undercounted.
+type pushContent struct {
+	Commits []struct {
+		Sha1      string    `json:"Sha1"`
+		Message   string    `json:"Message"`
+		Timestamp time.Time `json:"Timestamp"`
+	} `json:"Commits"`
+}
+
+// NewGiteaAnalyzer creates a new Gitea analyzer
+func NewGiteaAnalyzer() *GiteaAnalyzer {
+	token := os.Getenv("GITEA_TOKEN")
+	client := common.NewHTTPClient()
+	client.SetHeader("Authorization", "token "+token)
+	return &GiteaAnalyzer{
+		baseURL:  strings.TrimRight(os.Getenv("GITEA_URL"), "/"),
+		token:    token,
+		username: os.Getenv("GITEA_USERNAME"),
+		client:   client,
+	}
+}
+
+// GetName returns the analyzer name
+func (g *GiteaAnalyzer) GetName() string {
+	return "Gitea"
+}
+
+// ValidateConfig validates the required configuration
+func (g *GiteaAnalyzer) ValidateConfig(writer io.Writer) error {
+	if g.baseURL == "" || g.token == "" {
+		return common.NewError("GITEA_URL and GITEA_TOKEN environment variables are required")
+	}
+	if _, err := g.client.Get(g.apiURL("/user"), nil); err != nil {
+		return common.WrapError(err, "failed to access the Gitea API (check GITEA_URL and GITEA_TOKEN)")
+	}
+	fmt.Fprintln(writer, "✓ Gitea token is valid")
+	return nil
+}
+
+// WhoAmI reports the owner of GITEA_TOKEN
+func (g *GiteaAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if g.baseURL == "" || g.token == "" {
+		return nil, common.NewError("GITEA_URL and GITEA_TOKEN environment variables are required")
+	}
+	user, err := g.getUser()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to access the Gitea API (check GITEA_URL and GITEA_TOKEN)")
+	}
+	identity := &common.Identity{Source: g.GetName(), ID: strconv.FormatInt(user.ID, 10), Login: user.Login, Name: user.FullName}
+	if g.username != "" && !strings.EqualFold(g.username, user.Login) {
+		identity.Note = fmt.Sprintf("GITEA_USERNAME is '%s'", g.username)
+	}
+	return identity, nil
+}
+
+// giteaUser is the /user response
+type giteaUser struct {
+	ID       int64  `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+}
+
+func (g *GiteaAnalyzer) getUser() (*giteaUser, error) {
+	body, err := g.client.Get(g.apiURL("/user"), nil)
+	if err != nil {
+		return nil, err
+	}
+	var user giteaUser
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, common.WrapError(err, "failed to parse Gitea user")
+	}
+	return &user, nil
+}
+
+func (g *GiteaAnalyzer) apiURL(path string) string {
+	return g.baseURL + "/api/v1" + path
+}
+
+// Analyze reports PRs and issues the user opened, PRs merged, issues closed, and commits pushed
+func (g *GiteaAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := g.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := g.ignoreList.Reload(); err != nil {
+		return nil, err
+	}
+	username := g.username
+	if username == "" {
+		user, err := g.getUser()
+		if err != nil {
+			return nil, common.WrapError(err, "failed to get Gitea user")
+		}
+		username = user.Login
+	}
+
+	fmt.Fprintf(writer, "Fetching Gitea PRs and issues of %s from %s to %s...\n", username,
+		config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+	createdPRs, err := g.searchIssues("pulls", url.Values{"created": {"true"}}, config.StartDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to search PRs")
+	}
+	createdIssues, err := g.searchIssues("issues", url.Values{"created": {"true"}}, config.StartDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to search issues")
+	}
+	assignedIssues, err := g.searchIssues("issues", url.Values{"assigned": {"true"}, "state": {"closed"}}, config.StartDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to search assigned issues")
+	}
+
+	var authoredPRs, mergedPRs, openedIssues, closedIssues []Issue
+	for _, pr := range g.filterIgnored(writer, createdPRs) {
+		if inPeriod(pr.CreatedAt, config) {
+			authoredPRs = append(authoredPRs, pr)
+		}
+		if pr.PullRequest != nil && pr.PullRequest.MergedAt != nil && inPeriod(*pr.PullRequest.MergedAt, config) {
+			mergedPRs = append(mergedPRs, pr)
+		}
+	}
+	for _, issue := range g.filterIgnored(writer, createdIssues) {
+		if inPeriod(issue.CreatedAt, config) {
+			openedIssues = append(openedIssues, issue)
+		}
+	}
+	for _, issue := range g.filterIgnored(writer, assignedIssues) {
+		if issue.ClosedAt != nil && inPeriod(*issue.ClosedAt, config) {
+			closedIssues = append(closedIssues, issue)
+		}
+	}
+
+	fmt.Fprintln(writer, "Fetching pushed commits from the activity feed...")
+	commits, err := g.getCommits(username, config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get commits")
+	}
+
+	repos := make(map[string]bool)
+	for _, items := range [][]Issue{authoredPRs, mergedPRs, openedIssues, closedIssues} {
+		for _, item := range items {
+			repos[item.Repository] = true
+		}
+	}
+	for _, commit := range commits {
+		repos[commit.Repository] = true
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: g.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "gitea.prs_authored", Label: "PRs opened", Value: len(authoredPRs)},
+			{ID: "gitea.prs_merged", Label: "PRs merged", Value: len(mergedPRs)},
+			{ID: "gitea.issues_opened", Label: "Issues opened", Value: len(openedIssues)},
+			{ID: "gitea.issues_closed", Label: "Assigned issues closed", Value: len(closedIssues)},
+			{ID: "gitea.commits", Label: "Commits pushed", Value: len(commits)},
+			{ID: "gitea.repositories", Label: "Active repositories", Value: len(repos), Snapshot: true},
+		},
+		Details: map[string]interface{}{
+			"authored_prs":  authoredPRs,
+			"merged_prs":    mergedPRs,
+			"opened_issues": openedIssues,
+			"closed_issues": closedIssues,
+			"commits":       commits,
+		},
+		Activities: g.buildActivities(authoredPRs, openedIssues, closedIssues, commits),
+		CSVTables:  g.csvTables(authoredPRs, openedIssues, closedIssues, commits),
+	}
+	result.Explain("gitea.prs_authored", g.issueActivities(authoredPRs, "pr_authored"))
+	result.Explain("gitea.prs_merged", g.issueActivities(mergedPRs, "pr_merged"))
+	result.Explain("gitea.issues_opened", g.issueActivities(openedIssues, "issue_created"))
+	result.Explain("gitea.issues_closed", g.issueActivities(closedIssues, "issue_closed"))
+	result.Explain("gitea.commits", g.commitActivities(commits))
+
+	g.printResults(writer, result, authoredPRs, mergedPRs, openedIssues, closedIssues, commits)
+	return result, nil
+}
+
+// searchIssues lists issues or pulls matching filter that were updated since the start of the period.
+// The search has no creation-date filter, so callers filter by creation, merge, or close time.
+func (g *GiteaAnalyzer) searchIssues(kind string, filter url.Values, startDate time.Time) ([]Issue, error) {
+	var issues []Issue
+	for page := 1; ; page++ {
+		params := url.Values{}
+		for key, values := range filter {
+			params[key] = values
+		}
+		if params.Get("state") == "" {
+			params.Set("state", "all")
+		}
+		params.Set("type", kind)
+		params.Set("since", startDate.Format(time.RFC3339))
+		params.Set("limit", strconv.Itoa(pageSize))
+		params.Set("page", strconv.Itoa(page))
+
+		body, err := g.client.Get(g.apiURL("/repos/issues/search?"+params.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		var response []issueResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Gitea issue search")
+		}
+		for _, item := range response {
+			issues = append(issues, Issue{
+				Number:      item.Number,
+				Title:       item.Title,
+				URL:         item.HTMLURL,
+				State:       item.State,
+				CreatedAt:   item.CreatedAt,
+				ClosedAt:    item.ClosedAt,
+				Repository:  item.Repository.FullName,
+				PullRequest: item.PullRequest,
+			})
+		}
+		if len(response) < pageSize {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// getCommits reads the user's activity feed day by day and collects the commits of their pushes
+func (g *GiteaAnalyzer) getCommits(username string, startDate, endDate time.Time) ([]Commit, error) {
+	var commits []Commit
+	seen := make(map[string]bool)
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		for page := 1; ; page++ {
+			params := url.Values{}
+			params.Set("only-performed-by", "true")
+			params.Set("date", day.Format("2006-01-02"))
+			params.Set("limit", strconv.Itoa(pageSize))
+			params.Set("page", strconv.Itoa(page))
+
+			body, err := g.client.Get(g.apiURL(fmt.Sprintf("/users/%s/activities/feeds?%s", url.PathEscape(username), params.Encode())), nil)
+			if err != nil {
+				return nil, err
+			}
+			var activities []feedActivity
+			if err := json.Unmarshal(body, &activities); err != nil {
+				return nil, common.WrapError(err, "failed to parse Gitea activity feed")
+			}
+
+			for _, activity := range activities {
+				if activity.OpType != "commit_repo" || activity.Content == "" {
+					continue
+				}
+				var push pushContent
+				if err := json.Unmarshal([]byte(activity.Content), &push); err != nil {
+					continue
+				}
+				for _, c := range push.Commits {
+					key := activity.Repo.FullName + "@" + c.Sha1
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					commits = append(commits, Commit{
+						SHA:        c.Sha1,
+						Message:    c.Message,
+						Repository: activity.Repo.FullName,
+						URL:        activity.Repo.HTMLURL + "/commit/" + c.Sha1,
+						Time:       c.Timestamp,
+					})
+				}
+			}
+			if len(activities) < pageSize {
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Time.Before(commits[j].Time)
+	})
+	return commits, nil
+}
+
+// inPeriod reports whether t falls within the analysis period (end date inclusive)
+func inPeriod(t time.Time, config *common.Config) bool {
+	return !t.Before(config.StartDate) && t.Before(config.EndDate.AddDate(0, 0, 1))
+}
+
+// filterIgnored drops PRs and issues whose URL is listed in the ignore file
+func (g *GiteaAnalyzer) filterIgnored(writer io.Writer, issues []Issue) []Issue {
+	return config.FilterIgnored(writer, &g.ignoreList, issues, "items", func(issue Issue) []string {
+		return []string{issue.URL}
+	})
+}
+
+// buildActivities converts PRs, issues, and commits into dated activities
+func (g *GiteaAnalyzer) buildActivities(authoredPRs, openedIssues, closedIssues []Issue, commits []Commit) []common.Activity {
+	var activities []common.Activity
+	activities = append(activities, g.issueActivities(authoredPRs, "pr_authored")...)
+	activities = append(activities, g.issueActivities(openedIssues, "issue_created")...)
+	activities = append(activities, g.issueActivities(closedIssues, "issue_closed")...)
+	activities = append(activities, g.commitActivities(commits)...)
+	return activities
+}
+
+// issueActivities converts PRs or issues into activities dated by creation, or by closing for closed issues
+func (g *GiteaAnalyzer) issueActivities(issues []Issue, kind string) []common.Activity {
+	var activities []common.Activity
+	for _, issue := range issues {
+		at := issue.CreatedAt
+		if kind == "issue_closed" && issue.ClosedAt != nil {
+			at = *issue.ClosedAt
+		}
+		if kind == "pr_merged" && issue.PullRequest != nil && issue.PullRequest.MergedAt != nil {
+			at = *issue.PullRequest.MergedAt
+		}
+		activities = append(activities, common.Activity{
+			Source: g.GetName(),
+			Kind:   kind,
+			ID:     issue.URL,
+			Title:  fmt.Sprintf("%s#%d %s", issue.Repository, issue.Number, issue.Title),
+			URL:    issue.URL,
+			Time:   at,
+		})
+	}
+	return activities
+}
+
+// commitActivities converts commits into dated activities
+func (g *GiteaAnalyzer) commitActivities(commits []Commit) []common.Activity {
+	var activities []common.Activity
+	for _, commit := range commits {
+		activities = append(activities, common.Activity{
+			Source: g.GetName(),
+			Kind:   "commit",
+			ID:     commit.URL,
+			Title:  fmt.Sprintf("%s@%.7s %s", commit.Repository, commit.SHA, commit.Title()),
+			URL:    commit.URL,
+			Time:   commit.Time,
+		})
+	}
+	return activities
+}
+
+func (g *GiteaAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, authoredPRs, mergedPRs, openedIssues, closedIssues []Issue, commits []Commit) {
+	printIssues := func(title string, issues []Issue, date func(Issue) time.Time) {
+		fmt.Fprintf(writer, "\n%s (%d):\n", title, len(issues))
+		for _, issue := range issues {
+			state := issue.State
+			if issue.PullRequest != nil && issue.PullRequest.Merged {
+				state = "merged"
+			}
+			fmt.Fprintf(writer, "- %s: %s#%d %s [%s]\n", date(issue).Local().Format("2006-01-02"), issue.Repository, issue.Number, issue.Title, state)
+			fmt.Fprintf(writer, "  URL: %s\n", issue.URL)
+		}
+	}
+	created := func(issue Issue) time.Time { return issue.CreatedAt }
+	printIssues("PRs opened", authoredPRs, created)
+	printIssues("PRs merged", mergedPRs, func(issue Issue) time.Time { return *issue.PullRequest.MergedAt })
+	printIssues("Issues opened", openedIssues, created)
+	printIssues("Assigned issues closed", closedIssues, func(issue Issue) time.Time { return *issue.ClosedAt })
+
+	byRepo := make(map[string]int)
+	for _, commit := range commits {
+		byRepo[commit.Repository]++
+	}
+	var repos []string
+	for repo := range byRepo {
+		repos = append(repos, repo)
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		if byRepo[repos[i]] != byRepo[repos[j]] {
+			return byRepo[repos[i]] > byRepo[repos[j]]
+		}
+		return repos[i] < repos[j]
+	})
+	fmt.Fprintf(writer, "\nCommits pushed per repository (%d):\n", len(commits))
+	for _, repo := range repos[:common.RankingLimit(len(repos))] {
+		fmt.Fprintf(writer, "- %s: %d\n", repo, byRepo[repo])
+	}
+	common.PrintMoreEntries(writer, len(repos))
+
+	result.PrintSummary(writer)
+}
@@ -0,0 +1,73 @@
+package gitea
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// issueCSVRow is one PR or issue in gitea-items.csv
+type issueCSVRow struct {
+	Relation   string    `csv:"relation"` // pr_authored, issue_created, or issue_closed
+	Repository string    `csv:"repository"`
+	Number     int       `csv:"number"`
+	Title      string    `csv:"title"`
+	State      string    `csv:"state"`
+	CreatedAt  time.Time `csv:"created_at"`
+	ClosedAt   time.Time `csv:"closed_at"`
+	MergedAt   time.Time `csv:"merged_at"`
+	URL        string    `csv:"url"`
+}
+
+// commitCSVRow is one commit in gitea-commits.csv
+type commitCSVRow struct {
+	Repository string    `csv:"repository"`
+	SHA        string    `csv:"sha"`
+	Title      string    `csv:"title"`
+	Time       time.Time `csv:"time"`
+	URL        string    `csv:"url"`
+}
+
+// csvTables lists the PRs and issues, then the commits pushed
+func (g *GiteaAnalyzer) csvTables(authoredPRs, openedIssues, closedIssues []Issue, commits []Commit) []common.CSVTable {
+	var rows []issueCSVRow
+	add := func(issues []Issue, relation string) {
+		for _, issue := range issues {
+			row := issueCSVRow{
+				Relation:   relation,
+				Repository: issue.Repository,
+				Number:     issue.Number,
+				Title:      issue.Title,
+				State:      issue.State,
+				CreatedAt:  issue.CreatedAt,
+				URL:        issue.URL,
+			}
+			if issue.ClosedAt != nil {
+				row.ClosedAt = *issue.ClosedAt
+			}
+			if issue.PullRequest != nil && issue.PullRequest.MergedAt != nil {
+				row.State = "merged"
+				row.MergedAt = *issue.PullRequest.MergedAt
+			}
+			rows = append(rows, row)
+		}
+	}
+	add(authoredPRs, "pr_authored")
+	add(openedIssues, "issue_created")
+	add(closedIssues, "issue_closed")
+
+	var commitRows []commitCSVRow
+	for _, commit := range commits {
+		commitRows = append(commitRows, commitCSVRow{
+			Repository: commit.Repository,
+			SHA:        commit.SHA,
+			Title:      commit.Title(),
+			Time:       commit.Time,
+			URL:        commit.URL,
+		})
+	}
+	return []common.CSVTable{
+		common.NewCSVTable("items", rows),
+		common.NewCSVTable("commits", commitRows),
+	}
+}
@@ -0,0 +1,323 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// actionsReposAuto in GITHUB_ACTIONS_REPOS stands for the repositories with PRs or commits in the period
+const actionsReposAuto = "auto"
+
+// WorkflowRun is a GitHub Actions workflow run
+type WorkflowRun struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Event      string    `json:"event"` // push, pull_request, workflow_dispatch, schedule, ...
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"` // success, failure, cancelled, skipped, ... (empty while running)
+	HTMLURL    string    `json:"html_url"`
+	CreatedAt  time.Time `json:"created_at"`
+	Repository string    `json:"-"`
+}
+
+// Deployment is a deployment created through the deployments API, with the state of its latest status
+type Deployment struct {
+	ID          int64     `json:"id"`
+	Ref         string    `json:"ref"`
+	Environment string    `json:"environment"`
+	CreatedAt   time.Time `json:"created_at"`
+	Creator     struct {
+		Login string `json:"login"`
+	} `json:"creator"`
+	State      string `json:"-"` // success, failure, error, inactive, in_progress, ... ("" without statuses)
+	Repository string `json:"-"`
+}
+
+// Succeeded reports whether the deployment reached success; inactive deployments were successful and then superseded
+func (d Deployment) Succeeded() bool {
+	return d.State == "success" || d.State == "inactive"
+}
+
+// Failed reports whether the deployment ended in failure or error
+func (d Deployment) Failed() bool {
+	return d.State == "failure" || d.State == "error"
+}
+
+// RepoActionsStat totals workflow runs and deployments in one repository
+type RepoActionsStat struct {
+	Repository        string `json:"repository"`
+	Runs              int    `json:"runs"`
+	RunsSucceeded     int    `json:"runs_succeeded"`
+	RunsFailed        int    `json:"runs_failed"`
+	Deployments       int    `json:"deployments"`
+	DeploymentsFailed int    `json:"deployments_failed"`
+}
+
+// ActionsStats summarizes the workflow runs the user triggered and the deployments they created
+type ActionsStats struct {
+	Enabled            bool              `json:"enabled"`
+	Runs               []WorkflowRun     `json:"runs"`
+	ManualRuns         int               `json:"manual_runs"` // workflow_dispatch
+	RunsSucceeded      int               `json:"runs_succeeded"`
+	RunsFailed         int               `json:"runs_failed"`
+	Deployments        []Deployment      `json:"deployments"`
+	DeploymentsSuccess int               `json:"deployments_succeeded"`
+	DeploymentsFailed  int               `json:"deployments_failed"`
+	ByRepo             []RepoActionsStat `json:"by_repo"`
+}
+
+// RunSuccessRate returns the percentage of finished runs (success or failure) that succeeded
+func (s *ActionsStats) RunSuccessRate() float64 {
+	return successRate(s.RunsSucceeded, s.RunsFailed)
+}
+
+// DeploymentSuccessRate returns the percentage of finished deployments that succeeded
+func (s *ActionsStats) DeploymentSuccessRate() float64 {
+	return successRate(s.DeploymentsSuccess, s.DeploymentsFailed)
+}
+
+func successRate(succeeded, failed int) float64 {
+	if succeeded+failed == 0 {
+		return 0
+	}
+	return math.Round(float64(succeeded)/float64(succeeded+failed)*1000) / 10
+}
+
+// workflowRunsResponse is the list workflow runs API response
+type workflowRunsResponse struct {
+	TotalCount   int           `json:"total_count"`
+	WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+}
+
+// actionsRepos returns the repositories listed in GITHUB_ACTIONS_REPOS, expanding "auto" to the active repositories.
+// Analysis is off when the variable is not set, since it costs requests per repository.
+func (g *GitHubAnalyzer) actionsRepos(prs []PullRequest, commits *CommitStats) []string {
+	seen := make(map[string]bool)
+	var repos []string
+	add := func(fullName string) {
+		if fullName != "" && !seen[fullName] && g.repoFilter.Allows(fullName) {
+			seen[fullName] = true
+			repos = append(repos, fullName)
+		}
+	}
+	for _, entry := range strings.Split(os.Getenv("GITHUB_ACTIONS_REPOS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != actionsReposAuto {
+			add(entry)
+			continue
+		}
+		var active []string
+		for _, pr := range prs {
+			active = append(active, g.extractRepoFromURL(pr.RepositoryURL))
+		}
+		for _, stat := range commits.ByRepo {
+			active = append(active, stat.Repository)
+		}
+		sort.Strings(active)
+		for _, fullName := range active {
+			add(fullName)
+		}
+	}
+	return repos
+}
+
+// analyzeActions collects workflow runs triggered by the user and deployments they created in the period
+func (g *GitHubAnalyzer) analyzeActions(writer io.Writer, repos []string, startDate, endDate time.Time) *ActionsStats {
+	stats := &ActionsStats{Enabled: true}
+	for _, repo := range repos {
+		stat := RepoActionsStat{Repository: repo}
+
+		runs, err := g.workflowRuns(repo, startDate, endDate)
+		if err != nil {
+			g.warnings.Add("workflow runs", repo, err)
+		}
+		for _, run := range runs {
+			run.Repository = repo
+			stat.Runs++
+			switch run.Conclusion {
+			case "success":
+				stat.RunsSucceeded++
+				stats.RunsSucceeded++
+			case "failure", "timed_out", "startup_failure":
+				stat.RunsFailed++
+				stats.RunsFailed++
+			}
+			if run.Event == "workflow_dispatch" {
+				stats.ManualRuns++
+			}
+			stats.Runs = append(stats.Runs, run)
+		}
+
+		deployments, err := g.deployments(repo, startDate, endDate)
+		if err != nil {
+			g.warnings.Add("deployments", repo, err)
+		}
+		for _, deployment := range deployments {
+			deployment.Repository = repo
+			state, err := g.deploymentState(repo, deployment.ID)
+			if err != nil {
+				g.warnings.Add("deployment status", fmt.Sprintf("%s deployment %d", repo, deployment.ID), err)
+			}
+			deployment.State = state
+			stat.Deployments++
+			switch {
+			case deployment.Succeeded():
+				stats.DeploymentsSuccess++
+			case deployment.Failed():
+				stat.DeploymentsFailed++
+				stats.DeploymentsFailed++
+			}
+			stats.Deployments = append(stats.Deployments, deployment)
+		}
+
+		if stat.Runs > 0 || stat.Deployments > 0 {
+			stats.ByRepo = append(stats.ByRepo, stat)
+		}
+	}
+	fmt.Fprintf(writer, "Found %d workflow runs and %d deployments in %d repositories\n", len(stats.Runs), len(stats.Deployments), len(repos))
+
+	sort.SliceStable(stats.ByRepo, func(i, j int) bool {
+		if stats.ByRepo[i].Runs+stats.ByRepo[i].Deployments != stats.ByRepo[j].Runs+stats.ByRepo[j].Deployments {
+			return stats.ByRepo[i].Runs+stats.ByRepo[i].Deployments > stats.ByRepo[j].Runs+stats.ByRepo[j].Deployments
+		}
+		return stats.ByRepo[i].Repository < stats.ByRepo[j].Repository
+	})
+	sort.SliceStable(stats.Deployments, func(i, j int) bool {
+		return stats.Deployments[i].CreatedAt.Before(stats.Deployments[j].CreatedAt)
+	})
+	return stats
+}
+
+// workflowRuns lists the runs of a repository triggered by the user in the period, following pagination
+func (g *GitHubAnalyzer) workflowRuns(repo string, startDate, endDate time.Time) ([]WorkflowRun, error) {
+	var runs []WorkflowRun
+	perPage := 100
+	created := fmt.Sprintf("%s..%s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	for page := 1; ; page++ {
+		body, err := g.client.Get(fmt.Sprintf("%s/repos/%s/actions/runs?actor=%s&created=%s&per_page=%d&page=%d",
+			g.apiURL, repo, g.username, created, perPage, page), nil)
+		if err != nil {
+			return runs, err
+		}
+		var response workflowRunsResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return runs, err
+		}
+		runs = append(runs, response.WorkflowRuns...)
+		if len(response.WorkflowRuns) < perPage {
+			return runs, nil
+		}
+	}
+}
+
+// deployments lists the deployments the user created in the period. The API returns the newest first and
+// can't filter by date or creator, so paging stops at the first page that reaches before the period.
+func (g *GitHubAnalyzer) deployments(repo string, startDate, endDate time.Time) ([]Deployment, error) {
+	var deployments []Deployment
+	perPage := 100
+	endExclusive := endDate.AddDate(0, 0, 1)
+	for page := 1; ; page++ {
+		body, err := g.client.Get(fmt.Sprintf("%s/repos/%s/deployments?per_page=%d&page=%d", g.apiURL, repo, perPage, page), nil)
+		if err != nil {
+			return deployments, err
+		}
+		var pageDeployments []Deployment
+		if err := json.Unmarshal(body, &pageDeployments); err != nil {
+			return deployments, err
+		}
+		for _, deployment := range pageDeployments {
+			if strings.EqualFold(deployment.Creator.Login, g.username) &&
+				!deployment.CreatedAt.Before(startDate) && deployment.CreatedAt.Before(endExclusive) {
+				deployments = append(deployments, deployment)
+			}
+		}
+		if len(pageDeployments) < perPage || pageDeployments[len(pageDeployments)-1].CreatedAt.Before(startDate) {
+			return deployments, nil
+		}
+	}
+}
+
+// deploymentState returns the state of the latest status of a deployment
+func (g *GitHubAnalyzer) deploymentState(repo string, id int64) (string, error) {
+	body, err := g.client.Get(fmt.Sprintf("%s/repos/%s/deployments/%d/statuses?per_page=1", g.apiURL, repo, id), nil)
+	if err != nil {
+		return "", err
+	}
+	var statuses []struct {
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(body, &statuses); err != nil {
+		return "", err
+	}
+	if len(statuses) == 0 {
+		return "", nil
+	}
+	return statuses[0].State, nil
+}
+
+// deploymentActivities converts deployments into dated activities
+func (g *GitHubAnalyzer) deploymentActivities(deployments []Deployment) []common.Activity {
+	var activities []common.Activity
+	for _, deployment := range deployments {
+		activities = append(activities, common.Activity{
+			Source: g.GetName(),
+			Kind:   "deployment",
+			ID:     fmt.Sprintf("%s/deployments/%d", deployment.Repository, deployment.ID),
+			Title:  fmt.Sprintf("%s: deploy %s to %s", deployment.Repository, deployment.Ref, deployment.Environment),
+			Time:   deployment.CreatedAt,
+		})
+	}
+	return activities
+}
+
+// actionsMetrics returns the workflow and deployment metrics, which are only reported when GITHUB_ACTIONS_REPOS is set
+func actionsMetrics(stats *ActionsStats) []common.Metric {
+	if !stats.Enabled {
+		return nil
+	}
+	return []common.Metric{
+		{ID: "github.workflow_runs", Label: "Workflow runs triggered", Value: len(stats.Runs)},
+		{ID: "github.workflow_runs_manual", Label: "Workflow runs started manually", Value: stats.ManualRuns},
+		{ID: "github.workflow_runs_failed", Label: "Workflow runs failed", Value: stats.RunsFailed},
+		{ID: "github.workflow_success_rate", Label: "Workflow run success rate (%)", Value: stats.RunSuccessRate(), Snapshot: true},
+		{ID: "github.deployments", Label: "Deployments created", Value: len(stats.Deployments)},
+		{ID: "github.deployments_failed", Label: "Deployments failed", Value: stats.DeploymentsFailed},
+		{ID: "github.deployment_success_rate", Label: "Deployment success rate (%)", Value: stats.DeploymentSuccessRate(), Snapshot: true},
+	}
+}
+
+// printActions prints workflow runs and deployments per repository
+func (g *GitHubAnalyzer) printActions(writer io.Writer, stats *ActionsStats) {
+	if !stats.Enabled {
+		return
+	}
+	fmt.Fprintf(writer, "\nGitHub Actions and deployments (%d runs, %.1f%% succeeded; %d deployments, %.1f%% succeeded):\n",
+		len(stats.Runs), stats.RunSuccessRate(), len(stats.Deployments), stats.DeploymentSuccessRate())
+	if len(stats.ByRepo) == 0 {
+		fmt.Fprintln(writer, "- No workflow runs or deployments found")
+		return
+	}
+	for _, stat := range stats.ByRepo {
+		fmt.Fprintf(writer, "- %s: %d runs (%d succeeded, %d failed), %d deployments (%d failed)\n",
+			stat.Repository, stat.Runs, stat.RunsSucceeded, stat.RunsFailed, stat.Deployments, stat.DeploymentsFailed)
+	}
+	if len(stats.Deployments) > 0 {
+		fmt.Fprintln(writer, "\nDeployments you created:")
+		for _, deployment := range stats.Deployments {
+			state := deployment.State
+			if state == "" {
+				state = "no status"
+			}
+			fmt.Fprintf(writer, "- %s: %s %s → %s [%s]\n", deployment.CreatedAt.Local().Format("2006-01-02 15:04"),
+				deployment.Repository, deployment.Ref, deployment.Environment, state)
+		}
+	}
+}
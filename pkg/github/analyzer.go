@@ -4,22 +4,45 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/url"
 	"os"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
 )
 
 // GitHubAnalyzer implements the Analyzer interface for GitHub
 type GitHubAnalyzer struct {
-	token    string
-	username string
-	client   *common.HTTPClient
+	profile      string // profile name; empty for the default account (GITHUB_TOKEN)
+	token        string
+	username     string
+	host         string
+	apiURL       string
+	client       *common.HTTPClient
+	ignoreList   *config.IgnoreList
+	monorepos    *config.MonorepoConfig
+	prProjects   map[string][]string          // PR URL -> monorepo sub-projects touched by the PR
+	prSizes      map[string]int               // PR URL -> changed lines (additions + deletions) of authored PRs
+	prDetails    map[string]PullRequestDetail // PR URL -> state and merge time of authored PRs
+	repos        map[string]Repository        // lowercase owner/repo -> cached repository metadata
+	prFiles      map[string][]PullRequestFile // lowercase owner/repo#number -> changed files
+	botPatterns  []*regexp.Regexp
+	ossOrgs      map[string]bool // GITHUB_OSS_ORGS: always counted as open-source
+	internalOrgs map[string]bool // GITHUB_INTERNAL_ORGS: always counted as internal
+	repoFilter   repoFilter      // GITHUB_INCLUDE_*/GITHUB_EXCLUDE_*: organizations and repositories analyzed
+	warnings     common.Warnings // optional lookups that failed during the current run
 }
 
+// defaultBotPatterns match common automation accounts when GITHUB_BOT_PATTERNS is not set
+const defaultBotPatterns = "dependabot*,renovate*,*-bot,*[bot]"
+
+// defaultRateLimitMaxWaitMinutes is the longest wait for a GitHub rate limit to reset (the core quota resets hourly)
+const defaultRateLimitMaxWaitMinutes = 60
+
 // Label represents a GitHub label
 type Label struct {
 	Name  string `json:"name"`
@@ -33,6 +56,7 @@ type PullRequest struct {
 	CreatedAt time.Time `json:"created_at"`
 	User      struct {
 		Login string `json:"login"`
+		Type  string `json:"type"`
 	} `json:"user"`
 	RepositoryURL string  `json:"repository_url"`
 	Number        int     `json:"number"`
@@ -41,10 +65,13 @@ type PullRequest struct {
 
 // ReviewComment represents a PR review comment
 type ReviewComment struct {
-	ID        int       `json:"id"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
-	User      struct {
+	ID          int       `json:"id"`
+	InReplyToID int       `json:"in_reply_to_id"` // the first comment of the thread; 0 for the first comment itself
+	Body        string    `json:"body"`
+	Path        string    `json:"path"`
+	HTMLURL     string    `json:"html_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	User        struct {
 		Login string `json:"login"`
 	} `json:"user"`
 }
@@ -62,10 +89,42 @@ type Review struct {
 
 // ReviewStats tracks review activity
 type ReviewStats struct {
-	ReviewsGiven     int `json:"reviews_given"`
-	ApprovalsGiven   int `json:"approvals_given"`
-	CommentsGiven    int `json:"comments_given"`
-	ChangesRequested int `json:"changes_requested"`
+	ReviewsGiven          int               `json:"reviews_given"`
+	ApprovalsGiven        int               `json:"approvals_given"`
+	CommentsGiven         int               `json:"comments_given"` // reviews submitted as COMMENTED
+	ChangesRequested      int               `json:"changes_requested"`
+	ReviewCommentsWritten int               `json:"review_comments_written"` // comments on changed lines
+	ByRepo                []RepoReviewCount `json:"by_repo,omitempty"`
+	LongestThreads        []ReviewThread    `json:"longest_threads,omitempty"`
+	threads               []ReviewThread
+}
+
+// PullRequestDetail holds state, merge, and size information from the pulls API
+type PullRequestDetail struct {
+	State    string     `json:"state"`
+	ClosedAt *time.Time `json:"closed_at"`
+	MergedAt *time.Time `json:"merged_at"`
+	MergedBy *struct {
+		Login string `json:"login"`
+	} `json:"merged_by"`
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+}
+
+// PullRequestFile is a file changed by a PR, from the pulls files API
+type PullRequestFile struct {
+	Filename  string `json:"filename"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// DependencyUpdateStats tracks dependency-update PRs (dependabot/renovate) the user merged or approved
+type DependencyUpdateStats struct {
+	Merged   []PullRequest  `json:"merged"`
+	Approved []PullRequest  `json:"approved"`
+	Handled  []PullRequest  `json:"handled"`
+	ByRepo   map[string]int `json:"by_repo"`
 }
 
 // SearchResponse represents GitHub search API response
@@ -76,40 +135,202 @@ type SearchResponse struct {
 
 // NewGitHubAnalyzer creates a new GitHub analyzer
 func NewGitHubAnalyzer() *GitHubAnalyzer {
+	host := os.Getenv("GITHUB_HOST")
+	if host == "" {
+		host = defaultHost
+	}
 	return &GitHubAnalyzer{
-		token:    os.Getenv("GITHUB_TOKEN"),
-		username: os.Getenv("GITHUB_USERNAME"),
-		client:   common.NewHTTPClient(),
+		token:        os.Getenv("GITHUB_TOKEN"),
+		username:     os.Getenv("GITHUB_USERNAME"),
+		host:         host,
+		apiURL:       apiBaseURL(host),
+		client:       common.NewHTTPClient(),
+		botPatterns:  botPatternsFromEnv(),
+		ossOrgs:      orgSetFromEnv("GITHUB_OSS_ORGS"),
+		internalOrgs: orgSetFromEnv("GITHUB_INTERNAL_ORGS"),
+		repoFilter:   repoFilterFromEnv(),
+	}
+}
+
+// NewGitHubAnalyzerWithProfile creates a GitHub analyzer for a specific profile
+func NewGitHubAnalyzerWithProfile(profile *GitHubProfile) *GitHubAnalyzer {
+	analyzer := NewGitHubAnalyzer()
+	analyzer.profile = profile.Name
+	analyzer.token = profile.Token
+	analyzer.username = profile.Username
+	analyzer.host = profile.Host
+	analyzer.apiURL = apiBaseURL(profile.Host)
+	return analyzer
+}
+
+// envPrefix returns the prefix of the environment variables of this account (GITHUB or GITHUB_<PROFILE>)
+func (g *GitHubAnalyzer) envPrefix() string {
+	if g.profile == "" {
+		return "GITHUB"
 	}
+	return "GITHUB_" + g.profile
+}
+
+// tokenSettingsURL returns where tokens of this host are managed
+func (g *GitHubAnalyzer) tokenSettingsURL() string {
+	return fmt.Sprintf("https://%s/settings/tokens", g.host)
+}
+
+// WhoAmI reports the owner of the token
+func (g *GitHubAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if g.token == "" {
+		return nil, common.NewError("%s_TOKEN environment variable is required", g.envPrefix())
+	}
+	g.client.SetHeader("Authorization", "token "+g.token)
+	g.client.SetHeader("Accept", "application/vnd.github.v3+json")
+	g.client.WaitOnRateLimit(writer, rateLimitMaxWaitFromEnv())
+
+	body, err := g.client.Get(g.apiURL+"/user", nil)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get the token owner")
+	}
+	var user struct {
+		ID    int    `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, common.WrapError(err, "failed to parse user response")
+	}
+
+	identity := &common.Identity{Source: g.GetName(), ID: strconv.Itoa(user.ID), Login: user.Login, Name: user.Name}
+	if g.username != "" && !strings.EqualFold(g.username, user.Login) {
+		identity.Note = fmt.Sprintf("%s_USERNAME is '%s'", g.envPrefix(), g.username)
+	}
+	return identity, nil
+}
+
+// rateLimitMaxWaitFromEnv reads GITHUB_RATE_LIMIT_MAX_WAIT_MINUTES (default 60; 0 fails immediately on rate limits)
+func rateLimitMaxWaitFromEnv() time.Duration {
+	minutes := defaultRateLimitMaxWaitMinutes
+	if value := os.Getenv("GITHUB_RATE_LIMIT_MAX_WAIT_MINUTES"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			minutes = parsed
+		}
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// botPatternsFromEnv compiles GITHUB_BOT_PATTERNS (comma-separated, "*" is a wildcard) into matchers
+func botPatternsFromEnv() []*regexp.Regexp {
+	value := os.Getenv("GITHUB_BOT_PATTERNS")
+	if value == "" {
+		value = defaultBotPatterns
+	}
+
+	var patterns []*regexp.Regexp
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		patterns = append(patterns, regexp.MustCompile(expr))
+	}
+	return patterns
 }
 
 // GetName returns the analyzer name
 func (g *GitHubAnalyzer) GetName() string {
+	if g.profile != "" {
+		return fmt.Sprintf("GitHub (%s)", g.profile)
+	}
 	return "GitHub"
 }
 
-// ValidateConfig validates the required configuration
-func (g *GitHubAnalyzer) ValidateConfig() error {
+// ValidateConfig validates the required configuration and verifies token scopes up front
+func (g *GitHubAnalyzer) ValidateConfig(writer io.Writer) error {
 	if g.token == "" {
-		return common.NewError("GITHUB_TOKEN environment variable is required")
+		return common.NewError("%s_TOKEN environment variable is required", g.envPrefix())
 	}
 	if g.username == "" {
-		return common.NewError("GITHUB_USERNAME environment variable is required")
+		return common.NewError("%s_USERNAME environment variable is required", g.envPrefix())
 	}
+
+	g.client.SetHeader("Authorization", "token "+g.token)
+	g.client.SetHeader("Accept", "application/vnd.github.v3+json")
+	g.client.WaitOnRateLimit(writer, rateLimitMaxWaitFromEnv())
+
+	fmt.Fprintf(writer, "Checking %s token permissions...\n", g.GetName())
+	body, headers, err := g.client.GetWithResponseHeaders(g.apiURL+"/user", nil)
+	if err != nil {
+		switch common.HTTPStatusCode(err) {
+		case 401:
+			return common.NewError("%s_TOKEN is invalid or expired.\n"+
+				"Generate a new token at %s with 'repo' and 'read:org' scopes", g.envPrefix(), g.tokenSettingsURL())
+		case 403:
+			return common.NewError("%s_TOKEN was rejected (HTTP 403).\n"+
+				"The token may be blocked by your organization's SSO or IP allow list. Authorize it for SSO at %s", g.envPrefix(), g.tokenSettingsURL())
+		}
+		return common.WrapError(err, "failed to connect to GitHub API")
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.Unmarshal(body, &user); err == nil && user.Login != "" && !strings.EqualFold(user.Login, g.username) {
+		fmt.Fprintf(writer, "⚠️  %[1]s_TOKEN belongs to '%[2]s' but %[1]s_USERNAME is '%[3]s'. Private activity of '%[3]s' may be missing.\n",
+			g.envPrefix(), user.Login, g.username)
+	}
+
+	// Classic tokens report their scopes in X-OAuth-Scopes; fine-grained tokens do not
+	scopesHeader, hasScopes := headers["X-Oauth-Scopes"]
+	if !hasScopes {
+		fmt.Fprintln(writer, "✓ GitHub token is valid (fine-grained token: make sure it has read access to Pull requests and Metadata of the target repositories)")
+		return nil
+	}
+
+	scopes := make(map[string]bool)
+	for _, scope := range strings.Split(strings.Join(scopesHeader, ","), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes[scope] = true
+		}
+	}
+
+	var missing []string
+	if !scopes["repo"] {
+		if scopes["public_repo"] {
+			fmt.Fprintf(writer, "⚠️  %s_TOKEN only has 'public_repo' scope. Pull requests in private repositories will be missing.\n", g.envPrefix())
+		} else {
+			missing = append(missing, "repo")
+		}
+	}
+	if !scopes["read:org"] && !scopes["admin:org"] && !scopes["write:org"] {
+		missing = append(missing, "read:org")
+	}
+	if len(missing) > 0 {
+		return common.NewError("%s_TOKEN is missing required scopes: %s (current scopes: %s).\n"+
+			"Update the token at %s",
+			g.envPrefix(), strings.Join(missing, ", "), strings.Join(scopesHeader, ","), g.tokenSettingsURL())
+	}
+
+	fmt.Fprintln(writer, "✓ GitHub token has required scopes")
 	return nil
 }
 
 // Analyze performs GitHub analysis
 func (g *GitHubAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
-	if err := g.ValidateConfig(); err != nil {
+	if err := g.ValidateConfig(writer); err != nil {
 		return nil, err
 	}
-
-	g.client.SetHeader("Authorization", "token "+g.token)
-	g.client.SetHeader("Accept", "application/vnd.github.v3+json")
+	if err := g.loadConfigFiles(); err != nil {
+		return nil, err
+	}
+	g.warnings.Reset()
 
 	fmt.Fprintf(writer, "Analyzing GitHub activity for user: %s\n", g.username)
+	if g.host != defaultHost {
+		fmt.Fprintf(writer, "Host: %s\n", g.host)
+	}
 	fmt.Fprintf(writer, "Date range: %s to %s\n", config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+	if !g.repoFilter.IsEmpty() {
+		fmt.Fprintf(writer, "Repository filter: %s\n", g.repoFilter)
+	}
 
 	// Get PRs where user is involved
 	involvedPRs, err := g.searchPRs(writer, "involves:"+g.username, config.StartDate, config.EndDate)
@@ -123,14 +344,61 @@ func (g *GitHubAnalyzer) Analyze(config *common.Config, writer io.Writer) (*comm
 		return nil, common.WrapError(err, "failed to search authored PRs")
 	}
 
+	involvedPRs = g.filterIgnored(writer, involvedPRs)
+	authoredPRs = g.filterIgnored(writer, authoredPRs)
+
+	// "Involved" should reflect human collaboration, so PRs opened by bots are set aside
+	involvedPRs, botPRs := g.splitBotPRs(involvedPRs)
+	if len(botPRs) > 0 {
+		fmt.Fprintf(writer, "Excluded %d PRs opened by bot accounts from involved counts\n", len(botPRs))
+	}
+
 	// Analyze review activity
 	fmt.Fprintln(writer, "Analyzing review activity...")
 	reviewStats, err := g.analyzeReviewActivity(writer, involvedPRs, config.StartDate, config.EndDate)
 	if err != nil {
-		fmt.Fprintf(writer, "Warning: Failed to analyze review activity: %v\n", err)
+		g.warnings.Add("review activity", "", err)
 		reviewStats = &ReviewStats{} // Use empty stats if analysis fails
 	}
 
+	// PR sizes feed the effort estimation and merge times the cycle time and dependency updates; the search API
+	// doesn't include them
+	fmt.Fprintln(writer, "Fetching details of authored and dependency-update PRs...")
+	dependencyPRs := g.dependencyUpdatePRs(botPRs)
+	g.fetchPRDetails(writer, append(append([]PullRequest{}, authoredPRs...), dependencyPRs...))
+
+	// Dependency updates are maintenance work worth reporting even though bot PRs are excluded above
+	fmt.Fprintln(writer, "Analyzing dependency-update PRs...")
+	dependencyStats := g.analyzeDependencyUpdates(writer, dependencyPRs, config.StartDate, config.EndDate)
+
+	// Attribute monorepo PRs to sub-projects by changed file paths
+	monorepoStats := g.attributeMonorepoPRs(writer, authoredPRs, involvedPRs)
+
+	cycleTimeStats := g.analyzeCycleTime(authoredPRs)
+	prSizeStats := g.analyzePRSizes(authoredPRs)
+
+	// Commits on default branches complement PRs (direct pushes, personal repositories)
+	fmt.Fprintln(writer, "Analyzing authored commits...")
+	commitStats := g.analyzeCommits(writer, config.StartDate, config.EndDate)
+
+	// Workflow runs and deployments show DevOps work that PRs don't (opt-in: GITHUB_ACTIONS_REPOS)
+	actionsStats := &ActionsStats{}
+	if os.Getenv("GITHUB_ACTIONS_REPOS") != "" {
+		fmt.Fprintln(writer, "Analyzing workflow runs and deployments...")
+		repos := g.actionsRepos(append(append([]PullRequest{}, authoredPRs...), involvedPRs...), commitStats)
+		actionsStats = g.analyzeActions(writer, repos, config.StartDate, config.EndDate)
+	}
+
+	// Repository metadata (language, topics, visibility) is cached across runs
+	fmt.Fprintln(writer, "Fetching repository metadata...")
+	g.repos = g.fetchRepositories(writer, append(append([]PullRequest{}, authoredPRs...), involvedPRs...))
+	languageStats, topicStats, visibilityStats := g.analyzeRepoBreakdowns(authoredPRs, involvedPRs)
+	ossStats := g.analyzeOSS(authoredPRs, involvedPRs)
+
+	// Lines changed per language/file type in authored PRs (skills-usage profile)
+	fmt.Fprintln(writer, "Analyzing changed files of authored PRs...")
+	fileTypeStats := g.analyzeFileTypes(writer, authoredPRs)
+
 	// Analyze results
 	orgStats := make(map[string]struct{ authored, involved int })
 	repoStats := make(map[string]struct{ authored, involved int })
@@ -200,75 +468,288 @@ func (g *GitHubAnalyzer) Analyze(config *common.Config, writer io.Writer) (*comm
 		AnalyzerName: g.GetName(),
 		StartDate:    config.StartDate,
 		EndDate:      config.EndDate,
-		Summary: map[string]interface{}{
-			"Total PRs":            len(involvedPRs),
-			"Total PRs (author)":   len(authoredPRs),
-			"Total PRs (involves)": len(involvedPRs),
-			"PRs (valuable)":       len(valuablePRs),
-			"PRs (low-value)":      len(lowValuePRs),
-			"Active organizations": len(orgStats),
-			"Active repositories":  len(repoStats),
-			"Unique labels":        len(labelStats),
-			"Reviews given":        reviewStats.ReviewsGiven,
-			"Approvals given":      reviewStats.ApprovalsGiven,
-			"Review comments":      reviewStats.CommentsGiven,
-			"Changes requested":    reviewStats.ChangesRequested,
+		Metrics: []common.Metric{
+			{ID: "github.prs_total", Label: "Total PRs", Value: len(involvedPRs)},
+			{ID: "github.prs_authored", Label: "Total PRs (author)", Value: len(authoredPRs)},
+			{ID: "github.prs_involved", Label: "Total PRs (involves)", Value: len(involvedPRs)},
+			{ID: "github.prs_valuable", Label: "PRs (valuable)", Value: len(valuablePRs)},
+			{ID: "github.prs_low_value", Label: "PRs (low-value)", Value: len(lowValuePRs)},
+			{ID: "github.active_organizations", Label: "Active organizations", Value: len(orgStats), Snapshot: true},
+			{ID: "github.active_repositories", Label: "Active repositories", Value: len(repoStats), Snapshot: true},
+			{ID: "github.unique_labels", Label: "Unique labels", Value: len(labelStats), Snapshot: true},
+			{ID: "github.reviews_given", Label: "Reviews given", Value: reviewStats.ReviewsGiven},
+			{ID: "github.approvals_given", Label: "Approvals given", Value: reviewStats.ApprovalsGiven},
+			{ID: "github.review_comments", Label: "Review comments", Value: reviewStats.CommentsGiven},
+			{ID: "github.changes_requested", Label: "Changes requested", Value: reviewStats.ChangesRequested},
+			{ID: "github.review_comments_written", Label: "Review comments written", Value: reviewStats.ReviewCommentsWritten},
+			{ID: "github.comments_per_review", Label: "Review comments per review", Value: reviewStats.CommentsPerReview(), Snapshot: true},
+			{ID: "github.prs_oss_authored", Label: "PRs open-source (author)", Value: len(ossStats.OSSAuthored)},
+			{ID: "github.prs_oss_involved", Label: "PRs open-source (involves)", Value: len(ossStats.OSSInvolved)},
+			{ID: "github.prs_internal_authored", Label: "PRs internal (author)", Value: len(ossStats.InternalAuthored)},
+			{ID: "github.prs_internal_involved", Label: "PRs internal (involves)", Value: len(ossStats.InternalInvolved)},
+			{ID: "github.prs_by_bots_excluded", Label: "PRs by bots (excluded)", Value: len(botPRs)},
+			{ID: "github.dependency_updates_merged", Label: "Dependency updates merged", Value: len(dependencyStats.Merged)},
+			{ID: "github.dependency_updates_approved", Label: "Dependency updates approved", Value: len(dependencyStats.Approved)},
+			{ID: "github.commits", Label: "Commits", Value: len(commitStats.Commits)},
+			{ID: "github.commit_lines_added", Label: "Lines added (commits)", Value: commitStats.Additions},
+			{ID: "github.commit_lines_deleted", Label: "Lines deleted (commits)", Value: commitStats.Deletions},
+			{ID: "github.commit_repositories", Label: "Repositories with commits", Value: len(commitStats.ByRepo), Snapshot: true},
+			{ID: "github.prs_merged", Label: "Authored PRs merged", Value: len(cycleTimeStats.Merged)},
+			{ID: "github.prs_closed_unmerged", Label: "Authored PRs closed unmerged", Value: len(cycleTimeStats.ClosedUnmerged)},
+			{ID: "github.merge_rate", Label: "Merge rate of closed PRs (%)", Value: cycleTimeStats.MergeRate(), Snapshot: true},
+			{ID: "github.lead_time_median", Label: "Median time to merge", Value: cycleTimeStats.Median, Snapshot: true},
+			{ID: "github.lead_time_mean", Label: "Mean time to merge", Value: cycleTimeStats.Mean, Snapshot: true},
+			{ID: "github.pr_lines_added", Label: "Lines added (authored PRs)", Value: prSizeStats.Additions},
+			{ID: "github.pr_lines_deleted", Label: "Lines deleted (authored PRs)", Value: prSizeStats.Deletions},
+			{ID: "github.pr_files_changed", Label: "Files changed (authored PRs)", Value: prSizeStats.ChangedFiles},
 		},
 		Details: map[string]interface{}{
-			"authored_prs":  authoredPRs,
-			"involved_prs":  involvedPRs,
-			"valuable_prs":  valuablePRs,
-			"low_value_prs": lowValuePRs,
-			"org_stats":     orgStats,
-			"repo_stats":    repoStats,
-			"label_stats":   labelStats,
-			"review_stats":  reviewStats,
+			"authored_prs":       authoredPRs,
+			"involved_prs":       involvedPRs,
+			"valuable_prs":       valuablePRs,
+			"low_value_prs":      lowValuePRs,
+			"bot_prs":            botPRs,
+			"dependency_updates": dependencyStats,
+			"monorepo_stats":     monorepoStats,
+			"org_stats":          orgStats,
+			"repo_stats":         repoStats,
+			"label_stats":        labelStats,
+			"repositories":       g.repos,
+			"language_stats":     languageStats,
+			"topic_stats":        topicStats,
+			"visibility_stats":   visibilityStats,
+			"file_type_stats":    fileTypeStats,
+			"oss_stats":          ossStats,
+			"review_stats":       reviewStats,
+			"commit_stats":       commitStats,
+			"cycle_time_stats":   cycleTimeStats,
+			"pr_size_stats":      prSizeStats,
+			"actions_stats":      actionsStats,
 		},
+		Activities: append(g.buildActivities(authoredPRs, involvedPRs), g.commitActivities(commitStats.Commits)...),
+		CSVTables:  g.csvTables(authoredPRs, involvedPRs, commitStats.Commits),
+	}
+	g.explainMetrics(result, authoredPRs, involvedPRs, valuablePRs, lowValuePRs, botPRs, ossStats, dependencyStats, cycleTimeStats)
+	result.Explain("github.commits", g.commitActivities(commitStats.Commits))
+	if actionsStats.Enabled {
+		result.Metrics = append(result.Metrics, actionsMetrics(actionsStats)...)
+		result.Activities = append(result.Activities, g.deploymentActivities(actionsStats.Deployments)...)
+		result.Explain("github.deployments", g.deploymentActivities(actionsStats.Deployments))
 	}
 
 	g.printResults(writer, result, authoredPRs, involvedPRs, valuablePRs, lowValuePRs, orgStats, repoStats, labelStats, reviewStats)
+	g.printCommits(writer, commitStats)
+	g.printActions(writer, actionsStats)
+	g.printCycleTime(writer, cycleTimeStats)
+	g.printPRSizes(writer, prSizeStats)
+	g.printRepoBreakdown(writer, "PR share per repository language", languageStats, len(authoredPRs), len(involvedPRs))
+	g.printRepoBreakdown(writer, "PR share per repository topic", topicStats, len(authoredPRs), len(involvedPRs))
+	g.printRepoBreakdown(writer, "PR share per repository visibility", visibilityStats, len(authoredPRs), len(involvedPRs))
+	g.printOSS(writer, ossStats)
+	g.printFileTypes(writer, fileTypeStats)
+	g.printMonorepoStats(writer, monorepoStats)
+	g.printDependencyUpdates(writer, dependencyStats)
+	result.Warnings = g.warnings.List()
 	return result, nil
 }
 
+// searchPRs searches PRs created in the period. A query matching more than the search API returns is split
+// into months (or halves of a month) and searched again per part, so that no PR is dropped.
 func (g *GitHubAnalyzer) searchPRs(writer io.Writer, query string, startDate, endDate time.Time) ([]PullRequest, error) {
-	var allPRs []PullRequest
-	page := 1
-	perPage := 100
-
-	dateRange := fmt.Sprintf("created:%s..%s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
-	fullQuery := fmt.Sprintf("%s type:pr %s", query, dateRange)
-
-	fmt.Fprintf(writer, "Searching GitHub with query: %s\n", fullQuery)
-
-	for {
-		apiURL := fmt.Sprintf("https://api.github.com/search/issues?q=%s&page=%d&per_page=%d",
-			url.QueryEscape(fullQuery), page, perPage)
+	return g.searchPRsInRange(writer, query, dateRange{startDate, endDate})
+}
 
-		fmt.Fprintf(writer, "Making request to GitHub API (page %d)...\n", page)
+func (g *GitHubAnalyzer) searchPRsInRange(writer io.Writer, query string, period dateRange) ([]PullRequest, error) {
+	parts := period.split()
+	fullQuery := g.repoFilter.Qualify(fmt.Sprintf("%s type:pr created:%s", query, period))
+	prs, total, err := g.searchIssuePages(writer, fullQuery, len(parts) > 0)
+	if err != nil {
+		return nil, err
+	}
+	if total <= searchResultLimit || len(parts) == 0 {
+		g.warnTruncated(fullQuery, total, len(prs))
+		return prs, nil
+	}
 
-		body, err := g.client.Get(apiURL, nil)
+	fmt.Fprintf(writer, "%d results exceed the search limit of %d; splitting %s into %d parts\n", total, searchResultLimit, period, len(parts))
+	prs = nil
+	for _, part := range parts {
+		partPRs, err := g.searchPRsInRange(writer, query, part)
 		if err != nil {
 			return nil, err
 		}
+		prs = append(prs, partPRs...)
+	}
+	return prs, nil
+}
+
+// searchIssues runs a search query, following pagination up to the search limit (with a warning when it is hit)
+func (g *GitHubAnalyzer) searchIssues(writer io.Writer, fullQuery string) ([]PullRequest, error) {
+	fullQuery = g.repoFilter.Qualify(fullQuery)
+	prs, total, err := g.searchIssuePages(writer, fullQuery, false)
+	if err != nil {
+		return nil, err
+	}
+	g.warnTruncated(fullQuery, total, len(prs))
+	return prs, nil
+}
 
+// searchIssuePages fetches the pages of an issue search and returns the items with total_count
+func (g *GitHubAnalyzer) searchIssuePages(writer io.Writer, fullQuery string, stopOverLimit bool) ([]PullRequest, int, error) {
+	var allPRs []PullRequest
+	fmt.Fprintf(writer, "Searching GitHub with query: %s\n", fullQuery)
+	total, _, err := g.searchPages(writer, "issues", fullQuery, stopOverLimit, func(body []byte) (int, int, error) {
 		var response SearchResponse
 		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, common.WrapError(err, "failed to parse GitHub response")
+			return 0, 0, common.WrapError(err, "failed to parse GitHub response")
 		}
-
 		allPRs = append(allPRs, response.Items...)
+		return response.TotalCount, len(response.Items), nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return allPRs, total, nil
+}
 
-		if len(response.Items) < perPage {
-			break
+// loadConfigFiles loads config/ignore.yaml and config/monorepos.yaml for this run
+func (g *GitHubAnalyzer) loadConfigFiles() error {
+	ignoreList, err := config.LoadIgnoreList("")
+	if err != nil {
+		return err
+	}
+	monorepos, err := config.LoadMonorepoConfig("")
+	if err != nil {
+		return err
+	}
+	g.ignoreList = ignoreList
+	g.monorepos = monorepos
+	return nil
+}
+
+// isIgnored reports whether a PR is listed in the ignore file by URL or owner/repo#number
+func (g *GitHubAnalyzer) isIgnored(pr PullRequest) bool {
+	fullName := g.extractRepoFromURL(pr.RepositoryURL)
+	return g.ignoreList.Contains(pr.URL, fmt.Sprintf("%s#%d", fullName, pr.Number))
+}
+
+// filterIgnored drops PRs listed in the ignore file and PRs in repositories excluded by GITHUB_INCLUDE_*/GITHUB_EXCLUDE_*
+func (g *GitHubAnalyzer) filterIgnored(writer io.Writer, prs []PullRequest) []PullRequest {
+	var kept []PullRequest
+	ignored, filtered := 0, 0
+	for _, pr := range prs {
+		switch {
+		case g.isIgnored(pr):
+			ignored++
+		case !g.repoFilter.Allows(g.extractRepoFromURL(pr.RepositoryURL)):
+			filtered++
+		default:
+			kept = append(kept, pr)
+		}
+	}
+	if ignored > 0 {
+		fmt.Fprintf(writer, "Ignored %d PRs listed in %s\n", ignored, config.DefaultIgnoreListPath)
+	}
+	if filtered > 0 {
+		fmt.Fprintf(writer, "Excluded %d PRs by GITHUB_INCLUDE_*/GITHUB_EXCLUDE_* repository filters\n", filtered)
+	}
+	return kept
+}
+
+// isBot reports whether a login matches one of the bot patterns
+func (g *GitHubAnalyzer) isBot(login string) bool {
+	login = strings.ToLower(login)
+	for _, pattern := range g.botPatterns {
+		if pattern.MatchString(login) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBotPR reports whether a PR was opened by a GitHub App or an account matching the bot patterns
+func (g *GitHubAnalyzer) isBotPR(pr PullRequest) bool {
+	return pr.User.Type == "Bot" || g.isBot(pr.User.Login)
+}
+
+// splitBotPRs separates PRs opened by bots from those opened by people
+func (g *GitHubAnalyzer) splitBotPRs(prs []PullRequest) (humanPRs, botPRs []PullRequest) {
+	for _, pr := range prs {
+		if g.isBotPR(pr) {
+			botPRs = append(botPRs, pr)
+		} else {
+			humanPRs = append(humanPRs, pr)
+		}
+	}
+	return humanPRs, botPRs
+}
+
+// buildActivities converts PRs into dated activities, listing authored PRs once
+func (g *GitHubAnalyzer) buildActivities(authoredPRs, involvedPRs []PullRequest) []common.Activity {
+	var activities []common.Activity
+	seen := make(map[string]bool)
+
+	for _, pr := range authoredPRs {
+		seen[pr.URL] = true
+		activities = append(activities, g.prActivity(pr, "pr_authored"))
+	}
+	for _, pr := range involvedPRs {
+		if seen[pr.URL] {
+			continue
+		}
+		seen[pr.URL] = true
+		activities = append(activities, g.prActivity(pr, "pr_involved"))
+	}
+
+	return activities
+}
+
+// explainMetrics records the PRs counted in each PR metric for -explain
+func (g *GitHubAnalyzer) explainMetrics(result *common.AnalysisResult, authoredPRs, involvedPRs, valuablePRs, lowValuePRs, botPRs []PullRequest, ossStats *OSSStats, dependencyStats *DependencyUpdateStats, cycleTimeStats *CycleTimeStats) {
+	for id, prs := range map[string][]PullRequest{
+		"github.prs_total":                   involvedPRs,
+		"github.prs_authored":                authoredPRs,
+		"github.prs_involved":                involvedPRs,
+		"github.prs_valuable":                valuablePRs,
+		"github.prs_low_value":               lowValuePRs,
+		"github.prs_oss_authored":            ossStats.OSSAuthored,
+		"github.prs_oss_involved":            ossStats.OSSInvolved,
+		"github.prs_internal_authored":       ossStats.InternalAuthored,
+		"github.prs_internal_involved":       ossStats.InternalInvolved,
+		"github.prs_by_bots_excluded":        botPRs,
+		"github.dependency_updates_merged":   dependencyStats.Merged,
+		"github.dependency_updates_approved": dependencyStats.Approved,
+		"github.prs_merged":                  cycleTimeStats.Merged,
+		"github.prs_closed_unmerged":         cycleTimeStats.ClosedUnmerged,
+	} {
+		var activities []common.Activity
+		for _, pr := range prs {
+			activities = append(activities, g.prActivity(pr, "pr"))
 		}
-		page++
+		result.Explain(id, activities)
 	}
+}
 
-	return allPRs, nil
+// prActivity converts a PR into a common activity
+func (g *GitHubAnalyzer) prActivity(pr PullRequest, kind string) common.Activity {
+	activity := common.Activity{
+		Source: g.GetName(),
+		Kind:   kind,
+		ID:     pr.URL,
+		Title:  fmt.Sprintf("%s#%d %s", g.extractRepoFromURL(pr.RepositoryURL), pr.Number, pr.Title),
+		URL:    pr.URL,
+		Time:   pr.CreatedAt,
+		Size:   g.prSizes[pr.URL],
+	}
+	// A PR spanning several sub-projects can't be attributed to a single project
+	if projects := g.prProjects[pr.URL]; len(projects) == 1 {
+		activity.Project = projects[0]
+	}
+	return activity
 }
 
 func (g *GitHubAnalyzer) extractRepoFromURL(repoURL string) string {
-	// Extract repository name from URL like "https://api.github.com/repos/owner/repo"
+	// Extract repository name from URL like "https://api.github.com/repos/owner/repo" (or https://HOST/api/v3/repos/owner/repo)
 	parts := strings.Split(repoURL, "/")
 	if len(parts) >= 2 {
 		// Return "owner/repo" format
@@ -369,6 +850,7 @@ func (g *GitHubAnalyzer) printResults(writer io.Writer, result *common.AnalysisR
 	fmt.Fprintf(writer, "- Approvals given: %d\n", reviewStats.ApprovalsGiven)
 	fmt.Fprintf(writer, "- Review comments: %d\n", reviewStats.CommentsGiven)
 	fmt.Fprintf(writer, "- Changes requested: %d\n", reviewStats.ChangesRequested)
+	g.printReviewComments(writer, reviewStats)
 
 	// Print organization stats
 	fmt.Fprintln(writer, "\nPR count per organization (author/involves):")
@@ -382,11 +864,18 @@ func (g *GitHubAnalyzer) printResults(writer io.Writer, result *common.AnalysisR
 		sortedOrgs = append(sortedOrgs, orgStat{name, stat.authored, stat.involved})
 	}
 	sort.Slice(sortedOrgs, func(i, j int) bool {
+		if sortedOrgs[i].authored != sortedOrgs[j].authored {
+			return sortedOrgs[i].authored > sortedOrgs[j].authored
+		}
+		if sortedOrgs[i].involved != sortedOrgs[j].involved {
+			return sortedOrgs[i].involved > sortedOrgs[j].involved
+		}
 		return sortedOrgs[i].name < sortedOrgs[j].name
 	})
-	for _, stat := range sortedOrgs {
+	for _, stat := range sortedOrgs[:common.RankingLimit(len(sortedOrgs))] {
 		fmt.Fprintf(writer, "- %s: %d (%d)\n", stat.name, stat.authored, stat.involved)
 	}
+	common.PrintMoreEntries(writer, len(sortedOrgs))
 
 	// Print repository stats
 	fmt.Fprintln(writer, "\nPR count per repository (author/involves):")
@@ -400,11 +889,18 @@ func (g *GitHubAnalyzer) printResults(writer io.Writer, result *common.AnalysisR
 		sortedRepos = append(sortedRepos, repoStat{name, stat.authored, stat.involved})
 	}
 	sort.Slice(sortedRepos, func(i, j int) bool {
+		if sortedRepos[i].authored != sortedRepos[j].authored {
+			return sortedRepos[i].authored > sortedRepos[j].authored
+		}
+		if sortedRepos[i].involved != sortedRepos[j].involved {
+			return sortedRepos[i].involved > sortedRepos[j].involved
+		}
 		return sortedRepos[i].name < sortedRepos[j].name
 	})
-	for _, stat := range sortedRepos {
+	for _, stat := range sortedRepos[:common.RankingLimit(len(sortedRepos))] {
 		fmt.Fprintf(writer, "- %s: %d (%d)\n", stat.name, stat.authored, stat.involved)
 	}
+	common.PrintMoreEntries(writer, len(sortedRepos))
 
 	// Print label stats
 	fmt.Fprintln(writer, "\nLabel usage statistics:")
@@ -426,9 +922,10 @@ func (g *GitHubAnalyzer) printResults(writer io.Writer, result *common.AnalysisR
 	if len(sortedLabels) == 0 {
 		fmt.Fprintln(writer, "- No labels found in authored PRs")
 	} else {
-		for _, stat := range sortedLabels {
+		for _, stat := range sortedLabels[:common.RankingLimit(len(sortedLabels))] {
 			fmt.Fprintf(writer, "- %s: %d\n", stat.name, stat.count)
 		}
+		common.PrintMoreEntries(writer, len(sortedLabels))
 	}
 }
 
@@ -443,13 +940,22 @@ func (g *GitHubAnalyzer) analyzeReviewActivity(writer io.Writer, involvedPRs []P
 		repoMap[repoFullName] = true
 	}
 
-	fmt.Fprintf(writer, "Analyzing reviews across %d repositories...\n", len(repoMap))
+	repoNames := make([]string, 0, len(repoMap))
+	for repoFullName := range repoMap {
+		repoNames = append(repoNames, repoFullName)
+	}
+	sort.Strings(repoNames)
+
+	fmt.Fprintf(writer, "Analyzing reviews across %d repositories...\n", len(repoNames))
 
 	// Analyze each repository
-	for repoFullName := range repoMap {
+	var byRepo []RepoReviewCount
+	var threads []ReviewThread
+	for i, repoFullName := range repoNames {
+		fmt.Fprintf(writer, "  [%d/%d] %s\n", i+1, len(repoNames), repoFullName)
 		repoStats, err := g.getReviewStatsForRepo(writer, repoFullName, startDate, endDate)
 		if err != nil {
-			fmt.Fprintf(writer, "Warning: Failed to get review stats for %s: %v\n", repoFullName, err)
+			g.warnings.Add("review stats", repoFullName, err)
 			continue
 		}
 
@@ -457,7 +963,13 @@ func (g *GitHubAnalyzer) analyzeReviewActivity(writer io.Writer, involvedPRs []P
 		stats.ApprovalsGiven += repoStats.ApprovalsGiven
 		stats.CommentsGiven += repoStats.CommentsGiven
 		stats.ChangesRequested += repoStats.ChangesRequested
+		stats.ReviewCommentsWritten += repoStats.ReviewCommentsWritten
+		if repoStats.ReviewsGiven > 0 || repoStats.ReviewCommentsWritten > 0 {
+			byRepo = append(byRepo, RepoReviewCount{Repository: repoFullName, Reviews: repoStats.ReviewsGiven, Comments: repoStats.ReviewCommentsWritten})
+		}
+		threads = append(threads, repoStats.threads...)
 	}
+	summarizeReviewComments(stats, byRepo, threads)
 
 	return stats, nil
 }
@@ -470,42 +982,40 @@ func (g *GitHubAnalyzer) getReviewStatsForRepo(writer io.Writer, repoFullName st
 	query := fmt.Sprintf("repo:%s type:pr reviewed-by:%s created:%s..%s",
 		repoFullName, g.username, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
 
-	apiURL := fmt.Sprintf("https://api.github.com/search/issues?q=%s&per_page=100",
-		url.QueryEscape(query))
-
-	body, err := g.client.Get(apiURL, nil)
+	reviewedPRs, total, err := g.searchIssuePages(io.Discard, query, false)
 	if err != nil {
 		return stats, err
 	}
-
-	var response SearchResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return stats, common.WrapError(err, "failed to parse review search response")
-	}
+	g.warnTruncated(query, total, len(reviewedPRs))
 
 	// For each PR, get detailed review information
-	for _, pr := range response.Items {
-		reviewsURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d/reviews",
+	for _, pr := range reviewedPRs {
+		if g.isIgnored(pr) || g.isBotPR(pr) {
+			continue
+		}
+		reviewsURL := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews", g.apiURL,
 			repoFullName, pr.Number)
 
 		reviewBody, err := g.client.Get(reviewsURL, nil)
 		if err != nil {
-			fmt.Fprintf(writer, "Warning: Failed to get reviews for PR #%d: %v\n", pr.Number, err)
+			g.warnings.Add("reviews", fmt.Sprintf("%s#%d", repoFullName, pr.Number), err)
 			continue
 		}
 
 		var reviews []Review
 		if err := json.Unmarshal(reviewBody, &reviews); err != nil {
-			fmt.Fprintf(writer, "Warning: Failed to parse reviews for PR #%d: %v\n", pr.Number, err)
+			g.warnings.Add("reviews", fmt.Sprintf("%s#%d", repoFullName, pr.Number), err)
 			continue
 		}
 
 		// Count reviews by this user within date range
+		reviewed := false
 		for _, review := range reviews {
 			if review.User.Login == g.username &&
 				review.SubmittedAt.After(startDate.Add(-24*time.Hour)) &&
 				review.SubmittedAt.Before(endDate.Add(24*time.Hour)) {
 				stats.ReviewsGiven++
+				reviewed = true
 
 				switch review.State {
 				case "APPROVED":
@@ -517,7 +1027,240 @@ func (g *GitHubAnalyzer) getReviewStatsForRepo(writer io.Writer, repoFullName st
 				}
 			}
 		}
+		if !reviewed {
+			continue
+		}
+
+		// Comments on changed lines belong to reviews but are listed separately
+		comments, err := g.reviewComments(repoFullName, pr.Number)
+		if err != nil {
+			g.warnings.Add("review comments", fmt.Sprintf("%s#%d", repoFullName, pr.Number), err)
+			continue
+		}
+		written, threads := g.analyzeReviewComments(pr, repoFullName, comments, startDate, endDate)
+		stats.ReviewCommentsWritten += written
+		stats.threads = append(stats.threads, threads...)
 	}
 
 	return stats, nil
 }
+
+// isDependencyUpdatePR reports whether a bot PR is an automated dependency update
+func (g *GitHubAnalyzer) isDependencyUpdatePR(pr PullRequest) bool {
+	login := strings.ToLower(pr.User.Login)
+	return strings.HasPrefix(login, "dependabot") || strings.HasPrefix(login, "renovate")
+}
+
+// dependencyUpdatePRs returns the bot PRs that are automated dependency updates
+func (g *GitHubAnalyzer) dependencyUpdatePRs(botPRs []PullRequest) []PullRequest {
+	var prs []PullRequest
+	for _, pr := range botPRs {
+		if g.isDependencyUpdatePR(pr) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// analyzeDependencyUpdates checks which dependency-update PRs the user merged or approved within the period.
+// Merges are read from the PR details fetchPRDetails loaded.
+func (g *GitHubAnalyzer) analyzeDependencyUpdates(writer io.Writer, dependencyPRs []PullRequest, startDate, endDate time.Time) *DependencyUpdateStats {
+	stats := &DependencyUpdateStats{ByRepo: make(map[string]int)}
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(startDate) && t.Before(endDate.AddDate(0, 0, 1))
+	}
+
+	for _, pr := range dependencyPRs {
+		repoFullName := g.extractRepoFromURL(pr.RepositoryURL)
+
+		merged := false
+		if detail, exists := g.prDetails[pr.URL]; exists && detail.MergedBy != nil && detail.MergedAt != nil {
+			merged = strings.EqualFold(detail.MergedBy.Login, g.username) && inPeriod(*detail.MergedAt)
+		}
+
+		approved := false
+		reviewBody, err := g.client.Get(fmt.Sprintf("%s/repos/%s/pulls/%d/reviews", g.apiURL, repoFullName, pr.Number), nil)
+		if err != nil {
+			g.warnings.Add("reviews", fmt.Sprintf("%s#%d", repoFullName, pr.Number), err)
+		} else {
+			var reviews []Review
+			if err := json.Unmarshal(reviewBody, &reviews); err == nil {
+				for _, review := range reviews {
+					if strings.EqualFold(review.User.Login, g.username) && review.State == "APPROVED" && inPeriod(review.SubmittedAt) {
+						approved = true
+						break
+					}
+				}
+			}
+		}
+
+		if merged {
+			stats.Merged = append(stats.Merged, pr)
+		}
+		if approved {
+			stats.Approved = append(stats.Approved, pr)
+		}
+		if merged || approved {
+			stats.Handled = append(stats.Handled, pr)
+			stats.ByRepo[repoFullName]++
+		}
+	}
+
+	return stats
+}
+
+// printDependencyUpdates prints the dependency maintenance section
+func (g *GitHubAnalyzer) printDependencyUpdates(writer io.Writer, stats *DependencyUpdateStats) {
+	fmt.Fprintf(writer, "\nDependency updates handled (%d):\n", len(stats.Handled))
+	if len(stats.Handled) == 0 {
+		fmt.Fprintln(writer, "- No dependency-update PRs merged or approved")
+		return
+	}
+	fmt.Fprintf(writer, "- Merged: %d\n", len(stats.Merged))
+	fmt.Fprintf(writer, "- Approved: %d\n", len(stats.Approved))
+
+	var repos []string
+	for repo := range stats.ByRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	fmt.Fprintln(writer, "\nDependency updates per repository:")
+	for _, repo := range repos {
+		fmt.Fprintf(writer, "- %s: %d\n", repo, stats.ByRepo[repo])
+	}
+
+	fmt.Fprintln(writer)
+	for _, pr := range stats.Handled {
+		fmt.Fprintf(writer, "- %s: %s\n", pr.CreatedAt.Format("2006-01-02 15:04"), pr.Title)
+		fmt.Fprintf(writer, "  URL: %s\n", pr.URL)
+	}
+}
+
+// getPRFiles lists the files changed by a PR with their line counts.
+// Results are kept per PR so that monorepo attribution and file-type stats share one fetch.
+func (g *GitHubAnalyzer) getPRFiles(repoFullName string, number int) ([]PullRequestFile, error) {
+	key := fmt.Sprintf("%s#%d", strings.ToLower(repoFullName), number)
+	if files, exists := g.prFiles[key]; exists {
+		return files, nil
+	}
+
+	var files []PullRequestFile
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("%s/repos/%s/pulls/%d/files?per_page=100&page=%d", g.apiURL, repoFullName, number, page)
+		body, err := g.client.Get(apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []PullRequestFile
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, common.WrapError(err, "failed to parse PR files response")
+		}
+		files = append(files, items...)
+		if len(items) < 100 {
+			break
+		}
+	}
+
+	if g.prFiles == nil {
+		g.prFiles = make(map[string][]PullRequestFile)
+	}
+	g.prFiles[key] = files
+	return files, nil
+}
+
+// getChangedFiles lists the paths of files changed by a PR
+func (g *GitHubAnalyzer) getChangedFiles(repoFullName string, number int) ([]string, error) {
+	files, err := g.getPRFiles(repoFullName, number)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Filename)
+	}
+	return paths, nil
+}
+
+// attributeMonorepoPRs maps PRs in configured monorepos to sub-projects.
+// Returns counts per repository and project (author/involves).
+func (g *GitHubAnalyzer) attributeMonorepoPRs(writer io.Writer, authoredPRs, involvedPRs []PullRequest) map[string]map[string]struct{ authored, involved int } {
+	stats := make(map[string]map[string]struct{ authored, involved int })
+	g.prProjects = make(map[string][]string)
+	if g.monorepos == nil || len(g.monorepos.Repositories) == 0 {
+		return stats
+	}
+
+	projectsFor := func(pr PullRequest) (string, []string) {
+		repoFullName := g.extractRepoFromURL(pr.RepositoryURL)
+		if !g.monorepos.IsMonorepo(repoFullName) {
+			return repoFullName, nil
+		}
+		if projects, exists := g.prProjects[pr.URL]; exists {
+			return repoFullName, projects
+		}
+		files, err := g.getChangedFiles(repoFullName, pr.Number)
+		if err != nil {
+			g.warnings.Add("changed files", fmt.Sprintf("%s#%d", repoFullName, pr.Number), err)
+			return repoFullName, nil
+		}
+		projects := g.monorepos.ProjectsForFiles(repoFullName, files)
+		g.prProjects[pr.URL] = projects
+		return repoFullName, projects
+	}
+
+	fmt.Fprintln(writer, "Attributing monorepo PRs to sub-projects...")
+	for _, pr := range authoredPRs {
+		repoFullName, projects := projectsFor(pr)
+		for _, project := range projects {
+			if stats[repoFullName] == nil {
+				stats[repoFullName] = make(map[string]struct{ authored, involved int })
+			}
+			stat := stats[repoFullName][project]
+			stat.authored++
+			stats[repoFullName][project] = stat
+		}
+	}
+	for _, pr := range involvedPRs {
+		repoFullName, projects := projectsFor(pr)
+		for _, project := range projects {
+			if stats[repoFullName] == nil {
+				stats[repoFullName] = make(map[string]struct{ authored, involved int })
+			}
+			stat := stats[repoFullName][project]
+			stat.involved++
+			stats[repoFullName][project] = stat
+		}
+	}
+
+	return stats
+}
+
+// printMonorepoStats prints PR counts per monorepo sub-project
+func (g *GitHubAnalyzer) printMonorepoStats(writer io.Writer, stats map[string]map[string]struct{ authored, involved int }) {
+	if len(stats) == 0 {
+		return
+	}
+
+	var repos []string
+	for repo := range stats {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	fmt.Fprintln(writer, "\nPR count per monorepo project (author/involves):")
+	for _, repo := range repos {
+		var projects []string
+		for project := range stats[repo] {
+			projects = append(projects, project)
+		}
+		sort.Strings(projects)
+
+		fmt.Fprintf(writer, "- %s\n", repo)
+		for _, project := range projects {
+			stat := stats[repo][project]
+			fmt.Fprintf(writer, "  - %s: %d (%d)\n", project, stat.authored, stat.involved)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package github
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+// archiveFilePattern matches the JSON files of a GitHub account export or migration archive, e.g. pull_requests_000001.json
+var archiveFilePattern = regexp.MustCompile(`(?:^|/)(pull_requests|pull_request_reviews|pull_request_review_comments|issues)_\d+\.json$`)
+
+// archiveReviewStates maps the numeric review states of migration archives to the API names
+var archiveReviewStates = map[int]string{
+	1:  "COMMENTED",
+	30: "CHANGES_REQUESTED",
+	40: "APPROVED",
+}
+
+// ArchiveAnalyzer reads a GitHub account data export or organization migration archive (GITHUB_ARCHIVE_PATH)
+// so that periods the search API no longer returns, or organizations that were deleted, can be analyzed offline
+type ArchiveAnalyzer struct {
+	path       string // extracted directory or .tar.gz
+	username   string
+	ignoreList *config.IgnoreList
+}
+
+// ArchivePullRequest is a pull request from the archive
+type ArchivePullRequest struct {
+	URL        string     `json:"url"`
+	User       string     `json:"user"`       // profile URL of the author
+	Repository string     `json:"repository"` // repository URL
+	Title      string     `json:"title"`
+	CreatedAt  time.Time  `json:"created_at"`
+	MergedAt   *time.Time `json:"merged_at"`
+	ClosedAt   *time.Time `json:"closed_at"`
+}
+
+// ArchiveReview is a pull request review from the archive
+type ArchiveReview struct {
+	URL         string          `json:"url"`
+	PullRequest string          `json:"pull_request"`
+	User        string          `json:"user"`
+	RawState    json.RawMessage `json:"state"` // a number in migration archives, a name in newer exports
+	CreatedAt   time.Time       `json:"created_at"`
+	SubmittedAt *time.Time      `json:"submitted_at"`
+}
+
+// archiveComment is a review comment or issue from the archive; only the fields used here
+type archiveComment struct {
+	URL        string     `json:"url"`
+	User       string     `json:"user"`
+	Repository string     `json:"repository"`
+	Title      string     `json:"title"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ClosedAt   *time.Time `json:"closed_at"`
+}
+
+// State returns the review state as APPROVED, CHANGES_REQUESTED, or COMMENTED (empty when unknown)
+func (r ArchiveReview) State() string {
+	var name string
+	if err := json.Unmarshal(r.RawState, &name); err == nil {
+		return strings.ToUpper(name)
+	}
+	var code int
+	if err := json.Unmarshal(r.RawState, &code); err == nil {
+		return archiveReviewStates[code]
+	}
+	return ""
+}
+
+// Time returns when the review was submitted
+func (r ArchiveReview) Time() time.Time {
+	if r.SubmittedAt != nil {
+		return *r.SubmittedAt
+	}
+	return r.CreatedAt
+}
+
+// archiveContents holds the records read from an archive
+type archiveContents struct {
+	pullRequests   []ArchivePullRequest
+	reviews        []ArchiveReview
+	reviewComments []archiveComment
+	issues         []archiveComment
+}
+
+// NewArchiveAnalyzer creates a GitHub archive analyzer
+func NewArchiveAnalyzer() *ArchiveAnalyzer {
+	return &ArchiveAnalyzer{
+		path:     os.Getenv("GITHUB_ARCHIVE_PATH"),
+		username: os.Getenv("GITHUB_USERNAME"),
+	}
+}
+
+// GetName returns the analyzer name
+func (a *ArchiveAnalyzer) GetName() string {
+	return "GitHub (archive)"
+}
+
+// ValidateConfig validates the required configuration
+func (a *ArchiveAnalyzer) ValidateConfig(writer io.Writer) error {
+	if a.path == "" {
+		return common.NewError("GITHUB_ARCHIVE_PATH environment variable is required")
+	}
+	if a.username == "" {
+		return common.NewError("GITHUB_USERNAME environment variable is required")
+	}
+	if _, err := os.Stat(a.path); err != nil {
+		return common.WrapError(err, "GITHUB_ARCHIVE_PATH is not readable")
+	}
+	fmt.Fprintf(writer, "✓ GitHub archive: %s\n", a.path)
+	return nil
+}
+
+// Analyze reports PRs authored and merged, reviews given, review comments, and issues opened from the archive
+func (a *ArchiveAnalyzer) Analyze(cfg *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := a.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	ignoreList, err := config.LoadIgnoreList("")
+	if err != nil {
+		return nil, err
+	}
+	a.ignoreList = ignoreList
+
+	fmt.Fprintf(writer, "Reading GitHub archive %s...\n", a.path)
+	contents, err := readArchive(a.path)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(writer, "Archive: %d PRs, %d reviews, %d review comments, %d issues\n",
+		len(contents.pullRequests), len(contents.reviews), len(contents.reviewComments), len(contents.issues))
+
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(cfg.StartDate) && t.Before(cfg.EndDate.AddDate(0, 0, 1))
+	}
+
+	prsByURL := make(map[string]ArchivePullRequest)
+	var authored, merged []ArchivePullRequest
+	for _, pr := range contents.pullRequests {
+		prsByURL[pr.URL] = pr
+		if !a.isUser(pr.User) || a.ignoreList.Contains(pr.URL) {
+			continue
+		}
+		if inPeriod(pr.CreatedAt) {
+			authored = append(authored, pr)
+		}
+		if pr.MergedAt != nil && inPeriod(*pr.MergedAt) {
+			merged = append(merged, pr)
+		}
+	}
+
+	var reviews []ArchiveReview
+	var reviewed []ArchivePullRequest
+	seenReviewed := make(map[string]bool)
+	approvals, changesRequested := 0, 0
+	for _, review := range contents.reviews {
+		if !a.isUser(review.User) || !inPeriod(review.Time()) || a.ignoreList.Contains(review.PullRequest) {
+			continue
+		}
+		pr, known := prsByURL[review.PullRequest]
+		if known && a.isUser(pr.User) {
+			continue // replies on own PRs are not reviews
+		}
+		reviews = append(reviews, review)
+		switch review.State() {
+		case "APPROVED":
+			approvals++
+		case "CHANGES_REQUESTED":
+			changesRequested++
+		}
+		if !seenReviewed[review.PullRequest] {
+			seenReviewed[review.PullRequest] = true
+			if !known {
+				pr = ArchivePullRequest{URL: review.PullRequest, Title: "(not in archive)", CreatedAt: review.Time()}
+			}
+			reviewed = append(reviewed, pr)
+		}
+	}
+
+	reviewComments := 0
+	for _, comment := range contents.reviewComments {
+		if a.isUser(comment.User) && inPeriod(comment.CreatedAt) {
+			reviewComments++
+		}
+	}
+	var issues []archiveComment
+	for _, issue := range contents.issues {
+		if a.isUser(issue.User) && inPeriod(issue.CreatedAt) && !a.ignoreList.Contains(issue.URL) {
+			issues = append(issues, issue)
+		}
+	}
+
+	sortByCreation(authored)
+	sortByCreation(merged)
+	sortByCreation(reviewed)
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].CreatedAt.Before(issues[j].CreatedAt) })
+
+	repos := make(map[string]bool)
+	for _, pr := range append(append([]ArchivePullRequest{}, authored...), reviewed...) {
+		if name := archiveRepoName(pr.URL); name != "" {
+			repos[name] = true
+		}
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: a.GetName(),
+		StartDate:    cfg.StartDate,
+		EndDate:      cfg.EndDate,
+		Metrics: []common.Metric{
+			{ID: "github.prs_authored", Label: "Total PRs (author)", Value: len(authored)},
+			{ID: "github.prs_merged", Label: "Authored PRs merged", Value: len(merged)},
+			{ID: "github.reviews_given", Label: "Reviews given", Value: len(reviews)},
+			{ID: "github.approvals_given", Label: "Approvals given", Value: approvals},
+			{ID: "github.changes_requested", Label: "Changes requested", Value: changesRequested},
+			{ID: "github.review_comments_written", Label: "Review comments written", Value: reviewComments},
+			{ID: "github.issues_opened", Label: "Issues opened", Value: len(issues)},
+			{ID: "github.active_repositories", Label: "Active repositories", Value: len(repos), Snapshot: true},
+		},
+		Details: map[string]interface{}{
+			"authored_prs": authored,
+			"merged_prs":   merged,
+			"reviewed_prs": reviewed,
+			"reviews":      reviews,
+			"issues":       issues,
+		},
+		Activities: append(a.prActivities(authored, "pr_authored"), a.prActivities(reviewed, "pr_involved")...),
+		CSVTables:  archiveCSVTables(authored, reviewed, issues),
+	}
+	result.Explain("github.prs_authored", a.prActivities(authored, "pr_authored"))
+	result.Explain("github.prs_merged", a.prActivities(merged, "pr_authored"))
+	result.Explain("github.reviews_given", a.prActivities(reviewed, "pr_involved"))
+
+	a.printResults(writer, result, authored, reviewed, issues)
+	return result, nil
+}
+
+// isUser reports whether a profile URL (https://github.com/login) or login is the analyzed user
+func (a *ArchiveAnalyzer) isUser(user string) bool {
+	login := user[strings.LastIndex(user, "/")+1:]
+	return login != "" && strings.EqualFold(login, a.username)
+}
+
+// archiveRepoName returns owner/repo of a PR or issue URL like https://github.com/owner/repo/pull/1
+func archiveRepoName(itemURL string) string {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimPrefix(itemURL, "https://"), "http://"), "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1] + "/" + parts[2]
+}
+
+func sortByCreation(prs []ArchivePullRequest) {
+	sort.SliceStable(prs, func(i, j int) bool { return prs[i].CreatedAt.Before(prs[j].CreatedAt) })
+}
+
+// prActivities converts archived PRs into dated activities
+func (a *ArchiveAnalyzer) prActivities(prs []ArchivePullRequest, kind string) []common.Activity {
+	var activities []common.Activity
+	for _, pr := range prs {
+		activities = append(activities, common.Activity{
+			Source: a.GetName(),
+			Kind:   kind,
+			ID:     pr.URL,
+			Title:  strings.TrimSpace(fmt.Sprintf("%s %s", archiveRepoName(pr.URL), pr.Title)),
+			URL:    pr.URL,
+			Time:   pr.CreatedAt,
+		})
+	}
+	return activities
+}
+
+// archiveItemCSVRow is one PR or issue in github-archive-items.csv
+type archiveItemCSVRow struct {
+	Relation   string    `csv:"relation"` // authored, reviewed, or issue
+	Repository string    `csv:"repository"`
+	Title      string    `csv:"title"`
+	CreatedAt  time.Time `csv:"created_at"`
+	URL        string    `csv:"url"`
+}
+
+// archiveCSVTables lists the authored PRs, the reviewed PRs, then the opened issues
+func archiveCSVTables(authored, reviewed []ArchivePullRequest, issues []archiveComment) []common.CSVTable {
+	var rows []archiveItemCSVRow
+	add := func(prs []ArchivePullRequest, relation string) {
+		for _, pr := range prs {
+			rows = append(rows, archiveItemCSVRow{Relation: relation, Repository: archiveRepoName(pr.URL), Title: pr.Title, CreatedAt: pr.CreatedAt, URL: pr.URL})
+		}
+	}
+	add(authored, "authored")
+	add(reviewed, "reviewed")
+	for _, issue := range issues {
+		rows = append(rows, archiveItemCSVRow{Relation: "issue", Repository: archiveRepoName(issue.URL), Title: issue.Title, CreatedAt: issue.CreatedAt, URL: issue.URL})
+	}
+	return []common.CSVTable{common.NewCSVTable("items", rows)}
+}
+
+func (a *ArchiveAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, authored, reviewed []ArchivePullRequest, issues []archiveComment) {
+	printPRs := func(title string, prs []ArchivePullRequest) {
+		fmt.Fprintf(writer, "\n%s (%d):\n", title, len(prs))
+		for _, pr := range prs {
+			state := "open"
+			if pr.MergedAt != nil {
+				state = "merged"
+			} else if pr.ClosedAt != nil {
+				state = "closed"
+			}
+			fmt.Fprintf(writer, "- %s: %s %s [%s]\n", pr.CreatedAt.Local().Format("2006-01-02"), archiveRepoName(pr.URL), pr.Title, state)
+			fmt.Fprintf(writer, "  URL: %s\n", pr.URL)
+		}
+	}
+	printPRs("Pull Requests you authored", authored)
+	printPRs("Pull Requests you reviewed", reviewed)
+
+	fmt.Fprintf(writer, "\nIssues you opened (%d):\n", len(issues))
+	for _, issue := range issues {
+		fmt.Fprintf(writer, "- %s: %s %s\n", issue.CreatedAt.Local().Format("2006-01-02"), archiveRepoName(issue.URL), issue.Title)
+		fmt.Fprintf(writer, "  URL: %s\n", issue.URL)
+	}
+
+	result.PrintSummary(writer)
+}
+
+// readArchive reads the PR, review, review comment, and issue files of an extracted archive directory or a .tar.gz
+func readArchive(path string) (*archiveContents, error) {
+	contents := &archiveContents{}
+	add := func(name string, data []byte) error {
+		match := archiveFilePattern.FindStringSubmatch(filepath.ToSlash(name))
+		if match == nil {
+			return nil
+		}
+		var err error
+		switch match[1] {
+		case "pull_requests":
+			var items []ArchivePullRequest
+			err = json.Unmarshal(data, &items)
+			contents.pullRequests = append(contents.pullRequests, items...)
+		case "pull_request_reviews":
+			var items []ArchiveReview
+			err = json.Unmarshal(data, &items)
+			contents.reviews = append(contents.reviews, items...)
+		case "pull_request_review_comments":
+			var items []archiveComment
+			err = json.Unmarshal(data, &items)
+			contents.reviewComments = append(contents.reviewComments, items...)
+		case "issues":
+			var items []archiveComment
+			err = json.Unmarshal(data, &items)
+			contents.issues = append(contents.issues, items...)
+		}
+		if err != nil {
+			return common.WrapError(err, "failed to parse %s", name)
+		}
+		return nil
+	}
+
+	if strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz") {
+		return contents, readTarGz(path, add)
+	}
+	err := filepath.WalkDir(path, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if !archiveFilePattern.MatchString(filepath.ToSlash(name)) {
+			return nil
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		return add(name, data)
+	})
+	if err != nil {
+		return nil, common.WrapError(err, "failed to read %s", path)
+	}
+	return contents, nil
+}
+
+// readTarGz calls add for every regular file in a gzipped tar archive
+func readTarGz(path string, add func(name string, data []byte) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return common.WrapError(err, "failed to open %s", path)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return common.WrapError(err, "failed to read %s", path)
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return common.WrapError(err, "failed to read %s", path)
+		}
+		if header.Typeflag != tar.TypeReg || !archiveFilePattern.MatchString(header.Name) {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return common.WrapError(err, "failed to read %s in %s", header.Name, path)
+		}
+		if err := add(header.Name, data); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// Commit is a commit authored by the user, from the commit search API.
+// Additions and deletions are filled in from the commit API.
+type Commit struct {
+	SHA    string `json:"sha"`
+	URL    string `json:"html_url"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+// Title returns the first line of the commit message
+func (c Commit) Title() string {
+	title, _, _ := strings.Cut(c.Commit.Message, "\n")
+	return title
+}
+
+// CommitRepoStat totals the commits in one repository
+type CommitRepoStat struct {
+	Repository string `json:"repository"`
+	Commits    int    `json:"commits"`
+	Additions  int    `json:"additions"`
+	Deletions  int    `json:"deletions"`
+}
+
+// CommitStats summarizes the commits authored in the period
+type CommitStats struct {
+	Commits   []Commit         `json:"commits"`
+	Additions int              `json:"additions"`
+	Deletions int              `json:"deletions"`
+	ByRepo    []CommitRepoStat `json:"by_repo"`
+}
+
+// commitSearchResponse is the commit search API response
+type commitSearchResponse struct {
+	TotalCount int      `json:"total_count"`
+	Items      []Commit `json:"items"`
+}
+
+// commitDetail holds the line counts from the commit API
+type commitDetail struct {
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+}
+
+// analyzeCommits searches non-merge commits authored in the period and totals their changed lines per repository.
+// The commit search only covers default branches, so commits that were squashed into a PR are counted by the PR instead.
+func (g *GitHubAnalyzer) analyzeCommits(writer io.Writer, startDate, endDate time.Time) *CommitStats {
+	stats := &CommitStats{}
+	commits, err := g.searchCommits(writer, fmt.Sprintf("author:%s merge:false", g.username), dateRange{startDate, endDate})
+	if err != nil {
+		g.warnings.Add("commits", "", err)
+		return stats
+	}
+
+	byRepo := make(map[string]*CommitRepoStat)
+	for _, commit := range commits {
+		if g.ignoreList.Contains(commit.URL, commit.SHA) || !g.repoFilter.Allows(commit.Repository.FullName) {
+			continue
+		}
+		repoFullName := commit.Repository.FullName
+		body, err := g.client.Get(fmt.Sprintf("%s/repos/%s/commits/%s", g.apiURL, repoFullName, commit.SHA), nil)
+		if err != nil {
+			g.warnings.Add("commit stats", fmt.Sprintf("%s@%.7s", repoFullName, commit.SHA), err)
+		} else {
+			var detail commitDetail
+			if err := json.Unmarshal(body, &detail); err == nil {
+				commit.Additions = detail.Stats.Additions
+				commit.Deletions = detail.Stats.Deletions
+			}
+		}
+
+		stat, exists := byRepo[repoFullName]
+		if !exists {
+			stat = &CommitRepoStat{Repository: repoFullName}
+			byRepo[repoFullName] = stat
+		}
+		stat.Commits++
+		stat.Additions += commit.Additions
+		stat.Deletions += commit.Deletions
+		stats.Additions += commit.Additions
+		stats.Deletions += commit.Deletions
+		stats.Commits = append(stats.Commits, commit)
+	}
+
+	for _, stat := range byRepo {
+		stats.ByRepo = append(stats.ByRepo, *stat)
+	}
+	sort.Slice(stats.ByRepo, func(i, j int) bool {
+		if stats.ByRepo[i].Commits != stats.ByRepo[j].Commits {
+			return stats.ByRepo[i].Commits > stats.ByRepo[j].Commits
+		}
+		return stats.ByRepo[i].Repository < stats.ByRepo[j].Repository
+	})
+	sort.SliceStable(stats.Commits, func(i, j int) bool {
+		return stats.Commits[i].Commit.Author.Date.Before(stats.Commits[j].Commit.Author.Date)
+	})
+	return stats
+}
+
+// searchCommits searches commits authored in the period, following pagination. Like searchPRs, a query matching
+// more than the search API returns is split into months (or halves of a month).
+func (g *GitHubAnalyzer) searchCommits(writer io.Writer, query string, period dateRange) ([]Commit, error) {
+	parts := period.split()
+	fullQuery := g.repoFilter.Qualify(fmt.Sprintf("%s author-date:%s", query, period))
+
+	var commits []Commit
+	fmt.Fprintf(writer, "Searching GitHub commits with query: %s\n", fullQuery)
+	total, _, err := g.searchPages(io.Discard, "commits", fullQuery, len(parts) > 0, func(body []byte) (int, int, error) {
+		var response commitSearchResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return 0, 0, common.WrapError(err, "failed to parse GitHub commit search response")
+		}
+		commits = append(commits, response.Items...)
+		return response.TotalCount, len(response.Items), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if total <= searchResultLimit || len(parts) == 0 {
+		g.warnTruncated(fullQuery, total, len(commits))
+		return commits, nil
+	}
+
+	fmt.Fprintf(writer, "%d results exceed the search limit of %d; splitting %s into %d parts\n", total, searchResultLimit, period, len(parts))
+	commits = nil
+	for _, part := range parts {
+		partCommits, err := g.searchCommits(writer, query, part)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, partCommits...)
+	}
+	return commits, nil
+}
+
+// commitActivities converts commits into dated activities sized by changed lines
+func (g *GitHubAnalyzer) commitActivities(commits []Commit) []common.Activity {
+	var activities []common.Activity
+	for _, commit := range commits {
+		activities = append(activities, common.Activity{
+			Source: g.GetName(),
+			Kind:   "commit",
+			ID:     commit.URL,
+			Title:  fmt.Sprintf("%s@%.7s %s", commit.Repository.FullName, commit.SHA, commit.Title()),
+			URL:    commit.URL,
+			Time:   commit.Commit.Author.Date,
+			Size:   commit.Additions + commit.Deletions,
+		})
+	}
+	return activities
+}
+
+// printCommits prints commit totals and commit counts per repository
+func (g *GitHubAnalyzer) printCommits(writer io.Writer, stats *CommitStats) {
+	fmt.Fprintf(writer, "\nCommits authored (%d, +%d/-%d lines):\n", len(stats.Commits), stats.Additions, stats.Deletions)
+	if len(stats.Commits) == 0 {
+		fmt.Fprintln(writer, "- No commits found on default branches")
+		return
+	}
+	for _, stat := range stats.ByRepo {
+		fmt.Fprintf(writer, "- %s: %d commits (+%d/-%d)\n", stat.Repository, stat.Commits, stat.Additions, stat.Deletions)
+	}
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// prCSVRow is one PR in github-prs.csv
+type prCSVRow struct {
+	Relation   string    `csv:"relation"`
+	Repository string    `csv:"repository"`
+	Number     int       `csv:"number"`
+	Title      string    `csv:"title"`
+	Author     string    `csv:"author"`
+	CreatedAt  time.Time `csv:"created_at"`
+	Labels     []string  `csv:"labels"`
+	Size       int       `csv:"changed_lines"`
+	SizeLabel  string    `csv:"size"`          // XS to XL; authored PRs only
+	Files      int       `csv:"changed_files"` // authored PRs only
+	State      string    `csv:"state"`         // merged, closed, or open; authored PRs only
+	MergedAt   time.Time `csv:"merged_at"`
+	URL        string    `csv:"url"`
+}
+
+// commitCSVRow is one commit in github-commits.csv
+type commitCSVRow struct {
+	Repository string    `csv:"repository"`
+	SHA        string    `csv:"sha"`
+	Title      string    `csv:"title"`
+	AuthoredAt time.Time `csv:"authored_at"`
+	Additions  int       `csv:"additions"`
+	Deletions  int       `csv:"deletions"`
+	URL        string    `csv:"url"`
+}
+
+// csvTables lists authored PRs, then PRs you were otherwise involved in, and authored commits
+func (g *GitHubAnalyzer) csvTables(authoredPRs, involvedPRs []PullRequest, commits []Commit) []common.CSVTable {
+	var rows []prCSVRow
+	seen := make(map[string]bool)
+	add := func(pr PullRequest, relation string) {
+		if seen[pr.URL] {
+			return
+		}
+		seen[pr.URL] = true
+		var labels []string
+		for _, label := range pr.Labels {
+			labels = append(labels, label.Name)
+		}
+		row := prCSVRow{
+			Relation:   relation,
+			Repository: g.extractRepoFromURL(pr.RepositoryURL),
+			Number:     pr.Number,
+			Title:      pr.Title,
+			Author:     pr.User.Login,
+			CreatedAt:  pr.CreatedAt,
+			Labels:     labels,
+			Size:       g.prSizes[pr.URL],
+			URL:        pr.URL,
+		}
+		if detail, exists := g.prDetails[pr.URL]; exists {
+			row.SizeLabel = prSizeLabel(detail.Additions + detail.Deletions)
+			row.Files = detail.ChangedFiles
+			row.State = detail.State
+			if detail.MergedAt != nil {
+				row.State = "merged"
+				row.MergedAt = *detail.MergedAt
+			}
+		}
+		rows = append(rows, row)
+	}
+	for _, pr := range authoredPRs {
+		add(pr, "authored")
+	}
+	for _, pr := range involvedPRs {
+		add(pr, "involved")
+	}
+
+	commitRows := []commitCSVRow{}
+	for _, commit := range commits {
+		commitRows = append(commitRows, commitCSVRow{
+			Repository: commit.Repository.FullName,
+			SHA:        commit.SHA,
+			Title:      commit.Title(),
+			AuthoredAt: commit.Commit.Author.Date,
+			Additions:  commit.Additions,
+			Deletions:  commit.Deletions,
+			URL:        commit.URL,
+		})
+	}
+	return []common.CSVTable{common.NewCSVTable("prs", rows), common.NewCSVTable("commits", commitRows)}
+}
@@ -0,0 +1,174 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// cycleTimeBuckets are the upper bounds of the time-to-merge distribution; the last bucket is open-ended
+var cycleTimeBuckets = []struct {
+	Label string
+	Max   time.Duration
+}{
+	{"< 4h", 4 * time.Hour},
+	{"4h-1d", 24 * time.Hour},
+	{"1-3d", 3 * 24 * time.Hour},
+	{"3-7d", 7 * 24 * time.Hour},
+	{"> 7d", 0},
+}
+
+// RepoCycleTime is the merge outcome and time-to-merge distribution of authored PRs in one repository
+type RepoCycleTime struct {
+	Repository     string        `json:"repository"`
+	Merged         int           `json:"merged"`
+	ClosedUnmerged int           `json:"closed_unmerged"`
+	Open           int           `json:"open"`
+	Median         time.Duration `json:"median"`
+	Distribution   []int         `json:"distribution"` // merged PRs per cycleTimeBuckets entry
+}
+
+// CycleTimeStats summarizes how authored PRs ended and how long merged ones took from open to merge
+type CycleTimeStats struct {
+	Merged         []PullRequest   `json:"merged"`
+	ClosedUnmerged []PullRequest   `json:"closed_unmerged"`
+	Open           []PullRequest   `json:"open"`
+	Median         time.Duration   `json:"median"`
+	Mean           time.Duration   `json:"mean"`
+	Distribution   []int           `json:"distribution"` // merged PRs per cycleTimeBuckets entry
+	ByRepo         []RepoCycleTime `json:"by_repo"`
+}
+
+// MergeRate returns the percentage of closed PRs that were merged, rounded to one decimal (0 when none were closed)
+func (s *CycleTimeStats) MergeRate() float64 {
+	closed := len(s.Merged) + len(s.ClosedUnmerged)
+	if closed == 0 {
+		return 0
+	}
+	return math.Round(float64(len(s.Merged))/float64(closed)*1000) / 10
+}
+
+// analyzeCycleTime classifies authored PRs as merged, closed unmerged, or open from the PR details,
+// and measures the time from creation to merge. PRs whose details could not be fetched are left out.
+func (g *GitHubAnalyzer) analyzeCycleTime(prs []PullRequest) *CycleTimeStats {
+	stats := &CycleTimeStats{Distribution: make([]int, len(cycleTimeBuckets))}
+	var leadTimes []time.Duration
+	byRepo := make(map[string]*RepoCycleTime)
+	repoLeadTimes := make(map[string][]time.Duration)
+
+	for _, pr := range prs {
+		detail, exists := g.prDetails[pr.URL]
+		if !exists {
+			continue
+		}
+		repoFullName := g.extractRepoFromURL(pr.RepositoryURL)
+		repo, exists := byRepo[repoFullName]
+		if !exists {
+			repo = &RepoCycleTime{Repository: repoFullName, Distribution: make([]int, len(cycleTimeBuckets))}
+			byRepo[repoFullName] = repo
+		}
+
+		switch {
+		case detail.MergedAt != nil:
+			leadTime := detail.MergedAt.Sub(pr.CreatedAt)
+			bucket := cycleTimeBucket(leadTime)
+			stats.Merged = append(stats.Merged, pr)
+			stats.Distribution[bucket]++
+			leadTimes = append(leadTimes, leadTime)
+			repo.Merged++
+			repo.Distribution[bucket]++
+			repoLeadTimes[repoFullName] = append(repoLeadTimes[repoFullName], leadTime)
+		case detail.State == "closed":
+			stats.ClosedUnmerged = append(stats.ClosedUnmerged, pr)
+			repo.ClosedUnmerged++
+		default:
+			stats.Open = append(stats.Open, pr)
+			repo.Open++
+		}
+	}
+
+	stats.Median = medianDuration(leadTimes)
+	if len(leadTimes) > 0 {
+		var total time.Duration
+		for _, leadTime := range leadTimes {
+			total += leadTime
+		}
+		stats.Mean = (total / time.Duration(len(leadTimes))).Round(time.Minute)
+	}
+	for name, repo := range byRepo {
+		repo.Median = medianDuration(repoLeadTimes[name])
+		stats.ByRepo = append(stats.ByRepo, *repo)
+	}
+	sort.Slice(stats.ByRepo, func(i, j int) bool {
+		a, b := stats.ByRepo[i], stats.ByRepo[j]
+		if a.Merged+a.ClosedUnmerged+a.Open != b.Merged+b.ClosedUnmerged+b.Open {
+			return a.Merged+a.ClosedUnmerged+a.Open > b.Merged+b.ClosedUnmerged+b.Open
+		}
+		return a.Repository < b.Repository
+	})
+	return stats
+}
+
+// cycleTimeBucket returns the index of the distribution bucket of a time to merge
+func cycleTimeBucket(leadTime time.Duration) int {
+	for i, bucket := range cycleTimeBuckets {
+		if bucket.Max == 0 || leadTime < bucket.Max {
+			return i
+		}
+	}
+	return len(cycleTimeBuckets) - 1
+}
+
+// medianDuration returns the median rounded to the minute (0 for no values)
+func medianDuration(values []time.Duration) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return ((sorted[middle-1] + sorted[middle]) / 2).Round(time.Minute)
+	}
+	return sorted[middle].Round(time.Minute)
+}
+
+// printCycleTime prints merge outcomes, time to merge, and the distribution overall and per repository
+func (g *GitHubAnalyzer) printCycleTime(writer io.Writer, stats *CycleTimeStats) {
+	fmt.Fprintf(writer, "\nPR cycle time (authored, %d merged / %d closed unmerged / %d open):\n",
+		len(stats.Merged), len(stats.ClosedUnmerged), len(stats.Open))
+	if len(stats.Merged)+len(stats.ClosedUnmerged) == 0 {
+		fmt.Fprintln(writer, "- No authored PRs were merged or closed")
+		return
+	}
+	fmt.Fprintf(writer, "- Merged: %.1f%% of closed PRs\n", stats.MergeRate())
+	if len(stats.Merged) > 0 {
+		fmt.Fprintf(writer, "- Time to merge: median %s, mean %s\n", common.FormatDuration(stats.Median), common.FormatDuration(stats.Mean))
+		fmt.Fprintf(writer, "- Distribution: %s\n", formatCycleTimeDistribution(stats.Distribution))
+	}
+
+	fmt.Fprintln(writer, "\nPR cycle time per repository:")
+	for _, repo := range stats.ByRepo {
+		line := fmt.Sprintf("- %s: %d merged, %d closed unmerged, %d open", repo.Repository, repo.Merged, repo.ClosedUnmerged, repo.Open)
+		if repo.Merged > 0 {
+			line += fmt.Sprintf("; median %s (%s)", common.FormatDuration(repo.Median), formatCycleTimeDistribution(repo.Distribution))
+		}
+		fmt.Fprintln(writer, line)
+	}
+}
+
+// formatCycleTimeDistribution lists the non-empty buckets, e.g. "< 4h: 2, 1-3d: 1"
+func formatCycleTimeDistribution(distribution []int) string {
+	var parts []string
+	for i, count := range distribution {
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", cycleTimeBuckets[i].Label, count))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,153 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"dev-stats/pkg/common"
+)
+
+// FileTypeStat counts lines changed in one language or file type
+type FileTypeStat struct {
+	Name      string `json:"name"`
+	Files     int    `json:"files"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// Lines returns the total number of changed lines
+func (s FileTypeStat) Lines() int {
+	return s.Additions + s.Deletions
+}
+
+// languageByExtension maps common file extensions to languages; other files are reported by extension
+var languageByExtension = map[string]string{
+	".go":     "Go",
+	".rs":     "Rust",
+	".py":     "Python",
+	".rb":     "Ruby",
+	".php":    "PHP",
+	".java":   "Java",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".scala":  "Scala",
+	".swift":  "Swift",
+	".m":      "Objective-C",
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".hpp":    "C++",
+	".cs":     "C#",
+	".js":     "JavaScript",
+	".jsx":    "JavaScript",
+	".mjs":    "JavaScript",
+	".cjs":    "JavaScript",
+	".ts":     "TypeScript",
+	".tsx":    "TypeScript",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+	".dart":   "Dart",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".sql":    "SQL",
+	".html":   "HTML",
+	".css":    "CSS",
+	".scss":   "CSS",
+	".sass":   "CSS",
+	".tf":     "Terraform",
+	".proto":  "Protocol Buffers",
+	".md":     "Markdown",
+	".yaml":   "YAML",
+	".yml":    "YAML",
+	".json":   "JSON",
+	".toml":   "TOML",
+	".xml":    "XML",
+}
+
+// languageByFilename maps extensionless or special file names to languages
+var languageByFilename = map[string]string{
+	"dockerfile": "Dockerfile",
+	"makefile":   "Makefile",
+	"go.mod":     "Go modules",
+	"go.sum":     "Go modules",
+}
+
+// fileType returns the language or file type of a path, e.g. "Go" or ".lock"
+func fileType(filename string) string {
+	base := strings.ToLower(path.Base(filename))
+	if language, exists := languageByFilename[base]; exists {
+		return language
+	}
+	ext := path.Ext(base)
+	if language, exists := languageByExtension[ext]; exists {
+		return language
+	}
+	if ext == "" {
+		return "Other"
+	}
+	return ext
+}
+
+// analyzeFileTypes totals lines changed per language/file type across authored PRs
+func (g *GitHubAnalyzer) analyzeFileTypes(writer io.Writer, authoredPRs []PullRequest) []FileTypeStat {
+	stats := make(map[string]*FileTypeStat)
+	for _, pr := range authoredPRs {
+		repoFullName := g.extractRepoFromURL(pr.RepositoryURL)
+		files, err := g.getPRFiles(repoFullName, pr.Number)
+		if err != nil {
+			g.warnings.Add("changed files", fmt.Sprintf("%s#%d", repoFullName, pr.Number), err)
+			continue
+		}
+		for _, file := range files {
+			name := fileType(file.Filename)
+			stat, exists := stats[name]
+			if !exists {
+				stat = &FileTypeStat{Name: name}
+				stats[name] = stat
+			}
+			stat.Files++
+			stat.Additions += file.Additions
+			stat.Deletions += file.Deletions
+		}
+	}
+
+	var result []FileTypeStat
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Lines() != result[j].Lines() {
+			return result[i].Lines() > result[j].Lines()
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// printFileTypes prints lines changed per language/file type with their share of all changed lines
+func (g *GitHubAnalyzer) printFileTypes(writer io.Writer, stats []FileTypeStat) {
+	fmt.Fprintln(writer, "\nLines changed per language/file type (authored PRs):")
+	if len(stats) == 0 {
+		fmt.Fprintln(writer, "- No changed files found")
+		return
+	}
+
+	total := 0
+	for _, stat := range stats {
+		total += stat.Lines()
+	}
+	for _, stat := range stats[:common.RankingLimit(len(stats))] {
+		share := 0.0
+		if total > 0 {
+			share = float64(stat.Lines()) * 100 / float64(total)
+		}
+		fmt.Fprintf(writer, "- %s: +%d/-%d (%.0f%%), %d files\n", stat.Name, stat.Additions, stat.Deletions, share, stat.Files)
+	}
+	common.PrintMoreEntries(writer, len(stats))
+}
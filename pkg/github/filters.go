@@ -0,0 +1,122 @@
+package github
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// maxSearchQueryLength is the longest query the search API accepts; longer filters are only applied after the search
+const maxSearchQueryLength = 256
+
+// repoFilter limits the analysis to allowed organizations/repositories and drops denied ones.
+// Entries are lowercase; "*" is a wildcard (e.g. "*/dotfiles", "example-org/*-mirror").
+type repoFilter struct {
+	includeOrgs  []string // GITHUB_INCLUDE_ORGS
+	includeRepos []string // GITHUB_INCLUDE_REPOS (owner/repo)
+	excludeOrgs  []string // GITHUB_EXCLUDE_ORGS
+	excludeRepos []string // GITHUB_EXCLUDE_REPOS (owner/repo)
+}
+
+// repoFilterFromEnv reads the comma-separated GITHUB_INCLUDE_* and GITHUB_EXCLUDE_* lists
+func repoFilterFromEnv() repoFilter {
+	return repoFilter{
+		includeOrgs:  patternsFromEnv("GITHUB_INCLUDE_ORGS"),
+		includeRepos: patternsFromEnv("GITHUB_INCLUDE_REPOS"),
+		excludeOrgs:  patternsFromEnv("GITHUB_EXCLUDE_ORGS"),
+		excludeRepos: patternsFromEnv("GITHUB_EXCLUDE_REPOS"),
+	}
+}
+
+func patternsFromEnv(name string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(os.Getenv(name), ",") {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// IsEmpty returns true if no organization or repository is included or excluded
+func (f repoFilter) IsEmpty() bool {
+	return len(f.includeOrgs) == 0 && len(f.includeRepos) == 0 && len(f.excludeOrgs) == 0 && len(f.excludeRepos) == 0
+}
+
+// String describes the filter, e.g. "include orgs: example-org; exclude repos: */dotfiles"
+func (f repoFilter) String() string {
+	var parts []string
+	for _, list := range []struct {
+		label    string
+		patterns []string
+	}{
+		{"include orgs", f.includeOrgs},
+		{"include repos", f.includeRepos},
+		{"exclude orgs", f.excludeOrgs},
+		{"exclude repos", f.excludeRepos},
+	} {
+		if len(list.patterns) > 0 {
+			parts = append(parts, list.label+": "+strings.Join(list.patterns, ", "))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Allows reports whether a repository (owner/repo) passes the filter. Exclusions take precedence over inclusions.
+func (f repoFilter) Allows(fullName string) bool {
+	fullName = strings.ToLower(fullName)
+	org := strings.SplitN(fullName, "/", 2)[0]
+	if matchesAny(f.excludeOrgs, org) || matchesAny(f.excludeRepos, fullName) {
+		return false
+	}
+	if len(f.includeOrgs) == 0 && len(f.includeRepos) == 0 {
+		return true
+	}
+	return matchesAny(f.includeOrgs, org) || matchesAny(f.includeRepos, fullName)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Qualify adds org:/repo: qualifiers for the filter to a search query so that fewer results have to be fetched.
+// Wildcard entries can't be expressed in a query, and the search API ORs scope qualifiers, so inclusions are only
+// added when they are all exact and the query has no scope of its own. Results are still checked with Allows.
+func (f repoFilter) Qualify(query string) string {
+	var qualifiers []string
+	for _, org := range f.excludeOrgs {
+		if !strings.Contains(org, "*") {
+			qualifiers = append(qualifiers, "-org:"+org)
+		}
+	}
+	for _, repo := range f.excludeRepos {
+		if !strings.Contains(repo, "*") {
+			qualifiers = append(qualifiers, "-repo:"+repo)
+		}
+	}
+
+	scoped := strings.Contains(query, "repo:") || strings.Contains(query, "org:") || strings.Contains(query, "user:")
+	includes := append(append([]string{}, f.includeOrgs...), f.includeRepos...)
+	if !scoped && len(includes) > 0 && !strings.Contains(strings.Join(includes, ","), "*") {
+		for _, org := range f.includeOrgs {
+			qualifiers = append(qualifiers, "org:"+org)
+		}
+		for _, repo := range f.includeRepos {
+			qualifiers = append(qualifiers, "repo:"+repo)
+		}
+	}
+
+	if len(qualifiers) == 0 {
+		return query
+	}
+	qualified := query + " " + strings.Join(qualifiers, " ")
+	if len(qualified) > maxSearchQueryLength {
+		return query
+	}
+	return qualified
+}
@@ -0,0 +1,109 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// RepoActivity counts the user's PRs in one repository, from a single search (dev-stats github repos)
+type RepoActivity struct {
+	FullName     string
+	PRs          int // PRs the user was involved in
+	Authored     int
+	BotPRs       int // involved PRs opened by bot accounts (excluded from analysis counts)
+	LastActivity time.Time
+}
+
+// ListActiveRepositories returns repositories with any PR the user was involved in during the period,
+// most active first. It only runs the involves: search, so it is cheap compared to a full analysis.
+func (g *GitHubAnalyzer) ListActiveRepositories(config *common.Config, writer io.Writer) ([]RepoActivity, error) {
+	if err := g.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := g.loadConfigFiles(); err != nil {
+		return nil, err
+	}
+
+	prs, err := g.searchPRs(writer, "involves:"+g.username, config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to search involved PRs")
+	}
+	prs = g.filterIgnored(writer, prs)
+
+	repos := make(map[string]*RepoActivity)
+	for _, pr := range prs {
+		fullName := g.extractRepoFromURL(pr.RepositoryURL)
+		repo, exists := repos[fullName]
+		if !exists {
+			repo = &RepoActivity{FullName: fullName}
+			repos[fullName] = repo
+		}
+		repo.PRs++
+		if strings.EqualFold(pr.User.Login, g.username) {
+			repo.Authored++
+		}
+		if g.isBotPR(pr) {
+			repo.BotPRs++
+		}
+		if pr.CreatedAt.After(repo.LastActivity) {
+			repo.LastActivity = pr.CreatedAt
+		}
+	}
+
+	activities := make([]RepoActivity, 0, len(repos))
+	for _, repo := range repos {
+		activities = append(activities, *repo)
+	}
+	sort.Slice(activities, func(i, j int) bool {
+		if activities[i].PRs != activities[j].PRs {
+			return activities[i].PRs > activities[j].PRs
+		}
+		return activities[i].FullName < activities[j].FullName
+	})
+	return activities, nil
+}
+
+// PrintRepoActivity prints the repository inventory grouped by organization
+func PrintRepoActivity(writer io.Writer, repos []RepoActivity, startDate, endDate time.Time) {
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(writer, "GITHUB REPOSITORIES WITH ACTIVITY (%d)\n", len(repos))
+	fmt.Fprintf(writer, "Period: %s to %s\n", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	if len(repos) == 0 {
+		fmt.Fprintln(writer, "No PRs found in the period.")
+		return
+	}
+
+	orgTotals := make(map[string]int)
+	for _, repo := range repos {
+		orgTotals[strings.SplitN(repo.FullName, "/", 2)[0]] += repo.PRs
+	}
+	orgs := make([]string, 0, len(orgTotals))
+	for org := range orgTotals {
+		orgs = append(orgs, org)
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		if orgTotals[orgs[i]] != orgTotals[orgs[j]] {
+			return orgTotals[orgs[i]] > orgTotals[orgs[j]]
+		}
+		return orgs[i] < orgs[j]
+	})
+
+	for _, org := range orgs {
+		fmt.Fprintf(writer, "\n%s (%d PRs)\n", org, orgTotals[org])
+		fmt.Fprintf(writer, "  %-40s %6s %8s %6s  %s\n", "Repository", "PRs", "Authored", "Bots", "Last PR")
+		for _, repo := range repos {
+			if !strings.HasPrefix(repo.FullName, org+"/") {
+				continue
+			}
+			fmt.Fprintf(writer, "  %-40s %6d %8d %6d  %s\n", repo.FullName, repo.PRs, repo.Authored, repo.BotPRs,
+				repo.LastActivity.Local().Format("2006-01-02"))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// IssueComment is a conversation comment on a PR
+type IssueComment struct {
+	Body      string    `json:"body"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+// CollectKudos scans comments and reviews on PRs the user authored for thanks or kudos from others within the period
+func (g *GitHubAnalyzer) CollectKudos(config *common.Config, writer io.Writer, matcher *common.KudosMatcher) ([]common.Kudos, error) {
+	if err := g.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := g.loadConfigFiles(); err != nil {
+		return nil, err
+	}
+
+	// PRs created shortly before the period can still receive comments in it
+	authoredPRs, err := g.searchPRs(writer, "author:"+g.username, config.StartDate.AddDate(0, -1, 0), config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to search authored PRs")
+	}
+	authoredPRs = g.filterIgnored(writer, authoredPRs)
+
+	inPeriod := func(t time.Time) bool {
+		day := t.Local().Format("2006-01-02")
+		return day >= config.StartDate.Format("2006-01-02") && day <= config.EndDate.Format("2006-01-02")
+	}
+	fromOthers := func(login string) bool {
+		return login != "" && login != g.username && !g.isBot(login)
+	}
+
+	var kudos []common.Kudos
+	for _, pr := range authoredPRs {
+		repoFullName := g.extractRepoFromURL(pr.RepositoryURL)
+
+		body, err := g.client.Get(fmt.Sprintf("%s/repos/%s/issues/%d/comments?per_page=100", g.apiURL, repoFullName, pr.Number), nil)
+		if err != nil {
+			fmt.Fprintf(writer, "Warning: Failed to get comments for %s#%d: %v\n", repoFullName, pr.Number, err)
+		} else {
+			var comments []IssueComment
+			if err := json.Unmarshal(body, &comments); err == nil {
+				for _, comment := range comments {
+					if fromOthers(comment.User.Login) && inPeriod(comment.CreatedAt) && matcher.Matches(comment.Body) {
+						kudos = append(kudos, common.Kudos{
+							Source: "GitHub",
+							Author: comment.User.Login,
+							Quote:  common.KudosQuote(comment.Body),
+							URL:    comment.HTMLURL,
+							Time:   comment.CreatedAt,
+						})
+					}
+				}
+			}
+		}
+
+		body, err = g.client.Get(fmt.Sprintf("%s/repos/%s/pulls/%d/reviews?per_page=100", g.apiURL, repoFullName, pr.Number), nil)
+		if err != nil {
+			fmt.Fprintf(writer, "Warning: Failed to get reviews for %s#%d: %v\n", repoFullName, pr.Number, err)
+			continue
+		}
+		var reviews []Review
+		if err := json.Unmarshal(body, &reviews); err != nil {
+			continue
+		}
+		for _, review := range reviews {
+			if fromOthers(review.User.Login) && inPeriod(review.SubmittedAt) && matcher.Matches(review.Body) {
+				kudos = append(kudos, common.Kudos{
+					Source: "GitHub",
+					Author: review.User.Login,
+					Quote:  common.KudosQuote(review.Body),
+					URL:    fmt.Sprintf("%s#pullrequestreview-%d", pr.URL, review.ID),
+					Time:   review.SubmittedAt,
+				})
+			}
+		}
+	}
+	return kudos, nil
+}
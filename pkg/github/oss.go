@@ -0,0 +1,190 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// OSSStats splits PRs into open-source and internal work
+type OSSStats struct {
+	OSSAuthored      []PullRequest `json:"oss_authored"`
+	OSSInvolved      []PullRequest `json:"oss_involved"`
+	InternalAuthored []PullRequest `json:"internal_authored"`
+	InternalInvolved []PullRequest `json:"internal_involved"`
+}
+
+// orgSetFromEnv reads a comma-separated list of organizations into a lowercase set
+func orgSetFromEnv(name string) map[string]bool {
+	orgs := make(map[string]bool)
+	for _, org := range strings.Split(os.Getenv(name), ",") {
+		if org = strings.ToLower(strings.TrimSpace(org)); org != "" {
+			orgs[org] = true
+		}
+	}
+	return orgs
+}
+
+// isOSS reports whether a PR is open-source work.
+// GITHUB_OSS_ORGS and GITHUB_INTERNAL_ORGS take precedence over repository visibility,
+// e.g. to count a company's public repositories as internal work.
+func (g *GitHubAnalyzer) isOSS(pr PullRequest) bool {
+	org := strings.ToLower(g.extractOrgName(g.extractRepoFromURL(pr.RepositoryURL)))
+	if g.internalOrgs[org] {
+		return false
+	}
+	if g.ossOrgs[org] {
+		return true
+	}
+	repo, exists := g.repoFor(pr)
+	return exists && !repo.Private
+}
+
+// analyzeOSS classifies authored and involved PRs as open-source or internal
+func (g *GitHubAnalyzer) analyzeOSS(authoredPRs, involvedPRs []PullRequest) *OSSStats {
+	stats := &OSSStats{}
+	for _, pr := range authoredPRs {
+		if g.isOSS(pr) {
+			stats.OSSAuthored = append(stats.OSSAuthored, pr)
+		} else {
+			stats.InternalAuthored = append(stats.InternalAuthored, pr)
+		}
+	}
+	for _, pr := range involvedPRs {
+		if g.isOSS(pr) {
+			stats.OSSInvolved = append(stats.OSSInvolved, pr)
+		} else {
+			stats.InternalInvolved = append(stats.InternalInvolved, pr)
+		}
+	}
+	return stats
+}
+
+// printOSS prints the open-source vs internal split and lists authored open-source PRs
+func (g *GitHubAnalyzer) printOSS(writer io.Writer, stats *OSSStats) {
+	fmt.Fprintln(writer, "\nOpen-source vs internal (author/involves):")
+	fmt.Fprintf(writer, "- Open-source: %d (%d)\n", len(stats.OSSAuthored), len(stats.OSSInvolved))
+	fmt.Fprintf(writer, "- Internal: %d (%d)\n", len(stats.InternalAuthored), len(stats.InternalInvolved))
+
+	if len(stats.OSSAuthored) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "\nOpen-source Pull Requests you authored (%d):\n", len(stats.OSSAuthored))
+	for _, pr := range stats.OSSAuthored {
+		fmt.Fprintf(writer, "- %s: %s\n", pr.CreatedAt.Format("2006-01-02 15:04"), pr.Title)
+		fmt.Fprintf(writer, "  URL: %s\n", pr.URL)
+	}
+}
+
+// OSSContribution is an authored open-source PR with its merge status and repository popularity
+type OSSContribution struct {
+	PR       PullRequest `json:"pr"`
+	Repo     Repository  `json:"repo"`
+	Status   string      `json:"status"` // merged, open, closed
+	MergedAt *time.Time  `json:"merged_at,omitempty"`
+}
+
+// CollectOSSContributions finds PRs the user authored in open-source repositories within the period
+func (g *GitHubAnalyzer) CollectOSSContributions(config *common.Config, writer io.Writer) ([]OSSContribution, error) {
+	if err := g.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := g.loadConfigFiles(); err != nil {
+		return nil, err
+	}
+	g.warnings.Reset()
+
+	authoredPRs, err := g.searchPRs(writer, "author:"+g.username, config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to search authored PRs")
+	}
+	authoredPRs = g.filterIgnored(writer, authoredPRs)
+	g.repos = g.fetchRepositories(writer, authoredPRs)
+	if warnings := g.warnings.List(); len(warnings) > 0 {
+		fmt.Fprintln(writer, "Warning: Some repository lookups failed; their PRs may be missing or counted as internal:")
+		common.PrintWarningList(writer, warnings)
+		g.warnings.Reset()
+	}
+
+	var contributions []OSSContribution
+	for _, pr := range authoredPRs {
+		if !g.isOSS(pr) {
+			continue
+		}
+		repoFullName := g.extractRepoFromURL(pr.RepositoryURL)
+		repo, exists := g.repoFor(pr)
+		if !exists {
+			repo.FullName = repoFullName
+		}
+		contribution := OSSContribution{PR: pr, Repo: repo, Status: "open"}
+
+		body, err := g.client.Get(fmt.Sprintf("%s/repos/%s/pulls/%d", g.apiURL, repoFullName, pr.Number), nil)
+		if err != nil {
+			fmt.Fprintf(writer, "Warning: Failed to get PR %s#%d: %v\n", repoFullName, pr.Number, err)
+		} else {
+			var detail PullRequestDetail
+			if err := json.Unmarshal(body, &detail); err == nil {
+				switch {
+				case detail.MergedAt != nil:
+					contribution.Status = "merged"
+					contribution.MergedAt = detail.MergedAt
+				case detail.State == "closed":
+					contribution.Status = "closed"
+				}
+			}
+		}
+		contributions = append(contributions, contribution)
+	}
+
+	// Most popular repositories first, then newest PRs
+	sort.SliceStable(contributions, func(i, j int) bool {
+		if contributions[i].Repo.Stars != contributions[j].Repo.Stars {
+			return contributions[i].Repo.Stars > contributions[j].Repo.Stars
+		}
+		if contributions[i].Repo.FullName != contributions[j].Repo.FullName {
+			return contributions[i].Repo.FullName < contributions[j].Repo.FullName
+		}
+		return contributions[i].PR.CreatedAt.After(contributions[j].PR.CreatedAt)
+	})
+	return contributions, nil
+}
+
+// WriteOSSReport formats contributions as Markdown grouped by repository, for OSS program submissions or a portfolio page
+func WriteOSSReport(writer io.Writer, contributions []OSSContribution, startDate, endDate time.Time) {
+	repos := make(map[string]bool)
+	merged := 0
+	for _, contribution := range contributions {
+		repos[contribution.Repo.FullName] = true
+		if contribution.Status == "merged" {
+			merged++
+		}
+	}
+
+	fmt.Fprintf(writer, "# Open-source contributions (%s to %s)\n\n", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	fmt.Fprintf(writer, "%d pull requests to %d repositories, %d merged.\n", len(contributions), len(repos), merged)
+
+	currentRepo := ""
+	for _, contribution := range contributions {
+		if contribution.Repo.FullName != currentRepo {
+			currentRepo = contribution.Repo.FullName
+			heading := fmt.Sprintf("[%s](https://github.com/%s) ★ %d", currentRepo, currentRepo, contribution.Repo.Stars)
+			if contribution.Repo.Language != "" {
+				heading += " · " + contribution.Repo.Language
+			}
+			fmt.Fprintf(writer, "\n## %s\n\n", heading)
+		}
+
+		status := contribution.Status
+		if contribution.MergedAt != nil {
+			status += " " + contribution.MergedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(writer, "- [%s](%s) (#%d, opened %s, %s)\n", contribution.PR.Title, contribution.PR.URL,
+			contribution.PR.Number, contribution.PR.CreatedAt.Format("2006-01-02"), status)
+	}
+}
@@ -0,0 +1,104 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultHost is the host used when GITHUB_HOST or GITHUB_<PROFILE>_HOST is not set
+const defaultHost = "github.com"
+
+// GitHubProfile represents a GitHub account on github.com or a GitHub Enterprise Server
+type GitHubProfile struct {
+	Name     string
+	Token    string
+	Host     string // e.g., "github.com" or "github.example.com"
+	Username string
+}
+
+// IsComplete returns true if all required fields are set
+func (p *GitHubProfile) IsComplete() bool {
+	return p.Token != "" && p.Username != ""
+}
+
+// apiBaseURL returns the REST API root of a host: api.github.com for github.com, /api/v3 on GitHub Enterprise Server
+func apiBaseURL(host string) string {
+	host = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://"), "/")
+	if host == "" || host == defaultHost {
+		return "https://api.github.com"
+	}
+	return fmt.Sprintf("https://%s/api/v3", host)
+}
+
+// LoadGitHubProfiles loads the additional GitHub profiles from environment variables.
+// Profiles are defined with pattern: GITHUB_<PROFILE_NAME>_<SETTING> (TOKEN, USERNAME, HOST).
+// GITHUB_TOKEN/GITHUB_USERNAME/GITHUB_HOST remain the default account and are not a profile.
+func LoadGitHubProfiles() []GitHubProfile {
+	profileMap := make(map[string]*GitHubProfile)
+
+	for _, env := range os.Environ() {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], "GITHUB_") {
+			continue
+		}
+
+		// Parse the key: GITHUB_<PROFILE>_<SETTING>. Other GITHUB_* settings (GITHUB_OSS_ORGS, ...)
+		// parse into profiles without a token and are dropped below.
+		keyParts := strings.Split(parts[0], "_")
+		if len(keyParts) != 3 {
+			continue
+		}
+
+		profileName := keyParts[1]
+		if _, exists := profileMap[profileName]; !exists {
+			profileMap[profileName] = &GitHubProfile{Name: profileName, Host: defaultHost}
+		}
+		profile := profileMap[profileName]
+
+		switch keyParts[2] {
+		case "TOKEN":
+			profile.Token = parts[1]
+		case "USERNAME":
+			profile.Username = parts[1]
+		case "HOST":
+			if parts[1] != "" {
+				profile.Host = parts[1]
+			}
+		}
+	}
+
+	profiles := make([]GitHubProfile, 0, len(profileMap))
+	for _, profile := range profileMap {
+		if profile.Token != "" {
+			profiles = append(profiles, *profile)
+		}
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles
+}
+
+// NewGitHubAnalyzers returns the analyzer of the default account (GITHUB_TOKEN) followed by one per profile.
+// Without any profile the default analyzer is returned even if GITHUB_TOKEN is unset, so that its validation reports it.
+func NewGitHubAnalyzers() []*GitHubAnalyzer {
+	var analyzers []*GitHubAnalyzer
+	profiles := LoadGitHubProfiles()
+	if os.Getenv("GITHUB_TOKEN") != "" || len(profiles) == 0 {
+		analyzers = append(analyzers, NewGitHubAnalyzer())
+	}
+	for _, profile := range profiles {
+		analyzers = append(analyzers, NewGitHubAnalyzerWithProfile(&profile))
+	}
+	return analyzers
+}
+
+// MergeProfilesFromEnv reports whether GITHUB_MERGE_PROFILES asks for one combined GitHub result across accounts
+func MergeProfilesFromEnv() bool {
+	value := strings.ToLower(os.Getenv("GITHUB_MERGE_PROFILES"))
+	return value == "true" || value == "1" || value == "yes"
+}
@@ -0,0 +1,239 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"dev-stats/pkg/common"
+)
+
+// defaultConcurrency is the number of PR detail requests in flight when GITHUB_CONCURRENCY is not set
+const defaultConcurrency = 4
+
+// prSizeBuckets are the upper bounds (exclusive) of changed lines per size label; the last bucket is open-ended
+var prSizeBuckets = []struct {
+	Label string
+	Max   int
+}{
+	{"XS", 10},
+	{"S", 30},
+	{"M", 100},
+	{"L", 500},
+	{"XL", 0},
+}
+
+// PRSize is the size of one authored PR
+type PRSize struct {
+	Title        string `json:"title"`
+	URL          string `json:"url"`
+	Repository   string `json:"repository"`
+	Number       int    `json:"number"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	ChangedFiles int    `json:"changed_files"`
+}
+
+// Lines returns the changed lines (additions + deletions)
+func (s PRSize) Lines() int {
+	return s.Additions + s.Deletions
+}
+
+// PRSizeStats summarizes the size of authored PRs whose details could be fetched
+type PRSizeStats struct {
+	Additions    int      `json:"additions"`
+	Deletions    int      `json:"deletions"`
+	ChangedFiles int      `json:"changed_files"`
+	Distribution []int    `json:"distribution"` // PRs per prSizeBuckets entry
+	Largest      []PRSize `json:"largest"`
+}
+
+// prSizeLabel returns the size label (XS to XL) of a PR with the given changed lines
+func prSizeLabel(lines int) string {
+	return prSizeBuckets[prSizeBucket(lines)].Label
+}
+
+func prSizeBucket(lines int) int {
+	for i, bucket := range prSizeBuckets {
+		if bucket.Max == 0 || lines < bucket.Max {
+			return i
+		}
+	}
+	return len(prSizeBuckets) - 1
+}
+
+// concurrencyFromEnv reads GITHUB_CONCURRENCY (default 4; 1 fetches one PR at a time)
+func concurrencyFromEnv() int {
+	if value := os.Getenv("GITHUB_CONCURRENCY"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 1 {
+			return parsed
+		}
+	}
+	return defaultConcurrency
+}
+
+// getPRDetailCachePath returns the cache file of details of closed PRs
+func getPRDetailCachePath() string {
+	return filepath.Join(repoCacheDir, "pr-details.json")
+}
+
+// loadPRDetailCache loads cached details of closed PRs keyed by PR URL
+func loadPRDetailCache() map[string]PullRequestDetail {
+	cache := make(map[string]PullRequestDetail)
+	data, err := common.ReadProtectedFile(getPRDetailCachePath())
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(map[string]PullRequestDetail)
+	}
+	return cache
+}
+
+// savePRDetailCache saves details of closed PRs for later runs
+func savePRDetailCache(cache map[string]PullRequestDetail) error {
+	if err := os.MkdirAll(repoCacheDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return common.WriteProtectedFile(getPRDetailCachePath(), data)
+}
+
+// fetchPRDetails records changed lines, state, and merge time of each PR.
+// Closed and merged PRs no longer change, so their details are cached in .github-cache/ and reused;
+// open PRs are fetched every run, GITHUB_CONCURRENCY at a time.
+func (g *GitHubAnalyzer) fetchPRDetails(writer io.Writer, prs []PullRequest) {
+	g.prSizes = make(map[string]int)
+	g.prDetails = make(map[string]PullRequestDetail)
+	cache := loadPRDetailCache()
+
+	var pending []PullRequest
+	for _, pr := range prs {
+		if detail, exists := cache[pr.URL]; exists {
+			g.prSizes[pr.URL] = detail.Additions + detail.Deletions
+			g.prDetails[pr.URL] = detail
+			continue
+		}
+		pending = append(pending, pr)
+	}
+
+	// Workers fill their own slots; results are merged in PR order so that warnings stay in a stable order
+	details := make([]PullRequestDetail, len(pending))
+	errs := make([]error, len(pending))
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for i := 0; i < concurrencyFromEnv(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				pr := pending[index]
+				body, err := g.client.Get(fmt.Sprintf("%s/repos/%s/pulls/%d", g.apiURL, g.extractRepoFromURL(pr.RepositoryURL), pr.Number), nil)
+				if err == nil {
+					err = json.Unmarshal(body, &details[index])
+				}
+				errs[index] = err
+			}
+		}()
+	}
+	for index := range pending {
+		queue <- index
+	}
+	close(queue)
+	wg.Wait()
+
+	cached := 0
+	for index, pr := range pending {
+		if errs[index] != nil {
+			g.warnings.Add("PR details", fmt.Sprintf("%s#%d", g.extractRepoFromURL(pr.RepositoryURL), pr.Number), errs[index])
+			continue
+		}
+		detail := details[index]
+		g.prSizes[pr.URL] = detail.Additions + detail.Deletions
+		g.prDetails[pr.URL] = detail
+		if detail.State == "closed" {
+			cache[pr.URL] = detail
+			cached++
+		}
+	}
+
+	if cached > 0 {
+		if err := savePRDetailCache(cache); err != nil {
+			fmt.Fprintf(writer, "Warning: Failed to save PR detail cache: %v\n", err)
+		}
+	}
+	fmt.Fprintf(writer, "PR details: %d PRs (%d fetched, %d from %s)\n",
+		len(prs), len(pending), len(prs)-len(pending), getPRDetailCachePath())
+}
+
+// analyzePRSizes totals changed lines and files of authored PRs and buckets them by size
+func (g *GitHubAnalyzer) analyzePRSizes(prs []PullRequest) *PRSizeStats {
+	stats := &PRSizeStats{Distribution: make([]int, len(prSizeBuckets))}
+	var sizes []PRSize
+	for _, pr := range prs {
+		detail, exists := g.prDetails[pr.URL]
+		if !exists {
+			continue
+		}
+		size := PRSize{
+			Title:        pr.Title,
+			URL:          pr.URL,
+			Repository:   g.extractRepoFromURL(pr.RepositoryURL),
+			Number:       pr.Number,
+			Additions:    detail.Additions,
+			Deletions:    detail.Deletions,
+			ChangedFiles: detail.ChangedFiles,
+		}
+		stats.Additions += size.Additions
+		stats.Deletions += size.Deletions
+		stats.ChangedFiles += size.ChangedFiles
+		stats.Distribution[prSizeBucket(size.Lines())]++
+		sizes = append(sizes, size)
+	}
+
+	sort.SliceStable(sizes, func(i, j int) bool {
+		if sizes[i].Lines() != sizes[j].Lines() {
+			return sizes[i].Lines() > sizes[j].Lines()
+		}
+		return sizes[i].URL < sizes[j].URL
+	})
+	stats.Largest = sizes
+	return stats
+}
+
+// printPRSizes prints the size distribution, the total lines contributed, and the largest PRs
+func (g *GitHubAnalyzer) printPRSizes(writer io.Writer, stats *PRSizeStats) {
+	total := 0
+	for _, count := range stats.Distribution {
+		total += count
+	}
+	fmt.Fprintf(writer, "\nPR size (authored, %d with details):\n", total)
+	if total == 0 {
+		fmt.Fprintln(writer, "- No PR details available")
+		return
+	}
+	fmt.Fprintf(writer, "- Lines contributed: +%d / -%d in %d changed files\n", stats.Additions, stats.Deletions, stats.ChangedFiles)
+
+	var parts []string
+	for i, count := range stats.Distribution {
+		parts = append(parts, fmt.Sprintf("%s: %d", prSizeBuckets[i].Label, count))
+	}
+	fmt.Fprintf(writer, "- Distribution: %s\n", strings.Join(parts, ", "))
+
+	fmt.Fprintln(writer, "\nLargest authored PRs:")
+	for _, size := range stats.Largest[:common.RankingLimit(len(stats.Largest))] {
+		fmt.Fprintf(writer, "- [%s] %s#%d %s (+%d / -%d, %d files)\n", prSizeLabel(size.Lines()), size.Repository,
+			size.Number, size.Title, size.Additions, size.Deletions, size.ChangedFiles)
+		fmt.Fprintf(writer, "  URL: %s\n", size.URL)
+	}
+	common.PrintMoreEntries(writer, len(stats.Largest))
+}
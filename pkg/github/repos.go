@@ -0,0 +1,197 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// repoCacheDir holds repository metadata shared across runs and periods
+const repoCacheDir = ".github-cache"
+
+// repoCacheTTL is how long cached repository metadata is trusted before it is fetched again
+const repoCacheTTL = 7 * 24 * time.Hour
+
+// Repository holds metadata of a repository PRs were found in
+type Repository struct {
+	FullName      string    `json:"full_name"`
+	DefaultBranch string    `json:"default_branch"`
+	Private       bool      `json:"private"`
+	Fork          bool      `json:"fork"`
+	Language      string    `json:"language"`
+	Topics        []string  `json:"topics"`
+	Stars         int       `json:"stargazers_count"`
+	FetchedAt     time.Time `json:"fetched_at"`
+}
+
+// RepoBreakdown counts PRs (author/involves) per repository language or topic
+type RepoBreakdown map[string]struct{ authored, involved int }
+
+// getRepoCachePath returns the repository metadata cache file path
+func getRepoCachePath() string {
+	return filepath.Join(repoCacheDir, "repos.json")
+}
+
+// loadRepoCache loads cached repository metadata keyed by lowercase owner/repo (host/owner/repo outside github.com)
+func loadRepoCache() map[string]Repository {
+	cache := make(map[string]Repository)
+	data, err := common.ReadProtectedFile(getRepoCachePath())
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(map[string]Repository)
+	}
+	return cache
+}
+
+// saveRepoCache saves repository metadata for later runs
+func saveRepoCache(cache map[string]Repository) error {
+	if err := os.MkdirAll(repoCacheDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return common.WriteProtectedFile(getRepoCachePath(), data)
+}
+
+// fetchRepositories returns metadata for every repository the PRs belong to.
+// Each repository is fetched at most once and reused from the cache until it expires.
+func (g *GitHubAnalyzer) fetchRepositories(writer io.Writer, prs []PullRequest) map[string]Repository {
+	cache := loadRepoCache()
+	repos := make(map[string]Repository)
+	fetched := 0
+
+	for _, pr := range prs {
+		fullName := g.extractRepoFromURL(pr.RepositoryURL)
+		key := g.repoCacheKey(fullName)
+		if _, exists := repos[key]; exists {
+			continue
+		}
+		if cached, exists := cache[key]; exists && time.Since(cached.FetchedAt) < repoCacheTTL {
+			repos[key] = cached
+			continue
+		}
+
+		body, err := g.client.Get(g.apiURL+"/repos/"+fullName, nil)
+		if err != nil {
+			g.warnings.Add("repository metadata", fullName, err)
+			continue
+		}
+		var repo Repository
+		if err := json.Unmarshal(body, &repo); err != nil {
+			g.warnings.Add("repository metadata", fullName, err)
+			continue
+		}
+		repo.FetchedAt = time.Now()
+		repos[key] = repo
+		cache[key] = repo
+		fetched++
+	}
+
+	if fetched > 0 {
+		if err := saveRepoCache(cache); err != nil {
+			fmt.Fprintf(writer, "Warning: Failed to save repository cache: %v\n", err)
+		}
+	}
+	fmt.Fprintf(writer, "Repository metadata: %d repositories (%d fetched, %d from %s)\n",
+		len(repos), fetched, len(repos)-fetched, getRepoCachePath())
+	return repos
+}
+
+// repoFor returns cached metadata of the PR's repository
+func (g *GitHubAnalyzer) repoFor(pr PullRequest) (Repository, bool) {
+	repo, exists := g.repos[g.repoCacheKey(g.extractRepoFromURL(pr.RepositoryURL))]
+	return repo, exists
+}
+
+// repoCacheKey returns the cache key of a repository: lowercase owner/repo, prefixed with the host on GitHub Enterprise Server
+func (g *GitHubAnalyzer) repoCacheKey(fullName string) string {
+	if g.host != defaultHost {
+		return strings.ToLower(g.host + "/" + fullName)
+	}
+	return strings.ToLower(fullName)
+}
+
+// analyzeRepoBreakdowns counts PRs per repository language, topic, and visibility (public/private)
+func (g *GitHubAnalyzer) analyzeRepoBreakdowns(authoredPRs, involvedPRs []PullRequest) (languages, topics, visibility RepoBreakdown) {
+	languages = make(RepoBreakdown)
+	topics = make(RepoBreakdown)
+	visibility = make(RepoBreakdown)
+
+	add := func(breakdown RepoBreakdown, name string, authored bool) {
+		stat := breakdown[name]
+		if authored {
+			stat.authored++
+		} else {
+			stat.involved++
+		}
+		breakdown[name] = stat
+	}
+	count := func(prs []PullRequest, authored bool) {
+		for _, pr := range prs {
+			repo, exists := g.repoFor(pr)
+			if !exists {
+				continue
+			}
+			language := repo.Language
+			if language == "" {
+				language = "Unknown"
+			}
+			add(languages, language, authored)
+			for _, topic := range repo.Topics {
+				add(topics, topic, authored)
+			}
+			if repo.Private {
+				add(visibility, "private", authored)
+			} else {
+				add(visibility, "public", authored)
+			}
+		}
+	}
+	count(authoredPRs, true)
+	count(involvedPRs, false)
+
+	return languages, topics, visibility
+}
+
+// printRepoBreakdown prints PR counts with their share of all authored/involved PRs, e.g. "60% of PRs in Go repos"
+func (g *GitHubAnalyzer) printRepoBreakdown(writer io.Writer, title string, breakdown RepoBreakdown, totalAuthored, totalInvolved int) {
+	if len(breakdown) == 0 {
+		return
+	}
+
+	var names []string
+	for name := range breakdown {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if breakdown[names[i]].authored != breakdown[names[j]].authored {
+			return breakdown[names[i]].authored > breakdown[names[j]].authored
+		}
+		return names[i] < names[j]
+	})
+
+	share := func(count, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(count) * 100 / float64(total)
+	}
+
+	fmt.Fprintf(writer, "\n%s (author/involves):\n", title)
+	for _, name := range names {
+		stat := breakdown[name]
+		fmt.Fprintf(writer, "- %s: %d (%.0f%%) / %d (%.0f%%)\n", name,
+			stat.authored, share(stat.authored, totalAuthored), stat.involved, share(stat.involved, totalInvolved))
+	}
+}
@@ -0,0 +1,134 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// RepoReviewCount is the review activity of the user in one repository
+type RepoReviewCount struct {
+	Repository string `json:"repository"`
+	Reviews    int    `json:"reviews"`
+	Comments   int    `json:"comments"`
+}
+
+// ReviewThread is a review comment thread on a changed line the user wrote in
+type ReviewThread struct {
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	Path       string `json:"path"`
+	URL        string `json:"url"`
+	Comments   int    `json:"comments"`
+	MyComments int    `json:"my_comments"`
+}
+
+// reviewComments fetches the review comments (comments on changed lines) of a PR, following pagination
+func (g *GitHubAnalyzer) reviewComments(repoFullName string, number int) ([]ReviewComment, error) {
+	var comments []ReviewComment
+	perPage := 100
+	for page := 1; ; page++ {
+		body, err := g.client.Get(fmt.Sprintf("%s/repos/%s/pulls/%d/comments?per_page=%d&page=%d", g.apiURL, repoFullName, number, perPage, page), nil)
+		if err != nil {
+			return nil, err
+		}
+		var pageComments []ReviewComment
+		if err := json.Unmarshal(body, &pageComments); err != nil {
+			return nil, err
+		}
+		comments = append(comments, pageComments...)
+		if len(pageComments) < perPage {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// analyzeReviewComments counts the user's review comments on a PR within the period and collects the threads they wrote in
+func (g *GitHubAnalyzer) analyzeReviewComments(pr PullRequest, repoFullName string, comments []ReviewComment, startDate, endDate time.Time) (written int, threads []ReviewThread) {
+	byRoot := make(map[int]*ReviewThread)
+	var roots []int
+	for _, comment := range comments {
+		root := comment.ID
+		if comment.InReplyToID != 0 {
+			root = comment.InReplyToID
+		}
+		thread, exists := byRoot[root]
+		if !exists {
+			thread = &ReviewThread{Repository: repoFullName, Number: pr.Number, Title: pr.Title, Path: comment.Path, URL: comment.HTMLURL}
+			byRoot[root] = thread
+			roots = append(roots, root)
+		}
+		thread.Comments++
+
+		if comment.User.Login == g.username &&
+			comment.CreatedAt.After(startDate.Add(-24*time.Hour)) &&
+			comment.CreatedAt.Before(endDate.Add(24*time.Hour)) {
+			thread.MyComments++
+			written++
+		}
+	}
+
+	for _, root := range roots {
+		if byRoot[root].MyComments > 0 {
+			threads = append(threads, *byRoot[root])
+		}
+	}
+	return written, threads
+}
+
+// summarizeReviewComments ranks repositories by reviews and threads by length
+func summarizeReviewComments(stats *ReviewStats, byRepo []RepoReviewCount, threads []ReviewThread) {
+	sort.SliceStable(byRepo, func(i, j int) bool {
+		if byRepo[i].Reviews != byRepo[j].Reviews {
+			return byRepo[i].Reviews > byRepo[j].Reviews
+		}
+		return byRepo[i].Repository < byRepo[j].Repository
+	})
+	stats.ByRepo = byRepo
+
+	sort.SliceStable(threads, func(i, j int) bool {
+		if threads[i].Comments != threads[j].Comments {
+			return threads[i].Comments > threads[j].Comments
+		}
+		return threads[i].URL < threads[j].URL
+	})
+	stats.LongestThreads = threads
+}
+
+// CommentsPerReview returns the average review comments written per review given
+func (s *ReviewStats) CommentsPerReview() float64 {
+	if s.ReviewsGiven == 0 {
+		return 0
+	}
+	return math.Round(float64(s.ReviewCommentsWritten)/float64(s.ReviewsGiven)*10) / 10
+}
+
+// printReviewComments prints review comment totals, the most-reviewed repositories, and the longest threads
+func (g *GitHubAnalyzer) printReviewComments(writer io.Writer, stats *ReviewStats) {
+	fmt.Fprintf(writer, "- Review comments written: %d (%.1f per review)\n", stats.ReviewCommentsWritten, stats.CommentsPerReview())
+
+	if len(stats.ByRepo) > 0 {
+		fmt.Fprintln(writer, "\nMost-reviewed repositories (reviews / review comments):")
+		for _, repo := range stats.ByRepo[:common.RankingLimit(len(stats.ByRepo))] {
+			fmt.Fprintf(writer, "- %s: %d / %d\n", repo.Repository, repo.Reviews, repo.Comments)
+		}
+		common.PrintMoreEntries(writer, len(stats.ByRepo))
+	}
+
+	if len(stats.LongestThreads) > 0 {
+		fmt.Fprintln(writer, "\nLongest review threads you took part in:")
+		for _, thread := range stats.LongestThreads[:common.RankingLimit(len(stats.LongestThreads))] {
+			fmt.Fprintf(writer, "- %s#%d %s: %s (%d comments, %d yours)\n", thread.Repository, thread.Number, thread.Title,
+				thread.Path, thread.Comments, thread.MyComments)
+			fmt.Fprintf(writer, "  URL: %s\n", thread.URL)
+		}
+		common.PrintMoreEntries(writer, len(stats.LongestThreads))
+	}
+}
@@ -0,0 +1,61 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// ReviewDebt lists open PRs waiting on the user: reviews requested from them and their own PRs that have aged
+type ReviewDebt struct {
+	PendingReviews []PullRequest `json:"pending_reviews"`
+	AgingPRs       []PullRequest `json:"aging_prs"`
+}
+
+// CollectReviewDebt searches open PRs awaiting the user's review and authored PRs open longer than minAge.
+// Unlike the period analysis this reflects the current state, regardless of START_DATE/END_DATE.
+func (g *GitHubAnalyzer) CollectReviewDebt(writer io.Writer, minAge time.Duration) (*ReviewDebt, error) {
+	if err := g.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := g.loadConfigFiles(); err != nil {
+		return nil, err
+	}
+
+	pending, err := g.searchIssues(writer, fmt.Sprintf("review-requested:%s type:pr is:open archived:false", g.username))
+	if err != nil {
+		return nil, common.WrapError(err, "failed to search PRs awaiting review")
+	}
+	authored, err := g.searchIssues(writer, fmt.Sprintf("author:%s type:pr is:open archived:false", g.username))
+	if err != nil {
+		return nil, common.WrapError(err, "failed to search open authored PRs")
+	}
+
+	debt := &ReviewDebt{}
+	for _, pr := range g.filterIgnored(writer, pending) {
+		if !g.isBotPR(pr) {
+			debt.PendingReviews = append(debt.PendingReviews, pr)
+		}
+	}
+	for _, pr := range g.filterIgnored(writer, authored) {
+		if time.Since(pr.CreatedAt) >= minAge {
+			debt.AgingPRs = append(debt.AgingPRs, pr)
+		}
+	}
+
+	// Oldest first: they are the most overdue
+	for _, prs := range [][]PullRequest{debt.PendingReviews, debt.AgingPRs} {
+		sort.SliceStable(prs, func(i, j int) bool {
+			return prs[i].CreatedAt.Before(prs[j].CreatedAt)
+		})
+	}
+	return debt, nil
+}
+
+// RepoName returns owner/repo of a PR
+func (g *GitHubAnalyzer) RepoName(pr PullRequest) string {
+	return g.extractRepoFromURL(pr.RepositoryURL)
+}
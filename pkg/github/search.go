@@ -0,0 +1,86 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+const (
+	// searchResultLimit is the most results the search API returns for one query, whatever its total_count
+	searchResultLimit = 1000
+	searchPageSize    = 100
+)
+
+// dateRange is an inclusive range of days used in created:/author-date: qualifiers
+type dateRange struct {
+	start time.Time
+	end   time.Time
+}
+
+func (r dateRange) String() string {
+	return fmt.Sprintf("%s..%s", r.start.Format("2006-01-02"), r.end.Format("2006-01-02"))
+}
+
+// split divides the range into calendar months, or into two halves when it lies within one month.
+// A single day cannot be split and returns nil.
+func (r dateRange) split() []dateRange {
+	if !r.start.Before(r.end) {
+		return nil
+	}
+	var parts []dateRange
+	if r.start.Year() != r.end.Year() || r.start.Month() != r.end.Month() {
+		for start := r.start; !start.After(r.end); {
+			end := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, start.Location()).AddDate(0, 0, -1)
+			if end.After(r.end) {
+				end = r.end
+			}
+			parts = append(parts, dateRange{start, end})
+			start = end.AddDate(0, 0, 1)
+		}
+		return parts
+	}
+	days := int(r.end.Sub(r.start).Hours() / 24)
+	middle := r.start.AddDate(0, 0, days/2)
+	return []dateRange{{r.start, middle}, {middle.AddDate(0, 0, 1), r.end}}
+}
+
+// searchPages requests a search query page by page and passes each response body to collect, which returns the
+// total_count and the number of items on the page. It stops after the last page or at searchResultLimit, and with
+// stopOverLimit right after the first page when total_count is over the limit, so that the caller can split the query.
+// It returns total_count and the number of items fetched.
+func (g *GitHubAnalyzer) searchPages(writer io.Writer, endpoint, fullQuery string, stopOverLimit bool, collect func(body []byte) (int, int, error)) (int, int, error) {
+	total, fetched := 0, 0
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("%s/search/%s?q=%s&page=%d&per_page=%d", g.apiURL, endpoint, url.QueryEscape(fullQuery), page, searchPageSize)
+		fmt.Fprintf(writer, "Making request to GitHub API (page %d)...\n", page)
+
+		body, err := g.client.Get(apiURL, nil)
+		if err != nil {
+			return total, fetched, err
+		}
+		pageTotal, items, err := collect(body)
+		if err != nil {
+			return total, fetched, err
+		}
+		total = pageTotal
+		fetched += items
+
+		if stopOverLimit && total > searchResultLimit {
+			return total, fetched, nil
+		}
+		if items < searchPageSize || fetched >= searchResultLimit {
+			return total, fetched, nil
+		}
+	}
+}
+
+// warnTruncated records a warning when a query matched more results than the search API returned
+func (g *GitHubAnalyzer) warnTruncated(query string, total, fetched int) {
+	if total > fetched {
+		g.warnings.Add("search results", query, common.NewError("GitHub search returns at most %d results: %d of %d fetched", searchResultLimit, fetched, total))
+	}
+}
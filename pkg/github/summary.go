@@ -0,0 +1,70 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"dev-stats/pkg/common"
+)
+
+// summaryCount is a metric computed from the total_count of one search query
+type summaryCount struct {
+	id       string
+	label    string
+	endpoint string // issues or commits
+	query    string
+}
+
+// Summarize counts authored, involved, and merged PRs and authored commits from search totals (-summary-only).
+// Each count is one request for a single result, so no PR or commit is listed. ignore.yaml, bot exclusion, and
+// wildcard repository filters need the items and are not applied, so counts can be higher than in a full run.
+func (g *GitHubAnalyzer) Summarize(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := g.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	g.warnings.Reset()
+
+	dateRange := fmt.Sprintf("%s..%s", config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+	counts := []summaryCount{
+		{"github.prs_authored", "Total PRs (author)", "issues", fmt.Sprintf("author:%s type:pr created:%s", g.username, dateRange)},
+		{"github.prs_involved", "Total PRs (involves)", "issues", fmt.Sprintf("involves:%s type:pr created:%s", g.username, dateRange)},
+		{"github.prs_merged", "Authored PRs merged", "issues", fmt.Sprintf("author:%s type:pr is:merged created:%s", g.username, dateRange)},
+		{"github.commits", "Commits", "commits", fmt.Sprintf("author:%s merge:false author-date:%s", g.username, dateRange)},
+	}
+
+	fmt.Fprintf(writer, "Counting GitHub activity for user: %s (summary only)\n", g.username)
+	result := &common.AnalysisResult{
+		AnalyzerName: g.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+	}
+	for _, count := range counts {
+		total, err := g.searchTotal(count.endpoint, count.query)
+		if err != nil {
+			return nil, common.WrapError(err, "failed to count %s", count.label)
+		}
+		result.Metrics = append(result.Metrics, common.Metric{ID: count.id, Label: count.label, Value: total})
+	}
+
+	result.PrintSummary(writer)
+	result.Warnings = g.warnings.List()
+	return result, nil
+}
+
+// searchTotal returns the total_count of a search query without fetching its results
+func (g *GitHubAnalyzer) searchTotal(endpoint, query string) (int, error) {
+	apiURL := fmt.Sprintf("%s/search/%s?q=%s&per_page=1", g.apiURL, endpoint, url.QueryEscape(g.repoFilter.Qualify(query)))
+	body, err := g.client.Get(apiURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	var response struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return 0, common.WrapError(err, "failed to parse GitHub search response")
+	}
+	return response.TotalCount, nil
+}
@@ -0,0 +1,162 @@
+package google
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// driveFileIDPattern extracts the file ID from a Docs/Drive URL (/d/<id>/...)
+var driveFileIDPattern = regexp.MustCompile(`/d/([A-Za-z0-9_-]+)`)
+
+// myActivityEntry is one entry of a My Activity JSON export
+type myActivityEntry struct {
+	Title    string    `json:"title"`
+	TitleURL string    `json:"titleUrl"`
+	Time     time.Time `json:"time"`
+}
+
+// Actions of My Activity entries (the title starts with the verb), by how they count
+var (
+	driveCreateVerbs = []string{"Created", "Uploaded"}
+	driveEditVerbs   = []string{"Edited", "Renamed", "Commented on"}
+	driveViewVerbs   = []string{"Viewed", "Opened"}
+)
+
+// driveAction is one action on a file from My Activity
+type driveAction struct {
+	file GDocsFile
+	verb string // one of the verbs above
+	time time.Time
+}
+
+// readDriveActivity parses My Activity/Drive/MyActivity.json, keeping actions on Docs, Slides, and Sheets
+func readDriveActivity(name string, r io.Reader) ([]driveAction, error) {
+	var entries []myActivityEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, common.WrapError(err, "failed to parse %s", name)
+	}
+
+	var actions []driveAction
+	for _, entry := range entries {
+		verb, title := splitActivityTitle(entry.Title)
+		mimeType := mimeTypeFromURL(entry.TitleURL)
+		if verb == "" || mimeType == "" {
+			continue
+		}
+		link := strings.SplitN(entry.TitleURL, "?", 2)[0]
+		id := link
+		if match := driveFileIDPattern.FindStringSubmatch(link); match != nil {
+			id = match[1]
+		}
+		actions = append(actions, driveAction{
+			file: GDocsFile{ID: id, Name: title, MimeType: mimeType, WebViewLink: link},
+			verb: verb,
+			time: entry.Time,
+		})
+	}
+	return actions, nil
+}
+
+// splitActivityTitle splits "Edited Q3 plan" into the verb and the file name; the verb is "" for other actions
+func splitActivityTitle(title string) (verb, name string) {
+	for _, verbs := range [][]string{driveCreateVerbs, driveEditVerbs, driveViewVerbs} {
+		for _, v := range verbs {
+			if strings.HasPrefix(title, v+" ") {
+				return v, strings.TrimPrefix(title, v+" ")
+			}
+		}
+	}
+	return "", title
+}
+
+// mimeTypeFromURL returns the Docs/Slides/Sheets MIME type of a docs.google.com URL, or ""
+func mimeTypeFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host != "docs.google.com" {
+		return ""
+	}
+	switch strings.SplitN(strings.TrimPrefix(parsed.Path, "/"), "/", 2)[0] {
+	case "document":
+		return mimeDoc
+	case "presentation":
+		return mimeSlide
+	case "spreadsheets":
+		return mimeSheet
+	}
+	return ""
+}
+
+// categorizeDriveActions groups actions in the range by file: created (created in the range), updated (edited),
+// related (only viewed, title matches GOOGLE_DOCS_RELATED_NAMES), and excluded (only viewed).
+// My Activity only holds the user's own actions, so owner and last modifier are not known.
+func categorizeDriveActions(actions []driveAction, start, end time.Time, relatedKeywords []string) (created, updated, related, excluded []GDocsFile) {
+	endInclusive := end.AddDate(0, 0, 1)
+	files := make(map[string]*GDocsFile)
+	ranks := make(map[string]int) // the strongest action on each file: 0 viewed, 1 updated, 2 created
+	var order []string
+	for _, action := range actions {
+		if action.time.Before(start) || !action.time.Before(endInclusive) {
+			continue
+		}
+		file, exists := files[action.file.ID]
+		if !exists {
+			copied := action.file
+			file = &copied
+			files[action.file.ID] = file
+			order = append(order, action.file.ID)
+		}
+		rank := 0
+		switch {
+		case containsString(driveCreateVerbs, action.verb):
+			rank = 2
+			file.CreatedTime = action.time
+		case containsString(driveEditVerbs, action.verb):
+			rank = 1
+		}
+		if rank > 0 {
+			file.LastModifiedBy = "you"
+		}
+		// Files are dated by the latest change, or the latest view when they were only viewed.
+		// The first change replaces the view times; the name is the one at that time (after renames).
+		previous := ranks[action.file.ID]
+		if (rank > 0 && previous == 0) || ((rank > 0 || previous == 0) && action.time.After(file.ModifiedTime)) {
+			file.ModifiedTime = action.time
+			file.Name = action.file.Name
+		}
+		if rank > previous {
+			ranks[action.file.ID] = rank
+		}
+	}
+
+	for _, id := range order {
+		file := *files[id]
+		switch ranks[id] {
+		case 2:
+			created = append(created, file)
+		case 1:
+			updated = append(updated, file)
+		default:
+			if len(relatedKeywords) > 0 && titleMatchesKeywords(file.Name, relatedKeywords) {
+				related = append(related, file)
+			} else {
+				excluded = append(excluded, file)
+			}
+		}
+	}
+	return
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
 
 	"google.golang.org/api/drive/v3"
 )
@@ -37,7 +38,9 @@ func fileTypeLabel(mimeType string) string {
 }
 
 // GDocsAnalyzer implements the Analyzer interface for Google Workspace files (Docs, Slides, Sheets).
-type GDocsAnalyzer struct{}
+type GDocsAnalyzer struct {
+	ignoreList config.IgnoreList
+}
 
 // GDocsFile represents a single Google Docs/Drive file.
 type GDocsFile struct {
@@ -61,11 +64,65 @@ func (g *GDocsAnalyzer) GetName() string {
 	return "Google Workspace"
 }
 
+// ValidateConfig validates the required configuration.
+// A Google Takeout download (GOOGLE_TAKEOUT_PATH) is analyzed offline instead of the API.
+func (g *GDocsAnalyzer) ValidateConfig() error {
+	if TakeoutPathFromEnv() != "" {
+		return nil
+	}
+	if os.Getenv("GOOGLE_CLIENT_ID") == "" || os.Getenv("GOOGLE_CLIENT_SECRET") == "" {
+		return common.NewError("GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET environment variables are required")
+	}
+	return nil
+}
+
+// HasCachedToken returns true if an OAuth2 token is cached, so API calls will not open a browser.
+func HasCachedToken() bool {
+	_, err := loadToken(tokenFilePath())
+	return err == nil
+}
+
+// TokenFilePath returns the OAuth2 token cache path.
+func TokenFilePath() string {
+	return tokenFilePath()
+}
+
 // Analyze fetches Google Workspace files updated within config date range and prints results.
+// WhoAmI reports the Google account of the cached OAuth token (authorizing in the browser if there is none)
+func (g *GDocsAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if err := g.ValidateConfig(); err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	client, err := getHTTPClient(ctx, writer)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to authenticate with Google")
+	}
+	svc, err := newDriveService(ctx, client)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to create Drive service")
+	}
+	me, err := getMyUserInfo(svc)
+	if err != nil {
+		return nil, err
+	}
+	return &common.Identity{Source: g.GetName(), Login: me.EmailAddress, Name: me.DisplayName}, nil
+}
+
 func (g *GDocsAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := g.ValidateConfig(); err != nil {
+		return nil, err
+	}
+	if err := g.ignoreList.Reload(); err != nil {
+		return nil, err
+	}
+	if path := TakeoutPathFromEnv(); path != "" {
+		return g.analyzeTakeout(config, writer, NewTakeout(path))
+	}
+
 	ctx := context.Background()
 
-	client, err := getHTTPClient(ctx)
+	client, err := getHTTPClient(ctx, writer)
 	if err != nil {
 		return nil, common.WrapError(err, "failed to authenticate with Google")
 	}
@@ -90,6 +147,7 @@ func (g *GDocsAnalyzer) Analyze(config *common.Config, writer io.Writer) (*commo
 	if err != nil {
 		return nil, common.WrapError(err, "failed to list Drive files")
 	}
+	files = g.filterIgnored(writer, files)
 
 	relatedKeywords := relatedKeywordsFromEnv()
 
@@ -101,12 +159,12 @@ func (g *GDocsAnalyzer) Analyze(config *common.Config, writer io.Writer) (*commo
 		AnalyzerName: g.GetName(),
 		StartDate:    config.StartDate,
 		EndDate:      config.EndDate,
-		Summary: map[string]interface{}{
-			"Files created":  len(created),
-			"Files updated":  len(updated),
-			"Files related":  len(related),
-			"Files excluded": len(excluded),
-			"Total files":    len(files),
+		Metrics: []common.Metric{
+			{ID: "google.files_created", Label: "Files created", Value: len(created)},
+			{ID: "google.files_updated", Label: "Files updated", Value: len(updated)},
+			{ID: "google.files_related", Label: "Files related", Value: len(related)},
+			{ID: "google.files_excluded", Label: "Files excluded", Value: len(excluded)},
+			{ID: "google.files_total", Label: "Total files", Value: len(files)},
 		},
 		Details: map[string]interface{}{
 			"created_files":  created,
@@ -114,12 +172,25 @@ func (g *GDocsAnalyzer) Analyze(config *common.Config, writer io.Writer) (*commo
 			"related_files":  related,
 			"excluded_files": excluded,
 		},
+		Activities: buildActivities(g.GetName(), created, updated),
+		CSVTables:  csvTables(created, updated, related),
 	}
+	result.Explain("google.files_created", fileActivities(g.GetName(), "file_created", created))
+	result.Explain("google.files_updated", fileActivities(g.GetName(), "file_updated", updated))
+	result.Explain("google.files_related", fileActivities(g.GetName(), "file_related", related))
+	result.Explain("google.files_excluded", fileActivities(g.GetName(), "file_excluded", excluded))
 
 	result.PrintSummary(writer)
 	return result, nil
 }
 
+// filterIgnored drops files whose ID or URL is listed in the ignore file.
+func (g *GDocsAnalyzer) filterIgnored(writer io.Writer, files []GDocsFile) []GDocsFile {
+	return config.FilterIgnored(writer, &g.ignoreList, files, "files", func(file GDocsFile) []string {
+		return []string{file.ID, file.WebViewLink}
+	})
+}
+
 // listModifiedFiles fetches Google Workspace files modified in the given range.
 func listModifiedFiles(svc *drive.Service, start, end time.Time, writer io.Writer) ([]GDocsFile, error) {
 	startStr := start.Format(time.RFC3339)
@@ -252,6 +323,31 @@ func categorizeFiles(files []GDocsFile, start, end time.Time, me myUserInfo, rel
 	return
 }
 
+// buildActivities converts created and updated files into dated activities.
+func buildActivities(source string, created, updated []GDocsFile) []common.Activity {
+	return append(fileActivities(source, "file_created", created), fileActivities(source, "file_updated", updated)...)
+}
+
+// fileActivities converts files into activities dated by creation (file_created) or last modification.
+func fileActivities(source, kind string, files []GDocsFile) []common.Activity {
+	var activities []common.Activity
+	for _, f := range files {
+		activity := common.Activity{
+			Source: source,
+			Kind:   kind,
+			ID:     f.ID,
+			Title:  f.Name,
+			URL:    f.WebViewLink,
+			Time:   f.ModifiedTime,
+		}
+		if kind == "file_created" {
+			activity.Time = f.CreatedTime
+		}
+		activities = append(activities, activity)
+	}
+	return activities
+}
+
 // printFileResults prints the file listing to writer.
 func printFileResults(writer io.Writer, created, updated, related, excluded []GDocsFile, start, end time.Time) {
 	fmt.Fprintf(writer, "\nGoogle Workspace activity from %s to %s:\n",
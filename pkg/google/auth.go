@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
 
+	"dev-stats/pkg/common"
+
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/calendar/v3"
@@ -68,23 +71,28 @@ func saveToken(path string, tok *oauth2.Token) error {
 }
 
 // getHTTPClient returns an authenticated http.Client.
-// On first run it starts a local server to handle the OAuth2 callback automatically.
-func getHTTPClient(ctx context.Context) (*http.Client, error) {
+// On first run it starts a local server to handle the OAuth2 callback automatically; prompts go to writer.
+func getHTTPClient(ctx context.Context, writer io.Writer) (*http.Client, error) {
 	clientID := os.Getenv("GOOGLE_CLIENT_ID")
 	clientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
 	if clientID == "" || clientSecret == "" {
 		return nil, fmt.Errorf("GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET must be set")
 	}
 
+	// Token and API requests go through the shared transport like every common.HTTPClient (snapshot fixtures, bundles)
+	if transport := common.DefaultTransport(); transport != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+	}
+
 	tokPath := tokenFilePath()
 	tok, err := loadToken(tokPath)
 	if err != nil {
-		tok, err = runLocalhostAuth(ctx, clientID, clientSecret)
+		tok, err = runLocalhostAuth(ctx, clientID, clientSecret, writer)
 		if err != nil {
 			return nil, fmt.Errorf("OAuth2 auth failed: %w", err)
 		}
 		if err := saveToken(tokPath, tok); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to save token to %s: %v\n", tokPath, err)
+			fmt.Fprintf(writer, "Warning: failed to save token to %s: %v\n", tokPath, err)
 		}
 	}
 
@@ -93,7 +101,7 @@ func getHTTPClient(ctx context.Context) (*http.Client, error) {
 }
 
 // runLocalhostAuth runs OAuth2 flow using a temporary localhost HTTP server.
-func runLocalhostAuth(ctx context.Context, clientID, clientSecret string) (*oauth2.Token, error) {
+func runLocalhostAuth(ctx context.Context, clientID, clientSecret string, writer io.Writer) (*oauth2.Token, error) {
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen on localhost: %w", err)
@@ -136,10 +144,10 @@ func runLocalhostAuth(ctx context.Context, clientID, clientSecret string) (*oaut
 		}
 	}()
 
-	fmt.Println("Opening browser for Google authentication...")
+	fmt.Fprintln(writer, "Opening browser for Google authentication...")
 	if err := openBrowser(authURL); err != nil {
-		fmt.Println("Could not open browser automatically. Please open the following URL manually:")
-		fmt.Println(authURL)
+		fmt.Fprintln(writer, "Could not open browser automatically. Please open the following URL manually:")
+		fmt.Fprintln(writer, authURL)
 	}
 
 	var code string
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 	"time"
 
 	"google.golang.org/api/calendar/v3"
@@ -12,18 +14,45 @@ import (
 
 // CalendarEvent is a calendar event fetched from Google Calendar API.
 type CalendarEvent struct {
-	ID       string
-	Summary  string
-	Start    time.Time
-	End      time.Time
-	IsAllDay bool
+	ID        string
+	ICalUID   string // UID of the event in ICS exports, shared by the occurrences of a recurring event
+	Calendar  string // name of the calendar the event was fetched from (its ID when it has none)
+	Summary   string
+	Start     time.Time
+	End       time.Time
+	IsAllDay  bool
+	Organizer string // email
+	Attendees []CalendarAttendee
 }
 
-// FetchCalendarEvents returns events from all Google Calendars in the given date range.
-func FetchCalendarEvents(start, end time.Time, writer io.Writer) ([]CalendarEvent, error) {
+// CalendarAttendee is a guest of an event
+type CalendarAttendee struct {
+	Email          string
+	ResponseStatus string // accepted, declined, tentative, or needsAction
+	Resource       bool   // a room or equipment
+}
+
+// CalendarIDsFromEnv returns GOOGLE_CALENDAR_IDS, comma-separated, or only the primary calendar. Shared and team
+// calendars are left out unless listed, since their events are mostly not mine.
+func CalendarIDsFromEnv() []string {
+	var ids []string
+	for _, id := range strings.Split(os.Getenv("GOOGLE_CALENDAR_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return []string{"primary"}
+	}
+	return ids
+}
+
+// FetchCalendarEvents returns events of the calendars (IDs as in CalendarIDsFromEnv) in the given date range,
+// recurring events expanded into their occurrences. An event on several calendars is returned once.
+func FetchCalendarEvents(start, end time.Time, calendarIDs []string, writer io.Writer) ([]CalendarEvent, error) {
 	ctx := context.Background()
 
-	client, err := getHTTPClient(ctx)
+	client, err := getHTTPClient(ctx, writer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to authenticate with Google: %w", err)
 	}
@@ -39,8 +68,7 @@ func FetchCalendarEvents(start, end time.Time, writer io.Writer) ([]CalendarEven
 	seen := make(map[string]bool)
 	var events []CalendarEvent
 
-	// Fetch only from primary calendar to avoid picking up shared/team calendars
-	for _, calID := range []string{"primary"} {
+	for _, calID := range calendarIDs {
 		fmt.Fprintf(writer, "Fetching events from calendar: %s\n", calID)
 
 		req := svc.Events.List(calID).
@@ -53,8 +81,7 @@ func FetchCalendarEvents(start, end time.Time, writer io.Writer) ([]CalendarEven
 		for {
 			resp, err := req.Do()
 			if err != nil {
-				fmt.Fprintf(writer, "  Warning: failed to fetch events from %s: %v\n", calID, err)
-				break
+				return nil, fmt.Errorf("failed to fetch events from calendar %s: %w", calID, err)
 			}
 
 			for _, item := range resp.Items {
@@ -67,6 +94,10 @@ func FetchCalendarEvents(start, end time.Time, writer io.Writer) ([]CalendarEven
 				if !ok {
 					continue
 				}
+				ev.Calendar = resp.Summary
+				if ev.Calendar == "" {
+					ev.Calendar = calID
+				}
 				events = append(events, ev)
 			}
 
@@ -85,8 +116,19 @@ func FetchCalendarEvents(start, end time.Time, writer io.Writer) ([]CalendarEven
 func convertEvent(item *calendar.Event) (CalendarEvent, bool) {
 	ev := CalendarEvent{
 		ID:      item.Id,
+		ICalUID: item.ICalUID,
 		Summary: item.Summary,
 	}
+	if item.Organizer != nil {
+		ev.Organizer = item.Organizer.Email
+	}
+	for _, attendee := range item.Attendees {
+		ev.Attendees = append(ev.Attendees, CalendarAttendee{
+			Email:          attendee.Email,
+			ResponseStatus: attendee.ResponseStatus,
+			Resource:       attendee.Resource,
+		})
+	}
 
 	if item.Start == nil {
 		return ev, false
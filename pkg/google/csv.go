@@ -0,0 +1,58 @@
+package google
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// fileCSVRow is one file in google-workspace-files.csv
+type fileCSVRow struct {
+	Relation       string    `csv:"relation"`
+	Name           string    `csv:"name"`
+	Type           string    `csv:"type"`
+	Owner          string    `csv:"owner"`
+	LastModifiedBy string    `csv:"last_modified_by"`
+	CreatedTime    time.Time `csv:"created_time"`
+	ModifiedTime   time.Time `csv:"modified_time"`
+	URL            string    `csv:"url"`
+}
+
+// csvTables lists created, updated, and related files
+func csvTables(created, updated, related []GDocsFile) []common.CSVTable {
+	var rows []fileCSVRow
+	for _, group := range []struct {
+		relation string
+		files    []GDocsFile
+	}{{"created", created}, {"updated", updated}, {"related", related}} {
+		for _, file := range group.files {
+			rows = append(rows, fileCSVRow{
+				Relation:       group.relation,
+				Name:           file.Name,
+				Type:           fileTypeLabel(file.MimeType),
+				Owner:          file.OwnerEmail,
+				LastModifiedBy: file.LastModifiedBy,
+				CreatedTime:    file.CreatedTime,
+				ModifiedTime:   file.ModifiedTime,
+				URL:            file.WebViewLink,
+			})
+		}
+	}
+	return []common.CSVTable{common.NewCSVTable("files", rows)}
+}
+
+// emailCSVRow is one sent email in google-workspace-emails.csv
+type emailCSVRow struct {
+	Date      time.Time `csv:"date"`
+	Subject   string    `csv:"subject"`
+	MessageID string    `csv:"message_id"`
+}
+
+// emailCSVTable lists the emails sent, read from a Takeout mbox
+func emailCSVTable(emails []Email) common.CSVTable {
+	var rows []emailCSVRow
+	for _, email := range emails {
+		rows = append(rows, emailCSVRow{Date: email.Date, Subject: email.Subject, MessageID: email.MessageID})
+	}
+	return common.NewCSVTable("emails", rows)
+}
@@ -34,7 +34,7 @@ func NewGDocsDownloader() *GDocsDownloader {
 func (d *GDocsDownloader) DownloadAll(start, end time.Time, writer io.Writer) error {
 	ctx := context.Background()
 
-	client, err := getHTTPClient(ctx)
+	client, err := getHTTPClient(ctx, writer)
 	if err != nil {
 		return common.WrapError(err, "failed to authenticate with Google")
 	}
@@ -222,7 +222,7 @@ func revisionCachePath(outDir string) string {
 
 func loadRevisionCache(outDir string) revisionCache {
 	cache := make(revisionCache)
-	data, err := os.ReadFile(revisionCachePath(outDir))
+	data, err := common.ReadProtectedFile(revisionCachePath(outDir))
 	if err != nil {
 		return cache
 	}
@@ -239,7 +239,7 @@ func saveRevisionCache(outDir string, cache revisionCache) {
 	if err != nil {
 		return
 	}
-	_ = os.WriteFile(revisionCachePath(outDir), data, 0644)
+	_ = common.WriteProtectedFile(revisionCachePath(outDir), data)
 }
 
 // hasMyRevision returns true if the file has a revision made by the given email address.
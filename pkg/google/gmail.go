@@ -0,0 +1,133 @@
+package google
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// Email is the metadata of one Gmail message from a Takeout mbox; bodies are never kept
+type Email struct {
+	MessageID string    `json:"message_id"`
+	Subject   string    `json:"subject"`
+	Date      time.Time `json:"date"`
+	Labels    []string  `json:"labels"`
+}
+
+// HasLabel reports whether Gmail filed the message under label
+func (e Email) HasLabel(label string) bool {
+	for _, l := range e.Labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSent reports whether the user sent the message
+func (e Email) IsSent() bool {
+	return e.HasLabel("Sent")
+}
+
+// IsReceived reports whether the message was received: not sent, and not spam, trash, a draft, or a chat
+func (e Email) IsReceived() bool {
+	for _, label := range []string{"Sent", "Spam", "Trash", "Drafts", "Chat"} {
+		if e.HasLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// readMboxHeaders reads the headers of every message in an mbox, skipping bodies, and keeps the messages
+// dated within the range. Mailboxes of many gigabytes are read line by line.
+func readMboxHeaders(name string, r io.Reader, start, end time.Time) ([]Email, int, error) {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	decoder := new(mime.WordDecoder)
+	endInclusive := end.AddDate(0, 0, 1)
+
+	var emails []Email
+	parsed := 0
+	var headers []string
+	inHeaders, afterBlank := false, true
+
+	finishHeaders := func() {
+		parsed++
+		email := parseEmailHeaders(headers, decoder)
+		if !email.Date.IsZero() && !email.Date.Before(start) && email.Date.Before(endInclusive) {
+			emails = append(emails, email)
+		}
+		headers = nil
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, parsed, common.WrapError(err, "failed to read %s", name)
+		}
+		if line == "" && err == io.EOF {
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case afterBlank && strings.HasPrefix(line, "From "):
+			if inHeaders {
+				finishHeaders()
+			}
+			inHeaders = true
+		case inHeaders && line == "":
+			finishHeaders()
+			inHeaders = false
+		case inHeaders && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(headers) > 0:
+			headers[len(headers)-1] += " " + strings.TrimSpace(line)
+		case inHeaders:
+			headers = append(headers, line)
+		}
+		afterBlank = line == ""
+		if err == io.EOF {
+			break
+		}
+	}
+	if inHeaders {
+		finishHeaders()
+	}
+	return emails, parsed, nil
+}
+
+// parseEmailHeaders extracts the metadata from unfolded header lines
+func parseEmailHeaders(lines []string, decoder *mime.WordDecoder) Email {
+	var email Email
+	for _, line := range lines {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(key) {
+		case "message-id":
+			email.MessageID = value
+		case "subject":
+			if decoded, err := decoder.DecodeHeader(value); err == nil {
+				value = decoded
+			}
+			email.Subject = value
+		case "date":
+			if date, err := mail.ParseDate(value); err == nil {
+				email.Date = date
+			}
+		case "x-gmail-labels":
+			for _, label := range strings.Split(value, ",") {
+				if label = strings.TrimSpace(label); label != "" {
+					email.Labels = append(email.Labels, label)
+				}
+			}
+		}
+	}
+	return email
+}
@@ -0,0 +1,310 @@
+package google
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"dev-stats/pkg/common"
+)
+
+// Kinds of Takeout files that feed an analyzer
+const (
+	TakeoutCalendar          = "Calendar"              // Calendar/*.ics, read by the calendar analyzer
+	TakeoutDriveActivity     = "Drive activity"        // My Activity/Drive/MyActivity.json
+	TakeoutDriveActivityHTML = "Drive activity (HTML)" // the default My Activity format, which is not supported
+	TakeoutGmail             = "Gmail"                 // Mail/*.mbox
+)
+
+// Takeout is a Google Takeout download (GOOGLE_TAKEOUT_PATH): an extracted directory, a .zip or .tgz,
+// or a directory holding the parts of a split download
+type Takeout struct {
+	path string
+}
+
+// TakeoutPathFromEnv returns GOOGLE_TAKEOUT_PATH
+func TakeoutPathFromEnv() string {
+	return os.Getenv("GOOGLE_TAKEOUT_PATH")
+}
+
+// NewTakeout returns the Takeout at path
+func NewTakeout(path string) *Takeout {
+	return &Takeout{path: path}
+}
+
+// Path returns the location of the download
+func (t *Takeout) Path() string {
+	return t.path
+}
+
+// TakeoutKind returns which kind of data a file in a Takeout holds, or "" for files no analyzer reads.
+// Folder names are the ones Takeout uses for accounts in English.
+func TakeoutKind(name string) string {
+	name = filepath.ToSlash(name)
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(name, "/Calendar/") && strings.HasSuffix(lower, ".ics"):
+		return TakeoutCalendar
+	case strings.Contains(name, "/My Activity/Drive/") && strings.HasSuffix(lower, ".json"):
+		return TakeoutDriveActivity
+	case strings.Contains(name, "/My Activity/Drive/") && strings.HasSuffix(lower, ".html"):
+		return TakeoutDriveActivityHTML
+	case strings.Contains(name, "/Mail/") && strings.HasSuffix(lower, ".mbox"):
+		return TakeoutGmail
+	}
+	return ""
+}
+
+// Detect counts the files of each kind in the download
+func (t *Takeout) Detect() (map[string]int, error) {
+	counts := make(map[string]int)
+	err := t.Walk(func(kind string) bool {
+		counts[kind]++
+		return false
+	}, nil)
+	return counts, err
+}
+
+// DescribeKinds formats detected kinds as "Calendar (2 files), Gmail (1 file)"
+func DescribeKinds(counts map[string]int) string {
+	var kinds []string
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var parts []string
+	for _, kind := range kinds {
+		unit := "files"
+		if counts[kind] == 1 {
+			unit = "file"
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d %s)", kind, counts[kind], unit))
+	}
+	if len(parts) == 0 {
+		return "no Calendar, Drive activity, or Gmail data"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Walk calls read with the contents of every file whose kind is accepted by want, in archive order.
+// Archives inside a directory are opened too, so the parts of a split download can be kept side by side.
+func (t *Takeout) Walk(want func(kind string) bool, read func(name, kind string, r io.Reader) error) error {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return common.WrapError(err, "GOOGLE_TAKEOUT_PATH is not readable")
+	}
+	if !info.IsDir() {
+		return t.walkArchive(t.path, want, read)
+	}
+	return filepath.WalkDir(t.path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if isTakeoutArchive(path) {
+			return t.walkArchive(path, want, read)
+		}
+		// Match on the path below the download so that folders above it don't count
+		rel, _ := filepath.Rel(t.path, path)
+		kind := TakeoutKind("/" + filepath.ToSlash(rel))
+		if kind == "" || !want(kind) || read == nil {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return read(path, kind, file)
+	})
+}
+
+// isTakeoutArchive reports whether path is one of the archive formats Takeout offers
+func isTakeoutArchive(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".zip") || strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".tar.gz")
+}
+
+func (t *Takeout) walkArchive(path string, want func(kind string) bool, read func(name, kind string, r io.Reader) error) error {
+	if strings.HasSuffix(strings.ToLower(path), ".zip") {
+		return walkZip(path, want, read)
+	}
+	if isTakeoutArchive(path) {
+		return walkTarGz(path, want, read)
+	}
+	return common.NewError("unsupported Takeout archive %s (expected a directory, .zip, or .tgz)", path)
+}
+
+func walkZip(path string, want func(kind string) bool, read func(name, kind string, r io.Reader) error) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return common.WrapError(err, "failed to open %s", path)
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		kind := TakeoutKind("/" + file.Name)
+		if file.FileInfo().IsDir() || kind == "" || !want(kind) || read == nil {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return common.WrapError(err, "failed to read %s in %s", file.Name, path)
+		}
+		err = read(path+":"+file.Name, kind, reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkTarGz(path string, want func(kind string) bool, read func(name, kind string, r io.Reader) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return common.WrapError(err, "failed to open %s", path)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return common.WrapError(err, "failed to read %s", path)
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return common.WrapError(err, "failed to read %s", path)
+		}
+		kind := TakeoutKind("/" + header.Name)
+		if header.Typeflag != tar.TypeReg || kind == "" || !want(kind) || read == nil {
+			continue
+		}
+		if err := read(path+":"+header.Name, kind, reader); err != nil {
+			return err
+		}
+	}
+}
+
+// analyzeTakeout reports Drive activity (My Activity JSON) and Gmail metadata (mbox headers) from a Takeout download
+func (g *GDocsAnalyzer) analyzeTakeout(config *common.Config, writer io.Writer, takeout *Takeout) (*common.AnalysisResult, error) {
+	fmt.Fprintf(writer, "Reading Google Takeout %s...\n", takeout.Path())
+	counts, err := takeout.Detect()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(writer, "Takeout: %s\n", DescribeKinds(counts))
+	if counts[TakeoutDriveActivity] == 0 && counts[TakeoutDriveActivityHTML] > 0 {
+		fmt.Fprintln(writer, "Warning: My Activity was exported as HTML; choose the JSON format in Takeout to analyze Drive activity")
+	}
+
+	var actions []driveAction
+	var emails []Email
+	err = takeout.Walk(func(kind string) bool {
+		return kind == TakeoutDriveActivity || kind == TakeoutGmail
+	}, func(name, kind string, r io.Reader) error {
+		if kind == TakeoutDriveActivity {
+			fileActions, err := readDriveActivity(name, r)
+			actions = append(actions, fileActions...)
+			return err
+		}
+		mailboxEmails, parsed, err := readMboxHeaders(name, r, config.StartDate, config.EndDate)
+		fmt.Fprintf(writer, "Read %d messages from %s (%d in date range)\n", parsed, name, len(mailboxEmails))
+		emails = append(emails, mailboxEmails...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []driveAction
+	for _, action := range actions {
+		if !g.ignoreList.Contains(action.file.ID, action.file.WebViewLink) {
+			kept = append(kept, action)
+		}
+	}
+	created, updated, related, excluded := categorizeDriveActions(kept, config.StartDate, config.EndDate, relatedKeywordsFromEnv())
+	printFileResults(writer, created, updated, related, excluded, config.StartDate, config.EndDate)
+
+	var sent []Email
+	received := 0
+	for _, email := range emails {
+		if email.IsSent() {
+			sent = append(sent, email)
+		} else if email.IsReceived() {
+			received++
+		}
+	}
+	sort.SliceStable(sent, func(i, j int) bool { return sent[i].Date.Before(sent[j].Date) })
+
+	result := &common.AnalysisResult{
+		AnalyzerName: g.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "google.files_created", Label: "Files created", Value: len(created)},
+			{ID: "google.files_updated", Label: "Files updated", Value: len(updated)},
+			{ID: "google.files_related", Label: "Files related", Value: len(related)},
+			{ID: "google.files_excluded", Label: "Files excluded", Value: len(excluded)},
+			{ID: "google.files_total", Label: "Total files", Value: len(created) + len(updated) + len(related) + len(excluded)},
+		},
+		Details: map[string]interface{}{
+			"created_files":  created,
+			"updated_files":  updated,
+			"related_files":  related,
+			"excluded_files": excluded,
+			"sent_emails":    sent,
+		},
+		Activities: buildActivities(g.GetName(), created, updated),
+		CSVTables:  csvTables(created, updated, related),
+	}
+	result.Explain("google.files_created", fileActivities(g.GetName(), "file_created", created))
+	result.Explain("google.files_updated", fileActivities(g.GetName(), "file_updated", updated))
+	result.Explain("google.files_related", fileActivities(g.GetName(), "file_related", related))
+	result.Explain("google.files_excluded", fileActivities(g.GetName(), "file_excluded", excluded))
+
+	if counts[TakeoutGmail] > 0 {
+		fmt.Fprintf(writer, "\nEmails you sent (%d):\n", len(sent))
+		for _, email := range sent {
+			fmt.Fprintf(writer, "- %s: %s\n", email.Date.Local().Format("2006-01-02 15:04"), email.Subject)
+		}
+		result.Metrics = append(result.Metrics,
+			common.Metric{ID: "google.emails_sent", Label: "Emails sent", Value: len(sent)},
+			common.Metric{ID: "google.emails_received", Label: "Emails received", Value: received},
+		)
+		emailActivities := sentEmailActivities(g.GetName(), sent)
+		result.Activities = append(result.Activities, emailActivities...)
+		result.Explain("google.emails_sent", emailActivities)
+		result.CSVTables = append(result.CSVTables, emailCSVTable(sent))
+	}
+
+	result.PrintSummary(writer)
+	return result, nil
+}
+
+// sentEmailActivities converts sent emails into dated activities
+func sentEmailActivities(source string, emails []Email) []common.Activity {
+	var activities []common.Activity
+	for _, email := range emails {
+		activities = append(activities, common.Activity{
+			Source: source,
+			Kind:   "email_sent",
+			ID:     email.MessageID,
+			Title:  email.Subject,
+			Time:   email.Date,
+		})
+	}
+	return activities
+}
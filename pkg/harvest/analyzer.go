@@ -0,0 +1,377 @@
+package harvest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+const harvestAPIURL = "https://api.harvestapp.com/v2"
+
+// pageSize is the page size of time entry lists (Harvest allows up to 2000)
+const pageSize = 2000
+
+// HarvestAnalyzer implements the Analyzer interface for hours tracked in Harvest
+type HarvestAnalyzer struct {
+	token      string
+	accountID  string
+	client     *common.HTTPClient
+	ignoreList config.IgnoreList
+}
+
+// TimeEntry is time the user tracked on a project task.
+// Hours are the rounded hours, which are what Harvest reports and invoices show.
+type TimeEntry struct {
+	ID       int64     `json:"id"`
+	Date     time.Time `json:"date"`
+	Hours    float64   `json:"hours"`
+	Billable bool      `json:"billable"`
+	Invoiced bool      `json:"invoiced"`
+	Client   string    `json:"client"`
+	Project  string    `json:"project"`
+	Task     string    `json:"task"`
+	Notes    string    `json:"notes,omitempty"`
+	URL      string    `json:"url,omitempty"` // linked issue or PR (external reference), if any
+}
+
+// Duration returns the tracked time
+func (e TimeEntry) Duration() time.Duration {
+	return time.Duration(e.Hours * float64(time.Hour))
+}
+
+// ProjectHours totals the hours of one client project
+type ProjectHours struct {
+	Client      string        `json:"client"`
+	Project     string        `json:"project"`
+	Billable    time.Duration `json:"billable"`
+	NonBillable time.Duration `json:"non_billable"`
+	Invoiced    time.Duration `json:"invoiced"`
+}
+
+// Total returns the billable and non-billable hours of the project
+func (p ProjectHours) Total() time.Duration {
+	return p.Billable + p.NonBillable
+}
+
+// currentUser is the /users/me response
+type currentUser struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+// timeEntriesResponse is the /time_entries response
+type timeEntriesResponse struct {
+	TimeEntries []struct {
+		ID           int64   `json:"id"`
+		SpentDate    string  `json:"spent_date"`
+		StartedTime  string  `json:"started_time"`
+		Hours        float64 `json:"hours"`
+		RoundedHours float64 `json:"rounded_hours"`
+		Notes        string  `json:"notes"`
+		Billable     bool    `json:"billable"`
+		IsBilled     bool    `json:"is_billed"`
+		Client       struct {
+			Name string `json:"name"`
+		} `json:"client"`
+		Project struct {
+			Name string `json:"name"`
+		} `json:"project"`
+		Task struct {
+			Name string `json:"name"`
+		} `json:"task"`
+		ExternalReference *struct {
+			Permalink string `json:"permalink"`
+		} `json:"external_reference"`
+	} `json:"time_entries"`
+	NextPage *int `json:"next_page"`
+}
+
+// NewHarvestAnalyzer creates a new Harvest analyzer
+func NewHarvestAnalyzer() *HarvestAnalyzer {
+	token := os.Getenv("HARVEST_ACCESS_TOKEN")
+	accountID := os.Getenv("HARVEST_ACCOUNT_ID")
+
+	client := common.NewHTTPClient()
+	client.SetHeader("Authorization", "Bearer "+token)
+	client.SetHeader("Harvest-Account-Id", accountID)
+	client.SetHeader("User-Agent", "dev-stats")
+
+	return &HarvestAnalyzer{
+		token:     token,
+		accountID: accountID,
+		client:    client,
+	}
+}
+
+// GetName returns the analyzer name
+func (h *HarvestAnalyzer) GetName() string {
+	return "Harvest"
+}
+
+// ValidateConfig validates the required configuration
+func (h *HarvestAnalyzer) ValidateConfig(writer io.Writer) error {
+	if h.token == "" || h.accountID == "" {
+		return common.NewError("HARVEST_ACCESS_TOKEN and HARVEST_ACCOUNT_ID environment variables are required")
+	}
+	if _, err := h.getCurrentUser(); err != nil {
+		return common.WrapError(err, "failed to access Harvest API (check HARVEST_ACCESS_TOKEN and HARVEST_ACCOUNT_ID)")
+	}
+	fmt.Fprintln(writer, "✓ Harvest credentials are valid")
+	return nil
+}
+
+// WhoAmI reports the user behind HARVEST_ACCESS_TOKEN
+func (h *HarvestAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if h.token == "" || h.accountID == "" {
+		return nil, common.NewError("HARVEST_ACCESS_TOKEN and HARVEST_ACCOUNT_ID environment variables are required")
+	}
+	user, err := h.getCurrentUser()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to access Harvest API (check HARVEST_ACCESS_TOKEN and HARVEST_ACCOUNT_ID)")
+	}
+	return &common.Identity{
+		Source: h.GetName(),
+		ID:     strconv.FormatInt(user.ID, 10),
+		Login:  user.Email,
+		Name:   strings.TrimSpace(user.FirstName + " " + user.LastName),
+		Note:   "account " + h.accountID,
+	}, nil
+}
+
+// Analyze reports billable and non-billable hours per client project
+func (h *HarvestAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := h.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := h.ignoreList.Reload(); err != nil {
+		return nil, err
+	}
+
+	user, err := h.getCurrentUser()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get Harvest user")
+	}
+
+	fmt.Fprintf(writer, "Fetching Harvest time entries from %s to %s...\n",
+		config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+	entries, err := h.getTimeEntries(user.ID, config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get time entries")
+	}
+	entries = h.filterIgnored(writer, entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+
+	var billable, nonBillable, invoiced time.Duration
+	byProject := make(map[string]*ProjectHours)
+	var projectKeys []string
+	for _, entry := range entries {
+		key := entry.Client + "\x00" + entry.Project
+		project, exists := byProject[key]
+		if !exists {
+			project = &ProjectHours{Client: entry.Client, Project: entry.Project}
+			byProject[key] = project
+			projectKeys = append(projectKeys, key)
+		}
+		if entry.Billable {
+			project.Billable += entry.Duration()
+			billable += entry.Duration()
+		} else {
+			project.NonBillable += entry.Duration()
+			nonBillable += entry.Duration()
+		}
+		if entry.Invoiced {
+			project.Invoiced += entry.Duration()
+			invoiced += entry.Duration()
+		}
+	}
+
+	var projects []ProjectHours
+	for _, key := range projectKeys {
+		projects = append(projects, *byProject[key])
+	}
+	sort.SliceStable(projects, func(i, j int) bool {
+		if projects[i].Total() != projects[j].Total() {
+			return projects[i].Total() > projects[j].Total()
+		}
+		if projects[i].Client != projects[j].Client {
+			return projects[i].Client < projects[j].Client
+		}
+		return projects[i].Project < projects[j].Project
+	})
+
+	result := &common.AnalysisResult{
+		AnalyzerName: h.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "harvest.hours", Label: "Time tracked", Value: billable + nonBillable},
+			{ID: "harvest.billable_hours", Label: "Billable", Value: billable},
+			{ID: "harvest.non_billable_hours", Label: "Non-billable", Value: nonBillable},
+			{ID: "harvest.invoiced_hours", Label: "Invoiced", Value: invoiced},
+			{ID: "harvest.time_entries", Label: "Time entries", Value: len(entries)},
+			{ID: "harvest.projects", Label: "Client projects", Value: len(projects), Snapshot: true},
+		},
+		Details: map[string]interface{}{
+			"time_entries": entries,
+			"by_project":   projects,
+		},
+		Activities: h.buildActivities(entries),
+		CSVTables:  h.csvTables(entries, projects),
+	}
+	result.Explain("harvest.hours", result.Activities)
+	result.Explain("harvest.time_entries", result.Activities)
+
+	h.printResults(writer, result, entries, projects)
+	return result, nil
+}
+
+// getCurrentUser returns the user behind the access token
+func (h *HarvestAnalyzer) getCurrentUser() (*currentUser, error) {
+	body, err := h.client.Get(harvestAPIURL+"/users/me", nil)
+	if err != nil {
+		return nil, err
+	}
+	var user currentUser
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, common.WrapError(err, "failed to parse Harvest user")
+	}
+	return &user, nil
+}
+
+// getTimeEntries lists the user's time entries spent in the period, following page pagination
+func (h *HarvestAnalyzer) getTimeEntries(userID int64, startDate, endDate time.Time) ([]TimeEntry, error) {
+	var entries []TimeEntry
+	for page := 1; ; {
+		params := url.Values{}
+		params.Set("user_id", strconv.FormatInt(userID, 10))
+		params.Set("from", startDate.Format("2006-01-02"))
+		params.Set("to", endDate.Format("2006-01-02"))
+		params.Set("page", strconv.Itoa(page))
+		params.Set("per_page", strconv.Itoa(pageSize))
+
+		body, err := h.client.Get(fmt.Sprintf("%s/time_entries?%s", harvestAPIURL, params.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		var response timeEntriesResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Harvest time entries")
+		}
+
+		for _, item := range response.TimeEntries {
+			date, err := time.ParseInLocation("2006-01-02", item.SpentDate, time.Local)
+			if err != nil {
+				continue
+			}
+			// started_time is only set when the account tracks start and end times (e.g. "9:30am")
+			if started, err := time.Parse("3:04pm", item.StartedTime); err == nil {
+				date = date.Add(time.Duration(started.Hour())*time.Hour + time.Duration(started.Minute())*time.Minute)
+			}
+			hours := item.RoundedHours
+			if hours == 0 {
+				hours = item.Hours
+			}
+			entry := TimeEntry{
+				ID:       item.ID,
+				Date:     date,
+				Hours:    hours,
+				Billable: item.Billable,
+				Invoiced: item.IsBilled,
+				Client:   item.Client.Name,
+				Project:  item.Project.Name,
+				Task:     item.Task.Name,
+				Notes:    item.Notes,
+			}
+			if item.ExternalReference != nil {
+				entry.URL = item.ExternalReference.Permalink
+			}
+			entries = append(entries, entry)
+		}
+
+		if response.NextPage == nil {
+			break
+		}
+		page = *response.NextPage
+	}
+	return entries, nil
+}
+
+// filterIgnored drops time entries listed in the ignore file by ID or linked URL
+func (h *HarvestAnalyzer) filterIgnored(writer io.Writer, entries []TimeEntry) []TimeEntry {
+	return config.FilterIgnored(writer, &h.ignoreList, entries, "time entries", func(entry TimeEntry) []string {
+		return []string{strconv.FormatInt(entry.ID, 10), entry.URL}
+	})
+}
+
+// buildActivities converts time entries into worklog activities with the tracked duration.
+// Titles include the notes so that issue keys mentioned there link the entry to the same work item.
+func (h *HarvestAnalyzer) buildActivities(entries []TimeEntry) []common.Activity {
+	var activities []common.Activity
+	for _, entry := range entries {
+		title := projectName(entry.Client, entry.Project) + ": " + entry.Task
+		if entry.Notes != "" {
+			title += " - " + entry.Notes
+		}
+		activities = append(activities, common.Activity{
+			Source:   h.GetName(),
+			Kind:     common.ActivityKindWorklog,
+			ID:       strconv.FormatInt(entry.ID, 10),
+			Title:    title,
+			URL:      entry.URL,
+			Time:     entry.Date,
+			Duration: entry.Duration(),
+			Project:  entry.Project,
+		})
+	}
+	return activities
+}
+
+// projectName returns "Client / Project", or the project alone for internal projects without a client
+func projectName(client, project string) string {
+	if client == "" {
+		return project
+	}
+	return client + " / " + project
+}
+
+func (h *HarvestAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, entries []TimeEntry, projects []ProjectHours) {
+	fmt.Fprintf(writer, "\nTime entries from %s to %s (%d):\n",
+		result.StartDate.Format("2006-01-02"), result.EndDate.Format("2006-01-02"), len(entries))
+	for _, entry := range entries {
+		line := fmt.Sprintf("- %s: %s: %s (%s", entry.Date.Format("2006-01-02"), projectName(entry.Client, entry.Project), entry.Task,
+			common.FormatDuration(entry.Duration()))
+		if !entry.Billable {
+			line += ", non-billable"
+		}
+		if entry.Invoiced {
+			line += ", invoiced"
+		}
+		line += ")"
+		if entry.Notes != "" {
+			line += " - " + entry.Notes
+		}
+		fmt.Fprintln(writer, line)
+	}
+
+	result.PrintSummary(writer)
+
+	fmt.Fprintln(writer, "\nHours per client project (billable / non-billable / invoiced):")
+	for _, project := range projects[:common.RankingLimit(len(projects))] {
+		fmt.Fprintf(writer, "- %s: %.2f / %.2f / %.2f\n", projectName(project.Client, project.Project),
+			project.Billable.Hours(), project.NonBillable.Hours(), project.Invoiced.Hours())
+	}
+	common.PrintMoreEntries(writer, len(projects))
+}
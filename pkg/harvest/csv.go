@@ -0,0 +1,56 @@
+package harvest
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// timeEntryCSVRow is one time entry in harvest-entries.csv
+type timeEntryCSVRow struct {
+	Date     time.Time     `csv:"date"`
+	Client   string        `csv:"client"`
+	Project  string        `csv:"project"`
+	Task     string        `csv:"task"`
+	Duration time.Duration `csv:"hours"`
+	Billable bool          `csv:"billable"`
+	Invoiced bool          `csv:"invoiced"`
+	Notes    string        `csv:"notes"`
+	URL      string        `csv:"url"`
+}
+
+// projectCSVRow is one client project in harvest-projects.csv
+type projectCSVRow struct {
+	Client      string        `csv:"client"`
+	Project     string        `csv:"project"`
+	Billable    time.Duration `csv:"billable_hours"`
+	NonBillable time.Duration `csv:"non_billable_hours"`
+	Invoiced    time.Duration `csv:"invoiced_hours"`
+}
+
+// csvTables lists the time entries and the hours per client project
+func (h *HarvestAnalyzer) csvTables(entries []TimeEntry, projects []ProjectHours) []common.CSVTable {
+	var entryRows []timeEntryCSVRow
+	for _, entry := range entries {
+		entryRows = append(entryRows, timeEntryCSVRow{
+			Date:     entry.Date,
+			Client:   entry.Client,
+			Project:  entry.Project,
+			Task:     entry.Task,
+			Duration: entry.Duration(),
+			Billable: entry.Billable,
+			Invoiced: entry.Invoiced,
+			Notes:    entry.Notes,
+			URL:      entry.URL,
+		})
+	}
+
+	var projectRows []projectCSVRow
+	for _, project := range projects {
+		projectRows = append(projectRows, projectCSVRow(project))
+	}
+	return []common.CSVTable{
+		common.NewCSVTable("entries", entryRows),
+		common.NewCSVTable("projects", projectRows),
+	}
+}
@@ -0,0 +1,203 @@
+package ingest
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+// WebhookAnalyzer reports the events received by `dev-stats ingest` from the local store, so that a period is
+// counted without fetching it from the APIs. Only my events count: those of GITHUB_USERNAME and, per Backlog
+// profile, of BACKLOG_<PROFILE>_USER_ID.
+type WebhookAnalyzer struct {
+	store    *Store
+	username string
+}
+
+// NewWebhookAnalyzer creates an analyzer over the default event store
+func NewWebhookAnalyzer() *WebhookAnalyzer {
+	return &WebhookAnalyzer{store: NewStore(""), username: os.Getenv("GITHUB_USERNAME")}
+}
+
+// GetName returns the analyzer name
+func (a *WebhookAnalyzer) GetName() string {
+	return "Webhooks"
+}
+
+// ValidateConfig validates the required configuration
+func (a *WebhookAnalyzer) ValidateConfig(writer io.Writer) error {
+	if _, err := os.Stat(a.store.dir); err != nil {
+		return common.NewError("%s has no events yet: run `dev-stats ingest` and register its endpoints as webhooks", a.store.dir)
+	}
+	fmt.Fprintf(writer, "✓ Webhook events: %s\n", a.store.dir)
+	return nil
+}
+
+// isMine reports whether an event was done by me; Backlog events of profiles without USER_ID never are
+func (a *WebhookAnalyzer) isMine(event Event) bool {
+	switch event.Source {
+	case "github":
+		return a.username != "" && strings.EqualFold(event.Actor, a.username)
+	case "backlog":
+		userID := os.Getenv("BACKLOG_" + event.Profile + "_USER_ID")
+		return userID != "" && event.Actor == userID
+	}
+	return false
+}
+
+// MyEvents returns my stored events from start through the end date
+func (a *WebhookAnalyzer) MyEvents(start, end time.Time) ([]Event, error) {
+	stored, err := a.store.Load(start, end)
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, event := range stored {
+		if a.isMine(event) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// webhookMetrics are the counted kinds, in report order
+var webhookMetrics = []struct {
+	kind, id, label string
+}{
+	{KindPROpened, "webhooks.prs_opened", "PRs opened"},
+	{KindPRMerged, "webhooks.prs_merged", "PRs merged"},
+	{KindReview, "webhooks.reviews_given", "Reviews given"},
+	{KindPush, "webhooks.commits_pushed", "Commits pushed"},
+	{KindIssueOpened, "webhooks.issues_opened", "Issues opened"},
+	{KindIssueUpdated, "webhooks.issues_updated", "Issue updates"},
+	{KindComment, "webhooks.comments", "Comments"},
+	{KindWiki, "webhooks.wiki_edits", "Wiki edits"},
+}
+
+// Analyze counts my stored events of the period by kind and project
+func (a *WebhookAnalyzer) Analyze(cfg *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := a.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	ignoreList, err := config.LoadIgnoreList("")
+	if err != nil {
+		return nil, err
+	}
+	stored, err := a.store.Load(cfg.StartDate, cfg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	others := 0
+	for _, event := range stored {
+		if !a.isMine(event) {
+			others++
+			continue
+		}
+		if event.URL != "" && ignoreList.Contains(event.URL) {
+			continue
+		}
+		events = append(events, event)
+	}
+	fmt.Fprintf(writer, "Stored events: %d (%d by others or by unidentified accounts)\n", len(stored), others)
+
+	byKind := make(map[string][]common.Activity)
+	counts := make(map[string]int)
+	projects := make(map[string]map[string]int)
+	for _, event := range events {
+		activity := a.activity(event)
+		byKind[event.Kind] = append(byKind[event.Kind], activity)
+		count := 1
+		if event.Kind == KindPush {
+			count = event.Count
+		}
+		counts[event.Kind] += count
+		project := event.Source + ":" + event.Project
+		if projects[project] == nil {
+			projects[project] = make(map[string]int)
+		}
+		projects[project][event.Kind] += count
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: a.GetName(),
+		StartDate:    cfg.StartDate,
+		EndDate:      cfg.EndDate,
+		Details:      map[string]interface{}{"events": events},
+	}
+	for _, metric := range webhookMetrics {
+		result.Metrics = append(result.Metrics, common.Metric{ID: metric.id, Label: metric.label, Value: counts[metric.kind]})
+		result.Activities = append(result.Activities, byKind[metric.kind]...)
+		result.Explain(metric.id, byKind[metric.kind])
+	}
+	result.Metrics = append(result.Metrics, common.Metric{ID: "webhooks.active_projects", Label: "Active projects", Value: len(projects), Snapshot: true})
+
+	a.printResults(writer, result, projects)
+	return result, nil
+}
+
+// activity converts an event into a dated activity
+func (a *WebhookAnalyzer) activity(event Event) common.Activity {
+	return common.Activity{
+		Source:  a.GetName(),
+		Kind:    event.Kind,
+		ID:      event.ID,
+		Title:   strings.TrimSpace(fmt.Sprintf("%s %s", event.Project, event.Title)),
+		URL:     event.URL,
+		Time:    event.Time,
+		Project: event.Project,
+		Size:    event.Count,
+	}
+}
+
+func (a *WebhookAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, projects map[string]map[string]int) {
+	names := make([]string, 0, len(projects))
+	totals := make(map[string]int)
+	for name, kinds := range projects {
+		names = append(names, name)
+		for _, count := range kinds {
+			totals[name] += count
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if totals[names[i]] != totals[names[j]] {
+			return totals[names[i]] > totals[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Fprintf(writer, "\nActivity by project (%d):\n", len(names))
+	for _, name := range names[:common.RankingLimit(len(names))] {
+		var parts []string
+		for _, metric := range webhookMetrics {
+			if count := projects[name][metric.kind]; count > 0 {
+				parts = append(parts, fmt.Sprintf("%s %d", metric.label, count))
+			}
+		}
+		fmt.Fprintf(writer, "- %s: %s\n", name, strings.Join(parts, ", "))
+	}
+	common.PrintMoreEntries(writer, len(names))
+
+	for _, metric := range webhookMetrics[:3] {
+		activities := result.Provenance[metric.id]
+		if len(activities) == 0 {
+			continue
+		}
+		fmt.Fprintf(writer, "\n%s (%d):\n", metric.label, len(activities))
+		for _, activity := range activities {
+			fmt.Fprintf(writer, "- %s: %s\n", activity.Time.Local().Format("2006-01-02"), activity.Title)
+			if activity.URL != "" {
+				fmt.Fprintf(writer, "  URL: %s\n", activity.URL)
+			}
+		}
+	}
+
+	result.PrintSummary(writer)
+}
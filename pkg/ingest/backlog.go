@@ -0,0 +1,121 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// backlogKinds maps the activity types of Backlog webhooks to event kinds; pull request updates (19) count only
+// when they merge
+var backlogKinds = map[int]string{
+	1:  KindIssueOpened,
+	2:  KindIssueUpdated,
+	3:  KindComment,
+	5:  KindWiki,
+	6:  KindWiki,
+	12: KindPush,
+	14: KindIssueUpdated,
+	18: KindPROpened,
+	20: KindComment,
+}
+
+// backlogPRMerged is the status ID of a merged pull request
+const backlogPRMerged = "3"
+
+// backlogPayload holds the fields of a Backlog webhook (an activity) used here
+type backlogPayload struct {
+	ID      int       `json:"id"`
+	Type    int       `json:"type"`
+	Created time.Time `json:"created"`
+	Project struct {
+		ProjectKey string `json:"projectKey"`
+	} `json:"project"`
+	Content struct {
+		KeyID    int    `json:"key_id"`
+		Summary  string `json:"summary"`
+		Name     string `json:"name"` // wiki pages
+		Number   int    `json:"number"`
+		Ref      string `json:"ref"`
+		RevCount int    `json:"revision_count"`
+		Repo     *struct {
+			Name string `json:"name"`
+		} `json:"repository"`
+		Changes []struct {
+			Field    string `json:"field"`
+			NewValue string `json:"new_value"`
+		} `json:"changes"`
+	} `json:"content"`
+	CreatedUser struct {
+		ID int `json:"id"`
+	} `json:"createdUser"`
+}
+
+// backlogHost returns the host of a profile for links (BACKLOG_<PROFILE>_HOST), empty when only the space name is set
+func backlogHost(profile string) string {
+	host := os.Getenv("BACKLOG_" + profile + "_HOST")
+	if !strings.Contains(host, ".") {
+		return ""
+	}
+	return host
+}
+
+// parseBacklogEvent converts a Backlog webhook of a profile into an event; ok is false for activity types that
+// aren't counted
+func parseBacklogEvent(profile string, body []byte) (Event, bool, error) {
+	var payload backlogPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Event{}, false, common.WrapError(err, "failed to parse Backlog payload")
+	}
+	kind, counted := backlogKinds[payload.Type]
+	if payload.Type == 19 {
+		for _, change := range payload.Content.Changes {
+			if change.Field == "status" && change.NewValue == backlogPRMerged {
+				kind, counted = KindPRMerged, true
+			}
+		}
+	}
+	if !counted || payload.ID == 0 {
+		return Event{}, false, nil
+	}
+
+	key := payload.Project.ProjectKey
+	event := Event{
+		ID:      fmt.Sprintf("backlog:%s:%d", profile, payload.ID),
+		Source:  "backlog",
+		Profile: profile,
+		Kind:    kind,
+		Time:    payload.Created,
+		Actor:   strconv.Itoa(payload.CreatedUser.ID),
+		Project: key,
+	}
+	host := backlogHost(profile)
+	content := payload.Content
+	switch {
+	case kind == KindWiki:
+		event.Title = content.Name
+	case kind == KindPush:
+		event.Count = content.RevCount
+		if content.Repo != nil {
+			event.Title = fmt.Sprintf("%s %s", content.Repo.Name, strings.TrimPrefix(content.Ref, "refs/heads/"))
+		}
+	case payload.Type >= 18: // pull requests
+		if content.Repo != nil {
+			event.Title = fmt.Sprintf("%s#%d %s", content.Repo.Name, content.Number, content.Summary)
+			if host != "" {
+				event.URL = fmt.Sprintf("https://%s/git/%s/%s/pullRequests/%d", host, key, content.Repo.Name, content.Number)
+			}
+		}
+	default: // issues
+		event.Title = fmt.Sprintf("%s-%d %s", key, content.KeyID, content.Summary)
+		if host != "" {
+			event.URL = fmt.Sprintf("https://%s/view/%s-%d", host, key, content.KeyID)
+		}
+	}
+	return event, true, nil
+}
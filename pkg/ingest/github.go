@@ -0,0 +1,156 @@
+package ingest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// GitHubSecretEnv names the secret GitHub signs webhook payloads with (X-Hub-Signature-256)
+const GitHubSecretEnv = "GITHUB_WEBHOOK_SECRET"
+
+// verifyGitHubSignature checks the sha256=<hex> HMAC of the payload
+func verifyGitHubSignature(secret string, body []byte, signature string) bool {
+	digest, found := strings.CutPrefix(signature, "sha256=")
+	if !found {
+		return false
+	}
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubPullRequest struct {
+	HTMLURL   string     `json:"html_url"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	User      githubUser `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+	Merged    bool       `json:"merged"`
+	MergedAt  *time.Time `json:"merged_at"`
+}
+
+// githubPayload holds the fields of the webhook payloads used here; which are set depends on the event
+type githubPayload struct {
+	Action     string `json:"action"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender      githubUser         `json:"sender"`
+	PullRequest *githubPullRequest `json:"pull_request"`
+	Review      *struct {
+		HTMLURL     string     `json:"html_url"`
+		User        githubUser `json:"user"`
+		SubmittedAt time.Time  `json:"submitted_at"`
+	} `json:"review"`
+	Issue *struct {
+		HTMLURL   string     `json:"html_url"`
+		Number    int        `json:"number"`
+		Title     string     `json:"title"`
+		User      githubUser `json:"user"`
+		CreatedAt time.Time  `json:"created_at"`
+	} `json:"issue"`
+	Comment *struct {
+		HTMLURL   string     `json:"html_url"`
+		User      githubUser `json:"user"`
+		CreatedAt time.Time  `json:"created_at"`
+	} `json:"comment"`
+	// push
+	Ref     string `json:"ref"`
+	Deleted bool   `json:"deleted"`
+	Compare string `json:"compare"`
+	Commits []struct {
+		Distinct bool `json:"distinct"`
+	} `json:"commits"`
+	HeadCommit *struct {
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"head_commit"`
+}
+
+// parseGitHubEvent converts a webhook delivery (X-GitHub-Event, payload) into an event; ok is false for events and
+// actions that aren't counted (edits, labels, pings)
+func parseGitHubEvent(eventType, deliveryID string, body []byte, received time.Time) (Event, bool, error) {
+	var payload githubPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Event{}, false, common.WrapError(err, "failed to parse %s payload", eventType)
+	}
+	event := Event{ID: "github:" + deliveryID, Source: "github", Project: payload.Repository.FullName}
+
+	switch {
+	case eventType == "pull_request" && payload.PullRequest != nil:
+		pr := payload.PullRequest
+		event.Actor, event.URL = pr.User.Login, pr.HTMLURL
+		event.Title = fmt.Sprintf("#%d %s", pr.Number, pr.Title)
+		switch {
+		case payload.Action == "opened":
+			event.Kind, event.Time = KindPROpened, pr.CreatedAt
+		case payload.Action == "closed" && pr.Merged && pr.MergedAt != nil:
+			event.Kind, event.Time = KindPRMerged, *pr.MergedAt
+		default:
+			return Event{}, false, nil
+		}
+	case eventType == "pull_request_review" && payload.Review != nil && payload.PullRequest != nil:
+		pr := payload.PullRequest
+		if payload.Action != "submitted" || strings.EqualFold(payload.Review.User.Login, pr.User.Login) {
+			return Event{}, false, nil // replies on own PRs are not reviews
+		}
+		event.Kind, event.Time, event.Actor = KindReview, payload.Review.SubmittedAt, payload.Review.User.Login
+		event.Title, event.URL = fmt.Sprintf("#%d %s", pr.Number, pr.Title), payload.Review.HTMLURL
+	case eventType == "push":
+		if payload.Deleted {
+			return Event{}, false, nil
+		}
+		for _, commit := range payload.Commits {
+			if commit.Distinct {
+				event.Count++
+			}
+		}
+		if event.Count == 0 {
+			return Event{}, false, nil
+		}
+		event.Kind, event.Time, event.Actor = KindPush, received, payload.Sender.Login
+		if payload.HeadCommit != nil && !payload.HeadCommit.Timestamp.IsZero() {
+			event.Time = payload.HeadCommit.Timestamp
+		}
+		event.Title, event.URL = strings.TrimPrefix(payload.Ref, "refs/heads/"), payload.Compare
+	case eventType == "issues" && payload.Issue != nil:
+		if payload.Action != "opened" {
+			return Event{}, false, nil
+		}
+		issue := payload.Issue
+		event.Kind, event.Time, event.Actor = KindIssueOpened, issue.CreatedAt, issue.User.Login
+		event.Title, event.URL = fmt.Sprintf("#%d %s", issue.Number, issue.Title), issue.HTMLURL
+	case (eventType == "issue_comment" || eventType == "pull_request_review_comment") && payload.Comment != nil:
+		if payload.Action != "created" {
+			return Event{}, false, nil
+		}
+		comment := payload.Comment
+		event.Kind, event.Time, event.Actor, event.URL = KindComment, comment.CreatedAt, comment.User.Login, comment.HTMLURL
+		if payload.Issue != nil {
+			event.Title = fmt.Sprintf("#%d %s", payload.Issue.Number, payload.Issue.Title)
+		} else if payload.PullRequest != nil {
+			event.Title = fmt.Sprintf("#%d %s", payload.PullRequest.Number, payload.PullRequest.Title)
+		}
+	default:
+		return Event{}, false, nil
+	}
+
+	if event.Time.IsZero() {
+		event.Time = received
+	}
+	return event, true, nil
+}
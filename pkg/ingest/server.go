@@ -0,0 +1,147 @@
+package ingest
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// TokenEnv names the token Backlog webhooks must pass as ?token=, since Backlog doesn't sign its payloads
+const TokenEnv = "INGEST_TOKEN"
+
+// maxPayloadSize is the largest payload GitHub delivers
+const maxPayloadSize = 25 << 20
+
+// profilePattern matches the profile names of BACKLOG_<PROFILE>_* variables
+var profilePattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// Server receives GitHub and Backlog webhooks and appends their events to the store
+type Server struct {
+	store        *Store
+	githubSecret string
+	token        string
+	writer       io.Writer  // one line per stored event
+	mu           sync.Mutex // guards writer
+}
+
+// NewServer creates a server storing into store with the secrets of GITHUB_WEBHOOK_SECRET and INGEST_TOKEN
+func NewServer(store *Store, writer io.Writer) (*Server, error) {
+	s := &Server{store: store, githubSecret: os.Getenv(GitHubSecretEnv), token: os.Getenv(TokenEnv), writer: writer}
+	if s.githubSecret == "" && s.token == "" {
+		return nil, common.NewError("set %s to receive GitHub webhooks, %s to receive Backlog webhooks, or both", GitHubSecretEnv, TokenEnv)
+	}
+	return s, nil
+}
+
+// Endpoints describes the enabled endpoints, for the startup message
+func (s *Server) Endpoints() []string {
+	var endpoints []string
+	if s.githubSecret != "" {
+		endpoints = append(endpoints, "POST /github (GitHub, signed with "+GitHubSecretEnv+")")
+	}
+	if s.token != "" {
+		endpoints = append(endpoints, "POST /backlog/<profile>?token=... (Backlog, "+TokenEnv+")")
+	}
+	return endpoints
+}
+
+// Handler returns the routes of the server
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("POST /github", s.handleGitHub)
+	mux.HandleFunc("POST /backlog/{profile}", s.handleBacklog)
+	return mux
+}
+
+func (s *Server) handleGitHub(w http.ResponseWriter, r *http.Request) {
+	if s.githubSecret == "" {
+		http.Error(w, GitHubSecretEnv+" is not set", http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read payload", http.StatusBadRequest)
+		return
+	}
+	if !verifyGitHubSignature(s.githubSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	eventType, deliveryID := r.Header.Get("X-GitHub-Event"), r.Header.Get("X-GitHub-Delivery")
+	if eventType == "ping" {
+		fmt.Fprintln(w, "pong")
+		return
+	}
+	if deliveryID == "" {
+		http.Error(w, "missing X-GitHub-Delivery", http.StatusBadRequest)
+		return
+	}
+	event, counted, err := parseGitHubEvent(eventType, deliveryID, body, time.Now())
+	s.save(w, event, counted, err)
+}
+
+func (s *Server) handleBacklog(w http.ResponseWriter, r *http.Request) {
+	if s.token == "" {
+		http.Error(w, TokenEnv+" is not set", http.StatusNotFound)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(s.token)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	profile := strings.ToUpper(r.PathValue("profile"))
+	if !profilePattern.MatchString(profile) || os.Getenv("BACKLOG_"+profile+"_HOST") == "" {
+		http.Error(w, fmt.Sprintf("unknown Backlog profile %s (BACKLOG_%s_HOST is not set)", profile, profile), http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read payload", http.StatusBadRequest)
+		return
+	}
+	event, counted, err := parseBacklogEvent(profile, body)
+	s.save(w, event, counted, err)
+}
+
+// save answers a parsed delivery: stored events with 201 (200 for a redelivery), others with 202
+func (s *Server) save(w http.ResponseWriter, event Event, counted bool, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !counted {
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintln(w, "ignored")
+		return
+	}
+	added, err := s.store.Append(event)
+	if err != nil {
+		http.Error(w, "failed to store the event", http.StatusInternalServerError)
+		s.log("✗ Failed to store %s: %v", event.ID, err)
+		return
+	}
+	if !added {
+		fmt.Fprintln(w, "duplicate")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintln(w, "stored")
+	s.log("✓ %s %s %s %s by %s: %s", event.Time.Local().Format("2006-01-02 15:04"), event.Source, event.Kind, event.Project, event.Actor, event.Title)
+}
+
+func (s *Server) log(format string, args ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.writer, format+"\n", args...)
+}
@@ -0,0 +1,114 @@
+package ingest
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// DefaultDir holds the received webhook events, one file per month (2025-01.json)
+const DefaultDir = "storage/webhooks"
+
+// Event kinds
+const (
+	KindPROpened     = "pr_opened"
+	KindPRMerged     = "pr_merged"
+	KindReview       = "review"
+	KindPush         = "push"
+	KindIssueOpened  = "issue_opened"
+	KindIssueUpdated = "issue_updated"
+	KindComment      = "comment"
+	KindWiki         = "wiki"
+)
+
+// Event is one activity received by webhook
+type Event struct {
+	ID      string    `json:"id"`                // delivery ID, so that redeliveries are stored once
+	Source  string    `json:"source"`            // github or backlog
+	Profile string    `json:"profile,omitempty"` // Backlog profile the webhook was registered for
+	Kind    string    `json:"kind"`
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`   // GitHub login or Backlog numeric user ID
+	Project string    `json:"project"` // owner/repo or Backlog project key
+	Title   string    `json:"title"`
+	URL     string    `json:"url,omitempty"`
+	Count   int       `json:"count,omitempty"` // commits of a push
+}
+
+// Store appends events to the monthly files of a directory
+type Store struct {
+	dir string
+	mu  sync.Mutex // webhooks arrive concurrently
+}
+
+// NewStore opens the event store in dir (DefaultDir when empty)
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Store{dir: dir}
+}
+
+// monthPath returns the file of the month t falls in
+func (s *Store) monthPath(t time.Time) string {
+	return filepath.Join(s.dir, t.UTC().Format("2006-01")+".json")
+}
+
+// readMonth loads the events of a month file, none when it doesn't exist yet
+func readMonth(path string) ([]Event, error) {
+	var events []Event
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err := common.ReadJSONFile(path, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Append stores an event unless one with the same ID is stored already, and reports whether it was added
+func (s *Store) Append(event Event) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.monthPath(event.Time)
+	events, err := readMonth(path)
+	if err != nil {
+		return false, err
+	}
+	for _, stored := range events {
+		if stored.ID == event.ID {
+			return false, nil
+		}
+	}
+	events = append(events, event)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	if err := common.WriteJSONFile(path, events); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Load returns the events from start through the end date, in time order
+func (s *Store) Load(start, end time.Time) ([]Event, error) {
+	until := end.AddDate(0, 0, 1)
+	first := start.UTC()
+	var events []Event
+	for month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); month.Before(until); month = month.AddDate(0, 1, 0) {
+		stored, err := readMonth(s.monthPath(month))
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range stored {
+			if !event.Time.Before(start) && event.Time.Before(until) {
+				events = append(events, event)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
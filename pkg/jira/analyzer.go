@@ -0,0 +1,483 @@
+package jira
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+const tempoAPIURL = "https://api.tempo.io/4"
+
+// pageSize is the page size of issue searches, worklog lists, and Tempo worklogs
+const pageSize = 100
+
+// JiraAnalyzer implements the Analyzer interface for time logged in Jira (native worklogs or Tempo)
+type JiraAnalyzer struct {
+	baseURL     string
+	email       string
+	token       string
+	tempoToken  string
+	client      *common.HTTPClient
+	tempoClient *common.HTTPClient
+	ignoreList  config.IgnoreList
+	issues      map[string]issueInfo // issue ID -> key and summary, for Tempo worklogs
+	warnings    common.Warnings      // optional lookups that failed during the current run
+}
+
+// Worklog is time the user logged on an issue
+type Worklog struct {
+	ID               string    `json:"id"`
+	IssueKey         string    `json:"issue_key"`
+	IssueSummary     string    `json:"issue_summary"`
+	Started          time.Time `json:"started"`
+	TimeSpentSeconds int       `json:"time_spent_seconds"`
+	Description      string    `json:"description,omitempty"`
+}
+
+// Duration returns the logged time
+func (w Worklog) Duration() time.Duration {
+	return time.Duration(w.TimeSpentSeconds) * time.Second
+}
+
+// issueInfo is the key and summary of an issue
+type issueInfo struct {
+	Key     string
+	Summary string
+}
+
+// myself is the /rest/api/3/myself response
+type myself struct {
+	AccountID    string `json:"accountId"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// searchResponse is the /rest/api/3/search/jql response
+type searchResponse struct {
+	Issues []struct {
+		ID     string `json:"id"`
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+		} `json:"fields"`
+	} `json:"issues"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// worklogResponse is the /rest/api/3/issue/{key}/worklog response
+type worklogResponse struct {
+	StartAt    int `json:"startAt"`
+	MaxResults int `json:"maxResults"`
+	Total      int `json:"total"`
+	Worklogs   []struct {
+		ID     string `json:"id"`
+		Author struct {
+			AccountID string `json:"accountId"`
+		} `json:"author"`
+		Started          string `json:"started"`
+		TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	} `json:"worklogs"`
+}
+
+// tempoResponse is the Tempo /worklogs/user/{accountId} response
+type tempoResponse struct {
+	Results []struct {
+		TempoWorklogID int `json:"tempoWorklogId"`
+		Issue          struct {
+			ID int `json:"id"`
+		} `json:"issue"`
+		TimeSpentSeconds int    `json:"timeSpentSeconds"`
+		StartDate        string `json:"startDate"`
+		StartTime        string `json:"startTime"`
+		Description      string `json:"description"`
+	} `json:"results"`
+	Metadata struct {
+		Next string `json:"next"`
+	} `json:"metadata"`
+}
+
+// NewJiraAnalyzer creates a new Jira analyzer
+func NewJiraAnalyzer() *JiraAnalyzer {
+	email := os.Getenv("JIRA_EMAIL")
+	token := os.Getenv("JIRA_API_TOKEN")
+	tempoToken := os.Getenv("TEMPO_API_TOKEN")
+
+	client := common.NewHTTPClient()
+	client.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(email+":"+token)))
+	client.SetHeader("Accept", "application/json")
+	tempoClient := common.NewHTTPClient()
+	tempoClient.SetHeader("Authorization", "Bearer "+tempoToken)
+
+	return &JiraAnalyzer{
+		baseURL:     strings.TrimRight(os.Getenv("JIRA_BASE_URL"), "/"),
+		email:       email,
+		token:       token,
+		tempoToken:  tempoToken,
+		client:      client,
+		tempoClient: tempoClient,
+	}
+}
+
+// GetName returns the analyzer name
+func (j *JiraAnalyzer) GetName() string {
+	return "Jira"
+}
+
+// ValidateConfig validates the required configuration
+func (j *JiraAnalyzer) ValidateConfig(writer io.Writer) error {
+	if j.baseURL == "" || j.email == "" || j.token == "" {
+		return common.NewError("JIRA_BASE_URL, JIRA_EMAIL, and JIRA_API_TOKEN environment variables are required")
+	}
+	if _, err := j.getMyself(); err != nil {
+		return common.WrapError(err, "failed to access Jira API (check JIRA_BASE_URL, JIRA_EMAIL, and JIRA_API_TOKEN)")
+	}
+	fmt.Fprintln(writer, "✓ Jira credentials are valid")
+	if j.tempoToken != "" {
+		fmt.Fprintln(writer, "✓ Using Tempo worklogs (TEMPO_API_TOKEN)")
+	}
+	return nil
+}
+
+// WhoAmI reports the account behind JIRA_EMAIL/JIRA_API_TOKEN
+func (j *JiraAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if j.baseURL == "" || j.email == "" || j.token == "" {
+		return nil, common.NewError("JIRA_BASE_URL, JIRA_EMAIL, and JIRA_API_TOKEN environment variables are required")
+	}
+	me, err := j.getMyself()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to access Jira API (check JIRA_BASE_URL, JIRA_EMAIL, and JIRA_API_TOKEN)")
+	}
+	return &common.Identity{Source: j.GetName(), ID: me.AccountID, Login: me.EmailAddress, Name: me.DisplayName}, nil
+}
+
+// Analyze reports the time logged per issue and per day
+func (j *JiraAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := j.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := j.ignoreList.Reload(); err != nil {
+		return nil, err
+	}
+	j.warnings.Reset()
+
+	me, err := j.getMyself()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get Jira account")
+	}
+
+	fmt.Fprintf(writer, "Fetching Jira worklogs from %s to %s...\n",
+		config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+	var worklogs []Worklog
+	if j.tempoToken != "" {
+		worklogs, err = j.getTempoWorklogs(writer, me.AccountID, config.StartDate, config.EndDate)
+	} else {
+		worklogs, err = j.getJiraWorklogs(writer, me.AccountID, config.StartDate, config.EndDate)
+	}
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get worklogs")
+	}
+	worklogs = j.filterIgnored(writer, worklogs)
+	sort.SliceStable(worklogs, func(a, b int) bool {
+		return worklogs[a].Started.Before(worklogs[b].Started)
+	})
+
+	var total time.Duration
+	byIssue := make(map[string]time.Duration)
+	byDay := make(map[string]time.Duration)
+	for _, worklog := range worklogs {
+		total += worklog.Duration()
+		byIssue[worklog.IssueKey] += worklog.Duration()
+		byDay[worklog.Started.Local().Format("2006-01-02")] += worklog.Duration()
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: j.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "jira.logged_hours", Label: "Time logged", Value: total},
+			{ID: "jira.worklogs", Label: "Worklogs", Value: len(worklogs)},
+			{ID: "jira.issues_logged", Label: "Issues with logged time", Value: len(byIssue), Snapshot: true},
+			{ID: "jira.active_days", Label: "Days with logged time", Value: len(byDay)},
+		},
+		Details: map[string]interface{}{
+			"worklogs": worklogs,
+			"by_issue": byIssue,
+			"by_day":   byDay,
+		},
+		Activities: j.buildActivities(worklogs),
+		CSVTables:  j.csvTables(worklogs),
+	}
+	result.Explain("jira.logged_hours", result.Activities)
+	result.Explain("jira.worklogs", result.Activities)
+
+	j.printResults(writer, result, worklogs, byIssue, byDay)
+	result.Warnings = j.warnings.List()
+	return result, nil
+}
+
+// getMyself returns the account behind the credentials
+func (j *JiraAnalyzer) getMyself() (*myself, error) {
+	body, err := j.client.Get(j.baseURL+"/rest/api/3/myself", nil)
+	if err != nil {
+		return nil, err
+	}
+	var me myself
+	if err := json.Unmarshal(body, &me); err != nil {
+		return nil, common.WrapError(err, "failed to parse Jira account")
+	}
+	return &me, nil
+}
+
+// getJiraWorklogs searches issues with worklogs by the user in the period, then lists each issue's worklogs
+func (j *JiraAnalyzer) getJiraWorklogs(writer io.Writer, accountID string, startDate, endDate time.Time) ([]Worklog, error) {
+	jql := fmt.Sprintf(`worklogAuthor = currentUser() AND worklogDate >= "%s" AND worklogDate <= "%s"`,
+		startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	issues, err := j.searchIssues(jql)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(writer, "Found %d issues with worklogs\n", len(issues))
+
+	// Worklog times are compared by day so that the period boundaries match the search
+	first := startDate.Format("2006-01-02")
+	last := endDate.Format("2006-01-02")
+	startedAfter := startDate.AddDate(0, 0, -1).UnixMilli()
+	startedBefore := endDate.AddDate(0, 0, 2).UnixMilli()
+
+	var worklogs []Worklog
+	for _, issue := range issues {
+		for startAt := 0; ; {
+			apiURL := fmt.Sprintf("%s/rest/api/3/issue/%s/worklog?startAt=%d&maxResults=%d&startedAfter=%d&startedBefore=%d",
+				j.baseURL, url.PathEscape(issue.Key), startAt, pageSize, startedAfter, startedBefore)
+			body, err := j.client.Get(apiURL, nil)
+			if err != nil {
+				j.warnings.Add("issue worklogs", issue.Key, err)
+				break
+			}
+			var response worklogResponse
+			if err := json.Unmarshal(body, &response); err != nil {
+				j.warnings.Add("issue worklogs", issue.Key, err)
+				break
+			}
+
+			for _, entry := range response.Worklogs {
+				started, err := time.Parse("2006-01-02T15:04:05.000-0700", entry.Started)
+				if err != nil || entry.Author.AccountID != accountID {
+					continue
+				}
+				if day := started.Local().Format("2006-01-02"); day < first || day > last {
+					continue
+				}
+				worklogs = append(worklogs, Worklog{
+					ID:               entry.ID,
+					IssueKey:         issue.Key,
+					IssueSummary:     issue.Summary,
+					Started:          started,
+					TimeSpentSeconds: entry.TimeSpentSeconds,
+				})
+			}
+
+			startAt += len(response.Worklogs)
+			if len(response.Worklogs) == 0 || startAt >= response.Total {
+				break
+			}
+		}
+	}
+	return worklogs, nil
+}
+
+// searchIssues runs a JQL search, following nextPageToken pagination
+func (j *JiraAnalyzer) searchIssues(jql string) ([]issueInfo, error) {
+	var issues []issueInfo
+	nextPageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("jql", jql)
+		params.Set("fields", "summary")
+		params.Set("maxResults", strconv.Itoa(pageSize))
+		if nextPageToken != "" {
+			params.Set("nextPageToken", nextPageToken)
+		}
+
+		body, err := j.client.Get(fmt.Sprintf("%s/rest/api/3/search/jql?%s", j.baseURL, params.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		var response searchResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Jira search response")
+		}
+		for _, issue := range response.Issues {
+			issues = append(issues, issueInfo{Key: issue.Key, Summary: issue.Fields.Summary})
+		}
+
+		if response.NextPageToken == "" {
+			break
+		}
+		nextPageToken = response.NextPageToken
+	}
+	return issues, nil
+}
+
+// getTempoWorklogs lists the user's Tempo worklogs in the period, following offset pagination.
+// Tempo only returns issue IDs, so keys and summaries are looked up in Jira.
+func (j *JiraAnalyzer) getTempoWorklogs(writer io.Writer, accountID string, startDate, endDate time.Time) ([]Worklog, error) {
+	var worklogs []Worklog
+	for offset := 0; ; offset += pageSize {
+		params := url.Values{}
+		params.Set("from", startDate.Format("2006-01-02"))
+		params.Set("to", endDate.Format("2006-01-02"))
+		params.Set("offset", strconv.Itoa(offset))
+		params.Set("limit", strconv.Itoa(pageSize))
+
+		body, err := j.tempoClient.Get(fmt.Sprintf("%s/worklogs/user/%s?%s", tempoAPIURL, url.PathEscape(accountID), params.Encode()), nil)
+		if err != nil {
+			return nil, common.WrapError(err, "failed to access Tempo API (check TEMPO_API_TOKEN)")
+		}
+		var response tempoResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Tempo response")
+		}
+
+		for _, entry := range response.Results {
+			started, err := time.ParseInLocation("2006-01-02 15:04:05", entry.StartDate+" "+entry.StartTime, time.Local)
+			if err != nil {
+				started, err = time.ParseInLocation("2006-01-02", entry.StartDate, time.Local)
+				if err != nil {
+					continue
+				}
+			}
+			issue := j.lookupIssue(strconv.Itoa(entry.Issue.ID))
+			worklogs = append(worklogs, Worklog{
+				ID:               strconv.Itoa(entry.TempoWorklogID),
+				IssueKey:         issue.Key,
+				IssueSummary:     issue.Summary,
+				Started:          started,
+				TimeSpentSeconds: entry.TimeSpentSeconds,
+				Description:      entry.Description,
+			})
+		}
+
+		if response.Metadata.Next == "" || len(response.Results) == 0 {
+			break
+		}
+	}
+	fmt.Fprintf(writer, "Found %d Tempo worklogs\n", len(worklogs))
+	return worklogs, nil
+}
+
+// lookupIssue returns the key and summary of an issue ID; failures are cached and reported as warnings
+func (j *JiraAnalyzer) lookupIssue(issueID string) issueInfo {
+	if j.issues == nil {
+		j.issues = make(map[string]issueInfo)
+	}
+	if issue, exists := j.issues[issueID]; exists {
+		return issue
+	}
+
+	issue := issueInfo{Key: issueID}
+	body, err := j.client.Get(fmt.Sprintf("%s/rest/api/3/issue/%s?fields=summary", j.baseURL, url.PathEscape(issueID)), nil)
+	if err != nil {
+		j.warnings.Add("issue summary", issueID, err)
+	} else {
+		var response struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary string `json:"summary"`
+			} `json:"fields"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			j.warnings.Add("issue summary", issueID, err)
+		} else {
+			issue = issueInfo{Key: response.Key, Summary: response.Fields.Summary}
+		}
+	}
+	j.issues[issueID] = issue
+	return issue
+}
+
+// issueURL returns the browser URL of an issue
+func (j *JiraAnalyzer) issueURL(key string) string {
+	return j.baseURL + "/browse/" + key
+}
+
+// filterIgnored drops worklogs on issues listed in the ignore file by key or URL
+func (j *JiraAnalyzer) filterIgnored(writer io.Writer, worklogs []Worklog) []Worklog {
+	return config.FilterIgnored(writer, &j.ignoreList, worklogs, "worklogs on issues", func(worklog Worklog) []string {
+		return []string{worklog.IssueKey, j.issueURL(worklog.IssueKey)}
+	})
+}
+
+// buildActivities converts worklogs into dated activities with the logged duration.
+// Titles start with the issue key so that PRs, commits, and notes mentioning it are linked to the same work item.
+func (j *JiraAnalyzer) buildActivities(worklogs []Worklog) []common.Activity {
+	var activities []common.Activity
+	for _, worklog := range worklogs {
+		activities = append(activities, common.Activity{
+			Source:   j.GetName(),
+			Kind:     common.ActivityKindWorklog,
+			ID:       worklog.ID,
+			Title:    strings.TrimSpace(worklog.IssueKey + " " + worklog.IssueSummary),
+			URL:      j.issueURL(worklog.IssueKey),
+			Time:     worklog.Started,
+			Duration: worklog.Duration(),
+		})
+	}
+	return activities
+}
+
+func (j *JiraAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, worklogs []Worklog, byIssue, byDay map[string]time.Duration) {
+	fmt.Fprintf(writer, "\nWorklogs from %s to %s (%d):\n",
+		result.StartDate.Format("2006-01-02"), result.EndDate.Format("2006-01-02"), len(worklogs))
+	for _, worklog := range worklogs {
+		line := fmt.Sprintf("- %s: %s %s (%s)", worklog.Started.Local().Format("2006-01-02 15:04"),
+			worklog.IssueKey, worklog.IssueSummary, common.FormatDuration(worklog.Duration()))
+		if worklog.Description != "" {
+			line += " - " + worklog.Description
+		}
+		fmt.Fprintln(writer, line)
+	}
+
+	result.PrintSummary(writer)
+
+	summaries := make(map[string]string)
+	for _, worklog := range worklogs {
+		summaries[worklog.IssueKey] = worklog.IssueSummary
+	}
+	var keys []string
+	for key := range byIssue {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if byIssue[keys[a]] != byIssue[keys[b]] {
+			return byIssue[keys[a]] > byIssue[keys[b]]
+		}
+		return keys[a] < keys[b]
+	})
+	fmt.Fprintln(writer, "\nTime logged per issue:")
+	for _, key := range keys[:common.RankingLimit(len(keys))] {
+		fmt.Fprintf(writer, "- %s %s: %s\n", key, summaries[key], common.FormatDuration(byIssue[key]))
+	}
+	common.PrintMoreEntries(writer, len(keys))
+
+	var days []string
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	fmt.Fprintln(writer, "\nTime logged per day:")
+	for _, day := range days {
+		fmt.Fprintf(writer, "- %s: %s\n", day, common.FormatDuration(byDay[day]))
+	}
+}
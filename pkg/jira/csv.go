@@ -0,0 +1,33 @@
+package jira
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// worklogCSVRow is one worklog in jira-worklogs.csv
+type worklogCSVRow struct {
+	IssueKey    string        `csv:"issue_key"`
+	Summary     string        `csv:"summary"`
+	Started     time.Time     `csv:"started"`
+	Duration    time.Duration `csv:"hours"`
+	Description string        `csv:"description"`
+	URL         string        `csv:"url"`
+}
+
+// csvTables lists the worklogs within the period
+func (j *JiraAnalyzer) csvTables(worklogs []Worklog) []common.CSVTable {
+	var rows []worklogCSVRow
+	for _, worklog := range worklogs {
+		rows = append(rows, worklogCSVRow{
+			IssueKey:    worklog.IssueKey,
+			Summary:     worklog.IssueSummary,
+			Started:     worklog.Started,
+			Duration:    worklog.Duration(),
+			Description: worklog.Description,
+			URL:         j.issueURL(worklog.IssueKey),
+		})
+	}
+	return []common.CSVTable{common.NewCSVTable("worklogs", rows)}
+}
@@ -0,0 +1,192 @@
+package notion
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// todoLinePattern matches to-do blocks written by the downloader ("- [ ] text" / "- [x] text")
+var todoLinePattern = regexp.MustCompile(`^\s*- \[([ xX])\] (.+)$`)
+
+// createdLinePattern matches the creation date header written by the downloader
+var createdLinePattern = regexp.MustCompile(`^\*\*Created:\*\* (\d{4}-\d{2}-\d{2})`)
+
+// ActionItem is a to-do block in downloaded meeting notes
+type ActionItem struct {
+	Text      string    `json:"text"`
+	Completed bool      `json:"completed"`
+	Meeting   string    `json:"meeting"`
+	Category  string    `json:"category"`
+	Date      time.Time `json:"date"`
+	File      string    `json:"file"`
+}
+
+// ActionItemReport summarizes action items from the period's meetings
+type ActionItemReport struct {
+	Items          []ActionItem
+	Meetings       int      // meeting notes with at least one matching action item
+	ScannedFiles   int      // downloaded notes created within the period
+	AssigneeFilter []string // empty means all action items are counted
+}
+
+// Open returns action items not yet completed
+func (r *ActionItemReport) Open() []ActionItem {
+	var open []ActionItem
+	for _, item := range r.Items {
+		if !item.Completed {
+			open = append(open, item)
+		}
+	}
+	return open
+}
+
+// ActionItemAssigneesFromEnv returns NOTION_ACTION_ITEM_ASSIGNEES, the names that mark an action item as yours (e.g. "@Taro Yamada,山田")
+func ActionItemAssigneesFromEnv() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv("NOTION_ACTION_ITEM_ASSIGNEES"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// CollectActionItems scans meeting notes downloaded with -download under dir (category subdirectories)
+// for to-do blocks mentioning any of assignees, keeping notes created within the period
+func CollectActionItems(dir string, startDate, endDate time.Time, assignees []string) (*ActionItemReport, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, common.NewError("%s does not exist; download meeting notes first with -download notion-urls/<period>.md", dir)
+	}
+
+	report := &ActionItemReport{AssigneeFilter: assignees}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+
+		items, created, err := parseActionItems(path, assignees)
+		if err != nil {
+			return err
+		}
+		if created.IsZero() || created.Before(startDate) || created.After(endDate.AddDate(0, 0, 1)) {
+			return nil
+		}
+		report.ScannedFiles++
+		if len(items) > 0 {
+			report.Meetings++
+		}
+		report.Items = append(report.Items, items...)
+		return nil
+	})
+	if err != nil {
+		return nil, common.WrapError(err, "failed to scan %s", dir)
+	}
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		return report.Items[i].Date.Before(report.Items[j].Date)
+	})
+	return report, nil
+}
+
+// parseActionItems reads one downloaded note, returning its matching to-do items and creation date
+func parseActionItems(path string, assignees []string) ([]ActionItem, time.Time, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer file.Close()
+
+	meeting := strings.TrimSuffix(filepath.Base(path), ".md")
+	category := filepath.Base(filepath.Dir(path))
+	titleRead := false
+	var created time.Time
+	var items []ActionItem
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "# ") && !titleRead {
+			meeting = strings.TrimPrefix(line, "# ")
+			titleRead = true
+			continue
+		}
+		if matches := createdLinePattern.FindStringSubmatch(line); matches != nil && created.IsZero() {
+			created, _ = time.ParseInLocation("2006-01-02", matches[1], time.Local)
+			continue
+		}
+		matches := todoLinePattern.FindStringSubmatch(line)
+		if matches == nil || !mentionsAny(matches[2], assignees) {
+			continue
+		}
+		items = append(items, ActionItem{
+			Text:      strings.TrimSpace(matches[2]),
+			Completed: matches[1] != " ",
+			Meeting:   meeting,
+			Category:  category,
+			File:      path,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	for i := range items {
+		items[i].Date = created
+	}
+	return items, created, nil
+}
+
+// mentionsAny reports whether text contains any of names; no names means every item matches
+func mentionsAny(text string, names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	lower := strings.ToLower(text)
+	for _, name := range names {
+		if strings.Contains(lower, strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintActionItemReport prints open vs completed action items and lists the open ones
+func PrintActionItemReport(writer io.Writer, report *ActionItemReport, startDate, endDate time.Time) {
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(writer, "MEETING ACTION ITEMS (%s to %s)\n", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	if len(report.AssigneeFilter) > 0 {
+		fmt.Fprintf(writer, "Assigned to: %s\n", strings.Join(report.AssigneeFilter, ", "))
+	} else {
+		fmt.Fprintln(writer, "⚠️  NOTION_ACTION_ITEM_ASSIGNEES is not set; counting all action items")
+	}
+
+	open := report.Open()
+	completed := len(report.Items) - len(open)
+	fmt.Fprintf(writer, "Meeting notes scanned: %d (%d with action items)\n", report.ScannedFiles, report.Meetings)
+	fmt.Fprintf(writer, "Action items: %d (completed: %d, open: %d)\n", len(report.Items), completed, len(open))
+	if len(report.Items) > 0 {
+		fmt.Fprintf(writer, "Completion rate: %.1f%%\n", float64(completed)/float64(len(report.Items))*100)
+	}
+
+	if len(open) == 0 {
+		return
+	}
+	fmt.Fprintln(writer, "\nOpen action items:")
+	for _, item := range open {
+		fmt.Fprintf(writer, "- [ ] %s (%s, %s)\n", item.Text, item.Meeting, item.Date.Format("2006-01-02"))
+	}
+}
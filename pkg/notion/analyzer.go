@@ -6,8 +6,10 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"dev-stats/pkg/common"
 	"dev-stats/pkg/config"
@@ -16,14 +18,47 @@ import (
 const (
 	notionAPIURL = "https://api.notion.com/v1"
 	apiVersion   = "2022-06-28"
+
+	// Notion allows an average of 3 requests per second per integration, with some bursts
+	defaultRequestsPerSecond = 3.0
+	requestBurst             = 3
+	// 429 responses carry Retry-After in seconds; waits longer than this fail the request
+	maxRetryAfterWait = 5 * time.Minute
 )
 
+// requestsPerSecondFromEnv reads NOTION_REQUESTS_PER_SECOND (default 3)
+func requestsPerSecondFromEnv() float64 {
+	if value := os.Getenv("NOTION_REQUESTS_PER_SECOND"); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultRequestsPerSecond
+}
+
+// configureClient sets the API headers, the request throttle, and Retry-After handling on a Notion client
+func configureClient(client *common.HTTPClient, token string, writer io.Writer) {
+	client.SetHeader("Authorization", "Bearer "+token)
+	client.SetHeader("Notion-Version", apiVersion)
+	client.SetHeader("Content-Type", "application/json")
+	client.SetRequestRate(requestsPerSecondFromEnv(), requestBurst)
+	client.WaitOnRateLimit(writer, maxRetryAfterWait)
+}
+
 // NotionAnalyzer implements the Analyzer interface for Notion
 type NotionAnalyzer struct {
 	token          string
 	client         *common.HTTPClient
 	categoryConfig *config.CategorizationConfig
-	relationCache  map[string]string // Cache for relation page titles
+	overrides      *config.Overrides
+	ignoreList     config.IgnoreList
+	relationCache  map[string]string // Cache for relation page titles, including failed lookups within this run
+	titles         *titleCache       // Relation and database titles persisted across runs
+	cachedPages    []Page            // Pages fetched by the last run, reused while the date range is unchanged
+	cachedTasks    []DoneTask        // Tasks from config/notion-tasks.yaml databases fetched by the last run
+	cachedUserID   string
+	cachedRange    string
+	warnings       common.Warnings // optional lookups that failed while fetching the cached pages
 }
 
 // User represents a Notion user
@@ -43,7 +78,10 @@ type Page struct {
 	URL            string                 `json:"url"`
 	Object         string                 `json:"object"`
 	Title          string                 // Extracted from properties
+	Project        string                 `json:"project,omitempty"`   // Extracted from properties, before overrides
+	WorkTime       string                 `json:"work_time,omitempty"` // Extracted from properties
 	DatabaseTitle  string                 // Database name if page is in database
+	WordCount      int                    `json:"word_count,omitempty"` // Words in the page body, counted for created pages
 }
 
 // SearchResponse represents Notion search API response
@@ -79,24 +117,28 @@ type WorkPatterns struct {
 }
 
 // NewNotionAnalyzer creates a new Notion analyzer
-func NewNotionAnalyzer() *NotionAnalyzer {
+func NewNotionAnalyzer() (*NotionAnalyzer, error) {
 	client := common.NewHTTPClient()
 
 	// Load category configuration
 	categoryConfig, err := config.LoadCategorizationConfig("")
 	if err != nil {
-		// Return nil to indicate initialization failure
-		// The caller should handle this error
-		fmt.Printf("Error: Failed to load category config: %v\n", err)
-		return nil
+		return nil, common.WrapError(err, "failed to load category config")
+	}
+
+	overrides, err := config.LoadOverrides("", categoryConfig)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to load overrides")
 	}
 
 	return &NotionAnalyzer{
 		token:          os.Getenv("NOTION_TOKEN"),
 		client:         client,
 		categoryConfig: categoryConfig,
+		overrides:      overrides,
 		relationCache:  make(map[string]string),
-	}
+		titles:         loadTitleCache(titleCachePath, titleCacheTTLFromEnv()),
+	}, nil
 }
 
 // GetName returns the analyzer name
@@ -104,54 +146,134 @@ func (n *NotionAnalyzer) GetName() string {
 	return "Notion"
 }
 
-// ValidateConfig validates the required configuration
-func (n *NotionAnalyzer) ValidateConfig() error {
+// SetCategoryConfig replaces the categorization rules used by subsequent runs
+func (n *NotionAnalyzer) SetCategoryConfig(categoryConfig *config.CategorizationConfig) {
+	n.categoryConfig = categoryConfig
+}
+
+// SetOverrides replaces the manual overrides used by subsequent runs
+func (n *NotionAnalyzer) SetOverrides(overrides *config.Overrides) {
+	n.overrides = overrides
+}
+
+// rawData is the stored form of fetched pages, including resolved relation titles
+// so that reports can be rendered again without calling the API
+type rawData struct {
+	TargetUserID   string            `json:"target_user_id"`
+	Pages          []Page            `json:"pages"`
+	DoneTasks      []DoneTask        `json:"done_tasks,omitempty"`
+	RelationTitles map[string]string `json:"relation_titles"`
+}
+
+// SaveRawData stores the pages fetched by the last run so they can be recategorized later
+func (n *NotionAnalyzer) SaveRawData(path string) error {
+	if n.cachedRange == "" {
+		return common.NewError("no Notion pages have been fetched")
+	}
+	return common.WriteJSONFile(path, rawData{
+		TargetUserID:   n.cachedUserID,
+		Pages:          n.cachedPages,
+		DoneTasks:      n.cachedTasks,
+		RelationTitles: n.relationCache,
+	})
+}
+
+// LoadRawData loads stored pages; runs for the same period use them instead of calling the API
+func (n *NotionAnalyzer) LoadRawData(path string, config *common.Config) error {
+	var data rawData
+	if err := common.ReadJSONFile(path, &data); err != nil {
+		return err
+	}
+	n.cachedPages = data.Pages
+	n.cachedTasks = data.DoneTasks
+	n.cachedUserID = data.TargetUserID
+	n.cachedRange = config.PeriodLabel()
+	for pageID, title := range data.RelationTitles {
+		n.relationCache[pageID] = title
+	}
+	return nil
+}
+
+// ValidateConfig validates the required configuration and verifies integration capabilities up front
+func (n *NotionAnalyzer) ValidateConfig(writer io.Writer) error {
 	if n.token == "" {
 		return common.NewError("NOTION_TOKEN environment variable is required")
 	}
+
+	configureClient(n.client, n.token, writer)
+
+	fmt.Fprintln(writer, "Checking Notion integration capabilities...")
+
+	// Token validity
+	if _, err := n.client.Get(fmt.Sprintf("%s/users/me", notionAPIURL), nil); err != nil {
+		if common.HTTPStatusCode(err) == 401 {
+			return common.NewError("NOTION_TOKEN is invalid.\n" +
+				"Copy the Internal Integration Secret from https://www.notion.so/my-integrations")
+		}
+		return common.WrapError(err, "failed to connect to Notion API")
+	}
+
+	// "Read content" capability is required to search pages
+	body, err := n.client.Post(fmt.Sprintf("%s/search", notionAPIURL), `{"page_size": 1}`, nil)
+	if err != nil {
+		if common.HTTPStatusCode(err) == 403 {
+			return common.NewError("Notion integration lacks the 'Read content' capability.\n" +
+				"Enable it under Capabilities at https://www.notion.so/my-integrations")
+		}
+		return common.WrapError(err, "failed to search Notion pages")
+	}
+
+	var response SearchResponse
+	if err := json.Unmarshal(body, &response); err == nil && len(response.Results) == 0 {
+		fmt.Fprintln(writer, "⚠️  Notion integration cannot see any pages. Share your pages or teamspaces with the integration (··· → Connections).")
+	}
+
+	// "Read user information" capability is needed to resolve creator names
+	if _, err := n.client.Get(fmt.Sprintf("%s/users?page_size=1", notionAPIURL), nil); err != nil {
+		if common.HTTPStatusCode(err) == 403 {
+			fmt.Fprintln(writer, "⚠️  Notion integration lacks the 'Read user information' capability. Creator names will be shown as '-'.")
+		} else {
+			fmt.Fprintf(writer, "Warning: Failed to check user information capability: %v\n", err)
+		}
+	}
+
+	fmt.Fprintln(writer, "✓ Notion integration has required capabilities")
 	return nil
 }
 
 // Analyze performs Notion analysis
 func (n *NotionAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
-	if err := n.ValidateConfig(); err != nil {
+	if err := n.ignoreList.Reload(); err != nil {
 		return nil, err
 	}
 
-	n.client.SetHeader("Authorization", "Bearer "+n.token)
-	n.client.SetHeader("Notion-Version", apiVersion)
-	n.client.SetHeader("Content-Type", "application/json")
-
-	// Get current user
-	currentUser, err := n.getCurrentUser()
+	tasksConfig, err := loadTasksConfig()
 	if err != nil {
-		return nil, common.WrapError(err, "failed to get current user")
+		return nil, err
 	}
 
-	fmt.Fprintf(writer, "Analyzing Notion activity for user: %s (ID: %s)\n", currentUser.Name, currentUser.ID)
-
-	// Auto-detect the actual user ID
-	fmt.Fprintln(writer, "Auto-detecting user ID from workspace pages...")
-	detectedUserID := n.detectActualUserID(writer)
+	var pages []Page
+	var doneTasks []DoneTask
 	var targetUserID string
-
-	if detectedUserID != "" && detectedUserID != currentUser.ID {
-		fmt.Fprintf(writer, "Detected workspace user ID: %s (different from Integration Token user: %s)\n", detectedUserID, currentUser.ID)
-		targetUserID = detectedUserID
+	if n.cachedRange == config.PeriodLabel() {
+		fmt.Fprintf(writer, "Reusing %d Notion pages fetched earlier\n", len(n.cachedPages))
+		pages, doneTasks, targetUserID = n.cachedPages, n.cachedTasks, n.cachedUserID
 	} else {
-		fmt.Fprintf(writer, "Using Integration Token user ID: %s\n", currentUser.ID)
-		targetUserID = currentUser.ID
-	}
-
-	// Search for pages
-	fmt.Fprintln(writer, "Searching for pages...")
-	pages, err := n.searchPages(writer, targetUserID, config.StartDate, config.EndDate)
-	if err != nil {
-		return nil, common.WrapError(err, "failed to search pages")
+		n.warnings.Reset()
+		fetched, userID, err := n.fetchPages(config, writer)
+		if err != nil {
+			return nil, err
+		}
+		pages, targetUserID = fetched, userID
+		if len(tasksConfig.Databases) > 0 {
+			doneTasks = n.fetchDoneTasks(writer, tasksConfig, userID, config.StartDate, config.EndDate)
+		}
+		n.cachedPages, n.cachedTasks, n.cachedUserID, n.cachedRange = fetched, doneTasks, userID, config.PeriodLabel()
 	}
+	doneTasks = n.filterIgnoredTasks(doneTasks)
 
 	// Categorize pages
-	createdPages, updatedPages := n.categorizePages(pages, targetUserID)
+	createdPages, updatedPages := n.categorizePages(n.filterIgnored(writer, pages), targetUserID)
 
 	// Analyze categories and patterns
 	categoryStats := n.analyzeCategoryStats(createdPages, updatedPages)
@@ -162,18 +284,18 @@ func (n *NotionAnalyzer) Analyze(config *common.Config, writer io.Writer) (*comm
 		AnalyzerName: n.GetName(),
 		StartDate:    config.StartDate,
 		EndDate:      config.EndDate,
-		Summary: map[string]interface{}{
-			"Pages created":      len(createdPages),
-			"Pages updated":      len(updatedPages),
-			"Total activity":     len(createdPages) + len(updatedPages),
-			"Total pages found":  len(pages),
-			"Work categories":    len(categoryStats.Categories),
-			"Daily work logs":    categoryStats.DailyWorkLogs,
-			"Meeting notes":      categoryStats.MeetingNotes,
-			"Technical docs":     categoryStats.TechnicalDocs,
-			"Project planning":   categoryStats.ProjectPlanning,
-			"Peak activity day":  workPatterns.PeakDay,
-			"Peak activity hour": workPatterns.PeakHour,
+		Metrics: []common.Metric{
+			{ID: "notion.pages_created", Label: "Pages created", Value: len(createdPages)},
+			{ID: "notion.pages_updated", Label: "Pages updated", Value: len(updatedPages)},
+			{ID: "notion.pages_active", Label: "Total activity", Value: len(createdPages) + len(updatedPages)},
+			{ID: "notion.pages_found", Label: "Total pages found", Value: len(pages)},
+			{ID: "notion.work_categories", Label: "Work categories", Value: len(categoryStats.Categories), Snapshot: true},
+			{ID: "notion.daily_work_logs", Label: "Daily work logs", Value: categoryStats.DailyWorkLogs},
+			{ID: "notion.meeting_notes", Label: "Meeting notes", Value: categoryStats.MeetingNotes},
+			{ID: "notion.technical_docs", Label: "Technical docs", Value: categoryStats.TechnicalDocs},
+			{ID: "notion.project_planning", Label: "Project planning", Value: categoryStats.ProjectPlanning},
+			{ID: "notion.peak_day", Label: "Peak activity day", Value: workPatterns.PeakDay, Snapshot: true},
+			{ID: "notion.peak_hour", Label: "Peak activity hour", Value: workPatterns.PeakHour, Snapshot: true},
 		},
 		Details: map[string]interface{}{
 			"created_pages":  createdPages,
@@ -182,9 +304,27 @@ func (n *NotionAnalyzer) Analyze(config *common.Config, writer io.Writer) (*comm
 			"category_stats": categoryStats,
 			"work_patterns":  workPatterns,
 		},
+		Activities: n.buildActivities(createdPages, updatedPages),
+	}
+	result.CSVTables = n.csvTables(createdPages, updatedPages, result.Activities, doneTasks, len(tasksConfig.Databases) > 0)
+	if len(tasksConfig.Databases) > 0 {
+		result.Metrics = append(result.Metrics, common.Metric{ID: "notion.tasks_done", Label: "Tasks moved to Done", Value: len(doneTasks)})
+		result.Details.(map[string]interface{})["done_tasks"] = doneTasks
+		result.Activities = append(result.Activities, n.buildTaskActivities(doneTasks)...)
+		result.Explain("notion.tasks_done", n.buildTaskActivities(doneTasks))
 	}
+	result.Explain("notion.pages_created", common.ActivitiesOfKind(result.Activities, "page_created"))
+	result.Explain("notion.pages_updated", common.ActivitiesOfKind(result.Activities, "page_updated"))
+	result.Explain("notion.pages_active", common.ActivitiesOfKind(result.Activities, "page_created", "page_updated"))
 
 	n.printResults(writer, result, createdPages, updatedPages, targetUserID, categoryStats, workPatterns)
+	if len(tasksConfig.Databases) > 0 {
+		printDoneTasks(writer, doneTasks)
+	}
+	if err := n.titles.save(titleCachePath); err != nil {
+		fmt.Fprintf(writer, "Warning: Failed to save Notion title cache: %v\n", err)
+	}
+	result.Warnings = n.warnings.List()
 	return result, nil
 }
 
@@ -258,7 +398,8 @@ func (n *NotionAnalyzer) detectActualUserID(writer io.Writer) string {
 	maxCount := 0
 
 	for userID, count := range userIDCounts {
-		if count > maxCount {
+		// Ties go to the smaller ID so that the detected user does not depend on map order
+		if count > maxCount || (count == maxCount && userID < mostCommonUserID) {
 			maxCount = count
 			mostCommonUserID = userID
 		}
@@ -274,8 +415,7 @@ func (n *NotionAnalyzer) searchPages(writer io.Writer, userID string, startDate,
 	consecutiveOldPages := 0
 	maxConsecutiveOldPages := 500
 
-	// Cache for database titles and user names
-	databaseCache := make(map[string]string)
+	// Cache for user names (database titles are cached in n.titles)
 	userCache := make(map[string]string)
 
 	fmt.Fprintf(writer, "Searching pages (stopping when %d consecutive pages are outside date range)...\n", maxConsecutiveOldPages)
@@ -354,14 +494,14 @@ func (n *NotionAnalyzer) searchPages(writer io.Writer, userID string, startDate,
 
 					// Try to get database title if this page is in a database
 					if parent, ok := n.parseDatabaseParent(result); ok && parent != "" {
-						if cachedTitle, exists := databaseCache[parent]; exists {
+						if cachedTitle, exists := n.titles.database(parent); exists {
 							page.DatabaseTitle = cachedTitle
 						} else {
-							if database, err := n.getDatabase(parent); err == nil {
-								if len(database.Title) > 0 {
-									page.DatabaseTitle = database.Title[0].PlainText
-									databaseCache[parent] = page.DatabaseTitle
-								}
+							if database, err := n.getDatabase(parent); err != nil {
+								n.warnings.Add("database title", parent, err)
+							} else if len(database.Title) > 0 {
+								page.DatabaseTitle = database.Title[0].PlainText
+								n.titles.setDatabase(parent, page.DatabaseTitle)
 							}
 						}
 					}
@@ -371,14 +511,20 @@ func (n *NotionAnalyzer) searchPages(writer io.Writer, userID string, startDate,
 						if cachedName, exists := userCache[page.CreatedBy.ID]; exists {
 							page.CreatedBy.Name = cachedName
 						} else {
-							if userName := n.getUserName(page.CreatedBy.ID); userName != "" {
-								page.CreatedBy.Name = userName
-								userCache[page.CreatedBy.ID] = userName
+							// Failed lookups are cached too, so each user is tried once
+							userName, err := n.lookupUserName(page.CreatedBy.ID)
+							if err != nil {
+								n.warnings.Add("user name", page.CreatedBy.ID, err)
 							}
+							page.CreatedBy.Name = userName
+							userCache[page.CreatedBy.ID] = userName
 						}
 					}
 
+					// Keep only the extracted values: full property maps dominate memory on long periods
 					page.Title = n.extractPageTitle(page)
+					page.Project, page.WorkTime = n.extractProperties(page)
+					page.Properties = nil
 					allPages = append(allPages, page)
 				}
 			}
@@ -461,18 +607,24 @@ func (n *NotionAnalyzer) getDatabase(databaseID string) (*Database, error) {
 }
 
 func (n *NotionAnalyzer) getUserName(userID string) string {
+	name, _ := n.lookupUserName(userID)
+	return name
+}
+
+// lookupUserName returns the display name of a user (requires the user information capability)
+func (n *NotionAnalyzer) lookupUserName(userID string) (string, error) {
 	url := fmt.Sprintf("%s/users/%s", notionAPIURL, userID)
 	body, err := n.client.Get(url, nil)
 	if err != nil {
-		return ""
+		return "", err
 	}
 
 	var user User
 	if err := json.Unmarshal(body, &user); err != nil {
-		return ""
+		return "", common.WrapError(err, "failed to parse user response")
 	}
 
-	return user.Name
+	return user.Name, nil
 }
 
 // getRelatedPageTitle retrieves the title of a related page by its ID with caching
@@ -481,12 +633,17 @@ func (n *NotionAnalyzer) getRelatedPageTitle(pageID string) string {
 	if title, exists := n.relationCache[pageID]; exists {
 		return title
 	}
+	if title, exists := n.titles.relation(pageID); exists {
+		n.relationCache[pageID] = title
+		return title
+	}
 
 	url := fmt.Sprintf("%s/pages/%s", notionAPIURL, pageID)
 	body, err := n.client.Get(url, nil)
 	if err != nil {
 		// Cache empty result to avoid repeated failed requests
 		n.relationCache[pageID] = ""
+		n.warnings.Add("related page title", pageID, err)
 		return ""
 	}
 
@@ -494,12 +651,14 @@ func (n *NotionAnalyzer) getRelatedPageTitle(pageID string) string {
 	if err := json.Unmarshal(body, &page); err != nil {
 		// Cache empty result to avoid repeated failed requests
 		n.relationCache[pageID] = ""
+		n.warnings.Add("related page title", pageID, err)
 		return ""
 	}
 
 	title := n.extractPageTitle(page)
 	// Cache the result for future use
 	n.relationCache[pageID] = title
+	n.titles.setRelation(pageID, title)
 	return title
 }
 
@@ -594,14 +753,35 @@ func (n *NotionAnalyzer) extractPropertyValue(property interface{}) string {
 	return ""
 }
 
-// getPageProperties extracts specific properties from a page
+// sortedPropertyNames returns the property names in order, so that the last matching property wins the same way every run
+func sortedPropertyNames(properties map[string]interface{}) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getPageProperties returns the project and work time of a page, with the project of config/overrides.yaml applied
+// on every call so that watch and recategorize pick up changed overrides
 func (n *NotionAnalyzer) getPageProperties(page Page) (project string, workTime string) {
+	project, workTime = n.extractProperties(page)
+	if override, exists := n.overrides.Lookup(page.ID, page.URL); exists && override.Project != "" {
+		project = override.Project
+	}
+	return project, workTime
+}
+
+// extractProperties reads the project and work time from the page properties, or the values extracted before the
+// properties were released
+func (n *NotionAnalyzer) extractProperties(page Page) (project string, workTime string) {
 	if page.Properties == nil {
-		return "", ""
+		return page.Project, page.WorkTime
 	}
 
-	// Debug: log all property names to understand the structure
-	for propName, propValue := range page.Properties {
+	for _, propName := range sortedPropertyNames(page.Properties) {
+		propValue := page.Properties[propName]
 		propNameLower := strings.ToLower(propName)
 
 		// Check for project-related properties (supports multiple languages)
@@ -622,12 +802,39 @@ func (n *NotionAnalyzer) getPageProperties(page Page) (project string, workTime
 	return project, workTime
 }
 
-func (n *NotionAnalyzer) categorizePages(pages []Page, userID string) (created []Page, updated []Page) {
-	// Get specified user ID from environment, fallback to detected user ID
-	specifiedUserID := os.Getenv("NOTION_USER_ID")
-	if specifiedUserID == "" {
-		specifiedUserID = userID
+// WhoAmI reports the workspace user whose pages are analyzed (NOTION_USER_ID, or detected from recently edited pages)
+func (n *NotionAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if n.token == "" {
+		return nil, common.NewError("NOTION_TOKEN environment variable is required")
 	}
+	configureClient(n.client, n.token, writer)
+
+	bot, err := n.getCurrentUser()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get the integration user")
+	}
+	userID := n.effectiveUserID(n.detectActualUserID(writer))
+	if userID == "" {
+		userID = bot.ID
+	}
+
+	identity := &common.Identity{Source: n.GetName(), ID: userID, Name: n.getUserName(userID)}
+	if userID != bot.ID {
+		identity.Note = fmt.Sprintf("integration: %s (%s)", bot.Name, bot.ID)
+	}
+	return identity, nil
+}
+
+// effectiveUserID returns NOTION_USER_ID if set, falling back to the detected user ID
+func (n *NotionAnalyzer) effectiveUserID(userID string) string {
+	if specifiedUserID := os.Getenv("NOTION_USER_ID"); specifiedUserID != "" {
+		return specifiedUserID
+	}
+	return userID
+}
+
+func (n *NotionAnalyzer) categorizePages(pages []Page, userID string) (created []Page, updated []Page) {
+	specifiedUserID := n.effectiveUserID(userID)
 
 	for _, page := range pages {
 		if page.CreatedBy.ID == specifiedUserID {
@@ -640,6 +847,163 @@ func (n *NotionAnalyzer) categorizePages(pages []Page, userID string) (created [
 	return created, updated
 }
 
+// filterIgnored drops pages whose ID or URL is listed in the ignore file
+func (n *NotionAnalyzer) filterIgnored(writer io.Writer, pages []Page) []Page {
+	return config.FilterIgnored(writer, &n.ignoreList, pages, "pages", func(page Page) []string {
+		return []string{page.ID, page.URL}
+	})
+}
+
+// fetchPages validates the integration and searches pages for the target user
+func (n *NotionAnalyzer) fetchPages(config *common.Config, writer io.Writer) ([]Page, string, error) {
+	if err := n.ValidateConfig(writer); err != nil {
+		return nil, "", err
+	}
+
+	// Get current user
+	currentUser, err := n.getCurrentUser()
+	if err != nil {
+		return nil, "", common.WrapError(err, "failed to get current user")
+	}
+
+	fmt.Fprintf(writer, "Analyzing Notion activity for user: %s (ID: %s)\n", currentUser.Name, currentUser.ID)
+
+	// Auto-detect the actual user ID
+	fmt.Fprintln(writer, "Auto-detecting user ID from workspace pages...")
+	detectedUserID := n.detectActualUserID(writer)
+	var targetUserID string
+
+	if detectedUserID != "" && detectedUserID != currentUser.ID {
+		fmt.Fprintf(writer, "Detected workspace user ID: %s (different from Integration Token user: %s)\n", detectedUserID, currentUser.ID)
+		targetUserID = detectedUserID
+	} else {
+		fmt.Fprintf(writer, "Using Integration Token user ID: %s\n", currentUser.ID)
+		targetUserID = currentUser.ID
+	}
+
+	// Search for pages
+	fmt.Fprintln(writer, "Searching for pages...")
+	pages, err := n.searchPages(writer, targetUserID, config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, "", common.WrapError(err, "failed to search pages")
+	}
+
+	// Word counts feed the effort estimation; only pages the user created are counted
+	fmt.Fprintln(writer, "Counting words of created pages...")
+	specifiedUserID := n.effectiveUserID(targetUserID)
+	for i := range pages {
+		if pages[i].CreatedBy.ID != specifiedUserID {
+			continue
+		}
+		count, err := n.countWords(pages[i].ID)
+		if err != nil {
+			n.warnings.Add("page blocks (word count)", pages[i].Title, err)
+			continue
+		}
+		pages[i].WordCount = count
+	}
+
+	return pages, targetUserID, nil
+}
+
+// countWords counts words in the top-level blocks of a page.
+// CJK characters are counted individually since such text has no spaces between words.
+func (n *NotionAnalyzer) countWords(pageID string) (int, error) {
+	count := 0
+	cursor := ""
+	for {
+		url := fmt.Sprintf("%s/blocks/%s/children?page_size=100", notionAPIURL, pageID)
+		if cursor != "" {
+			url += "&start_cursor=" + cursor
+		}
+		body, err := n.client.Get(url, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		var response struct {
+			Results    []map[string]interface{} `json:"results"`
+			HasMore    bool                     `json:"has_more"`
+			NextCursor string                   `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return 0, err
+		}
+
+		for _, block := range response.Results {
+			blockType, _ := block["type"].(string)
+			blockData, ok := block[blockType].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if richText, ok := blockData["rich_text"].([]interface{}); ok {
+				count += countWordsInText(n.extractTextFromRichTextArray(richText))
+			}
+		}
+
+		if !response.HasMore || response.NextCursor == "" {
+			return count, nil
+		}
+		cursor = response.NextCursor
+	}
+}
+
+// countWordsInText counts space-separated words, with each CJK character counted as one word
+func countWordsInText(text string) int {
+	count := 0
+	for _, field := range strings.Fields(text) {
+		latin := false
+		for _, r := range field {
+			if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+				count++
+				if latin {
+					count++
+					latin = false
+				}
+				continue
+			}
+			latin = true
+		}
+		if latin {
+			count++
+		}
+	}
+	return count
+}
+
+// buildActivities converts created and updated pages into dated activities
+func (n *NotionAnalyzer) buildActivities(createdPages, updatedPages []Page) []common.Activity {
+	var activities []common.Activity
+	for _, page := range createdPages {
+		project, _ := n.getPageProperties(page)
+		activities = append(activities, common.Activity{
+			Source:   n.GetName(),
+			Kind:     "page_created",
+			ID:       page.ID,
+			Title:    page.Title,
+			URL:      page.URL,
+			Time:     page.CreatedTime,
+			Category: n.pageCategory(page),
+			Project:  project,
+			Size:     page.WordCount,
+		})
+	}
+	for _, page := range updatedPages {
+		project, _ := n.getPageProperties(page)
+		activities = append(activities, common.Activity{
+			Source:   n.GetName(),
+			Kind:     "page_updated",
+			ID:       page.ID,
+			Title:    page.Title,
+			URL:      page.URL,
+			Time:     page.LastEditedTime,
+			Category: n.pageCategory(page),
+			Project:  project,
+		})
+	}
+	return activities
+}
+
 func (n *NotionAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, createdPages, updatedPages []Page, targetUserID string, categoryStats *CategoryStats, workPatterns *WorkPatterns) {
 	userIDDisplay := targetUserID
 	if len(targetUserID) > 8 {
@@ -728,10 +1092,7 @@ func (n *NotionAnalyzer) analyzeCategoryStats(createdPages, updatedPages []Page)
 	allPages := append(createdPages, updatedPages...)
 
 	for _, page := range allPages {
-		title := strings.ToLower(page.Title)
-
-		// Categorize by title patterns using configuration
-		category := n.categoryConfig.CategorizeNotionPage(title)
+		category := n.pageCategory(page)
 
 		switch category {
 		case "daily work log":
@@ -776,19 +1137,29 @@ func (n *NotionAnalyzer) analyzeWorkPatterns(createdPages, updatedPages []Page)
 	maxHourActivity := 0
 	maxDayActivity := 0
 
-	for hour, count := range patterns.HourlyActivity {
-		if count > maxHourActivity {
+	// Walk hours and weekdays in order so that ties go to the earliest one
+	for hour := 0; hour < 24; hour++ {
+		if count := patterns.HourlyActivity[hour]; count > maxHourActivity {
 			maxHourActivity = count
 			patterns.PeakHour = hour
 		}
 	}
 
-	for day, count := range patterns.DailyActivity {
-		if count > maxDayActivity {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if count := patterns.DailyActivity[weekday.String()]; count > maxDayActivity {
 			maxDayActivity = count
-			patterns.PeakDay = day
+			patterns.PeakDay = weekday.String()
 		}
 	}
 
 	return patterns
 }
+
+// pageCategory categorizes a page by title patterns using configuration.
+// Manual overrides take precedence over keyword rules.
+func (n *NotionAnalyzer) pageCategory(page Page) string {
+	if override, exists := n.overrides.Lookup(page.ID, page.URL); exists && override.Category != "" {
+		return override.Category
+	}
+	return n.categoryConfig.CategorizeNotionPage(strings.ToLower(page.Title))
+}
@@ -0,0 +1,63 @@
+package notion
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// pageCSVRow is one page in notion-pages.csv
+type pageCSVRow struct {
+	Relation    string    `csv:"relation"`
+	Title       string    `csv:"title"`
+	Database    string    `csv:"database"`
+	Category    string    `csv:"category"`
+	Project     string    `csv:"project"`
+	CreatedBy   string    `csv:"created_by"`
+	CreatedTime time.Time `csv:"created_time"`
+	LastEdited  time.Time `csv:"last_edited_time"`
+	WordCount   int       `csv:"word_count"`
+	URL         string    `csv:"url"`
+}
+
+// taskCSVRow is one task in notion-tasks.csv
+type taskCSVRow struct {
+	Database    string    `csv:"database"`
+	Title       string    `csv:"title"`
+	CompletedAt time.Time `csv:"completed_at"`
+	URL         string    `csv:"url"`
+}
+
+// csvTables lists created and updated pages, and tasks moved to Done when task databases are configured.
+// pageActivities are built from the pages in the same order and carry the categories and projects after overrides.
+func (n *NotionAnalyzer) csvTables(createdPages, updatedPages []Page, pageActivities []common.Activity, doneTasks []DoneTask, hasTaskDatabases bool) []common.CSVTable {
+	var rows []pageCSVRow
+	for i, page := range append(append([]Page{}, createdPages...), updatedPages...) {
+		relation := "updated"
+		if i < len(createdPages) {
+			relation = "created"
+		}
+		rows = append(rows, pageCSVRow{
+			Relation:    relation,
+			Title:       page.Title,
+			Database:    page.DatabaseTitle,
+			Category:    pageActivities[i].Category,
+			Project:     pageActivities[i].Project,
+			CreatedBy:   page.CreatedBy.Name,
+			CreatedTime: page.CreatedTime,
+			LastEdited:  page.LastEditedTime,
+			WordCount:   page.WordCount,
+			URL:         page.URL,
+		})
+	}
+	tables := []common.CSVTable{common.NewCSVTable("pages", rows)}
+
+	if hasTaskDatabases {
+		var tasks []taskCSVRow
+		for _, task := range doneTasks {
+			tasks = append(tasks, taskCSVRow{Database: task.Database, Title: task.Title, CompletedAt: task.CompletedAt, URL: task.URL})
+		}
+		tables = append(tables, common.NewCSVTable("tasks", tasks))
+	}
+	return tables
+}
@@ -0,0 +1,147 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// DatabaseSummary is a database the integration can access
+type DatabaseSummary struct {
+	ID             string
+	Title          string
+	URL            string
+	LastEditedTime time.Time
+	// Properties usable in config/notion-tasks.yaml, by role
+	StatusProperties []string // status, select, checkbox
+	DateProperties   []string
+	PeopleProperties []string
+}
+
+// databaseSearchResult is a database object returned by /search
+type databaseSearchResult struct {
+	ID    string `json:"id"`
+	URL   string `json:"url"`
+	Title []struct {
+		PlainText string `json:"plain_text"`
+	} `json:"title"`
+	LastEditedTime time.Time `json:"last_edited_time"`
+	Properties     map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+}
+
+// ListDatabases returns every database shared with the integration, sorted by title
+func (n *NotionAnalyzer) ListDatabases(writer io.Writer) ([]DatabaseSummary, error) {
+	if n.token == "" {
+		return nil, common.NewError("NOTION_TOKEN environment variable is required")
+	}
+	configureClient(n.client, n.token, writer)
+
+	var databases []DatabaseSummary
+	cursor := ""
+	for {
+		request := map[string]interface{}{
+			"filter":    map[string]string{"property": "object", "value": "database"},
+			"page_size": 100,
+		}
+		if cursor != "" {
+			request["start_cursor"] = cursor
+		}
+		payload, err := json.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+		body, err := n.client.Post(fmt.Sprintf("%s/search", notionAPIURL), string(payload), nil)
+		if err != nil {
+			return nil, common.WrapError(err, "failed to search databases")
+		}
+
+		var response SearchResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse search response")
+		}
+		for _, raw := range response.Results {
+			var result databaseSearchResult
+			if err := json.Unmarshal(raw, &result); err != nil {
+				continue
+			}
+			databases = append(databases, summarizeDatabase(result))
+		}
+		fmt.Fprintf(writer, "Found %d databases so far...\n", len(databases))
+
+		if !response.HasMore || response.NextCursor == "" {
+			break
+		}
+		cursor = response.NextCursor
+	}
+
+	sort.SliceStable(databases, func(i, j int) bool {
+		return strings.ToLower(databases[i].Title) < strings.ToLower(databases[j].Title)
+	})
+	return databases, nil
+}
+
+func summarizeDatabase(result databaseSearchResult) DatabaseSummary {
+	var title strings.Builder
+	for _, part := range result.Title {
+		title.WriteString(part.PlainText)
+	}
+	summary := DatabaseSummary{
+		ID:             strings.ReplaceAll(result.ID, "-", ""),
+		Title:          title.String(),
+		URL:            result.URL,
+		LastEditedTime: result.LastEditedTime,
+	}
+	if summary.Title == "" {
+		summary.Title = "(untitled)"
+	}
+
+	for name, property := range result.Properties {
+		switch property.Type {
+		case "status", "select", "checkbox":
+			summary.StatusProperties = append(summary.StatusProperties, fmt.Sprintf("%s (%s)", name, property.Type))
+		case "date":
+			summary.DateProperties = append(summary.DateProperties, name)
+		case "people":
+			summary.PeopleProperties = append(summary.PeopleProperties, name)
+		}
+	}
+	sort.Strings(summary.StatusProperties)
+	sort.Strings(summary.DateProperties)
+	sort.Strings(summary.PeopleProperties)
+	return summary
+}
+
+// PrintDatabases prints databases with their IDs and the properties that config/notion-tasks.yaml can refer to
+func PrintDatabases(writer io.Writer, databases []DatabaseSummary) {
+	fmt.Fprintf(writer, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(writer, "NOTION DATABASES (%d)\n", len(databases))
+	fmt.Fprintf(writer, strings.Repeat("=", 60)+"\n")
+
+	if len(databases) == 0 {
+		fmt.Fprintln(writer, "No databases are shared with the integration (··· → Connections on the database or its parent page).")
+		return
+	}
+
+	for _, database := range databases {
+		fmt.Fprintf(writer, "\n%s\n", database.Title)
+		fmt.Fprintf(writer, "  ID: %s\n", database.ID)
+		fmt.Fprintf(writer, "  URL: %s\n", database.URL)
+		fmt.Fprintf(writer, "  Last edited: %s\n", database.LastEditedTime.Local().Format("2006-01-02"))
+		if len(database.StatusProperties) > 0 {
+			fmt.Fprintf(writer, "  Status properties: %s\n", strings.Join(database.StatusProperties, ", "))
+		}
+		if len(database.DateProperties) > 0 {
+			fmt.Fprintf(writer, "  Date properties: %s\n", strings.Join(database.DateProperties, ", "))
+		}
+		if len(database.PeopleProperties) > 0 {
+			fmt.Fprintf(writer, "  People properties: %s\n", strings.Join(database.PeopleProperties, ", "))
+		}
+	}
+}
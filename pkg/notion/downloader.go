@@ -9,7 +9,6 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-	"time"
 
 	"dev-stats/pkg/common"
 )
@@ -167,9 +166,7 @@ func (d *NotionDownloader) DownloadPages(config *DownloadConfig, writer io.Write
 		return err
 	}
 
-	d.client.SetHeader("Authorization", "Bearer "+d.token)
-	d.client.SetHeader("Notion-Version", apiVersion)
-	d.client.SetHeader("Content-Type", "application/json")
+	configureClient(d.client, d.token, writer)
 
 	fmt.Fprintf(writer, "Starting download of %d categories to: %s\n", len(config.Categories), config.OutputDir)
 
@@ -220,9 +217,6 @@ func (d *NotionDownloader) DownloadPages(config *DownloadConfig, writer io.Write
 
 			downloadedCount++
 			fmt.Fprintf(writer, "    ✓ Downloaded (%d/%d): %s\n", downloadedCount, totalPages, actualTitle)
-
-			// Rate limiting
-			time.Sleep(500 * time.Millisecond)
 		}
 	}
 
@@ -574,7 +568,8 @@ func (d *NotionDownloader) getPageProperties(page Page) (project string, workTim
 		return "", ""
 	}
 
-	for propName, propValue := range page.Properties {
+	for _, propName := range sortedPropertyNames(page.Properties) {
+		propValue := page.Properties[propName]
 		propNameLower := strings.ToLower(propName)
 
 		// Check for project-related properties (supports multiple languages)
@@ -0,0 +1,232 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+// DoneTask is a task in a configured task database that reached a done status within the period
+type DoneTask struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Database    string    `json:"database"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// databaseQueryResponse is the response of /databases/{id}/query
+type databaseQueryResponse struct {
+	Results    []Page `json:"results"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// databaseSchema holds the property types of a database
+type databaseSchema struct {
+	Properties map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+}
+
+// loadTasksConfig loads config/notion-tasks.yaml for this run
+func loadTasksConfig() (*config.NotionTasksConfig, error) {
+	return config.LoadNotionTasksConfig("")
+}
+
+// fetchDoneTasks queries each configured task database for tasks done within the period
+func (n *NotionAnalyzer) fetchDoneTasks(writer io.Writer, tasksConfig *config.NotionTasksConfig, userID string, startDate, endDate time.Time) []DoneTask {
+	var tasks []DoneTask
+	for _, database := range tasksConfig.Databases {
+		fmt.Fprintf(writer, "Querying task database %s...\n", database.Name)
+		found, err := n.queryDoneTasks(database, n.effectiveUserID(userID), startDate, endDate)
+		if err != nil {
+			n.warnings.Add("task database", database.Name, err)
+			continue
+		}
+		tasks = append(tasks, found...)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CompletedAt.Before(tasks[j].CompletedAt)
+	})
+	return tasks
+}
+
+// queryDoneTasks runs a filtered, paginated query against one task database
+func (n *NotionAnalyzer) queryDoneTasks(database config.NotionTaskDatabase, userID string, startDate, endDate time.Time) ([]DoneTask, error) {
+	body, err := n.client.Get(fmt.Sprintf("%s/databases/%s", notionAPIURL, database.ID), nil)
+	if err != nil {
+		return nil, err
+	}
+	var schema databaseSchema
+	if err := json.Unmarshal(body, &schema); err != nil {
+		return nil, common.WrapError(err, "failed to parse database response")
+	}
+	statusProperty, exists := schema.Properties[database.StatusProperty]
+	if !exists {
+		return nil, common.NewError("property '%s' not found", database.StatusProperty)
+	}
+
+	var doneFilter interface{}
+	switch statusProperty.Type {
+	case "checkbox":
+		doneFilter = map[string]interface{}{"property": database.StatusProperty, "checkbox": map[string]bool{"equals": true}}
+	case "status", "select":
+		if len(database.DoneValues) == 0 {
+			return nil, common.NewError("done_values is required for %s property '%s'", statusProperty.Type, database.StatusProperty)
+		}
+		var options []interface{}
+		for _, value := range database.DoneValues {
+			options = append(options, map[string]interface{}{"property": database.StatusProperty, statusProperty.Type: map[string]string{"equals": value}})
+		}
+		doneFilter = map[string]interface{}{"or": options}
+	default:
+		return nil, common.NewError("property '%s' is a %s property (expected status, select, or checkbox)", database.StatusProperty, statusProperty.Type)
+	}
+
+	filters := []interface{}{doneFilter}
+	start := startDate.Format("2006-01-02")
+	end := endDate.Format("2006-01-02")
+	if database.CompletedDateProperty != "" {
+		filters = append(filters,
+			map[string]interface{}{"property": database.CompletedDateProperty, "date": map[string]string{"on_or_after": start}},
+			map[string]interface{}{"property": database.CompletedDateProperty, "date": map[string]string{"on_or_before": end}})
+	} else {
+		filters = append(filters,
+			map[string]interface{}{"timestamp": "last_edited_time", "last_edited_time": map[string]string{"on_or_after": start}},
+			map[string]interface{}{"timestamp": "last_edited_time", "last_edited_time": map[string]string{"on_or_before": end}})
+	}
+	if database.AssigneeProperty != "" && userID != "" {
+		filters = append(filters, map[string]interface{}{"property": database.AssigneeProperty, "people": map[string]string{"contains": userID}})
+	}
+
+	var tasks []DoneTask
+	cursor := ""
+	for {
+		request := map[string]interface{}{
+			"filter":    map[string]interface{}{"and": filters},
+			"page_size": 100,
+		}
+		if cursor != "" {
+			request["start_cursor"] = cursor
+		}
+		payload, err := json.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+		body, err := n.client.Post(fmt.Sprintf("%s/databases/%s/query", notionAPIURL, database.ID), string(payload), nil)
+		if err != nil {
+			return nil, err
+		}
+		var response databaseQueryResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse query response")
+		}
+
+		for _, page := range response.Results {
+			completedAt := page.LastEditedTime
+			if database.CompletedDateProperty != "" {
+				if date, ok := datePropertyValue(page.Properties[database.CompletedDateProperty]); ok {
+					completedAt = date
+				}
+			}
+			tasks = append(tasks, DoneTask{
+				ID:          page.ID,
+				Title:       n.extractPageTitle(page),
+				URL:         page.URL,
+				Database:    database.Name,
+				CompletedAt: completedAt,
+			})
+		}
+
+		if !response.HasMore || response.NextCursor == "" {
+			break
+		}
+		cursor = response.NextCursor
+	}
+	return tasks, nil
+}
+
+// datePropertyValue returns the start of a date property
+func datePropertyValue(property interface{}) (time.Time, bool) {
+	prop, ok := property.(map[string]interface{})
+	if !ok {
+		return time.Time{}, false
+	}
+	date, ok := prop["date"].(map[string]interface{})
+	if !ok {
+		return time.Time{}, false
+	}
+	start, ok := date["start"].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if parsed, err := time.ParseInLocation(layout, start, time.Local); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// filterIgnoredTasks drops tasks whose ID or URL is listed in the ignore file
+func (n *NotionAnalyzer) filterIgnoredTasks(tasks []DoneTask) []DoneTask {
+	return config.FilterIgnored(nil, &n.ignoreList, tasks, "tasks", func(task DoneTask) []string {
+		return []string{task.ID, task.URL}
+	})
+}
+
+// buildTaskActivities converts done tasks into dated activities
+func (n *NotionAnalyzer) buildTaskActivities(tasks []DoneTask) []common.Activity {
+	var activities []common.Activity
+	for _, task := range tasks {
+		activities = append(activities, common.Activity{
+			Source: n.GetName(),
+			Kind:   "task_done",
+			ID:     task.ID,
+			Title:  task.Title,
+			URL:    task.URL,
+			Time:   task.CompletedAt,
+		})
+	}
+	return activities
+}
+
+// printDoneTasks prints task throughput per database and lists the tasks
+func printDoneTasks(writer io.Writer, tasks []DoneTask) {
+	fmt.Fprintf(writer, "\nTasks moved to Done (%d):\n", len(tasks))
+	if len(tasks) == 0 {
+		fmt.Fprintln(writer, "- None")
+		return
+	}
+
+	byDatabase := make(map[string]int)
+	var databases []string
+	for _, task := range tasks {
+		if byDatabase[task.Database] == 0 {
+			databases = append(databases, task.Database)
+		}
+		byDatabase[task.Database]++
+	}
+	sort.Slice(databases, func(i, j int) bool {
+		if byDatabase[databases[i]] != byDatabase[databases[j]] {
+			return byDatabase[databases[i]] > byDatabase[databases[j]]
+		}
+		return databases[i] < databases[j]
+	})
+	for _, database := range databases[:common.RankingLimit(len(databases))] {
+		fmt.Fprintf(writer, "- %s: %d\n", database, byDatabase[database])
+	}
+	common.PrintMoreEntries(writer, len(databases))
+
+	fmt.Fprintln(writer)
+	for _, task := range tasks {
+		fmt.Fprintf(writer, "- %s: %s [%s]\n", task.CompletedAt.Local().Format("2006-01-02"), task.Title, task.Database)
+		fmt.Fprintf(writer, "  %s\n", task.URL)
+	}
+}
@@ -0,0 +1,109 @@
+package notion
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// titleCachePath stores resolved relation and database titles shared across runs and periods
+const titleCachePath = ".notion-cache/titles.json"
+
+// defaultTitleCacheTTLDays is how long a resolved title is reused before it is fetched again
+const defaultTitleCacheTTLDays = 7
+
+// cachedTitle is a resolved title and when it was fetched
+type cachedTitle struct {
+	Title     string    `json:"title"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// titleCache persists relation page titles (page ID → title) and database titles (database ID → title)
+type titleCache struct {
+	Relations map[string]cachedTitle `json:"relations"`
+	Databases map[string]cachedTitle `json:"databases"`
+	ttl       time.Duration
+	dirty     bool
+}
+
+// titleCacheTTLFromEnv reads NOTION_CACHE_TTL_DAYS (default 7; 0 disables the persistent cache)
+func titleCacheTTLFromEnv() time.Duration {
+	days := defaultTitleCacheTTLDays
+	if value := os.Getenv("NOTION_CACHE_TTL_DAYS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			days = parsed
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// loadTitleCache loads the cache, dropping entries older than ttl. A missing or unreadable file means an empty cache.
+func loadTitleCache(path string, ttl time.Duration) *titleCache {
+	cache := &titleCache{ttl: ttl}
+	if ttl > 0 {
+		if _, err := os.Stat(path); err == nil {
+			_ = common.ReadJSONFile(path, cache)
+		}
+	}
+	if cache.Relations == nil {
+		cache.Relations = make(map[string]cachedTitle)
+	}
+	if cache.Databases == nil {
+		cache.Databases = make(map[string]cachedTitle)
+	}
+
+	now := time.Now()
+	for _, entries := range []map[string]cachedTitle{cache.Relations, cache.Databases} {
+		for id, entry := range entries {
+			if now.Sub(entry.FetchedAt) > ttl {
+				delete(entries, id)
+				cache.dirty = true
+			}
+		}
+	}
+	return cache
+}
+
+// relation returns the cached title of a related page
+func (c *titleCache) relation(pageID string) (string, bool) {
+	entry, exists := c.Relations[pageID]
+	return entry.Title, exists
+}
+
+// database returns the cached title of a database
+func (c *titleCache) database(databaseID string) (string, bool) {
+	entry, exists := c.Databases[databaseID]
+	return entry.Title, exists
+}
+
+// setRelation records a resolved relation title. Failed lookups aren't persisted so that they are retried next run.
+func (c *titleCache) setRelation(pageID, title string) {
+	if title == "" {
+		return
+	}
+	c.Relations[pageID] = cachedTitle{Title: title, FetchedAt: time.Now()}
+	c.dirty = true
+}
+
+// setDatabase records a resolved database title
+func (c *titleCache) setDatabase(databaseID, title string) {
+	if title == "" {
+		return
+	}
+	c.Databases[databaseID] = cachedTitle{Title: title, FetchedAt: time.Now()}
+	c.dirty = true
+}
+
+// save writes the cache when it changed
+func (c *titleCache) save(path string) error {
+	if c.ttl <= 0 || !c.dirty {
+		return nil
+	}
+	if err := common.WriteJSONFile(path, c); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
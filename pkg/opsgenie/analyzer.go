@@ -0,0 +1,402 @@
+package opsgenie
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+const defaultAPIURL = "https://api.opsgenie.com"
+
+// alertPageSize is the maximum page size of the alert list
+const alertPageSize = 100
+
+// OpsgenieAnalyzer implements the Analyzer interface for Opsgenie on-call schedules and alerts
+type OpsgenieAnalyzer struct {
+	apiKey     string
+	user       string   // username (email) of the user in Opsgenie
+	apiURL     string   // https://api.opsgenie.com, or https://api.eu.opsgenie.com for EU accounts
+	schedules  []string // OPSGENIE_SCHEDULES: schedule names to include, all when empty
+	client     *common.HTTPClient
+	ignoreList config.IgnoreList
+}
+
+// userResponse is the /v2/users/{identifier} response
+type userResponse struct {
+	Data struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		FullName string `json:"fullName"`
+	} `json:"data"`
+}
+
+// schedulesResponse is the /v2/schedules response
+type schedulesResponse struct {
+	Data []struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+	} `json:"data"`
+}
+
+// timelineResponse is the /v2/schedules/{id}/timeline response
+type timelineResponse struct {
+	Data struct {
+		FinalTimeline struct {
+			Rotations []struct {
+				Name    string `json:"name"`
+				Periods []struct {
+					StartDate time.Time `json:"startDate"`
+					EndDate   time.Time `json:"endDate"`
+					Recipient struct {
+						ID   string `json:"id"`
+						Type string `json:"type"`
+						Name string `json:"name"`
+					} `json:"recipient"`
+				} `json:"periods"`
+			} `json:"rotations"`
+		} `json:"finalTimeline"`
+	} `json:"data"`
+}
+
+// alertsResponse is the /v2/alerts response
+type alertsResponse struct {
+	Data []struct {
+		ID        string    `json:"id"`
+		TinyID    string    `json:"tinyId"`
+		Message   string    `json:"message"`
+		Priority  string    `json:"priority"`
+		CreatedAt time.Time `json:"createdAt"`
+		Report    struct {
+			AckTime        int64  `json:"ackTime"` // milliseconds from creation
+			AcknowledgedBy string `json:"acknowledgedBy"`
+			ClosedBy       string `json:"closedBy"`
+		} `json:"report"`
+	} `json:"data"`
+}
+
+// NewOpsgenieAnalyzer creates a new Opsgenie analyzer
+func NewOpsgenieAnalyzer() *OpsgenieAnalyzer {
+	apiKey := os.Getenv("OPSGENIE_API_KEY")
+	apiURL := strings.TrimRight(os.Getenv("OPSGENIE_API_URL"), "/")
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	var schedules []string
+	for _, name := range strings.Split(os.Getenv("OPSGENIE_SCHEDULES"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			schedules = append(schedules, name)
+		}
+	}
+
+	client := common.NewHTTPClient()
+	client.SetHeader("Authorization", "GenieKey "+apiKey)
+	return &OpsgenieAnalyzer{
+		apiKey:    apiKey,
+		user:      os.Getenv("OPSGENIE_USER"),
+		apiURL:    apiURL,
+		schedules: schedules,
+		client:    client,
+	}
+}
+
+// GetName returns the analyzer name
+func (o *OpsgenieAnalyzer) GetName() string {
+	return "Opsgenie"
+}
+
+// ValidateConfig validates the required configuration
+func (o *OpsgenieAnalyzer) ValidateConfig(writer io.Writer) error {
+	if o.apiKey == "" || o.user == "" {
+		return common.NewError("OPSGENIE_API_KEY and OPSGENIE_USER environment variables are required")
+	}
+	if _, err := o.getUser(); err != nil {
+		return common.WrapError(err, "failed to look up OPSGENIE_USER in Opsgenie (check OPSGENIE_API_KEY, OPSGENIE_USER, and OPSGENIE_API_URL)")
+	}
+	fmt.Fprintln(writer, "✓ Opsgenie API key is valid")
+	return nil
+}
+
+// WhoAmI reports the Opsgenie user named by OPSGENIE_USER
+func (o *OpsgenieAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if o.apiKey == "" || o.user == "" {
+		return nil, common.NewError("OPSGENIE_API_KEY and OPSGENIE_USER environment variables are required")
+	}
+	user, err := o.getUser()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to look up OPSGENIE_USER in Opsgenie")
+	}
+	return &common.Identity{Source: o.GetName(), ID: user.Data.ID, Login: user.Data.Username, Name: user.Data.FullName}, nil
+}
+
+// Analyze reports the time on call and the alerts the user acknowledged or closed
+func (o *OpsgenieAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := o.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := o.ignoreList.Reload(); err != nil {
+		return nil, err
+	}
+	user, err := o.getUser()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get Opsgenie user")
+	}
+
+	fmt.Fprintf(writer, "Fetching Opsgenie on-call shifts from %s to %s...\n",
+		config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+	shifts, err := o.getShifts(writer, user.Data.ID, config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get on-call shifts")
+	}
+
+	fmt.Fprintln(writer, "Fetching Opsgenie alerts...")
+	alerts, err := o.getAlerts(config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get alerts")
+	}
+	alerts = o.filterIgnored(writer, alerts)
+
+	stats := &common.OnCallStats{Shifts: shifts, Alerts: alerts}
+	acknowledged, closed := 0, 0
+	for _, alert := range alerts {
+		if !alert.AcknowledgedAt.IsZero() {
+			acknowledged++
+		}
+		if alert.Closed {
+			closed++
+		}
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: o.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "opsgenie.oncall_hours", Label: "Time on call", Value: stats.ShiftDuration()},
+			{ID: "opsgenie.oncall_shifts", Label: "On-call shifts", Value: len(shifts)},
+			{ID: "opsgenie.alerts_handled", Label: "Alerts handled", Value: len(alerts)},
+			{ID: "opsgenie.alerts_acknowledged", Label: "Alerts acknowledged", Value: acknowledged},
+			{ID: "opsgenie.alerts_closed", Label: "Alerts closed", Value: closed},
+			{ID: "opsgenie.alerts_off_hours", Label: "Alerts handled off-hours", Value: stats.OffHoursAlerts()},
+			{ID: "opsgenie.mean_time_to_ack", Label: "Mean time to acknowledge", Value: stats.MeanTimeToAcknowledge(), Snapshot: true},
+		},
+		Details: map[string]interface{}{
+			common.OnCallDetailsKey: stats,
+		},
+		Activities: o.buildActivities(stats),
+		CSVTables:  o.csvTables(stats),
+	}
+	result.Explain("opsgenie.alerts_handled", result.Activities)
+
+	o.printResults(writer, result, stats)
+	return result, nil
+}
+
+// getUser looks up OPSGENIE_USER
+func (o *OpsgenieAnalyzer) getUser() (*userResponse, error) {
+	body, err := o.client.Get(fmt.Sprintf("%s/v2/users/%s", o.apiURL, url.PathEscape(o.user)), nil)
+	if err != nil {
+		return nil, err
+	}
+	var user userResponse
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, common.WrapError(err, "failed to parse Opsgenie user")
+	}
+	return &user, nil
+}
+
+// getShifts reads the final timeline (with overrides applied) of each schedule and keeps the user's periods,
+// merging periods that continue one another and clipping them to the analysis period
+func (o *OpsgenieAnalyzer) getShifts(writer io.Writer, userID string, startDate, endDate time.Time) ([]common.OnCallShift, error) {
+	body, err := o.client.Get(o.apiURL+"/v2/schedules", nil)
+	if err != nil {
+		return nil, err
+	}
+	var schedules schedulesResponse
+	if err := json.Unmarshal(body, &schedules); err != nil {
+		return nil, common.WrapError(err, "failed to parse Opsgenie schedules")
+	}
+
+	days := int(endDate.Sub(startDate).Hours()/24) + 1
+	var shifts []common.OnCallShift
+	for _, schedule := range schedules.Data {
+		if !o.includesSchedule(schedule.Name) {
+			continue
+		}
+		params := url.Values{}
+		params.Set("identifierType", "id")
+		params.Set("interval", strconv.Itoa(days))
+		params.Set("intervalUnit", "days")
+		params.Set("date", startDate.Format(time.RFC3339))
+		body, err := o.client.Get(fmt.Sprintf("%s/v2/schedules/%s/timeline?%s", o.apiURL, url.PathEscape(schedule.ID), params.Encode()), nil)
+		if err != nil {
+			return nil, common.WrapError(err, "failed to get timeline of schedule %s", schedule.Name)
+		}
+		var timeline timelineResponse
+		if err := json.Unmarshal(body, &timeline); err != nil {
+			return nil, common.WrapError(err, "failed to parse timeline of schedule %s", schedule.Name)
+		}
+
+		var periods []common.OnCallShift
+		for _, rotation := range timeline.Data.FinalTimeline.Rotations {
+			for _, period := range rotation.Periods {
+				if period.Recipient.Type != "user" || (period.Recipient.ID != userID && !strings.EqualFold(period.Recipient.Name, o.user)) {
+					continue
+				}
+				if shift, ok := common.ClipShift(common.OnCallShift{Schedule: schedule.Name, Start: period.StartDate, End: period.EndDate}, startDate, endDate); ok {
+					periods = append(periods, shift)
+				}
+			}
+		}
+		shifts = append(shifts, mergeShifts(periods)...)
+	}
+
+	sort.SliceStable(shifts, func(i, j int) bool {
+		return shifts[i].Start.Before(shifts[j].Start)
+	})
+	fmt.Fprintf(writer, "Found %d on-call shifts\n", len(shifts))
+	return shifts, nil
+}
+
+// includesSchedule reports whether a schedule is selected by OPSGENIE_SCHEDULES
+func (o *OpsgenieAnalyzer) includesSchedule(name string) bool {
+	if len(o.schedules) == 0 {
+		return true
+	}
+	for _, schedule := range o.schedules {
+		if strings.EqualFold(schedule, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeShifts joins periods of one schedule that overlap or continue one another
+func mergeShifts(periods []common.OnCallShift) []common.OnCallShift {
+	sort.SliceStable(periods, func(i, j int) bool {
+		return periods[i].Start.Before(periods[j].Start)
+	})
+	var merged []common.OnCallShift
+	for _, period := range periods {
+		if last := len(merged) - 1; last >= 0 && !period.Start.After(merged[last].End) {
+			if period.End.After(merged[last].End) {
+				merged[last].End = period.End
+			}
+			continue
+		}
+		merged = append(merged, period)
+	}
+	return merged
+}
+
+// getAlerts lists alerts created in the period and keeps those the user acknowledged or closed
+func (o *OpsgenieAnalyzer) getAlerts(startDate, endDate time.Time) ([]common.OnCallAlert, error) {
+	query := fmt.Sprintf("createdAt >= %d AND createdAt < %d", startDate.UnixMilli(), endDate.AddDate(0, 0, 1).UnixMilli())
+	var alerts []common.OnCallAlert
+	for offset := 0; ; offset += alertPageSize {
+		params := url.Values{}
+		params.Set("query", query)
+		params.Set("limit", strconv.Itoa(alertPageSize))
+		params.Set("offset", strconv.Itoa(offset))
+		params.Set("sort", "createdAt")
+		params.Set("order", "asc")
+
+		body, err := o.client.Get(fmt.Sprintf("%s/v2/alerts?%s", o.apiURL, params.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		var response alertsResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Opsgenie alerts")
+		}
+
+		for _, item := range response.Data {
+			acknowledged := strings.EqualFold(item.Report.AcknowledgedBy, o.user)
+			closed := strings.EqualFold(item.Report.ClosedBy, o.user)
+			if !acknowledged && !closed {
+				continue
+			}
+			alert := common.OnCallAlert{
+				ID:        item.ID,
+				Title:     fmt.Sprintf("#%s %s", item.TinyID, item.Message),
+				Priority:  item.Priority,
+				CreatedAt: item.CreatedAt,
+				Closed:    closed,
+			}
+			if acknowledged {
+				alert.AcknowledgedAt = item.CreatedAt.Add(time.Duration(item.Report.AckTime) * time.Millisecond)
+			}
+			alerts = append(alerts, alert)
+		}
+
+		if len(response.Data) < alertPageSize {
+			break
+		}
+	}
+	return alerts, nil
+}
+
+// filterIgnored drops alerts listed in the ignore file by ID
+func (o *OpsgenieAnalyzer) filterIgnored(writer io.Writer, alerts []common.OnCallAlert) []common.OnCallAlert {
+	return config.FilterIgnored(writer, &o.ignoreList, alerts, "alerts", func(alert common.OnCallAlert) []string {
+		return []string{alert.ID}
+	})
+}
+
+// buildActivities converts shifts and alerts into dated activities.
+// Shifts carry no duration: being on call is availability, not time spent on work items.
+func (o *OpsgenieAnalyzer) buildActivities(stats *common.OnCallStats) []common.Activity {
+	var activities []common.Activity
+	for _, shift := range stats.Shifts {
+		activities = append(activities, common.Activity{
+			Source: o.GetName(),
+			Kind:   "oncall_shift",
+			ID:     fmt.Sprintf("%s@%s", shift.Schedule, shift.Start.Format(time.RFC3339)),
+			Title:  fmt.Sprintf("On call: %s (%s)", shift.Schedule, common.FormatDuration(shift.Duration())),
+			Time:   shift.Start,
+		})
+	}
+	for _, alert := range stats.Alerts {
+		activities = append(activities, common.Activity{
+			Source: o.GetName(),
+			Kind:   "alert",
+			ID:     alert.ID,
+			Title:  alert.Title,
+			Time:   alert.CreatedAt,
+		})
+	}
+	return activities
+}
+
+func (o *OpsgenieAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, stats *common.OnCallStats) {
+	fmt.Fprintf(writer, "\nOn-call shifts from %s to %s (%d):\n",
+		result.StartDate.Format("2006-01-02"), result.EndDate.Format("2006-01-02"), len(stats.Shifts))
+	for _, shift := range stats.Shifts {
+		fmt.Fprintf(writer, "- %s: %s to %s (%s)\n", shift.Schedule, shift.Start.Local().Format("2006-01-02 15:04"),
+			shift.End.Local().Format("2006-01-02 15:04"), common.FormatDuration(shift.Duration()))
+	}
+
+	fmt.Fprintf(writer, "\nAlerts handled (%d):\n", len(stats.Alerts))
+	for _, alert := range stats.Alerts {
+		var actions []string
+		if !alert.AcknowledgedAt.IsZero() {
+			actions = append(actions, "acknowledged in "+common.FormatDuration(alert.AcknowledgedAt.Sub(alert.CreatedAt)))
+		}
+		if alert.Closed {
+			actions = append(actions, "closed")
+		}
+		fmt.Fprintf(writer, "- %s [%s] %s (%s)\n", alert.CreatedAt.Local().Format("2006-01-02 15:04"), alert.Priority,
+			alert.Title, strings.Join(actions, ", "))
+	}
+
+	result.PrintSummary(writer)
+}
@@ -0,0 +1,59 @@
+package opsgenie
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// shiftCSVRow is one on-call shift in opsgenie-shifts.csv
+type shiftCSVRow struct {
+	Schedule string        `csv:"schedule"`
+	Start    time.Time     `csv:"start"`
+	End      time.Time     `csv:"end"`
+	Duration time.Duration `csv:"hours"`
+}
+
+// alertCSVRow is one handled alert in opsgenie-alerts.csv
+type alertCSVRow struct {
+	ID             string        `csv:"id"`
+	Title          string        `csv:"title"`
+	Priority       string        `csv:"priority"`
+	CreatedAt      time.Time     `csv:"created_at"`
+	AcknowledgedAt time.Time     `csv:"acknowledged_at"`
+	TimeToAck      time.Duration `csv:"time_to_ack_hours"`
+	Closed         bool          `csv:"closed"`
+}
+
+// csvTables lists the on-call shifts and the alerts the user handled
+func (o *OpsgenieAnalyzer) csvTables(stats *common.OnCallStats) []common.CSVTable {
+	var shiftRows []shiftCSVRow
+	for _, shift := range stats.Shifts {
+		shiftRows = append(shiftRows, shiftCSVRow{
+			Schedule: shift.Schedule,
+			Start:    shift.Start,
+			End:      shift.End,
+			Duration: shift.Duration(),
+		})
+	}
+
+	var alertRows []alertCSVRow
+	for _, alert := range stats.Alerts {
+		row := alertCSVRow{
+			ID:             alert.ID,
+			Title:          alert.Title,
+			Priority:       alert.Priority,
+			CreatedAt:      alert.CreatedAt,
+			AcknowledgedAt: alert.AcknowledgedAt,
+			Closed:         alert.Closed,
+		}
+		if !alert.AcknowledgedAt.IsZero() {
+			row.TimeToAck = alert.AcknowledgedAt.Sub(alert.CreatedAt)
+		}
+		alertRows = append(alertRows, row)
+	}
+	return []common.CSVTable{
+		common.NewCSVTable("shifts", shiftRows),
+		common.NewCSVTable("alerts", alertRows),
+	}
+}
@@ -0,0 +1,457 @@
+package phabricator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+// pageSize is the page size of Conduit searches (the server caps it at 100)
+const pageSize = 100
+
+// reviewedStatuses are reviewer states that mean the user acted on a revision, not just that they were added
+var reviewedStatuses = map[string]bool{
+	"accepted":       true,
+	"accepted-older": true,
+	"rejected":       true,
+	"rejected-older": true,
+}
+
+// PhabricatorAnalyzer implements the Analyzer interface for Phabricator Differential,
+// read from a (possibly archived) instance's Conduit API or from an exported JSON file
+type PhabricatorAnalyzer struct {
+	baseURL    string // e.g. https://phabricator.example.com
+	token      string
+	username   string // PHABRICATOR_USERNAME; the token owner when empty
+	userPHID   string // PHABRICATOR_USER_PHID; required with an export file
+	exportFile string
+	client     *common.HTTPClient
+	ignoreList config.IgnoreList
+}
+
+// Revision is a Differential revision
+type Revision struct {
+	ID          int        `json:"id"`
+	PHID        string     `json:"phid"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Status      string     `json:"status"` // e.g. published, abandoned, needs-review, accepted
+	AuthorPHID  string     `json:"author_phid"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ModifiedAt  time.Time  `json:"modified_at"`
+	Reviewers   []Reviewer `json:"reviewers,omitempty"`
+	ReviewState string     `json:"review_state,omitempty"` // the user's reviewer state on revisions of others
+}
+
+// Reviewer is a reviewer of a revision and their state (added, accepted, rejected, blocking, resigned, ...)
+type Reviewer struct {
+	PHID   string `json:"phid"`
+	Status string `json:"status"`
+}
+
+// Name returns the monogram and title, e.g. "D123 Fix login"
+func (r Revision) Name() string {
+	return fmt.Sprintf("D%d %s", r.ID, r.Title)
+}
+
+// revisionData is an item of differential.revision.search (also the format of PHABRICATOR_EXPORT_FILE)
+type revisionData struct {
+	ID     int    `json:"id"`
+	PHID   string `json:"phid"`
+	Fields struct {
+		Title      string `json:"title"`
+		URI        string `json:"uri"`
+		AuthorPHID string `json:"authorPHID"`
+		Status     struct {
+			Value string `json:"value"`
+		} `json:"status"`
+		DateCreated  int64 `json:"dateCreated"`
+		DateModified int64 `json:"dateModified"`
+	} `json:"fields"`
+	Attachments struct {
+		Reviewers struct {
+			Reviewers []struct {
+				ReviewerPHID string `json:"reviewerPHID"`
+				Status       string `json:"status"`
+			} `json:"reviewers"`
+		} `json:"reviewers"`
+	} `json:"attachments"`
+}
+
+// conduitResponse is the envelope of every Conduit method
+type conduitResponse struct {
+	Result    json.RawMessage `json:"result"`
+	ErrorCode *string         `json:"error_code"`
+	ErrorInfo *string         `json:"error_info"`
+}
+
+// searchResult is the result of a *.search method
+type searchResult struct {
+	Data   []revisionData `json:"data"`
+	Cursor struct {
+		After *string `json:"after"`
+	} `json:"cursor"`
+}
+
+// conduitUser is the result of user.whoami and an item of user.search
+type conduitUser struct {
+	PHID     string `json:"phid"`
+	UserName string `json:"userName"`
+	RealName string `json:"realName"`
+	Fields   struct {
+		Username string `json:"username"`
+		RealName string `json:"realName"`
+	} `json:"fields"`
+}
+
+// NewPhabricatorAnalyzer creates a new Phabricator analyzer
+func NewPhabricatorAnalyzer() *PhabricatorAnalyzer {
+	return &PhabricatorAnalyzer{
+		baseURL:    strings.TrimRight(os.Getenv("PHABRICATOR_URL"), "/"),
+		token:      os.Getenv("PHABRICATOR_API_TOKEN"),
+		username:   os.Getenv("PHABRICATOR_USERNAME"),
+		userPHID:   os.Getenv("PHABRICATOR_USER_PHID"),
+		exportFile: os.Getenv("PHABRICATOR_EXPORT_FILE"),
+		client:     common.NewHTTPClient(),
+	}
+}
+
+// GetName returns the analyzer name
+func (p *PhabricatorAnalyzer) GetName() string {
+	return "Phabricator"
+}
+
+// ValidateConfig validates the required configuration
+func (p *PhabricatorAnalyzer) ValidateConfig(writer io.Writer) error {
+	if p.exportFile != "" {
+		if p.userPHID == "" {
+			return common.NewError("PHABRICATOR_USER_PHID environment variable is required with PHABRICATOR_EXPORT_FILE")
+		}
+		if _, err := os.Stat(p.exportFile); err != nil {
+			return common.WrapError(err, "PHABRICATOR_EXPORT_FILE is not readable")
+		}
+		fmt.Fprintf(writer, "✓ Phabricator export file: %s\n", p.exportFile)
+		return nil
+	}
+	if p.baseURL == "" || p.token == "" {
+		return common.NewError("PHABRICATOR_EXPORT_FILE, or PHABRICATOR_URL and PHABRICATOR_API_TOKEN environment variables are required")
+	}
+	if _, err := p.whoAmI(); err != nil {
+		return common.WrapError(err, "failed to access the Conduit API (check PHABRICATOR_URL and PHABRICATOR_API_TOKEN)")
+	}
+	fmt.Fprintln(writer, "✓ Phabricator API token is valid")
+	return nil
+}
+
+// WhoAmI reports the owner of PHABRICATOR_API_TOKEN
+func (p *PhabricatorAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if p.baseURL == "" || p.token == "" {
+		return nil, common.NewError("PHABRICATOR_URL and PHABRICATOR_API_TOKEN environment variables are required")
+	}
+	user, err := p.whoAmI()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to access the Conduit API (check PHABRICATOR_URL and PHABRICATOR_API_TOKEN)")
+	}
+	identity := &common.Identity{Source: p.GetName(), ID: user.PHID, Login: user.UserName, Name: user.RealName}
+	if p.username != "" && !strings.EqualFold(p.username, user.UserName) {
+		identity.Note = fmt.Sprintf("PHABRICATOR_USERNAME is '%s'", p.username)
+	}
+	return identity, nil
+}
+
+// call invokes a Conduit method with form-encoded parameters
+func (p *PhabricatorAnalyzer) call(method string, params url.Values, result interface{}) error {
+	form := url.Values{}
+	for key, values := range params {
+		form[key] = values
+	}
+	form.Set("api.token", p.token)
+	body, err := p.client.Post(p.baseURL+"/api/"+method, form.Encode(),
+		map[string]string{"Content-Type": "application/x-www-form-urlencoded"})
+	if err != nil {
+		return err
+	}
+	var response conduitResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return common.WrapError(err, "failed to parse %s response", method)
+	}
+	if response.ErrorCode != nil {
+		info := ""
+		if response.ErrorInfo != nil {
+			info = *response.ErrorInfo
+		}
+		return common.NewError("%s failed: %s %s", method, *response.ErrorCode, info)
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return common.WrapError(err, "failed to parse %s result", method)
+	}
+	return nil
+}
+
+func (p *PhabricatorAnalyzer) whoAmI() (*conduitUser, error) {
+	var user conduitUser
+	if err := p.call("user.whoami", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// resolveUserPHID returns the PHID of PHABRICATOR_USER_PHID, PHABRICATOR_USERNAME, or the token owner
+func (p *PhabricatorAnalyzer) resolveUserPHID() (string, error) {
+	if p.userPHID != "" {
+		return p.userPHID, nil
+	}
+	if p.username == "" {
+		user, err := p.whoAmI()
+		if err != nil {
+			return "", err
+		}
+		return user.PHID, nil
+	}
+	var result struct {
+		Data []conduitUser `json:"data"`
+	}
+	if err := p.call("user.search", url.Values{"constraints[usernames][0]": {p.username}}, &result); err != nil {
+		return "", err
+	}
+	if len(result.Data) == 0 {
+		return "", common.NewError("Phabricator user '%s' not found", p.username)
+	}
+	return result.Data[0].PHID, nil
+}
+
+// Analyze reports revisions the user authored and revisions of others they accepted or requested changes on
+func (p *PhabricatorAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := p.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := p.ignoreList.Reload(); err != nil {
+		return nil, err
+	}
+
+	var authored, reviewed []Revision
+	if p.exportFile != "" {
+		fmt.Fprintf(writer, "Reading Phabricator revisions from %s...\n", p.exportFile)
+		revisions, err := p.readExport(p.exportFile)
+		if err != nil {
+			return nil, err
+		}
+		authored, reviewed = splitRevisions(revisions, p.userPHID, config)
+	} else {
+		userPHID, err := p.resolveUserPHID()
+		if err != nil {
+			return nil, common.WrapError(err, "failed to get the Phabricator user")
+		}
+		fmt.Fprintf(writer, "Fetching Phabricator revisions of %s from %s to %s...\n", userPHID,
+			config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+		created, err := p.searchRevisions(url.Values{
+			"constraints[authorPHIDs][0]": {userPHID},
+			"constraints[createdStart]":   {strconv.FormatInt(config.StartDate.Unix(), 10)},
+			"constraints[createdEnd]":     {strconv.FormatInt(config.EndDate.AddDate(0, 0, 1).Unix()-1, 10)},
+		})
+		if err != nil {
+			return nil, common.WrapError(err, "failed to search authored revisions")
+		}
+		// Conduit has no review date; revisions modified in the period stand in for reviews in the period
+		modified, err := p.searchRevisions(url.Values{
+			"constraints[reviewerPHIDs][0]": {userPHID},
+			"constraints[modifiedStart]":    {strconv.FormatInt(config.StartDate.Unix(), 10)},
+			"constraints[modifiedEnd]":      {strconv.FormatInt(config.EndDate.AddDate(0, 0, 1).Unix()-1, 10)},
+		})
+		if err != nil {
+			return nil, common.WrapError(err, "failed to search reviewed revisions")
+		}
+		authored, reviewed = splitRevisions(append(created, modified...), userPHID, config)
+	}
+	authored = p.filterIgnored(writer, authored)
+	reviewed = p.filterIgnored(writer, reviewed)
+
+	var landed, abandoned, accepted, rejected []Revision
+	for _, revision := range authored {
+		switch revision.Status {
+		case "published":
+			landed = append(landed, revision)
+		case "abandoned":
+			abandoned = append(abandoned, revision)
+		}
+	}
+	for _, revision := range reviewed {
+		if strings.HasPrefix(revision.ReviewState, "accepted") {
+			accepted = append(accepted, revision)
+		} else {
+			rejected = append(rejected, revision)
+		}
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: p.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "phabricator.revisions_authored", Label: "Revisions authored", Value: len(authored)},
+			{ID: "phabricator.revisions_landed", Label: "Authored revisions landed", Value: len(landed)},
+			{ID: "phabricator.revisions_abandoned", Label: "Authored revisions abandoned", Value: len(abandoned)},
+			{ID: "phabricator.revisions_reviewed", Label: "Revisions reviewed", Value: len(reviewed)},
+			{ID: "phabricator.revisions_accepted", Label: "Revisions accepted", Value: len(accepted)},
+			{ID: "phabricator.changes_requested", Label: "Changes requested", Value: len(rejected)},
+		},
+		Details: map[string]interface{}{
+			"authored_revisions": authored,
+			"reviewed_revisions": reviewed,
+		},
+		Activities: append(p.revisionActivities(authored, "revision_authored"), p.revisionActivities(reviewed, "revision_reviewed")...),
+		CSVTables:  csvTables(authored, reviewed),
+	}
+	result.Explain("phabricator.revisions_authored", p.revisionActivities(authored, "revision_authored"))
+	result.Explain("phabricator.revisions_landed", p.revisionActivities(landed, "revision_authored"))
+	result.Explain("phabricator.revisions_abandoned", p.revisionActivities(abandoned, "revision_authored"))
+	result.Explain("phabricator.revisions_reviewed", p.revisionActivities(reviewed, "revision_reviewed"))
+	result.Explain("phabricator.revisions_accepted", p.revisionActivities(accepted, "revision_reviewed"))
+	result.Explain("phabricator.changes_requested", p.revisionActivities(rejected, "revision_reviewed"))
+
+	p.printResults(writer, result, authored, reviewed)
+	return result, nil
+}
+
+// searchRevisions runs differential.revision.search with the reviewers attachment, following the cursor
+func (p *PhabricatorAnalyzer) searchRevisions(constraints url.Values) ([]Revision, error) {
+	var revisions []Revision
+	after := ""
+	for {
+		params := url.Values{}
+		for key, values := range constraints {
+			params[key] = values
+		}
+		params.Set("attachments[reviewers]", "1")
+		params.Set("order", "oldest")
+		params.Set("limit", strconv.Itoa(pageSize))
+		if after != "" {
+			params.Set("after", after)
+		}
+
+		var result searchResult
+		if err := p.call("differential.revision.search", params, &result); err != nil {
+			return nil, err
+		}
+		for _, data := range result.Data {
+			revisions = append(revisions, p.toRevision(data))
+		}
+		if result.Cursor.After == nil || *result.Cursor.After == "" {
+			break
+		}
+		after = *result.Cursor.After
+	}
+	return revisions, nil
+}
+
+// toRevision converts a search item; revisions without a uri field (older servers, exports) link to /D<id>
+func (p *PhabricatorAnalyzer) toRevision(data revisionData) Revision {
+	revision := Revision{
+		ID:         data.ID,
+		PHID:       data.PHID,
+		Title:      data.Fields.Title,
+		URL:        data.Fields.URI,
+		Status:     data.Fields.Status.Value,
+		AuthorPHID: data.Fields.AuthorPHID,
+		CreatedAt:  time.Unix(data.Fields.DateCreated, 0),
+		ModifiedAt: time.Unix(data.Fields.DateModified, 0),
+	}
+	if revision.URL == "" && p.baseURL != "" {
+		revision.URL = fmt.Sprintf("%s/D%d", p.baseURL, data.ID)
+	}
+	for _, reviewer := range data.Attachments.Reviewers.Reviewers {
+		revision.Reviewers = append(revision.Reviewers, Reviewer{PHID: reviewer.ReviewerPHID, Status: reviewer.Status})
+	}
+	return revision
+}
+
+// splitRevisions returns revisions the user authored in the period and revisions of others modified in the period
+// that the user accepted or rejected. Each revision is listed once.
+func splitRevisions(revisions []Revision, userPHID string, config *common.Config) (authored, reviewed []Revision) {
+	seen := make(map[string]bool)
+	for _, revision := range revisions {
+		key := strconv.Itoa(revision.ID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if revision.AuthorPHID == userPHID {
+			if inPeriod(revision.CreatedAt, config) {
+				authored = append(authored, revision)
+			}
+			continue
+		}
+		if !inPeriod(revision.ModifiedAt, config) {
+			continue
+		}
+		for _, reviewer := range revision.Reviewers {
+			if reviewer.PHID == userPHID && reviewedStatuses[reviewer.Status] {
+				revision.ReviewState = reviewer.Status
+				reviewed = append(reviewed, revision)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(authored, func(i, j int) bool { return authored[i].CreatedAt.Before(authored[j].CreatedAt) })
+	sort.SliceStable(reviewed, func(i, j int) bool { return reviewed[i].ModifiedAt.Before(reviewed[j].ModifiedAt) })
+	return authored, reviewed
+}
+
+// inPeriod reports whether t falls within the analysis period (end date inclusive)
+func inPeriod(t time.Time, config *common.Config) bool {
+	return !t.Before(config.StartDate) && t.Before(config.EndDate.AddDate(0, 0, 1))
+}
+
+// filterIgnored drops revisions whose URL or monogram (D123) is listed in the ignore file
+func (p *PhabricatorAnalyzer) filterIgnored(writer io.Writer, revisions []Revision) []Revision {
+	return config.FilterIgnored(writer, &p.ignoreList, revisions, "revisions", func(revision Revision) []string {
+		return []string{revision.URL, fmt.Sprintf("D%d", revision.ID)}
+	})
+}
+
+// revisionActivities converts revisions into activities dated by creation, or by the last change for reviews
+func (p *PhabricatorAnalyzer) revisionActivities(revisions []Revision, kind string) []common.Activity {
+	var activities []common.Activity
+	for _, revision := range revisions {
+		at := revision.CreatedAt
+		if kind == "revision_reviewed" {
+			at = revision.ModifiedAt
+		}
+		activities = append(activities, common.Activity{
+			Source: p.GetName(),
+			Kind:   kind,
+			ID:     revision.PHID,
+			Title:  revision.Name(),
+			URL:    revision.URL,
+			Time:   at,
+		})
+	}
+	return activities
+}
+
+func (p *PhabricatorAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, authored, reviewed []Revision) {
+	fmt.Fprintf(writer, "\nRevisions authored (%d):\n", len(authored))
+	for _, revision := range authored {
+		fmt.Fprintf(writer, "- %s: %s [%s]\n", revision.CreatedAt.Local().Format("2006-01-02"), revision.Name(), revision.Status)
+		fmt.Fprintf(writer, "  URL: %s\n", revision.URL)
+	}
+
+	fmt.Fprintf(writer, "\nRevisions reviewed (%d, dated by last change):\n", len(reviewed))
+	for _, revision := range reviewed {
+		fmt.Fprintf(writer, "- %s: %s [%s]\n", revision.ModifiedAt.Local().Format("2006-01-02"), revision.Name(), revision.ReviewState)
+		fmt.Fprintf(writer, "  URL: %s\n", revision.URL)
+	}
+
+	result.PrintSummary(writer)
+}
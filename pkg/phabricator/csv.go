@@ -0,0 +1,42 @@
+package phabricator
+
+import (
+	"fmt"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// revisionCSVRow is one revision in phabricator-revisions.csv
+type revisionCSVRow struct {
+	Relation   string    `csv:"relation"` // authored or reviewed
+	Revision   string    `csv:"revision"`
+	Title      string    `csv:"title"`
+	Status     string    `csv:"status"`
+	Review     string    `csv:"review"` // the user's reviewer state on reviewed revisions
+	CreatedAt  time.Time `csv:"created_at"`
+	ModifiedAt time.Time `csv:"modified_at"`
+	URL        string    `csv:"url"`
+}
+
+// csvTables lists the authored revisions, then the reviewed ones
+func csvTables(authored, reviewed []Revision) []common.CSVTable {
+	var rows []revisionCSVRow
+	add := func(revisions []Revision, relation string) {
+		for _, revision := range revisions {
+			rows = append(rows, revisionCSVRow{
+				Relation:   relation,
+				Revision:   fmt.Sprintf("D%d", revision.ID),
+				Title:      revision.Title,
+				Status:     revision.Status,
+				Review:     revision.ReviewState,
+				CreatedAt:  revision.CreatedAt,
+				ModifiedAt: revision.ModifiedAt,
+				URL:        revision.URL,
+			})
+		}
+	}
+	add(authored, "authored")
+	add(reviewed, "reviewed")
+	return []common.CSVTable{common.NewCSVTable("revisions", rows)}
+}
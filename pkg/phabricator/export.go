@@ -0,0 +1,56 @@
+package phabricator
+
+import (
+	"encoding/json"
+	"os"
+
+	"dev-stats/pkg/common"
+)
+
+// readExport reads revisions saved from differential.revision.search (with attachments[reviewers]=1).
+// The file may hold the data array, the search result ({"data": [...]}), or whole Conduit responses,
+// e.g. one page per line as written by a script paging through an instance before it was shut down.
+func (p *PhabricatorAnalyzer) readExport(path string) ([]Revision, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to open %s", path)
+	}
+	defer file.Close()
+
+	var revisions []Revision
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, common.WrapError(err, "failed to parse %s", path)
+		}
+		data, err := exportData(value)
+		if err != nil {
+			return nil, common.WrapError(err, "failed to parse %s", path)
+		}
+		for _, item := range data {
+			revisions = append(revisions, p.toRevision(item))
+		}
+	}
+	return revisions, nil
+}
+
+// exportData extracts the revisions of one JSON value in an export file
+func exportData(value json.RawMessage) ([]revisionData, error) {
+	var data []revisionData
+	if err := json.Unmarshal(value, &data); err == nil {
+		return data, nil
+	}
+
+	var envelope struct {
+		Data   []revisionData `json:"data"`
+		Result *searchResult  `json:"result"`
+	}
+	if err := json.Unmarshal(value, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Result != nil {
+		return envelope.Result.Data, nil
+	}
+	return envelope.Data, nil
+}
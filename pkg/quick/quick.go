@@ -0,0 +1,141 @@
+package quick
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/api"
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+	"dev-stats/pkg/ingest"
+)
+
+// Summary is the current week so far, read from stored data only
+type Summary struct {
+	Week         string   `json:"week"`  // YYYY-Www
+	Start        string   `json:"start"` // first day of the week
+	PRs          int      `json:"prs"`
+	Reviews      int      `json:"reviews"`
+	MeetingHours float64  `json:"meeting_hours"`
+	Sources      []string `json:"sources"` // stored data that covered the week: github, calendar, webhooks
+}
+
+// Sources is the stored data a summary is read from
+type Sources struct {
+	Results    *api.Results // results saved with -output json
+	Webhooks   *ingest.WebhookAnalyzer
+	Categories *config.CategorizationConfig
+}
+
+// Collect summarizes the week of now up to now. PRs are the ones I opened, from saved GitHub results and webhook
+// events (counted once by URL); reviews are the higher of the webhook count and the reviews_given of saved GitHub
+// results within the week, since saved results don't date reviews; meeting hours are the saved calendar events of a
+// meeting category that started by now.
+func Collect(sources Sources, now time.Time, weeks common.WeekConfig) (*Summary, error) {
+	start := weeks.WeekStart(now)
+	first, today := start.Format("2006-01-02"), now.Format("2006-01-02")
+	summary := &Summary{Week: weeks.WeekLabel(now), Start: first}
+	inWeek := func(t time.Time) bool { return !t.Before(start) && !t.After(now) }
+
+	prs := make(map[string]bool)
+	meetings := make(map[string]time.Duration)
+	used := make(map[string]bool)
+	savedReviews, webhookReviews := 0, 0
+
+	periods, err := sources.Results.Periods()
+	if err != nil {
+		return nil, err
+	}
+	for _, period := range periods {
+		if period.Scope != "" || period.StartDate > today || period.EndDate < first {
+			continue
+		}
+		for _, analyzer := range period.Analyzers {
+			if analyzer != "github" && analyzer != "calendar" {
+				continue
+			}
+			result, err := sources.Results.Load(period, analyzer)
+			if err != nil {
+				return nil, err
+			}
+			used[analyzer] = true
+			for _, metric := range result.Metrics {
+				if metric.ID == "github.reviews_given" && period.StartDate >= first {
+					if value, ok := metric.Value.(float64); ok && int(value) > savedReviews {
+						savedReviews = int(value)
+					}
+				}
+			}
+			for _, activity := range result.Activities {
+				if !inWeek(activity.Time) {
+					continue
+				}
+				switch {
+				case analyzer == "github" && activity.Kind == "pr_authored":
+					prs[activity.ID] = true
+				case analyzer == "calendar" && activity.Kind == common.ActivityKindEvent && activity.Duration > 0 &&
+					sources.Categories.MainCategory(activity.Category) == "meeting":
+					meetings[activity.ID] = activity.Duration
+				}
+			}
+		}
+	}
+
+	if sources.Webhooks != nil {
+		events, err := sources.Webhooks.MyEvents(start, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if !inWeek(event.Time) {
+				continue
+			}
+			used["webhooks"] = true
+			switch event.Kind {
+			case ingest.KindPROpened:
+				if event.URL != "" {
+					prs[event.URL] = true
+				} else {
+					prs[event.ID] = true
+				}
+			case ingest.KindReview:
+				webhookReviews++
+			}
+		}
+	}
+
+	var meetingTime time.Duration
+	for _, duration := range meetings {
+		meetingTime += duration
+	}
+	summary.PRs = len(prs)
+	summary.Reviews = max(savedReviews, webhookReviews)
+	summary.MeetingHours = meetingTime.Hours()
+	for _, source := range []string{"github", "calendar", "webhooks"} {
+		if used[source] {
+			summary.Sources = append(summary.Sources, source)
+		}
+	}
+	return summary, nil
+}
+
+// Line formats the summary as one line for shell prompts and launchers (e.g. "2025-W03: 3 PRs, 5 reviews, 6.5h meetings")
+func (s *Summary) Line() string {
+	if len(s.Sources) == 0 {
+		return fmt.Sprintf("%s: no stored data yet", s.Week)
+	}
+	parts := []string{
+		fmt.Sprintf("%d %s", s.PRs, plural(s.PRs, "PR", "PRs")),
+		fmt.Sprintf("%d %s", s.Reviews, plural(s.Reviews, "review", "reviews")),
+		fmt.Sprintf("%.1fh meetings", s.MeetingHours),
+	}
+	return fmt.Sprintf("%s: %s", s.Week, strings.Join(parts, ", "))
+}
+
+func plural(count int, one, many string) string {
+	if count == 1 {
+		return one
+	}
+	return many
+}
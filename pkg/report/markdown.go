@@ -0,0 +1,222 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// ReportFileName is the Markdown report written next to the per-analyzer stats files
+const ReportFileName = "report.md"
+
+// SaveMarkdown writes the report for results to path
+func SaveMarkdown(path string, results []*common.AnalysisResult, startDate, endDate time.Time) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return common.WrapError(err, "failed to create %s", path)
+	}
+	defer file.Close()
+
+	WriteMarkdown(file, results, startDate, endDate)
+	return nil
+}
+
+// WriteMarkdown renders results as a single Markdown document: a summary table, then one section per analyzer
+// with its metrics and activities. Notion pages are also listed in the notion-urls format so that the list can be
+// copied into notion-urls/<period>.md and downloaded with -download.
+func WriteMarkdown(writer io.Writer, results []*common.AnalysisResult, startDate, endDate time.Time) {
+	fmt.Fprintf(writer, "# Development Stats (%s to %s)\n\n", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+
+	fmt.Fprintln(writer, "## Summary")
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "| Source | Activities | Scheduled |")
+	fmt.Fprintln(writer, "| --- | ---: | ---: |")
+	for _, result := range results {
+		var scheduled time.Duration
+		for _, activity := range result.Activities {
+			scheduled += activity.Duration
+		}
+		fmt.Fprintf(writer, "| [%s](#%s) | %d | %s |\n",
+			escapeCell(result.AnalyzerName), anchor(result.AnalyzerName), len(result.Activities), formatOptionalDuration(scheduled))
+	}
+
+	for _, result := range results {
+		writeResult(writer, result)
+	}
+}
+
+func writeResult(writer io.Writer, result *common.AnalysisResult) {
+	fmt.Fprintf(writer, "\n## %s\n", result.AnalyzerName)
+
+	if len(result.Metrics) > 0 {
+		fmt.Fprintln(writer, "\n### Metrics")
+		fmt.Fprintln(writer)
+		fmt.Fprintln(writer, "| Metric | Value |")
+		fmt.Fprintln(writer, "| --- | ---: |")
+		for _, metric := range result.Metrics {
+			fmt.Fprintf(writer, "| %s | %s |\n", escapeCell(metric.Label), escapeCell(formatValue(metric.Value)))
+		}
+	}
+
+	// Activities grouped by kind (pr_authored, event, page_updated, ...) in chronological order
+	byKind := make(map[string][]common.Activity)
+	var kinds []string
+	for _, activity := range result.Activities {
+		if _, exists := byKind[activity.Kind]; !exists {
+			kinds = append(kinds, activity.Kind)
+		}
+		byKind[activity.Kind] = append(byKind[activity.Kind], activity)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		activities := byKind[kind]
+		sort.SliceStable(activities, func(i, j int) bool { return activities[i].Time.Before(activities[j].Time) })
+		writeActivityTable(writer, kind, activities)
+	}
+
+	if notionPages := notionPageList(result.Activities); len(notionPages) > 0 {
+		fmt.Fprintln(writer, "\n### Notion Pages by Category")
+		fmt.Fprint(writer, notionPages)
+	}
+}
+
+// writeActivityTable prints a table of activities, with Project/Category/Duration columns only when any activity has them
+func writeActivityTable(writer io.Writer, kind string, activities []common.Activity) {
+	hasProject, hasCategory, hasDuration := false, false, false
+	for _, activity := range activities {
+		hasProject = hasProject || activity.Project != ""
+		hasCategory = hasCategory || activity.Category != ""
+		hasDuration = hasDuration || activity.Duration > 0
+	}
+
+	fmt.Fprintf(writer, "\n### %s (%d)\n\n", kindTitle(kind), len(activities))
+	header := []string{"Date", "Title"}
+	align := []string{"---", "---"}
+	if hasProject {
+		header, align = append(header, "Project"), append(align, "---")
+	}
+	if hasCategory {
+		header, align = append(header, "Category"), append(align, "---")
+	}
+	if hasDuration {
+		header, align = append(header, "Duration"), append(align, "---:")
+	}
+	fmt.Fprintf(writer, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(writer, "| %s |\n", strings.Join(align, " | "))
+
+	for _, activity := range activities {
+		title := escapeCell(activity.Title)
+		if activity.URL != "" {
+			title = fmt.Sprintf("[%s](%s)", escapeLinkText(title), activity.URL)
+		}
+		row := []string{activity.Time.Local().Format("2006-01-02"), title}
+		if hasProject {
+			row = append(row, escapeCell(activity.Project))
+		}
+		if hasCategory {
+			row = append(row, escapeCell(activity.Category))
+		}
+		if hasDuration {
+			row = append(row, formatOptionalDuration(activity.Duration))
+		}
+		fmt.Fprintf(writer, "| %s |\n", strings.Join(row, " | "))
+	}
+}
+
+// notionPageList lists Notion pages in the notion-urls format (## Category, - Title, indented URL)
+func notionPageList(activities []common.Activity) string {
+	byCategory := make(map[string][]common.Activity)
+	seen := make(map[string]bool)
+	for _, activity := range activities {
+		if activity.Source != "Notion" || activity.URL == "" || seen[activity.URL] {
+			continue
+		}
+		seen[activity.URL] = true
+		category := activity.Category
+		if category == "" {
+			category = "Uncategorized"
+		}
+		byCategory[category] = append(byCategory[category], activity)
+	}
+	if len(byCategory) == 0 {
+		return ""
+	}
+
+	var categories []string
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var builder strings.Builder
+	builder.WriteString("\n```markdown\n")
+	for _, category := range categories {
+		fmt.Fprintf(&builder, "## %s\n", category)
+		for _, page := range byCategory[category] {
+			fmt.Fprintf(&builder, "- %s\n    - %s\n", page.Title, page.URL)
+		}
+	}
+	builder.WriteString("```\n")
+	return builder.String()
+}
+
+// kindTitle turns an activity kind such as pr_authored into "Pr Authored"
+func kindTitle(kind string) string {
+	if kind == "" {
+		return "Other"
+	}
+	words := strings.Split(kind, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Duration:
+		return common.FormatDuration(v)
+	case float64:
+		return fmt.Sprintf("%.1f", v)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func formatOptionalDuration(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return common.FormatDuration(d)
+}
+
+// anchor returns the GitHub-style heading anchor of title
+func anchor(title string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r == ' ':
+			builder.WriteRune('-')
+		case r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r > 127:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+func escapeCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+func escapeLinkText(value string) string {
+	value = strings.ReplaceAll(value, "[", `\[`)
+	return strings.ReplaceAll(value, "]", `\]`)
+}
@@ -0,0 +1,226 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// The dev-stats section of a daily note is kept between these markers so that re-runs replace it
+// without touching what the user wrote in the note
+const (
+	obsidianBlockStart = "<!-- dev-stats:start -->"
+	obsidianBlockEnd   = "<!-- dev-stats:end -->"
+)
+
+// defaultDailyNoteFormat is Obsidian's default daily note name (a Moment.js format)
+const defaultDailyNoteFormat = "YYYY-MM-DD"
+
+// dailyNotesSettings is the Daily notes core plugin configuration in .obsidian/daily-notes.json
+type dailyNotesSettings struct {
+	Folder string `json:"folder"`
+	Format string `json:"format"`
+}
+
+// ObsidianVault writes per-day summaries into a vault's daily notes
+type ObsidianVault struct {
+	Path   string
+	Folder string // daily notes folder relative to the vault, empty for the vault root
+	Format string // Moment.js date format of the note name, may contain subfolders (YYYY/MM/YYYY-MM-DD)
+}
+
+// NewObsidianVault opens the vault at path and reads its daily notes folder and name format,
+// falling back to Obsidian's defaults (vault root, YYYY-MM-DD) when the plugin was never configured
+func NewObsidianVault(path string) (*ObsidianVault, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, common.WrapError(err, "Obsidian vault not found")
+	}
+	if !info.IsDir() {
+		return nil, common.NewError("Obsidian vault %s is not a directory", path)
+	}
+
+	vault := &ObsidianVault{Path: path, Format: defaultDailyNoteFormat}
+	data, err := os.ReadFile(filepath.Join(path, ".obsidian", "daily-notes.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return vault, nil
+	}
+	if err != nil {
+		return nil, common.WrapError(err, "failed to read daily notes settings")
+	}
+
+	var settings dailyNotesSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, common.WrapError(err, "failed to parse .obsidian/daily-notes.json")
+	}
+	vault.Folder = strings.Trim(settings.Folder, "/")
+	if settings.Format != "" {
+		vault.Format = settings.Format
+	}
+	return vault, nil
+}
+
+// NotePath returns the path of the daily note for date (YYYY-MM-DD)
+func (v *ObsidianVault) NotePath(date string) (string, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", common.WrapError(err, "invalid date %s", date)
+	}
+	return filepath.Join(v.Path, filepath.FromSlash(v.Folder), filepath.FromSlash(formatMomentDate(v.Format, day))+".md"), nil
+}
+
+// ExportDailyNotes writes one dev-stats section per day into the daily notes, creating notes that do not exist yet.
+// Days without activity are left alone. Returns the number of notes written.
+func (v *ObsidianVault) ExportDailyNotes(writer io.Writer, days []common.TimelineDay) (int, error) {
+	written := 0
+	for _, day := range days {
+		path, err := v.NotePath(day.Date)
+		if err != nil {
+			return written, err
+		}
+		if err := writeDailyNoteSection(path, dailyNoteSection(day)); err != nil {
+			return written, err
+		}
+		fmt.Fprintf(writer, "  📝 %s\n", path)
+		written++
+	}
+	return written, nil
+}
+
+// writeDailyNoteSection replaces the dev-stats section of the note at path, or appends it when there is none
+func writeDailyNoteSection(path, section string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return common.WrapError(err, "failed to read %s", path)
+	}
+
+	content := string(existing)
+	start := strings.Index(content, obsidianBlockStart)
+	end := strings.Index(content, obsidianBlockEnd)
+	switch {
+	case start >= 0 && end > start:
+		content = content[:start] + section + content[end+len(obsidianBlockEnd):]
+	case content == "":
+		content = section + "\n"
+	default:
+		content = strings.TrimRight(content, "\n") + "\n\n" + section + "\n"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return common.WrapError(err, "failed to create %s", filepath.Dir(path))
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return common.WrapError(err, "failed to write %s", path)
+	}
+	return nil
+}
+
+// dailyNoteSection renders the day's items grouped by source (meetings, PRs, pages, tickets, ...) between the markers
+func dailyNoteSection(day common.TimelineDay) string {
+	bySource := make(map[string][]common.Activity)
+	var sources []string
+	for _, activity := range day.Activities {
+		if _, exists := bySource[activity.Source]; !exists {
+			sources = append(sources, activity.Source)
+		}
+		bySource[activity.Source] = append(bySource[activity.Source], activity)
+	}
+	sort.Strings(sources)
+
+	var builder strings.Builder
+	builder.WriteString(obsidianBlockStart + "\n")
+	builder.WriteString("## Dev Stats\n")
+	for _, source := range sources {
+		activities := bySource[source]
+		var scheduled time.Duration
+		for _, activity := range activities {
+			if activity.Kind == common.ActivityKindEvent {
+				scheduled += activity.Duration
+			}
+		}
+		if scheduled > 0 {
+			fmt.Fprintf(&builder, "\n### %s (%d, %s)\n", source, len(activities), common.FormatDuration(scheduled))
+		} else {
+			fmt.Fprintf(&builder, "\n### %s (%d)\n", source, len(activities))
+		}
+
+		for _, activity := range activities {
+			clock := activity.Time.Local().Format("15:04")
+			if common.IsAllDayActivity(activity) {
+				clock = "all day"
+			}
+			title := activity.Title
+			if activity.URL != "" {
+				title = fmt.Sprintf("[%s](%s)", escapeLinkText(title), activity.URL)
+			}
+			line := fmt.Sprintf("- %s %s: %s", clock, activity.Kind, title)
+			if activity.Duration > 0 {
+				line += fmt.Sprintf(" (%s)", common.FormatDuration(activity.Duration))
+			}
+			builder.WriteString(line + "\n")
+		}
+	}
+	builder.WriteString(obsidianBlockEnd)
+	return builder.String()
+}
+
+// momentTokens are the Moment.js tokens supported in daily note formats, longest first
+var momentTokens = []struct {
+	token  string
+	format func(time.Time) string
+}{
+	{"YYYY", func(t time.Time) string { return t.Format("2006") }},
+	{"YY", func(t time.Time) string { return t.Format("06") }},
+	{"MMMM", func(t time.Time) string { return t.Format("January") }},
+	{"MMM", func(t time.Time) string { return t.Format("Jan") }},
+	{"MM", func(t time.Time) string { return t.Format("01") }},
+	{"M", func(t time.Time) string { return t.Format("1") }},
+	{"DDDD", func(t time.Time) string { return fmt.Sprintf("%03d", t.YearDay()) }},
+	{"DD", func(t time.Time) string { return t.Format("02") }},
+	{"D", func(t time.Time) string { return t.Format("2") }},
+	{"dddd", func(t time.Time) string { return t.Format("Monday") }},
+	{"ddd", func(t time.Time) string { return t.Format("Mon") }},
+	{"GGGG", func(t time.Time) string { year, _ := t.ISOWeek(); return fmt.Sprintf("%d", year) }},
+	{"WW", func(t time.Time) string { _, week := t.ISOWeek(); return fmt.Sprintf("%02d", week) }},
+	{"W", func(t time.Time) string { _, week := t.ISOWeek(); return fmt.Sprintf("%d", week) }},
+	{"Q", func(t time.Time) string { return fmt.Sprintf("%d", (int(t.Month())-1)/3+1) }},
+}
+
+// formatMomentDate formats day with a Moment.js format as used by Obsidian. Text in [brackets] is literal;
+// unsupported tokens are copied as is.
+func formatMomentDate(format string, day time.Time) string {
+	var builder strings.Builder
+	for i := 0; i < len(format); {
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end > 0 {
+				builder.WriteString(format[i+1 : i+end])
+				i += end + 1
+				continue
+			}
+		}
+
+		matched := false
+		for _, token := range momentTokens {
+			if strings.HasPrefix(format[i:], token.token) {
+				builder.WriteString(token.format(day))
+				i += len(token.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			builder.WriteByte(format[i])
+			i++
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,279 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// ReviewFileName is the self-review document written by `dev-stats review`
+const ReviewFileName = "self-review.md"
+
+// reviewTopCount is how many repositories, projects, and highlights are listed per section
+const reviewTopCount = 10
+
+// reviewHighlightCount is how many of the largest PRs and pages are suggested as highlights
+const reviewHighlightCount = 5
+
+// SelfReview is the input of the self-review document
+type SelfReview struct {
+	Results      []*common.AnalysisResult
+	WorkItems    []common.WorkItem
+	Achievements []common.Achievement // logged with `dev-stats log` within the period
+	StartDate    time.Time
+	EndDate      time.Time
+}
+
+// repositoryTotal counts the PRs of one repository
+type repositoryTotal struct {
+	Name     string
+	Authored int
+	Involved int
+	Lines    int // changed lines of authored PRs
+}
+
+// SaveSelfReview writes the self-review document to path
+func SaveSelfReview(path string, review SelfReview) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return common.WrapError(err, "failed to create %s", path)
+	}
+	defer file.Close()
+
+	WriteSelfReview(file, review)
+	return nil
+}
+
+// WriteSelfReview renders a self-review template: an overview in numbers, top repositories, biggest projects,
+// contribution highlights, and every metric as an appendix. Prompts in italics are meant to be replaced with prose.
+func WriteSelfReview(writer io.Writer, review SelfReview) {
+	fmt.Fprintf(writer, "# Self-Review (%s to %s)\n\n", review.StartDate.Format("2006-01-02"), review.EndDate.Format("2006-01-02"))
+	fmt.Fprintln(writer, "> Generated by dev-stats. Replace the prompts in *italics* with your own words; the numbers are listed in the appendix.")
+
+	writeReviewOverview(writer, review.Results)
+	writeTopRepositories(writer, review.Results)
+	writeBiggestProjects(writer, review.WorkItems)
+	writeHighlights(writer, review)
+	writeGrowth(writer)
+	writeRawCounts(writer, review.Results)
+}
+
+// writeReviewOverview summarizes each source in one line: items by kind and scheduled hours
+func writeReviewOverview(writer io.Writer, results []*common.AnalysisResult) {
+	fmt.Fprintln(writer, "\n## Summary")
+	fmt.Fprintln(writer, "\n*Describe your main focus this period and the outcome you are most proud of.*")
+	fmt.Fprintln(writer)
+
+	for _, result := range results {
+		kinds := make(map[string]int)
+		var names []string
+		var scheduled time.Duration
+		for _, activity := range result.Activities {
+			if kinds[activity.Kind] == 0 {
+				names = append(names, activity.Kind)
+			}
+			kinds[activity.Kind]++
+			if activity.Kind == common.ActivityKindEvent {
+				scheduled += activity.Duration
+			}
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			names[i] = fmt.Sprintf("%d %s", kinds[name], strings.ToLower(kindTitle(name)))
+		}
+
+		line := fmt.Sprintf("- **%s**: %d items", result.AnalyzerName, len(result.Activities))
+		if len(names) > 0 {
+			line += fmt.Sprintf(" (%s)", strings.Join(names, ", "))
+		}
+		if scheduled > 0 {
+			line += fmt.Sprintf(", %s scheduled", common.FormatDuration(scheduled))
+		}
+		fmt.Fprintln(writer, line)
+	}
+}
+
+// writeTopRepositories lists the repositories with the most PRs authored or taken part in
+func writeTopRepositories(writer io.Writer, results []*common.AnalysisResult) {
+	totals := make(map[string]*repositoryTotal)
+	for _, result := range results {
+		for _, activity := range result.Activities {
+			if !strings.HasPrefix(activity.Kind, "pr_") {
+				continue
+			}
+			name := repositoryFromPRURL(activity.URL)
+			if name == "" {
+				continue
+			}
+			total, exists := totals[name]
+			if !exists {
+				total = &repositoryTotal{Name: name}
+				totals[name] = total
+			}
+			if activity.Kind == "pr_authored" {
+				total.Authored++
+				total.Lines += activity.Size
+			} else {
+				total.Involved++
+			}
+		}
+	}
+	if len(totals) == 0 {
+		return
+	}
+
+	var repositories []repositoryTotal
+	for _, total := range totals {
+		repositories = append(repositories, *total)
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		a, b := repositories[i], repositories[j]
+		if a.Authored+a.Involved != b.Authored+b.Involved {
+			return a.Authored+a.Involved > b.Authored+b.Involved
+		}
+		return a.Name < b.Name
+	})
+	if len(repositories) > reviewTopCount {
+		repositories = repositories[:reviewTopCount]
+	}
+
+	fmt.Fprintln(writer, "\n## Top Repositories")
+	fmt.Fprintln(writer, "\n*What did you change in these repositories, and why did it matter?*")
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "| Repository | Authored PRs | Other PRs | Changed lines |")
+	fmt.Fprintln(writer, "| --- | ---: | ---: | ---: |")
+	for _, repository := range repositories {
+		fmt.Fprintf(writer, "| %s | %d | %d | %d |\n", escapeCell(repository.Name), repository.Authored, repository.Involved, repository.Lines)
+	}
+}
+
+// writeBiggestProjects lists projects by work items, with calendar hours, Notion pages, and PRs of each
+func writeBiggestProjects(writer io.Writer, items []common.WorkItem) {
+	fmt.Fprintln(writer, "\n## Biggest Projects")
+	projects := common.AggregateProjects(items)
+	if len(projects) == 0 {
+		fmt.Fprintln(writer, "\n*No work items have a project. Assign projects in config/overrides.yaml or in Notion to fill this section.*")
+		return
+	}
+	sort.SliceStable(projects, func(i, j int) bool {
+		if projects[i].Count != projects[j].Count {
+			return projects[i].Count > projects[j].Count
+		}
+		return projects[i].Duration > projects[j].Duration
+	})
+	if len(projects) > reviewTopCount {
+		projects = projects[:reviewTopCount]
+	}
+
+	fmt.Fprintln(writer, "\n*For each project: your role, the decisions you drove, and the result.*")
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "| Project | Work items | Calendar hours | Notion pages | PRs | Other |")
+	fmt.Fprintln(writer, "| --- | ---: | ---: | ---: | ---: | ---: |")
+	for _, project := range projects {
+		other := 0
+		for source, count := range project.Sources {
+			if source != "Calendar" && source != "Notion" && source != "GitHub" {
+				other += count
+			}
+		}
+		fmt.Fprintf(writer, "| %s | %d | %.1f | %d | %d | %d |\n", escapeCell(project.Project), project.Count,
+			project.Duration.Hours(), project.Sources["Notion"], project.Sources["GitHub"], other)
+	}
+}
+
+// writeHighlights suggests highlights: logged achievements, the largest authored PRs, and the longest created pages
+func writeHighlights(writer io.Writer, review SelfReview) {
+	fmt.Fprintln(writer, "\n## Contribution Highlights")
+	fmt.Fprintln(writer, "\n*Keep the items that show impact and add one sentence on the effect of each.*")
+
+	if len(review.Achievements) > 0 {
+		fmt.Fprintln(writer, "\n### Logged Achievements")
+		fmt.Fprintln(writer)
+		for _, achievement := range review.Achievements {
+			fmt.Fprintf(writer, "- %s: %s\n", achievement.Time.Local().Format("2006-01-02"), achievement.Text)
+		}
+	}
+
+	writeLargestActivities(writer, "Largest Pull Requests", "changed lines", largestActivities(review.Results, "pr_authored"))
+	writeLargestActivities(writer, "Longest Pages Written", "words", largestActivities(review.Results, "page_created"))
+}
+
+// writeLargestActivities lists activities with their size
+func writeLargestActivities(writer io.Writer, title, unit string, activities []common.Activity) {
+	if len(activities) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "\n### %s\n\n", title)
+	for _, activity := range activities {
+		name := activity.Title
+		if activity.URL != "" {
+			name = fmt.Sprintf("[%s](%s)", escapeLinkText(name), activity.URL)
+		}
+		fmt.Fprintf(writer, "- %s (%d %s, %s)\n", name, activity.Size, unit, activity.Time.Local().Format("2006-01-02"))
+	}
+}
+
+// largestActivities returns the activities of kind with the largest size, largest first
+func largestActivities(results []*common.AnalysisResult, kind string) []common.Activity {
+	var activities []common.Activity
+	for _, result := range results {
+		for _, activity := range result.Activities {
+			if activity.Kind == kind && activity.Size > 0 {
+				activities = append(activities, activity)
+			}
+		}
+	}
+	sort.SliceStable(activities, func(i, j int) bool {
+		if activities[i].Size != activities[j].Size {
+			return activities[i].Size > activities[j].Size
+		}
+		return activities[i].Time.Before(activities[j].Time)
+	})
+	if len(activities) > reviewHighlightCount {
+		activities = activities[:reviewHighlightCount]
+	}
+	return activities
+}
+
+// writeGrowth adds the sections that numbers can't fill
+func writeGrowth(writer io.Writer) {
+	fmt.Fprintln(writer, "\n## Collaboration")
+	fmt.Fprintln(writer, "\n*Reviews, mentoring, and cross-team work you want to call out.*")
+	fmt.Fprintln(writer, "\n## Growth and Next Period")
+	fmt.Fprintln(writer, "\n*What you learned, what you would do differently, and your goals for the next period.*")
+}
+
+// writeRawCounts lists every metric of every analyzer
+func writeRawCounts(writer io.Writer, results []*common.AnalysisResult) {
+	fmt.Fprintln(writer, "\n## Appendix: Raw Counts")
+	for _, result := range results {
+		if len(result.Metrics) == 0 {
+			continue
+		}
+		fmt.Fprintf(writer, "\n### %s\n\n", result.AnalyzerName)
+		fmt.Fprintln(writer, "| Metric | Value |")
+		fmt.Fprintln(writer, "| --- | ---: |")
+		for _, metric := range result.Metrics {
+			fmt.Fprintf(writer, "| %s | %s |\n", escapeCell(metric.Label), escapeCell(formatValue(metric.Value)))
+		}
+	}
+}
+
+// repositoryFromPRURL returns owner/repo of a pull request URL such as https://github.com/owner/repo/pull/1
+func repositoryFromPRURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) < 4 || parts[2] != "pull" {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
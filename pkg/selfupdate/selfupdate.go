@@ -0,0 +1,293 @@
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// DefaultRepository is the GitHub repository whose releases are installed (override: DEV_STATS_UPDATE_REPO)
+const DefaultRepository = "ishikawam/dev-stats"
+
+// ChecksumsAsset is the release asset listing the SHA-256 of every binary, in sha256sum format
+const ChecksumsAsset = "checksums.txt"
+
+// Channels lists the release channels: stable installs only full releases, beta also pre-releases
+var Channels = []string{"stable", "beta"}
+
+// Release is a GitHub release
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	HTMLURL     string    `json:"html_url"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+	Size        int64  `json:"size"`
+}
+
+// Updater finds and installs releases of one repository
+type Updater struct {
+	client     *common.HTTPClient
+	repository string
+	apiURL     string
+}
+
+// NewUpdater creates an updater for DEV_STATS_UPDATE_REPO (owner/repo), else DefaultRepository
+func NewUpdater() *Updater {
+	repository := strings.TrimSpace(os.Getenv("DEV_STATS_UPDATE_REPO"))
+	if repository == "" {
+		repository = DefaultRepository
+	}
+	client := common.NewHTTPClient()
+	// The latest release must be read fresh each time, and release binaries don't belong in .http-cache/
+	client.DisableCache()
+	client.SetHeader("Accept", "application/vnd.github+json")
+	return &Updater{client: client, repository: repository, apiURL: "https://api.github.com"}
+}
+
+// Repository returns the owner/repo releases are read from
+func (u *Updater) Repository() string {
+	return u.repository
+}
+
+// AssetName returns the name of the binary asset for a platform (dev-stats_linux_amd64, dev-stats_windows_amd64.exe)
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("dev-stats_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Latest returns the newest published release of the channel, by version
+func (u *Updater) Latest(channel string) (*Release, error) {
+	if channel != "stable" && channel != "beta" {
+		return nil, common.NewError("unknown channel %q (expected %s)", channel, strings.Join(Channels, " or "))
+	}
+	body, err := u.client.Get(fmt.Sprintf("%s/repos/%s/releases?per_page=50", u.apiURL, u.repository), nil)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to list releases of %s", u.repository)
+	}
+	var releases []Release
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, common.WrapError(err, "failed to parse releases of %s", u.repository)
+	}
+
+	var latest *Release
+	for i, release := range releases {
+		if release.Draft || (release.Prerelease && channel == "stable") {
+			continue
+		}
+		if _, ok := parseVersion(release.TagName); !ok {
+			continue
+		}
+		if latest == nil || CompareVersions(release.TagName, latest.TagName) > 0 {
+			latest = &releases[i]
+		}
+	}
+	if latest == nil {
+		return nil, common.NewError("no %s release of %s found", channel, u.repository)
+	}
+	return latest, nil
+}
+
+// Install downloads the binary of the current platform from release, verifies its SHA-256 against the release's
+// checksums.txt, and replaces the executable at path with it
+func (u *Updater) Install(release *Release, path string) error {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	binary, checksums := findAsset(release, name), findAsset(release, ChecksumsAsset)
+	if binary == nil {
+		return common.NewError("release %s has no %s asset for this platform", release.TagName, name)
+	}
+	if checksums == nil {
+		return common.NewError("release %s has no %s; refusing to install an unverified binary", release.TagName, ChecksumsAsset)
+	}
+
+	sums, err := u.client.Get(checksums.DownloadURL, nil)
+	if err != nil {
+		return common.WrapError(err, "failed to download %s", ChecksumsAsset)
+	}
+	expected, err := checksumOf(sums, name)
+	if err != nil {
+		return err
+	}
+	data, err := u.client.Get(binary.DownloadURL, map[string]string{"Accept": "application/octet-stream"})
+	if err != nil {
+		return common.WrapError(err, "failed to download %s", name)
+	}
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return common.NewError("checksum mismatch for %s: expected %s, got %s", name, expected, actual)
+	}
+	return replaceExecutable(path, data)
+}
+
+// findAsset returns the asset of a release with the name, or nil
+func findAsset(release *Release, name string) *Asset {
+	for i, asset := range release.Assets {
+		if asset.Name == name {
+			return &release.Assets[i]
+		}
+	}
+	return nil
+}
+
+// checksumOf returns the SHA-256 listed for name in sha256sum output ("<hex>  <name>", or "<hex> *<name>")
+func checksumOf(sums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", common.NewError("%s lists no checksum for %s", ChecksumsAsset, name)
+}
+
+// replaceExecutable writes data next to the executable and renames it over, keeping the file mode. The old binary
+// is moved aside first, since Windows can't overwrite a running executable.
+func replaceExecutable(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return common.WrapError(err, "failed to stat %s", path)
+	}
+	dir := filepath.Dir(path)
+	temp, err := os.CreateTemp(dir, ".dev-stats-update-*")
+	if err != nil {
+		return common.WrapError(err, "failed to write to %s (run with permission to replace the binary)", dir)
+	}
+	tempPath := temp.Name()
+	defer os.Remove(tempPath)
+	if _, err := io.Copy(temp, bytes.NewReader(data)); err != nil {
+		temp.Close()
+		return common.WrapError(err, "failed to write %s", tempPath)
+	}
+	if err := temp.Close(); err != nil {
+		return common.WrapError(err, "failed to write %s", tempPath)
+	}
+	if err := os.Chmod(tempPath, info.Mode().Perm()|0o111); err != nil {
+		return common.WrapError(err, "failed to make %s executable", tempPath)
+	}
+
+	old := path + ".old"
+	os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return common.WrapError(err, "failed to move %s aside", path)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Rename(old, path)
+		return common.WrapError(err, "failed to replace %s", path)
+	}
+	os.Remove(old) // fails on Windows while the old binary runs; it is removed by the next update
+	return nil
+}
+
+// CompareVersions compares two versions like v1.4.0 and v1.5.0-beta.2 by semantic versioning: -1, 0, or 1.
+// Versions that don't parse (dev builds) are older than any release.
+func CompareVersions(a, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := 0; i < 3; i++ {
+		if va.numbers[i] != vb.numbers[i] {
+			return compareInts(va.numbers[i], vb.numbers[i])
+		}
+	}
+	// A pre-release is older than the release of the same numbers
+	switch {
+	case va.prerelease == vb.prerelease:
+		return 0
+	case va.prerelease == "":
+		return 1
+	case vb.prerelease == "":
+		return -1
+	}
+	return comparePrerelease(va.prerelease, vb.prerelease)
+}
+
+type version struct {
+	numbers    [3]int
+	prerelease string
+}
+
+// parseVersion parses v1.2.3, 1.2, or v1.2.3-rc.1 (build metadata after + is ignored)
+func parseVersion(value string) (version, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "v")
+	value, _, _ = strings.Cut(value, "+")
+	core, prerelease, _ := strings.Cut(value, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return version{}, false
+	}
+	var v version
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		v.numbers[i] = n
+	}
+	v.prerelease = prerelease
+	return v, true
+}
+
+// comparePrerelease compares dot-separated pre-release identifiers: numbers numerically, others as text
+func comparePrerelease(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numA, errA := strconv.Atoi(partsA[i])
+		numB, errB := strconv.Atoi(partsB[i])
+		switch {
+		case errA == nil && errB == nil:
+			if numA != numB {
+				return compareInts(numA, numB)
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(partsA[i], partsB[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(partsA), len(partsB))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,173 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+const slackAPIURL = "https://slack.com/api"
+
+// KudosCollector searches Slack for messages thanking the user.
+// search.messages requires a user token (xoxp-) with the search:read scope.
+type KudosCollector struct {
+	token  string
+	userID string
+	client *common.HTTPClient
+}
+
+// searchResponse is the response of search.messages
+type searchResponse struct {
+	OK       bool   `json:"ok"`
+	Error    string `json:"error"`
+	Messages struct {
+		Matches []struct {
+			Text      string `json:"text"`
+			Permalink string `json:"permalink"`
+			Timestamp string `json:"ts"`
+			User      string `json:"user"`
+			Username  string `json:"username"`
+		} `json:"matches"`
+		Paging struct {
+			Pages int `json:"pages"`
+		} `json:"paging"`
+	} `json:"messages"`
+}
+
+// NewKudosCollector creates a collector from SLACK_USER_TOKEN and SLACK_USER_ID; returns nil when Slack is not configured
+func NewKudosCollector() *KudosCollector {
+	token := os.Getenv("SLACK_USER_TOKEN")
+	userID := os.Getenv("SLACK_USER_ID")
+	if token == "" || userID == "" {
+		return nil
+	}
+	client := common.NewHTTPClient()
+	client.SetHeader("Authorization", "Bearer "+token)
+	return &KudosCollector{token: token, userID: userID, client: client}
+}
+
+// ValidateConfig checks that the token is accepted by Slack
+func (c *KudosCollector) ValidateConfig(writer io.Writer) error {
+	body, err := c.client.Get(slackAPIURL+"/auth.test", nil)
+	if err != nil {
+		return common.WrapError(err, "failed to access Slack API")
+	}
+	var response struct {
+		OK     bool   `json:"ok"`
+		Error  string `json:"error"`
+		UserID string `json:"user_id"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return common.WrapError(err, "failed to parse Slack response")
+	}
+	if !response.OK {
+		return common.NewError("Slack API error: %s (check SLACK_USER_TOKEN)", response.Error)
+	}
+	if response.UserID != c.userID {
+		fmt.Fprintf(writer, "⚠️  SLACK_USER_ID (%s) differs from the token's user (%s)\n", c.userID, response.UserID)
+		return nil
+	}
+	fmt.Fprintln(writer, "✓ Slack token is valid")
+	return nil
+}
+
+// WhoAmI reports the owner of SLACK_USER_TOKEN
+func (c *KudosCollector) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	body, err := c.client.Get(slackAPIURL+"/auth.test", nil)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to access Slack API")
+	}
+	var response struct {
+		OK     bool   `json:"ok"`
+		Error  string `json:"error"`
+		User   string `json:"user"`
+		UserID string `json:"user_id"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, common.WrapError(err, "failed to parse Slack response")
+	}
+	if !response.OK {
+		return nil, common.NewError("Slack API error: %s (check SLACK_USER_TOKEN)", response.Error)
+	}
+
+	identity := &common.Identity{Source: "Slack", ID: response.UserID, Login: response.User}
+	if c.userID != "" && c.userID != response.UserID {
+		identity.Note = fmt.Sprintf("SLACK_USER_ID is %s", c.userID)
+	}
+	return identity, nil
+}
+
+// CollectKudos finds messages mentioning or sent to the user within the period that contain kudos keywords or emoji
+func (c *KudosCollector) CollectKudos(config *common.Config, writer io.Writer, matcher *common.KudosMatcher) ([]common.Kudos, error) {
+	// after:/before: are exclusive
+	dateRange := fmt.Sprintf("after:%s before:%s",
+		config.StartDate.AddDate(0, 0, -1).Format("2006-01-02"), config.EndDate.AddDate(0, 0, 1).Format("2006-01-02"))
+
+	seen := make(map[string]bool)
+	var kudos []common.Kudos
+	for _, query := range []string{fmt.Sprintf("<@%s> %s", c.userID, dateRange), "to:me " + dateRange} {
+		fmt.Fprintf(writer, "Searching Slack with query: %s\n", query)
+		for page := 1; ; page++ {
+			response, err := c.search(query, page)
+			if err != nil {
+				return nil, err
+			}
+			for _, match := range response.Messages.Matches {
+				if seen[match.Permalink] || match.User == c.userID || !matcher.Matches(match.Text) {
+					continue
+				}
+				seen[match.Permalink] = true
+				author := match.Username
+				if author == "" {
+					author = match.User
+				}
+				kudos = append(kudos, common.Kudos{
+					Source: "Slack",
+					Author: author,
+					Quote:  common.KudosQuote(strings.ReplaceAll(match.Text, "<@"+c.userID+">", "@me")),
+					URL:    match.Permalink,
+					Time:   slackTime(match.Timestamp),
+				})
+			}
+			if page >= response.Messages.Paging.Pages {
+				break
+			}
+		}
+	}
+	return kudos, nil
+}
+
+func (c *KudosCollector) search(query string, page int) (*searchResponse, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("count", "100")
+	params.Set("page", strconv.Itoa(page))
+	body, err := c.client.Get(fmt.Sprintf("%s/search.messages?%s", slackAPIURL, params.Encode()), nil)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to search Slack messages")
+	}
+	var response searchResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, common.WrapError(err, "failed to parse Slack response")
+	}
+	if !response.OK {
+		return nil, common.NewError("Slack API error: %s (SLACK_USER_TOKEN needs the search:read scope)", response.Error)
+	}
+	return &response, nil
+}
+
+// slackTime converts a Slack message timestamp ("1700000000.123456") to time
+func slackTime(ts string) time.Time {
+	seconds, err := strconv.ParseFloat(ts, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(int64(seconds), 0)
+}
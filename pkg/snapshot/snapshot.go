@@ -0,0 +1,408 @@
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/backlog"
+	"dev-stats/pkg/calendar"
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+	"dev-stats/pkg/copilot"
+	"dev-stats/pkg/focus"
+	"dev-stats/pkg/gitea"
+	"dev-stats/pkg/github"
+	"dev-stats/pkg/google"
+	"dev-stats/pkg/harvest"
+	"dev-stats/pkg/ingest"
+	"dev-stats/pkg/jira"
+	"dev-stats/pkg/notion"
+	"dev-stats/pkg/opsgenie"
+	"dev-stats/pkg/phabricator"
+	"dev-stats/pkg/support"
+	"dev-stats/pkg/todoist"
+)
+
+// DefaultDir holds one directory per snapshot case
+const DefaultDir = "testdata/snapshots"
+
+const (
+	caseFileName     = "case.yaml"
+	expectedFileName = "expected.txt"
+	workdirName      = "workdir" // copied into the working directory of the run (config/, storage/calendar/, ...)
+)
+
+// Analyzers builds the analyzer named in case.yaml. Analyzers that use common.HTTPClient are served the recorded
+// responses without changes, so a new analyzer only needs an entry here to get snapshot cases.
+var Analyzers = map[string]func() (common.Analyzer, error){
+	"github": func() (common.Analyzer, error) {
+		return github.NewGitHubAnalyzer(), nil
+	},
+	"backlog": func() (common.Analyzer, error) {
+		profiles := backlog.LoadBacklogProfiles()
+		if len(profiles) == 0 {
+			return nil, common.NewError("no Backlog profile in the case env (BACKLOG_<PROFILE>_API_KEY/HOST/USER_ID/PROJECT_ID)")
+		}
+		return backlog.NewBacklogAnalyzerWithProfile(&profiles[0]), nil
+	},
+	"calendar": func() (common.Analyzer, error) {
+		return calendar.NewCalendarAnalyzer()
+	},
+	"google": func() (common.Analyzer, error) {
+		return google.NewGDocsAnalyzer(), nil // the case env sets GOOGLE_TAKEOUT_PATH, or the workdir has a fixture token for the Drive API
+	},
+	"notion": func() (common.Analyzer, error) {
+		return notion.NewNotionAnalyzer()
+	},
+	"todoist": func() (common.Analyzer, error) {
+		return todoist.NewTodoistAnalyzer(), nil
+	},
+	"jira": func() (common.Analyzer, error) {
+		return jira.NewJiraAnalyzer(), nil
+	},
+	"harvest": func() (common.Analyzer, error) {
+		return harvest.NewHarvestAnalyzer(), nil
+	},
+	"support": func() (common.Analyzer, error) {
+		return support.NewSupportAnalyzer(), nil
+	},
+	"opsgenie": func() (common.Analyzer, error) {
+		return opsgenie.NewOpsgenieAnalyzer(), nil
+	},
+	"copilot": func() (common.Analyzer, error) {
+		return copilot.NewCopilotAnalyzer(), nil
+	},
+	"gitea": func() (common.Analyzer, error) {
+		return gitea.NewGiteaAnalyzer(), nil
+	},
+	"phabricator": func() (common.Analyzer, error) {
+		return phabricator.NewPhabricatorAnalyzer(), nil
+	},
+	"focus": func() (common.Analyzer, error) {
+		return focus.NewFocusAnalyzer(), nil
+	},
+	"github-archive": func() (common.Analyzer, error) {
+		return github.NewArchiveAnalyzer(), nil
+	},
+	"webhooks": func() (common.Analyzer, error) {
+		return ingest.NewWebhookAnalyzer(), nil
+	},
+}
+
+// preservedEnv are kept when the environment is replaced by the case env
+var preservedEnv = []string{"PATH", "HOME", "TMPDIR"}
+
+// Case is one analyzer run against recorded API responses (<dir>/<name>/case.yaml)
+type Case struct {
+	Name      string            `yaml:"-"`
+	Dir       string            `yaml:"-"`
+	Analyzer  string            `yaml:"analyzer"`
+	StartDate string            `yaml:"start_date"`
+	EndDate   string            `yaml:"end_date"`
+	Summary   bool              `yaml:"summary_only"` // run common.AnalyzeSummary as -summary-only does
+	Env       map[string]string `yaml:"env"`
+	Responses []Response        `yaml:"responses"`
+}
+
+// LoadCases loads the named cases, or every case in dir when names is empty
+func LoadCases(dir string, names []string) ([]*Case, error) {
+	if len(names) == 0 {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, common.WrapError(err, "failed to read %s", dir)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+	}
+
+	var cases []*Case
+	for _, name := range names {
+		caseDir, err := filepath.Abs(filepath.Join(dir, name))
+		if err != nil {
+			return nil, common.WrapError(err, "failed to resolve %s", name)
+		}
+		snapshotCase := &Case{Name: name, Dir: caseDir}
+		if _, err := config.LoadStrictYAML(filepath.Join(caseDir, caseFileName), snapshotCase); err != nil {
+			return nil, err
+		}
+		if _, exists := Analyzers[snapshotCase.Analyzer]; !exists {
+			return nil, common.NewError("%s: unknown analyzer '%s'", name, snapshotCase.Analyzer)
+		}
+		cases = append(cases, snapshotCase)
+	}
+	return cases, nil
+}
+
+// Run runs the analyzer in a temporary working directory with only the case env, UTC as the local time zone,
+// and the recorded responses. The output is the analyzer report, its metrics by ID and warnings, and requests without a recording.
+func (c *Case) Run() (string, error) {
+	startDate, err := time.Parse("2006-01-02", c.StartDate)
+	if err != nil {
+		return "", common.WrapError(err, "%s: invalid start_date", c.Name)
+	}
+	endDate, err := time.Parse("2006-01-02", c.EndDate)
+	if err != nil {
+		return "", common.WrapError(err, "%s: invalid end_date", c.Name)
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		return "", common.WrapError(err, "failed to get working directory")
+	}
+	workDir, err := os.MkdirTemp("", "dev-stats-snapshot-")
+	if err != nil {
+		return "", common.WrapError(err, "failed to create working directory")
+	}
+	defer os.RemoveAll(workDir)
+
+	// Shared categorization rules first, so that cases only need to ship what they change
+	if err := copyFile(filepath.Join(originalDir, "config", "categorization.yaml"), filepath.Join(workDir, "config", "categorization.yaml")); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := copyTree(filepath.Join(c.Dir, workdirName), workDir); err != nil {
+		return "", err
+	}
+
+	restoreEnv := replaceEnv(c.Env)
+	defer restoreEnv()
+
+	originalLocal := time.Local
+	time.Local = time.UTC
+	defer func() { time.Local = originalLocal }()
+
+	transport := &FixtureTransport{Dir: c.Dir, Responses: c.Responses}
+	previousTransport := common.SetDefaultTransport(transport)
+	defer common.SetDefaultTransport(previousTransport)
+
+	if err := os.Chdir(workDir); err != nil {
+		return "", common.WrapError(err, "failed to enter working directory")
+	}
+	defer os.Chdir(originalDir)
+
+	var output bytes.Buffer
+	analyzer, err := Analyzers[c.Analyzer]()
+	if err != nil {
+		fmt.Fprintf(&output, "Error: %v\n", err)
+	} else if result, err := c.analyze(analyzer, &common.Config{StartDate: startDate, EndDate: endDate}, &output); err != nil {
+		fmt.Fprintf(&output, "Error: %v\n", err)
+	} else {
+		fmt.Fprintln(&output, "\n--- metrics ---")
+		for _, metric := range result.Metrics {
+			fmt.Fprintf(&output, "%s = %v\n", metric.ID, metric.Value)
+		}
+		if len(result.Warnings) > 0 {
+			fmt.Fprintln(&output, "\n--- warnings ---")
+			common.PrintWarningList(&output, result.Warnings)
+		}
+	}
+
+	if unmatched := transport.unmatchedRequests(); len(unmatched) > 0 {
+		fmt.Fprintln(&output, "\n--- requests without a recorded response ---")
+		for _, request := range unmatched {
+			fmt.Fprintln(&output, request)
+		}
+	}
+
+	// Temporary paths differ per run
+	return strings.ReplaceAll(output.String(), workDir, "<workdir>"), nil
+}
+
+// analyze runs the analyzer in full, or only its counts for summary_only cases
+func (c *Case) analyze(analyzer common.Analyzer, cfg *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if c.Summary {
+		return common.AnalyzeSummary(analyzer, cfg, writer)
+	}
+	return analyzer.Analyze(cfg, writer)
+}
+
+// Check runs every case twice and compares its output with expected.txt, or rewrites expected.txt when update is set.
+// A case whose two runs differ fails (and is not written), since its output depends on map order or similar.
+// It returns the number of cases whose output changed.
+func Check(writer io.Writer, cases []*Case, update bool) (int, error) {
+	failed := 0
+	for _, snapshotCase := range cases {
+		actual, err := snapshotCase.Run()
+		if err != nil {
+			return failed, err
+		}
+		rerun, err := snapshotCase.Run()
+		if err != nil {
+			return failed, err
+		}
+		if rerun != actual {
+			failed++
+			fmt.Fprintf(writer, "✗ %s: output differs between two runs (- first, + second); sort before printing\n", snapshotCase.Name)
+			printDiff(writer, actual, rerun)
+			continue
+		}
+
+		expectedPath := filepath.Join(snapshotCase.Dir, expectedFileName)
+		if update {
+			if err := os.WriteFile(expectedPath, []byte(actual), 0644); err != nil {
+				return failed, common.WrapError(err, "failed to write %s", expectedPath)
+			}
+			fmt.Fprintf(writer, "📝 %s: updated %s\n", snapshotCase.Name, expectedFileName)
+			continue
+		}
+
+		expected, err := os.ReadFile(expectedPath)
+		if os.IsNotExist(err) {
+			failed++
+			fmt.Fprintf(writer, "✗ %s: %s is missing (run with -update to create it)\n", snapshotCase.Name, expectedFileName)
+			continue
+		}
+		if err != nil {
+			return failed, common.WrapError(err, "failed to read %s", expectedPath)
+		}
+		if string(expected) == actual {
+			fmt.Fprintf(writer, "✓ %s\n", snapshotCase.Name)
+			continue
+		}
+
+		failed++
+		fmt.Fprintf(writer, "✗ %s: output differs from %s (- expected, + actual)\n", snapshotCase.Name, expectedFileName)
+		printDiff(writer, string(expected), actual)
+	}
+	return failed, nil
+}
+
+// printDiff prints a line diff, collapsing long runs of unchanged lines
+func printDiff(writer io.Writer, expected, actual string) {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	// Longest common subsequence table
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	const context = 2
+	var unchanged []string
+	flush := func(last bool) {
+		if len(unchanged) > 2*context+1 || (last && len(unchanged) > context) {
+			for _, line := range unchanged[:context] {
+				fmt.Fprintf(writer, "    %s\n", line)
+			}
+			fmt.Fprintln(writer, "    ...")
+			if !last {
+				for _, line := range unchanged[len(unchanged)-context:] {
+					fmt.Fprintf(writer, "    %s\n", line)
+				}
+			}
+		} else {
+			for _, line := range unchanged {
+				fmt.Fprintf(writer, "    %s\n", line)
+			}
+		}
+		unchanged = nil
+	}
+
+	i, j := 0, 0
+	started := false
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			if started {
+				unchanged = append(unchanged, a[i])
+			}
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			flush(false)
+			started = true
+			fmt.Fprintf(writer, "  + %s\n", b[j])
+			j++
+		default:
+			flush(false)
+			started = true
+			fmt.Fprintf(writer, "  - %s\n", a[i])
+			i++
+		}
+	}
+	flush(true)
+}
+
+// replaceEnv clears the environment except preservedEnv, applies env, and returns a function restoring the original
+func replaceEnv(env map[string]string) func() {
+	original := os.Environ()
+	preserved := make(map[string]string)
+	for _, name := range preservedEnv {
+		if value, exists := os.LookupEnv(name); exists {
+			preserved[name] = value
+		}
+	}
+
+	os.Clearenv()
+	for name, value := range preserved {
+		os.Setenv(name, value)
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+
+	return func() {
+		os.Clearenv()
+		for _, entry := range original {
+			if parts := strings.SplitN(entry, "=", 2); len(parts) == 2 {
+				os.Setenv(parts[0], parts[1])
+			}
+		}
+	}
+}
+
+// copyTree copies the files under src into dst; a missing src copies nothing
+func copyTree(src, dst string) error {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		relative, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		return copyFile(path, filepath.Join(dst, relative))
+	})
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return common.WrapError(err, "failed to create %s", filepath.Dir(dst))
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		return common.WrapError(err, "failed to write %s", dst)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Response is a recorded API response served for matching requests
+type Response struct {
+	Method       string            `yaml:"method"`        // default GET
+	URL          string            `yaml:"url"`           // scheme://host/path, without the query
+	Query        map[string]string `yaml:"query"`         // each value must be contained in the request's parameter
+	BodyContains string            `yaml:"body_contains"` // for POST requests (e.g. Notion search filters)
+	Status       int               `yaml:"status"`        // default 200
+	Headers      map[string]string `yaml:"headers"`
+	BodyFile     string            `yaml:"body_file"` // relative to the case directory
+	Body         string            `yaml:"body"`
+}
+
+// matches reports whether the response was recorded for the request
+func (r Response) matches(req *http.Request, body string) bool {
+	method := r.Method
+	if method == "" {
+		method = "GET"
+	}
+	if !strings.EqualFold(method, req.Method) {
+		return false
+	}
+	if r.URL != req.URL.Scheme+"://"+req.URL.Host+req.URL.Path {
+		return false
+	}
+	query := req.URL.Query()
+	for key, value := range r.Query {
+		if !strings.Contains(query.Get(key), value) {
+			return false
+		}
+	}
+	return r.BodyContains == "" || strings.Contains(body, r.BodyContains)
+}
+
+// FixtureTransport serves recorded responses; the first matching entry wins.
+// Requests without a recording get a 404 and are listed in Unmatched so that missing fixtures show up in the snapshot.
+type FixtureTransport struct {
+	Dir       string
+	Responses []Response
+	Unmatched []string
+	mu        sync.Mutex // analyzers may send requests concurrently
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		body = string(data)
+	}
+
+	for _, recorded := range t.Responses {
+		if !recorded.matches(req, body) {
+			continue
+		}
+		content := []byte(recorded.Body)
+		if recorded.BodyFile != "" {
+			data, err := os.ReadFile(filepath.Join(t.Dir, recorded.BodyFile))
+			if err != nil {
+				return nil, err
+			}
+			content = data
+		}
+		status := recorded.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		header := make(http.Header)
+		header.Set("Content-Type", "application/json")
+		for key, value := range recorded.Headers {
+			header.Set(key, value)
+		}
+		return newResponse(req, status, header, content), nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Unmatched = append(t.Unmatched, fmt.Sprintf("%s %s://%s%s", req.Method, req.URL.Scheme, req.URL.Host, req.URL.Path))
+	return newResponse(req, http.StatusNotFound, make(http.Header), []byte(`{"message":"no fixture recorded"}`)), nil
+}
+
+// unmatchedRequests returns the distinct requests without a recording, sorted
+func (t *FixtureTransport) unmatchedRequests() []string {
+	seen := make(map[string]bool)
+	var requests []string
+	for _, request := range t.Unmatched {
+		if !seen[request] {
+			seen[request] = true
+			requests = append(requests, request)
+		}
+	}
+	sort.Strings(requests)
+	return requests
+}
+
+func newResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
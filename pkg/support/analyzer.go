@@ -0,0 +1,275 @@
+package support
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+// SupportAnalyzer implements the Analyzer interface for support desk tickets (Zendesk or Freshdesk)
+type SupportAnalyzer struct {
+	desk       desk
+	ignoreList config.IgnoreList
+	warnings   common.Warnings // comment and metric lookups that failed during the current run, shared with the desk
+}
+
+// Ticket is a ticket the user resolved or replied to within the period
+type Ticket struct {
+	ID            string        `json:"id"`
+	Subject       string        `json:"subject"`
+	Status        string        `json:"status"`
+	URL           string        `json:"url"`
+	CreatedAt     time.Time     `json:"created_at"`
+	ResolvedAt    time.Time     `json:"resolved_at,omitempty"` // set when the user resolved the ticket within the period
+	Replies       []Reply       `json:"replies,omitempty"`     // the user's public replies within the period
+	FirstResponse time.Duration `json:"first_response,omitempty"`
+
+	requesterID string // replies by the requester are not agent responses
+}
+
+// Resolved reports whether the user resolved the ticket within the period
+func (t Ticket) Resolved() bool {
+	return !t.ResolvedAt.IsZero()
+}
+
+// Reply is a public reply by the user
+type Reply struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+}
+
+// agent is the account behind the credentials
+type agent struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+// comment is a ticket comment (Zendesk) or conversation (Freshdesk)
+type comment struct {
+	ID           string
+	AuthorID     string
+	Public       bool
+	FromCustomer bool // written by the requester rather than an agent
+	CreatedAt    time.Time
+}
+
+// desk is a support desk API
+type desk interface {
+	name() string
+	configured() error // returns the error to show when credentials are missing
+	currentAgent() (*agent, error)
+	// candidateTickets returns tickets the agent may have resolved or replied to in the period,
+	// with ResolvedAt set for tickets the agent resolved in the period
+	candidateTickets(writer io.Writer, me *agent, startDate, endDate time.Time) ([]Ticket, error)
+	comments(ticket Ticket) ([]comment, error)
+}
+
+// NewSupportAnalyzer creates a support desk analyzer for Zendesk (ZENDESK_SUBDOMAIN) or Freshdesk (FRESHDESK_DOMAIN)
+func NewSupportAnalyzer() *SupportAnalyzer {
+	analyzer := &SupportAnalyzer{}
+	if os.Getenv("ZENDESK_SUBDOMAIN") == "" && os.Getenv("FRESHDESK_DOMAIN") != "" {
+		analyzer.desk = newFreshdesk()
+	} else {
+		analyzer.desk = newZendesk(&analyzer.warnings)
+	}
+	return analyzer
+}
+
+// GetName returns the analyzer name
+func (s *SupportAnalyzer) GetName() string {
+	return s.desk.name()
+}
+
+// ValidateConfig validates the required configuration
+func (s *SupportAnalyzer) ValidateConfig(writer io.Writer) error {
+	if err := s.desk.configured(); err != nil {
+		return err
+	}
+	if _, err := s.desk.currentAgent(); err != nil {
+		return common.WrapError(err, "failed to access %s API (check the %s credentials)", s.desk.name(), s.desk.name())
+	}
+	fmt.Fprintf(writer, "✓ %s credentials are valid\n", s.desk.name())
+	return nil
+}
+
+// WhoAmI reports the agent behind the support desk credentials
+func (s *SupportAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if err := s.desk.configured(); err != nil {
+		return nil, err
+	}
+	me, err := s.desk.currentAgent()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to access %s API (check the %s credentials)", s.desk.name(), s.desk.name())
+	}
+	return &common.Identity{Source: s.GetName(), ID: me.ID, Login: me.Email, Name: me.Name}, nil
+}
+
+// Analyze reports tickets resolved, tickets replied to, and the average first-response time
+func (s *SupportAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := s.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := s.ignoreList.Reload(); err != nil {
+		return nil, err
+	}
+	s.warnings.Reset()
+
+	me, err := s.desk.currentAgent()
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get %s agent", s.desk.name())
+	}
+
+	fmt.Fprintf(writer, "Fetching %s tickets from %s to %s...\n", s.desk.name(),
+		config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+	candidates, err := s.desk.candidateTickets(writer, me, config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get tickets")
+	}
+	candidates = s.filterIgnored(writer, candidates)
+
+	var tickets []Ticket
+	for _, ticket := range candidates {
+		comments, err := s.desk.comments(ticket)
+		if err != nil {
+			s.warnings.Add("ticket comments", ticket.ID, err)
+		} else {
+			applyComments(&ticket, comments, me.ID, config.StartDate, config.EndDate)
+		}
+		if ticket.Resolved() || len(ticket.Replies) > 0 {
+			tickets = append(tickets, ticket)
+		}
+	}
+	sort.SliceStable(tickets, func(i, j int) bool {
+		return tickets[i].CreatedAt.Before(tickets[j].CreatedAt)
+	})
+
+	resolved, replies, replied, firstResponses := 0, 0, 0, 0
+	var firstResponseTotal, averageFirstResponse time.Duration
+	for _, ticket := range tickets {
+		if ticket.Resolved() {
+			resolved++
+		}
+		if len(ticket.Replies) > 0 {
+			replied++
+			replies += len(ticket.Replies)
+		}
+		if ticket.FirstResponse > 0 {
+			firstResponses++
+			firstResponseTotal += ticket.FirstResponse
+		}
+	}
+	if firstResponses > 0 {
+		averageFirstResponse = (firstResponseTotal / time.Duration(firstResponses)).Round(time.Minute)
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: s.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "support.tickets_resolved", Label: "Tickets resolved", Value: resolved},
+			{ID: "support.tickets_replied", Label: "Tickets replied to", Value: replied},
+			{ID: "support.replies", Label: "Replies", Value: replies},
+			{ID: "support.first_responses", Label: "First responses", Value: firstResponses},
+			{ID: "support.avg_first_response", Label: "Average first-response time", Value: averageFirstResponse, Snapshot: true},
+		},
+		Details: map[string]interface{}{
+			"tickets": tickets,
+		},
+		Activities: s.buildActivities(tickets),
+		CSVTables:  csvTables(tickets),
+	}
+	result.Explain("support.tickets_resolved", result.Activities)
+	result.Explain("support.replies", result.Activities)
+
+	s.printResults(writer, result, tickets)
+	result.Warnings = s.warnings.List()
+	return result, nil
+}
+
+// applyComments records the user's public replies within the period and, when the user gave the ticket's
+// first agent response within the period, the time from ticket creation to that response
+func applyComments(ticket *Ticket, comments []comment, agentID string, startDate, endDate time.Time) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+	firstResponseSeen := false
+	for _, c := range comments {
+		if !c.Public || c.FromCustomer {
+			continue
+		}
+		mine := c.AuthorID == agentID
+		if mine && inPeriod(c.CreatedAt, startDate, endDate) {
+			ticket.Replies = append(ticket.Replies, Reply{ID: c.ID, Time: c.CreatedAt})
+			if !firstResponseSeen {
+				ticket.FirstResponse = c.CreatedAt.Sub(ticket.CreatedAt)
+			}
+		}
+		firstResponseSeen = true
+	}
+}
+
+// inPeriod reports whether t falls on a day from startDate to endDate (local time)
+func inPeriod(t, startDate, endDate time.Time) bool {
+	day := t.Local().Format("2006-01-02")
+	return day >= startDate.Format("2006-01-02") && day <= endDate.Format("2006-01-02")
+}
+
+// filterIgnored drops tickets listed in the ignore file by ID or URL
+func (s *SupportAnalyzer) filterIgnored(writer io.Writer, tickets []Ticket) []Ticket {
+	return config.FilterIgnored(writer, &s.ignoreList, tickets, "tickets", func(ticket Ticket) []string {
+		return []string{ticket.ID, ticket.URL}
+	})
+}
+
+// buildActivities converts resolutions and replies into dated activities
+func (s *SupportAnalyzer) buildActivities(tickets []Ticket) []common.Activity {
+	var activities []common.Activity
+	for _, ticket := range tickets {
+		title := fmt.Sprintf("#%s %s", ticket.ID, ticket.Subject)
+		for _, reply := range ticket.Replies {
+			activities = append(activities, common.Activity{
+				Source: s.GetName(),
+				Kind:   "ticket_reply",
+				ID:     ticket.URL + "#" + reply.ID,
+				Title:  title,
+				URL:    ticket.URL,
+				Time:   reply.Time,
+			})
+		}
+		if ticket.Resolved() {
+			activities = append(activities, common.Activity{
+				Source: s.GetName(),
+				Kind:   "ticket_resolved",
+				ID:     ticket.URL,
+				Title:  title,
+				URL:    ticket.URL,
+				Time:   ticket.ResolvedAt,
+			})
+		}
+	}
+	return activities
+}
+
+func (s *SupportAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, tickets []Ticket) {
+	fmt.Fprintf(writer, "\nTickets from %s to %s (%d):\n",
+		result.StartDate.Format("2006-01-02"), result.EndDate.Format("2006-01-02"), len(tickets))
+	for _, ticket := range tickets {
+		line := fmt.Sprintf("- #%s %s [%s] replies: %d", ticket.ID, ticket.Subject, ticket.Status, len(ticket.Replies))
+		if ticket.FirstResponse > 0 {
+			line += fmt.Sprintf(", first response in %s", common.FormatDuration(ticket.FirstResponse))
+		}
+		if ticket.Resolved() {
+			line += fmt.Sprintf(", resolved %s", ticket.ResolvedAt.Local().Format("2006-01-02"))
+		}
+		fmt.Fprintln(writer, line)
+	}
+
+	result.PrintSummary(writer)
+}
@@ -0,0 +1,37 @@
+package support
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// ticketCSVRow is one ticket in <desk>-tickets.csv
+type ticketCSVRow struct {
+	ID            string        `csv:"id"`
+	Subject       string        `csv:"subject"`
+	Status        string        `csv:"status"`
+	CreatedAt     time.Time     `csv:"created_at"`
+	Replies       int           `csv:"replies"`
+	FirstResponse time.Duration `csv:"first_response_hours"`
+	ResolvedAt    time.Time     `csv:"resolved_at"`
+	URL           string        `csv:"url"`
+}
+
+// csvTables lists the tickets resolved or replied to within the period
+func csvTables(tickets []Ticket) []common.CSVTable {
+	var rows []ticketCSVRow
+	for _, ticket := range tickets {
+		rows = append(rows, ticketCSVRow{
+			ID:            ticket.ID,
+			Subject:       ticket.Subject,
+			Status:        ticket.Status,
+			CreatedAt:     ticket.CreatedAt,
+			Replies:       len(ticket.Replies),
+			FirstResponse: ticket.FirstResponse,
+			ResolvedAt:    ticket.ResolvedAt,
+			URL:           ticket.URL,
+		})
+	}
+	return []common.CSVTable{common.NewCSVTable("tickets", rows)}
+}
@@ -0,0 +1,180 @@
+package support
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// freshdeskPageSize is the maximum page size of Freshdesk lists
+const freshdeskPageSize = 100
+
+// freshdeskStatuses names Freshdesk's built-in ticket statuses
+var freshdeskStatuses = map[int]string{2: "open", 3: "pending", 4: "resolved", 5: "closed"}
+
+// freshdesk reads tickets from the Freshdesk API with an API key
+type freshdesk struct {
+	domain string // e.g. mycompany.freshdesk.com
+	apiKey string
+	client *common.HTTPClient
+}
+
+// freshdeskTicket is a ticket in the /tickets list with include=stats
+type freshdeskTicket struct {
+	ID          int64     `json:"id"`
+	Subject     string    `json:"subject"`
+	Status      int       `json:"status"`
+	ResponderID *int64    `json:"responder_id"`
+	RequesterID int64     `json:"requester_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	Stats       struct {
+		ResolvedAt *time.Time `json:"resolved_at"`
+	} `json:"stats"`
+}
+
+// freshdeskConversation is a reply or note on a ticket
+type freshdeskConversation struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Incoming  bool      `json:"incoming"`
+	Private   bool      `json:"private"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newFreshdesk() *freshdesk {
+	domain := strings.TrimSuffix(strings.TrimPrefix(os.Getenv("FRESHDESK_DOMAIN"), "https://"), "/")
+	if domain != "" && !strings.Contains(domain, ".") {
+		domain += ".freshdesk.com"
+	}
+	apiKey := os.Getenv("FRESHDESK_API_KEY")
+	client := common.NewHTTPClient()
+	client.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(apiKey+":X")))
+	return &freshdesk{
+		domain: domain,
+		apiKey: apiKey,
+		client: client,
+	}
+}
+
+func (f *freshdesk) name() string {
+	return "Freshdesk"
+}
+
+func (f *freshdesk) configured() error {
+	if f.domain == "" || f.apiKey == "" {
+		return common.NewError("FRESHDESK_DOMAIN and FRESHDESK_API_KEY environment variables are required")
+	}
+	return nil
+}
+
+func (f *freshdesk) apiURL(path string) string {
+	return fmt.Sprintf("https://%s/api/v2%s", f.domain, path)
+}
+
+func (f *freshdesk) currentAgent() (*agent, error) {
+	body, err := f.client.Get(f.apiURL("/agents/me"), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		ID      int64 `json:"id"`
+		Contact struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"contact"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, common.WrapError(err, "failed to parse Freshdesk agent")
+	}
+	return &agent{ID: strconv.FormatInt(response.ID, 10), Name: response.Contact.Name, Email: response.Contact.Email}, nil
+}
+
+// candidateTickets lists tickets updated since the start of the period and keeps those assigned to the user.
+// Freshdesk has no search for tickets an agent replied to, so replies on tickets assigned to others are not counted.
+func (f *freshdesk) candidateTickets(writer io.Writer, me *agent, startDate, endDate time.Time) ([]Ticket, error) {
+	var tickets []Ticket
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("updated_since", startDate.UTC().Format(time.RFC3339))
+		params.Set("include", "stats")
+		params.Set("order_by", "created_at")
+		params.Set("order_type", "asc")
+		params.Set("per_page", strconv.Itoa(freshdeskPageSize))
+		params.Set("page", strconv.Itoa(page))
+
+		body, err := f.client.Get(f.apiURL("/tickets?"+params.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		var response []freshdeskTicket
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Freshdesk tickets")
+		}
+
+		for _, item := range response {
+			if item.ResponderID == nil || strconv.FormatInt(*item.ResponderID, 10) != me.ID {
+				continue
+			}
+			status := freshdeskStatuses[item.Status]
+			if status == "" {
+				status = strconv.Itoa(item.Status)
+			}
+			ticket := Ticket{
+				ID:          strconv.FormatInt(item.ID, 10),
+				Subject:     item.Subject,
+				Status:      status,
+				URL:         fmt.Sprintf("https://%s/a/tickets/%d", f.domain, item.ID),
+				CreatedAt:   item.CreatedAt,
+				requesterID: strconv.FormatInt(item.RequesterID, 10),
+			}
+			if resolvedAt := item.Stats.ResolvedAt; resolvedAt != nil && inPeriod(*resolvedAt, startDate, endDate) {
+				ticket.ResolvedAt = *resolvedAt
+			}
+			tickets = append(tickets, ticket)
+		}
+
+		if len(response) < freshdeskPageSize {
+			break
+		}
+	}
+	fmt.Fprintf(writer, "Found %d tickets assigned to you updated since %s\n", len(tickets), startDate.Format("2006-01-02"))
+	return tickets, nil
+}
+
+// comments lists the ticket's conversations; incoming ones are from the customer
+func (f *freshdesk) comments(ticket Ticket) ([]comment, error) {
+	var comments []comment
+	for page := 1; ; page++ {
+		apiURL := f.apiURL(fmt.Sprintf("/tickets/%s/conversations?per_page=%d&page=%d", url.PathEscape(ticket.ID), freshdeskPageSize, page))
+		body, err := f.client.Get(apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		var response []freshdeskConversation
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Freshdesk conversations")
+		}
+		for _, c := range response {
+			userID := strconv.FormatInt(c.UserID, 10)
+			comments = append(comments, comment{
+				ID:           strconv.FormatInt(c.ID, 10),
+				AuthorID:     userID,
+				Public:       !c.Private,
+				FromCustomer: c.Incoming || userID == ticket.requesterID,
+				CreatedAt:    c.CreatedAt,
+			})
+		}
+		if len(response) < freshdeskPageSize {
+			break
+		}
+	}
+	return comments, nil
+}
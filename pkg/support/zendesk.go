@@ -0,0 +1,215 @@
+package support
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// zendesk reads tickets from the Zendesk Support API with an API token
+type zendesk struct {
+	subdomain string
+	email     string
+	token     string
+	client    *common.HTTPClient
+	warnings  *common.Warnings
+}
+
+// zendeskTicket is a ticket in Zendesk search results
+type zendeskTicket struct {
+	ID          int64     `json:"id"`
+	Subject     string    `json:"subject"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	RequesterID int64     `json:"requester_id"`
+}
+
+// zendeskSearchResponse is the /search.json response
+type zendeskSearchResponse struct {
+	Results  []zendeskTicket `json:"results"`
+	NextPage string          `json:"next_page"`
+}
+
+// zendeskCommentsResponse is the /tickets/{id}/comments.json response
+type zendeskCommentsResponse struct {
+	Comments []struct {
+		ID        int64     `json:"id"`
+		AuthorID  int64     `json:"author_id"`
+		Public    bool      `json:"public"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"comments"`
+	NextPage string `json:"next_page"`
+}
+
+func newZendesk(warnings *common.Warnings) *zendesk {
+	email := os.Getenv("ZENDESK_EMAIL")
+	token := os.Getenv("ZENDESK_API_TOKEN")
+	client := common.NewHTTPClient()
+	client.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(email+"/token:"+token)))
+	return &zendesk{
+		subdomain: os.Getenv("ZENDESK_SUBDOMAIN"),
+		email:     email,
+		token:     token,
+		client:    client,
+		warnings:  warnings,
+	}
+}
+
+func (z *zendesk) name() string {
+	return "Zendesk"
+}
+
+func (z *zendesk) configured() error {
+	if z.subdomain == "" || z.email == "" || z.token == "" {
+		return common.NewError("ZENDESK_SUBDOMAIN, ZENDESK_EMAIL, and ZENDESK_API_TOKEN (or FRESHDESK_DOMAIN and FRESHDESK_API_KEY) environment variables are required")
+	}
+	return nil
+}
+
+func (z *zendesk) apiURL(path string) string {
+	return fmt.Sprintf("https://%s.zendesk.com/api/v2%s", z.subdomain, path)
+}
+
+func (z *zendesk) currentAgent() (*agent, error) {
+	body, err := z.client.Get(z.apiURL("/users/me.json"), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		User struct {
+			ID    int64  `json:"id"`
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, common.WrapError(err, "failed to parse Zendesk user")
+	}
+	if response.User.ID == 0 {
+		return nil, common.NewError("Zendesk returned an anonymous user")
+	}
+	return &agent{ID: strconv.FormatInt(response.User.ID, 10), Name: response.User.Name, Email: response.User.Email}, nil
+}
+
+// candidateTickets searches tickets the user commented on and tickets assigned to the user that were solved in the period.
+// Solve times come from the ticket metrics.
+func (z *zendesk) candidateTickets(writer io.Writer, me *agent, startDate, endDate time.Time) ([]Ticket, error) {
+	first := startDate.Format("2006-01-02")
+	last := endDate.Format("2006-01-02")
+
+	commented, err := z.search(fmt.Sprintf("type:ticket commenter:me updated>=%s", first))
+	if err != nil {
+		return nil, err
+	}
+	solved, err := z.search(fmt.Sprintf("type:ticket assignee:me solved>=%s solved<=%s", first, last))
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(writer, "Found %d tickets commented on and %d tickets solved\n", len(commented), len(solved))
+
+	var tickets []Ticket
+	indexes := make(map[int64]int)
+	for _, result := range append(commented, solved...) {
+		if _, exists := indexes[result.ID]; exists {
+			continue
+		}
+		indexes[result.ID] = len(tickets)
+		tickets = append(tickets, Ticket{
+			ID:          strconv.FormatInt(result.ID, 10),
+			Subject:     result.Subject,
+			Status:      result.Status,
+			URL:         fmt.Sprintf("https://%s.zendesk.com/agent/tickets/%d", z.subdomain, result.ID),
+			CreatedAt:   result.CreatedAt,
+			requesterID: strconv.FormatInt(result.RequesterID, 10),
+		})
+	}
+
+	for _, result := range solved {
+		ticket := &tickets[indexes[result.ID]]
+		solvedAt, err := z.solvedAt(result.ID)
+		if err != nil {
+			z.warnings.Add("ticket metrics", ticket.ID, err)
+			continue
+		}
+		if !solvedAt.IsZero() && inPeriod(solvedAt, startDate, endDate) {
+			ticket.ResolvedAt = solvedAt
+		}
+	}
+	return tickets, nil
+}
+
+// search runs a ticket search, following next_page links
+func (z *zendesk) search(query string) ([]zendeskTicket, error) {
+	var tickets []zendeskTicket
+	params := url.Values{}
+	params.Set("query", query)
+	next := z.apiURL("/search.json?" + params.Encode())
+	for next != "" {
+		body, err := z.client.Get(next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var response zendeskSearchResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Zendesk search response")
+		}
+		tickets = append(tickets, response.Results...)
+		next = response.NextPage
+	}
+	return tickets, nil
+}
+
+// solvedAt returns when the ticket was last solved (zero when it never was)
+func (z *zendesk) solvedAt(ticketID int64) (time.Time, error) {
+	body, err := z.client.Get(z.apiURL(fmt.Sprintf("/tickets/%d/metrics.json", ticketID)), nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var response struct {
+		TicketMetric struct {
+			SolvedAt *time.Time `json:"solved_at"`
+		} `json:"ticket_metric"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return time.Time{}, common.WrapError(err, "failed to parse Zendesk ticket metrics")
+	}
+	if response.TicketMetric.SolvedAt == nil {
+		return time.Time{}, nil
+	}
+	return *response.TicketMetric.SolvedAt, nil
+}
+
+// comments lists the ticket's comments
+func (z *zendesk) comments(ticket Ticket) ([]comment, error) {
+	var comments []comment
+	next := z.apiURL(fmt.Sprintf("/tickets/%s/comments.json", url.PathEscape(ticket.ID)))
+	for next != "" {
+		body, err := z.client.Get(next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var response zendeskCommentsResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Zendesk comments")
+		}
+		for _, c := range response.Comments {
+			authorID := strconv.FormatInt(c.AuthorID, 10)
+			comments = append(comments, comment{
+				ID:           strconv.FormatInt(c.ID, 10),
+				AuthorID:     authorID,
+				Public:       c.Public,
+				FromCustomer: authorID == ticket.requesterID,
+				CreatedAt:    c.CreatedAt,
+			})
+		}
+		next = response.NextPage
+	}
+	return comments, nil
+}
@@ -0,0 +1,188 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"dev-stats/pkg/backlog"
+	"dev-stats/pkg/common"
+)
+
+// DefaultExportLogPath records tasks already exported so that re-runs don't create duplicates
+const DefaultExportLogPath = "storage/exported-tasks.json"
+
+// Task is an action item created in a task manager
+type Task struct {
+	Key   string // stable identity used to avoid duplicates (e.g. PR URL)
+	Title string
+	Notes string
+}
+
+// Exporter creates tasks in a task manager
+type Exporter interface {
+	Name() string
+	// Export creates the task and returns a reference to it (URL or ID)
+	Export(task Task) (string, error)
+}
+
+// NewExporter creates an exporter by name (todoist, things, backlog).
+// backlogProfile selects the Backlog profile; it may be empty if only one profile is configured.
+func NewExporter(name, backlogProfile string, writer io.Writer) (Exporter, error) {
+	switch name {
+	case "todoist":
+		token := os.Getenv("TODOIST_API_TOKEN")
+		if token == "" {
+			return nil, common.NewError("TODOIST_API_TOKEN environment variable is required")
+		}
+		client := common.NewHTTPClient()
+		client.SetHeader("Authorization", "Bearer "+token)
+		return &todoistExporter{client: client}, nil
+	case "things":
+		return &thingsExporter{writer: writer}, nil
+	case "backlog":
+		profile, err := selectBacklogProfile(backlogProfile)
+		if err != nil {
+			return nil, err
+		}
+		return &backlogExporter{analyzer: backlog.NewBacklogAnalyzerWithProfile(profile)}, nil
+	}
+	return nil, common.NewError("unknown export target: %s (expected todoist, things, or backlog)", name)
+}
+
+// selectBacklogProfile returns the named profile, or the only configured profile when name is empty
+func selectBacklogProfile(name string) (*backlog.BacklogProfile, error) {
+	if name != "" {
+		return backlog.GetProfileByName(name)
+	}
+	profiles := backlog.LoadBacklogProfiles()
+	if len(profiles) != 1 {
+		return nil, common.NewError("%d Backlog profiles are configured; specify one with -backlog-profile", len(profiles))
+	}
+	return &profiles[0], nil
+}
+
+// todoistExporter creates tasks with the Todoist REST API
+type todoistExporter struct {
+	client *common.HTTPClient
+}
+
+func (e *todoistExporter) Name() string {
+	return "todoist"
+}
+
+func (e *todoistExporter) Export(task Task) (string, error) {
+	payload, err := json.Marshal(map[string]string{"content": task.Title, "description": task.Notes})
+	if err != nil {
+		return "", err
+	}
+	body, err := e.client.Post("https://api.todoist.com/rest/v2/tasks", string(payload), map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return "", common.WrapError(err, "failed to create Todoist task")
+	}
+	var created struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return "", common.WrapError(err, "failed to parse Todoist response")
+	}
+	if created.URL != "" {
+		return created.URL, nil
+	}
+	return created.ID, nil
+}
+
+// thingsExporter prints things:///add links; Things has no web API, so the links are opened on the Mac
+type thingsExporter struct {
+	writer io.Writer
+}
+
+func (e *thingsExporter) Name() string {
+	return "things"
+}
+
+func (e *thingsExporter) Export(task Task) (string, error) {
+	params := url.Values{}
+	params.Set("title", task.Title)
+	params.Set("notes", task.Notes)
+	link := "things:///add?" + strings.ReplaceAll(params.Encode(), "+", "%20")
+	fmt.Fprintf(e.writer, "  %s\n", link)
+	return link, nil
+}
+
+// backlogExporter creates issues in the profile's project
+type backlogExporter struct {
+	analyzer *backlog.BacklogAnalyzer
+}
+
+func (e *backlogExporter) Name() string {
+	return "backlog"
+}
+
+func (e *backlogExporter) Export(task Task) (string, error) {
+	issue, err := e.analyzer.CreateIssue(task.Title, task.Notes)
+	if err != nil {
+		return "", err
+	}
+	return e.analyzer.IssueURL(issue.IssueKey), nil
+}
+
+// ExportLog maps export target -> task key -> reference of the created task
+type ExportLog map[string]map[string]string
+
+// LoadExportLog loads the export log. A missing file means nothing has been exported.
+func LoadExportLog(path string) (ExportLog, error) {
+	exportLog := make(ExportLog)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return exportLog, nil
+	}
+	if err := common.ReadJSONFile(path, &exportLog); err != nil {
+		return nil, err
+	}
+	return exportLog, nil
+}
+
+// Export creates tasks that haven't been exported to the exporter's target yet.
+// With dryRun, tasks are only listed. Returns the number of tasks created.
+func Export(writer io.Writer, exporter Exporter, taskList []Task, logPath string, dryRun bool) (int, error) {
+	exportLog, err := LoadExportLog(logPath)
+	if err != nil {
+		return 0, err
+	}
+	exported := exportLog[exporter.Name()]
+	if exported == nil {
+		exported = make(map[string]string)
+		exportLog[exporter.Name()] = exported
+	}
+
+	created := 0
+	for _, task := range taskList {
+		if ref, exists := exported[task.Key]; exists {
+			fmt.Fprintf(writer, "- (already exported: %s) %s\n", ref, task.Title)
+			continue
+		}
+		if dryRun {
+			fmt.Fprintf(writer, "- (dry run) %s\n", task.Title)
+			continue
+		}
+		ref, err := exporter.Export(task)
+		if err != nil {
+			fmt.Fprintf(writer, "⚠️  Failed to export %s: %v\n", task.Title, err)
+			continue
+		}
+		fmt.Fprintf(writer, "✓ %s → %s\n", task.Title, ref)
+		exported[task.Key] = ref
+		created++
+	}
+
+	if created > 0 {
+		if err := common.WriteJSONFile(logPath, exportLog); err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,150 @@
+package team
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReportFileName is the team report written next to the stats files of the period
+const ReportFileName = "team-report.md"
+
+// minAnonymousMembers is the fewest members an anonymized report shows a metric for: with one or two, the range
+// would give away individual values
+const minAnonymousMembers = 3
+
+// Aggregate is one metric across the members who shared it
+type Aggregate struct {
+	ID      string
+	Label   string
+	Values  map[string]float64 // by member
+	Total   float64
+	Average float64
+	Median  float64
+	Min     float64
+	Max     float64
+}
+
+// AggregateMetrics combines the summaries per metric, in the order the metrics first appear (members sorted by name)
+func AggregateMetrics(summaries []*Summary) []*Aggregate {
+	sorted := append([]*Summary{}, summaries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Member < sorted[j].Member })
+
+	var aggregates []*Aggregate
+	byID := make(map[string]*Aggregate)
+	for _, summary := range sorted {
+		for _, metric := range summary.Metrics {
+			aggregate, exists := byID[metric.ID]
+			if !exists {
+				aggregate = &Aggregate{ID: metric.ID, Label: metric.Label, Values: make(map[string]float64)}
+				byID[metric.ID] = aggregate
+				aggregates = append(aggregates, aggregate)
+			}
+			aggregate.Values[summary.Member] = metric.Value
+		}
+	}
+
+	for _, aggregate := range aggregates {
+		values := make([]float64, 0, len(aggregate.Values))
+		for _, value := range aggregate.Values {
+			values = append(values, value)
+			aggregate.Total += value
+		}
+		sort.Float64s(values)
+		aggregate.Min, aggregate.Max = values[0], values[len(values)-1]
+		aggregate.Average = aggregate.Total / float64(len(values))
+		if middle := len(values) / 2; len(values)%2 == 1 {
+			aggregate.Median = values[middle]
+		} else {
+			aggregate.Median = (values[middle-1] + values[middle]) / 2
+		}
+	}
+	return aggregates
+}
+
+// WriteReport renders the team's metrics for a retro: total, average, median, and range per metric, then (unless
+// anonymized) every member's value. Anonymized reports name no one, not even who shared, and leave out metrics
+// fewer than minAnonymousMembers shared.
+func WriteReport(writer io.Writer, summaries []*Summary, startDate, endDate time.Time, anonymize bool) {
+	fmt.Fprintf(writer, "# Team Report (%s to %s)\n\n", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+
+	var members []string
+	for _, summary := range summaries {
+		members = append(members, summary.Member)
+	}
+	sort.Strings(members)
+	if anonymize {
+		fmt.Fprintf(writer, "%d members shared their metrics.\n", len(members))
+	} else {
+		fmt.Fprintf(writer, "%d members shared their metrics: %s.\n", len(members), strings.Join(members, ", "))
+	}
+
+	aggregates := AggregateMetrics(summaries)
+	if anonymize {
+		var shown []*Aggregate
+		for _, aggregate := range aggregates {
+			if len(aggregate.Values) >= minAnonymousMembers {
+				shown = append(shown, aggregate)
+			}
+		}
+		if hidden := len(aggregates) - len(shown); hidden > 0 {
+			fmt.Fprintf(writer, "%d metrics shared by fewer than %d members are left out.\n", hidden, minAnonymousMembers)
+		}
+		aggregates = shown
+	}
+	if len(aggregates) == 0 {
+		return
+	}
+	fmt.Fprintln(writer, "\nDurations (`_hours`) are in hours.")
+
+	fmt.Fprintln(writer, "\n## Team")
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "| Metric | Members | Total | Average | Median | Min | Max |")
+	fmt.Fprintln(writer, "| --- | ---: | ---: | ---: | ---: | ---: | ---: |")
+	for _, aggregate := range aggregates {
+		fmt.Fprintf(writer, "| %s (`%s`) | %d | %s | %s | %s | %s | %s |\n", escapeCell(aggregate.Label), aggregate.ID, len(aggregate.Values),
+			formatNumber(aggregate.Total), formatNumber(aggregate.Average), formatNumber(aggregate.Median),
+			formatNumber(aggregate.Min), formatNumber(aggregate.Max))
+	}
+	if anonymize {
+		return
+	}
+
+	fmt.Fprintln(writer, "\n## Members")
+	fmt.Fprintln(writer)
+	fmt.Fprintf(writer, "| Metric | %s |\n", strings.Join(escapeCells(members), " | "))
+	fmt.Fprintf(writer, "| --- |%s\n", strings.Repeat(" ---: |", len(members)))
+	for _, aggregate := range aggregates {
+		cells := make([]string, len(members))
+		for i, member := range members {
+			cells[i] = "-"
+			if value, ok := aggregate.Values[member]; ok {
+				cells[i] = formatNumber(value)
+			}
+		}
+		fmt.Fprintf(writer, "| `%s` | %s |\n", aggregate.ID, strings.Join(cells, " | "))
+	}
+}
+
+// formatNumber prints whole numbers without decimals and others with one
+func formatNumber(value float64) string {
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
+
+func escapeCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+func escapeCells(values []string) []string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = escapeCell(value)
+	}
+	return escaped
+}
@@ -0,0 +1,96 @@
+package team
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/upload"
+)
+
+// StoreEnv names the shared store members push their summaries to: s3://bucket/prefix, gs://bucket/prefix, or a
+// directory such as a mounted file share
+const StoreEnv = "TEAM_STORE"
+
+// Store is the shared store with the prefix summaries are kept under
+type Store struct {
+	objects upload.Store
+	prefix  string
+}
+
+// OpenStore opens the store at location (TEAM_STORE)
+func OpenStore(location string) (*Store, error) {
+	if strings.HasPrefix(location, "s3://") || strings.HasPrefix(location, "gs://") {
+		target, err := upload.ParseTarget(location)
+		if err != nil {
+			return nil, err
+		}
+		objects, err := upload.NewStore(target)
+		if err != nil {
+			return nil, common.WrapError(err, "failed to open %s", location)
+		}
+		return &Store{objects: objects, prefix: target.Prefix}, nil
+	}
+	if location == "" {
+		return nil, common.NewError("%s is not set (s3://bucket/prefix, gs://bucket/prefix, or a shared directory)", StoreEnv)
+	}
+	return &Store{objects: directoryStore(location)}, nil
+}
+
+// key returns the key of a path within the store's prefix
+func (s *Store) key(path string) string {
+	if s.prefix == "" {
+		return path
+	}
+	return s.prefix + "/" + path
+}
+
+// directoryStore keeps objects as files under a directory; keys are slash-separated relative paths
+type directoryStore string
+
+func (d directoryStore) path(key string) string {
+	return filepath.Join(string(d), filepath.FromSlash(key))
+}
+
+func (d directoryStore) URL(key string) string {
+	return d.path(key)
+}
+
+func (d directoryStore) Upload(key string, body []byte, contentType string) error {
+	path := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0644)
+}
+
+func (d directoryStore) Download(key string) ([]byte, error) {
+	return os.ReadFile(d.path(key))
+}
+
+func (d directoryStore) List(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.Walk(string(d), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relative, err := filepath.Rel(string(d), path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(relative); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	sort.Strings(keys)
+	return keys, err
+}
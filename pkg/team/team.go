@@ -0,0 +1,178 @@
+package team
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// formatVersion is increased when the summary format changes incompatibly
+const formatVersion = 1
+
+// Summary is what one member shares for a period: metric values only, never the items behind them
+type Summary struct {
+	Version   int       `json:"version"`
+	Member    string    `json:"member"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Metrics   []Metric  `json:"metrics"`
+}
+
+// Metric is a shared metric value; durations are in hours, like every export of metrics
+type Metric struct {
+	ID    string  `json:"id"`
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+}
+
+// source returns the source of a metric ID (github.prs_authored → github)
+func source(id string) string {
+	name, _, _ := strings.Cut(id, ".")
+	return name
+}
+
+// MemberFromEnv returns TEAM_MEMBER, the name this machine shares its summaries under
+func MemberFromEnv() (string, error) {
+	member := strings.TrimSpace(os.Getenv("TEAM_MEMBER"))
+	if member == "" {
+		return "", common.NewError("TEAM_MEMBER is not set: name yourself in the team store (e.g. TEAM_MEMBER=alice)")
+	}
+	return member, nil
+}
+
+// metricFilter reads TEAM_METRICS: comma-separated sources (github) or metric IDs (calendar.meeting_hours) to
+// share. Everything is shared when it is empty.
+func metricFilter() func(id string) bool {
+	var entries []string
+	for _, entry := range strings.Split(os.Getenv("TEAM_METRICS"), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return func(id string) bool {
+		if len(entries) == 0 {
+			return true
+		}
+		for _, entry := range entries {
+			if id == entry || source(id) == entry {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// unsafeKeyChars are replaced in member names, which become object keys
+var unsafeKeyChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// summaryKey returns the key of a member's summary for a period: <period>/<member>.json
+func summaryKey(period, member string) string {
+	name := strings.Trim(unsafeKeyChars.ReplaceAllString(strings.ToLower(member), "-"), "-.")
+	return path.Join(period, name+".json")
+}
+
+// Push shares the metrics of results as member's summary for the period. Metrics of sources that didn't run this
+// time are kept from the summary already in the store, so that runs of single analyzers add up. Returns the URL
+// written and the number of metrics shared.
+func (s *Store) Push(member string, cfg *common.Config, results []*common.AnalysisResult) (string, int, error) {
+	period := cfg.PeriodLabel()
+	key := s.key(summaryKey(period, member))
+	summary := &Summary{
+		Version:   formatVersion,
+		Member:    member,
+		StartDate: cfg.StartDate.Format("2006-01-02"),
+		EndDate:   cfg.EndDate.Format("2006-01-02"),
+		UpdatedAt: time.Now(),
+	}
+
+	shared := metricFilter()
+	ran := make(map[string]bool)
+	for _, result := range results {
+		for _, metric := range result.Metrics {
+			ran[source(metric.ID)] = true
+			value, ok := metric.Number()
+			if !ok || !shared(metric.ID) {
+				continue
+			}
+			summary.Metrics = append(summary.Metrics, Metric{ID: metric.ID, Label: metric.Label, Value: value})
+		}
+	}
+
+	previous, err := s.load(key)
+	if err != nil {
+		return "", 0, err
+	}
+	if previous != nil {
+		for _, metric := range previous.Metrics {
+			if !ran[source(metric.ID)] && shared(metric.ID) {
+				summary.Metrics = append(summary.Metrics, metric)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", 0, common.WrapError(err, "failed to encode the team summary")
+	}
+	if err := s.objects.Upload(key, data, "application/json"); err != nil {
+		return "", 0, common.WrapError(err, "failed to write %s", s.objects.URL(key))
+	}
+	return s.objects.URL(key), len(summary.Metrics), nil
+}
+
+// load returns the summary at key, or nil when there is none
+func (s *Store) load(key string) (*Summary, error) {
+	keys, err := s.objects.List(key)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to list the team store")
+	}
+	for _, listed := range keys {
+		if listed == key {
+			return s.read(key)
+		}
+	}
+	return nil, nil
+}
+
+// read downloads and decodes a summary
+func (s *Store) read(key string) (*Summary, error) {
+	data, err := s.objects.Download(key)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to read %s", s.objects.URL(key))
+	}
+	var summary Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, common.WrapError(err, "failed to parse %s", s.objects.URL(key))
+	}
+	if summary.Version != formatVersion {
+		return nil, common.NewError("%s is a version %d summary; this build reads version %d", s.objects.URL(key), summary.Version, formatVersion)
+	}
+	return &summary, nil
+}
+
+// Summaries returns the summaries every member shared for the period, by member key
+func (s *Store) Summaries(cfg *common.Config) ([]*Summary, error) {
+	prefix := s.key(cfg.PeriodLabel() + "/")
+	keys, err := s.objects.List(prefix)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to list the team store")
+	}
+	var summaries []*Summary
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".json") || strings.Contains(strings.TrimPrefix(key, prefix), "/") {
+			continue
+		}
+		summary, err := s.read(key)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
@@ -0,0 +1,295 @@
+package todoist
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+	"dev-stats/pkg/config"
+)
+
+const todoistSyncURL = "https://api.todoist.com/sync/v9"
+
+// completedPageSize is the maximum page size of the completed items endpoint
+const completedPageSize = 200
+
+// TodoistAnalyzer implements the Analyzer interface for Todoist
+type TodoistAnalyzer struct {
+	token      string
+	client     *common.HTTPClient
+	ignoreList config.IgnoreList
+}
+
+// CompletedTask is a task completed within the period
+type CompletedTask struct {
+	ID          string    `json:"task_id"`
+	Content     string    `json:"content"`
+	CompletedAt time.Time `json:"completed_at"`
+	ProjectID   string    `json:"project_id"`
+	Project     string    `json:"project"`
+	Labels      []string  `json:"labels"`
+}
+
+// completedResponse is the response of /completed/get_all with annotate_items=true
+type completedResponse struct {
+	Items []struct {
+		TaskID      string    `json:"task_id"`
+		Content     string    `json:"content"`
+		CompletedAt time.Time `json:"completed_at"`
+		ProjectID   string    `json:"project_id"`
+		ItemObject  *struct {
+			Labels []string `json:"labels"`
+		} `json:"item_object"`
+	} `json:"items"`
+	Projects map[string]struct {
+		Name string `json:"name"`
+	} `json:"projects"`
+}
+
+// NewTodoistAnalyzer creates a new Todoist analyzer
+func NewTodoistAnalyzer() *TodoistAnalyzer {
+	token := os.Getenv("TODOIST_API_TOKEN")
+	client := common.NewHTTPClient()
+	client.SetHeader("Authorization", "Bearer "+token)
+	return &TodoistAnalyzer{
+		token:  token,
+		client: client,
+	}
+}
+
+// GetName returns the analyzer name
+func (t *TodoistAnalyzer) GetName() string {
+	return "Todoist"
+}
+
+// ValidateConfig validates the required configuration
+func (t *TodoistAnalyzer) ValidateConfig(writer io.Writer) error {
+	if t.token == "" {
+		return common.NewError("TODOIST_API_TOKEN environment variable is required")
+	}
+
+	params := url.Values{}
+	params.Set("sync_token", "*")
+	params.Set("resource_types", `["user"]`)
+	if _, err := t.client.Get(fmt.Sprintf("%s/sync?%s", todoistSyncURL, params.Encode()), nil); err != nil {
+		return common.WrapError(err, "failed to access Todoist API (check TODOIST_API_TOKEN)")
+	}
+	fmt.Fprintln(writer, "✓ Todoist token is valid")
+	return nil
+}
+
+// WhoAmI reports the owner of TODOIST_API_TOKEN
+func (t *TodoistAnalyzer) WhoAmI(writer io.Writer) (*common.Identity, error) {
+	if t.token == "" {
+		return nil, common.NewError("TODOIST_API_TOKEN environment variable is required")
+	}
+
+	params := url.Values{}
+	params.Set("sync_token", "*")
+	params.Set("resource_types", `["user"]`)
+	body, err := t.client.Get(fmt.Sprintf("%s/sync?%s", todoistSyncURL, params.Encode()), nil)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to access Todoist API (check TODOIST_API_TOKEN)")
+	}
+	var response struct {
+		User struct {
+			ID       string `json:"id"`
+			FullName string `json:"full_name"`
+			Email    string `json:"email"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, common.WrapError(err, "failed to parse Todoist user")
+	}
+	return &common.Identity{Source: t.GetName(), ID: response.User.ID, Login: response.User.Email, Name: response.User.FullName}, nil
+}
+
+// Analyze reports tasks completed per day, project, and label
+func (t *TodoistAnalyzer) Analyze(config *common.Config, writer io.Writer) (*common.AnalysisResult, error) {
+	if err := t.ValidateConfig(writer); err != nil {
+		return nil, err
+	}
+	if err := t.ignoreList.Reload(); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(writer, "Fetching Todoist tasks completed from %s to %s...\n",
+		config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"))
+	tasks, err := t.getCompletedTasks(config.StartDate, config.EndDate)
+	if err != nil {
+		return nil, common.WrapError(err, "failed to get completed tasks")
+	}
+	tasks = t.filterIgnored(writer, tasks)
+
+	byDay := make(map[string]int)
+	byProject := make(map[string]int)
+	byLabel := make(map[string]int)
+	for _, task := range tasks {
+		byDay[task.CompletedAt.Local().Format("2006-01-02")]++
+		byProject[task.Project]++
+		if len(task.Labels) == 0 {
+			byLabel["No labels"]++
+		}
+		for _, label := range task.Labels {
+			byLabel[label]++
+		}
+	}
+
+	result := &common.AnalysisResult{
+		AnalyzerName: t.GetName(),
+		StartDate:    config.StartDate,
+		EndDate:      config.EndDate,
+		Metrics: []common.Metric{
+			{ID: "todoist.tasks_completed", Label: "Tasks completed", Value: len(tasks)},
+			{ID: "todoist.active_days", Label: "Days with completed tasks", Value: len(byDay)},
+			{ID: "todoist.projects", Label: "Projects", Value: len(byProject), Snapshot: true},
+			{ID: "todoist.labels", Label: "Labels", Value: len(byLabel), Snapshot: true},
+		},
+		Details: map[string]interface{}{
+			"completed_tasks": tasks,
+			"by_day":          byDay,
+			"by_project":      byProject,
+			"by_label":        byLabel,
+		},
+		Activities: t.buildActivities(tasks),
+		CSVTables:  csvTables(tasks),
+	}
+	result.Explain("todoist.tasks_completed", result.Activities)
+
+	t.printResults(writer, result, tasks, byDay, byProject, byLabel)
+	return result, nil
+}
+
+// getCompletedTasks fetches completed tasks in the period with the Sync API, following offset pagination
+func (t *TodoistAnalyzer) getCompletedTasks(startDate, endDate time.Time) ([]CompletedTask, error) {
+	var tasks []CompletedTask
+	for offset := 0; ; offset += completedPageSize {
+		params := url.Values{}
+		params.Set("since", startDate.Format("2006-01-02T15:04:05"))
+		params.Set("until", endDate.AddDate(0, 0, 1).Format("2006-01-02T15:04:05"))
+		params.Set("limit", strconv.Itoa(completedPageSize))
+		params.Set("offset", strconv.Itoa(offset))
+		params.Set("annotate_items", "true")
+
+		body, err := t.client.Get(fmt.Sprintf("%s/completed/get_all?%s", todoistSyncURL, params.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		var response completedResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, common.WrapError(err, "failed to parse Todoist response")
+		}
+
+		for _, item := range response.Items {
+			task := CompletedTask{
+				ID:          item.TaskID,
+				Content:     item.Content,
+				CompletedAt: item.CompletedAt,
+				ProjectID:   item.ProjectID,
+				Project:     response.Projects[item.ProjectID].Name,
+			}
+			if task.Project == "" {
+				task.Project = "Unknown project"
+			}
+			if item.ItemObject != nil {
+				task.Labels = item.ItemObject.Labels
+			}
+			tasks = append(tasks, task)
+		}
+
+		if len(response.Items) < completedPageSize {
+			break
+		}
+	}
+	return tasks, nil
+}
+
+// taskURL returns the browser URL of a task
+func taskURL(taskID string) string {
+	return "https://app.todoist.com/app/task/" + taskID
+}
+
+// filterIgnored drops tasks whose ID or URL is listed in the ignore file
+func (t *TodoistAnalyzer) filterIgnored(writer io.Writer, tasks []CompletedTask) []CompletedTask {
+	return config.FilterIgnored(writer, &t.ignoreList, tasks, "tasks", func(task CompletedTask) []string {
+		return []string{task.ID, taskURL(task.ID)}
+	})
+}
+
+// buildActivities converts completed tasks into dated activities
+func (t *TodoistAnalyzer) buildActivities(tasks []CompletedTask) []common.Activity {
+	var activities []common.Activity
+	for _, task := range tasks {
+		activities = append(activities, common.Activity{
+			Source: t.GetName(),
+			Kind:   "task_completed",
+			ID:     task.ID,
+			Title:  task.Content,
+			URL:    taskURL(task.ID),
+			Time:   task.CompletedAt,
+		})
+	}
+	return activities
+}
+
+func (t *TodoistAnalyzer) printResults(writer io.Writer, result *common.AnalysisResult, tasks []CompletedTask, byDay, byProject, byLabel map[string]int) {
+	fmt.Fprintf(writer, "\nTasks completed from %s to %s (%d):\n",
+		result.StartDate.Format("2006-01-02"), result.EndDate.Format("2006-01-02"), len(tasks))
+
+	sorted := append([]CompletedTask{}, tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CompletedAt.Before(sorted[j].CompletedAt)
+	})
+	for _, task := range sorted {
+		line := fmt.Sprintf("- %s: %s [%s]", task.CompletedAt.Local().Format("2006-01-02 15:04"), task.Content, task.Project)
+		if len(task.Labels) > 0 {
+			line += " @" + strings.Join(task.Labels, " @")
+		}
+		fmt.Fprintln(writer, line)
+	}
+
+	result.PrintSummary(writer)
+
+	var days []string
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	fmt.Fprintln(writer, "\nTasks completed per day:")
+	for _, day := range days {
+		fmt.Fprintf(writer, "- %s: %d\n", day, byDay[day])
+	}
+
+	printCounts(writer, "Tasks completed per project:", byProject)
+	printCounts(writer, "Tasks completed per label:", byLabel)
+}
+
+// printCounts prints counts sorted by count, then name
+func printCounts(writer io.Writer, title string, counts map[string]int) {
+	var names []string
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Fprintf(writer, "\n%s\n", title)
+	if len(names) == 0 {
+		fmt.Fprintln(writer, "- None")
+		return
+	}
+	for _, name := range names {
+		fmt.Fprintf(writer, "- %s: %d\n", name, counts[name])
+	}
+}
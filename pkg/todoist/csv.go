@@ -0,0 +1,31 @@
+package todoist
+
+import (
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// taskCSVRow is one task in todoist-tasks.csv
+type taskCSVRow struct {
+	Content     string    `csv:"content"`
+	Project     string    `csv:"project"`
+	Labels      []string  `csv:"labels"`
+	CompletedAt time.Time `csv:"completed_at"`
+	URL         string    `csv:"url"`
+}
+
+// csvTables lists the tasks completed within the period
+func csvTables(tasks []CompletedTask) []common.CSVTable {
+	var rows []taskCSVRow
+	for _, task := range tasks {
+		rows = append(rows, taskCSVRow{
+			Content:     task.Content,
+			Project:     task.Project,
+			Labels:      task.Labels,
+			CompletedAt: task.CompletedAt,
+			URL:         taskURL(task.ID),
+		})
+	}
+	return []common.CSVTable{common.NewCSVTable("tasks", rows)}
+}
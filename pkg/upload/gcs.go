@@ -0,0 +1,63 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+
+	"dev-stats/pkg/common"
+)
+
+// gcsUploader uploads, reads, and lists objects with the Cloud Storage JSON API using Application Default Credentials
+// (GOOGLE_APPLICATION_CREDENTIALS, gcloud auth application-default login, or the attached service account)
+type gcsUploader struct {
+	bucket  string
+	service *storage.Service
+}
+
+func newGCSUploader(bucket string) (*gcsUploader, error) {
+	service, err := storage.NewService(context.Background(), option.WithScopes(storage.DevstorageReadWriteScope))
+	if err != nil {
+		return nil, common.WrapError(err, "failed to create Cloud Storage client (check Application Default Credentials)")
+	}
+	return &gcsUploader{bucket: bucket, service: service}, nil
+}
+
+func (u *gcsUploader) URL(key string) string {
+	return fmt.Sprintf("gs://%s/%s", u.bucket, key)
+}
+
+func (u *gcsUploader) Upload(key string, body []byte, contentType string) error {
+	object := &storage.Object{Name: key, ContentType: contentType}
+	_, err := u.service.Objects.Insert(u.bucket, object).Media(bytes.NewReader(body)).Do()
+	return err
+}
+
+func (u *gcsUploader) Download(key string) ([]byte, error) {
+	resp, err := u.service.Objects.Get(u.bucket, key).Download()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (u *gcsUploader) List(prefix string) ([]string, error) {
+	var keys []string
+	err := u.service.Objects.List(u.bucket).Prefix(prefix).Pages(context.Background(), func(objects *storage.Objects) error {
+		for _, object := range objects.Items {
+			keys = append(keys, object.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
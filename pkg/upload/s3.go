@@ -0,0 +1,300 @@
+package upload
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-stats/pkg/common"
+)
+
+// imdsURL is the EC2 instance metadata endpoint used for instance role credentials
+const imdsURL = "http://169.254.169.254/latest"
+
+// awsCredentials are resolved access keys
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// s3Uploader uploads, reads, and lists objects with S3 requests signed with Signature Version 4
+type s3Uploader struct {
+	bucket      string
+	region      string
+	endpoint    string // AWS_ENDPOINT_URL for S3-compatible storage; path-style requests are used then
+	credentials *awsCredentials
+	client      *common.HTTPClient
+}
+
+func newS3Uploader(bucket string) (*s3Uploader, error) {
+	credentials, err := resolveAWSCredentials()
+	if err != nil {
+		return nil, err
+	}
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	// Every request is signed with its time, so its responses would never be found in the cache again
+	client := common.NewHTTPClient()
+	client.DisableCache()
+	return &s3Uploader{
+		bucket:      bucket,
+		region:      region,
+		endpoint:    strings.TrimSuffix(os.Getenv("AWS_ENDPOINT_URL"), "/"),
+		credentials: credentials,
+		client:      client,
+	}, nil
+}
+
+// resolveAWSCredentials follows the standard chain: environment, shared credentials file, then the EC2 instance role
+func resolveAWSCredentials() (*awsCredentials, error) {
+	if accessKey := os.Getenv("AWS_ACCESS_KEY_ID"); accessKey != "" {
+		return &awsCredentials{
+			AccessKeyID:     accessKey,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+	if credentials, err := sharedAWSCredentials(); err == nil && credentials != nil {
+		return credentials, nil
+	}
+	if credentials, err := instanceRoleCredentials(); err == nil {
+		return credentials, nil
+	}
+	return nil, common.NewError("no AWS credentials found (set AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY, configure ~/.aws/credentials, or run with an instance role)")
+}
+
+// sharedAWSCredentials reads AWS_PROFILE (default "default") from AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials
+func sharedAWSCredentials() (*awsCredentials, error) {
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var credentials *awsCredentials
+	inProfile := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inProfile = strings.TrimSpace(line[1:len(line)-1]) == profile
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !inProfile || !found {
+			continue
+		}
+		if credentials == nil {
+			credentials = &awsCredentials{}
+		}
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			credentials.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			credentials.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			credentials.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if credentials == nil || credentials.AccessKeyID == "" {
+		return nil, nil
+	}
+	return credentials, scanner.Err()
+}
+
+// instanceRoleCredentials fetches temporary credentials of the EC2 instance role with IMDSv2
+func instanceRoleCredentials() (*awsCredentials, error) {
+	client := common.NewHTTPClient()
+	client.SetTimeout(2 * time.Second)
+
+	token, err := client.Put(imdsURL+"/api/token", "", map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "300"})
+	if err != nil {
+		return nil, err
+	}
+	client.SetHeader("X-aws-ec2-metadata-token", string(token))
+
+	role, err := client.Get(imdsURL+"/meta-data/iam/security-credentials/", nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.Get(imdsURL+"/meta-data/iam/security-credentials/"+strings.TrimSpace(strings.Split(string(role), "\n")[0]), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		SecretAccessKey string `json:"SecretAccessKey"`
+		Token           string `json:"Token"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	return &awsCredentials{AccessKeyID: response.AccessKeyID, SecretAccessKey: response.SecretAccessKey, SessionToken: response.Token}, nil
+}
+
+// objectURL returns the request URL of key (virtual-hosted style on AWS, path style on custom endpoints)
+func (u *s3Uploader) objectURL(key string) *url.URL {
+	if u.endpoint != "" {
+		endpoint, err := url.Parse(u.endpoint)
+		if err == nil {
+			endpoint.Path = "/" + u.bucket + "/" + key
+			return endpoint
+		}
+	}
+	return &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", u.bucket, u.region), Path: "/" + key}
+}
+
+func (u *s3Uploader) URL(key string) string {
+	return fmt.Sprintf("s3://%s/%s", u.bucket, key)
+}
+
+func (u *s3Uploader) Upload(key string, body []byte, contentType string) error {
+	objectURL := u.objectURL(key)
+	headers := u.sign("PUT", objectURL, body)
+	headers["Content-Type"] = contentType
+	_, err := u.client.Put(objectURL.String(), string(body), headers)
+	return err
+}
+
+func (u *s3Uploader) Download(key string) ([]byte, error) {
+	objectURL := u.objectURL(key)
+	return u.client.Get(objectURL.String(), u.sign("GET", objectURL, nil))
+}
+
+// s3ListResult is a page of a ListObjectsV2 response
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (u *s3Uploader) List(prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		listURL := u.objectURL("")
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		listURL.RawQuery = canonicalQuery(query)
+		body, err := u.client.Get(listURL.String(), u.sign("GET", listURL, nil))
+		if err != nil {
+			return nil, err
+		}
+		var page s3ListResult
+		if err := xml.Unmarshal(body, &page); err != nil {
+			return nil, common.WrapError(err, "failed to parse the object list of %s", u.bucket)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, object.Key)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// sign returns the headers of a request signed with Signature Version 4
+func (u *s3Uploader) sign(method string, requestURL *url.URL, payload []byte) map[string]string {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	headers := map[string]string{
+		"host":                 requestURL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if u.credentials.SessionToken != "" {
+		headers["x-amz-security-token"] = u.credentials.SessionToken
+	}
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if u.credentials.SessionToken != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		method,
+		requestURL.EscapedPath(),
+		canonicalQuery(requestURL.Query()),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, u.region)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+u.credentials.SecretAccessKey), date)
+	for _, part := range []string{u.region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	requestHeaders := map[string]string{
+		"Authorization": fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			u.credentials.AccessKeyID, scope, strings.Join(signedHeaders, ";"), signature),
+	}
+	for name, value := range headers {
+		if name != "host" {
+			requestHeaders[name] = value
+		}
+	}
+	return requestHeaders
+}
+
+// canonicalQuery encodes query parameters sorted by name with %20 for spaces, as Signature Version 4 requires
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
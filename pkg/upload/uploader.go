@@ -0,0 +1,113 @@
+package upload
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"dev-stats/pkg/common"
+)
+
+// Uploader stores objects in a bucket
+type Uploader interface {
+	// Upload writes body to key within the bucket
+	Upload(key string, body []byte, contentType string) error
+	// URL returns the location of key for display
+	URL(key string) string
+}
+
+// Store is a bucket that objects are also read back from, such as the shared team store
+type Store interface {
+	Uploader
+	// Download returns the content of key
+	Download(key string) ([]byte, error)
+	// List returns the keys starting with prefix, sorted
+	List(prefix string) ([]string, error)
+}
+
+// Target is a parsed upload destination such as s3://bucket/prefix or gs://bucket/prefix
+type Target struct {
+	Scheme string
+	Bucket string
+	Prefix string
+}
+
+// ParseTarget parses an s3:// or gs:// URL
+func ParseTarget(raw string) (*Target, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "s3" && parsed.Scheme != "gs") {
+		return nil, common.NewError("invalid upload target '%s' (expected s3://bucket/prefix or gs://bucket/prefix)", raw)
+	}
+	return &Target{Scheme: parsed.Scheme, Bucket: parsed.Host, Prefix: strings.Trim(parsed.Path, "/")}, nil
+}
+
+// NewUploader creates an uploader for the target, resolving credentials with the provider's standard chain
+func NewUploader(target *Target) (Uploader, error) {
+	switch target.Scheme {
+	case "s3":
+		return newS3Uploader(target.Bucket)
+	case "gs":
+		return newGCSUploader(target.Bucket)
+	}
+	return nil, common.NewError("unsupported upload scheme: %s", target.Scheme)
+}
+
+// NewStore opens the target for reading as well as uploading
+func NewStore(target *Target) (Store, error) {
+	switch target.Scheme {
+	case "s3":
+		return newS3Uploader(target.Bucket)
+	case "gs":
+		return newGCSUploader(target.Bucket)
+	}
+	return nil, common.NewError("unsupported upload scheme: %s", target.Scheme)
+}
+
+// SyncDirectory uploads every file under localDir to <prefix>/<remoteDir>/<relative path>. Returns the number of files uploaded.
+func SyncDirectory(writer io.Writer, uploader Uploader, target *Target, localDir, remoteDir string) (int, error) {
+	uploaded := 0
+	err := filepath.Walk(localDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relative, err := filepath.Rel(localDir, filePath)
+		if err != nil {
+			return err
+		}
+		body, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		key := path.Join(target.Prefix, remoteDir, filepath.ToSlash(relative))
+		if err := uploader.Upload(key, body, contentType(filePath)); err != nil {
+			return common.WrapError(err, "failed to upload %s", filePath)
+		}
+		fmt.Fprintf(writer, "✓ %s → %s\n", filePath, uploader.URL(key))
+		uploaded++
+		return nil
+	})
+	return uploaded, err
+}
+
+// contentType guesses the MIME type from the file extension
+func contentType(filePath string) string {
+	switch filepath.Ext(filePath) {
+	case ".txt":
+		return "text/plain; charset=utf-8"
+	case ".md":
+		return "text/markdown; charset=utf-8"
+	}
+	if guessed := mime.TypeByExtension(filepath.Ext(filePath)); guessed != "" {
+		return guessed
+	}
+	return "application/octet-stream"
+}